@@ -31,6 +31,7 @@ import {
   Toast,
   ToastContainer,
   Spinner,
+  Form,
 } from 'react-bootstrap';
 import 'bootstrap/dist/css/bootstrap.min.css';
 import 'bootstrap-icons/font/bootstrap-icons.css';
@@ -134,6 +135,7 @@ import TrimNetworkRangesModal from './modals/TrimNetworkRangesModal.js';
 import LiveWebServersResultsModal from './modals/LiveWebServersResultsModal.js';
 import AmassEnumConfigModal from './modals/AmassEnumConfigModal.js';
 import AmassIntelConfigModal from './modals/AmassIntelConfigModal.js';
+import IPPortScanConfigModal from './modals/IPPortScanConfigModal.js';
 import DNSxConfigModal from './modals/DNSxConfigModal.js';
 import fetchMetabigorCompanyScans from './utils/fetchMetabigorCompanyScans';
 
@@ -181,13 +183,17 @@ const getMetabigorNetworkRangesCount = (networkRanges) => {
 };
 
 // Calculate estimated IP/Port scan time based on network ranges
-const calculateEstimatedScanTime = (networkRanges) => {
-  // Calculate total IPs from all CIDR blocks
+const calculateEstimatedScanTime = (networkRanges, maxIPsPerRange = 254) => {
+  // Calculate the IPs probed from all CIDR blocks, each range is capped by the scan profile.
+  // IPv6 ranges are not swept, only their already known hosts are probed.
   const totalIPs = networkRanges.reduce((total, range) => {
     const cidr = range.cidr_block || range.cidr;
+    if (cidr.includes(':')) {
+      return total;
+    }
     const [, prefix] = cidr.split('/');
     const prefixLength = parseInt(prefix);
-    const ipCount = Math.pow(2, 32 - prefixLength);
+    const ipCount = Math.min(Math.pow(2, 32 - prefixLength), maxIPsPerRange);
     return total + ipCount;
   }, 0);
 
@@ -530,6 +536,10 @@ function App() {
   const [mostRecentIPPortScan, setMostRecentIPPortScan] = useState(null);
   const [mostRecentIPPortScanStatus, setMostRecentIPPortScanStatus] = useState(null);
   const [isIPPortScanning, setIsIPPortScanning] = useState(false);
+  const [ipPortScanProfile, setIPPortScanProfile] = useState('');
+  const [showIPPortScanConfigModal, setShowIPPortScanConfigModal] = useState(false);
+  const [ipPortScanConfig, setIPPortScanConfig] = useState(null);
+  const [ipPortScanCustomPorts, setIPPortScanCustomPorts] = useState('');
   const [MetaDataScans, setMetaDataScans] = useState([]);
   const [mostRecentMetaDataScanStatus, setMostRecentMetaDataScanStatus] = useState(null);
   const [mostRecentMetaDataScan, setMostRecentMetaDataScan] = useState(null);
@@ -877,6 +887,25 @@ function App() {
     }
   };
 
+  const loadIPPortScanConfig = async () => {
+    if (!activeTarget?.id) return;
+
+    try {
+      const response = await fetch(
+        `${process.env.REACT_APP_SERVER_PROTOCOL}://${process.env.REACT_APP_SERVER_IP}:${process.env.REACT_APP_SERVER_PORT}/ip-port-scan-config/${activeTarget.id}`
+      );
+
+      if (response.ok) {
+        setIPPortScanConfig(await response.json());
+      } else {
+        setIPPortScanConfig(null);
+      }
+    } catch (error) {
+      console.error('Error loading IP/Port scan config:', error);
+      setIPPortScanConfig(null);
+    }
+  };
+
   const loadAmassIntelConfig = async () => {
     if (!activeTarget?.id) return;
 
@@ -926,7 +955,10 @@ function App() {
 
     try {
       setIsIPPortScanning(true);
-      const response = await initiateIPPortScan(activeTarget.id);
+      const response = await initiateIPPortScan(activeTarget.id, null, {
+        portProfile: ipPortScanProfile,
+        customPorts: ipPortScanCustomPorts
+      });
       console.log('IP/Port scan initiated:', response);
 
       // Start monitoring the scan status
@@ -1069,6 +1101,13 @@ function App() {
     }
   }, [activeTarget?.id]); // Run when activeTarget.id changes (including initial load)
 
+  // Load the IP/Port scan profile when component mounts and activeTarget becomes available
+  useEffect(() => {
+    if (activeTarget) {
+      loadIPPortScanConfig();
+    }
+  }, [activeTarget?.id]); // Run when activeTarget.id changes (including initial load)
+
   useEffect(() => {
     if (activeTarget) {
       fetchAmassScans(activeTarget, setAmassScans, setMostRecentAmassScan, setMostRecentAmassScanStatus, setDnsRecords, setSubdomains, setCloudDomains);
@@ -3066,6 +3105,7 @@ function App() {
               httpx_round1: config.consolidate_httpx_round1 !== false,
               shuffledns: config.shuffledns !== false,
               cewl: config.cewl !== false,
+              permutations: config.permutations !== false,
               consolidate_round2: config.consolidate_httpx_round2 !== false,
               httpx_round2: config.consolidate_httpx_round2 !== false,
               gospider: config.gospider !== false,
@@ -4767,15 +4807,59 @@ function App() {
                               <small className="text-white-50">Network Ranges</small>
                             </div>
                             <div className="col">
-                              <h3 className="mb-0">{calculateEstimatedScanTime(consolidatedNetworkRanges)}</h3>
+                              <h3 className="mb-0">{calculateEstimatedScanTime(consolidatedNetworkRanges, ipPortScanConfig?.max_ips_per_range)}</h3>
                               <small className="text-white-50">Est. Scan Time</small>
                             </div>
                             <div className="col">
                               <h3 className="mb-0">{mostRecentIPPortScan?.live_web_servers_found || 0}</h3>
                               <small className="text-white-50">Live Web Servers</small>
                             </div>
+                            <div className="col">
+                              <h3 className="mb-0">{mostRecentIPPortScan?.services_found || 0}</h3>
+                              <small className="text-white-50">Other Services</small>
+                            </div>
                           </div>
                         </div>
+                        <div className="d-flex justify-content-center gap-2 mb-3">
+                          <Form.Select
+                            size="sm"
+                            style={{ maxWidth: '220px' }}
+                            value={ipPortScanProfile}
+                            onChange={(e) => setIPPortScanProfile(e.target.value)}
+                            disabled={isIPPortScanning}
+                          >
+                            <option value="">Saved profile ({ipPortScanConfig?.port_profile || 'web'})</option>
+                            <option value="web">Web ports (40)</option>
+                            <option value="top-100">Top 100 ports</option>
+                            <option value="top-1000">Top 1000 ports</option>
+                            <option value="full">All 65535 ports</option>
+                            <option value="custom">Custom ports</option>
+                          </Form.Select>
+                          {ipPortScanProfile === 'custom' && (
+                            <Form.Control
+                              size="sm"
+                              style={{ maxWidth: '220px' }}
+                              placeholder="22,80,8000-8100"
+                              value={ipPortScanCustomPorts}
+                              onChange={(e) => setIPPortScanCustomPorts(e.target.value)}
+                              disabled={isIPPortScanning}
+                            />
+                          )}
+                          <Button
+                            variant="outline-danger"
+                            size="sm"
+                            onClick={() => setShowIPPortScanConfigModal(true)}
+                            disabled={isIPPortScanning}
+                          >
+                            <i className="bi bi-gear" />
+                          </Button>
+                        </div>
+                        {mostRecentIPPortScan?.truncated_ranges > 0 && (
+                          <div className="text-center text-warning small mb-3">
+                            {mostRecentIPPortScan.truncated_ranges} range(s) were sampled down to {mostRecentIPPortScan.scan_config?.max_ips_per_range || 254} IPs,{' '}
+                            {Number(mostRecentIPPortScan.ips_skipped).toLocaleString()} addresses were not probed
+                          </div>
+                        )}
                         <div className="d-flex justify-content-between mt-auto gap-2">
                           <Button 
                             variant="outline-danger" 
@@ -6251,7 +6335,7 @@ function App() {
                 <th className="text-center">Live Web Servers</th>
                 <th colSpan={6} className="text-center bg-dark border-danger">Subdomain Scraping</th>
                 <th className="text-center bg-dark border-danger">R1</th>
-                <th colSpan={2} className="text-center bg-dark border-danger">Brute Force</th>
+                <th colSpan={3} className="text-center bg-dark border-danger">Brute Force</th>
                 <th className="text-center bg-dark border-danger">R2</th>
                 <th colSpan={2} className="text-center bg-dark border-danger">JS/Link Discovery</th>
                 <th className="text-center bg-dark border-danger">R3</th>
@@ -6268,6 +6352,7 @@ function App() {
                 <th className="text-center" style={{width: '40px'}}>HX1</th>
                 <th className="text-center" style={{width: '40px'}}>SDS</th>
                 <th className="text-center" style={{width: '40px'}}>CWL</th>
+                <th className="text-center" style={{width: '40px'}}>PRM</th>
                 <th className="text-center" style={{width: '40px'}}>HX2</th>
                 <th className="text-center" style={{width: '40px'}}>GS</th>
                 <th className="text-center" style={{width: '40px'}}>SDZ</th>
@@ -6279,7 +6364,7 @@ function App() {
             <tbody>
               {(!autoScanSessions || autoScanSessions.length === 0) ? (
                 <tr>
-                  <td colSpan={20} className="text-center text-white-50">
+                  <td colSpan={21} className="text-center text-white-50">
                     No auto scan sessions found for this target.
                   </td>
                 </tr>
@@ -6304,6 +6389,7 @@ function App() {
                     <td className="text-center">{session.config?.httpx_round1 ? <span className="text-danger fw-bold">✓</span> : ''}</td>
                     <td className="text-center">{session.config?.shuffledns ? <span className="text-danger fw-bold">✓</span> : ''}</td>
                     <td className="text-center">{session.config?.cewl ? <span className="text-danger fw-bold">✓</span> : ''}</td>
+                    <td className="text-center">{session.config?.permutations ? <span className="text-danger fw-bold">✓</span> : ''}</td>
                     <td className="text-center">{session.config?.httpx_round2 ? <span className="text-danger fw-bold">✓</span> : ''}</td>
                     <td className="text-center">{session.config?.gospider ? <span className="text-danger fw-bold">✓</span> : ''}</td>
                     <td className="text-center">{session.config?.subdomainizer ? <span className="text-danger fw-bold">✓</span> : ''}</td>
@@ -6575,6 +6661,12 @@ function App() {
         consolidatedCompanyDomains={consolidatedCompanyDomains}
         onSaveConfig={handleAmassEnumConfigSave}
       />
+      <IPPortScanConfigModal
+        show={showIPPortScanConfigModal}
+        handleClose={() => setShowIPPortScanConfigModal(false)}
+        activeTarget={activeTarget}
+        onSaveConfig={setIPPortScanConfig}
+      />
       <AmassIntelConfigModal
         show={showAmassIntelConfigModal}
         handleClose={handleCloseAmassIntelConfigModal}
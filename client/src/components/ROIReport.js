@@ -301,11 +301,11 @@ const TargetSection = ({ targetURL, roiScore }) => {
           </Card>
         </Col>
         <Col md={4}>
-          {targetURL.screenshot && (
+          {targetURL.screenshot_url && (
             <Card className="bg-dark border-danger h-100">
               <Card.Body className="p-2 d-flex align-items-center justify-content-center">
                 <img 
-                  src={`data:image/png;base64,${targetURL.screenshot}`}
+                  src={`${process.env.REACT_APP_SERVER_PROTOCOL}://${process.env.REACT_APP_SERVER_IP}:${process.env.REACT_APP_SERVER_PORT}${targetURL.screenshot_url}`}
                   alt="Target Screenshot"
                   className="img-fluid"
                   style={{ 
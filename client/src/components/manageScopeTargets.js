@@ -47,7 +47,7 @@ function ManageScopeTargets({
         }
       } catch (e) {
         const fallback = {
-          amass: true, sublist3r: true, assetfinder: true, gau: true, ctl: true, subfinder: true, consolidate_httpx_round1: true, shuffledns: true, cewl: true, consolidate_httpx_round2: true, gospider: true, subdomainizer: true, consolidate_httpx_round3: true, nuclei_screenshot: true, metadata: true, maxConsolidatedSubdomains: 2500, maxLiveWebServers: 500
+          amass: true, sublist3r: true, assetfinder: true, gau: true, ctl: true, subfinder: true, consolidate_httpx_round1: true, recursive_enumeration: false, shuffledns: true, cewl: true, permutations: true, consolidate_httpx_round2: true, gospider: true, subdomainizer: true, consolidate_httpx_round3: true, nuclei_screenshot: true, metadata: true, maxConsolidatedSubdomains: 2500, maxLiveWebServers: 500, maxPermutationCandidates: 50000, recursiveMaxDepth: 2, recursiveMaxZones: 10
         };
         setAutoScanConfig(fallback);
         console.log('[AutoScanConfig] Fallback to defaults:', fallback);
@@ -321,8 +321,10 @@ function ManageScopeTargets({
         'subfinder': 'subfinder',
         'consolidate': 'consolidate_httpx_round1',
         'httpx': 'consolidate_httpx_round1',
+        'recursive_enum': 'recursive_enumeration',
         'shuffledns': 'shuffledns',
         'shuffledns_cewl': 'cewl',
+        'permutations': 'permutations',
         'consolidate_round2': 'consolidate_httpx_round2',
         'httpx_round2': 'consolidate_httpx_round2',
         'gospider': 'gospider',
@@ -365,8 +367,8 @@ function ManageScopeTargets({
     // Define the full step sequence in execution order
     const fullStepSequence = [
       'amass', 'sublist3r', 'assetfinder', 'gau', 'ctl', 'subfinder',
-      'consolidate', 'httpx', 
-      'shuffledns', 'shuffledns_cewl',
+      'consolidate', 'httpx', 'recursive_enum',
+      'shuffledns', 'shuffledns_cewl', 'permutations',
       'consolidate_round2', 'httpx_round2',
       'gospider', 'subdomainizer',
       'consolidate_round3', 'httpx_round3',
@@ -383,8 +385,10 @@ function ManageScopeTargets({
       'subfinder': 'subfinder',
       'consolidate': 'consolidate_httpx_round1',
       'httpx': 'consolidate_httpx_round1',
+      'recursive_enum': 'recursive_enumeration',
       'shuffledns': 'shuffledns',
       'shuffledns_cewl': 'cewl',
+      'permutations': 'permutations',
       'consolidate_round2': 'consolidate_httpx_round2',
       'httpx_round2': 'consolidate_httpx_round2',
       'gospider': 'gospider',
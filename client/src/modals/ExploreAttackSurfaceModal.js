@@ -22,7 +22,8 @@ const ExploreAttackSurfaceModal = ({
     { key: 'ip_address', label: 'IP Addresses', count: 0 },
     { key: 'fqdn', label: 'Domain Names', count: 0 },
     { key: 'cloud_asset', label: 'Cloud Asset Domains', count: 0 },
-    { key: 'live_web_server', label: 'Live Web Servers', count: 0 }
+    { key: 'live_web_server', label: 'Live Web Servers', count: 0 },
+    { key: 'network_service', label: 'Network Services', count: 0 }
   ];
 
   useEffect(() => {
@@ -84,6 +85,10 @@ const ExploreAttackSurfaceModal = ({
           asset.status_code?.toString(),
           asset.title,
           asset.web_server,
+          asset.service_name,
+          asset.service_product,
+          asset.service_version,
+          asset.service_banner,
           asset.cloud_provider,
           asset.cloud_service_type,
           asset.cloud_region,
@@ -201,6 +206,7 @@ const ExploreAttackSurfaceModal = ({
       case 'live_web_server': return 'success';
       case 'cloud_asset': return 'warning';
       case 'fqdn': return 'danger';
+      case 'network_service': return 'light';
       default: return 'dark';
     }
   };
@@ -213,6 +219,7 @@ const ExploreAttackSurfaceModal = ({
       case 'live_web_server': return 'Live Web Servers';
       case 'cloud_asset': return 'Cloud Asset Domains';
       case 'fqdn': return 'Domain Names';
+      case 'network_service': return 'Network Services';
       default: return assetType;
     }
   };
@@ -298,6 +305,18 @@ const ExploreAttackSurfaceModal = ({
             {asset.title || <span className="text-white-50">-</span>}
           </div>
         );
+      case 'service_name':
+        return asset.service_name ? <Badge variant="info">{asset.service_name}</Badge> : <span className="text-white-50">-</span>;
+      case 'service_product':
+        return asset.service_product
+          ? `${asset.service_product}${asset.service_version ? ` ${asset.service_version}` : ''}`
+          : <span className="text-white-50">-</span>;
+      case 'service_banner':
+        return asset.service_banner ? (
+          <div style={{ maxWidth: '300px', overflow: 'hidden', textOverflow: 'ellipsis', whiteSpace: 'nowrap' }} title={asset.service_banner}>
+            <code>{asset.service_banner}</code>
+          </div>
+        ) : <span className="text-white-50">-</span>;
       case 'cloud_provider':
         return asset.cloud_provider || <span className="text-white-50">-</span>;
       case 'cloud_service_type':
@@ -752,6 +771,44 @@ const ExploreAttackSurfaceModal = ({
             sortable: true
           }
         ];
+      case 'network_service':
+        return [
+          {
+            key: 'ip_address',
+            label: 'IP Address',
+            sortable: true
+          },
+          {
+            key: 'port',
+            label: 'Port',
+            sortable: true
+          },
+          {
+            key: 'service_name',
+            label: 'Service',
+            sortable: true
+          },
+          {
+            key: 'service_product',
+            label: 'Product',
+            sortable: true
+          },
+          {
+            key: 'domain',
+            label: 'Hostname',
+            sortable: true
+          },
+          {
+            key: 'service_banner',
+            label: 'Banner',
+            sortable: false
+          },
+          {
+            key: 'last_updated',
+            label: 'Last Updated',
+            sortable: true
+          }
+        ];
       case 'cloud_asset':
         return [
           {
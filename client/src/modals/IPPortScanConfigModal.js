@@ -0,0 +1,304 @@
+import { useState, useEffect } from 'react';
+import { Modal, Button, Spinner, Alert, Row, Col, Form } from 'react-bootstrap';
+
+const defaultConfig = {
+  max_ips_per_range: 254,
+  ip_sampling: 'first',
+  max_concurrent_ips: 50,
+  max_concurrent_ports: 20,
+  host_probe_timeout_ms: 1000,
+  port_scan_timeout_ms: 1000,
+  web_service_timeout_ms: 5000,
+  banner_timeout_ms: 3000,
+  port_profile: 'web',
+  custom_ports: '',
+  host_discovery: 'tcp',
+  discovery_ports: ''
+};
+
+const numericFields = [
+  'max_ips_per_range',
+  'max_concurrent_ips',
+  'max_concurrent_ports',
+  'host_probe_timeout_ms',
+  'port_scan_timeout_ms',
+  'web_service_timeout_ms',
+  'banner_timeout_ms'
+];
+
+const IPPortScanConfigModal = ({
+  show,
+  handleClose,
+  activeTarget,
+  onSaveConfig
+}) => {
+  const [config, setConfig] = useState(defaultConfig);
+  const [saving, setSaving] = useState(false);
+  const [error, setError] = useState('');
+
+  useEffect(() => {
+    if (show) {
+      loadSavedConfig();
+    }
+  }, [show, activeTarget]);
+
+  const loadSavedConfig = async () => {
+    if (!activeTarget?.id) return;
+
+    try {
+      const response = await fetch(
+        `${process.env.REACT_APP_SERVER_PROTOCOL}://${process.env.REACT_APP_SERVER_IP}:${process.env.REACT_APP_SERVER_PORT}/ip-port-scan-config/${activeTarget.id}`
+      );
+
+      if (response.ok) {
+        const savedConfig = await response.json();
+        setConfig({ ...defaultConfig, ...savedConfig });
+      } else {
+        setConfig(defaultConfig);
+      }
+    } catch (error) {
+      console.error('Error loading IP/Port scan config:', error);
+      setConfig(defaultConfig);
+    }
+  };
+
+  const updateField = (field, value) => {
+    setConfig(prev => ({
+      ...prev,
+      [field]: numericFields.includes(field) ? parseInt(value, 10) || 0 : value
+    }));
+  };
+
+  const handleSaveConfig = async () => {
+    if (!activeTarget?.id) {
+      setError('No active target selected');
+      return;
+    }
+
+    setSaving(true);
+    setError('');
+
+    try {
+      const response = await fetch(
+        `${process.env.REACT_APP_SERVER_PROTOCOL}://${process.env.REACT_APP_SERVER_IP}:${process.env.REACT_APP_SERVER_PORT}/ip-port-scan-config/${activeTarget.id}`,
+        {
+          method: 'POST',
+          headers: {
+            'Content-Type': 'application/json',
+          },
+          body: JSON.stringify(config),
+        }
+      );
+
+      if (!response.ok) {
+        throw new Error(await response.text());
+      }
+
+      const savedConfig = await response.json();
+      if (onSaveConfig) {
+        onSaveConfig(savedConfig);
+      }
+
+      handleClose();
+    } catch (error) {
+      console.error('Error saving IP/Port scan config:', error);
+      setError(`Failed to save configuration: ${error.message}`);
+    } finally {
+      setSaving(false);
+    }
+  };
+
+  const handleCloseModal = () => {
+    setError('');
+    handleClose();
+  };
+
+  return (
+    <Modal
+      show={show}
+      onHide={handleCloseModal}
+      size="lg"
+      data-bs-theme="dark"
+    >
+      <Modal.Header closeButton>
+        <Modal.Title className="text-danger">
+          <i className="bi bi-hdd-network me-2" />
+          Configure IP/Port Scan
+        </Modal.Title>
+      </Modal.Header>
+      <Modal.Body>
+        {error && (
+          <Alert variant="danger" dismissible onClose={() => setError('')}>
+            {error}
+          </Alert>
+        )}
+
+        <div className="mb-4 text-white-50">
+          <i className="bi bi-info-circle me-2" />
+          <strong>Scan Profile:</strong> Saved for this scope target and used by every IP/Port scan, including auto scans.
+          Ranges larger than the IP limit are sampled and the number of skipped addresses is reported with the scan.
+        </div>
+
+        <h6 className="text-danger mb-3">Address Selection</h6>
+        <Row className="mb-4">
+          <Col md={6}>
+            <Form.Group>
+              <Form.Label className="text-white-50 small">Max IPs per range</Form.Label>
+              <Form.Control
+                type="number"
+                min={1}
+                max={65536}
+                value={config.max_ips_per_range}
+                onChange={(e) => updateField('max_ips_per_range', e.target.value)}
+              />
+            </Form.Group>
+          </Col>
+          <Col md={6}>
+            <Form.Group>
+              <Form.Label className="text-white-50 small">Sampling for larger ranges</Form.Label>
+              <Form.Select
+                value={config.ip_sampling}
+                onChange={(e) => updateField('ip_sampling', e.target.value)}
+              >
+                <option value="first">First N addresses</option>
+                <option value="spread">Spread evenly across the range</option>
+                <option value="random">Random sample</option>
+              </Form.Select>
+            </Form.Group>
+          </Col>
+        </Row>
+
+        <h6 className="text-danger mb-3">Host Discovery</h6>
+        <Row className="mb-4">
+          <Col md={6}>
+            <Form.Group>
+              <Form.Label className="text-white-50 small">Method</Form.Label>
+              <Form.Select
+                value={config.host_discovery}
+                onChange={(e) => updateField('host_discovery', e.target.value)}
+              >
+                <option value="tcp">TCP connect (open or refused)</option>
+                <option value="tcp-open">TCP connect (open only)</option>
+                <option value="none">Skip discovery, port scan every address</option>
+              </Form.Select>
+            </Form.Group>
+          </Col>
+          <Col md={6}>
+            <Form.Group>
+              <Form.Label className="text-white-50 small">Discovery ports (blank for defaults)</Form.Label>
+              <Form.Control
+                placeholder="22,80,443,3389"
+                value={config.discovery_ports}
+                onChange={(e) => updateField('discovery_ports', e.target.value)}
+                disabled={config.host_discovery === 'none'}
+              />
+            </Form.Group>
+          </Col>
+        </Row>
+
+        <h6 className="text-danger mb-3">Ports</h6>
+        <Row className="mb-4">
+          <Col md={6}>
+            <Form.Group>
+              <Form.Label className="text-white-50 small">Port profile</Form.Label>
+              <Form.Select
+                value={config.port_profile}
+                onChange={(e) => updateField('port_profile', e.target.value)}
+              >
+                <option value="web">Web ports (40)</option>
+                <option value="top-100">Top 100 ports</option>
+                <option value="top-1000">Top 1000 ports</option>
+                <option value="full">All 65535 ports</option>
+                <option value="custom">Custom ports</option>
+              </Form.Select>
+            </Form.Group>
+          </Col>
+          <Col md={6}>
+            <Form.Group>
+              <Form.Label className="text-white-50 small">Custom ports</Form.Label>
+              <Form.Control
+                placeholder="22,80,8000-8100"
+                value={config.custom_ports}
+                onChange={(e) => updateField('custom_ports', e.target.value)}
+                disabled={config.port_profile !== 'custom'}
+              />
+            </Form.Group>
+          </Col>
+        </Row>
+
+        <h6 className="text-danger mb-3">Concurrency and Timeouts</h6>
+        <Row className="mb-3">
+          <Col md={6}>
+            <Form.Group>
+              <Form.Label className="text-white-50 small">Concurrent host probes</Form.Label>
+              <Form.Control
+                type="number"
+                min={1}
+                max={1000}
+                value={config.max_concurrent_ips}
+                onChange={(e) => updateField('max_concurrent_ips', e.target.value)}
+              />
+            </Form.Group>
+          </Col>
+          <Col md={6}>
+            <Form.Group>
+              <Form.Label className="text-white-50 small">Concurrent port scans</Form.Label>
+              <Form.Control
+                type="number"
+                min={1}
+                max={500}
+                value={config.max_concurrent_ports}
+                onChange={(e) => updateField('max_concurrent_ports', e.target.value)}
+              />
+            </Form.Group>
+          </Col>
+        </Row>
+        <Row>
+          {[
+            ['host_probe_timeout_ms', 'Host probe timeout (ms)'],
+            ['port_scan_timeout_ms', 'Port connect timeout (ms)'],
+            ['web_service_timeout_ms', 'HTTP check timeout (ms)'],
+            ['banner_timeout_ms', 'Banner grab timeout (ms)']
+          ].map(([field, label]) => (
+            <Col md={3} key={field}>
+              <Form.Group>
+                <Form.Label className="text-white-50 small">{label}</Form.Label>
+                <Form.Control
+                  type="number"
+                  min={100}
+                  max={60000}
+                  value={config[field]}
+                  onChange={(e) => updateField(field, e.target.value)}
+                />
+              </Form.Group>
+            </Col>
+          ))}
+        </Row>
+      </Modal.Body>
+      <Modal.Footer>
+        <Button variant="secondary" onClick={handleCloseModal} className="me-2">
+          Cancel
+        </Button>
+        <Button
+          variant="danger"
+          onClick={handleSaveConfig}
+          disabled={saving}
+        >
+          {saving ? (
+            <>
+              <Spinner animation="border" size="sm" className="me-2" />
+              Saving...
+            </>
+          ) : (
+            <>
+              <i className="bi bi-save me-2" />
+              Save Configuration
+            </>
+          )}
+        </Button>
+      </Modal.Footer>
+    </Modal>
+  );
+};
+
+export default IPPortScanConfigModal;
@@ -126,7 +126,7 @@ const ScreenshotResultsModal = ({
                   )}
                 </div>
               </div>
-              {targetURL.screenshot && (
+              {targetURL.screenshot_url && (
                 <div 
                   style={{ 
                     height: expandedIndex === index ? '500px' : '150px',
@@ -136,9 +136,8 @@ const ScreenshotResultsModal = ({
                     transition: 'height 0.3s ease-in-out'
                   }}
                 >
-                  {console.log('[DEBUG] Screenshot data:', targetURL.screenshot)}
                   <img 
-                    src={`data:image/png;base64,${targetURL.screenshot}`} 
+                    src={`${process.env.REACT_APP_SERVER_PROTOCOL}://${process.env.REACT_APP_SERVER_IP}:${process.env.REACT_APP_SERVER_PORT}${expandedIndex === index ? targetURL.screenshot_url : targetURL.thumbnail_url}`} 
                     alt={`Screenshot of ${targetURL.url}`}
                     style={{ 
                       width: '100%',
@@ -93,7 +93,9 @@ function SettingsModal({ show, handleClose, initialTab = 'rate-limits', onApiKey
         burp_proxy_port: 8080,
         burp_api_ip: '127.0.0.1',
         burp_api_port: 1337,
-        burp_api_key: ''
+        burp_api_key: '',
+        burp_proxy_enabled: false,
+        gau_providers: 'wayback'
       };
       
       // Check if data is empty or missing expected properties
@@ -121,7 +123,9 @@ function SettingsModal({ show, handleClose, initialTab = 'rate-limits', onApiKey
         burp_proxy_port: 8080,
         burp_api_ip: '127.0.0.1',
         burp_api_port: 1337,
-        burp_api_key: ''
+        burp_api_key: '',
+        burp_proxy_enabled: false,
+        gau_providers: 'wayback'
       });
     } finally {
       setLoading(false);
@@ -548,6 +552,16 @@ function SettingsModal({ show, handleClose, initialTab = 'rate-limits', onApiKey
     </Form.Group>
   );
 
+  const gauProviders = ['wayback', 'commoncrawl', 'otx', 'urlscan'];
+  const selectedGauProviders = (settings.gau_providers || 'wayback').split(',').filter(Boolean);
+
+  const toggleGauProvider = (provider, checked) => {
+    const selected = checked
+      ? [...selectedGauProviders, provider]
+      : selectedGauProviders.filter(p => p !== provider);
+    handleChange('gau_providers', gauProviders.filter(p => selected.includes(p)).join(','));
+  };
+
   // Tool descriptions
   const toolDescriptions = {
     amass: "Controls requests per second for DNS queries. Higher values may trigger rate limiting by DNS servers.",
@@ -700,6 +714,26 @@ function SettingsModal({ show, handleClose, initialTab = 'rate-limits', onApiKey
             {renderSlider('httpx', 'HTTPX', 50, 500, 10, toolDescriptions.httpx)}
             {renderSlider('subfinder', 'Subfinder', 1, 100, 1, toolDescriptions.subfinder)}
             {renderSlider('gau', 'GAU', 1, 50, 1, toolDescriptions.gau)}
+            <Form.Group as={Row} className="mb-4 align-items-center">
+              <Form.Label column sm={4} className="text-white">
+                GAU Providers
+              </Form.Label>
+              <Col sm={8}>
+                {gauProviders.map(provider => (
+                  <Form.Check
+                    key={provider}
+                    inline
+                    type="checkbox"
+                    id={`gau-provider-${provider}`}
+                    label={provider}
+                    checked={selectedGauProviders.includes(provider)}
+                    onChange={(e) => toggleGauProvider(provider, e.target.checked)}
+                    className="text-white"
+                  />
+                ))}
+                <p className="text-white-50 small mt-1">Archives GAU pulls historical URLs from. Wayback is used when none are selected.</p>
+              </Col>
+            </Form.Group>
             {renderSlider('sublist3r', 'Sublist3r', 1, 50, 1, toolDescriptions.sublist3r)}
             {renderSlider('ctl', 'CTL', 1, 50, 1, toolDescriptions.ctl)}
             {renderSlider('shuffledns', 'ShuffleDNS', 1000, 20000, 1000, toolDescriptions.shuffledns)}
@@ -1006,6 +1040,20 @@ function SettingsModal({ show, handleClose, initialTab = 'rate-limits', onApiKey
                             Port where Burp proxy is listening (default: 8080)
                           </Form.Text>
                         </Form.Group>
+
+                        <Form.Group className="mb-3">
+                          <Form.Check
+                            type="switch"
+                            id="burp-proxy-enabled"
+                            label="Route tool traffic through Burp proxy"
+                            checked={!!settings.burp_proxy_enabled}
+                            onChange={(e) => handleChange('burp_proxy_enabled', e.target.checked)}
+                            className="text-white"
+                          />
+                          <Form.Text className="text-white-50">
+                            Sends httpx, Katana, GoSpider and ffuf requests through Burp. A loopback proxy IP is reached from the tool containers via host.docker.internal.
+                          </Form.Text>
+                        </Form.Group>
                       </Col>
 
                       <Col md={6}>
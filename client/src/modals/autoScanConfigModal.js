@@ -10,8 +10,10 @@ function AutoScanConfigModal({ show, handleClose, config, onSave, loading: exter
     { id: 'ctl', name: 'CTL' },
     { id: 'subfinder', name: 'Subfinder' },
     { id: 'consolidate_httpx_round1', name: 'Consolidate & Live Web Servers (Round 1)' },
+    { id: 'recursive_enumeration', name: 'Recursive Sub-zone Enumeration' },
     { id: 'shuffledns', name: 'ShuffleDNS' },
     { id: 'cewl', name: 'CeWL' },
+    { id: 'permutations', name: 'Subdomain Permutations' },
     { id: 'consolidate_httpx_round2', name: 'Consolidate & Live Web Servers (Round 2)' },
     { id: 'gospider', name: 'GoSpider' },
     { id: 'subdomainizer', name: 'Subdomainizer' },
@@ -21,7 +23,7 @@ function AutoScanConfigModal({ show, handleClose, config, onSave, loading: exter
   ];
 
   const defaultConfig = {
-    amass: true, sublist3r: true, assetfinder: true, gau: true, ctl: true, subfinder: true, consolidate_httpx_round1: true, shuffledns: true, cewl: true, consolidate_httpx_round2: true, gospider: true, subdomainizer: true, consolidate_httpx_round3: true, nuclei_screenshot: true, metadata: true, maxConsolidatedSubdomains: 2500, maxLiveWebServers: 500
+    amass: true, sublist3r: true, assetfinder: true, gau: true, ctl: true, subfinder: true, consolidate_httpx_round1: true, recursive_enumeration: false, shuffledns: true, cewl: true, permutations: true, consolidate_httpx_round2: true, gospider: true, subdomainizer: true, consolidate_httpx_round3: true, nuclei_screenshot: true, metadata: true, maxConsolidatedSubdomains: 2500, maxLiveWebServers: 500, maxPermutationCandidates: 50000, recursiveMaxDepth: 2, recursiveMaxZones: 10
   };
 
   const scanProfiles = {
@@ -41,8 +43,10 @@ function AutoScanConfigModal({ show, handleClose, config, onSave, loading: exter
         ctl: true, 
         subfinder: true, 
         consolidate_httpx_round1: true, 
+        recursive_enumeration: false, 
         shuffledns: false, 
         cewl: false, 
+        permutations: false, 
         consolidate_httpx_round2: false, 
         gospider: false, 
         subdomainizer: false, 
@@ -62,8 +66,10 @@ function AutoScanConfigModal({ show, handleClose, config, onSave, loading: exter
         ctl: true, 
         subfinder: true, 
         consolidate_httpx_round1: true, 
+        recursive_enumeration: false, 
         shuffledns: false, 
         cewl: false, 
+        permutations: false, 
         consolidate_httpx_round2: false, 
         gospider: false, 
         subdomainizer: false, 
@@ -83,8 +89,10 @@ function AutoScanConfigModal({ show, handleClose, config, onSave, loading: exter
         ctl: true, 
         subfinder: true, 
         consolidate_httpx_round1: true, 
+        recursive_enumeration: false, 
         shuffledns: true, 
         cewl: false, 
+        permutations: true, 
         consolidate_httpx_round2: true, 
         gospider: true, 
         subdomainizer: false, 
@@ -104,8 +112,10 @@ function AutoScanConfigModal({ show, handleClose, config, onSave, loading: exter
         ctl: true, 
         subfinder: true, 
         consolidate_httpx_round1: true, 
+        recursive_enumeration: false, 
         shuffledns: true, 
         cewl: true, 
+        permutations: true, 
         consolidate_httpx_round2: true, 
         gospider: true, 
         subdomainizer: true, 
@@ -125,8 +135,10 @@ function AutoScanConfigModal({ show, handleClose, config, onSave, loading: exter
         ctl: true, 
         subfinder: true, 
         consolidate_httpx_round1: true, 
+        recursive_enumeration: false, 
         shuffledns: true, 
         cewl: true, 
+        permutations: true, 
         consolidate_httpx_round2: true, 
         gospider: true, 
         subdomainizer: true, 
@@ -320,6 +332,45 @@ function AutoScanConfigModal({ show, handleClose, config, onSave, loading: exter
               onChange={e => handleSliderChange('maxLiveWebServers', Number(e.target.value))}
             />
           </Form.Group>
+          <Form.Group className="mb-2">
+            <div className="d-flex justify-content-between align-items-center">
+              <Form.Label className="text-danger mb-0">Max Permutation Candidates</Form.Label>
+              <span className="text-white">{localConfig.maxPermutationCandidates}</span>
+            </div>
+            <Form.Range
+              min={1000}
+              max={500000}
+              step={1000}
+              value={localConfig.maxPermutationCandidates}
+              onChange={e => handleSliderChange('maxPermutationCandidates', Number(e.target.value))}
+            />
+          </Form.Group>
+          <Form.Group className="mb-2">
+            <div className="d-flex justify-content-between align-items-center">
+              <Form.Label className="text-danger mb-0">Recursive Enumeration Depth</Form.Label>
+              <span className="text-white">{localConfig.recursiveMaxDepth}</span>
+            </div>
+            <Form.Range
+              min={1}
+              max={5}
+              step={1}
+              value={localConfig.recursiveMaxDepth}
+              onChange={e => handleSliderChange('recursiveMaxDepth', Number(e.target.value))}
+            />
+          </Form.Group>
+          <Form.Group className="mb-2">
+            <div className="d-flex justify-content-between align-items-center">
+              <Form.Label className="text-danger mb-0">Max Recursive Sub-zones</Form.Label>
+              <span className="text-white">{localConfig.recursiveMaxZones}</span>
+            </div>
+            <Form.Range
+              min={1}
+              max={100}
+              step={1}
+              value={localConfig.recursiveMaxZones}
+              onChange={e => handleSliderChange('recursiveMaxZones', Number(e.target.value))}
+            />
+          </Form.Group>
         </Modal.Body>
         <Modal.Footer className="border-secondary">
           <Button variant="outline-secondary" onClick={handleClose} disabled={isLoading}>
@@ -5,6 +5,8 @@ import initiateSubfinderScan from './initiateSubfinderScan';
 import initiateHttpxScan from './initiateHttpxScan';
 import initiateShuffleDNSScan from './initiateShuffleDNSScan';
 import initiateCeWLScan from './initiateCeWLScan';
+import initiatePermutationScan from './initiatePermutationScan';
+import initiateRecursiveEnumScan from './initiateRecursiveEnumScan';
 import initiateNucleiScreenshotScan from './initiateNucleiScreenshotScan';
 import initiateMetaDataScan from './initiateMetaDataScan';
 import fetchHttpxScans from './fetchHttpxScans';
@@ -688,6 +690,35 @@ const getAutoScanSteps = (
         setIsHttpxScanning(false);
       }
     }},
+    { name: AUTO_SCAN_STEPS.RECURSIVE_ENUM, action: async () => {
+      if (!config || config.recursive_enumeration !== true) {
+        console.log('[AutoScan] Step: recursive_enumeration is DISABLED in config. Skipping.');
+        return;
+      }
+      console.log('[AutoScan] Step: recursive_enumeration is ENABLED. Running.');
+      console.log("Starting Recursive Sub-zone Enumeration...");
+      setAutoScanCurrentStep(AUTO_SCAN_STEPS.RECURSIVE_ENUM);
+      await updateAutoScanState(activeTarget.id, AUTO_SCAN_STEPS.RECURSIVE_ENUM);
+      
+      try {
+        // Sub-zones are picked from the consolidated subdomains, so this runs after Round 1
+        const started = await initiateRecursiveEnumScan(activeTarget, autoScanSessionId);
+        
+        if (started) {
+          const completedScan = await waitForScanCompletion(
+            'recursive-enum',
+            activeTarget.id,
+            () => {},
+            () => {}
+          );
+          debugTrace(`Recursive enumeration covered ${completedScan?.zones?.length || 0} sub-zones and found ${completedScan?.new_count || 0} new subdomains`);
+        }
+        
+        console.log('[AutoScan] Step: recursive_enumeration completed.');
+      } catch (error) {
+        console.error('[AutoScan] Step: recursive_enumeration ERROR:', error);
+      }
+    }},
     { name: AUTO_SCAN_STEPS.SHUFFLEDNS, action: async () => {
       if (config && config.shuffledns === false) {
         console.log('[AutoScan] Step: shuffledns is DISABLED in config. Skipping.');
@@ -830,6 +861,35 @@ const getAutoScanSteps = (
         console.error('[AutoScan] Step: cewl ERROR:', error);
       }
     }},
+    { name: AUTO_SCAN_STEPS.PERMUTATIONS, action: async () => {
+      if (config && config.permutations === false) {
+        console.log('[AutoScan] Step: permutations is DISABLED in config. Skipping.');
+        return;
+      }
+      console.log('[AutoScan] Step: permutations is ENABLED. Running.');
+      console.log("Starting Subdomain Permutation Scan...");
+      setAutoScanCurrentStep(AUTO_SCAN_STEPS.PERMUTATIONS);
+      await updateAutoScanState(activeTarget.id, AUTO_SCAN_STEPS.PERMUTATIONS);
+      
+      try {
+        // Candidates are generated from the consolidated subdomains and resolved with ShuffleDNS
+        const started = await initiatePermutationScan(activeTarget, autoScanSessionId);
+        
+        if (started) {
+          const completedScan = await waitForScanCompletion(
+            'permutations',
+            activeTarget.id,
+            () => {},
+            () => {}
+          );
+          debugTrace(`Permutation scan resolved ${completedScan?.resolved_count || 0} of ${completedScan?.candidate_count || 0} candidates${completedScan?.truncated ? ' (candidate cap reached)' : ''}`);
+        }
+        
+        console.log('[AutoScan] Step: permutations completed.');
+      } catch (error) {
+        console.error('[AutoScan] Step: permutations ERROR:', error);
+      }
+    }},
     { name: AUTO_SCAN_STEPS.CONSOLIDATE_ROUND2, action: async () => {
       if (config && config.consolidate_httpx_round2 === false) {
         console.log('[AutoScan] Step: consolidate_httpx_round2 is DISABLED in config. Skipping.');
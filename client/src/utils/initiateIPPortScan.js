@@ -1,4 +1,4 @@
-const initiateIPPortScan = async (scopeTargetId, autoScanSessionId = null) => {
+const initiateIPPortScan = async (scopeTargetId, autoScanSessionId = null, { portProfile = null, customPorts = '' } = {}) => {
   const API_BASE_URL = process.env.REACT_APP_API_BASE_URL || 'http://localhost:8443';
 
   const payload = {
@@ -9,6 +9,13 @@ const initiateIPPortScan = async (scopeTargetId, autoScanSessionId = null) => {
     payload.auto_scan_session_id = autoScanSessionId;
   }
 
+  if (portProfile) {
+    payload.port_profile = portProfile;
+    if (portProfile === 'custom') {
+      payload.custom_ports = customPorts;
+    }
+  }
+
   try {
     const response = await fetch(`${API_BASE_URL}/ip-port-scan/run`, {
       method: 'POST',
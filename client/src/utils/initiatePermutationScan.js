@@ -0,0 +1,38 @@
+const initiatePermutationScan = async (activeTarget, autoScanSessionId) => {
+  if (!activeTarget || !activeTarget.scope_target) {
+    console.error('No active target or invalid target format');
+    return;
+  }
+
+  const domain = activeTarget.scope_target.replace('*.', '');
+  if (!domain) {
+    console.error('Invalid domain');
+    return;
+  }
+
+  try {
+    const body = { fqdn: domain };
+    if (autoScanSessionId) body.auto_scan_session_id = autoScanSessionId;
+    const response = await fetch(
+      `${process.env.REACT_APP_SERVER_PROTOCOL}://${process.env.REACT_APP_SERVER_IP}:${process.env.REACT_APP_SERVER_PORT}/permutations/run`,
+      {
+        method: 'POST',
+        headers: {
+          'Content-Type': 'application/json',
+        },
+        body: JSON.stringify(body),
+      }
+    );
+
+    if (!response.ok) {
+      const errorText = await response.text();
+      throw new Error(`Failed to initiate permutation scan: ${errorText}`);
+    }
+
+    return await response.json();
+  } catch (error) {
+    console.error('Error initiating permutation scan:', error);
+  }
+};
+
+export default initiatePermutationScan;
@@ -9,8 +9,10 @@ const AUTO_SCAN_STEPS = {
   SUBFINDER: 'subfinder', // 6
   CONSOLIDATE: 'consolidate', // 7
   HTTPX: 'httpx', // 8
+  RECURSIVE_ENUM: 'recursive_enum', // 8.5
   SHUFFLEDNS: 'shuffledns', // 9
   SHUFFLEDNS_CEWL: 'shuffledns_cewl', // 10
+  PERMUTATIONS: 'permutations', // 10.25
   CONSOLIDATE_ROUND2: 'consolidate_round2', // 10.5
   HTTPX_ROUND2: 'httpx_round2', // 10.75
   GOSPIDER: 'gospider', // 11
@@ -41,8 +43,10 @@ const updateAutoScanState = async (targetId, currentStep, isPaused = false, isCa
         [AUTO_SCAN_STEPS.SUBFINDER]: 'subfinder',
         [AUTO_SCAN_STEPS.CONSOLIDATE]: 'consolidate_httpx_round1',
         [AUTO_SCAN_STEPS.HTTPX]: 'consolidate_httpx_round1',
+        [AUTO_SCAN_STEPS.RECURSIVE_ENUM]: 'recursive_enumeration',
         [AUTO_SCAN_STEPS.SHUFFLEDNS]: 'shuffledns',
         [AUTO_SCAN_STEPS.SHUFFLEDNS_CEWL]: 'cewl',
+        [AUTO_SCAN_STEPS.PERMUTATIONS]: 'permutations',
         [AUTO_SCAN_STEPS.CONSOLIDATE_ROUND2]: 'consolidate_httpx_round2',
         [AUTO_SCAN_STEPS.HTTPX_ROUND2]: 'consolidate_httpx_round2',
         [AUTO_SCAN_STEPS.GOSPIDER]: 'gospider',
@@ -313,8 +317,10 @@ const resumeAutoScan = async (
           [AUTO_SCAN_STEPS.SUBFINDER]: 'subfinder',
           [AUTO_SCAN_STEPS.CONSOLIDATE]: 'consolidate_httpx_round1',
           [AUTO_SCAN_STEPS.HTTPX]: 'consolidate_httpx_round1',
+          [AUTO_SCAN_STEPS.RECURSIVE_ENUM]: 'recursive_enumeration',
           [AUTO_SCAN_STEPS.SHUFFLEDNS]: 'shuffledns',
           [AUTO_SCAN_STEPS.SHUFFLEDNS_CEWL]: 'cewl',
+          [AUTO_SCAN_STEPS.PERMUTATIONS]: 'permutations',
           [AUTO_SCAN_STEPS.CONSOLIDATE_ROUND2]: 'consolidate_httpx_round2',
           [AUTO_SCAN_STEPS.HTTPX_ROUND2]: 'consolidate_httpx_round2',
           [AUTO_SCAN_STEPS.GOSPIDER]: 'gospider',
@@ -453,8 +459,10 @@ const startAutoScan = async (
           [AUTO_SCAN_STEPS.SUBFINDER]: 'subfinder',
           [AUTO_SCAN_STEPS.CONSOLIDATE]: 'consolidate_httpx_round1',
           [AUTO_SCAN_STEPS.HTTPX]: 'consolidate_httpx_round1',
+          [AUTO_SCAN_STEPS.RECURSIVE_ENUM]: 'recursive_enumeration',
           [AUTO_SCAN_STEPS.SHUFFLEDNS]: 'shuffledns',
           [AUTO_SCAN_STEPS.SHUFFLEDNS_CEWL]: 'cewl',
+          [AUTO_SCAN_STEPS.PERMUTATIONS]: 'permutations',
           [AUTO_SCAN_STEPS.CONSOLIDATE_ROUND2]: 'consolidate_httpx_round2',
           [AUTO_SCAN_STEPS.HTTPX_ROUND2]: 'consolidate_httpx_round2',
           [AUTO_SCAN_STEPS.GOSPIDER]: 'gospider',
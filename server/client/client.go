@@ -0,0 +1,126 @@
+// Package client is a typed Go client for the Ars0n Framework REST API.
+//
+// The operations and types in client_gen.go are generated from the OpenAPI spec served at
+// /api/openapi.json. Regenerate them after changing routes with:
+//
+//	go generate ./client
+package client
+
+//go:generate sh -c "cd .. && go run . openapi > openapi/openapi.json"
+//go:generate sh -c "cd .. && go run ./openapi/clientgen -spec openapi/openapi.json -out client/client_gen.go"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MediaType asks the API for normalized responses and JSON error envelopes
+const MediaType = "application/vnd.ars0n.v1+json"
+
+// DefaultBaseURL is where the framework's API container listens by default
+const DefaultBaseURL = "http://127.0.0.1:8443"
+
+// Client talks to a running framework API
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+	UserAgent  string
+}
+
+// New returns a client for the API at baseURL, or DefaultBaseURL when empty
+func New(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 5 * time.Minute},
+		UserAgent:  "ars0n-go-client",
+	}
+}
+
+// Error is returned for non-2xx responses
+type Error struct {
+	StatusCode int    `json:"status"`
+	Message    string `json:"error"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("ars0n API error %d: %s", e.StatusCode, e.Message)
+}
+
+// do sends a request and decodes a JSON response into out when it is not nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	data, err := c.doRaw(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode %s %s response: %v", method, path, err)
+	}
+	return nil
+}
+
+// doRaw sends a request and returns the raw response body
+func (c *Client) doRaw(ctx context.Context, method, path string, query url.Values, body interface{}) ([]byte, error) {
+	endpoint := c.BaseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request body: %v", err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", MediaType)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		if json.Unmarshal(data, apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		apiErr.StatusCode = resp.StatusCode
+		return nil, apiErr
+	}
+	return data, nil
+}
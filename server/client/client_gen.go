@@ -0,0 +1,5082 @@
+// Code generated by openapi/clientgen from the OpenAPI spec. DO NOT EDIT.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var (
+	_ = json.RawMessage(nil)
+	_ = time.Time{}
+	_ = url.Values{}
+)
+
+type APIKey struct {
+	APIKeyName string    `json:"api_key_name"`
+	CreatedAt  time.Time `json:"created_at"`
+	ID         string    `json:"id"`
+	KeyValues  KeyValues `json:"key_values"`
+	ToolName   string    `json:"tool_name"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type APIKeyRequest struct {
+	APIKeyName string          `json:"api_key_name"`
+	KeyValues  json.RawMessage `json:"key_values"`
+	ToolName   string          `json:"tool_name"`
+}
+
+type ASNResponse struct {
+	Number  string `json:"number"`
+	RawData string `json:"raw_data"`
+}
+
+type AiAPIKey struct {
+	APIKeyName string                     `json:"api_key_name"`
+	CreatedAt  time.Time                  `json:"created_at"`
+	ID         string                     `json:"id"`
+	KeyValues  map[string]json.RawMessage `json:"key_values"`
+	Provider   string                     `json:"provider"`
+	UpdatedAt  time.Time                  `json:"updated_at"`
+}
+
+type AiAPIKeyRequest struct {
+	APIKeyName string                     `json:"api_key_name"`
+	KeyValues  map[string]json.RawMessage `json:"key_values"`
+	Provider   string                     `json:"provider"`
+}
+
+type AmassEnumCloudDomain struct {
+	CreatedAt     time.Time `json:"created_at"`
+	Domain        string    `json:"domain"`
+	ID            string    `json:"id"`
+	LastScannedAt time.Time `json:"last_scanned_at"`
+	RootDomain    string    `json:"root_domain"`
+	Type          string    `json:"type"`
+}
+
+type AmassEnumCompanyScan struct {
+	Command       string    `json:"command"`
+	CreatedAt     time.Time `json:"created_at"`
+	Domains       []string  `json:"domains"`
+	Error         string    `json:"error"`
+	ExecutionTime string    `json:"execution_time"`
+	ID            string    `json:"id"`
+	Result        string    `json:"result"`
+	ScanID        string    `json:"scan_id"`
+	Status        string    `json:"status"`
+	Stderr        string    `json:"stderr"`
+	Stdout        string    `json:"stdout"`
+}
+
+type AmassEnumRawResult struct {
+	CreatedAt     time.Time `json:"created_at"`
+	Domain        string    `json:"domain"`
+	ID            string    `json:"id"`
+	LastScannedAt time.Time `json:"last_scanned_at"`
+	RawOutput     string    `json:"raw_output"`
+}
+
+type AmassIntelConfig struct {
+	NetworkRanges []string `json:"network_ranges"`
+}
+
+type AmassIntelScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CompanyName       string    `json:"company_name"`
+	CreatedAt         time.Time `json:"created_at"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type AmassScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domain            string    `json:"domain"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type AssetRelationship struct {
+	ChildAssetID     string                     `json:"child_asset_id"`
+	CreatedAt        time.Time                  `json:"created_at"`
+	ID               string                     `json:"id"`
+	ParentAssetID    string                     `json:"parent_asset_id"`
+	RelationshipData map[string]json.RawMessage `json:"relationship_data,omitempty"`
+	RelationshipType string                     `json:"relationship_type"`
+}
+
+type AssetfinderScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domain            string    `json:"domain"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type AttackSurfaceAsset struct {
+	ARecords            []string                   `json:"a_records,omitempty"`
+	AaaaRecords         []string                   `json:"aaaa_records,omitempty"`
+	ASNCountry          *string                    `json:"asn_country,omitempty"`
+	ASNDescription      *string                    `json:"asn_description,omitempty"`
+	ASNNumber           *string                    `json:"asn_number,omitempty"`
+	ASNOrganization     *string                    `json:"asn_organization,omitempty"`
+	AssetIdentifier     string                     `json:"asset_identifier"`
+	AssetSubtype        *string                    `json:"asset_subtype,omitempty"`
+	AssetType           string                     `json:"asset_type"`
+	CaaRecords          []string                   `json:"caa_records,omitempty"`
+	CIDRBlock           *string                    `json:"cidr_block,omitempty"`
+	CloudProvider       *string                    `json:"cloud_provider,omitempty"`
+	CloudRegion         *string                    `json:"cloud_region,omitempty"`
+	CloudServiceType    *string                    `json:"cloud_service_type,omitempty"`
+	CNAMERecords        []string                   `json:"cname_records,omitempty"`
+	ContentLength       *int                       `json:"content_length,omitempty"`
+	CreatedAt           time.Time                  `json:"created_at"`
+	CreationDate        *time.Time                 `json:"creation_date,omitempty"`
+	DkimRecord          *string                    `json:"dkim_record,omitempty"`
+	DmarcRecord         *string                    `json:"dmarc_record,omitempty"`
+	DNSRecords          []AttackSurfaceDNSRecord   `json:"dns_records,omitempty"`
+	Domain              *string                    `json:"domain,omitempty"`
+	ExpirationDate      *time.Time                 `json:"expiration_date,omitempty"`
+	FindingsJSON        map[string]json.RawMessage `json:"findings_json,omitempty"`
+	FQDN                *string                    `json:"fqdn,omitempty"`
+	HTTPResponseHeaders map[string]json.RawMessage `json:"http_response_headers,omitempty"`
+	ID                  string                     `json:"id"`
+	IPAddress           *string                    `json:"ip_address,omitempty"`
+	IPType              *string                    `json:"ip_type,omitempty"`
+	LastDNSScan         *time.Time                 `json:"last_dns_scan,omitempty"`
+	LastSSLScan         *time.Time                 `json:"last_ssl_scan,omitempty"`
+	LastUpdated         time.Time                  `json:"last_updated"`
+	LastWhoisScan       *time.Time                 `json:"last_whois_scan,omitempty"`
+	MailServers         []string                   `json:"mail_servers,omitempty"`
+	MXRecords           []string                   `json:"mx_records,omitempty"`
+	NameServers         []string                   `json:"name_servers,omitempty"`
+	NSRecords           []string                   `json:"ns_records,omitempty"`
+	Port                *int                       `json:"port,omitempty"`
+	Protocol            *string                    `json:"protocol,omitempty"`
+	PTRRecords          []string                   `json:"ptr_records,omitempty"`
+	Registrar           *string                    `json:"registrar,omitempty"`
+	Relationships       []AssetRelationship        `json:"relationships,omitempty"`
+	ResolvedIPS         []string                   `json:"resolved_ips,omitempty"`
+	ResponseTimeMs      *float64                   `json:"response_time_ms,omitempty"`
+	ResponsiveIPCount   *int                       `json:"responsive_ip_count,omitempty"`
+	ResponsivePortCount *int                       `json:"responsive_port_count,omitempty"`
+	RootDomain          *string                    `json:"root_domain,omitempty"`
+	ScopeTargetID       string                     `json:"scope_target_id"`
+	ScreenshotPath      *string                    `json:"screenshot_path,omitempty"`
+	ServiceBanner       *string                    `json:"service_banner,omitempty"`
+	ServiceName         *string                    `json:"service_name,omitempty"`
+	ServiceProduct      *string                    `json:"service_product,omitempty"`
+	ServiceVersion      *string                    `json:"service_version,omitempty"`
+	SOARecord           map[string]json.RawMessage `json:"soa_record,omitempty"`
+	SpfRecord           *string                    `json:"spf_record,omitempty"`
+	SRVRecords          []string                   `json:"srv_records,omitempty"`
+	SSLCertificate      map[string]json.RawMessage `json:"ssl_certificate,omitempty"`
+	SSLCipherSuite      *string                    `json:"ssl_cipher_suite,omitempty"`
+	SSLExpiryDate       *time.Time                 `json:"ssl_expiry_date,omitempty"`
+	SSLInfo             map[string]json.RawMessage `json:"ssl_info,omitempty"`
+	SSLIssuer           *string                    `json:"ssl_issuer,omitempty"`
+	SSLProtocols        []string                   `json:"ssl_protocols,omitempty"`
+	SSLSubject          *string                    `json:"ssl_subject,omitempty"`
+	SSLVersion          *string                    `json:"ssl_version,omitempty"`
+	Status              []string                   `json:"status,omitempty"`
+	StatusCode          *int                       `json:"status_code,omitempty"`
+	Subdomain           *string                    `json:"subdomain,omitempty"`
+	SubnetSize          *int                       `json:"subnet_size,omitempty"`
+	TakeoverFindings    []SubdomainTakeoverFinding `json:"takeover_findings,omitempty"`
+	Technologies        []string                   `json:"technologies,omitempty"`
+	TechnologyDetails   []DetectedTechnology       `json:"technology_details,omitempty"`
+	Title               *string                    `json:"title,omitempty"`
+	TXTRecords          []string                   `json:"txt_records,omitempty"`
+	UpdatedDate         *time.Time                 `json:"updated_date,omitempty"`
+	URL                 *string                    `json:"url,omitempty"`
+	WebServer           *string                    `json:"web_server,omitempty"`
+	WhoisInfo           map[string]json.RawMessage `json:"whois_info,omitempty"`
+}
+
+type AttackSurfaceAssetsResponse struct {
+	Assets []AttackSurfaceAsset `json:"assets"`
+	Total  int                  `json:"total"`
+}
+
+type AttackSurfaceDNSRecord struct {
+	AssetID     string    `json:"asset_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	ID          string    `json:"id"`
+	RecordType  string    `json:"record_type"`
+	RecordValue string    `json:"record_value"`
+	TTL         *int      `json:"ttl,omitempty"`
+}
+
+type AutoScanConfig struct {
+	Amass                     bool `json:"amass"`
+	Assetfinder               bool `json:"assetfinder"`
+	Cewl                      bool `json:"cewl"`
+	ConsolidateHttpxRound1    bool `json:"consolidate_httpx_round1"`
+	ConsolidateHttpxRound2    bool `json:"consolidate_httpx_round2"`
+	ConsolidateHttpxRound3    bool `json:"consolidate_httpx_round3"`
+	Ctl                       bool `json:"ctl"`
+	Gau                       bool `json:"gau"`
+	Gospider                  bool `json:"gospider"`
+	MaxConsolidatedSubdomains int  `json:"maxConsolidatedSubdomains"`
+	MaxLiveWebServers         int  `json:"maxLiveWebServers"`
+	MaxPermutationCandidates  int  `json:"maxPermutationCandidates"`
+	Metadata                  bool `json:"metadata"`
+	NucleiScreenshot          bool `json:"nuclei_screenshot"`
+	Permutations              bool `json:"permutations"`
+	RecursiveMaxDepth         int  `json:"recursiveMaxDepth"`
+	RecursiveMaxZones         int  `json:"recursiveMaxZones"`
+	RecursiveEnumeration      bool `json:"recursive_enumeration"`
+	Shuffledns                bool `json:"shuffledns"`
+	Subdomainizer             bool `json:"subdomainizer"`
+	Subfinder                 bool `json:"subfinder"`
+	Sublist3r                 bool `json:"sublist3r"`
+}
+
+type AutoScanFinalStatsRequest struct {
+	FinalConsolidatedSubdomains *int   `json:"final_consolidated_subdomains,omitempty"`
+	FinalLiveWebServers         *int   `json:"final_live_web_servers,omitempty"`
+	ScopeTargetID               string `json:"scope_target_id"`
+}
+
+type AutoScanSession struct {
+	ConfigSnapshot              AutoScanConfig  `json:"config_snapshot"`
+	EndedAt                     *time.Time      `json:"ended_at,omitempty"`
+	ErrorMessage                *string         `json:"error_message,omitempty"`
+	FinalConsolidatedSubdomains *int            `json:"final_consolidated_subdomains,omitempty"`
+	FinalLiveWebServers         *int            `json:"final_live_web_servers,omitempty"`
+	ID                          string          `json:"id"`
+	ScopeTargetID               string          `json:"scope_target_id"`
+	StartedAt                   time.Time       `json:"started_at"`
+	Status                      string          `json:"status"`
+	StepsRun                    json.RawMessage `json:"steps_run"`
+}
+
+type AutoScanSessionRequest struct {
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+}
+
+type AutoScanSessionStartRequest struct {
+	ConfigSnapshot AutoScanConfig `json:"config_snapshot"`
+	ScopeTargetID  string         `json:"scope_target_id"`
+}
+
+type AutoScanSessionStatusResponse struct {
+	Message string `json:"message,omitempty"`
+	Status  string `json:"status"`
+	Success bool   `json:"success"`
+}
+
+type AutoScanState struct {
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	CurrentStep   string     `json:"current_step"`
+	ID            string     `json:"id,omitempty"`
+	IsCancelled   bool       `json:"is_cancelled"`
+	IsPaused      bool       `json:"is_paused"`
+	ScopeTargetID string     `json:"scope_target_id"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+type AutoScanStateRequest struct {
+	CurrentStep string `json:"current_step"`
+	IsCancelled bool   `json:"is_cancelled"`
+	IsPaused    bool   `json:"is_paused"`
+}
+
+type AutoScanStateResponse struct {
+	CurrentStep   string `json:"current_step"`
+	IsCancelled   bool   `json:"is_cancelled"`
+	IsPaused      bool   `json:"is_paused"`
+	ScopeTargetID string `json:"scope_target_id"`
+	Success       bool   `json:"success"`
+}
+
+type BurpScanIssue struct {
+	Confidence string `json:"confidence"`
+	Name       string `json:"name"`
+	Severity   string `json:"severity"`
+	URL        string `json:"url"`
+}
+
+type BurpScanStatus struct {
+	Errors        []string        `json:"errors"`
+	Issues        []BurpScanIssue `json:"issues"`
+	NetworkErrors int             `json:"network_errors"`
+	Progress      int             `json:"progress"`
+	RequestsMade  int             `json:"requests_made"`
+	ScanStatus    string          `json:"scan_status"`
+	TaskID        string          `json:"task_id"`
+}
+
+type BurpSendRequest struct {
+	Mode               string   `json:"mode,omitempty"`
+	ScanConfigurations []string `json:"scan_configurations,omitempty"`
+	ScopeTargetID      string   `json:"scope_target_id"`
+	TargetURLIDS       []string `json:"target_url_ids"`
+}
+
+type BurpSendResponse struct {
+	Mode    string           `json:"mode"`
+	Results []BurpSendResult `json:"results"`
+}
+
+type BurpSendResult struct {
+	ProxiedStatusCode int    `json:"proxied_status_code,omitempty"`
+	ProxyError        string `json:"proxy_error,omitempty"`
+	ScanError         string `json:"scan_error,omitempty"`
+	ScanLaunched      bool   `json:"scan_launched"`
+	ScanTaskID        string `json:"scan_task_id,omitempty"`
+	SentToProxy       bool   `json:"sent_to_proxy"`
+	URL               string `json:"url"`
+}
+
+type BurpStatusResponse struct {
+	APIError       string `json:"api_error"`
+	APIReachable   bool   `json:"api_reachable"`
+	ProxyAddress   string `json:"proxy_address"`
+	ProxyEnabled   bool   `json:"proxy_enabled"`
+	ProxyReachable bool   `json:"proxy_reachable"`
+}
+
+type CORSProbe struct {
+	AllowCredentials bool   `json:"allow_credentials"`
+	AllowOrigin      string `json:"allow_origin"`
+	Kind             string `json:"kind"`
+	Origin           string `json:"origin"`
+	Reflected        bool   `json:"reflected"`
+}
+
+type CTLCompanyScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CompanyName       string    `json:"company_name"`
+	CreatedAt         time.Time `json:"created_at"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type CTLScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domain            string    `json:"domain"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type CeWLScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+	URL               string    `json:"url"`
+}
+
+type CensysCompanyScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CompanyName       string    `json:"company_name"`
+	CreatedAt         time.Time `json:"created_at"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type CloudDomainsResponse struct {
+	AWSDomains   []string `json:"aws_domains"`
+	AzureDomains []string `json:"azure_domains"`
+	GCPDomains   []string `json:"gcp_domains"`
+}
+
+type CloudEnumConfig struct {
+	AdditionalResolvers string              `json:"additional_resolvers"`
+	BruteFilePath       string              `json:"brute_file_path"`
+	CustomDNSServer     string              `json:"custom_dns_server"`
+	DNSResolverMode     string              `json:"dns_resolver_mode"`
+	EnabledPlatforms    map[string]bool     `json:"enabled_platforms"`
+	Keywords            []string            `json:"keywords"`
+	MutationsFilePath   string              `json:"mutations_file_path"`
+	ResolverConfig      string              `json:"resolver_config"`
+	ResolverFilePath    string              `json:"resolver_file_path"`
+	SelectedRegions     map[string][]string `json:"selected_regions"`
+	SelectedServices    map[string][]string `json:"selected_services"`
+	Threads             int                 `json:"threads"`
+}
+
+type CloudEnumScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CompanyName       string    `json:"company_name"`
+	CreatedAt         time.Time `json:"created_at"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type CompanyDomain struct {
+	CreatedAt     time.Time `json:"created_at"`
+	Domain        string    `json:"domain"`
+	ID            string    `json:"id"`
+	ScopeTargetID string    `json:"scope_target_id"`
+}
+
+type CompanyDomainCreatedResponse struct {
+	Domain        string `json:"domain"`
+	ID            string `json:"id"`
+	ScopeTargetID string `json:"scope_target_id"`
+	Success       bool   `json:"success"`
+}
+
+type CompanyDomainsConfig struct {
+	Domains                []string `json:"domains"`
+	IncludeWildcardResults bool     `json:"include_wildcard_results"`
+	WildcardDomains        []string `json:"wildcard_domains"`
+}
+
+type CompanyDomainsResponse struct {
+	Count   int      `json:"count"`
+	Domains []string `json:"domains"`
+}
+
+type CompanyMetaDataResult struct {
+	ContentLength       int      `json:"content_length"`
+	CreatedAt           string   `json:"created_at"`
+	DNSARecords         []string `json:"dns_a_records"`
+	DNSAaaaRecords      []string `json:"dns_aaaa_records"`
+	DNSCNAMERecords     []string `json:"dns_cname_records"`
+	DNSMXRecords        []string `json:"dns_mx_records"`
+	DNSNSRecords        []string `json:"dns_ns_records"`
+	DNSPTRRecords       []string `json:"dns_ptr_records"`
+	DNSSRVRecords       []string `json:"dns_srv_records"`
+	DNSTXTRecords       []string `json:"dns_txt_records"`
+	FfufResults         string   `json:"ffuf_results"`
+	FindingsJSON        string   `json:"findings_json"`
+	HasDeprecatedTLS    bool     `json:"has_deprecated_tls"`
+	HasExpiredSSL       bool     `json:"has_expired_ssl"`
+	HasMismatchedSSL    bool     `json:"has_mismatched_ssl"`
+	HasRevokedSSL       bool     `json:"has_revoked_ssl"`
+	HasSelfSignedSSL    bool     `json:"has_self_signed_ssl"`
+	HasUntrustedRootSSL bool     `json:"has_untrusted_root_ssl"`
+	HTTPResponse        string   `json:"http_response"`
+	HTTPResponseHeaders string   `json:"http_response_headers"`
+	ID                  string   `json:"id"`
+	KatanaResults       string   `json:"katana_results"`
+	ROIScore            float64  `json:"roi_score"`
+	ScopeTargetID       string   `json:"scope_target_id"`
+	ScreenshotID        string   `json:"screenshot_id"`
+	StatusCode          int      `json:"status_code"`
+	Technologies        []string `json:"technologies"`
+	Title               string   `json:"title"`
+	URL                 string   `json:"url"`
+	WebServer           string   `json:"web_server"`
+}
+
+type CompanyMetaDataScan struct {
+	CreatedAt     string `json:"created_at"`
+	ErrorMessage  string `json:"error_message"`
+	ExecutionTime string `json:"execution_time"`
+	IPPortScanID  string `json:"ip_port_scan_id"`
+	ScanID        string `json:"scan_id"`
+	ScopeTargetID string `json:"scope_target_id"`
+	Status        string `json:"status"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+type CompanyMetaDataScanRequest struct {
+	IPPortScanID  string `json:"ip_port_scan_id"`
+	ScopeTargetID string `json:"scope_target_id"`
+}
+
+type CompanyScanRequest struct {
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+	CompanyName       string `json:"company_name"`
+}
+
+type ConfigSavedResponse struct {
+	ConfigID string `json:"config_id"`
+	Message  string `json:"message"`
+	Success  bool   `json:"success"`
+}
+
+type ConsolidatedCompanyDomainsResponse struct {
+	Count   int      `json:"count"`
+	Domains []string `json:"domains"`
+}
+
+type ConsolidatedNetworkRange struct {
+	ASN          string `json:"asn"`
+	CIDRBlock    string `json:"cidr_block"`
+	Country      string `json:"country"`
+	Description  string `json:"description"`
+	ID           string `json:"id"`
+	Organization string `json:"organization"`
+	ScanType     string `json:"scan_type,omitempty"`
+	Source       string `json:"source"`
+}
+
+type ConsolidatedNetworkRangesResponse struct {
+	Count         int                        `json:"count"`
+	NetworkRanges []ConsolidatedNetworkRange `json:"network_ranges"`
+}
+
+type ConsolidatedSubdomainsResponse struct {
+	Count            int      `json:"count"`
+	Subdomains       []string `json:"subdomains"`
+	WildcardFiltered int      `json:"wildcard_filtered"`
+}
+
+type ConsolidationResult struct {
+	Asns               int                  `json:"asns"`
+	Assets             []AttackSurfaceAsset `json:"assets"`
+	CloudAssets        int                  `json:"cloud_assets"`
+	ConsolidatedAt     time.Time            `json:"consolidated_at"`
+	ExecutionTime      string               `json:"execution_time"`
+	Fqdns              int                  `json:"fqdns"`
+	IPAddresses        int                  `json:"ip_addresses"`
+	LiveWebServers     int                  `json:"live_web_servers"`
+	NetworkRanges      int                  `json:"network_ranges"`
+	NetworkServices    int                  `json:"network_services"`
+	TotalAssets        int                  `json:"total_assets"`
+	TotalRelationships int                  `json:"total_relationships"`
+}
+
+type ContentDiscoveryResult struct {
+	BaseURL          string    `json:"base_url"`
+	ContentLength    int       `json:"content_length"`
+	ContentType      string    `json:"content_type"`
+	CreatedAt        time.Time `json:"created_at"`
+	Depth            int       `json:"depth"`
+	ID               string    `json:"id"`
+	IsDirectory      bool      `json:"is_directory"`
+	Lines            int       `json:"lines"`
+	Path             string    `json:"path"`
+	RedirectLocation string    `json:"redirect_location"`
+	ScanID           *string   `json:"scan_id,omitempty"`
+	ScopeTargetID    string    `json:"scope_target_id"`
+	StatusCode       int       `json:"status_code"`
+	URL              string    `json:"url"`
+	Wordlist         string    `json:"wordlist"`
+	Words            int       `json:"words"`
+}
+
+type ContentDiscoveryScan struct {
+	CreatedAt     time.Time `json:"created_at"`
+	Error         *string   `json:"error,omitempty"`
+	ExecutionTime *string   `json:"execution_time,omitempty"`
+	ID            string    `json:"id"`
+	MaxDepth      int       `json:"max_depth"`
+	RequestsSent  int       `json:"requests_sent"`
+	ResultsCount  int       `json:"results_count"`
+	ScanID        string    `json:"scan_id"`
+	ScopeTargetID string    `json:"scope_target_id"`
+	Status        string    `json:"status"`
+	URLSScanned   int       `json:"urls_scanned"`
+}
+
+type ContentDiscoveryScanRequest struct {
+	MaxDepth      *int     `json:"max_depth,omitempty"`
+	ScopeTargetID string   `json:"scope_target_id"`
+	URLS          []string `json:"urls,omitempty"`
+	WordlistIDS   []string `json:"wordlist_ids,omitempty"`
+}
+
+type ContentWordlist struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Tags      []string  `json:"tags"`
+	UpdatedAt time.Time `json:"updated_at"`
+	WordCount int       `json:"word_count"`
+}
+
+type ContentWordlistRequest struct {
+	Name  string   `json:"name"`
+	Tags  []string `json:"tags"`
+	Words []string `json:"words"`
+}
+
+type ContentWordlistTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+type CorpusURL struct {
+	Categories    []string            `json:"categories"`
+	ExampleURL    string              `json:"example_url"`
+	Extension     *string             `json:"extension,omitempty"`
+	FirstSeen     time.Time           `json:"first_seen"`
+	HitCount      int                 `json:"hit_count"`
+	Host          string              `json:"host"`
+	ID            string              `json:"id"`
+	LastSeen      time.Time           `json:"last_seen"`
+	ParamValues   map[string][]string `json:"param_values"`
+	Params        []string            `json:"params"`
+	Pattern       string              `json:"pattern"`
+	ScanID        *string             `json:"scan_id,omitempty"`
+	Scheme        string              `json:"scheme"`
+	ScopeTargetID string              `json:"scope_target_id"`
+	Source        string              `json:"source"`
+}
+
+type CorpusURLPage struct {
+	CategoryCounts map[string]int `json:"category_counts"`
+	Page           int            `json:"page"`
+	PageSize       int            `json:"page_size"`
+	Total          int            `json:"total"`
+	URLS           []CorpusURL    `json:"urls"`
+}
+
+type CountResponse struct {
+	Count int `json:"count"`
+}
+
+type DKIMSelector struct {
+	KeyBits  int    `json:"key_bits,omitempty"`
+	KeyType  string `json:"key_type"`
+	Revoked  bool   `json:"revoked"`
+	Selector string `json:"selector"`
+}
+
+type DNSAuditFinding struct {
+	CheckType     string          `json:"check_type"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Details       string          `json:"details"`
+	Domain        string          `json:"domain"`
+	Evidence      json.RawMessage `json:"evidence,omitempty"`
+	ID            string          `json:"id"`
+	Nameserver    string          `json:"nameserver"`
+	NameserverIP  string          `json:"nameserver_ip"`
+	ScanID        string          `json:"scan_id"`
+	ScopeTargetID string          `json:"scope_target_id"`
+	Severity      string          `json:"severity"`
+	Title         string          `json:"title"`
+}
+
+type DNSAuditScan struct {
+	CreatedAt       time.Time `json:"created_at"`
+	DiscoveredCount int       `json:"discovered_count"`
+	Domains         []string  `json:"domains"`
+	Error           *string   `json:"error,omitempty"`
+	ExecutionTime   *string   `json:"execution_time,omitempty"`
+	FindingsCount   int       `json:"findings_count"`
+	ID              string    `json:"id"`
+	NewCount        int       `json:"new_count"`
+	Result          *string   `json:"result,omitempty"`
+	ScanID          string    `json:"scan_id"`
+	ScopeTargetID   string    `json:"scope_target_id"`
+	Status          string    `json:"status"`
+}
+
+type DNSAuditScanRequest struct {
+	Domains       []string `json:"domains,omitempty"`
+	ScopeTargetID string   `json:"scope_target_id"`
+}
+
+type DNSRecord struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+	Record    string    `json:"record"`
+	ScanID    string    `json:"scan_id"`
+	Type      string    `json:"type"`
+}
+
+type DNSResolversRequest struct {
+	Resolvers []string `json:"resolvers"`
+}
+
+type DNSxDNSRecord struct {
+	CreatedAt time.Time `json:"created_at"`
+	Domain    string    `json:"domain"`
+	ID        string    `json:"id"`
+	Record    string    `json:"record"`
+	ScanID    string    `json:"scan_id"`
+	Type      string    `json:"type"`
+}
+
+type DNSxRawResult struct {
+	CreatedAt time.Time `json:"created_at"`
+	Domain    string    `json:"domain"`
+	ID        string    `json:"id"`
+	RawOutput string    `json:"raw_output"`
+	ScanID    string    `json:"scan_id"`
+}
+
+type DNSxScanStatus struct {
+	Command       *string   `json:"command,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	Domains       []string  `json:"domains"`
+	Error         *string   `json:"error,omitempty"`
+	ExecutionTime *string   `json:"execution_time,omitempty"`
+	ID            string    `json:"id"`
+	Result        *string   `json:"result,omitempty"`
+	ScanID        string    `json:"scan_id"`
+	ScopeTargetID string    `json:"scope_target_id"`
+	Status        string    `json:"status"`
+	Stderr        *string   `json:"stderr,omitempty"`
+	Stdout        *string   `json:"stdout,omitempty"`
+}
+
+type DatabaseExportRequest struct {
+	ScopeTargetIDS []string `json:"scope_target_ids"`
+}
+
+type DatabaseImportRequest struct {
+	Data []byte `json:"data"`
+}
+
+type DatabaseImportResponse struct {
+	ImportedScopeTargets int    `json:"imported_scope_targets"`
+	ImportedTables       int    `json:"imported_tables"`
+	Message              string `json:"message"`
+	TotalRecords         int    `json:"total_records"`
+}
+
+type DatabaseImportURLRequest struct {
+	URL string `json:"url"`
+}
+
+type DefectDojoExport struct {
+	Findings []map[string]json.RawMessage `json:"findings"`
+}
+
+type DefectDojoImportResponse struct {
+	Applied   int      `json:"applied"`
+	Skipped   int      `json:"skipped"`
+	Unmatched []string `json:"unmatched"`
+}
+
+type DeletedCountResponse struct {
+	DeletedCount int    `json:"deleted_count"`
+	Message      string `json:"message"`
+}
+
+type DeletedDomainsResponse struct {
+	Count   int    `json:"count"`
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+}
+
+type DetectedTechnology struct {
+	Categories []string `json:"categories"`
+	Confidence int      `json:"confidence"`
+	Name       string   `json:"name"`
+	Version    string   `json:"version,omitempty"`
+	Website    string   `json:"website,omitempty"`
+}
+
+type DiscoveredIP struct {
+	DiscoveredAt time.Time `json:"discovered_at"`
+	Hostname     string    `json:"hostname,omitempty"`
+	ID           string    `json:"id"`
+	IPAddress    string    `json:"ip_address"`
+	NetworkRange string    `json:"network_range"`
+	PingTimeMs   *float64  `json:"ping_time_ms,omitempty"`
+	ScanID       string    `json:"scan_id"`
+}
+
+type DiscoveredService struct {
+	Banner          string    `json:"banner,omitempty"`
+	DetectionMethod string    `json:"detection_method"`
+	DiscoveredAt    time.Time `json:"discovered_at"`
+	Hostname        string    `json:"hostname,omitempty"`
+	ID              string    `json:"id"`
+	IPAddress       string    `json:"ip_address"`
+	Port            int       `json:"port"`
+	Product         string    `json:"product,omitempty"`
+	ScanID          string    `json:"scan_id"`
+	Service         string    `json:"service"`
+	TLS             bool      `json:"tls"`
+	Transport       string    `json:"transport"`
+	Version         string    `json:"version,omitempty"`
+}
+
+type DomainScanRequest struct {
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+	FQDN              string `json:"fqdn"`
+}
+
+type DomainsScanRequest struct {
+	AutoScanSessionID string   `json:"auto_scan_session_id,omitempty"`
+	Domains           []string `json:"domains"`
+}
+
+type EmailSecurityFinding struct {
+	Check    string `json:"check"`
+	Details  string `json:"details"`
+	Severity string `json:"severity"`
+	Title    string `json:"title"`
+}
+
+type EmailSecurityResult struct {
+	CheckedAt     time.Time              `json:"checked_at"`
+	DkimSelectors []DKIMSelector         `json:"dkim_selectors"`
+	DmarcPolicy   *string                `json:"dmarc_policy,omitempty"`
+	DmarcRecord   *string                `json:"dmarc_record,omitempty"`
+	Domain        string                 `json:"domain"`
+	Findings      []EmailSecurityFinding `json:"findings"`
+	Grade         string                 `json:"grade"`
+	ID            string                 `json:"id"`
+	MtaStsMode    *string                `json:"mta_sts_mode,omitempty"`
+	MXRecords     []string               `json:"mx_records"`
+	ScanID        string                 `json:"scan_id"`
+	ScopeTargetID string                 `json:"scope_target_id"`
+	Score         int                    `json:"score"`
+	SpfLookups    int                    `json:"spf_lookups"`
+	SpfRecord     *string                `json:"spf_record,omitempty"`
+	TLSRptRecord  *string                `json:"tls_rpt_record,omitempty"`
+}
+
+type EmailSecurityScan struct {
+	CreatedAt      time.Time `json:"created_at"`
+	DomainsChecked int       `json:"domains_checked"`
+	Error          *string   `json:"error,omitempty"`
+	ExecutionTime  *string   `json:"execution_time,omitempty"`
+	FindingsCount  int       `json:"findings_count"`
+	ID             string    `json:"id"`
+	ScanID         string    `json:"scan_id"`
+	ScopeTargetID  string    `json:"scope_target_id"`
+	Status         string    `json:"status"`
+}
+
+type EmailSecurityScanRequest struct {
+	Domains       []string `json:"domains,omitempty"`
+	ScopeTargetID string   `json:"scope_target_id"`
+}
+
+type EmailSecuritySummary struct {
+	AverageScore  int                   `json:"average_score"`
+	Domains       int                   `json:"domains"`
+	Grades        map[string]int        `json:"grades"`
+	LatestScan    *EmailSecurityScan    `json:"latest_scan,omitempty"`
+	Results       []EmailSecurityResult `json:"results"`
+	ScopeTargetID string                `json:"scope_target_id"`
+	Severities    map[string]int        `json:"severities"`
+}
+
+type ErrorResponse struct {
+	Error  string `json:"error"`
+	Status int    `json:"status"`
+}
+
+type ExportFileSummary struct {
+	AmassEnumAnalysis map[string]json.RawMessage `json:"amass_enum_analysis"`
+	Metadata          ExportMetadata             `json:"metadata"`
+	ScopeTargets      int                        `json:"scope_targets"`
+	Tables            map[string]int             `json:"tables"`
+}
+
+type ExportMetadata struct {
+	ExportedAt     time.Time `json:"exported_at"`
+	ScopeTargetIDS []string  `json:"scope_target_ids"`
+	ScopeTargets   []string  `json:"scope_targets"`
+	TablesExported []string  `json:"tables_exported"`
+	TotalRecords   int       `json:"total_records"`
+	Version        string    `json:"version"`
+}
+
+type ExportRequest struct {
+	Amass                     bool `json:"amass"`
+	AmassEnumCompany          bool `json:"amass_enum_company"`
+	AmassIntel                bool `json:"amass_intel"`
+	Assetfinder               bool `json:"assetfinder"`
+	CensysCompany             bool `json:"censys_company"`
+	Cewl                      bool `json:"cewl"`
+	CloudEnum                 bool `json:"cloud_enum"`
+	ConsolidatedAttackSurface bool `json:"consolidated_attack_surface"`
+	Ctl                       bool `json:"ctl"`
+	DnsxCompany               bool `json:"dnsx_company"`
+	Gau                       bool `json:"gau"`
+	GithubRecon               bool `json:"github_recon"`
+	Gospider                  bool `json:"gospider"`
+	Httpx                     bool `json:"httpx"`
+	IPPortScans               bool `json:"ip_port_scans"`
+	KatanaCompany             bool `json:"katana_company"`
+	MetabigorCompany          bool `json:"metabigor_company"`
+	Nuclei                    bool `json:"nuclei"`
+	ROI                       bool `json:"roi"`
+	SecuritytrailsCompany     bool `json:"securitytrails_company"`
+	ShodanCompany             bool `json:"shodan_company"`
+	Shuffledns                bool `json:"shuffledns"`
+	Subdomainizer             bool `json:"subdomainizer"`
+	Subdomains                bool `json:"subdomains"`
+	Subfinder                 bool `json:"subfinder"`
+	Sublist3r                 bool `json:"sublist3r"`
+}
+
+type ExportScopeTarget struct {
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	ID          string    `json:"id"`
+	ScopeTarget string    `json:"scope_target"`
+	Type        string    `json:"type"`
+}
+
+type ExportableFinding struct {
+	CurlCommand      string         `json:"curl_command,omitempty"`
+	Description      string         `json:"description,omitempty"`
+	ExtractedResults []string       `json:"extracted_results,omitempty"`
+	Fingerprint      string         `json:"fingerprint"`
+	Host             string         `json:"host"`
+	ID               string         `json:"id"`
+	MatchedAt        string         `json:"matched_at"`
+	Name             string         `json:"name"`
+	References       []string       `json:"references,omitempty"`
+	Severity         string         `json:"severity"`
+	Source           string         `json:"source"`
+	Tags             []string       `json:"tags,omitempty"`
+	TemplateID       string         `json:"template_id"`
+	Timestamp        string         `json:"timestamp,omitempty"`
+	Triage           *FindingTriage `json:"triage,omitempty"`
+}
+
+type FindingTriage struct {
+	Active       bool      `json:"active"`
+	Duplicate    bool      `json:"duplicate"`
+	FalseP       bool      `json:"false_p"`
+	IsMitigated  bool      `json:"is_mitigated"`
+	Notes        string    `json:"notes,omitempty"`
+	OutOfScope   bool      `json:"out_of_scope"`
+	RiskAccepted bool      `json:"risk_accepted"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Verified     bool      `json:"verified"`
+}
+
+type FingerprintRuleFile struct {
+	CategoryCount   int       `json:"category_count"`
+	CreatedAt       time.Time `json:"created_at"`
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	TechnologyCount int       `json:"technology_count"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type FingerprintURLRequest struct {
+	URL string `json:"url"`
+}
+
+type GauScanRequest struct {
+	AutoScanSessionID string   `json:"auto_scan_session_id,omitempty"`
+	FQDN              string   `json:"fqdn"`
+	Providers         []string `json:"providers,omitempty"`
+}
+
+type GauScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domain            string    `json:"domain"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type GitHubReconScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CompanyName       string    `json:"company_name"`
+	CreatedAt         time.Time `json:"created_at"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type GoSpiderScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domain            string    `json:"domain"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type HttpxScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domain            string    `json:"domain"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type IPPortScan struct {
+	AutoScanSessionID      string            `json:"auto_scan_session_id,omitempty"`
+	CreatedAt              time.Time         `json:"created_at"`
+	CustomPorts            string            `json:"custom_ports,omitempty"`
+	ErrorMessage           string            `json:"error_message,omitempty"`
+	ExecutionTime          string            `json:"execution_time,omitempty"`
+	ID                     string            `json:"id"`
+	IPSSkipped             int               `json:"ips_skipped"`
+	LiveWebServersFound    int               `json:"live_web_servers_found"`
+	PortProfile            string            `json:"port_profile"`
+	ProcessedNetworkRanges int               `json:"processed_network_ranges"`
+	ScanConfig             *IPPortScanConfig `json:"scan_config,omitempty"`
+	ScanID                 string            `json:"scan_id"`
+	ScopeTargetID          string            `json:"scope_target_id"`
+	ServicesFound          int               `json:"services_found"`
+	Status                 string            `json:"status"`
+	TotalIPSDiscovered     int               `json:"total_ips_discovered"`
+	TotalIPSProbed         int               `json:"total_ips_probed"`
+	TotalNetworkRanges     int               `json:"total_network_ranges"`
+	TotalPortsScanned      int               `json:"total_ports_scanned"`
+	TruncatedRanges        int               `json:"truncated_ranges"`
+}
+
+type IPPortScanConfig struct {
+	BannerTimeoutMs     int        `json:"banner_timeout_ms"`
+	CustomPorts         string     `json:"custom_ports"`
+	DiscoveryPorts      string     `json:"discovery_ports"`
+	HostDiscovery       string     `json:"host_discovery"`
+	HostProbeTimeoutMs  int        `json:"host_probe_timeout_ms"`
+	IPSampling          string     `json:"ip_sampling"`
+	MaxConcurrentIPS    int        `json:"max_concurrent_ips"`
+	MaxConcurrentPorts  int        `json:"max_concurrent_ports"`
+	MaxIPSPerRange      int        `json:"max_ips_per_range"`
+	PortProfile         string     `json:"port_profile"`
+	PortScanTimeoutMs   int        `json:"port_scan_timeout_ms"`
+	UpdatedAt           *time.Time `json:"updated_at,omitempty"`
+	WebServiceTimeoutMs int        `json:"web_service_timeout_ms"`
+}
+
+type IPPortScanRequest struct {
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+	CustomPorts       string `json:"custom_ports,omitempty"`
+	PortProfile       string `json:"port_profile,omitempty"`
+	ScopeTargetID     string `json:"scope_target_id"`
+}
+
+type IPPortScansResponse struct {
+	Count int          `json:"count"`
+	Scans []IPPortScan `json:"scans"`
+}
+
+type IntelASNResponse struct {
+	ASNNumber    string `json:"asn_number"`
+	Country      string `json:"country"`
+	Description  string `json:"description"`
+	Organization string `json:"organization"`
+	ScanID       string `json:"scan_id"`
+}
+
+type IntelNetworkRangeResponse struct {
+	ASN          string `json:"asn"`
+	CIDRBlock    string `json:"cidr_block"`
+	Country      string `json:"country"`
+	Description  string `json:"description"`
+	ID           string `json:"id"`
+	Organization string `json:"organization"`
+	ScanID       string `json:"scan_id"`
+}
+
+type InvestigateStatus struct {
+	Command       *string   `json:"command,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	Error         *string   `json:"error,omitempty"`
+	ExecutionTime *string   `json:"execution_time,omitempty"`
+	ID            string    `json:"id"`
+	Result        *string   `json:"result,omitempty"`
+	ScanID        string    `json:"scan_id"`
+	ScopeTargetID string    `json:"scope_target_id"`
+	Status        string    `json:"status"`
+	Stderr        *string   `json:"stderr,omitempty"`
+	Stdout        *string   `json:"stdout,omitempty"`
+}
+
+type JSAnalysisScan struct {
+	ChangedCount    int       `json:"changed_count"`
+	CreatedAt       time.Time `json:"created_at"`
+	EndpointsCount  int       `json:"endpoints_count"`
+	Error           *string   `json:"error,omitempty"`
+	ExecutionTime   *string   `json:"execution_time,omitempty"`
+	FilesAnalyzed   int       `json:"files_analyzed"`
+	ID              string    `json:"id"`
+	ScanID          string    `json:"scan_id"`
+	ScopeTargetID   string    `json:"scope_target_id"`
+	SecretsCount    int       `json:"secrets_count"`
+	SourceMapsCount int       `json:"source_maps_count"`
+	Status          string    `json:"status"`
+}
+
+type JSFile struct {
+	ChangeCount    int        `json:"change_count"`
+	Endpoints      []string   `json:"endpoints"`
+	FirstSeen      time.Time  `json:"first_seen"`
+	ID             string     `json:"id"`
+	LastChanged    *time.Time `json:"last_changed,omitempty"`
+	LastSeen       time.Time  `json:"last_seen"`
+	PageURL        string     `json:"page_url"`
+	PreviousSha256 *string    `json:"previous_sha256,omitempty"`
+	ScanID         string     `json:"scan_id"`
+	ScopeTargetID  string     `json:"scope_target_id"`
+	SecretsCount   int        `json:"secrets_count"`
+	Sha256         string     `json:"sha256"`
+	Size           int        `json:"size"`
+	SourceFiles    int        `json:"source_files"`
+	SourceMapURL   *string    `json:"source_map_url,omitempty"`
+	URL            string     `json:"url"`
+}
+
+type JSSecret struct {
+	Context       string    `json:"context"`
+	CreatedAt     time.Time `json:"created_at"`
+	ID            string    `json:"id"`
+	JSURL         string    `json:"js_url"`
+	Line          int       `json:"line"`
+	Match         string    `json:"match"`
+	Rule          string    `json:"rule"`
+	ScanID        string    `json:"scan_id"`
+	ScopeTargetID string    `json:"scope_target_id"`
+	Severity      string    `json:"severity"`
+	SourcePath    string    `json:"source_path"`
+}
+
+type JSSourceFile struct {
+	CreatedAt     time.Time `json:"created_at"`
+	ID            string    `json:"id"`
+	JSURL         string    `json:"js_url"`
+	Path          string    `json:"path"`
+	ScopeTargetID string    `json:"scope_target_id"`
+	Size          int       `json:"size"`
+}
+
+type KatanaCompanyCloudAsset struct {
+	CreatedAt     time.Time `json:"created_at"`
+	Description   string    `json:"description"`
+	Domain        string    `json:"domain"`
+	ID            string    `json:"id"`
+	LastScannedAt time.Time `json:"last_scanned_at"`
+	RootDomain    string    `json:"root_domain"`
+	Service       string    `json:"service"`
+	SourceURL     string    `json:"source_url"`
+	Type          string    `json:"type"`
+	URL           string    `json:"url"`
+}
+
+type KatanaCompanyConfig struct {
+	CreatedAt               time.Time `json:"created_at"`
+	ID                      string    `json:"id"`
+	IncludeWildcardResults  bool      `json:"include_wildcard_results"`
+	ScopeTargetID           string    `json:"scope_target_id"`
+	SelectedDomains         []string  `json:"selected_domains"`
+	SelectedLiveWebServers  []string  `json:"selected_live_web_servers"`
+	SelectedWildcardDomains []string  `json:"selected_wildcard_domains"`
+	UpdatedAt               time.Time `json:"updated_at"`
+}
+
+type KatanaCompanyScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domains           []string  `json:"domains"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type KeyValues struct {
+	APIKey    string `json:"api_key,omitempty"`
+	AppID     string `json:"app_id,omitempty"`
+	AppSecret string `json:"app_secret,omitempty"`
+}
+
+type LiveWebServer struct {
+	ContentLength     *int                 `json:"content_length,omitempty"`
+	Hostname          string               `json:"hostname,omitempty"`
+	ID                string               `json:"id"`
+	IPAddress         string               `json:"ip_address"`
+	LastChecked       time.Time            `json:"last_checked"`
+	Port              int                  `json:"port"`
+	Protocol          string               `json:"protocol"`
+	ResponseTimeMs    *float64             `json:"response_time_ms,omitempty"`
+	ScanID            string               `json:"scan_id"`
+	ServerHeader      string               `json:"server_header,omitempty"`
+	StatusCode        *int                 `json:"status_code,omitempty"`
+	Technologies      []string             `json:"technologies,omitempty"`
+	TechnologyDetails []DetectedTechnology `json:"technology_details,omitempty"`
+	Title             string               `json:"title,omitempty"`
+	URL               string               `json:"url"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+type MetaDataStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domain            string    `json:"domain"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type MetabigorASNData struct {
+	ASNNumber    string `json:"asn_number"`
+	Country      string `json:"country"`
+	ID           string `json:"id"`
+	Organization string `json:"organization"`
+	ScanID       string `json:"scan_id"`
+	ScanType     string `json:"scan_type"`
+}
+
+type MetabigorASNScanRequest struct {
+	ASNNumber         string `json:"asn_number"`
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+	ScanType          string `json:"scan_type"`
+}
+
+type MetabigorCompanyScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CompanyName       string    `json:"company_name"`
+	CreatedAt         time.Time `json:"created_at"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type MetabigorIPIntelligence struct {
+	ASN          string   `json:"asn"`
+	City         string   `json:"city"`
+	Country      string   `json:"country"`
+	IPAddress    string   `json:"ip_address"`
+	OpenPorts    []int    `json:"open_ports"`
+	Organization string   `json:"organization"`
+	ScanID       string   `json:"scan_id"`
+	Services     []string `json:"services"`
+}
+
+type MetabigorIPIntelligenceRequest struct {
+	AutoScanSessionID string   `json:"auto_scan_session_id,omitempty"`
+	IPAddresses       []string `json:"ip_addresses"`
+	ScanType          string   `json:"scan_type"`
+}
+
+type MetabigorNetworkRange struct {
+	ASN          string `json:"asn"`
+	CIDRBlock    string `json:"cidr_block"`
+	Country      string `json:"country"`
+	ID           string `json:"id"`
+	Organization string `json:"organization"`
+	ScanID       string `json:"scan_id"`
+	ScanType     string `json:"scan_type"`
+}
+
+type NucleiConfig struct {
+	CreatedAt         *time.Time                   `json:"created_at,omitempty"`
+	Severities        []string                     `json:"severities"`
+	Targets           []string                     `json:"targets"`
+	Templates         []string                     `json:"templates"`
+	UploadedTemplates []map[string]json.RawMessage `json:"uploaded_templates"`
+}
+
+type NucleiScan struct {
+	CreatedAt     time.Time `json:"created_at"`
+	Error         string    `json:"error"`
+	ExecutionTime string    `json:"execution_time"`
+	Result        string    `json:"result"`
+	ScanID        string    `json:"scan_id"`
+	Status        string    `json:"status"`
+	Targets       []string  `json:"targets"`
+	Templates     []string  `json:"templates"`
+}
+
+type NucleiScanStartedResponse struct {
+	Message string `json:"message"`
+	ScanID  string `json:"scan_id"`
+	Status  string `json:"status"`
+}
+
+type NucleiScanStatus struct {
+	CreatedAt     time.Time `json:"created_at"`
+	Error         string    `json:"error"`
+	ExecutionTime string    `json:"execution_time"`
+	Result        string    `json:"result"`
+	ScanID        string    `json:"scan_id"`
+	Status        string    `json:"status"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type NucleiScreenshotStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domain            string    `json:"domain"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type PageCapture struct {
+	ConsoleErrors []string `json:"console_errors"`
+	Error         string   `json:"error,omitempty"`
+	FinalURL      string   `json:"final_url"`
+	StatusCode    int      `json:"status_code"`
+	Title         string   `json:"title"`
+	URL           string   `json:"url"`
+}
+
+type ParameterMiningScan struct {
+	CreatedAt       time.Time `json:"created_at"`
+	Error           *string   `json:"error,omitempty"`
+	ExecutionTime   *string   `json:"execution_time,omitempty"`
+	ID              string    `json:"id"`
+	ParametersCount int       `json:"parameters_count"`
+	ScanID          string    `json:"scan_id"`
+	ScopeTargetID   string    `json:"scope_target_id"`
+	Status          string    `json:"status"`
+	TaggedCount     int       `json:"tagged_count"`
+	URLSProcessed   int       `json:"urls_processed"`
+}
+
+type PermutationScanRequest struct {
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+	FQDN              string `json:"fqdn"`
+	MaxCandidates     int    `json:"max_candidates,omitempty"`
+}
+
+type PermutationScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	CandidateCount    int       `json:"candidate_count"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domain            string    `json:"domain"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	ResolvedCount     int       `json:"resolved_count"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Truncated         bool      `json:"truncated"`
+}
+
+type ROIScoreRequest struct {
+	ROIScore int `json:"roi_score"`
+}
+
+type RecursiveEnumerationScan struct {
+	AutoScanSessionID *string         `json:"auto_scan_session_id,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+	Domain            string          `json:"domain"`
+	Error             *string         `json:"error,omitempty"`
+	ExecutionTime     *string         `json:"execution_time,omitempty"`
+	ID                string          `json:"id"`
+	MaxDepth          int             `json:"max_depth"`
+	MaxZones          int             `json:"max_zones"`
+	NewCount          int             `json:"new_count"`
+	Result            *string         `json:"result,omitempty"`
+	ScanID            string          `json:"scan_id"`
+	ScopeTargetID     string          `json:"scope_target_id"`
+	Status            string          `json:"status"`
+	Zones             []RecursiveZone `json:"zones"`
+}
+
+type RecursiveEnumerationScanRequest struct {
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+	FQDN              string `json:"fqdn"`
+	MaxDepth          int    `json:"max_depth,omitempty"`
+	MaxZones          int    `json:"max_zones,omitempty"`
+	MinChildren       int    `json:"min_children,omitempty"`
+}
+
+type RecursiveZone struct {
+	Children   int      `json:"children"`
+	Delegated  bool     `json:"delegated"`
+	Depth      int      `json:"depth"`
+	Discovered int      `json:"discovered"`
+	Errors     []string `json:"errors,omitempty"`
+	New        int      `json:"new"`
+	Zone       string   `json:"zone"`
+}
+
+type ReportRequest struct {
+	AssetIDS           []string `json:"asset_ids"`
+	FindingIDS         []string `json:"finding_ids"`
+	Format             string   `json:"format"`
+	IncludeScreenshots bool     `json:"include_screenshots"`
+	NucleiScanIDS      []string `json:"nuclei_scan_ids"`
+	Preset             string   `json:"preset"`
+	ScopeTargetID      string   `json:"scope_target_id"`
+	Severities         []string `json:"severities"`
+	TargetURLIDS       []string `json:"target_url_ids"`
+	TemplateID         string   `json:"template_id"`
+	Title              string   `json:"title"`
+}
+
+type ReportTemplate struct {
+	BuiltIn   bool      `json:"built_in"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+	Format    string    `json:"format"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Preset    string    `json:"preset,omitempty"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type RequestPayload struct {
+	Active      bool   `json:"active"`
+	Mode        string `json:"mode"`
+	ScopeTarget string `json:"scope_target"`
+	Type        string `json:"type"`
+}
+
+type ResolverStatus struct {
+	Address      string     `json:"address"`
+	AvgLatencyMs float64    `json:"avg_latency_ms"`
+	Failures     int        `json:"failures"`
+	Healthy      bool       `json:"healthy"`
+	LastChecked  *time.Time `json:"last_checked,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	Lying        bool       `json:"lying"`
+	Queries      int        `json:"queries"`
+}
+
+type ResponseChange struct {
+	After  string `json:"after,omitempty"`
+	Before string `json:"before,omitempty"`
+	Field  string `json:"field"`
+}
+
+type ResponsePayload struct {
+	Active      bool   `json:"active"`
+	ID          string `json:"id"`
+	ScopeTarget string `json:"scope_target"`
+	Type        string `json:"type"`
+}
+
+type ResponseSnapshot struct {
+	BodySha256         string              `json:"body_sha256"`
+	BodySimilarity     *float64            `json:"body_similarity,omitempty"`
+	Changes            []ResponseChange    `json:"changes"`
+	ContentLength      int                 `json:"content_length"`
+	FirstSeenAt        time.Time           `json:"first_seen_at"`
+	Headers            map[string][]string `json:"headers"`
+	ID                 string              `json:"id"`
+	LastSeenAt         time.Time           `json:"last_seen_at"`
+	MeaningfulChange   bool                `json:"meaningful_change"`
+	PreviousSnapshotID *string             `json:"previous_snapshot_id,omitempty"`
+	ScopeTargetID      string              `json:"scope_target_id"`
+	Source             string              `json:"source"`
+	StatusCode         int                 `json:"status_code"`
+	TimesSeen          int                 `json:"times_seen"`
+	Title              string              `json:"title"`
+	URL                string              `json:"url"`
+}
+
+type ScanStartedResponse struct {
+	ScanID string `json:"scan_id"`
+}
+
+type ScanSummary struct {
+	Command       string    `json:"command"`
+	CreatedAt     time.Time `json:"created_at"`
+	Domain        string    `json:"domain"`
+	Error         string    `json:"error"`
+	ExecutionTime string    `json:"execution_time"`
+	ID            string    `json:"id"`
+	Result        string    `json:"result"`
+	ScanID        string    `json:"scan_id"`
+	ScanType      string    `json:"scan_type"`
+	Status        string    `json:"status"`
+	Stderr        string    `json:"stderr"`
+	Stdout        string    `json:"stdout"`
+}
+
+type ScopeTargetDomainRequest struct {
+	Domain        string `json:"domain"`
+	ScopeTargetID string `json:"scope_target_id"`
+}
+
+type ScopeTargetScanRequest struct {
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+	ScopeTargetID     string `json:"scope_target_id"`
+}
+
+type Screenshot struct {
+	ConsoleErrors  []string  `json:"console_errors"`
+	CreatedAt      time.Time `json:"created_at"`
+	FinalURL       string    `json:"final_url"`
+	Format         string    `json:"format"`
+	FullPage       bool      `json:"full_page"`
+	Height         int       `json:"height"`
+	ID             string    `json:"id"`
+	ImageURL       string    `json:"image_url"`
+	PageTitle      string    `json:"page_title"`
+	Phash          string    `json:"phash"`
+	ScopeTargetID  string    `json:"scope_target_id"`
+	Sha256         string    `json:"sha256"`
+	SizeBytes      int       `json:"size_bytes"`
+	StatusCode     int       `json:"status_code"`
+	ThumbnailURL   string    `json:"thumbnail_url"`
+	Title          string    `json:"title"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	URL            string    `json:"url"`
+	ViewportHeight int       `json:"viewport_height"`
+	ViewportWidth  int       `json:"viewport_width"`
+	Width          int       `json:"width"`
+}
+
+type ScreenshotCluster struct {
+	Count       int          `json:"count"`
+	Phash       string       `json:"phash"`
+	Screenshots []Screenshot `json:"screenshots"`
+}
+
+type ScreenshotOptions struct {
+	Concurrency    int  `json:"concurrency"`
+	FullPage       bool `json:"full_page"`
+	TimeoutSeconds int  `json:"timeout_seconds"`
+	ViewportHeight int  `json:"viewport_height"`
+	ViewportWidth  int  `json:"viewport_width"`
+}
+
+type ScreenshotScan struct {
+	CapturedCount int               `json:"captured_count"`
+	CreatedAt     time.Time         `json:"created_at"`
+	Error         *string           `json:"error,omitempty"`
+	ExecutionTime *string           `json:"execution_time,omitempty"`
+	Failures      []PageCapture     `json:"failures"`
+	ID            string            `json:"id"`
+	Options       ScreenshotOptions `json:"options"`
+	ScanID        string            `json:"scan_id"`
+	ScopeTargetID string            `json:"scope_target_id"`
+	Status        string            `json:"status"`
+	URLSCount     int               `json:"urls_count"`
+}
+
+type ScreenshotScanRequest struct {
+	Concurrency    int      `json:"concurrency,omitempty"`
+	FullPage       bool     `json:"full_page,omitempty"`
+	ScopeTargetID  string   `json:"scope_target_id"`
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"`
+	URLS           []string `json:"urls,omitempty"`
+	ViewportHeight int      `json:"viewport_height,omitempty"`
+	ViewportWidth  int      `json:"viewport_width,omitempty"`
+}
+
+type SecurityHeaderFinding struct {
+	Check    string `json:"check"`
+	Details  string `json:"details"`
+	Severity string `json:"severity"`
+	Title    string `json:"title"`
+}
+
+type SecurityHeaderResult struct {
+	CheckedAt     time.Time               `json:"checked_at"`
+	CorsProbes    []CORSProbe             `json:"cors_probes"`
+	FinalURL      string                  `json:"final_url"`
+	Findings      []SecurityHeaderFinding `json:"findings"`
+	Grade         string                  `json:"grade"`
+	Headers       map[string]string       `json:"headers"`
+	ID            string                  `json:"id"`
+	ScanID        string                  `json:"scan_id"`
+	ScopeTargetID string                  `json:"scope_target_id"`
+	Score         int                     `json:"score"`
+	Source        string                  `json:"source"`
+	StatusCode    *int                    `json:"status_code,omitempty"`
+	URL           string                  `json:"url"`
+}
+
+type SecurityHeaderScan struct {
+	CreatedAt     time.Time `json:"created_at"`
+	Error         *string   `json:"error,omitempty"`
+	ExecutionTime *string   `json:"execution_time,omitempty"`
+	FindingsCount int       `json:"findings_count"`
+	ID            string    `json:"id"`
+	ScanID        string    `json:"scan_id"`
+	ScopeTargetID string    `json:"scope_target_id"`
+	Status        string    `json:"status"`
+	URLSChecked   int       `json:"urls_checked"`
+}
+
+type SecurityHeaderScanRequest struct {
+	ScopeTargetID string   `json:"scope_target_id"`
+	URLS          []string `json:"urls,omitempty"`
+}
+
+type SecurityHeaderSummary struct {
+	AverageScore  int                    `json:"average_score"`
+	Checks        map[string]int         `json:"checks"`
+	Grades        map[string]int         `json:"grades"`
+	LatestScan    *SecurityHeaderScan    `json:"latest_scan,omitempty"`
+	Results       []SecurityHeaderResult `json:"results"`
+	ScopeTargetID string                 `json:"scope_target_id"`
+	Severities    map[string]int         `json:"severities"`
+	URLS          int                    `json:"urls"`
+}
+
+type SecurityTrailsCompanyScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CompanyName       string    `json:"company_name"`
+	CreatedAt         time.Time `json:"created_at"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type ServiceProviderResponse struct {
+	Provider string `json:"provider"`
+	RawData  string `json:"raw_data"`
+}
+
+type SessionStartedResponse struct {
+	SessionID string `json:"session_id"`
+}
+
+type ShodanCompanyScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CompanyName       string    `json:"company_name"`
+	CreatedAt         time.Time `json:"created_at"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type ShodanFaviconMatch struct {
+	FaviconHash int      `json:"favicon_hash"`
+	Hostnames   []string `json:"hostnames"`
+	IP          string   `json:"ip"`
+	KnownURLS   []string `json:"known_urls"`
+	Org         string   `json:"org"`
+	Port        int      `json:"port"`
+	Title       string   `json:"title"`
+}
+
+type ShuffleDNSScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domain            string    `json:"domain"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type StatusResponse struct {
+	Status string `json:"status"`
+}
+
+type SubdomainTakeoverFinding struct {
+	CNAME            string    `json:"cname"`
+	FQDN             string    `json:"fqdn"`
+	HTTPStatus       *int      `json:"http_status,omitempty"`
+	ID               string    `json:"id"`
+	LastChecked      time.Time `json:"last_checked"`
+	MatchedSignature *string   `json:"matched_signature,omitempty"`
+	Nxdomain         bool      `json:"nxdomain"`
+	ScanID           string    `json:"scan_id"`
+	ScopeTargetID    string    `json:"scope_target_id"`
+	Service          string    `json:"service"`
+	Severity         string    `json:"severity"`
+	Status           string    `json:"status"`
+}
+
+type SubdomainTakeoverScan struct {
+	CandidatesChecked int       `json:"candidates_checked"`
+	CreatedAt         time.Time `json:"created_at"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	VulnerableCount   int       `json:"vulnerable_count"`
+}
+
+type SubdomainizerScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domain            string    `json:"domain"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type SubfinderScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domain            string    `json:"domain"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type Sublist3rScanStatus struct {
+	AutoScanSessionID *string   `json:"auto_scan_session_id,omitempty"`
+	Command           *string   `json:"command,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+	Domain            string    `json:"domain"`
+	Error             *string   `json:"error,omitempty"`
+	ExecutionTime     *string   `json:"execution_time,omitempty"`
+	ID                string    `json:"id"`
+	Result            *string   `json:"result,omitempty"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	Stderr            *string   `json:"stderr,omitempty"`
+	Stdout            *string   `json:"stdout,omitempty"`
+}
+
+type SubnetResponse struct {
+	CIDR    string `json:"cidr"`
+	RawData string `json:"raw_data"`
+}
+
+type SuccessMessageResponse struct {
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+}
+
+type SuccessResponse struct {
+	Success bool `json:"success"`
+}
+
+type TargetURL struct {
+	ContentLength int      `json:"content_length"`
+	CreatedAt     string   `json:"created_at"`
+	ID            string   `json:"id"`
+	ROIScore      int      `json:"roi_score"`
+	ScopeTargetID string   `json:"scope_target_id"`
+	StatusCode    int      `json:"status_code"`
+	Technologies  []string `json:"technologies"`
+	Title         string   `json:"title"`
+	URL           string   `json:"url"`
+	WebServer     string   `json:"web_server"`
+}
+
+type TechnologyInventoryEntry struct {
+	Categories []string `json:"categories"`
+	Count      int      `json:"count"`
+	Name       string   `json:"name"`
+	URLS       []string `json:"urls"`
+	Version    string   `json:"version"`
+}
+
+type URLParameter struct {
+	CreatedAt     time.Time `json:"created_at"`
+	Host          string    `json:"host"`
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Occurrences   int       `json:"occurrences"`
+	ScanID        string    `json:"scan_id"`
+	ScopeTargetID string    `json:"scope_target_id"`
+	SourceURLS    []string  `json:"source_urls"`
+	Sources       []string  `json:"sources"`
+	Tags          []string  `json:"tags"`
+	Values        []string  `json:"values"`
+}
+
+type URLsScanRequest struct {
+	URLS []string `json:"urls"`
+}
+
+type UserSettings struct {
+	AmassRateLimit            int    `json:"amass_rate_limit"`
+	BurpAPIIP                 string `json:"burp_api_ip"`
+	BurpAPIKey                string `json:"burp_api_key"`
+	BurpAPIPort               int    `json:"burp_api_port"`
+	BurpProxyEnabled          bool   `json:"burp_proxy_enabled"`
+	BurpProxyIP               string `json:"burp_proxy_ip"`
+	BurpProxyPort             int    `json:"burp_proxy_port"`
+	CewlRateLimit             int    `json:"cewl_rate_limit"`
+	CtlRateLimit              int    `json:"ctl_rate_limit"`
+	CustomHeader              string `json:"custom_header"`
+	CustomUserAgent           string `json:"custom_user_agent"`
+	GauProviders              string `json:"gau_providers"`
+	GauRateLimit              int    `json:"gau_rate_limit"`
+	GospiderRateLimit         int    `json:"gospider_rate_limit"`
+	HttpxRateLimit            int    `json:"httpx_rate_limit"`
+	NucleiScreenshotRateLimit int    `json:"nuclei_screenshot_rate_limit"`
+	ShufflednsRateLimit       int    `json:"shuffledns_rate_limit"`
+	SubdomainizerRateLimit    int    `json:"subdomainizer_rate_limit"`
+	SubfinderRateLimit        int    `json:"subfinder_rate_limit"`
+	Sublist3rRateLimit        int    `json:"sublist3r_rate_limit"`
+}
+
+type WebFingerprint struct {
+	FaviconHash       *int       `json:"favicon_hash,omitempty"`
+	FaviconMd5        *string    `json:"favicon_md5,omitempty"`
+	FaviconURL        *string    `json:"favicon_url,omitempty"`
+	HTMLStructureHash *string    `json:"html_structure_hash,omitempty"`
+	HTMLTagCount      int        `json:"html_tag_count"`
+	ID                string     `json:"id"`
+	Ja3s              *string    `json:"ja3s,omitempty"`
+	ScanID            string     `json:"scan_id"`
+	ScopeTargetID     string     `json:"scope_target_id"`
+	Source            string     `json:"source"`
+	Title             string     `json:"title"`
+	TLSCertIssuer     *string    `json:"tls_cert_issuer,omitempty"`
+	TLSCertNotAfter   *time.Time `json:"tls_cert_not_after,omitempty"`
+	TLSCertSha256     *string    `json:"tls_cert_sha256,omitempty"`
+	TLSCertSubject    *string    `json:"tls_cert_subject,omitempty"`
+	TLSServerHello    *string    `json:"tls_server_hello,omitempty"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	URL               string     `json:"url"`
+}
+
+type WebFingerprintCluster struct {
+	Count  int      `json:"count"`
+	Kind   string   `json:"kind"`
+	Label  string   `json:"label"`
+	Titles []string `json:"titles"`
+	URLS   []string `json:"urls"`
+	Value  string   `json:"value"`
+}
+
+type WebFingerprintScan struct {
+	CreatedAt          time.Time `json:"created_at"`
+	Error              *string   `json:"error,omitempty"`
+	ExecutionTime      *string   `json:"execution_time,omitempty"`
+	FingerprintedCount int       `json:"fingerprinted_count"`
+	ID                 string    `json:"id"`
+	ScanID             string    `json:"scan_id"`
+	ScopeTargetID      string    `json:"scope_target_id"`
+	Status             string    `json:"status"`
+	TargetsCount       int       `json:"targets_count"`
+}
+
+type WildcardDNSZone struct {
+	CheckedAt      time.Time `json:"checked_at"`
+	FilteredCount  int       `json:"filtered_count"`
+	IsWildcard     bool      `json:"is_wildcard"`
+	WildcardCnames []string  `json:"wildcard_cnames"`
+	WildcardIPS    []string  `json:"wildcard_ips"`
+	Zone           string    `json:"zone"`
+}
+
+type WordlistScanRequest struct {
+	Wordlist string `json:"wordlist"`
+}
+
+// ActivateScopeTarget calls POST /scopetarget/{id}/activate.
+//
+// Activate scope target.
+func (c *Client) ActivateScopeTarget(ctx context.Context, id string) (*MessageResponse, error) {
+	var out MessageResponse
+	if err := c.do(ctx, http.MethodPost, "/scopetarget/"+url.PathEscape(id)+"/activate", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// BuildWordlistFromDomains calls POST /api/build-wordlist/{scope_target_id}/{type}.
+//
+// Build wordlist from domains.
+func (c *Client) BuildWordlistFromDomains(ctx context.Context, scopeTargetID string, typeParam string) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodPost, "/api/build-wordlist/"+url.PathEscape(scopeTargetID)+"/"+url.PathEscape(typeParam), nil, nil)
+}
+
+// CancelAutoScanSession calls POST /api/auto-scan/session/{id}/cancel.
+//
+// Cancel auto scan session.
+func (c *Client) CancelAutoScanSession(ctx context.Context, id string) (*AutoScanSessionStatusResponse, error) {
+	var out AutoScanSessionStatusResponse
+	if err := c.do(ctx, http.MethodPost, "/api/auto-scan/session/"+url.PathEscape(id)+"/cancel", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ConsolidateAttackSurface calls POST /consolidate-attack-surface/{scope_target_id}.
+//
+// Consolidate attack surface.
+func (c *Client) ConsolidateAttackSurface(ctx context.Context, scopeTargetID string) (*ConsolidationResult, error) {
+	var out ConsolidationResult
+	if err := c.do(ctx, http.MethodPost, "/consolidate-attack-surface/"+url.PathEscape(scopeTargetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateAPIKey calls POST /api/api-keys.
+//
+// Create API key.
+func (c *Client) CreateAPIKey(ctx context.Context, body APIKeyRequest) error {
+	return c.do(ctx, http.MethodPost, "/api/api-keys", nil, body, nil)
+}
+
+// CreateAiAPIKey calls POST /api/ai-api-keys.
+//
+// Create ai API key.
+func (c *Client) CreateAiAPIKey(ctx context.Context, body AiAPIKeyRequest) error {
+	return c.do(ctx, http.MethodPost, "/api/ai-api-keys", nil, body, nil)
+}
+
+// CreateGoogleDorkingDomain calls POST /api/google-dorking-domains.
+//
+// Create google dorking domain.
+func (c *Client) CreateGoogleDorkingDomain(ctx context.Context, body ScopeTargetDomainRequest) (*CompanyDomainCreatedResponse, error) {
+	var out CompanyDomainCreatedResponse
+	if err := c.do(ctx, http.MethodPost, "/api/google-dorking-domains", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateReportTemplate calls POST /api/report-templates.
+//
+// Create report template.
+func (c *Client) CreateReportTemplate(ctx context.Context, body ReportTemplate) (*ReportTemplate, error) {
+	var out ReportTemplate
+	if err := c.do(ctx, http.MethodPost, "/api/report-templates", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateReverseWhoisDomain calls POST /api/reverse-whois-domains.
+//
+// Create reverse whois domain.
+func (c *Client) CreateReverseWhoisDomain(ctx context.Context, body ScopeTargetDomainRequest) (*CompanyDomainCreatedResponse, error) {
+	var out CompanyDomainCreatedResponse
+	if err := c.do(ctx, http.MethodPost, "/api/reverse-whois-domains", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateScopeTarget calls POST /scopetarget/add.
+//
+// Create scope target.
+func (c *Client) CreateScopeTarget(ctx context.Context, body RequestPayload) (*MessageResponse, error) {
+	var out MessageResponse
+	if err := c.do(ctx, http.MethodPost, "/scopetarget/add", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DebugExportFile calls POST /api/debug-export-file.
+//
+// Debug export file.
+func (c *Client) DebugExportFile(ctx context.Context, body DatabaseImportRequest) (*ExportFileSummary, error) {
+	var out ExportFileSummary
+	if err := c.do(ctx, http.MethodPost, "/api/debug-export-file", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteAPIKey calls DELETE /api/api-keys/{id}.
+//
+// Delete API key.
+func (c *Client) DeleteAPIKey(ctx context.Context, id string) (*MessageResponse, error) {
+	var out MessageResponse
+	if err := c.do(ctx, http.MethodDelete, "/api/api-keys/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteAiAPIKey calls DELETE /api/ai-api-keys/{id}.
+//
+// Delete ai API key.
+func (c *Client) DeleteAiAPIKey(ctx context.Context, id string) (*MessageResponse, error) {
+	var out MessageResponse
+	if err := c.do(ctx, http.MethodDelete, "/api/ai-api-keys/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteAllCompanyDomainsFromTool calls DELETE /api/company-domains/{scope_target_id}/{tool}/all.
+//
+// Delete all company domains from tool.
+func (c *Client) DeleteAllCompanyDomainsFromTool(ctx context.Context, scopeTargetID string, tool string) (*DeletedDomainsResponse, error) {
+	var out DeletedDomainsResponse
+	if err := c.do(ctx, http.MethodDelete, "/api/company-domains/"+url.PathEscape(scopeTargetID)+"/"+url.PathEscape(tool)+"/all", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteAllIntelNetworkRanges calls DELETE /amass-intel/scan/{scan_id}/network-ranges.
+//
+// Delete all intel network ranges.
+func (c *Client) DeleteAllIntelNetworkRanges(ctx context.Context, scanID string) (*DeletedCountResponse, error) {
+	var out DeletedCountResponse
+	if err := c.do(ctx, http.MethodDelete, "/amass-intel/scan/"+url.PathEscape(scanID)+"/network-ranges", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteAllMetabigorNetworkRanges calls DELETE /metabigor/scan/{scan_id}/network-ranges.
+//
+// Delete all metabigor network ranges.
+func (c *Client) DeleteAllMetabigorNetworkRanges(ctx context.Context, scanID string) (*DeletedCountResponse, error) {
+	var out DeletedCountResponse
+	if err := c.do(ctx, http.MethodDelete, "/metabigor/scan/"+url.PathEscape(scanID)+"/network-ranges", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteCompanyDomainFromTool calls DELETE /api/company-domains/{scope_target_id}/{tool}/{domain}.
+//
+// Delete company domain from tool.
+func (c *Client) DeleteCompanyDomainFromTool(ctx context.Context, scopeTargetID string, tool string, domain string) (*SuccessMessageResponse, error) {
+	var out SuccessMessageResponse
+	if err := c.do(ctx, http.MethodDelete, "/api/company-domains/"+url.PathEscape(scopeTargetID)+"/"+url.PathEscape(tool)+"/"+url.PathEscape(domain), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteContentWordlist calls DELETE /content-wordlists/{id}.
+//
+// Delete content wordlist.
+func (c *Client) DeleteContentWordlist(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/content-wordlists/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteFingerprintRuleFile calls DELETE /fingerprint-rules/{id}.
+//
+// Delete fingerprint rule file.
+func (c *Client) DeleteFingerprintRuleFile(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/fingerprint-rules/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteGoogleDorkingDomain calls DELETE /api/google-dorking-domains/{domain_id}.
+//
+// Delete google dorking domain.
+func (c *Client) DeleteGoogleDorkingDomain(ctx context.Context, domainID string) (*SuccessMessageResponse, error) {
+	var out SuccessMessageResponse
+	if err := c.do(ctx, http.MethodDelete, "/api/google-dorking-domains/"+url.PathEscape(domainID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteIntelNetworkRange calls DELETE /amass-intel/network-range/{id}.
+//
+// Delete intel network range.
+func (c *Client) DeleteIntelNetworkRange(ctx context.Context, id string) (*MessageResponse, error) {
+	var out MessageResponse
+	if err := c.do(ctx, http.MethodDelete, "/amass-intel/network-range/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteMetabigorNetworkRange calls DELETE /metabigor/network-range/{id}.
+//
+// Delete metabigor network range.
+func (c *Client) DeleteMetabigorNetworkRange(ctx context.Context, id string) (*MessageResponse, error) {
+	var out MessageResponse
+	if err := c.do(ctx, http.MethodDelete, "/metabigor/network-range/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteReportTemplate calls DELETE /api/report-templates/{id}.
+//
+// Delete report template.
+func (c *Client) DeleteReportTemplate(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/api/report-templates/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// DeleteReverseWhoisDomain calls DELETE /api/reverse-whois-domains/{domain_id}.
+//
+// Delete reverse whois domain.
+func (c *Client) DeleteReverseWhoisDomain(ctx context.Context, domainID string) (*SuccessMessageResponse, error) {
+	var out SuccessMessageResponse
+	if err := c.do(ctx, http.MethodDelete, "/api/reverse-whois-domains/"+url.PathEscape(domainID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteScopeTarget calls DELETE /scopetarget/delete/{id}.
+//
+// Delete scope target.
+func (c *Client) DeleteScopeTarget(ctx context.Context, id string) (*MessageResponse, error) {
+	var out MessageResponse
+	if err := c.do(ctx, http.MethodDelete, "/scopetarget/delete/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DownloadJSSourceFiles calls GET /js-files/{id}/sources/download.
+//
+// Download JS source files.
+// Zip archive of the sources reconstructed from the file's source map.
+func (c *Client) DownloadJSSourceFiles(ctx context.Context, id string) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodGet, "/js-files/"+url.PathEscape(id)+"/sources/download", nil, nil)
+}
+
+// ExportURLParametersParams holds the query parameters of ExportURLParameters
+type ExportURLParametersParams struct {
+	// urls (default), csv or json
+	Format string
+	// Only parameters seen on this host
+	Host string
+	// One of: ssrf, open_redirect, lfi, idor, sqli
+	Tag string
+	// Only parameters with this name
+	Name string
+}
+
+// ExportURLParameters calls GET /scopetarget/{id}/parameters/export.
+//
+// Export URL parameters.
+// Downloads the parameter inventory. The urls format lists one URL per parameter with its value set to FUZZ.
+func (c *Client) ExportURLParameters(ctx context.Context, id string, params *ExportURLParametersParams) ([]byte, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Format != "" {
+			query.Set("format", params.Format)
+		}
+		if params.Host != "" {
+			query.Set("host", params.Host)
+		}
+		if params.Tag != "" {
+			query.Set("tag", params.Tag)
+		}
+		if params.Name != "" {
+			query.Set("name", params.Name)
+		}
+	}
+	return c.doRaw(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/parameters/export", query, nil)
+}
+
+// FingerprintURL calls POST /fingerprint.
+//
+// Fingerprint URL.
+func (c *Client) FingerprintURL(ctx context.Context, body FingerprintURLRequest) ([]DetectedTechnology, error) {
+	var out []DetectedTechnology
+	if err := c.do(ctx, http.MethodPost, "/fingerprint", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GenerateReport calls POST /api/reports/generate.
+//
+// Generate report.
+// Returns the rendered report as a Markdown or HTML attachment.
+func (c *Client) GenerateReport(ctx context.Context, body ReportRequest) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodPost, "/api/reports/generate", nil, body)
+}
+
+// GetAPIKeys calls GET /api/api-keys.
+//
+// Get API keys.
+func (c *Client) GetAPIKeys(ctx context.Context) ([]APIKey, error) {
+	var out []APIKey
+	if err := c.do(ctx, http.MethodGet, "/api/api-keys", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetASNs calls GET /amass/{scan_id}/asn.
+//
+// Get AS ns.
+func (c *Client) GetASNs(ctx context.Context, scanID string) ([]ASNResponse, error) {
+	var out []ASNResponse
+	if err := c.do(ctx, http.MethodGet, "/amass/"+url.PathEscape(scanID)+"/asn", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAiAPIKeys calls GET /api/ai-api-keys.
+//
+// Get ai API keys.
+func (c *Client) GetAiAPIKeys(ctx context.Context) ([]AiAPIKey, error) {
+	var out []AiAPIKey
+	if err := c.do(ctx, http.MethodGet, "/api/ai-api-keys", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAllScansForScopeTarget calls GET /scopetarget/{id}/scans.
+//
+// Get all scans for scope target.
+func (c *Client) GetAllScansForScopeTarget(ctx context.Context, id string) ([]ScanSummary, error) {
+	var out []ScanSummary
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAmassEnumCloudDomains calls GET /amass-enum-company/{scan_id}/cloud-domains.
+//
+// Get amass enum cloud domains.
+func (c *Client) GetAmassEnumCloudDomains(ctx context.Context, scanID string) ([]AmassEnumCloudDomain, error) {
+	var out []AmassEnumCloudDomain
+	if err := c.do(ctx, http.MethodGet, "/amass-enum-company/"+url.PathEscape(scanID)+"/cloud-domains", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAmassEnumCompanyScanStatus calls GET /amass-enum-company/status/{scan_id}.
+//
+// Get amass enum company scan status.
+func (c *Client) GetAmassEnumCompanyScanStatus(ctx context.Context, scanID string) (*AmassEnumCompanyScan, error) {
+	var out AmassEnumCompanyScan
+	if err := c.do(ctx, http.MethodGet, "/amass-enum-company/status/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAmassEnumCompanyScansForScopeTarget calls GET /scopetarget/{id}/scans/amass-enum-company.
+//
+// Get amass enum company scans for scope target.
+func (c *Client) GetAmassEnumCompanyScansForScopeTarget(ctx context.Context, id string) ([]AmassEnumCompanyScan, error) {
+	var out []AmassEnumCompanyScan
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/amass-enum-company", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAmassEnumConfig calls GET /amass-enum-config/{scope_target_id}.
+//
+// Get amass enum config.
+func (c *Client) GetAmassEnumConfig(ctx context.Context, scopeTargetID string) (*CompanyDomainsConfig, error) {
+	var out CompanyDomainsConfig
+	if err := c.do(ctx, http.MethodGet, "/amass-enum-config/"+url.PathEscape(scopeTargetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAmassEnumRawResults calls GET /amass-enum-company/{scan_id}/raw-results.
+//
+// Get amass enum raw results.
+func (c *Client) GetAmassEnumRawResults(ctx context.Context, scanID string) ([]AmassEnumRawResult, error) {
+	var out []AmassEnumRawResult
+	if err := c.do(ctx, http.MethodGet, "/amass-enum-company/"+url.PathEscape(scanID)+"/raw-results", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAmassIntelConfig calls GET /amass-intel-config/{scope_target_id}.
+//
+// Get amass intel config.
+func (c *Client) GetAmassIntelConfig(ctx context.Context, scopeTargetID string) (*AmassIntelConfig, error) {
+	var out AmassIntelConfig
+	if err := c.do(ctx, http.MethodGet, "/amass-intel-config/"+url.PathEscape(scopeTargetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAmassIntelScanStatus calls GET /amass-intel/{scanID}.
+//
+// Get amass intel scan status.
+func (c *Client) GetAmassIntelScanStatus(ctx context.Context, scanID string) (*AmassIntelScanStatus, error) {
+	var out AmassIntelScanStatus
+	if err := c.do(ctx, http.MethodGet, "/amass-intel/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAmassIntelScansForScopeTarget calls GET /scopetarget/{id}/scans/amass-intel.
+//
+// Get amass intel scans for scope target.
+func (c *Client) GetAmassIntelScansForScopeTarget(ctx context.Context, id string) ([]AmassIntelScanStatus, error) {
+	var out []AmassIntelScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/amass-intel", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAmassScanStatus calls GET /amass/{scanID}.
+//
+// Get amass scan status.
+func (c *Client) GetAmassScanStatus(ctx context.Context, scanID string) (*AmassScanStatus, error) {
+	var out AmassScanStatus
+	if err := c.do(ctx, http.MethodGet, "/amass/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAmassScansForScopeTarget calls GET /scopetarget/{id}/scans/amass.
+//
+// Get amass scans for scope target.
+func (c *Client) GetAmassScansForScopeTarget(ctx context.Context, id string) ([]AmassScanStatus, error) {
+	var out []AmassScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/amass", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAssetfinderScanStatus calls GET /assetfinder/{scan_id}.
+//
+// Get assetfinder scan status.
+func (c *Client) GetAssetfinderScanStatus(ctx context.Context, scanID string) (*AssetfinderScanStatus, error) {
+	var out AssetfinderScanStatus
+	if err := c.do(ctx, http.MethodGet, "/assetfinder/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAssetfinderScansForScopeTarget calls GET /scopetarget/{id}/scans/assetfinder.
+//
+// Get assetfinder scans for scope target.
+func (c *Client) GetAssetfinderScansForScopeTarget(ctx context.Context, id string) ([]AssetfinderScanStatus, error) {
+	var out []AssetfinderScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/assetfinder", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAttackSurfaceAssetCounts calls GET /attack-surface-asset-counts/{scope_target_id}.
+//
+// Get attack surface asset counts.
+func (c *Client) GetAttackSurfaceAssetCounts(ctx context.Context, scopeTargetID string) (map[string]int, error) {
+	var out map[string]int
+	if err := c.do(ctx, http.MethodGet, "/attack-surface-asset-counts/"+url.PathEscape(scopeTargetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetAttackSurfaceAssets calls GET /attack-surface-assets/{scope_target_id}.
+//
+// Get attack surface assets.
+func (c *Client) GetAttackSurfaceAssets(ctx context.Context, scopeTargetID string) (*AttackSurfaceAssetsResponse, error) {
+	var out AttackSurfaceAssetsResponse
+	if err := c.do(ctx, http.MethodGet, "/attack-surface-assets/"+url.PathEscape(scopeTargetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAutoScanConfig calls GET /api/auto-scan-config.
+//
+// Get auto scan config.
+func (c *Client) GetAutoScanConfig(ctx context.Context) (*AutoScanConfig, error) {
+	var out AutoScanConfig
+	if err := c.do(ctx, http.MethodGet, "/api/auto-scan-config", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAutoScanSession calls GET /api/auto-scan/session/{id}.
+//
+// Get auto scan session.
+func (c *Client) GetAutoScanSession(ctx context.Context, id string) (*AutoScanSession, error) {
+	var out AutoScanSession
+	if err := c.do(ctx, http.MethodGet, "/api/auto-scan/session/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAutoScanState calls GET /api/auto-scan-state/{target_id}.
+//
+// Get auto scan state.
+func (c *Client) GetAutoScanState(ctx context.Context, targetID string) (*AutoScanState, error) {
+	var out AutoScanState
+	if err := c.do(ctx, http.MethodGet, "/api/auto-scan-state/"+url.PathEscape(targetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBurpScanStatus calls GET /api/burp/scan/{task_id}.
+//
+// Get burp scan status.
+func (c *Client) GetBurpScanStatus(ctx context.Context, taskID string) (*BurpScanStatus, error) {
+	var out BurpScanStatus
+	if err := c.do(ctx, http.MethodGet, "/api/burp/scan/"+url.PathEscape(taskID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetBurpSuiteStatus calls GET /api/burp/status.
+//
+// Get burp suite status.
+func (c *Client) GetBurpSuiteStatus(ctx context.Context) (*BurpStatusResponse, error) {
+	var out BurpStatusResponse
+	if err := c.do(ctx, http.MethodGet, "/api/burp/status", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCTLCompanyScanStatus calls GET /ctl-company/{scan_id}.
+//
+// Get CTL company scan status.
+func (c *Client) GetCTLCompanyScanStatus(ctx context.Context, scanID string) (*CTLCompanyScanStatus, error) {
+	var out CTLCompanyScanStatus
+	if err := c.do(ctx, http.MethodGet, "/ctl-company/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCTLCompanyScansForScopeTarget calls GET /scopetarget/{id}/scans/ctl-company.
+//
+// Get CTL company scans for scope target.
+func (c *Client) GetCTLCompanyScansForScopeTarget(ctx context.Context, id string) ([]CTLCompanyScanStatus, error) {
+	var out []CTLCompanyScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/ctl-company", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetCTLScanStatus calls GET /ctl/{scan_id}.
+//
+// Get CTL scan status.
+func (c *Client) GetCTLScanStatus(ctx context.Context, scanID string) (*CTLScanStatus, error) {
+	var out CTLScanStatus
+	if err := c.do(ctx, http.MethodGet, "/ctl/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCTLScansForScopeTarget calls GET /scopetarget/{id}/scans/ctl.
+//
+// Get CTL scans for scope target.
+func (c *Client) GetCTLScansForScopeTarget(ctx context.Context, id string) ([]CTLScanStatus, error) {
+	var out []CTLScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/ctl", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetCeWLScanStatus calls GET /cewl/{scan_id}.
+//
+// Get ce WL scan status.
+func (c *Client) GetCeWLScanStatus(ctx context.Context, scanID string) (*CeWLScanStatus, error) {
+	var out CeWLScanStatus
+	if err := c.do(ctx, http.MethodGet, "/cewl/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCeWLScansForScopeTarget calls GET /scopetarget/{id}/scans/cewl.
+//
+// Get ce WL scans for scope target.
+func (c *Client) GetCeWLScansForScopeTarget(ctx context.Context, id string) ([]CeWLScanStatus, error) {
+	var out []CeWLScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/cewl", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetCeWLWordlistScanStatus calls GET /cewl-wordlist/{scan_id}.
+//
+// Get ce WL wordlist scan status.
+func (c *Client) GetCeWLWordlistScanStatus(ctx context.Context, scanID string) (*ShuffleDNSScanStatus, error) {
+	var out ShuffleDNSScanStatus
+	if err := c.do(ctx, http.MethodGet, "/cewl-wordlist/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCensysCompanyScanStatus calls GET /censys-company/status/{scan_id}.
+//
+// Get censys company scan status.
+func (c *Client) GetCensysCompanyScanStatus(ctx context.Context, scanID string) (*CensysCompanyScanStatus, error) {
+	var out CensysCompanyScanStatus
+	if err := c.do(ctx, http.MethodGet, "/censys-company/status/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCensysCompanyScansForScopeTarget calls GET /scopetarget/{id}/scans/censys-company.
+//
+// Get censys company scans for scope target.
+func (c *Client) GetCensysCompanyScansForScopeTarget(ctx context.Context, id string) ([]CensysCompanyScanStatus, error) {
+	var out []CensysCompanyScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/censys-company", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetCloudDomains calls GET /amass/{scan_id}/cloud.
+//
+// Get cloud domains.
+func (c *Client) GetCloudDomains(ctx context.Context, scanID string) (*CloudDomainsResponse, error) {
+	var out CloudDomainsResponse
+	if err := c.do(ctx, http.MethodGet, "/amass/"+url.PathEscape(scanID)+"/cloud", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCloudEnumConfig calls GET /cloud-enum-config/{scope_target_id}.
+//
+// Get cloud enum config.
+func (c *Client) GetCloudEnumConfig(ctx context.Context, scopeTargetID string) (*CloudEnumConfig, error) {
+	var out CloudEnumConfig
+	if err := c.do(ctx, http.MethodGet, "/cloud-enum-config/"+url.PathEscape(scopeTargetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCloudEnumScanStatus calls GET /cloud-enum/{scan_id}.
+//
+// Get cloud enum scan status.
+func (c *Client) GetCloudEnumScanStatus(ctx context.Context, scanID string) (*CloudEnumScanStatus, error) {
+	var out CloudEnumScanStatus
+	if err := c.do(ctx, http.MethodGet, "/cloud-enum/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCloudEnumScansForScopeTarget calls GET /scopetarget/{id}/scans/cloud-enum.
+//
+// Get cloud enum scans for scope target.
+func (c *Client) GetCloudEnumScansForScopeTarget(ctx context.Context, id string) ([]CloudEnumScanStatus, error) {
+	var out []CloudEnumScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/cloud-enum", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetCompanyDomainsByTool calls GET /api/company-domains/{scope_target_id}/{tool}.
+//
+// Get company domains by tool.
+func (c *Client) GetCompanyDomainsByTool(ctx context.Context, scopeTargetID string, tool string) (*CompanyDomainsResponse, error) {
+	var out CompanyDomainsResponse
+	if err := c.do(ctx, http.MethodGet, "/api/company-domains/"+url.PathEscape(scopeTargetID)+"/"+url.PathEscape(tool), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCompanyMetaDataResults calls GET /ip-port-scan/{scan_id}/metadata-results.
+//
+// Get company meta data results.
+func (c *Client) GetCompanyMetaDataResults(ctx context.Context, scanID string) ([]CompanyMetaDataResult, error) {
+	var out []CompanyMetaDataResult
+	if err := c.do(ctx, http.MethodGet, "/ip-port-scan/"+url.PathEscape(scanID)+"/metadata-results", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetCompanyMetaDataScansForIPPortScan calls GET /ip-port-scan/{scan_id}/metadata-scans.
+//
+// Get company meta data scans for IP port scan.
+func (c *Client) GetCompanyMetaDataScansForIPPortScan(ctx context.Context, scanID string) ([]CompanyMetaDataScan, error) {
+	var out []CompanyMetaDataScan
+	if err := c.do(ctx, http.MethodGet, "/ip-port-scan/"+url.PathEscape(scanID)+"/metadata-scans", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetConsolidatedCompanyDomains calls GET /consolidated-company-domains/{id}.
+//
+// Get consolidated company domains.
+func (c *Client) GetConsolidatedCompanyDomains(ctx context.Context, id string) (*ConsolidatedCompanyDomainsResponse, error) {
+	var out ConsolidatedCompanyDomainsResponse
+	if err := c.do(ctx, http.MethodGet, "/consolidated-company-domains/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetConsolidatedNetworkRanges calls GET /consolidated-network-ranges/{id}.
+//
+// Get consolidated network ranges.
+func (c *Client) GetConsolidatedNetworkRanges(ctx context.Context, id string) (*ConsolidatedNetworkRangesResponse, error) {
+	var out ConsolidatedNetworkRangesResponse
+	if err := c.do(ctx, http.MethodGet, "/consolidated-network-ranges/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetConsolidatedSubdomains calls GET /consolidated-subdomains/{id}.
+//
+// Get consolidated subdomains.
+func (c *Client) GetConsolidatedSubdomains(ctx context.Context, id string) (*ConsolidatedSubdomainsResponse, error) {
+	var out ConsolidatedSubdomainsResponse
+	if err := c.do(ctx, http.MethodGet, "/consolidated-subdomains/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetContentDiscoveryResultsParams holds the query parameters of GetContentDiscoveryResults
+type GetContentDiscoveryResultsParams struct {
+	// Only results found below this target URL
+	BaseURL string
+	// Only results with this status code
+	Status string
+}
+
+// GetContentDiscoveryResults calls GET /scopetarget/{id}/content-discovery.
+//
+// Get content discovery results.
+func (c *Client) GetContentDiscoveryResults(ctx context.Context, id string, params *GetContentDiscoveryResultsParams) ([]ContentDiscoveryResult, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.BaseURL != "" {
+			query.Set("base_url", params.BaseURL)
+		}
+		if params.Status != "" {
+			query.Set("status", params.Status)
+		}
+	}
+	var out []ContentDiscoveryResult
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/content-discovery", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetContentDiscoveryScanStatus calls GET /content-discovery/{scan_id}.
+//
+// Get content discovery scan status.
+func (c *Client) GetContentDiscoveryScanStatus(ctx context.Context, scanID string) (*ContentDiscoveryScan, error) {
+	var out ContentDiscoveryScan
+	if err := c.do(ctx, http.MethodGet, "/content-discovery/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetContentDiscoveryScansForScopeTarget calls GET /scopetarget/{id}/scans/content-discovery.
+//
+// Get content discovery scans for scope target.
+func (c *Client) GetContentDiscoveryScansForScopeTarget(ctx context.Context, id string) ([]ContentDiscoveryScan, error) {
+	var out []ContentDiscoveryScan
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/content-discovery", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetContentWordlists calls GET /content-wordlists.
+//
+// Get content wordlists.
+func (c *Client) GetContentWordlists(ctx context.Context) ([]ContentWordlist, error) {
+	var out []ContentWordlist
+	if err := c.do(ctx, http.MethodGet, "/content-wordlists", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDNSAuditFindings calls GET /scopetarget/{id}/dns-audit-findings.
+//
+// Get DNS audit findings.
+func (c *Client) GetDNSAuditFindings(ctx context.Context, id string) ([]DNSAuditFinding, error) {
+	var out []DNSAuditFinding
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/dns-audit-findings", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDNSAuditScanStatus calls GET /dns-audit/{scan_id}.
+//
+// Get DNS audit scan status.
+func (c *Client) GetDNSAuditScanStatus(ctx context.Context, scanID string) (*DNSAuditScan, error) {
+	var out DNSAuditScan
+	if err := c.do(ctx, http.MethodGet, "/dns-audit/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDNSAuditScansForScopeTarget calls GET /scopetarget/{id}/scans/dns-audit.
+//
+// Get DNS audit scans for scope target.
+func (c *Client) GetDNSAuditScansForScopeTarget(ctx context.Context, id string) ([]DNSAuditScan, error) {
+	var out []DNSAuditScan
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/dns-audit", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDNSRecords calls GET /amass/{scan_id}/dns.
+//
+// Get DNS records.
+func (c *Client) GetDNSRecords(ctx context.Context, scanID string) ([]DNSRecord, error) {
+	var out []DNSRecord
+	if err := c.do(ctx, http.MethodGet, "/amass/"+url.PathEscape(scanID)+"/dns", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDNSResolvers calls GET /api/dns-resolvers.
+//
+// Get DNS resolvers.
+func (c *Client) GetDNSResolvers(ctx context.Context) ([]ResolverStatus, error) {
+	var out []ResolverStatus
+	if err := c.do(ctx, http.MethodGet, "/api/dns-resolvers", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDNSxCompanyScanStatus calls GET /dnsx-company/status/{scan_id}.
+//
+// Get DN sx company scan status.
+func (c *Client) GetDNSxCompanyScanStatus(ctx context.Context, scanID string) (*DNSxScanStatus, error) {
+	var out DNSxScanStatus
+	if err := c.do(ctx, http.MethodGet, "/dnsx-company/status/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDNSxCompanyScansForScopeTarget calls GET /scopetarget/{id}/scans/dnsx-company.
+//
+// Get DN sx company scans for scope target.
+func (c *Client) GetDNSxCompanyScansForScopeTarget(ctx context.Context, id string) ([]DNSxScanStatus, error) {
+	var out []DNSxScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/dnsx-company", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDNSxConfig calls GET /dnsx-config/{scope_target_id}.
+//
+// Get DN sx config.
+func (c *Client) GetDNSxConfig(ctx context.Context, scopeTargetID string) (*CompanyDomainsConfig, error) {
+	var out CompanyDomainsConfig
+	if err := c.do(ctx, http.MethodGet, "/dnsx-config/"+url.PathEscape(scopeTargetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDNSxDNSRecords calls GET /dnsx-company/{scan_id}/dns-records.
+//
+// Get DN sx DNS records.
+func (c *Client) GetDNSxDNSRecords(ctx context.Context, scanID string) ([]DNSxDNSRecord, error) {
+	var out []DNSxDNSRecord
+	if err := c.do(ctx, http.MethodGet, "/dnsx-company/"+url.PathEscape(scanID)+"/dns-records", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDNSxRawResults calls GET /dnsx-company/{scan_id}/raw-results.
+//
+// Get DN sx raw results.
+func (c *Client) GetDNSxRawResults(ctx context.Context, scanID string) ([]DNSxRawResult, error) {
+	var out []DNSxRawResult
+	if err := c.do(ctx, http.MethodGet, "/dnsx-company/"+url.PathEscape(scanID)+"/raw-results", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDiscoveredIPs calls GET /ip-port-scan/{scan_id}/discovered-ips.
+//
+// Get discovered i ps.
+func (c *Client) GetDiscoveredIPs(ctx context.Context, scanID string) ([]DiscoveredIP, error) {
+	var out []DiscoveredIP
+	if err := c.do(ctx, http.MethodGet, "/ip-port-scan/"+url.PathEscape(scanID)+"/discovered-ips", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetDiscoveredServices calls GET /ip-port-scan/{scan_id}/services.
+//
+// Get discovered services.
+func (c *Client) GetDiscoveredServices(ctx context.Context, scanID string) ([]DiscoveredService, error) {
+	var out []DiscoveredService
+	if err := c.do(ctx, http.MethodGet, "/ip-port-scan/"+url.PathEscape(scanID)+"/services", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetEmailSecurityScanStatus calls GET /email-security/{scan_id}.
+//
+// Get email security scan status.
+func (c *Client) GetEmailSecurityScanStatus(ctx context.Context, scanID string) (*EmailSecurityScan, error) {
+	var out EmailSecurityScan
+	if err := c.do(ctx, http.MethodGet, "/email-security/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetEmailSecurityScansForScopeTarget calls GET /scopetarget/{id}/scans/email-security.
+//
+// Get email security scans for scope target.
+func (c *Client) GetEmailSecurityScansForScopeTarget(ctx context.Context, id string) ([]EmailSecurityScan, error) {
+	var out []EmailSecurityScan
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/email-security", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetEmailSecuritySummary calls GET /scopetarget/{id}/email-security.
+//
+// Get email security summary.
+func (c *Client) GetEmailSecuritySummary(ctx context.Context, id string) (*EmailSecuritySummary, error) {
+	var out EmailSecuritySummary
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/email-security", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFingerprintRuleFiles calls GET /fingerprint-rules.
+//
+// Get fingerprint rule files.
+func (c *Client) GetFingerprintRuleFiles(ctx context.Context) ([]FingerprintRuleFile, error) {
+	var out []FingerprintRuleFile
+	if err := c.do(ctx, http.MethodGet, "/fingerprint-rules", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetGauScanStatus calls GET /gau/{scanID}.
+//
+// Get gau scan status.
+func (c *Client) GetGauScanStatus(ctx context.Context, scanID string) (*GauScanStatus, error) {
+	var out GauScanStatus
+	if err := c.do(ctx, http.MethodGet, "/gau/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetGauScansForScopeTarget calls GET /scopetarget/{id}/scans/gau.
+//
+// Get gau scans for scope target.
+func (c *Client) GetGauScansForScopeTarget(ctx context.Context, id string) ([]GauScanStatus, error) {
+	var out []GauScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/gau", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetGitHubReconScanStatus calls GET /github-recon/status/{scan_id}.
+//
+// Get git hub recon scan status.
+func (c *Client) GetGitHubReconScanStatus(ctx context.Context, scanID string) (*GitHubReconScanStatus, error) {
+	var out GitHubReconScanStatus
+	if err := c.do(ctx, http.MethodGet, "/github-recon/status/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetGitHubReconScansForScopeTarget calls GET /scopetarget/{id}/scans/github-recon.
+//
+// Get git hub recon scans for scope target.
+func (c *Client) GetGitHubReconScansForScopeTarget(ctx context.Context, id string) ([]GitHubReconScanStatus, error) {
+	var out []GitHubReconScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/github-recon", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetGoSpiderScanStatus calls GET /gospider/{scan_id}.
+//
+// Get go spider scan status.
+func (c *Client) GetGoSpiderScanStatus(ctx context.Context, scanID string) (*GoSpiderScanStatus, error) {
+	var out GoSpiderScanStatus
+	if err := c.do(ctx, http.MethodGet, "/gospider/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetGoSpiderScansForScopeTarget calls GET /scopetarget/{id}/scans/gospider.
+//
+// Get go spider scans for scope target.
+func (c *Client) GetGoSpiderScansForScopeTarget(ctx context.Context, id string) ([]GoSpiderScanStatus, error) {
+	var out []GoSpiderScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/gospider", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetGoogleDorkingDomains calls GET /api/google-dorking-domains/{target_id}.
+//
+// Get google dorking domains.
+func (c *Client) GetGoogleDorkingDomains(ctx context.Context, targetID string) ([]CompanyDomain, error) {
+	var out []CompanyDomain
+	if err := c.do(ctx, http.MethodGet, "/api/google-dorking-domains/"+url.PathEscape(targetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetHttpxScanStatus calls GET /httpx/{scanID}.
+//
+// Get httpx scan status.
+func (c *Client) GetHttpxScanStatus(ctx context.Context, scanID string) (*HttpxScanStatus, error) {
+	var out HttpxScanStatus
+	if err := c.do(ctx, http.MethodGet, "/httpx/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetHttpxScansForScopeTarget calls GET /scopetarget/{id}/scans/httpx.
+//
+// Get httpx scans for scope target.
+func (c *Client) GetHttpxScansForScopeTarget(ctx context.Context, id string) ([]HttpxScanStatus, error) {
+	var out []HttpxScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/httpx", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetIPPortScanConfig calls GET /ip-port-scan-config/{scope_target_id}.
+//
+// Get IP port scan config.
+func (c *Client) GetIPPortScanConfig(ctx context.Context, scopeTargetID string) (*IPPortScanConfig, error) {
+	var out IPPortScanConfig
+	if err := c.do(ctx, http.MethodGet, "/ip-port-scan-config/"+url.PathEscape(scopeTargetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetIPPortScanStatus calls GET /ip-port-scan/status/{scan_id}.
+//
+// Get IP port scan status.
+func (c *Client) GetIPPortScanStatus(ctx context.Context, scanID string) (*IPPortScan, error) {
+	var out IPPortScan
+	if err := c.do(ctx, http.MethodGet, "/ip-port-scan/status/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetIPPortScansForScopeTarget calls GET /scopetarget/{id}/scans/ip-port.
+//
+// Get IP port scans for scope target.
+func (c *Client) GetIPPortScansForScopeTarget(ctx context.Context, id string) (*IPPortScansResponse, error) {
+	var out IPPortScansResponse
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/ip-port", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetIPs calls GET /amass/{scan_id}/ip.
+//
+// Get i ps.
+func (c *Client) GetIPs(ctx context.Context, scanID string) ([]string, error) {
+	var out []string
+	if err := c.do(ctx, http.MethodGet, "/amass/"+url.PathEscape(scanID)+"/ip", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetIntelASNData calls GET /amass-intel/{scan_id}/asn.
+//
+// Get intel ASN data.
+func (c *Client) GetIntelASNData(ctx context.Context, scanID string) ([]IntelASNResponse, error) {
+	var out []IntelASNResponse
+	if err := c.do(ctx, http.MethodGet, "/amass-intel/"+url.PathEscape(scanID)+"/asn", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetIntelNetworkRanges calls GET /amass-intel/{scan_id}/networks.
+//
+// Get intel network ranges.
+func (c *Client) GetIntelNetworkRanges(ctx context.Context, scanID string) ([]IntelNetworkRangeResponse, error) {
+	var out []IntelNetworkRangeResponse
+	if err := c.do(ctx, http.MethodGet, "/amass-intel/"+url.PathEscape(scanID)+"/networks", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetInvestigateScanStatus calls GET /investigate/{scan_id}.
+//
+// Get investigate scan status.
+func (c *Client) GetInvestigateScanStatus(ctx context.Context, scanID string) (*InvestigateStatus, error) {
+	var out InvestigateStatus
+	if err := c.do(ctx, http.MethodGet, "/investigate/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetInvestigateScansForScopeTarget calls GET /scopetarget/{id}/scans/investigate.
+//
+// Get investigate scans for scope target.
+func (c *Client) GetInvestigateScansForScopeTarget(ctx context.Context, id string) ([]InvestigateStatus, error) {
+	var out []InvestigateStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/investigate", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetJSAnalysisScanStatus calls GET /js-analysis/{scan_id}.
+//
+// Get JS analysis scan status.
+func (c *Client) GetJSAnalysisScanStatus(ctx context.Context, scanID string) (*JSAnalysisScan, error) {
+	var out JSAnalysisScan
+	if err := c.do(ctx, http.MethodGet, "/js-analysis/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetJSAnalysisScansForScopeTarget calls GET /scopetarget/{id}/scans/js-analysis.
+//
+// Get JS analysis scans for scope target.
+func (c *Client) GetJSAnalysisScansForScopeTarget(ctx context.Context, id string) ([]JSAnalysisScan, error) {
+	var out []JSAnalysisScan
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/js-analysis", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetJSFilesParams holds the query parameters of GetJSFiles
+type GetJSFilesParams struct {
+	// Only files whose content changed between scans
+	Changed string
+}
+
+// GetJSFiles calls GET /scopetarget/{id}/js-files.
+//
+// Get JS files.
+func (c *Client) GetJSFiles(ctx context.Context, id string, params *GetJSFilesParams) ([]JSFile, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Changed != "" {
+			query.Set("changed", params.Changed)
+		}
+	}
+	var out []JSFile
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/js-files", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetJSSecrets calls GET /scopetarget/{id}/js-secrets.
+//
+// Get JS secrets.
+func (c *Client) GetJSSecrets(ctx context.Context, id string) ([]JSSecret, error) {
+	var out []JSSecret
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/js-secrets", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetJSSourceFiles calls GET /js-files/{id}/sources.
+//
+// Get JS source files.
+func (c *Client) GetJSSourceFiles(ctx context.Context, id string) ([]JSSourceFile, error) {
+	var out []JSSourceFile
+	if err := c.do(ctx, http.MethodGet, "/js-files/"+url.PathEscape(id)+"/sources", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetKatanaCompanyCloudAssetsByTarget calls GET /katana-company/target/{scope_target_id}/cloud-assets.
+//
+// Get katana company cloud assets by target.
+func (c *Client) GetKatanaCompanyCloudAssetsByTarget(ctx context.Context, scopeTargetID string) ([]KatanaCompanyCloudAsset, error) {
+	var out []KatanaCompanyCloudAsset
+	if err := c.do(ctx, http.MethodGet, "/katana-company/target/"+url.PathEscape(scopeTargetID)+"/cloud-assets", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetKatanaCompanyConfig calls GET /katana-company-config/{scope_target_id}.
+//
+// Get katana company config.
+func (c *Client) GetKatanaCompanyConfig(ctx context.Context, scopeTargetID string) (*KatanaCompanyConfig, error) {
+	var out KatanaCompanyConfig
+	if err := c.do(ctx, http.MethodGet, "/katana-company-config/"+url.PathEscape(scopeTargetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetKatanaCompanyScanStatus calls GET /katana-company/status/{scan_id}.
+//
+// Get katana company scan status.
+func (c *Client) GetKatanaCompanyScanStatus(ctx context.Context, scanID string) (*KatanaCompanyScanStatus, error) {
+	var out KatanaCompanyScanStatus
+	if err := c.do(ctx, http.MethodGet, "/katana-company/status/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetKatanaCompanyScansForScopeTarget calls GET /scopetarget/{id}/scans/katana-company.
+//
+// Get katana company scans for scope target.
+func (c *Client) GetKatanaCompanyScansForScopeTarget(ctx context.Context, id string) ([]KatanaCompanyScanStatus, error) {
+	var out []KatanaCompanyScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/katana-company", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetLiveWebServers calls GET /ip-port-scan/{scan_id}/live-web-servers.
+//
+// Get live web servers.
+func (c *Client) GetLiveWebServers(ctx context.Context, scanID string) ([]LiveWebServer, error) {
+	var out []LiveWebServer
+	if err := c.do(ctx, http.MethodGet, "/ip-port-scan/"+url.PathEscape(scanID)+"/live-web-servers", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetLiveWebServersCount calls GET /scope-target/{scope_target_id}/live-web-servers-count.
+//
+// Get live web servers count.
+func (c *Client) GetLiveWebServersCount(ctx context.Context, scopeTargetID string) (*CountResponse, error) {
+	var out CountResponse
+	if err := c.do(ctx, http.MethodGet, "/scope-target/"+url.PathEscape(scopeTargetID)+"/live-web-servers-count", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMetaDataScanStatus calls GET /metadata/{scan_id}.
+//
+// Get meta data scan status.
+func (c *Client) GetMetaDataScanStatus(ctx context.Context, scanID string) (*MetaDataStatus, error) {
+	var out MetaDataStatus
+	if err := c.do(ctx, http.MethodGet, "/metadata/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMetaDataScansForScopeTarget calls GET /scopetarget/{id}/scans/metadata.
+//
+// Get meta data scans for scope target.
+func (c *Client) GetMetaDataScansForScopeTarget(ctx context.Context, id string) ([]MetaDataStatus, error) {
+	var out []MetaDataStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/metadata", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetMetabigorASNData calls GET /metabigor-company/{scan_id}/asn.
+//
+// Get metabigor ASN data.
+func (c *Client) GetMetabigorASNData(ctx context.Context, scanID string) ([]MetabigorASNData, error) {
+	var out []MetabigorASNData
+	if err := c.do(ctx, http.MethodGet, "/metabigor-company/"+url.PathEscape(scanID)+"/asn", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetMetabigorCompanyScanStatus calls GET /metabigor-company/{scan_id}.
+//
+// Get metabigor company scan status.
+func (c *Client) GetMetabigorCompanyScanStatus(ctx context.Context, scanID string) (*MetabigorCompanyScanStatus, error) {
+	var out MetabigorCompanyScanStatus
+	if err := c.do(ctx, http.MethodGet, "/metabigor-company/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetMetabigorCompanyScansForScopeTarget calls GET /scopetarget/{id}/scans/metabigor-company.
+//
+// Get metabigor company scans for scope target.
+func (c *Client) GetMetabigorCompanyScansForScopeTarget(ctx context.Context, id string) ([]MetabigorCompanyScanStatus, error) {
+	var out []MetabigorCompanyScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/metabigor-company", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetMetabigorIPIntelligence calls GET /metabigor-ip/{scan_id}/intelligence.
+//
+// Get metabigor IP intelligence.
+func (c *Client) GetMetabigorIPIntelligence(ctx context.Context, scanID string) ([]MetabigorIPIntelligence, error) {
+	var out []MetabigorIPIntelligence
+	if err := c.do(ctx, http.MethodGet, "/metabigor-ip/"+url.PathEscape(scanID)+"/intelligence", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetMetabigorNetworkRanges calls GET /metabigor-company/{scan_id}/networks.
+//
+// Get metabigor network ranges.
+func (c *Client) GetMetabigorNetworkRanges(ctx context.Context, scanID string) ([]MetabigorNetworkRange, error) {
+	var out []MetabigorNetworkRange
+	if err := c.do(ctx, http.MethodGet, "/metabigor-company/"+url.PathEscape(scanID)+"/networks", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetNucleiConfig calls GET /nuclei-config/{scope_target_id}.
+//
+// Get nuclei config.
+func (c *Client) GetNucleiConfig(ctx context.Context, scopeTargetID string) (*NucleiConfig, error) {
+	var out NucleiConfig
+	if err := c.do(ctx, http.MethodGet, "/nuclei-config/"+url.PathEscape(scopeTargetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetNucleiScanStatus calls GET /nuclei-scan/{scan_id}/status.
+//
+// Get nuclei scan status.
+func (c *Client) GetNucleiScanStatus(ctx context.Context, scanID string) (*NucleiScanStatus, error) {
+	var out NucleiScanStatus
+	if err := c.do(ctx, http.MethodGet, "/nuclei-scan/"+url.PathEscape(scanID)+"/status", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetNucleiScansForScopeTarget calls GET /scopetarget/{id}/scans/nuclei.
+//
+// Get nuclei scans for scope target.
+func (c *Client) GetNucleiScansForScopeTarget(ctx context.Context, id string) ([]NucleiScan, error) {
+	var out []NucleiScan
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/nuclei", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetNucleiScreenshotScanStatus calls GET /nuclei-screenshot/{scan_id}.
+//
+// Get nuclei screenshot scan status.
+func (c *Client) GetNucleiScreenshotScanStatus(ctx context.Context, scanID string) (*NucleiScreenshotStatus, error) {
+	var out NucleiScreenshotStatus
+	if err := c.do(ctx, http.MethodGet, "/nuclei-screenshot/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetNucleiScreenshotScansForScopeTarget calls GET /scopetarget/{id}/scans/nuclei-screenshot.
+//
+// Get nuclei screenshot scans for scope target.
+func (c *Client) GetNucleiScreenshotScansForScopeTarget(ctx context.Context, id string) ([]NucleiScreenshotStatus, error) {
+	var out []NucleiScreenshotStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/nuclei-screenshot", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetOpenAPISpec calls GET /api/openapi.json.
+//
+// Get the OpenAPI specification.
+func (c *Client) GetOpenAPISpec(ctx context.Context) (map[string]json.RawMessage, error) {
+	var out map[string]json.RawMessage
+	if err := c.do(ctx, http.MethodGet, "/api/openapi.json", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetParameterMiningScanStatus calls GET /parameter-mining/{scan_id}.
+//
+// Get parameter mining scan status.
+func (c *Client) GetParameterMiningScanStatus(ctx context.Context, scanID string) (*ParameterMiningScan, error) {
+	var out ParameterMiningScan
+	if err := c.do(ctx, http.MethodGet, "/parameter-mining/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetParameterMiningScansForScopeTarget calls GET /scopetarget/{id}/scans/parameter-mining.
+//
+// Get parameter mining scans for scope target.
+func (c *Client) GetParameterMiningScansForScopeTarget(ctx context.Context, id string) ([]ParameterMiningScan, error) {
+	var out []ParameterMiningScan
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/parameter-mining", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetPermutationScanStatus calls GET /permutations/{scan_id}.
+//
+// Get permutation scan status.
+func (c *Client) GetPermutationScanStatus(ctx context.Context, scanID string) (*PermutationScanStatus, error) {
+	var out PermutationScanStatus
+	if err := c.do(ctx, http.MethodGet, "/permutations/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetPermutationScansForScopeTarget calls GET /scopetarget/{id}/scans/permutations.
+//
+// Get permutation scans for scope target.
+func (c *Client) GetPermutationScansForScopeTarget(ctx context.Context, id string) ([]PermutationScanStatus, error) {
+	var out []PermutationScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/permutations", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetRecursiveEnumerationScanStatus calls GET /recursive-enum/{scan_id}.
+//
+// Get recursive enumeration scan status.
+func (c *Client) GetRecursiveEnumerationScanStatus(ctx context.Context, scanID string) (*RecursiveEnumerationScan, error) {
+	var out RecursiveEnumerationScan
+	if err := c.do(ctx, http.MethodGet, "/recursive-enum/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetRecursiveEnumerationScansForScopeTarget calls GET /scopetarget/{id}/scans/recursive-enum.
+//
+// Get recursive enumeration scans for scope target.
+func (c *Client) GetRecursiveEnumerationScansForScopeTarget(ctx context.Context, id string) ([]RecursiveEnumerationScan, error) {
+	var out []RecursiveEnumerationScan
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/recursive-enum", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetReportTemplates calls GET /api/report-templates.
+//
+// Get report templates.
+func (c *Client) GetReportTemplates(ctx context.Context) ([]ReportTemplate, error) {
+	var out []ReportTemplate
+	if err := c.do(ctx, http.MethodGet, "/api/report-templates", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetResponseChangesParams holds the query parameters of GetResponseChanges
+type GetResponseChangesParams struct {
+	// Only changes first seen at or after this RFC 3339 time
+	Since string
+	// Set to true to include changes below the meaningful threshold
+	All string
+}
+
+// GetResponseChanges calls GET /scopetarget/{id}/response-changes.
+//
+// Get response changes.
+func (c *Client) GetResponseChanges(ctx context.Context, id string, params *GetResponseChangesParams) ([]ResponseSnapshot, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Since != "" {
+			query.Set("since", params.Since)
+		}
+		if params.All != "" {
+			query.Set("all", params.All)
+		}
+	}
+	var out []ResponseSnapshot
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/response-changes", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetResponseSnapshotBody calls GET /response-snapshots/{id}/body.
+//
+// Get response snapshot body.
+// Serves the archived body as plain text whatever its original content type.
+func (c *Client) GetResponseSnapshotBody(ctx context.Context, id string) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodGet, "/response-snapshots/"+url.PathEscape(id)+"/body", nil, nil)
+}
+
+// GetResponseTimelineParams holds the query parameters of GetResponseTimeline
+type GetResponseTimelineParams struct {
+	// Target URL whose history is returned
+	URL string
+}
+
+// GetResponseTimeline calls GET /scopetarget/{id}/response-history.
+//
+// Get response timeline.
+func (c *Client) GetResponseTimeline(ctx context.Context, id string, params *GetResponseTimelineParams) ([]ResponseSnapshot, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.URL != "" {
+			query.Set("url", params.URL)
+		}
+	}
+	var out []ResponseSnapshot
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/response-history", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetReverseWhoisDomains calls GET /api/reverse-whois-domains/{target_id}.
+//
+// Get reverse whois domains.
+func (c *Client) GetReverseWhoisDomains(ctx context.Context, targetID string) ([]CompanyDomain, error) {
+	var out []CompanyDomain
+	if err := c.do(ctx, http.MethodGet, "/api/reverse-whois-domains/"+url.PathEscape(targetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetScopeTargetFindingsParams holds the query parameters of GetScopeTargetFindings
+type GetScopeTargetFindingsParams struct {
+	// Comma separated finding sources: nuclei, tls, services, takeover, dns, email, js
+	Sources string
+}
+
+// GetScopeTargetFindings calls GET /api/findings/{scope_target_id}.
+//
+// Get scope target findings.
+func (c *Client) GetScopeTargetFindings(ctx context.Context, scopeTargetID string, params *GetScopeTargetFindingsParams) ([]ExportableFinding, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Sources != "" {
+			query.Set("sources", params.Sources)
+		}
+	}
+	var out []ExportableFinding
+	if err := c.do(ctx, http.MethodGet, "/api/findings/"+url.PathEscape(scopeTargetID), query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetScopeTargetsForExport calls GET /api/scope-targets-for-export.
+//
+// Get scope targets for export.
+func (c *Client) GetScopeTargetsForExport(ctx context.Context) ([]ExportScopeTarget, error) {
+	var out []ExportScopeTarget
+	if err := c.do(ctx, http.MethodGet, "/api/scope-targets-for-export", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetScreenshotClustersParams holds the query parameters of GetScreenshotClusters
+type GetScreenshotClustersParams struct {
+	// Largest perceptual hash distance in bits treated as similar (default 8)
+	Threshold string
+	// Smallest group returned (default 2)
+	MinSize string
+}
+
+// GetScreenshotClusters calls GET /scopetarget/{id}/screenshots/clusters.
+//
+// Get screenshot clusters.
+func (c *Client) GetScreenshotClusters(ctx context.Context, id string, params *GetScreenshotClustersParams) ([]ScreenshotCluster, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Threshold != "" {
+			query.Set("threshold", params.Threshold)
+		}
+		if params.MinSize != "" {
+			query.Set("min_size", params.MinSize)
+		}
+	}
+	var out []ScreenshotCluster
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/screenshots/clusters", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetScreenshotImage calls GET /screenshots/{id}.
+//
+// Get screenshot image.
+// Serves the stored capture in its original format, usually PNG.
+func (c *Client) GetScreenshotImage(ctx context.Context, id string) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodGet, "/screenshots/"+url.PathEscape(id), nil, nil)
+}
+
+// GetScreenshotScanStatus calls GET /screenshot/{scan_id}.
+//
+// Get screenshot scan status.
+func (c *Client) GetScreenshotScanStatus(ctx context.Context, scanID string) (*ScreenshotScan, error) {
+	var out ScreenshotScan
+	if err := c.do(ctx, http.MethodGet, "/screenshot/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetScreenshotScansForScopeTarget calls GET /scopetarget/{id}/scans/screenshot.
+//
+// Get screenshot scans for scope target.
+func (c *Client) GetScreenshotScansForScopeTarget(ctx context.Context, id string) ([]ScreenshotScan, error) {
+	var out []ScreenshotScan
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/screenshot", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetScreenshotThumbnail calls GET /screenshots/{id}/thumbnail.
+//
+// Get screenshot thumbnail.
+func (c *Client) GetScreenshotThumbnail(ctx context.Context, id string) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodGet, "/screenshots/"+url.PathEscape(id)+"/thumbnail", nil, nil)
+}
+
+// GetScreenshots calls GET /scopetarget/{id}/screenshots.
+//
+// Get screenshots.
+func (c *Client) GetScreenshots(ctx context.Context, id string) ([]Screenshot, error) {
+	var out []Screenshot
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/screenshots", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSecurityHeaderScanStatus calls GET /security-headers/{scan_id}.
+//
+// Get security header scan status.
+func (c *Client) GetSecurityHeaderScanStatus(ctx context.Context, scanID string) (*SecurityHeaderScan, error) {
+	var out SecurityHeaderScan
+	if err := c.do(ctx, http.MethodGet, "/security-headers/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSecurityHeaderScansForScopeTarget calls GET /scopetarget/{id}/scans/security-headers.
+//
+// Get security header scans for scope target.
+func (c *Client) GetSecurityHeaderScansForScopeTarget(ctx context.Context, id string) ([]SecurityHeaderScan, error) {
+	var out []SecurityHeaderScan
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/security-headers", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSecurityHeaderSummary calls GET /scopetarget/{id}/security-headers.
+//
+// Get security header summary.
+func (c *Client) GetSecurityHeaderSummary(ctx context.Context, id string) (*SecurityHeaderSummary, error) {
+	var out SecurityHeaderSummary
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/security-headers", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSecurityTrailsCompanyScanStatus calls GET /securitytrails-company/status/{scan_id}.
+//
+// Get security trails company scan status.
+func (c *Client) GetSecurityTrailsCompanyScanStatus(ctx context.Context, scanID string) (*SecurityTrailsCompanyScanStatus, error) {
+	var out SecurityTrailsCompanyScanStatus
+	if err := c.do(ctx, http.MethodGet, "/securitytrails-company/status/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSecurityTrailsCompanyScansForScopeTarget calls GET /scopetarget/{id}/scans/securitytrails-company.
+//
+// Get security trails company scans for scope target.
+func (c *Client) GetSecurityTrailsCompanyScansForScopeTarget(ctx context.Context, id string) ([]SecurityTrailsCompanyScanStatus, error) {
+	var out []SecurityTrailsCompanyScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/securitytrails-company", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetServiceProviders calls GET /amass/{scan_id}/sp.
+//
+// Get service providers.
+func (c *Client) GetServiceProviders(ctx context.Context, scanID string) ([]ServiceProviderResponse, error) {
+	var out []ServiceProviderResponse
+	if err := c.do(ctx, http.MethodGet, "/amass/"+url.PathEscape(scanID)+"/sp", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetShodanCompanyScanStatus calls GET /shodan-company/status/{scan_id}.
+//
+// Get shodan company scan status.
+func (c *Client) GetShodanCompanyScanStatus(ctx context.Context, scanID string) (*ShodanCompanyScanStatus, error) {
+	var out ShodanCompanyScanStatus
+	if err := c.do(ctx, http.MethodGet, "/shodan-company/status/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetShodanCompanyScansForScopeTarget calls GET /scopetarget/{id}/scans/shodan-company.
+//
+// Get shodan company scans for scope target.
+func (c *Client) GetShodanCompanyScansForScopeTarget(ctx context.Context, id string) ([]ShodanCompanyScanStatus, error) {
+	var out []ShodanCompanyScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/shodan-company", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetShuffleDNSCustomScansForScopeTarget calls GET /api/scope-targets/{id}/shufflednscustom-scans.
+//
+// Get shuffle DNS custom scans for scope target.
+func (c *Client) GetShuffleDNSCustomScansForScopeTarget(ctx context.Context, id string) ([]ShuffleDNSScanStatus, error) {
+	var out []ShuffleDNSScanStatus
+	if err := c.do(ctx, http.MethodGet, "/api/scope-targets/"+url.PathEscape(id)+"/shufflednscustom-scans", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetShuffleDNSScanStatus calls GET /shuffledns/{scan_id}.
+//
+// Get shuffle DNS scan status.
+func (c *Client) GetShuffleDNSScanStatus(ctx context.Context, scanID string) (*ShuffleDNSScanStatus, error) {
+	var out ShuffleDNSScanStatus
+	if err := c.do(ctx, http.MethodGet, "/shuffledns/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetShuffleDNSScansForScopeTarget calls GET /scopetarget/{id}/scans/shuffledns.
+//
+// Get shuffle DNS scans for scope target.
+func (c *Client) GetShuffleDNSScansForScopeTarget(ctx context.Context, id string) ([]ShuffleDNSScanStatus, error) {
+	var out []ShuffleDNSScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/shuffledns", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSubdomainTakeoverFindings calls GET /scopetarget/{id}/subdomain-takeover-findings.
+//
+// Get subdomain takeover findings.
+func (c *Client) GetSubdomainTakeoverFindings(ctx context.Context, id string) ([]SubdomainTakeoverFinding, error) {
+	var out []SubdomainTakeoverFinding
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/subdomain-takeover-findings", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSubdomainTakeoverScanStatus calls GET /subdomain-takeover/{scan_id}.
+//
+// Get subdomain takeover scan status.
+func (c *Client) GetSubdomainTakeoverScanStatus(ctx context.Context, scanID string) (*SubdomainTakeoverScan, error) {
+	var out SubdomainTakeoverScan
+	if err := c.do(ctx, http.MethodGet, "/subdomain-takeover/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSubdomainTakeoverScansForScopeTarget calls GET /scopetarget/{id}/scans/subdomain-takeover.
+//
+// Get subdomain takeover scans for scope target.
+func (c *Client) GetSubdomainTakeoverScansForScopeTarget(ctx context.Context, id string) ([]SubdomainTakeoverScan, error) {
+	var out []SubdomainTakeoverScan
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/subdomain-takeover", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSubdomainizerScanStatus calls GET /subdomainizer/{scan_id}.
+//
+// Get subdomainizer scan status.
+func (c *Client) GetSubdomainizerScanStatus(ctx context.Context, scanID string) (*SubdomainizerScanStatus, error) {
+	var out SubdomainizerScanStatus
+	if err := c.do(ctx, http.MethodGet, "/subdomainizer/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSubdomainizerScansForScopeTarget calls GET /scopetarget/{id}/scans/subdomainizer.
+//
+// Get subdomainizer scans for scope target.
+func (c *Client) GetSubdomainizerScansForScopeTarget(ctx context.Context, id string) ([]SubdomainizerScanStatus, error) {
+	var out []SubdomainizerScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/subdomainizer", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSubdomains calls GET /amass/{scan_id}/subdomain.
+//
+// Get subdomains.
+func (c *Client) GetSubdomains(ctx context.Context, scanID string) ([]string, error) {
+	var out []string
+	if err := c.do(ctx, http.MethodGet, "/amass/"+url.PathEscape(scanID)+"/subdomain", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSubfinderScanStatus calls GET /subfinder/{scan_id}.
+//
+// Get subfinder scan status.
+func (c *Client) GetSubfinderScanStatus(ctx context.Context, scanID string) (*SubfinderScanStatus, error) {
+	var out SubfinderScanStatus
+	if err := c.do(ctx, http.MethodGet, "/subfinder/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSubfinderScansForScopeTarget calls GET /scopetarget/{id}/scans/subfinder.
+//
+// Get subfinder scans for scope target.
+func (c *Client) GetSubfinderScansForScopeTarget(ctx context.Context, id string) ([]SubfinderScanStatus, error) {
+	var out []SubfinderScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/subfinder", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSublist3rScanStatus calls GET /sublist3r/{scan_id}.
+//
+// Get sublist3r scan status.
+func (c *Client) GetSublist3rScanStatus(ctx context.Context, scanID string) (*Sublist3rScanStatus, error) {
+	var out Sublist3rScanStatus
+	if err := c.do(ctx, http.MethodGet, "/sublist3r/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSublist3rScansForScopeTarget calls GET /scopetarget/{id}/scans/sublist3r.
+//
+// Get sublist3r scans for scope target.
+func (c *Client) GetSublist3rScansForScopeTarget(ctx context.Context, id string) ([]Sublist3rScanStatus, error) {
+	var out []Sublist3rScanStatus
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/sublist3r", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetSubnets calls GET /amass/{scan_id}/subnet.
+//
+// Get subnets.
+func (c *Client) GetSubnets(ctx context.Context, scanID string) ([]SubnetResponse, error) {
+	var out []SubnetResponse
+	if err := c.do(ctx, http.MethodGet, "/amass/"+url.PathEscape(scanID)+"/subnet", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetTargetURLsForScopeTarget calls GET /api/scope-targets/{id}/target-urls.
+//
+// Get target UR ls for scope target.
+func (c *Client) GetTargetURLsForScopeTarget(ctx context.Context, id string) ([]TargetURL, error) {
+	var out []TargetURL
+	if err := c.do(ctx, http.MethodGet, "/api/scope-targets/"+url.PathEscape(id)+"/target-urls", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetTechnologyInventoryParams holds the query parameters of GetTechnologyInventory
+type GetTechnologyInventoryParams struct {
+	// Only technologies in this category, e.g. CMS
+	Category string
+}
+
+// GetTechnologyInventory calls GET /scopetarget/{id}/technologies.
+//
+// Get technology inventory.
+func (c *Client) GetTechnologyInventory(ctx context.Context, id string, params *GetTechnologyInventoryParams) ([]TechnologyInventoryEntry, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Category != "" {
+			query.Set("category", params.Category)
+		}
+	}
+	var out []TechnologyInventoryEntry
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/technologies", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetURLCorpusParams holds the query parameters of GetURLCorpus
+type GetURLCorpusParams struct {
+	// Page number, starting at 1
+	Page string
+	// URLs per page, up to 1000 (default 100)
+	PageSize string
+	// One of: api, static, javascript, interesting_file, parameterized, page
+	Category string
+	// Only URLs on this host
+	Host string
+	// Only URLs with this file extension
+	Extension string
+	// Only URLs that take this query parameter
+	Param string
+	// Case insensitive match against the path pattern and example URL
+	Search string
+}
+
+// GetURLCorpus calls GET /scopetarget/{id}/urls.
+//
+// Get URL corpus.
+func (c *Client) GetURLCorpus(ctx context.Context, id string, params *GetURLCorpusParams) (*CorpusURLPage, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Page != "" {
+			query.Set("page", params.Page)
+		}
+		if params.PageSize != "" {
+			query.Set("page_size", params.PageSize)
+		}
+		if params.Category != "" {
+			query.Set("category", params.Category)
+		}
+		if params.Host != "" {
+			query.Set("host", params.Host)
+		}
+		if params.Extension != "" {
+			query.Set("extension", params.Extension)
+		}
+		if params.Param != "" {
+			query.Set("param", params.Param)
+		}
+		if params.Search != "" {
+			query.Set("search", params.Search)
+		}
+	}
+	var out CorpusURLPage
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/urls", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetURLParametersParams holds the query parameters of GetURLParameters
+type GetURLParametersParams struct {
+	// Only parameters seen on this host
+	Host string
+	// One of: ssrf, open_redirect, lfi, idor, sqli
+	Tag string
+	// Only parameters with this name
+	Name string
+}
+
+// GetURLParameters calls GET /scopetarget/{id}/parameters.
+//
+// Get URL parameters.
+func (c *Client) GetURLParameters(ctx context.Context, id string, params *GetURLParametersParams) ([]URLParameter, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Host != "" {
+			query.Set("host", params.Host)
+		}
+		if params.Tag != "" {
+			query.Set("tag", params.Tag)
+		}
+		if params.Name != "" {
+			query.Set("name", params.Name)
+		}
+	}
+	var out []URLParameter
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/parameters", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetUserSettings calls GET /user/settings.
+//
+// Get user settings.
+func (c *Client) GetUserSettings(ctx context.Context) (*UserSettings, error) {
+	var out UserSettings
+	if err := c.do(ctx, http.MethodGet, "/user/settings", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWebFingerprintClustersParams holds the query parameters of GetWebFingerprintClusters
+type GetWebFingerprintClustersParams struct {
+	// Fingerprint to group by: favicon, html_structure, tls_cert, ja3s or all (default)
+	By string
+	// Smallest group returned (default 2)
+	MinSize string
+}
+
+// GetWebFingerprintClusters calls GET /scopetarget/{id}/web-fingerprints/clusters.
+//
+// Get web fingerprint clusters.
+func (c *Client) GetWebFingerprintClusters(ctx context.Context, id string, params *GetWebFingerprintClustersParams) ([]WebFingerprintCluster, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.By != "" {
+			query.Set("by", params.By)
+		}
+		if params.MinSize != "" {
+			query.Set("min_size", params.MinSize)
+		}
+	}
+	var out []WebFingerprintCluster
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/web-fingerprints/clusters", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetWebFingerprintScanStatus calls GET /web-fingerprint/{scan_id}.
+//
+// Get web fingerprint scan status.
+func (c *Client) GetWebFingerprintScanStatus(ctx context.Context, scanID string) (*WebFingerprintScan, error) {
+	var out WebFingerprintScan
+	if err := c.do(ctx, http.MethodGet, "/web-fingerprint/"+url.PathEscape(scanID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetWebFingerprintScansForScopeTarget calls GET /scopetarget/{id}/scans/web-fingerprint.
+//
+// Get web fingerprint scans for scope target.
+func (c *Client) GetWebFingerprintScansForScopeTarget(ctx context.Context, id string) ([]WebFingerprintScan, error) {
+	var out []WebFingerprintScan
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/scans/web-fingerprint", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetWebFingerprints calls GET /scopetarget/{id}/web-fingerprints.
+//
+// Get web fingerprints.
+func (c *Client) GetWebFingerprints(ctx context.Context, id string) ([]WebFingerprint, error) {
+	var out []WebFingerprint
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/"+url.PathEscape(id)+"/web-fingerprints", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GetWildcardDNSZones calls GET /wildcard-dns-zones/{id}.
+//
+// Get wildcard DNS zones.
+func (c *Client) GetWildcardDNSZones(ctx context.Context, id string) ([]WildcardDNSZone, error) {
+	var out []WildcardDNSZone
+	if err := c.do(ctx, http.MethodGet, "/wildcard-dns-zones/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HandleConsolidateCompanyDomains calls GET /consolidate-company-domains/{id}.
+//
+// Handle consolidate company domains.
+func (c *Client) HandleConsolidateCompanyDomains(ctx context.Context, id string) (*ConsolidatedCompanyDomainsResponse, error) {
+	var out ConsolidatedCompanyDomainsResponse
+	if err := c.do(ctx, http.MethodGet, "/consolidate-company-domains/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// HandleConsolidateNetworkRanges calls GET /consolidate-network-ranges/{id}.
+//
+// Handle consolidate network ranges.
+func (c *Client) HandleConsolidateNetworkRanges(ctx context.Context, id string) (*ConsolidatedNetworkRangesResponse, error) {
+	var out ConsolidatedNetworkRangesResponse
+	if err := c.do(ctx, http.MethodGet, "/consolidate-network-ranges/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// HandleConsolidateSubdomains calls GET /consolidate-subdomains/{id}.
+//
+// Handle consolidate subdomains.
+func (c *Client) HandleConsolidateSubdomains(ctx context.Context, id string) (*ConsolidatedSubdomainsResponse, error) {
+	var out ConsolidatedSubdomainsResponse
+	if err := c.do(ctx, http.MethodGet, "/consolidate-subdomains/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// HandleDatabaseExport calls POST /api/database-export.
+//
+// Handle database export.
+func (c *Client) HandleDatabaseExport(ctx context.Context, body DatabaseExportRequest) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodPost, "/api/database-export", nil, body)
+}
+
+// HandleDatabaseImport calls POST /api/database-import.
+//
+// Handle database import.
+// Accepts a multipart/form-data upload of a database export file.
+func (c *Client) HandleDatabaseImport(ctx context.Context) (*DatabaseImportResponse, error) {
+	var out DatabaseImportResponse
+	if err := c.do(ctx, http.MethodPost, "/api/database-import", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// HandleDatabaseImportURL calls POST /api/database-import-url.
+//
+// Handle database import URL.
+func (c *Client) HandleDatabaseImportURL(ctx context.Context, body DatabaseImportURLRequest) (*DatabaseImportResponse, error) {
+	var out DatabaseImportResponse
+	if err := c.do(ctx, http.MethodPost, "/api/database-import-url", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// HandleDefectDojoExportParams holds the query parameters of HandleDefectDojoExport
+type HandleDefectDojoExportParams struct {
+	// Comma separated finding sources: nuclei, tls, services, takeover, dns, email, js
+	Sources string
+}
+
+// HandleDefectDojoExport calls GET /api/findings/{scope_target_id}/defectdojo.
+//
+// Handle defect dojo export.
+// DefectDojo Generic Findings Import document.
+func (c *Client) HandleDefectDojoExport(ctx context.Context, scopeTargetID string, params *HandleDefectDojoExportParams) (*DefectDojoExport, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Sources != "" {
+			query.Set("sources", params.Sources)
+		}
+	}
+	var out DefectDojoExport
+	if err := c.do(ctx, http.MethodGet, "/api/findings/"+url.PathEscape(scopeTargetID)+"/defectdojo", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// HandleDefectDojoImport calls POST /api/findings/{scope_target_id}/defectdojo/import.
+//
+// Handle defect dojo import.
+// Accepts a DefectDojo findings export and stores the triage status of matching findings. IDs that match no local finding are returned as unmatched.
+func (c *Client) HandleDefectDojoImport(ctx context.Context, scopeTargetID string) (*DefectDojoImportResponse, error) {
+	var out DefectDojoImportResponse
+	if err := c.do(ctx, http.MethodPost, "/api/findings/"+url.PathEscape(scopeTargetID)+"/defectdojo/import", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// HandleExportData calls POST /api/export-data.
+//
+// Handle export data.
+func (c *Client) HandleExportData(ctx context.Context, body ExportRequest) ([]byte, error) {
+	return c.doRaw(ctx, http.MethodPost, "/api/export-data", nil, body)
+}
+
+// HandleSARIFExportParams holds the query parameters of HandleSARIFExport
+type HandleSARIFExportParams struct {
+	// Comma separated finding sources: nuclei, tls, services, takeover, dns, email, js
+	Sources string
+}
+
+// HandleSARIFExport calls GET /api/findings/{scope_target_id}/sarif.
+//
+// Handle SARIF export.
+// SARIF 2.1.0 log with one run per finding source.
+func (c *Client) HandleSARIFExport(ctx context.Context, scopeTargetID string, params *HandleSARIFExportParams) ([]byte, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Sources != "" {
+			query.Set("sources", params.Sources)
+		}
+	}
+	return c.doRaw(ctx, http.MethodGet, "/api/findings/"+url.PathEscape(scopeTargetID)+"/sarif", query, nil)
+}
+
+// ListAutoScanSessions calls GET /api/auto-scan/sessions.
+//
+// List auto scan sessions.
+func (c *Client) ListAutoScanSessions(ctx context.Context) ([]AutoScanSession, error) {
+	var out []AutoScanSession
+	if err := c.do(ctx, http.MethodGet, "/api/auto-scan/sessions", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MatchShodanExport calls POST /scopetarget/{id}/web-fingerprints/shodan-match.
+//
+// Match shodan export.
+// Accepts a decompressed Shodan download, one JSON banner per line, as the request body or as a multipart/form-data upload with a file field.
+func (c *Client) MatchShodanExport(ctx context.Context, id string) ([]ShodanFaviconMatch, error) {
+	var out []ShodanFaviconMatch
+	if err := c.do(ctx, http.MethodPost, "/scopetarget/"+url.PathEscape(id)+"/web-fingerprints/shodan-match", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ReadScopeTarget calls GET /scopetarget/read.
+//
+// Read scope target.
+func (c *Client) ReadScopeTarget(ctx context.Context) ([]ResponsePayload, error) {
+	var out []ResponsePayload
+	if err := c.do(ctx, http.MethodGet, "/scopetarget/read", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RunAmassEnumCompanyScan calls POST /amass-enum-company/run/{scope_target_id}.
+//
+// Run amass enum company scan.
+func (c *Client) RunAmassEnumCompanyScan(ctx context.Context, scopeTargetID string, body DomainsScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/amass-enum-company/run/"+url.PathEscape(scopeTargetID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunAmassIntelScan calls POST /amass-intel/run.
+//
+// Run amass intel scan.
+func (c *Client) RunAmassIntelScan(ctx context.Context, body CompanyScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/amass-intel/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunAmassScan calls POST /amass/run.
+//
+// Run amass scan.
+func (c *Client) RunAmassScan(ctx context.Context, body DomainScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/amass/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunAssetfinderScan calls POST /assetfinder/run.
+//
+// Run assetfinder scan.
+func (c *Client) RunAssetfinderScan(ctx context.Context, body DomainScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/assetfinder/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunCTLCompanyScan calls POST /ctl-company/run.
+//
+// Run CTL company scan.
+func (c *Client) RunCTLCompanyScan(ctx context.Context, body CompanyScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/ctl-company/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunCTLScan calls POST /ctl/run.
+//
+// Run CTL scan.
+func (c *Client) RunCTLScan(ctx context.Context, body DomainScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/ctl/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunCeWLScan calls POST /cewl/run.
+//
+// Run ce WL scan.
+func (c *Client) RunCeWLScan(ctx context.Context, body DomainScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/cewl/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunCeWLScansForUrls calls POST /cewl-urls/run.
+//
+// Run ce WL scans for urls.
+func (c *Client) RunCeWLScansForUrls(ctx context.Context, body URLsScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/cewl-urls/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunCensysCompanyScan calls POST /censys-company/run.
+//
+// Run censys company scan.
+func (c *Client) RunCensysCompanyScan(ctx context.Context, body CompanyScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/censys-company/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunCloudEnumScan calls POST /cloud-enum/run.
+//
+// Run cloud enum scan.
+func (c *Client) RunCloudEnumScan(ctx context.Context, body CompanyScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/cloud-enum/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunCompanyMetaDataScan calls POST /metadata/run-company.
+//
+// Run company meta data scan.
+func (c *Client) RunCompanyMetaDataScan(ctx context.Context, body CompanyMetaDataScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/metadata/run-company", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunContentDiscoveryScan calls POST /content-discovery/run.
+//
+// Run content discovery scan.
+func (c *Client) RunContentDiscoveryScan(ctx context.Context, body ContentDiscoveryScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/content-discovery/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunDNSAuditScan calls POST /dns-audit/run.
+//
+// Run DNS audit scan.
+func (c *Client) RunDNSAuditScan(ctx context.Context, body DNSAuditScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/dns-audit/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunDNSResolverHealthCheck calls POST /api/dns-resolvers/health-check.
+//
+// Run DNS resolver health check.
+func (c *Client) RunDNSResolverHealthCheck(ctx context.Context) ([]ResolverStatus, error) {
+	var out []ResolverStatus
+	if err := c.do(ctx, http.MethodPost, "/api/dns-resolvers/health-check", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// RunDNSxCompanyScan calls POST /dnsx-company/run/{scope_target_id}.
+//
+// Run DN sx company scan.
+func (c *Client) RunDNSxCompanyScan(ctx context.Context, scopeTargetID string, body DomainsScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/dnsx-company/run/"+url.PathEscape(scopeTargetID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunEmailSecurityScan calls POST /email-security/run.
+//
+// Run email security scan.
+func (c *Client) RunEmailSecurityScan(ctx context.Context, body EmailSecurityScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/email-security/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunGauScan calls POST /gau/run.
+//
+// Run gau scan.
+func (c *Client) RunGauScan(ctx context.Context, body GauScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/gau/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunGitHubReconScan calls POST /github-recon/run.
+//
+// Run git hub recon scan.
+func (c *Client) RunGitHubReconScan(ctx context.Context, body CompanyScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/github-recon/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunGoSpiderScan calls POST /gospider/run.
+//
+// Run go spider scan.
+func (c *Client) RunGoSpiderScan(ctx context.Context, body DomainScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/gospider/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunHttpxScan calls POST /httpx/run.
+//
+// Run httpx scan.
+func (c *Client) RunHttpxScan(ctx context.Context, body DomainScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/httpx/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunIPPortScan calls POST /ip-port-scan/run.
+//
+// Run IP port scan.
+func (c *Client) RunIPPortScan(ctx context.Context, body IPPortScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/ip-port-scan/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunInvestigateScan calls POST /investigate/run.
+//
+// Run investigate scan.
+func (c *Client) RunInvestigateScan(ctx context.Context, body ScopeTargetScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/investigate/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunJSAnalysisScan calls POST /js-analysis/run.
+//
+// Run JS analysis scan.
+func (c *Client) RunJSAnalysisScan(ctx context.Context, body ScopeTargetScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/js-analysis/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunKatanaCompanyScan calls POST /katana-company/run/{scope_target_id}.
+//
+// Run katana company scan.
+func (c *Client) RunKatanaCompanyScan(ctx context.Context, scopeTargetID string, body DomainsScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/katana-company/run/"+url.PathEscape(scopeTargetID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunMetaDataScan calls POST /metadata/run.
+//
+// Run meta data scan.
+func (c *Client) RunMetaDataScan(ctx context.Context, body ScopeTargetScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/metadata/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunMetabigorASNScan calls POST /metabigor-asn/run.
+//
+// Run metabigor ASN scan.
+func (c *Client) RunMetabigorASNScan(ctx context.Context, body MetabigorASNScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/metabigor-asn/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunMetabigorCompanyScan calls POST /metabigor-company/run.
+//
+// Run metabigor company scan.
+func (c *Client) RunMetabigorCompanyScan(ctx context.Context, body CompanyScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/metabigor-company/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunMetabigorIPIntelligence calls POST /metabigor-ip/run.
+//
+// Run metabigor IP intelligence.
+func (c *Client) RunMetabigorIPIntelligence(ctx context.Context, body MetabigorIPIntelligenceRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/metabigor-ip/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunMetabigorNetdScan calls POST /metabigor-netd/run.
+//
+// Run metabigor netd scan.
+func (c *Client) RunMetabigorNetdScan(ctx context.Context, body CompanyScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/metabigor-netd/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunNucleiScreenshotScan calls POST /nuclei-screenshot/run.
+//
+// Run nuclei screenshot scan.
+func (c *Client) RunNucleiScreenshotScan(ctx context.Context, body AutoScanSessionRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/nuclei-screenshot/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunNucleiScreenshotScanForScopeTarget calls POST /scopetarget/{id}/nuclei-screenshot/run.
+//
+// Run nuclei screenshot scan for scope target.
+func (c *Client) RunNucleiScreenshotScanForScopeTarget(ctx context.Context, id string, body AutoScanSessionRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/scopetarget/"+url.PathEscape(id)+"/nuclei-screenshot/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunParameterMiningScan calls POST /parameter-mining/run.
+//
+// Run parameter mining scan.
+func (c *Client) RunParameterMiningScan(ctx context.Context, body ScopeTargetScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/parameter-mining/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunPermutationScan calls POST /permutations/run.
+//
+// Run permutation scan.
+func (c *Client) RunPermutationScan(ctx context.Context, body PermutationScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/permutations/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunRecursiveEnumerationScan calls POST /recursive-enum/run.
+//
+// Run recursive enumeration scan.
+func (c *Client) RunRecursiveEnumerationScan(ctx context.Context, body RecursiveEnumerationScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/recursive-enum/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunScreenshotScan calls POST /screenshot/run.
+//
+// Run screenshot scan.
+// Captures the given URLs, or every live target URL of the scope target, in the headless Chrome container. Defaults: 1280x800 viewport, 30 second timeout, 5 pages at a time.
+func (c *Client) RunScreenshotScan(ctx context.Context, body ScreenshotScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/screenshot/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunSecurityHeaderScan calls POST /security-headers/run.
+//
+// Run security header scan.
+// Requests every live web server from an arbitrary Origin, then from null and look-alike origins, and grades its CSP, HSTS, framing, CORS and cookie flags. URLs that do not answer are graded from the headers stored by the metadata scan.
+func (c *Client) RunSecurityHeaderScan(ctx context.Context, body SecurityHeaderScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/security-headers/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunSecurityTrailsCompanyScan calls POST /securitytrails-company/run.
+//
+// Run security trails company scan.
+func (c *Client) RunSecurityTrailsCompanyScan(ctx context.Context, body CompanyScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/securitytrails-company/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunShodanCompanyScan calls POST /shodan-company/run.
+//
+// Run shodan company scan.
+func (c *Client) RunShodanCompanyScan(ctx context.Context, body CompanyScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/shodan-company/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunShuffleDNSScan calls POST /shuffledns/run.
+//
+// Run shuffle DNS scan.
+func (c *Client) RunShuffleDNSScan(ctx context.Context, body DomainScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/shuffledns/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunShuffleDNSWithWordlist calls POST /cewl-wordlist/run.
+//
+// Run shuffle DNS with wordlist.
+func (c *Client) RunShuffleDNSWithWordlist(ctx context.Context, body WordlistScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/cewl-wordlist/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunSubdomainTakeoverScan calls POST /subdomain-takeover/run.
+//
+// Run subdomain takeover scan.
+func (c *Client) RunSubdomainTakeoverScan(ctx context.Context, body ScopeTargetScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/subdomain-takeover/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunSubdomainizerScan calls POST /subdomainizer/run.
+//
+// Run subdomainizer scan.
+func (c *Client) RunSubdomainizerScan(ctx context.Context, body DomainScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/subdomainizer/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunSubfinderScan calls POST /subfinder/run.
+//
+// Run subfinder scan.
+func (c *Client) RunSubfinderScan(ctx context.Context, body DomainScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/subfinder/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunSublist3rScan calls POST /sublist3r/run.
+//
+// Run sublist3r scan.
+func (c *Client) RunSublist3rScan(ctx context.Context, body DomainScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/sublist3r/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RunWebFingerprintScan calls POST /web-fingerprint/run.
+//
+// Run web fingerprint scan.
+func (c *Client) RunWebFingerprintScan(ctx context.Context, body ScopeTargetScanRequest) (*ScanStartedResponse, error) {
+	var out ScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/web-fingerprint/run", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveAmassEnumConfig calls POST /amass-enum-config/{scope_target_id}.
+//
+// Save amass enum config.
+func (c *Client) SaveAmassEnumConfig(ctx context.Context, scopeTargetID string, body CompanyDomainsConfig) (*ConfigSavedResponse, error) {
+	var out ConfigSavedResponse
+	if err := c.do(ctx, http.MethodPost, "/amass-enum-config/"+url.PathEscape(scopeTargetID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveAmassIntelConfig calls POST /amass-intel-config/{scope_target_id}.
+//
+// Save amass intel config.
+func (c *Client) SaveAmassIntelConfig(ctx context.Context, scopeTargetID string, body AmassIntelConfig) (*ConfigSavedResponse, error) {
+	var out ConfigSavedResponse
+	if err := c.do(ctx, http.MethodPost, "/amass-intel-config/"+url.PathEscape(scopeTargetID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveCloudEnumConfig calls POST /cloud-enum-config/{scope_target_id}.
+//
+// Save cloud enum config.
+func (c *Client) SaveCloudEnumConfig(ctx context.Context, scopeTargetID string) (*StatusResponse, error) {
+	var out StatusResponse
+	if err := c.do(ctx, http.MethodPost, "/cloud-enum-config/"+url.PathEscape(scopeTargetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveDNSxConfig calls POST /dnsx-config/{scope_target_id}.
+//
+// Save DN sx config.
+func (c *Client) SaveDNSxConfig(ctx context.Context, scopeTargetID string, body CompanyDomainsConfig) (*ConfigSavedResponse, error) {
+	var out ConfigSavedResponse
+	if err := c.do(ctx, http.MethodPost, "/dnsx-config/"+url.PathEscape(scopeTargetID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveIPPortScanConfig calls POST /ip-port-scan-config/{scope_target_id}.
+//
+// Save IP port scan config.
+func (c *Client) SaveIPPortScanConfig(ctx context.Context, scopeTargetID string, body IPPortScanConfig) (*IPPortScanConfig, error) {
+	var out IPPortScanConfig
+	if err := c.do(ctx, http.MethodPost, "/ip-port-scan-config/"+url.PathEscape(scopeTargetID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveKatanaCompanyConfig calls POST /katana-company-config/{scope_target_id}.
+//
+// Save katana company config.
+func (c *Client) SaveKatanaCompanyConfig(ctx context.Context, scopeTargetID string) (*StatusResponse, error) {
+	var out StatusResponse
+	if err := c.do(ctx, http.MethodPost, "/katana-company-config/"+url.PathEscape(scopeTargetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SaveNucleiConfig calls POST /nuclei-config/{scope_target_id}.
+//
+// Save nuclei config.
+func (c *Client) SaveNucleiConfig(ctx context.Context, scopeTargetID string) (*StatusResponse, error) {
+	var out StatusResponse
+	if err := c.do(ctx, http.MethodPost, "/nuclei-config/"+url.PathEscape(scopeTargetID), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendTargetURLsToBurp calls POST /api/burp/send.
+//
+// Send target UR ls to burp.
+func (c *Client) SendTargetURLsToBurp(ctx context.Context, body BurpSendRequest) (*BurpSendResponse, error) {
+	var out BurpSendResponse
+	if err := c.do(ctx, http.MethodPost, "/api/burp/send", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// StartAutoScanSession calls POST /api/auto-scan/session/start.
+//
+// Start auto scan session.
+func (c *Client) StartAutoScanSession(ctx context.Context, body AutoScanSessionStartRequest) (*SessionStartedResponse, error) {
+	var out SessionStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/api/auto-scan/session/start", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// StartNucleiScan calls POST /scopetarget/{id}/scans/nuclei/start.
+//
+// Start nuclei scan.
+func (c *Client) StartNucleiScan(ctx context.Context, id string) (*NucleiScanStartedResponse, error) {
+	var out NucleiScanStartedResponse
+	if err := c.do(ctx, http.MethodPost, "/scopetarget/"+url.PathEscape(id)+"/scans/nuclei/start", nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateAPIKey calls PUT /api/api-keys/{id}.
+//
+// Update API key.
+func (c *Client) UpdateAPIKey(ctx context.Context, id string) (*MessageResponse, error) {
+	var out MessageResponse
+	if err := c.do(ctx, http.MethodPut, "/api/api-keys/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateAiAPIKey calls PUT /api/ai-api-keys/{id}.
+//
+// Update ai API key.
+func (c *Client) UpdateAiAPIKey(ctx context.Context, id string) (*MessageResponse, error) {
+	var out MessageResponse
+	if err := c.do(ctx, http.MethodPut, "/api/ai-api-keys/"+url.PathEscape(id), nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateAutoScanConfig calls POST /api/auto-scan-config.
+//
+// Update auto scan config.
+func (c *Client) UpdateAutoScanConfig(ctx context.Context, body AutoScanConfig) (*AutoScanConfig, error) {
+	var out AutoScanConfig
+	if err := c.do(ctx, http.MethodPost, "/api/auto-scan-config", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateAutoScanSessionFinalStats calls POST /api/auto-scan/session/{id}/final-stats.
+//
+// Update auto scan session final stats.
+func (c *Client) UpdateAutoScanSessionFinalStats(ctx context.Context, id string, body AutoScanFinalStatsRequest) (*AutoScanSessionStatusResponse, error) {
+	var out AutoScanSessionStatusResponse
+	if err := c.do(ctx, http.MethodPost, "/api/auto-scan/session/"+url.PathEscape(id)+"/final-stats", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateAutoScanState calls POST /api/auto-scan-state/{target_id}.
+//
+// Update auto scan state.
+func (c *Client) UpdateAutoScanState(ctx context.Context, targetID string, body AutoScanStateRequest) (*AutoScanStateResponse, error) {
+	var out AutoScanStateResponse
+	if err := c.do(ctx, http.MethodPost, "/api/auto-scan-state/"+url.PathEscape(targetID), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateContentWordlistTags calls PUT /content-wordlists/{id}.
+//
+// Update content wordlist tags.
+func (c *Client) UpdateContentWordlistTags(ctx context.Context, id string, body ContentWordlistTagsRequest) (*ContentWordlist, error) {
+	var out ContentWordlist
+	if err := c.do(ctx, http.MethodPut, "/content-wordlists/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateDNSResolvers calls PUT /api/dns-resolvers.
+//
+// Update DNS resolvers.
+func (c *Client) UpdateDNSResolvers(ctx context.Context, body DNSResolversRequest) ([]ResolverStatus, error) {
+	var out []ResolverStatus
+	if err := c.do(ctx, http.MethodPut, "/api/dns-resolvers", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UpdateReportTemplate calls PUT /api/report-templates/{id}.
+//
+// Update report template.
+func (c *Client) UpdateReportTemplate(ctx context.Context, id string, body ReportTemplate) (*ReportTemplate, error) {
+	var out ReportTemplate
+	if err := c.do(ctx, http.MethodPut, "/api/report-templates/"+url.PathEscape(id), nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateTargetURLROIScore calls PUT /api/target-urls/{id}/roi-score.
+//
+// Update target URLROI score.
+func (c *Client) UpdateTargetURLROIScore(ctx context.Context, id string, body ROIScoreRequest) error {
+	return c.do(ctx, http.MethodPut, "/api/target-urls/"+url.PathEscape(id)+"/roi-score", nil, body, nil)
+}
+
+// UpdateUserSettings calls POST /user/settings.
+//
+// Update user settings.
+func (c *Client) UpdateUserSettings(ctx context.Context, body UserSettings) (*SuccessResponse, error) {
+	var out SuccessResponse
+	if err := c.do(ctx, http.MethodPost, "/user/settings", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UploadContentWordlist calls POST /content-wordlists.
+//
+// Upload content wordlist.
+// Also accepts a multipart/form-data upload with file, name and comma separated tags fields.
+func (c *Client) UploadContentWordlist(ctx context.Context, body ContentWordlistRequest) (*ContentWordlist, error) {
+	var out ContentWordlist
+	if err := c.do(ctx, http.MethodPost, "/content-wordlists", nil, body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UploadFingerprintRuleFileParams holds the query parameters of UploadFingerprintRuleFile
+type UploadFingerprintRuleFileParams struct {
+	// Rule file name, replacing a file of the same name
+	Name string
+}
+
+// UploadFingerprintRuleFile calls POST /fingerprint-rules.
+//
+// Upload fingerprint rule file.
+// Accepts a Wappalyzer-format rule file as the JSON body, named by the name query parameter, or as a multipart/form-data upload with file and name fields.
+func (c *Client) UploadFingerprintRuleFile(ctx context.Context, params *UploadFingerprintRuleFileParams) (*FingerprintRuleFile, error) {
+	query := url.Values{}
+	if params != nil {
+		if params.Name != "" {
+			query.Set("name", params.Name)
+		}
+	}
+	var out FingerprintRuleFile
+	if err := c.do(ctx, http.MethodPost, "/fingerprint-rules", query, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
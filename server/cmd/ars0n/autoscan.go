@@ -0,0 +1,343 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"ars0n-framework-v2-server/client"
+)
+
+// Auto scan step names as stored in the auto scan state, matching AUTO_SCAN_STEPS in the web UI
+const (
+	stepConsolidate       = "consolidate"
+	stepHttpx             = "httpx"
+	stepRecursiveEnum     = "recursive_enum"
+	stepShuffleDNSCeWL    = "shuffledns_cewl"
+	stepConsolidateRound2 = "consolidate_round2"
+	stepHttpxRound2       = "httpx_round2"
+	stepConsolidateRound3 = "consolidate_round3"
+	stepHttpxRound3       = "httpx_round3"
+	stepCompleted         = "completed"
+)
+
+// errLimitExceeded stops an auto scan the same way the web UI pauses it
+type errLimitExceeded struct {
+	what  string
+	count int
+	limit int
+	flag  string
+}
+
+func (e *errLimitExceeded) Error() string {
+	return fmt.Sprintf("%d %s exceed the configured limit of %d, raise %s in the auto scan config or pass -ignore-limits", e.count, e.what, e.limit, e.flag)
+}
+
+type autoScan struct {
+	c            *client.Client
+	tools        map[string]scanTool
+	target       client.ResponsePayload
+	config       *client.AutoScanConfig
+	sessionID    string
+	interval     time.Duration
+	timeout      time.Duration
+	ignoreLimits bool
+}
+
+func runAutoScan(ctx context.Context, c *client.Client, args []string) error {
+	fs := newFlagSet("autoscan")
+	targetRef := fs.String("target", "", "wildcard scope target ID or value")
+	interval := fs.Duration("interval", 10*time.Second, "status poll interval")
+	timeout := fs.Duration("timeout", 0, "give up waiting on a single scan after this long (0 waits forever)")
+	skip := fs.String("skip", "", "comma separated steps to skip in addition to the ones disabled in the auto scan config")
+	ignoreLimits := fs.Bool("ignore-limits", false, "keep going when the subdomain or live web server limits are exceeded")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	target, err := resolveTarget(ctx, c, *targetRef)
+	if err != nil {
+		return err
+	}
+	if target.Type != "Wildcard" {
+		return fmt.Errorf("auto scan runs against wildcard targets, %s is a %s target", target.ScopeTarget, target.Type)
+	}
+
+	config, err := c.GetAutoScanConfig(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get auto scan config: %v", err)
+	}
+	for _, step := range strings.Split(*skip, ",") {
+		if err := disableStep(config, strings.TrimSpace(step)); err != nil {
+			return err
+		}
+	}
+
+	session, err := c.StartAutoScanSession(ctx, client.AutoScanSessionStartRequest{ScopeTargetID: target.ID, ConfigSnapshot: *config})
+	if err != nil {
+		return fmt.Errorf("failed to start auto scan session: %v", err)
+	}
+	log.Printf("[auto-scan] Started session %s for %s", session.SessionID, target.ScopeTarget)
+
+	scan := &autoScan{
+		c:            c,
+		tools:        scanTools(c),
+		target:       target,
+		config:       config,
+		sessionID:    session.SessionID,
+		interval:     *interval,
+		timeout:      *timeout,
+		ignoreLimits: *ignoreLimits,
+	}
+
+	if err := scan.run(ctx); err != nil {
+		if ctx.Err() != nil {
+			// Interrupted, so record the cancellation with a fresh context for the web UI
+			cleanupCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			c.CancelAutoScanSession(cleanupCtx, scan.sessionID)
+			c.UpdateAutoScanState(cleanupCtx, target.ID, client.AutoScanStateRequest{CurrentStep: stepCompleted, IsCancelled: true})
+		}
+		return err
+	}
+	return nil
+}
+
+// disableStep turns off a step by its auto scan config key or its step name
+func disableStep(config *client.AutoScanConfig, step string) error {
+	switch step {
+	case "":
+	case "amass":
+		config.Amass = false
+	case "sublist3r":
+		config.Sublist3r = false
+	case "assetfinder":
+		config.Assetfinder = false
+	case "gau":
+		config.Gau = false
+	case "ctl":
+		config.Ctl = false
+	case "subfinder":
+		config.Subfinder = false
+	case "consolidate_httpx_round1", stepConsolidate, stepHttpx:
+		config.ConsolidateHttpxRound1 = false
+	case "recursive_enumeration", stepRecursiveEnum:
+		config.RecursiveEnumeration = false
+	case "shuffledns":
+		config.Shuffledns = false
+	case "cewl", stepShuffleDNSCeWL:
+		config.Cewl = false
+	case "permutations":
+		config.Permutations = false
+	case "consolidate_httpx_round2", stepConsolidateRound2, stepHttpxRound2:
+		config.ConsolidateHttpxRound2 = false
+	case "gospider":
+		config.Gospider = false
+	case "subdomainizer":
+		config.Subdomainizer = false
+	case "consolidate_httpx_round3", stepConsolidateRound3, stepHttpxRound3:
+		config.ConsolidateHttpxRound3 = false
+	case "nuclei_screenshot", "nuclei-screenshot":
+		config.NucleiScreenshot = false
+	case "metadata":
+		config.Metadata = false
+	default:
+		return fmt.Errorf("unknown auto scan step %q", step)
+	}
+	return nil
+}
+
+// run walks the wildcard workflow in the same order as the web UI's auto scan
+func (a *autoScan) run(ctx context.Context) error {
+	steps := []struct {
+		enabled bool
+		run     func(context.Context) error
+	}{
+		{a.config.Amass, a.tool("amass")},
+		{a.config.Sublist3r, a.tool("sublist3r")},
+		{a.config.Assetfinder, a.tool("assetfinder")},
+		{a.config.Gau, a.tool("gau")},
+		{a.config.Ctl, a.tool("ctl")},
+		{a.config.Subfinder, a.tool("subfinder")},
+		{a.config.ConsolidateHttpxRound1, a.consolidateAndProbe(stepConsolidate, stepHttpx)},
+		{a.config.RecursiveEnumeration, a.tool(stepRecursiveEnum)},
+		{a.config.Shuffledns, a.tool("shuffledns")},
+		{a.config.Cewl, a.cewl},
+		{a.config.Permutations, a.tool("permutations")},
+		{a.config.ConsolidateHttpxRound2, a.consolidateAndProbe(stepConsolidateRound2, stepHttpxRound2)},
+		{a.config.Gospider, a.tool("gospider")},
+		{a.config.Subdomainizer, a.tool("subdomainizer")},
+		{a.config.ConsolidateHttpxRound3, a.consolidateAndProbe(stepConsolidateRound3, stepHttpxRound3)},
+		{a.config.NucleiScreenshot, a.tool("nuclei-screenshot")},
+		{a.config.Metadata, a.tool("metadata")},
+	}
+	for _, step := range steps {
+		if !step.enabled {
+			continue
+		}
+		if err := step.run(ctx); err != nil {
+			return err
+		}
+	}
+
+	a.setState(ctx, stepCompleted, false)
+	return a.finish(ctx)
+}
+
+// tool runs a single scan and waits for it. Scan failures are logged and the auto scan moves on,
+// like it does in the web UI.
+func (a *autoScan) tool(name string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		a.setState(ctx, name, false)
+		_, err := a.runTool(ctx, name)
+		return err
+	}
+}
+
+func (a *autoScan) runTool(ctx context.Context, name string) (string, error) {
+	tool := a.tools[name]
+	started, err := tool.start(ctx, a.target, a.sessionID)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		log.Printf("[auto-scan] Failed to start %s scan, skipping: %v", name, err)
+		return "", nil
+	}
+	status, err := waitForScan(ctx, name, tool, started.ScanID, a.interval, a.timeout)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", err
+		}
+		log.Printf("[auto-scan] %v, moving on", err)
+		return "", nil
+	}
+	if scanFailed(status) {
+		log.Printf("[auto-scan] %s scan %s finished with status %s, moving on", name, started.ScanID, status)
+	}
+	return started.ScanID, nil
+}
+
+// cewl runs CeWL, which makes the server start a ShuffleDNS scan with the generated wordlist, and
+// waits for that scan too
+func (a *autoScan) cewl(ctx context.Context) error {
+	a.setState(ctx, stepShuffleDNSCeWL, false)
+
+	// Remember the existing wordlist scans so we wait on the one this CeWL run starts
+	previous := make(map[string]bool)
+	if scans, err := a.c.GetShuffleDNSCustomScansForScopeTarget(ctx, a.target.ID); err == nil {
+		for _, scan := range scans {
+			previous[scan.ScanID] = true
+		}
+	}
+
+	scanID, err := a.runTool(ctx, "cewl")
+	if err != nil || scanID == "" {
+		return err
+	}
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+	var deadline <-chan time.Time
+	if a.timeout > 0 {
+		timer := time.NewTimer(a.timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	lastStatus := ""
+	for {
+		scans, err := a.c.GetShuffleDNSCustomScansForScopeTarget(ctx, a.target.ID)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[shuffledns-cewl] Failed to list scans: %v", err)
+		}
+		if len(scans) > 0 && !previous[scans[0].ScanID] {
+			latest := scans[0]
+			if latest.Status != lastStatus {
+				log.Printf("[shuffledns-cewl] Scan %s is %s", latest.ScanID, latest.Status)
+				lastStatus = latest.Status
+			}
+			if scanDone(latest.Status) {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline:
+			log.Printf("[auto-scan] Stopped waiting for the ShuffleDNS scan with the CeWL wordlist, moving on")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// consolidateAndProbe consolidates the subdomains found so far and runs httpx against them,
+// stopping when either result exceeds the limits in the auto scan config
+func (a *autoScan) consolidateAndProbe(consolidateStep, httpxStep string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		a.setState(ctx, consolidateStep, false)
+		consolidated, err := a.c.HandleConsolidateSubdomains(ctx, a.target.ID)
+		if err != nil {
+			return fmt.Errorf("failed to consolidate subdomains: %v", err)
+		}
+		log.Printf("[auto-scan] Consolidated %d subdomains", len(consolidated.Subdomains))
+		if limit := a.config.MaxConsolidatedSubdomains; !a.ignoreLimits && limit > 0 && len(consolidated.Subdomains) > limit {
+			a.setState(ctx, consolidateStep, true)
+			return &errLimitExceeded{what: "consolidated subdomains", count: len(consolidated.Subdomains), limit: limit, flag: "maxConsolidatedSubdomains"}
+		}
+
+		a.setState(ctx, httpxStep, false)
+		if _, err := a.runTool(ctx, "httpx"); err != nil {
+			return err
+		}
+		live, err := a.c.GetLiveWebServersCount(ctx, a.target.ID)
+		if err != nil {
+			return fmt.Errorf("failed to count live web servers: %v", err)
+		}
+		log.Printf("[auto-scan] %d live web servers", live.Count)
+		if limit := a.config.MaxLiveWebServers; !a.ignoreLimits && limit > 0 && live.Count > limit {
+			a.setState(ctx, httpxStep, true)
+			return &errLimitExceeded{what: "live web servers", count: live.Count, limit: limit, flag: "maxLiveWebServers"}
+		}
+		return nil
+	}
+}
+
+// setState mirrors progress into the auto scan state the web UI displays. Failures only affect
+// the UI, so they are logged and ignored.
+func (a *autoScan) setState(ctx context.Context, step string, paused bool) {
+	if step != stepCompleted {
+		log.Printf("[auto-scan] Step: %s", step)
+	}
+	if _, err := a.c.UpdateAutoScanState(ctx, a.target.ID, client.AutoScanStateRequest{CurrentStep: step, IsPaused: paused}); err != nil && ctx.Err() == nil {
+		log.Printf("[auto-scan] Failed to update auto scan state: %v", err)
+	}
+}
+
+// finish stores the final counts on the session, which also marks it completed
+func (a *autoScan) finish(ctx context.Context) error {
+	subdomains, err := a.c.GetConsolidatedSubdomains(ctx, a.target.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get consolidated subdomains: %v", err)
+	}
+	live, err := a.c.GetLiveWebServersCount(ctx, a.target.ID)
+	if err != nil {
+		return fmt.Errorf("failed to count live web servers: %v", err)
+	}
+
+	subdomainCount, liveCount := len(subdomains.Subdomains), live.Count
+	_, err = a.c.UpdateAutoScanSessionFinalStats(ctx, a.sessionID, client.AutoScanFinalStatsRequest{
+		FinalConsolidatedSubdomains: &subdomainCount,
+		FinalLiveWebServers:         &liveCount,
+		ScopeTargetID:               a.target.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store final auto scan stats: %v", err)
+	}
+	log.Printf("[auto-scan] Completed with %d consolidated subdomains and %d live web servers", subdomainCount, liveCount)
+	return nil
+}
@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"ars0n-framework-v2-server/client"
+)
+
+func runExport(ctx context.Context, c *client.Client, args []string) error {
+	fs := newFlagSet("export")
+	targetRef := fs.String("target", "", "scope target ID or value")
+	format := fs.String("format", "json", "output format: json, csv or text")
+	output := fs.String("o", "", "write to this file instead of stdout")
+	refresh := fs.Bool("refresh", false, "consolidate subdomains or attack surface assets before exporting them")
+	assetType := fs.String("type", "", "only export attack surface assets of this type")
+	sources := fs.String("sources", "", "comma separated finding sources: nuclei, tls, services, takeover, dns, email, js")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected one of subdomains, live-urls, assets or findings")
+	}
+	switch *format {
+	case "json", "csv", "text":
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	target, err := resolveTarget(ctx, c, *targetRef)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch positional[0] {
+	case "subdomains":
+		var subdomains *client.ConsolidatedSubdomainsResponse
+		if *refresh {
+			subdomains, err = c.HandleConsolidateSubdomains(ctx, target.ID)
+		} else {
+			subdomains, err = c.GetConsolidatedSubdomains(ctx, target.ID)
+		}
+		if err != nil {
+			return err
+		}
+		return exportSubdomains(out, subdomains.Subdomains, *format)
+
+	case "live-urls":
+		urls, err := c.GetTargetURLsForScopeTarget(ctx, target.ID)
+		if err != nil {
+			return err
+		}
+		return exportLiveURLs(out, urls, *format)
+
+	case "assets":
+		if *refresh {
+			if _, err := c.ConsolidateAttackSurface(ctx, target.ID); err != nil {
+				return err
+			}
+		}
+		resp, err := c.GetAttackSurfaceAssets(ctx, target.ID)
+		if err != nil {
+			return err
+		}
+		assets := resp.Assets
+		if *assetType != "" {
+			assets = assets[:0]
+			for _, asset := range resp.Assets {
+				if asset.AssetType == *assetType {
+					assets = append(assets, asset)
+				}
+			}
+		}
+		return exportAssets(out, assets, *format)
+
+	case "findings":
+		findings, err := c.GetScopeTargetFindings(ctx, target.ID, &client.GetScopeTargetFindingsParams{Sources: *sources})
+		if err != nil {
+			return err
+		}
+		return exportFindings(out, findings, *format)
+
+	default:
+		return fmt.Errorf("unknown export %q", positional[0])
+	}
+}
+
+func exportSubdomains(out io.Writer, subdomains []string, format string) error {
+	switch format {
+	case "json":
+		return writeJSON(out, subdomains)
+	case "csv":
+		rows := make([][]string, 0, len(subdomains))
+		for _, subdomain := range subdomains {
+			rows = append(rows, []string{subdomain})
+		}
+		return writeCSV(out, []string{"subdomain"}, rows)
+	default:
+		return writeLines(out, subdomains)
+	}
+}
+
+func exportLiveURLs(out io.Writer, urls []client.TargetURL, format string) error {
+	switch format {
+	case "json":
+		return writeJSON(out, urls)
+	case "csv":
+		rows := make([][]string, 0, len(urls))
+		for _, u := range urls {
+			rows = append(rows, []string{
+				u.URL, strconv.Itoa(u.StatusCode), u.Title, u.WebServer, strconv.Itoa(u.ContentLength),
+				strings.Join(u.Technologies, ";"), strconv.Itoa(u.ROIScore),
+			})
+		}
+		return writeCSV(out, []string{"url", "status_code", "title", "web_server", "content_length", "technologies", "roi_score"}, rows)
+	default:
+		lines := make([]string, 0, len(urls))
+		for _, u := range urls {
+			lines = append(lines, u.URL)
+		}
+		return writeLines(out, lines)
+	}
+}
+
+func exportAssets(out io.Writer, assets []client.AttackSurfaceAsset, format string) error {
+	switch format {
+	case "json":
+		return writeJSON(out, assets)
+	case "csv":
+		rows := make([][]string, 0, len(assets))
+		for _, a := range assets {
+			rows = append(rows, []string{
+				a.ID, a.AssetType, a.AssetIdentifier, deref(a.AssetSubtype), deref(a.IPAddress), derefInt(a.Port),
+				deref(a.URL), derefInt(a.StatusCode), deref(a.Title), deref(a.CloudProvider), strings.Join(a.ResolvedIPS, ";"),
+			})
+		}
+		return writeCSV(out, []string{
+			"id", "asset_type", "asset_identifier", "asset_subtype", "ip_address", "port",
+			"url", "status_code", "title", "cloud_provider", "resolved_ips",
+		}, rows)
+	default:
+		lines := make([]string, 0, len(assets))
+		for _, a := range assets {
+			lines = append(lines, a.AssetType+"\t"+a.AssetIdentifier)
+		}
+		return writeLines(out, lines)
+	}
+}
+
+func exportFindings(out io.Writer, findings []client.ExportableFinding, format string) error {
+	switch format {
+	case "json":
+		return writeJSON(out, findings)
+	case "csv":
+		rows := make([][]string, 0, len(findings))
+		for _, f := range findings {
+			rows = append(rows, []string{f.Source, f.Severity, f.Name, f.TemplateID, f.Host, f.MatchedAt, triageStatus(f.Triage), f.Fingerprint})
+		}
+		return writeCSV(out, []string{"source", "severity", "name", "template_id", "host", "matched_at", "triage", "fingerprint"}, rows)
+	default:
+		lines := make([]string, 0, len(findings))
+		for _, f := range findings {
+			lines = append(lines, fmt.Sprintf("[%s] [%s] %s %s", f.Source, f.Severity, f.Name, f.MatchedAt))
+		}
+		return writeLines(out, lines)
+	}
+}
+
+// triageStatus summarizes the DefectDojo triage flags of a finding
+func triageStatus(t *client.FindingTriage) string {
+	if t == nil {
+		return ""
+	}
+	var flags []string
+	for _, flag := range []struct {
+		set  bool
+		name string
+	}{
+		{t.Active, "active"}, {t.Verified, "verified"}, {t.FalseP, "false_positive"}, {t.Duplicate, "duplicate"},
+		{t.OutOfScope, "out_of_scope"}, {t.RiskAccepted, "risk_accepted"}, {t.IsMitigated, "mitigated"},
+	} {
+		if flag.set {
+			flags = append(flags, flag.name)
+		}
+	}
+	return strings.Join(flags, ";")
+}
+
+func writeJSON(out io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func writeCSV(out io.Writer, header []string, rows [][]string) error {
+	w := csv.NewWriter(out)
+	w.Write(header)
+	w.WriteAll(rows)
+	return w.Error()
+}
+
+func writeLines(out io.Writer, lines []string) error {
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(out, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func derefInt(i *int) string {
+	if i == nil {
+		return ""
+	}
+	return strconv.Itoa(*i)
+}
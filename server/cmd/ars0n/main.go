@@ -0,0 +1,123 @@
+// Command ars0n drives a running framework instance from the command line through its REST API.
+//
+//	ars0n [-api URL] <command> [flags]
+//
+// It can manage scope targets, start single scans or a full wildcard auto scan, wait for scans
+// to finish and export consolidated subdomains, live URLs, attack surface assets and findings
+// as JSON, CSV or plain text.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"ars0n-framework-v2-server/client"
+)
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, c *client.Client, args []string) error
+}
+
+// commands is filled in init since the commands look themselves up for their usage text
+var commands []command
+
+func init() {
+	commands = []command{
+		{"targets", "targets <list|add|delete|activate> [flags]", "Manage scope targets", runTargets},
+		{"scan", "scan -target <target> [-wait] <tool>", "Start a single scan", runScan},
+		{"wait", "wait <tool> <scan_id>", "Wait for a scan to finish", runWait},
+		{"autoscan", "autoscan -target <target> [flags]", "Run the wildcard auto scan workflow", runAutoScan},
+		{"export", "export -target <target> [-format json|csv|text] <subdomains|live-urls|assets|findings>", "Export results", runExport},
+		{"tools", "tools", "List the scan tools known to the scan and wait commands", runTools},
+	}
+}
+
+func main() {
+	log.SetFlags(log.Ltime)
+	log.SetPrefix("")
+
+	defaultAPI := os.Getenv("ARS0N_API_URL")
+	if defaultAPI == "" {
+		defaultAPI = client.DefaultBaseURL
+	}
+	apiURL := flag.String("api", defaultAPI, "base URL of the framework API (env ARS0N_API_URL)")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name, args := flag.Arg(0), flag.Args()[1:]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		err := cmd.run(ctx, client.New(*apiURL), args)
+		stop()
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ars0n %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "ars0n: unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: ars0n [-api URL] <command> [flags]\n\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(out, "\nGlobal flags:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(out, "\nRun 'ars0n <command> -h' for the flags of a command.\n")
+}
+
+// newFlagSet returns a flag set for a subcommand that prints the command's usage line on errors
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		for _, cmd := range commands {
+			if cmd.name == name {
+				fmt.Fprintf(fs.Output(), "Usage: ars0n %s\n\n%s.\n\nFlags:\n", cmd.usage, cmd.summary)
+			}
+		}
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags parses flags that may appear before, between or after the positional arguments and
+// returns the positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"ars0n-framework-v2-server/client"
+)
+
+// scanInput is what a tool is started against
+type scanInput int
+
+const (
+	inputDomain scanInput = iota
+	inputCompany
+	inputScopeTarget
+)
+
+func (i scanInput) String() string {
+	switch i {
+	case inputDomain:
+		return "domain"
+	case inputCompany:
+		return "company"
+	default:
+		return "scope target"
+	}
+}
+
+type scanTool struct {
+	input  scanInput
+	start  func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error)
+	status func(ctx context.Context, scanID string) (string, error)
+}
+
+func domainTool(run func(context.Context, client.DomainScanRequest) (*client.ScanStartedResponse, error)) func(context.Context, client.ResponsePayload, string) (*client.ScanStartedResponse, error) {
+	return func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error) {
+		return run(ctx, client.DomainScanRequest{FQDN: targetDomain(target), AutoScanSessionID: sessionID})
+	}
+}
+
+func companyTool(run func(context.Context, client.CompanyScanRequest) (*client.ScanStartedResponse, error)) func(context.Context, client.ResponsePayload, string) (*client.ScanStartedResponse, error) {
+	return func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error) {
+		return run(ctx, client.CompanyScanRequest{CompanyName: target.ScopeTarget, AutoScanSessionID: sessionID})
+	}
+}
+
+func scopeTargetTool(run func(context.Context, client.ScopeTargetScanRequest) (*client.ScanStartedResponse, error)) func(context.Context, client.ResponsePayload, string) (*client.ScanStartedResponse, error) {
+	return func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error) {
+		return run(ctx, client.ScopeTargetScanRequest{ScopeTargetID: target.ID, AutoScanSessionID: sessionID})
+	}
+}
+
+// statusOf adapts a typed status getter to return just the scan status
+func statusOf[T any](get func(context.Context, string) (*T, error), status func(*T) string) func(context.Context, string) (string, error) {
+	return func(ctx context.Context, scanID string) (string, error) {
+		scan, err := get(ctx, scanID)
+		if err != nil {
+			return "", err
+		}
+		return status(scan), nil
+	}
+}
+
+// scanTools lists the tools that can be started with the scan command, keyed by the names the
+// auto scan uses for its steps
+func scanTools(c *client.Client) map[string]scanTool {
+	return map[string]scanTool{
+		"amass":       {inputDomain, domainTool(c.RunAmassScan), statusOf(c.GetAmassScanStatus, func(s *client.AmassScanStatus) string { return s.Status })},
+		"sublist3r":   {inputDomain, domainTool(c.RunSublist3rScan), statusOf(c.GetSublist3rScanStatus, func(s *client.Sublist3rScanStatus) string { return s.Status })},
+		"assetfinder": {inputDomain, domainTool(c.RunAssetfinderScan), statusOf(c.GetAssetfinderScanStatus, func(s *client.AssetfinderScanStatus) string { return s.Status })},
+		"gau": {
+			inputDomain,
+			func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error) {
+				return c.RunGauScan(ctx, client.GauScanRequest{FQDN: targetDomain(target), AutoScanSessionID: sessionID})
+			},
+			statusOf(c.GetGauScanStatus, func(s *client.GauScanStatus) string { return s.Status }),
+		},
+		"ctl":           {inputDomain, domainTool(c.RunCTLScan), statusOf(c.GetCTLScanStatus, func(s *client.CTLScanStatus) string { return s.Status })},
+		"subfinder":     {inputDomain, domainTool(c.RunSubfinderScan), statusOf(c.GetSubfinderScanStatus, func(s *client.SubfinderScanStatus) string { return s.Status })},
+		"shuffledns":    {inputDomain, domainTool(c.RunShuffleDNSScan), statusOf(c.GetShuffleDNSScanStatus, func(s *client.ShuffleDNSScanStatus) string { return s.Status })},
+		"cewl":          {inputDomain, domainTool(c.RunCeWLScan), statusOf(c.GetCeWLScanStatus, func(s *client.CeWLScanStatus) string { return s.Status })},
+		"gospider":      {inputDomain, domainTool(c.RunGoSpiderScan), statusOf(c.GetGoSpiderScanStatus, func(s *client.GoSpiderScanStatus) string { return s.Status })},
+		"subdomainizer": {inputDomain, domainTool(c.RunSubdomainizerScan), statusOf(c.GetSubdomainizerScanStatus, func(s *client.SubdomainizerScanStatus) string { return s.Status })},
+		"httpx":         {inputDomain, domainTool(c.RunHttpxScan), statusOf(c.GetHttpxScanStatus, func(s *client.HttpxScanStatus) string { return s.Status })},
+
+		"recursive_enum": {
+			inputDomain,
+			func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error) {
+				return c.RunRecursiveEnumerationScan(ctx, client.RecursiveEnumerationScanRequest{FQDN: targetDomain(target), AutoScanSessionID: sessionID})
+			},
+			statusOf(c.GetRecursiveEnumerationScanStatus, func(s *client.RecursiveEnumerationScan) string { return s.Status }),
+		},
+		"permutations": {
+			inputDomain,
+			func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error) {
+				return c.RunPermutationScan(ctx, client.PermutationScanRequest{FQDN: targetDomain(target), AutoScanSessionID: sessionID})
+			},
+			statusOf(c.GetPermutationScanStatus, func(s *client.PermutationScanStatus) string { return s.Status }),
+		},
+		"nuclei-screenshot": {
+			inputScopeTarget,
+			func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error) {
+				return c.RunNucleiScreenshotScanForScopeTarget(ctx, target.ID, client.AutoScanSessionRequest{AutoScanSessionID: sessionID})
+			},
+			statusOf(c.GetNucleiScreenshotScanStatus, func(s *client.NucleiScreenshotStatus) string { return s.Status }),
+		},
+		"metadata":    {inputScopeTarget, scopeTargetTool(c.RunMetaDataScan), statusOf(c.GetMetaDataScanStatus, func(s *client.MetaDataStatus) string { return s.Status })},
+		"investigate": {inputScopeTarget, scopeTargetTool(c.RunInvestigateScan), statusOf(c.GetInvestigateScanStatus, func(s *client.InvestigateStatus) string { return s.Status })},
+		"ip-port": {
+			inputScopeTarget,
+			func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error) {
+				return c.RunIPPortScan(ctx, client.IPPortScanRequest{ScopeTargetID: target.ID, AutoScanSessionID: sessionID})
+			},
+			statusOf(c.GetIPPortScanStatus, func(s *client.IPPortScan) string { return s.Status }),
+		},
+		"dns-audit": {
+			inputScopeTarget,
+			func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error) {
+				return c.RunDNSAuditScan(ctx, client.DNSAuditScanRequest{ScopeTargetID: target.ID})
+			},
+			statusOf(c.GetDNSAuditScanStatus, func(s *client.DNSAuditScan) string { return s.Status }),
+		},
+		"email-security": {
+			inputScopeTarget,
+			func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error) {
+				return c.RunEmailSecurityScan(ctx, client.EmailSecurityScanRequest{ScopeTargetID: target.ID})
+			},
+			statusOf(c.GetEmailSecurityScanStatus, func(s *client.EmailSecurityScan) string { return s.Status }),
+		},
+		"parameter-mining": {inputScopeTarget, scopeTargetTool(c.RunParameterMiningScan), statusOf(c.GetParameterMiningScanStatus, func(s *client.ParameterMiningScan) string { return s.Status })},
+		"js-analysis":      {inputScopeTarget, scopeTargetTool(c.RunJSAnalysisScan), statusOf(c.GetJSAnalysisScanStatus, func(s *client.JSAnalysisScan) string { return s.Status })},
+		"content-discovery": {
+			inputScopeTarget,
+			func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error) {
+				return c.RunContentDiscoveryScan(ctx, client.ContentDiscoveryScanRequest{ScopeTargetID: target.ID})
+			},
+			statusOf(c.GetContentDiscoveryScanStatus, func(s *client.ContentDiscoveryScan) string { return s.Status }),
+		},
+		"web-fingerprint": {inputScopeTarget, scopeTargetTool(c.RunWebFingerprintScan), statusOf(c.GetWebFingerprintScanStatus, func(s *client.WebFingerprintScan) string { return s.Status })},
+		"screenshot": {
+			inputScopeTarget,
+			func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error) {
+				return c.RunScreenshotScan(ctx, client.ScreenshotScanRequest{ScopeTargetID: target.ID})
+			},
+			statusOf(c.GetScreenshotScanStatus, func(s *client.ScreenshotScan) string { return s.Status }),
+		},
+		"security-headers": {
+			inputScopeTarget,
+			func(ctx context.Context, target client.ResponsePayload, sessionID string) (*client.ScanStartedResponse, error) {
+				return c.RunSecurityHeaderScan(ctx, client.SecurityHeaderScanRequest{ScopeTargetID: target.ID})
+			},
+			statusOf(c.GetSecurityHeaderScanStatus, func(s *client.SecurityHeaderScan) string { return s.Status }),
+		},
+
+		"amass-intel":    {inputCompany, companyTool(c.RunAmassIntelScan), statusOf(c.GetAmassIntelScanStatus, func(s *client.AmassIntelScanStatus) string { return s.Status })},
+		"ctl-company":    {inputCompany, companyTool(c.RunCTLCompanyScan), statusOf(c.GetCTLCompanyScanStatus, func(s *client.CTLCompanyScanStatus) string { return s.Status })},
+		"cloud-enum":     {inputCompany, companyTool(c.RunCloudEnumScan), statusOf(c.GetCloudEnumScanStatus, func(s *client.CloudEnumScanStatus) string { return s.Status })},
+		"metabigor":      {inputCompany, companyTool(c.RunMetabigorCompanyScan), statusOf(c.GetMetabigorCompanyScanStatus, func(s *client.MetabigorCompanyScanStatus) string { return s.Status })},
+		"securitytrails": {inputCompany, companyTool(c.RunSecurityTrailsCompanyScan), statusOf(c.GetSecurityTrailsCompanyScanStatus, func(s *client.SecurityTrailsCompanyScanStatus) string { return s.Status })},
+		"censys":         {inputCompany, companyTool(c.RunCensysCompanyScan), statusOf(c.GetCensysCompanyScanStatus, func(s *client.CensysCompanyScanStatus) string { return s.Status })},
+		"shodan":         {inputCompany, companyTool(c.RunShodanCompanyScan), statusOf(c.GetShodanCompanyScanStatus, func(s *client.ShodanCompanyScanStatus) string { return s.Status })},
+		"github-recon":   {inputCompany, companyTool(c.RunGitHubReconScan), statusOf(c.GetGitHubReconScanStatus, func(s *client.GitHubReconScanStatus) string { return s.Status })},
+	}
+}
+
+// targetDomain is the root domain of a wildcard target, or the scope target itself otherwise
+func targetDomain(target client.ResponsePayload) string {
+	return strings.TrimPrefix(target.ScopeTarget, "*.")
+}
+
+func lookupTool(c *client.Client, name string) (scanTool, error) {
+	tool, ok := scanTools(c)[strings.ToLower(name)]
+	if !ok {
+		return scanTool{}, fmt.Errorf("unknown tool %q, run 'ars0n tools' for the list", name)
+	}
+	return tool, nil
+}
+
+func runScan(ctx context.Context, c *client.Client, args []string) error {
+	fs := newFlagSet("scan")
+	targetRef := fs.String("target", "", "scope target ID or value")
+	wait := fs.Bool("wait", false, "wait for the scan to finish")
+	interval := fs.Duration("interval", 10*time.Second, "status poll interval when waiting")
+	timeout := fs.Duration("timeout", 0, "give up waiting after this long (0 waits forever)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected exactly one tool")
+	}
+
+	tool, err := lookupTool(c, positional[0])
+	if err != nil {
+		return err
+	}
+	target, err := resolveTarget(ctx, c, *targetRef)
+	if err != nil {
+		return err
+	}
+
+	started, err := tool.start(ctx, target, "")
+	if err != nil {
+		return fmt.Errorf("failed to start %s scan: %v", positional[0], err)
+	}
+	fmt.Println(started.ScanID)
+	if !*wait {
+		return nil
+	}
+
+	status, err := waitForScan(ctx, positional[0], tool, started.ScanID, *interval, *timeout)
+	if err != nil {
+		return err
+	}
+	if scanFailed(status) {
+		return fmt.Errorf("%s scan %s finished with status %q", positional[0], started.ScanID, status)
+	}
+	return nil
+}
+
+func runWait(ctx context.Context, c *client.Client, args []string) error {
+	fs := newFlagSet("wait")
+	interval := fs.Duration("interval", 10*time.Second, "status poll interval")
+	timeout := fs.Duration("timeout", 0, "give up after this long (0 waits forever)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 2 {
+		fs.Usage()
+		return fmt.Errorf("expected a tool and a scan ID")
+	}
+
+	tool, err := lookupTool(c, positional[0])
+	if err != nil {
+		return err
+	}
+	status, err := waitForScan(ctx, positional[0], tool, positional[1], *interval, *timeout)
+	if err != nil {
+		return err
+	}
+	fmt.Println(status)
+	if scanFailed(status) {
+		return fmt.Errorf("scan finished with status %q", status)
+	}
+	return nil
+}
+
+func runTools(ctx context.Context, c *client.Client, args []string) error {
+	tools := scanTools(c)
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TOOL\tINPUT")
+	for _, name := range names {
+		fmt.Fprintf(tw, "%s\t%s\n", name, tools[name].input)
+	}
+	return tw.Flush()
+}
+
+// scanDone reports whether a scan status is terminal. Running scans report "pending",
+// "processing" or "running" depending on the tool.
+func scanDone(status string) bool {
+	switch strings.ToLower(status) {
+	case "success", "completed", "failed", "error", "cancelled":
+		return true
+	}
+	return false
+}
+
+func scanFailed(status string) bool {
+	switch strings.ToLower(status) {
+	case "failed", "error", "cancelled":
+		return true
+	}
+	return false
+}
+
+// waitForScan polls a scan until it reaches a terminal status and returns that status
+func waitForScan(ctx context.Context, name string, tool scanTool, scanID string, interval, timeout time.Duration) (string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastStatus := ""
+	for {
+		status, err := tool.status(ctx, scanID)
+		if err != nil {
+			// The scan row may not be committed yet right after starting, so keep polling
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			log.Printf("[%s] Failed to get status of scan %s: %v", name, scanID, err)
+		} else {
+			if status != lastStatus {
+				log.Printf("[%s] Scan %s is %s", name, scanID, status)
+				lastStatus = status
+			}
+			if scanDone(status) {
+				return status, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("stopped waiting for %s scan %s: %v", name, scanID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
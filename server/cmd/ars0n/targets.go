@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"ars0n-framework-v2-server/client"
+)
+
+// scopeTargetTypes maps the accepted -type values to the type names stored by the web UI
+var scopeTargetTypes = map[string]string{
+	"wildcard": "Wildcard",
+	"company":  "Company",
+	"url":      "URL",
+}
+
+func runTargets(ctx context.Context, c *client.Client, args []string) error {
+	fs := newFlagSet("targets")
+	targetType := fs.String("type", "wildcard", "scope target type for add: wildcard, company or url")
+	mode := fs.String("mode", "Passive", "scope target mode for add")
+	activate := fs.Bool("activate", false, "make the new target the active one after add")
+	format := fs.String("format", "text", "output format for list: json, csv or text")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		fs.Usage()
+		return fmt.Errorf("missing targets subcommand")
+	}
+
+	switch positional[0] {
+	case "list":
+		targets, err := c.ReadScopeTarget(ctx)
+		if err != nil {
+			return err
+		}
+		return writeTargets(targets, *format)
+
+	case "add":
+		if len(positional) != 2 {
+			return fmt.Errorf("usage: ars0n targets add [-type wildcard|company|url] <scope_target>")
+		}
+		typeName, ok := scopeTargetTypes[strings.ToLower(*targetType)]
+		if !ok {
+			return fmt.Errorf("unknown scope target type %q", *targetType)
+		}
+		value := positional[1]
+		if typeName == "Wildcard" && !strings.HasPrefix(value, "*.") {
+			value = "*." + value
+		}
+		if _, err := c.CreateScopeTarget(ctx, client.RequestPayload{Type: typeName, Mode: *mode, ScopeTarget: value}); err != nil {
+			return err
+		}
+		target, err := resolveTarget(ctx, c, value)
+		if err != nil {
+			return err
+		}
+		if *activate {
+			if _, err := c.ActivateScopeTarget(ctx, target.ID); err != nil {
+				return err
+			}
+		}
+		fmt.Println(target.ID)
+		return nil
+
+	case "delete", "activate":
+		if len(positional) != 2 {
+			return fmt.Errorf("usage: ars0n targets %s <target>", positional[0])
+		}
+		target, err := resolveTarget(ctx, c, positional[1])
+		if err != nil {
+			return err
+		}
+		var resp *client.MessageResponse
+		if positional[0] == "delete" {
+			resp, err = c.DeleteScopeTarget(ctx, target.ID)
+		} else {
+			resp, err = c.ActivateScopeTarget(ctx, target.ID)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, resp.Message)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown targets subcommand %q", positional[0])
+	}
+}
+
+// resolveTarget finds a scope target by ID or by its scope target value. Wildcard targets also
+// match on the bare domain, so "example.com" finds "*.example.com".
+func resolveTarget(ctx context.Context, c *client.Client, ref string) (client.ResponsePayload, error) {
+	if ref == "" {
+		return client.ResponsePayload{}, fmt.Errorf("a -target is required")
+	}
+	targets, err := c.ReadScopeTarget(ctx)
+	if err != nil {
+		return client.ResponsePayload{}, err
+	}
+
+	var matches []client.ResponsePayload
+	for _, target := range targets {
+		if target.ID == ref {
+			return target, nil
+		}
+		if strings.EqualFold(target.ScopeTarget, ref) || strings.EqualFold(strings.TrimPrefix(target.ScopeTarget, "*."), ref) {
+			matches = append(matches, target)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return client.ResponsePayload{}, fmt.Errorf("no scope target matches %q", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		return client.ResponsePayload{}, fmt.Errorf("%q matches %d scope targets, use the target ID instead", ref, len(matches))
+	}
+}
+
+func writeTargets(targets []client.ResponsePayload, format string) error {
+	switch format {
+	case "json":
+		return writeJSON(os.Stdout, targets)
+	case "csv":
+		rows := make([][]string, 0, len(targets))
+		for _, t := range targets {
+			rows = append(rows, []string{t.ID, t.Type, t.ScopeTarget, fmt.Sprint(t.Active)})
+		}
+		return writeCSV(os.Stdout, []string{"id", "type", "scope_target", "active"}, rows)
+	case "text":
+		tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tTYPE\tSCOPE TARGET\tACTIVE")
+		for _, t := range targets {
+			active := ""
+			if t.Active {
+				active = "*"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", t.ID, t.Type, t.ScopeTarget, active)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+}
@@ -53,6 +53,9 @@ func createTables() {
 			burp_api_ip TEXT DEFAULT '127.0.0.1',
 			burp_api_port INTEGER DEFAULT 1337,
 			burp_api_key TEXT DEFAULT '',
+			burp_proxy_enabled BOOLEAN DEFAULT false,
+			gau_providers TEXT DEFAULT 'wayback',
+			default_wordlist_seeded BOOLEAN DEFAULT false,
 			created_at TIMESTAMP DEFAULT NOW(),
 			updated_at TIMESTAMP DEFAULT NOW()
 		);`,
@@ -90,8 +93,10 @@ func createTables() {
 			ctl BOOLEAN DEFAULT TRUE,
 			subfinder BOOLEAN DEFAULT TRUE,
 			consolidate_httpx_round1 BOOLEAN DEFAULT TRUE,
+			recursive_enumeration BOOLEAN DEFAULT FALSE,
 			shuffledns BOOLEAN DEFAULT TRUE,
 			cewl BOOLEAN DEFAULT TRUE,
+			permutations BOOLEAN DEFAULT TRUE,
 			consolidate_httpx_round2 BOOLEAN DEFAULT TRUE,
 			gospider BOOLEAN DEFAULT TRUE,
 			subdomainizer BOOLEAN DEFAULT TRUE,
@@ -100,6 +105,9 @@ func createTables() {
 			metadata BOOLEAN DEFAULT TRUE,
 			max_consolidated_subdomains INTEGER DEFAULT 2500,
 			max_live_web_servers INTEGER DEFAULT 500,
+			max_permutation_candidates INTEGER DEFAULT 50000,
+			recursive_max_depth INTEGER DEFAULT 2,
+			recursive_max_zones INTEGER DEFAULT 10,
 			created_at TIMESTAMP DEFAULT NOW(),
 			updated_at TIMESTAMP DEFAULT NOW()
 		);`,
@@ -294,6 +302,39 @@ func createTables() {
 			auto_scan_session_id UUID REFERENCES auto_scan_sessions(id) ON DELETE SET NULL
 		);`,
 
+		`CREATE TABLE IF NOT EXISTS permutation_scans (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL UNIQUE,
+			domain TEXT NOT NULL,
+			status VARCHAR(50) NOT NULL,
+			result TEXT,
+			error TEXT,
+			command TEXT,
+			execution_time TEXT,
+			candidate_count INT DEFAULT 0,
+			truncated BOOLEAN DEFAULT false,
+			created_at TIMESTAMP DEFAULT NOW(),
+			scope_target_id UUID REFERENCES scope_targets(id) ON DELETE CASCADE,
+			auto_scan_session_id UUID REFERENCES auto_scan_sessions(id) ON DELETE SET NULL
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS recursive_enumeration_scans (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL UNIQUE,
+			domain TEXT NOT NULL,
+			status VARCHAR(50) NOT NULL,
+			result TEXT,
+			error TEXT,
+			execution_time TEXT,
+			max_depth INT DEFAULT 2,
+			max_zones INT DEFAULT 10,
+			zones JSONB DEFAULT '[]'::jsonb,
+			new_count INT DEFAULT 0,
+			created_at TIMESTAMP DEFAULT NOW(),
+			scope_target_id UUID REFERENCES scope_targets(id) ON DELETE CASCADE,
+			auto_scan_session_id UUID REFERENCES auto_scan_sessions(id) ON DELETE SET NULL
+		);`,
+
 		`CREATE TABLE IF NOT EXISTS shufflednscustom_scans (
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 			scan_id UUID NOT NULL UNIQUE,
@@ -561,7 +602,14 @@ func createTables() {
 			command TEXT,
 			execution_time TEXT,
 			created_at TIMESTAMP DEFAULT NOW(),
-			auto_scan_session_id UUID REFERENCES auto_scan_sessions(id) ON DELETE SET NULL
+			auto_scan_session_id UUID REFERENCES auto_scan_sessions(id) ON DELETE SET NULL,
+			port_profile TEXT DEFAULT 'web',
+			custom_ports TEXT,
+			services_found INT DEFAULT 0,
+			total_ips_probed INT DEFAULT 0,
+			truncated_ranges INT DEFAULT 0,
+			ips_skipped BIGINT DEFAULT 0,
+			scan_config JSONB
 		);`,
 
 		`CREATE TABLE IF NOT EXISTS discovered_live_ips (
@@ -596,6 +644,23 @@ func createTables() {
 			UNIQUE(scan_id, ip_address, port, protocol)
 		);`,
 
+		`CREATE TABLE IF NOT EXISTS discovered_services (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID REFERENCES ip_port_scans(scan_id) ON DELETE CASCADE,
+			ip_address INET NOT NULL,
+			hostname TEXT,
+			port INT NOT NULL,
+			transport VARCHAR(10) NOT NULL DEFAULT 'tcp',
+			service VARCHAR(50) NOT NULL,
+			product TEXT,
+			version TEXT,
+			banner TEXT,
+			is_tls BOOLEAN DEFAULT false,
+			detection_method VARCHAR(20),
+			discovered_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scan_id, ip_address, port, transport)
+		);`,
+
 		`CREATE TABLE IF NOT EXISTS target_urls (
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 			url TEXT NOT NULL,
@@ -695,10 +760,411 @@ func createTables() {
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 			scope_target_id UUID REFERENCES scope_targets(id) ON DELETE CASCADE,
 			subdomain TEXT NOT NULL,
+			is_wildcard BOOLEAN DEFAULT false,
+			wildcard_zone TEXT,
 			created_at TIMESTAMP DEFAULT NOW(),
 			UNIQUE(scope_target_id, subdomain)
 		);`,
 
+		`CREATE TABLE IF NOT EXISTS subdomain_takeover_scans (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL UNIQUE,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			status VARCHAR(50) NOT NULL,
+			candidates_checked INT DEFAULT 0,
+			vulnerable_count INT DEFAULT 0,
+			error TEXT,
+			execution_time TEXT,
+			created_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS subdomain_takeover_findings (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			fqdn TEXT NOT NULL,
+			cname TEXT NOT NULL,
+			service TEXT NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			severity VARCHAR(20) NOT NULL,
+			nxdomain BOOLEAN DEFAULT false,
+			http_status INT,
+			matched_signature TEXT,
+			last_checked TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scope_target_id, fqdn, cname)
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS dns_audit_scans (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL UNIQUE,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			status VARCHAR(50) NOT NULL,
+			domains JSONB DEFAULT '[]'::jsonb,
+			result TEXT,
+			findings_count INT DEFAULT 0,
+			discovered_count INT DEFAULT 0,
+			new_count INT DEFAULT 0,
+			error TEXT,
+			execution_time TEXT,
+			created_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS dns_audit_findings (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			domain TEXT NOT NULL,
+			nameserver TEXT NOT NULL DEFAULT '',
+			nameserver_ip TEXT NOT NULL DEFAULT '',
+			check_type VARCHAR(50) NOT NULL,
+			severity VARCHAR(20) NOT NULL,
+			title TEXT NOT NULL,
+			details TEXT,
+			evidence JSONB,
+			created_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scope_target_id, domain, nameserver, nameserver_ip, check_type)
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS email_security_scans (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL UNIQUE,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			status VARCHAR(50) NOT NULL,
+			domains_checked INT DEFAULT 0,
+			findings_count INT DEFAULT 0,
+			error TEXT,
+			execution_time TEXT,
+			created_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS email_security_results (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			domain TEXT NOT NULL,
+			grade VARCHAR(2) NOT NULL,
+			score INT NOT NULL,
+			mx_records TEXT[],
+			spf_record TEXT,
+			spf_lookups INT DEFAULT 0,
+			dmarc_record TEXT,
+			dmarc_policy VARCHAR(20),
+			dkim_selectors JSONB DEFAULT '[]'::jsonb,
+			mta_sts_mode VARCHAR(20),
+			tls_rpt_record TEXT,
+			findings JSONB DEFAULT '[]'::jsonb,
+			checked_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scope_target_id, domain)
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS urls (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			host TEXT NOT NULL,
+			scheme VARCHAR(10) NOT NULL,
+			pattern TEXT NOT NULL,
+			example_url TEXT NOT NULL,
+			extension VARCHAR(20),
+			params TEXT[] DEFAULT '{}',
+			param_values JSONB DEFAULT '{}',
+			categories TEXT[] DEFAULT '{}',
+			hit_count INT DEFAULT 1,
+			source VARCHAR(50) DEFAULT 'gau',
+			scan_id UUID,
+			first_seen TIMESTAMP DEFAULT NOW(),
+			last_seen TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scope_target_id, host, pattern)
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS parameter_mining_scans (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL UNIQUE,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			status VARCHAR(50) NOT NULL,
+			urls_processed INT DEFAULT 0,
+			parameters_count INT DEFAULT 0,
+			tagged_count INT DEFAULT 0,
+			error TEXT,
+			execution_time TEXT,
+			created_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS url_parameters (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			host TEXT NOT NULL,
+			name TEXT NOT NULL,
+			param_values TEXT[] DEFAULT '{}',
+			source_urls TEXT[] DEFAULT '{}',
+			sources TEXT[] DEFAULT '{}',
+			tags TEXT[] DEFAULT '{}',
+			occurrences INT DEFAULT 0,
+			created_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scope_target_id, host, name)
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS js_analysis_scans (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL UNIQUE,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			status VARCHAR(50) NOT NULL,
+			files_analyzed INT DEFAULT 0,
+			endpoints_count INT DEFAULT 0,
+			secrets_count INT DEFAULT 0,
+			source_maps_count INT DEFAULT 0,
+			changed_count INT DEFAULT 0,
+			error TEXT,
+			execution_time TEXT,
+			created_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS js_files (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			url TEXT NOT NULL,
+			page_url TEXT,
+			sha256 VARCHAR(64) NOT NULL,
+			previous_sha256 VARCHAR(64),
+			size INT DEFAULT 0,
+			endpoints TEXT[] DEFAULT '{}',
+			source_map_url TEXT,
+			source_files INT DEFAULT 0,
+			secrets_count INT DEFAULT 0,
+			change_count INT DEFAULT 0,
+			scan_id UUID,
+			first_seen TIMESTAMP DEFAULT NOW(),
+			last_seen TIMESTAMP DEFAULT NOW(),
+			last_changed TIMESTAMP,
+			UNIQUE(scope_target_id, url)
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS js_secrets (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			js_url TEXT NOT NULL,
+			source_path TEXT NOT NULL DEFAULT '',
+			rule VARCHAR(100) NOT NULL,
+			severity VARCHAR(20) NOT NULL,
+			match TEXT NOT NULL,
+			line INT DEFAULT 0,
+			context TEXT,
+			created_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scope_target_id, js_url, source_path, rule, match)
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS js_source_files (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			js_url TEXT NOT NULL,
+			path TEXT NOT NULL,
+			content TEXT NOT NULL,
+			size INT DEFAULT 0,
+			scan_id UUID,
+			created_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scope_target_id, js_url, path)
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS content_wordlists (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			name VARCHAR(255) NOT NULL UNIQUE,
+			tags TEXT[] DEFAULT '{}',
+			word_count INT DEFAULT 0,
+			words TEXT NOT NULL DEFAULT '',
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS content_discovery_scans (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL UNIQUE,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			status VARCHAR(50) NOT NULL,
+			max_depth INT DEFAULT 1,
+			urls_scanned INT DEFAULT 0,
+			requests_sent INT DEFAULT 0,
+			results_count INT DEFAULT 0,
+			error TEXT,
+			execution_time TEXT,
+			created_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS content_discovery_results (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			base_url TEXT NOT NULL,
+			url TEXT NOT NULL,
+			path TEXT NOT NULL,
+			status_code INT NOT NULL,
+			content_length INT DEFAULT 0,
+			words INT DEFAULT 0,
+			lines INT DEFAULT 0,
+			content_type TEXT,
+			redirect_location TEXT,
+			depth INT DEFAULT 0,
+			is_directory BOOLEAN DEFAULT FALSE,
+			wordlist TEXT,
+			created_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scope_target_id, url)
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS technology_fingerprint_rules (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			name VARCHAR(255) NOT NULL UNIQUE,
+			content TEXT NOT NULL,
+			technology_count INT DEFAULT 0,
+			category_count INT DEFAULT 0,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS web_fingerprint_scans (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL UNIQUE,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			status VARCHAR(50) NOT NULL,
+			targets_count INT DEFAULT 0,
+			fingerprinted_count INT DEFAULT 0,
+			error TEXT,
+			execution_time TEXT,
+			created_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS web_fingerprints (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			url TEXT NOT NULL,
+			source VARCHAR(50),
+			title TEXT,
+			favicon_url TEXT,
+			favicon_hash INT,
+			favicon_md5 VARCHAR(32),
+			html_structure_hash VARCHAR(64),
+			html_tag_count INT DEFAULT 0,
+			tls_cert_sha256 VARCHAR(64),
+			tls_cert_subject TEXT,
+			tls_cert_issuer TEXT,
+			tls_cert_not_after TIMESTAMP,
+			tls_server_hello TEXT,
+			ja3s VARCHAR(32),
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scope_target_id, url)
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS screenshots (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			url TEXT NOT NULL,
+			format VARCHAR(10) NOT NULL,
+			width INT DEFAULT 0,
+			height INT DEFAULT 0,
+			size_bytes INT DEFAULT 0,
+			sha256 VARCHAR(64) NOT NULL,
+			phash VARCHAR(16) NOT NULL,
+			blob_key TEXT NOT NULL,
+			thumbnail_key TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scope_target_id, url)
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS screenshot_scans (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL UNIQUE,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			status VARCHAR(50) NOT NULL,
+			options JSONB,
+			urls_count INT DEFAULT 0,
+			captured_count INT DEFAULT 0,
+			failures JSONB DEFAULT '[]'::jsonb,
+			error TEXT,
+			execution_time TEXT,
+			created_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS response_bodies (
+			sha256 VARCHAR(64) PRIMARY KEY,
+			size_bytes INT NOT NULL,
+			compressed_size INT NOT NULL,
+			content BYTEA NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS response_snapshots (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			url TEXT NOT NULL,
+			source VARCHAR(50) NOT NULL,
+			status_code INT,
+			title TEXT,
+			content_length INT DEFAULT 0,
+			headers JSONB,
+			body_sha256 VARCHAR(64) NOT NULL,
+			previous_snapshot_id UUID,
+			body_similarity DOUBLE PRECISION,
+			changes JSONB DEFAULT '[]'::jsonb,
+			meaningful_change BOOLEAN DEFAULT FALSE,
+			times_seen INT DEFAULT 1,
+			first_seen_at TIMESTAMP DEFAULT NOW(),
+			last_seen_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS security_header_scans (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL UNIQUE,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			status VARCHAR(50) NOT NULL,
+			urls_checked INT DEFAULT 0,
+			findings_count INT DEFAULT 0,
+			error TEXT,
+			execution_time TEXT,
+			created_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS security_header_results (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID NOT NULL,
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			url TEXT NOT NULL,
+			final_url TEXT,
+			source VARCHAR(20) NOT NULL,
+			status_code INT,
+			grade VARCHAR(2) NOT NULL,
+			score INT NOT NULL,
+			headers JSONB DEFAULT '{}'::jsonb,
+			cors_probes JSONB DEFAULT '[]'::jsonb,
+			findings JSONB DEFAULT '[]'::jsonb,
+			checked_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scope_target_id, url)
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS wildcard_dns_zones (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scope_target_id UUID REFERENCES scope_targets(id) ON DELETE CASCADE,
+			zone TEXT NOT NULL,
+			is_wildcard BOOLEAN DEFAULT false,
+			wildcard_ips TEXT[],
+			wildcard_cnames TEXT[],
+			filtered_count INT DEFAULT 0,
+			checked_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scope_target_id, zone)
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS dns_resolvers (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			address TEXT NOT NULL UNIQUE,
+			healthy BOOLEAN DEFAULT true,
+			lying BOOLEAN DEFAULT false,
+			avg_latency_ms DOUBLE PRECISION DEFAULT 0,
+			last_error TEXT,
+			last_checked TIMESTAMP,
+			created_at TIMESTAMP DEFAULT NOW()
+		);`,
+
 		`CREATE TABLE IF NOT EXISTS intel_network_ranges (
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 			scan_id UUID NOT NULL,
@@ -822,6 +1288,25 @@ func createTables() {
 			updated_at TIMESTAMP DEFAULT NOW()
 		);`,
 
+		`CREATE TABLE IF NOT EXISTS ip_port_scan_configs (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scope_target_id UUID NOT NULL UNIQUE REFERENCES scope_targets(id) ON DELETE CASCADE,
+			max_ips_per_range INT NOT NULL DEFAULT 254,
+			ip_sampling VARCHAR(20) NOT NULL DEFAULT 'first',
+			max_concurrent_ips INT NOT NULL DEFAULT 50,
+			max_concurrent_ports INT NOT NULL DEFAULT 20,
+			host_probe_timeout_ms INT NOT NULL DEFAULT 1000,
+			port_scan_timeout_ms INT NOT NULL DEFAULT 1000,
+			web_service_timeout_ms INT NOT NULL DEFAULT 5000,
+			banner_timeout_ms INT NOT NULL DEFAULT 3000,
+			port_profile TEXT NOT NULL DEFAULT 'web',
+			custom_ports TEXT,
+			host_discovery VARCHAR(20) NOT NULL DEFAULT 'tcp',
+			discovery_ports TEXT,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW()
+		);`,
+
 		`CREATE TABLE IF NOT EXISTS dnsx_configs (
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 			scope_target_id UUID NOT NULL UNIQUE REFERENCES scope_targets(id) ON DELETE CASCADE,
@@ -968,6 +1453,33 @@ func createTables() {
 			UNIQUE(scope_target_id)
 		);`,
 
+		`CREATE TABLE IF NOT EXISTS report_templates (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			name TEXT NOT NULL,
+			format VARCHAR(20) NOT NULL CHECK (format IN ('markdown', 'html')),
+			preset VARCHAR(50),
+			content TEXT NOT NULL,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW()
+		);`,
+
+		`CREATE TABLE IF NOT EXISTS finding_triage (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
+			fingerprint TEXT NOT NULL,
+			active BOOLEAN DEFAULT TRUE,
+			verified BOOLEAN DEFAULT FALSE,
+			false_p BOOLEAN DEFAULT FALSE,
+			duplicate BOOLEAN DEFAULT FALSE,
+			out_of_scope BOOLEAN DEFAULT FALSE,
+			risk_accepted BOOLEAN DEFAULT FALSE,
+			is_mitigated BOOLEAN DEFAULT FALSE,
+			notes TEXT,
+			source VARCHAR(50),
+			updated_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scope_target_id, fingerprint)
+		);`,
+
 		// Migration: Drop unused Katana Company tables
 		`DROP TABLE IF EXISTS katana_company_cloud_findings CASCADE;`,
 		`DROP TABLE IF EXISTS katana_company_domain_results CASCADE;`,
@@ -975,7 +1487,7 @@ func createTables() {
 		`CREATE TABLE IF NOT EXISTS consolidated_attack_surface_assets (
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
 			scope_target_id UUID NOT NULL REFERENCES scope_targets(id) ON DELETE CASCADE,
-			asset_type VARCHAR(50) NOT NULL CHECK (asset_type IN ('asn', 'network_range', 'ip_address', 'live_web_server', 'cloud_asset', 'fqdn')),
+			asset_type VARCHAR(50) NOT NULL CHECK (asset_type IN ('asn', 'network_range', 'ip_address', 'live_web_server', 'cloud_asset', 'fqdn', 'network_service')),
 			asset_identifier TEXT NOT NULL,
 			asset_subtype VARCHAR(50),
 			
@@ -1014,6 +1526,12 @@ func createTables() {
 			http_response_headers JSONB,
 			findings_json JSONB,
 			
+			-- Network service specific fields
+			service_name TEXT,
+			service_product TEXT,
+			service_version TEXT,
+			service_banner TEXT,
+			
 			-- Cloud asset specific fields
 			cloud_provider VARCHAR(50),
 			cloud_service_type VARCHAR(100),
@@ -1100,6 +1618,66 @@ func createTables() {
 		`ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS burp_api_ip TEXT DEFAULT '127.0.0.1';`,
 		`ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS burp_api_port INTEGER DEFAULT 1337;`,
 		`ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS burp_api_key TEXT DEFAULT '';`,
+		`ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS burp_proxy_enabled BOOLEAN DEFAULT false;`,
+
+		// Migration: Port profiles and service fingerprinting for IP/Port scans
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS port_profile TEXT DEFAULT 'web';`,
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS custom_ports TEXT;`,
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS services_found INT DEFAULT 0;`,
+		`ALTER TABLE consolidated_attack_surface_assets ADD COLUMN IF NOT EXISTS service_name TEXT;`,
+		`ALTER TABLE consolidated_attack_surface_assets ADD COLUMN IF NOT EXISTS service_product TEXT;`,
+		`ALTER TABLE consolidated_attack_surface_assets ADD COLUMN IF NOT EXISTS service_version TEXT;`,
+		`ALTER TABLE consolidated_attack_surface_assets ADD COLUMN IF NOT EXISTS service_banner TEXT;`,
+		`ALTER TABLE consolidated_attack_surface_assets DROP CONSTRAINT IF EXISTS consolidated_attack_surface_assets_asset_type_check;`,
+		`ALTER TABLE consolidated_attack_surface_assets ADD CONSTRAINT consolidated_attack_surface_assets_asset_type_check CHECK (asset_type IN ('asn', 'network_range', 'ip_address', 'live_web_server', 'cloud_asset', 'fqdn', 'network_service'));`,
+
+		// Migration: Saved IP/Port scan profiles and truncation reporting
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS total_ips_probed INT DEFAULT 0;`,
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS truncated_ranges INT DEFAULT 0;`,
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS ips_skipped BIGINT DEFAULT 0;`,
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS scan_config JSONB;`,
+
+		// Migration: Wildcard DNS detection for consolidated subdomains
+		`ALTER TABLE consolidated_subdomains ADD COLUMN IF NOT EXISTS is_wildcard BOOLEAN DEFAULT false;`,
+		`ALTER TABLE consolidated_subdomains ADD COLUMN IF NOT EXISTS wildcard_zone TEXT;`,
+
+		// Migration: Subdomain permutation auto scan step
+		`ALTER TABLE auto_scan_config ADD COLUMN IF NOT EXISTS permutations BOOLEAN DEFAULT TRUE;`,
+		`ALTER TABLE auto_scan_config ADD COLUMN IF NOT EXISTS max_permutation_candidates INTEGER DEFAULT 50000;`,
+
+		// Migration: Recursive enumeration of discovered sub-zones
+		`ALTER TABLE auto_scan_config ADD COLUMN IF NOT EXISTS recursive_enumeration BOOLEAN DEFAULT FALSE;`,
+		`ALTER TABLE auto_scan_config ADD COLUMN IF NOT EXISTS recursive_max_depth INTEGER DEFAULT 2;`,
+		`ALTER TABLE auto_scan_config ADD COLUMN IF NOT EXISTS recursive_max_zones INTEGER DEFAULT 10;`,
+
+		// Migration: Selectable GAU providers
+		`ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS gau_providers TEXT DEFAULT 'wayback';`,
+
+		// Migration: Fingerprint engine technology details
+		`ALTER TABLE target_urls ADD COLUMN IF NOT EXISTS technology_details JSONB;`,
+		`ALTER TABLE live_web_servers ADD COLUMN IF NOT EXISTS technology_details JSONB;`,
+		`ALTER TABLE consolidated_attack_surface_assets ADD COLUMN IF NOT EXISTS technology_details JSONB;`,
+
+		// Migration: Headless Chrome page details for screenshots
+		`ALTER TABLE screenshots ADD COLUMN IF NOT EXISTS final_url TEXT;`,
+		`ALTER TABLE screenshots ADD COLUMN IF NOT EXISTS page_title TEXT;`,
+		`ALTER TABLE screenshots ADD COLUMN IF NOT EXISTS status_code INT;`,
+		`ALTER TABLE screenshots ADD COLUMN IF NOT EXISTS console_errors JSONB;`,
+		`ALTER TABLE screenshots ADD COLUMN IF NOT EXISTS viewport_width INT;`,
+		`ALTER TABLE screenshots ADD COLUMN IF NOT EXISTS viewport_height INT;`,
+		`ALTER TABLE screenshots ADD COLUMN IF NOT EXISTS full_page BOOLEAN DEFAULT FALSE;`,
+
+		// Migration: Parameter values in the URL corpus
+		`ALTER TABLE urls ADD COLUMN IF NOT EXISTS param_values JSONB DEFAULT '{}';`,
+
+		// Migration: Seed the default content discovery wordlist only once
+		`ALTER TABLE user_settings ADD COLUMN IF NOT EXISTS default_wordlist_seeded BOOLEAN DEFAULT false;`,
+		`UPDATE user_settings SET default_wordlist_seeded = true
+		WHERE EXISTS (SELECT 1 FROM content_wordlists WHERE name = 'ffuf-wordlist-5000');`,
+
+		// Migration: URL corpus entries keep "page" only while they have no other category
+		`UPDATE urls SET categories = array_remove(categories, 'page')
+		WHERE 'page' = ANY(categories) AND cardinality(categories) > 1;`,
 
 		// Create indexes for performance
 		`CREATE INDEX IF NOT EXISTS target_urls_url_idx ON target_urls (url);`,
@@ -1107,6 +1685,7 @@ func createTables() {
 		`CREATE INDEX IF NOT EXISTS idx_discovered_live_ips_scan_id ON discovered_live_ips(scan_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_live_web_servers_scan_id ON live_web_servers(scan_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_live_web_servers_ip_port ON live_web_servers(ip_address, port);`,
+		`CREATE INDEX IF NOT EXISTS idx_discovered_services_scan_id ON discovered_services(scan_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_consolidated_attack_surface_assets_scope_target ON consolidated_attack_surface_assets(scope_target_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_consolidated_attack_surface_assets_asset_type ON consolidated_attack_surface_assets(asset_type);`,
 		`CREATE INDEX IF NOT EXISTS idx_consolidated_attack_surface_assets_asset_identifier ON consolidated_attack_surface_assets(asset_identifier);`,
@@ -1121,6 +1700,12 @@ func createTables() {
 		`CREATE INDEX IF NOT EXISTS idx_consolidated_attack_surface_relationships_child ON consolidated_attack_surface_relationships(child_asset_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_consolidated_attack_surface_dns_records_asset_id ON consolidated_attack_surface_dns_records(asset_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_consolidated_attack_surface_metadata_asset_id ON consolidated_attack_surface_metadata(asset_id);`,
+		`CREATE INDEX IF NOT EXISTS idx_urls_categories ON urls USING GIN (categories);`,
+		`CREATE INDEX IF NOT EXISTS idx_content_discovery_results_base_url ON content_discovery_results(scope_target_id, base_url);`,
+		`CREATE INDEX IF NOT EXISTS idx_web_fingerprints_favicon_hash ON web_fingerprints(favicon_hash);`,
+		`CREATE INDEX IF NOT EXISTS idx_screenshots_blob_key ON screenshots(blob_key);`,
+		`CREATE INDEX IF NOT EXISTS idx_response_snapshots_url ON response_snapshots(scope_target_id, url, first_seen_at DESC);`,
+		`CREATE INDEX IF NOT EXISTS idx_response_snapshots_body ON response_snapshots(body_sha256);`,
 	}
 
 	for _, query := range queries {
@@ -1144,6 +1729,16 @@ func createTables() {
 		DELETE FROM subfinder_scans WHERE status = 'pending';
 		DELETE FROM shuffledns_scans WHERE status = 'pending';
 		DELETE FROM cewl_scans WHERE status = 'pending';
+		DELETE FROM permutation_scans WHERE status = 'pending' OR status = 'processing';
+		DELETE FROM recursive_enumeration_scans WHERE status = 'pending' OR status = 'processing';
+		DELETE FROM dns_audit_scans WHERE status = 'pending' OR status = 'processing';
+		DELETE FROM email_security_scans WHERE status = 'pending' OR status = 'processing';
+		DELETE FROM parameter_mining_scans WHERE status = 'pending' OR status = 'processing';
+		DELETE FROM js_analysis_scans WHERE status = 'pending' OR status = 'processing';
+		DELETE FROM content_discovery_scans WHERE status = 'pending' OR status = 'processing';
+		DELETE FROM web_fingerprint_scans WHERE status = 'pending' OR status = 'processing';
+		DELETE FROM screenshot_scans WHERE status = 'pending' OR status = 'processing';
+		DELETE FROM security_header_scans WHERE status = 'pending' OR status = 'processing';
 		DELETE FROM shufflednscustom_scans WHERE status = 'pending';
 		DELETE FROM gospider_scans WHERE status = 'pending';
 		DELETE FROM subdomainizer_scans WHERE status = 'pending';
@@ -0,0 +1,256 @@
+package dnsresolver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// The functions in this file talk to one name server directly instead of going through the
+// pool. They are for auditing authoritative servers, so nothing is retried, rate limited or
+// cross-checked and responses are returned unfiltered.
+
+// Upper bound on the records accepted from a single zone transfer
+const maxTransferRecords = 500000
+
+// ErrNotWalkable is returned by WalkNSEC when the server synthesizes minimally covering NSEC
+// records, so the chain only ever points at made up names
+var ErrNotWalkable = errors.New("zone uses synthesized NSEC records and cannot be walked")
+
+// QueryOptions controls a query sent straight to one name server
+type QueryOptions struct {
+	Timeout time.Duration
+	// Set RD, which name servers should ignore unless they are also open resolvers
+	Recursive bool
+	// Set the DO bit to get NSEC or NSEC3 records with negative answers
+	DNSSEC bool
+}
+
+func (o QueryOptions) timeout() time.Duration {
+	if o.Timeout <= 0 {
+		return DefaultOptions().Timeout
+	}
+	return o.Timeout
+}
+
+func serverResolver(server string) (*resolver, error) {
+	address, err := NormalizeAddress(server)
+	if err != nil {
+		return nil, err
+	}
+	return &resolver{address: address}, nil
+}
+
+// Exchange sends one query to server, an IP address with an optional port, and returns the
+// whole response so the caller can inspect its flags and authority section
+func Exchange(ctx context.Context, server, name string, qtype uint16, opts QueryOptions) (*Message, error) {
+	r, err := serverResolver(server)
+	if err != nil {
+		return nil, err
+	}
+	msg, _, err := r.send(ctx, name, qtype, opts.timeout(), opts.Recursive, opts.DNSSEC)
+	return msg, err
+}
+
+// Transfer requests a full zone transfer (AXFR) over TCP and returns every record in the zone.
+// A server that refuses returns an error carrying the response code.
+func Transfer(ctx context.Context, server, zone string, timeout time.Duration) ([]Record, error) {
+	r, err := serverResolver(server)
+	if err != nil {
+		return nil, err
+	}
+	zone = normalizeName(zone)
+
+	var idBytes [2]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return nil, err
+	}
+	id := binary.BigEndian.Uint16(idBytes[:])
+	query, err := buildQuery(id, zone, TypeAXFR, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	d := net.Dialer{Deadline: deadline}
+	conn, err := d.DialContext(ctx, "tcp", r.address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(deadline)
+
+	framed := binary.BigEndian.AppendUint16(nil, uint16(len(query)))
+	if _, err := conn.Write(append(framed, query...)); err != nil {
+		return nil, err
+	}
+
+	// The transfer is a stream of messages that starts and ends with the zone's SOA record
+	var records []Record
+	soaCount := 0
+	for soaCount < 2 {
+		var lenBuf [2]byte
+		if _, err := io.ReadFull(conn, lenBuf[:]); err != nil {
+			if len(records) > 0 {
+				return records, fmt.Errorf("transfer ended early: %v", err)
+			}
+			return nil, err
+		}
+		buf := make([]byte, binary.BigEndian.Uint16(lenBuf[:]))
+		if _, err := io.ReadFull(conn, buf); err != nil {
+			return records, err
+		}
+		msg, err := parseMessage(buf)
+		if err != nil {
+			return records, err
+		}
+		if msg.ID != id {
+			return records, errIDMismatch
+		}
+		if msg.Rcode != RcodeSuccess {
+			return nil, rcodeError(msg.Rcode)
+		}
+		if len(records) == 0 && (len(msg.Answers) == 0 || msg.Answers[0].Type != TypeSOA) {
+			return nil, fmt.Errorf("transfer of %s did not start with an SOA record", zone)
+		}
+		for _, rr := range msg.Answers {
+			if rr.Type == TypeSOA && rr.Name == zone {
+				soaCount++
+				if soaCount == 2 {
+					break
+				}
+			}
+			records = append(records, rr)
+		}
+		if len(records) > maxTransferRecords {
+			return records, fmt.Errorf("transfer of %s exceeded %d records", zone, maxTransferRecords)
+		}
+	}
+	return records, nil
+}
+
+// findNSEC returns the NSEC record owned by name from the answer or authority section
+func findNSEC(msg *Message, name string) (Record, bool) {
+	for _, section := range [][]Record{msg.Answers, msg.Authority} {
+		for _, rr := range section {
+			if rr.Type == TypeNSEC && rr.Name == name {
+				return rr, true
+			}
+		}
+	}
+	return Record{}, false
+}
+
+// WalkNSEC follows the NSEC chain of a zone on one of its name servers and returns the owner
+// names in chain order, starting with the apex. It stops when the chain loops back to the apex
+// or after maxNames names.
+func WalkNSEC(ctx context.Context, server, zone string, maxNames int, opts QueryOptions) ([]string, error) {
+	zone = normalizeName(zone)
+	opts.DNSSEC = true
+	opts.Recursive = false
+
+	names := []string{zone}
+	seen := map[string]bool{zone: true}
+	current := zone
+	for maxNames <= 0 || len(names) < maxNames {
+		if err := ctx.Err(); err != nil {
+			return names, err
+		}
+		msg, err := Exchange(ctx, server, current, TypeNSEC, opts)
+		if err != nil {
+			return names, err
+		}
+		if msg.Rcode != RcodeSuccess && msg.Rcode != RcodeNXDomain {
+			return names, rcodeError(msg.Rcode)
+		}
+		rr, ok := findNSEC(msg, current)
+		if !ok {
+			if current == zone {
+				return nil, fmt.Errorf("%s returned no NSEC record for %s", server, zone)
+			}
+			return names, fmt.Errorf("NSEC chain broke at %s", current)
+		}
+
+		next := rr.Target
+		// Online signers answer with the next possible name, which starts with a zero byte
+		if strings.ContainsRune(next, 0) {
+			return names, ErrNotWalkable
+		}
+		if next == zone || seen[next] || !strings.HasSuffix(next, "."+zone) {
+			break
+		}
+		seen[next] = true
+		names = append(names, next)
+		current = next
+	}
+	return names, nil
+}
+
+// NSEC3Chain is what probing a zone for NSEC3 records reveals. The hashes can be cracked
+// offline with the salt and iteration count to recover names in the zone.
+type NSEC3Chain struct {
+	Iterations uint16   `json:"iterations"`
+	Salt       string   `json:"salt"`
+	Hashes     []string `json:"hashes"`
+}
+
+// CollectNSEC3 queries probes random names in a zone and gathers the hashed owner names from
+// the NSEC3 records that deny them. It returns nil when the zone doesn't use NSEC3.
+func CollectNSEC3(ctx context.Context, server, zone string, probes int, opts QueryOptions) (*NSEC3Chain, error) {
+	zone = normalizeName(zone)
+	opts.DNSSEC = true
+	opts.Recursive = false
+
+	var chain *NSEC3Chain
+	seen := make(map[string]bool)
+	addHash := func(hash string) {
+		if hash != "" && !seen[hash] {
+			seen[hash] = true
+			chain.Hashes = append(chain.Hashes, hash)
+		}
+	}
+
+	for i := 0; i < probes; i++ {
+		if err := ctx.Err(); err != nil {
+			break
+		}
+		msg, err := Exchange(ctx, server, randomLabel()+"."+zone, TypeA, opts)
+		if err != nil {
+			if chain == nil {
+				return nil, err
+			}
+			continue
+		}
+		for _, rr := range msg.Authority {
+			if rr.Type != TypeNSEC3 {
+				continue
+			}
+			if chain == nil {
+				chain = &NSEC3Chain{Iterations: rr.Iterations, Salt: rr.Salt}
+			}
+			owner := rr.Name
+			if idx := strings.Index(owner, "."); idx > 0 {
+				owner = owner[:idx]
+			}
+			addHash(owner)
+			addHash(rr.Target)
+		}
+		if chain == nil && i == 0 {
+			// The first answer has no NSEC3 records, so the zone is unsigned or uses NSEC
+			return nil, nil
+		}
+	}
+	return chain, nil
+}
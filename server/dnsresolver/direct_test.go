@@ -0,0 +1,238 @@
+package dnsresolver_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"ars0n-framework-v2-server/dnsresolver"
+	"ars0n-framework-v2-server/dnsresolver/dnstest"
+)
+
+func startStub(t *testing.T, handler dnstest.Handler) string {
+	t.Helper()
+	server, err := dnstest.NewServer(handler)
+	if err != nil {
+		t.Fatalf("failed to start DNS stub: %v", err)
+	}
+	t.Cleanup(server.Close)
+	return server.Addr
+}
+
+func soa(zone string, serial uint32) dnsresolver.Record {
+	return dnsresolver.Record{Name: zone, Type: dnsresolver.TypeSOA, Target: "ns1." + zone, Serial: serial}
+}
+
+func a(name, ip string) dnsresolver.Record {
+	return dnsresolver.Record{Name: name, Type: dnsresolver.TypeA, IP: net.ParseIP(ip)}
+}
+
+func TestTransfer(t *testing.T) {
+	addr := startStub(t, func(q dnstest.Query) []dnstest.Response {
+		if q.Type != dnsresolver.TypeAXFR || !q.TCP {
+			return nil
+		}
+		switch q.Name {
+		case "example.com":
+			// Split across two messages, as large zones are
+			return []dnstest.Response{
+				{Authoritative: true, Answers: []dnsresolver.Record{
+					soa("example.com", 7),
+					{Name: "example.com", Type: dnsresolver.TypeNS, Target: "ns1.example.com"},
+					a("www.example.com", "192.0.2.10"),
+				}},
+				{Authoritative: true, Answers: []dnsresolver.Record{
+					a("mail.example.com", "192.0.2.20"),
+					soa("example.com", 7),
+				}},
+			}
+		default:
+			return []dnstest.Response{{Rcode: dnsresolver.RcodeRefused}}
+		}
+	})
+
+	records, err := dnsresolver.Transfer(context.Background(), addr, "example.com", 2*time.Second)
+	if err != nil {
+		t.Fatalf("transfer failed: %v", err)
+	}
+	var names []string
+	for _, rr := range records {
+		names = append(names, rr.Name)
+	}
+	want := []string{"example.com", "example.com", "www.example.com", "mail.example.com"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("transferred %v, want %v", names, want)
+	}
+
+	records, err = dnsresolver.Transfer(context.Background(), addr, "example.org", 2*time.Second)
+	if err == nil || records != nil {
+		t.Fatalf("refused transfer returned %d records and error %v", len(records), err)
+	}
+	if !strings.Contains(err.Error(), "refused") {
+		t.Errorf("error %q does not mention the refusal", err)
+	}
+}
+
+// nsecZone answers NSEC queries from a fixed chain of owner names
+func nsecZone(chain map[string]string) dnstest.Handler {
+	return func(q dnstest.Query) []dnstest.Response {
+		next, ok := chain[q.Name]
+		if q.Type != dnsresolver.TypeNSEC || !q.DNSSEC || q.Recursive || !ok {
+			return []dnstest.Response{{Rcode: dnsresolver.RcodeRefused}}
+		}
+		return []dnstest.Response{{Authoritative: true, Answers: []dnsresolver.Record{{
+			Name: q.Name, Type: dnsresolver.TypeNSEC, Target: next,
+			Types: []uint16{dnsresolver.TypeA, dnsresolver.TypeNSEC},
+		}}}}
+	}
+}
+
+func TestWalkNSEC(t *testing.T) {
+	opts := dnsresolver.QueryOptions{Timeout: time.Second}
+
+	t.Run("chain ends at the apex", func(t *testing.T) {
+		addr := startStub(t, nsecZone(map[string]string{
+			"example.com":      "api.example.com",
+			"api.example.com":  "dev.example.com",
+			"dev.example.com":  "www.example.com",
+			"www.example.com":  "example.com",
+			"skip.example.com": "never.example.com",
+		}))
+		names, err := dnsresolver.WalkNSEC(context.Background(), addr, "example.com", 0, opts)
+		if err != nil {
+			t.Fatalf("walk failed: %v", err)
+		}
+		want := []string{"example.com", "api.example.com", "dev.example.com", "www.example.com"}
+		if !reflect.DeepEqual(names, want) {
+			t.Errorf("walked %v, want %v", names, want)
+		}
+	})
+
+	t.Run("chain leaves the zone", func(t *testing.T) {
+		addr := startStub(t, nsecZone(map[string]string{
+			"example.com":     "www.example.com",
+			"www.example.com": "example.net",
+		}))
+		names, err := dnsresolver.WalkNSEC(context.Background(), addr, "example.com", 0, opts)
+		if err != nil || len(names) != 2 {
+			t.Errorf("walked %v (%v), want the apex and www", names, err)
+		}
+	})
+
+	t.Run("max names", func(t *testing.T) {
+		addr := startStub(t, nsecZone(map[string]string{
+			"example.com":   "a.example.com",
+			"a.example.com": "b.example.com",
+			"b.example.com": "c.example.com",
+			"c.example.com": "example.com",
+		}))
+		names, err := dnsresolver.WalkNSEC(context.Background(), addr, "example.com", 2, opts)
+		if err != nil || len(names) != 2 {
+			t.Errorf("walked %v (%v), want 2 names", names, err)
+		}
+	})
+
+	t.Run("synthesized records", func(t *testing.T) {
+		addr := startStub(t, nsecZone(map[string]string{"example.com": "\x00.example.com"}))
+		_, err := dnsresolver.WalkNSEC(context.Background(), addr, "example.com", 0, opts)
+		if !errors.Is(err, dnsresolver.ErrNotWalkable) {
+			t.Errorf("got error %v, want ErrNotWalkable", err)
+		}
+	})
+
+	t.Run("broken chain", func(t *testing.T) {
+		addr := startStub(t, nsecZone(map[string]string{"example.com": "gone.example.com"}))
+		names, err := dnsresolver.WalkNSEC(context.Background(), addr, "example.com", 0, opts)
+		if err == nil || len(names) != 2 {
+			t.Errorf("walked %v (%v), want the names before the break and an error", names, err)
+		}
+	})
+}
+
+func TestCollectNSEC3(t *testing.T) {
+	opts := dnsresolver.QueryOptions{Timeout: time.Second}
+	hashes := []string{
+		"0p9mhaveqvm6t7vbl5lop2u3t2rp3tom",
+		"2t7b4g4vsa5smi47k61mv5bv1a22bojr",
+		"9lcg0h5pcgdsbcq8ulqkn0dpbq0pp18g",
+	}
+
+	addr := startStub(t, func(q dnstest.Query) []dnstest.Response {
+		if !q.DNSSEC {
+			return []dnstest.Response{{Rcode: dnsresolver.RcodeNXDomain, Authoritative: true}}
+		}
+		// Deny every name with the two records around the apex, as a real zone would for
+		// names hashing into those intervals
+		return []dnstest.Response{{Rcode: dnsresolver.RcodeNXDomain, Authoritative: true, Authority: []dnsresolver.Record{
+			soa("example.com", 1),
+			{Name: hashes[0] + ".example.com", Type: dnsresolver.TypeNSEC3, Target: hashes[1], Iterations: 10, Salt: "aabbccdd"},
+			{Name: hashes[1] + ".example.com", Type: dnsresolver.TypeNSEC3, Target: hashes[2], Iterations: 10, Salt: "aabbccdd"},
+		}}}
+	})
+
+	chain, err := dnsresolver.CollectNSEC3(context.Background(), addr, "example.com", 3, opts)
+	if err != nil || chain == nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+	if chain.Iterations != 10 || chain.Salt != "aabbccdd" {
+		t.Errorf("got iterations %d and salt %q", chain.Iterations, chain.Salt)
+	}
+	if !reflect.DeepEqual(chain.Hashes, hashes) {
+		t.Errorf("collected %v, want %v", chain.Hashes, hashes)
+	}
+
+	unsigned := startStub(t, func(q dnstest.Query) []dnstest.Response {
+		return []dnstest.Response{{Rcode: dnsresolver.RcodeNXDomain, Authoritative: true, Authority: []dnsresolver.Record{soa("example.com", 1)}}}
+	})
+	chain, err = dnsresolver.CollectNSEC3(context.Background(), unsigned, "example.com", 3, opts)
+	if err != nil || chain != nil {
+		t.Errorf("unsigned zone returned %v (%v), want nil", chain, err)
+	}
+}
+
+func TestLookupIPAddr(t *testing.T) {
+	addr := startStub(t, func(q dnstest.Query) []dnstest.Response {
+		switch q.Name {
+		case "gone.example.com":
+			return []dnstest.Response{{Rcode: dnsresolver.RcodeNXDomain, RecursionAvailable: true}}
+		case "v6only.example.com":
+			if q.Type == dnsresolver.TypeAAAA {
+				return []dnstest.Response{{RecursionAvailable: true, Answers: []dnsresolver.Record{
+					{Name: q.Name, Type: dnsresolver.TypeAAAA, IP: net.ParseIP("2001:db8::1")},
+				}}}
+			}
+			return []dnstest.Response{{RecursionAvailable: true}}
+		case "slow.example.com":
+			// A times out while AAAA answers with no records
+			if q.Type == dnsresolver.TypeA {
+				return nil
+			}
+			return []dnstest.Response{{RecursionAvailable: true}}
+		}
+		return []dnstest.Response{{RecursionAvailable: true}}
+	})
+	options := dnsresolver.DefaultOptions()
+	options.Timeout = 200 * time.Millisecond
+	options.Retries = 0
+	pool := dnsresolver.NewPool([]string{addr}, options)
+
+	addrs, err := pool.LookupIPAddr(context.Background(), "v6only.example.com")
+	if err != nil || len(addrs) != 1 || addrs[0].IP.String() != "2001:db8::1" {
+		t.Errorf("v6-only host gave %v (%v)", addrs, err)
+	}
+
+	_, err = pool.LookupIPAddr(context.Background(), "gone.example.com")
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Errorf("missing host gave %v, want not found", err)
+	}
+
+	_, err = pool.LookupIPAddr(context.Background(), "slow.example.com")
+	if err == nil || (errors.As(err, &dnsErr) && dnsErr.IsNotFound) {
+		t.Errorf("timed out lookup gave %v, want a non not-found error", err)
+	}
+}
@@ -0,0 +1,296 @@
+// Package dnstest provides an authoritative DNS stub for tests of code that talks to name
+// servers directly. It answers over UDP and TCP on the same loopback port.
+package dnstest
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"ars0n-framework-v2-server/dnsresolver"
+)
+
+var nsec3Encoding = base32.HexEncoding.WithPadding(base32.NoPadding)
+
+// Query is a question the server received
+type Query struct {
+	Name      string
+	Type      uint16
+	Recursive bool
+	DNSSEC    bool
+	TCP       bool
+}
+
+// Response is one message sent back. Records are encoded from the dnsresolver.Record fields
+// the package decodes, so a test can round-trip what it expects to read.
+type Response struct {
+	Rcode              int
+	Authoritative      bool
+	RecursionAvailable bool
+	Truncated          bool
+	Answers            []dnsresolver.Record
+	Authority          []dnsresolver.Record
+}
+
+// Handler answers a query. Every response is sent in order over TCP, which is how zone
+// transfers are streamed; UDP gets only the first. Returning none drops the query, which
+// the client sees as a timeout.
+type Handler func(q Query) []Response
+
+// Server is a running stub
+type Server struct {
+	Addr string
+
+	udp     net.PacketConn
+	tcp     net.Listener
+	handler Handler
+	wg      sync.WaitGroup
+}
+
+// NewServer starts a stub on a free port of 127.0.0.1
+func NewServer(handler Handler) (*Server, error) {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	udp, err := net.ListenPacket("udp", tcp.Addr().String())
+	if err != nil {
+		tcp.Close()
+		return nil, err
+	}
+
+	s := &Server{Addr: tcp.Addr().String(), udp: udp, tcp: tcp, handler: handler}
+	s.wg.Add(2)
+	go s.serveUDP()
+	go s.serveTCP()
+	return s, nil
+}
+
+// Close stops the server and waits for its connections to finish
+func (s *Server) Close() {
+	s.udp.Close()
+	s.tcp.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serveUDP() {
+	defer s.wg.Done()
+	buf := make([]byte, 4096)
+	for {
+		n, addr, err := s.udp.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		id, q, err := parseQuery(buf[:n])
+		if err != nil {
+			continue
+		}
+		responses := s.handler(q)
+		if len(responses) == 0 {
+			continue
+		}
+		if msg, err := encodeResponse(id, q, responses[0]); err == nil {
+			s.udp.WriteTo(msg, addr)
+		}
+	}
+}
+
+func (s *Server) serveTCP() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.tcp.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+
+			var lenBuf [2]byte
+			if _, err := io.ReadFull(conn, lenBuf[:]); err != nil {
+				return
+			}
+			buf := make([]byte, binary.BigEndian.Uint16(lenBuf[:]))
+			if _, err := io.ReadFull(conn, buf); err != nil {
+				return
+			}
+			id, q, err := parseQuery(buf)
+			if err != nil {
+				return
+			}
+			q.TCP = true
+			for _, response := range s.handler(q) {
+				msg, err := encodeResponse(id, q, response)
+				if err != nil {
+					return
+				}
+				if _, err := conn.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(msg))), msg...)); err != nil {
+					return
+				}
+			}
+		}()
+	}
+}
+
+var errMalformed = errors.New("malformed query")
+
+// parseQuery reads the question and the DO bit of an uncompressed query
+func parseQuery(msg []byte) (uint16, Query, error) {
+	if len(msg) < 12 || binary.BigEndian.Uint16(msg[4:]) != 1 {
+		return 0, Query{}, errMalformed
+	}
+	q := Query{Recursive: binary.BigEndian.Uint16(msg[2:])&0x0100 != 0}
+	var labels []string
+	off := 12
+	for {
+		if off >= len(msg) {
+			return 0, Query{}, errMalformed
+		}
+		n := int(msg[off])
+		off++
+		if n == 0 {
+			break
+		}
+		if off+n > len(msg) {
+			return 0, Query{}, errMalformed
+		}
+		labels = append(labels, string(msg[off:off+n]))
+		off += n
+	}
+	if off+4 > len(msg) {
+		return 0, Query{}, errMalformed
+	}
+	q.Name = strings.ToLower(strings.Join(labels, "."))
+	q.Type = binary.BigEndian.Uint16(msg[off:])
+	off += 4
+
+	// An OPT record in the additional section carries the DO bit
+	if binary.BigEndian.Uint16(msg[10:]) > 0 && off+11 <= len(msg) && msg[off] == 0 {
+		q.DNSSEC = binary.BigEndian.Uint32(msg[off+5:])&0x8000 != 0
+	}
+	return binary.BigEndian.Uint16(msg[0:]), q, nil
+}
+
+func appendName(b []byte, name string) []byte {
+	name = strings.TrimSuffix(name, ".")
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			b = append(b, byte(len(label)))
+			b = append(b, label...)
+		}
+	}
+	return append(b, 0)
+}
+
+func appendTypeBitmap(b []byte, types []uint16) []byte {
+	windows := make(map[byte][]byte)
+	var order []byte
+	for _, t := range types {
+		window, bit := byte(t>>8), int(t&0xff)
+		bitmap, ok := windows[window]
+		if !ok {
+			order = append(order, window)
+		}
+		for len(bitmap) <= bit/8 {
+			bitmap = append(bitmap, 0)
+		}
+		bitmap[bit/8] |= 0x80 >> (bit % 8)
+		windows[window] = bitmap
+	}
+	for _, window := range order {
+		b = append(b, window, byte(len(windows[window])))
+		b = append(b, windows[window]...)
+	}
+	return b
+}
+
+func encodeRData(rr dnsresolver.Record) ([]byte, error) {
+	switch rr.Type {
+	case dnsresolver.TypeA:
+		return rr.IP.To4(), nil
+	case dnsresolver.TypeAAAA:
+		return rr.IP.To16(), nil
+	case dnsresolver.TypeCNAME, dnsresolver.TypeNS, dnsresolver.TypePTR:
+		return appendName(nil, rr.Target), nil
+	case dnsresolver.TypeTXT:
+		var b []byte
+		for _, text := range rr.Text {
+			b = append(b, byte(len(text)))
+			b = append(b, text...)
+		}
+		return b, nil
+	case dnsresolver.TypeSOA:
+		b := appendName(nil, rr.Target)
+		b = appendName(b, "hostmaster."+rr.Name)
+		b = binary.BigEndian.AppendUint32(b, rr.Serial)
+		for _, v := range []uint32{3600, 600, 604800, 300} {
+			b = binary.BigEndian.AppendUint32(b, v)
+		}
+		return b, nil
+	case dnsresolver.TypeNSEC:
+		return appendTypeBitmap(appendName(nil, rr.Target), rr.Types), nil
+	case dnsresolver.TypeNSEC3:
+		salt, err := hex.DecodeString(rr.Salt)
+		if err != nil {
+			return nil, err
+		}
+		hash, err := nsec3Encoding.DecodeString(strings.ToUpper(rr.Target))
+		if err != nil {
+			return nil, err
+		}
+		b := []byte{1, 0}
+		b = binary.BigEndian.AppendUint16(b, rr.Iterations)
+		b = append(b, byte(len(salt)))
+		b = append(b, salt...)
+		b = append(b, byte(len(hash)))
+		b = append(b, hash...)
+		return appendTypeBitmap(b, rr.Types), nil
+	}
+	return nil, errors.New("unsupported record type")
+}
+
+func encodeResponse(id uint16, q Query, response Response) ([]byte, error) {
+	flags := uint16(0x8000) | uint16(response.Rcode&0x0f)
+	if q.Recursive {
+		flags |= 0x0100
+	}
+	if response.Authoritative {
+		flags |= 0x0400
+	}
+	if response.Truncated {
+		flags |= 0x0200
+	}
+	if response.RecursionAvailable {
+		flags |= 0x0080
+	}
+
+	b := make([]byte, 12)
+	binary.BigEndian.PutUint16(b[0:], id)
+	binary.BigEndian.PutUint16(b[2:], flags)
+	binary.BigEndian.PutUint16(b[4:], 1)
+	binary.BigEndian.PutUint16(b[6:], uint16(len(response.Answers)))
+	binary.BigEndian.PutUint16(b[8:], uint16(len(response.Authority)))
+	b = appendName(b, q.Name)
+	b = binary.BigEndian.AppendUint16(b, q.Type)
+	b = binary.BigEndian.AppendUint16(b, 1)
+
+	for _, rr := range append(append([]dnsresolver.Record(nil), response.Answers...), response.Authority...) {
+		rdata, err := encodeRData(rr)
+		if err != nil {
+			return nil, err
+		}
+		b = appendName(b, rr.Name)
+		b = binary.BigEndian.AppendUint16(b, rr.Type)
+		b = binary.BigEndian.AppendUint16(b, 1)
+		b = binary.BigEndian.AppendUint32(b, rr.TTL)
+		b = binary.BigEndian.AppendUint16(b, uint16(len(rdata)))
+		b = append(b, rdata...)
+	}
+	return b, nil
+}
@@ -0,0 +1,266 @@
+package dnsresolver
+
+import (
+	"context"
+	"net"
+	"sort"
+	"strings"
+)
+
+// The Lookup methods mirror net.Resolver so callers can swap one for the other. Names that
+// don't exist return a *net.DNSError with IsNotFound set, like the standard library. A pool
+// without resolvers falls back to the system resolver.
+
+func notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func lookupError(name string, err error) error {
+	if _, ok := err.(*net.DNSError); ok {
+		return err
+	}
+	return &net.DNSError{Err: err.Error(), Name: name, IsTimeout: isTimeout(err), IsTemporary: true}
+}
+
+func isTimeout(err error) bool {
+	if ne, ok := err.(net.Error); ok {
+		return ne.Timeout()
+	}
+	return err == context.DeadlineExceeded
+}
+
+// records queries a name and returns the answers of the wanted type at the end of the chain
+func (p *Pool) records(ctx context.Context, name string, qtype uint16) ([]Record, error) {
+	msg, err := p.Query(ctx, name, qtype)
+	if err != nil {
+		return nil, lookupError(name, err)
+	}
+	if msg.Rcode == RcodeNXDomain {
+		return nil, notFound(name)
+	}
+	var records []Record
+	for _, rr := range msg.Answers {
+		if rr.Type == qtype {
+			records = append(records, rr)
+		}
+	}
+	return records, nil
+}
+
+// LookupIPAddr returns the IPv4 and IPv6 addresses of a host
+func (p *Pool) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	if p.empty() {
+		return p.fallback.LookupIPAddr(ctx, host)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IPAddr{{IP: ip}}, nil
+	}
+
+	// Both families are queried at once so a lookup costs one round trip
+	var v6 []Record
+	var err6 error
+	done := make(chan struct{})
+	go func() {
+		v6, err6 = p.records(ctx, host, TypeAAAA)
+		close(done)
+	}()
+	v4, err4 := p.records(ctx, host, TypeA)
+	<-done
+	if err4 != nil && err6 != nil {
+		return nil, err4
+	}
+
+	var addrs []net.IPAddr
+	for _, rr := range append(v4, v6...) {
+		addrs = append(addrs, net.IPAddr{IP: rr.IP})
+	}
+	if len(addrs) == 0 {
+		// A failed family may hold the records the other lacks, so the name is only missing
+		// when neither lookup failed for another reason
+		for _, err := range []error{err4, err6} {
+			if dnsErr, ok := err.(*net.DNSError); err != nil && (!ok || !dnsErr.IsNotFound) {
+				return nil, err
+			}
+		}
+		return nil, notFound(host)
+	}
+	return addrs, nil
+}
+
+// LookupIP returns the addresses of a host for network "ip", "ip4" or "ip6"
+func (p *Pool) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	addrs, err := p.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var ips []net.IP
+	for _, addr := range addrs {
+		isV4 := addr.IP.To4() != nil
+		if (network == "ip4" && !isV4) || (network == "ip6" && isV4) {
+			continue
+		}
+		ips = append(ips, addr.IP)
+	}
+	if len(ips) == 0 {
+		return nil, notFound(host)
+	}
+	return ips, nil
+}
+
+// LookupHost returns the addresses of a host as strings
+func (p *Pool) LookupHost(ctx context.Context, host string) ([]string, error) {
+	addrs, err := p.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	hosts := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		hosts = append(hosts, addr.IP.String())
+	}
+	return hosts, nil
+}
+
+// LookupCNAME follows the CNAME chain of a host and returns the canonical name with a
+// trailing dot, or the host itself when it has no CNAME
+func (p *Pool) LookupCNAME(ctx context.Context, host string) (string, error) {
+	if p.empty() {
+		return p.fallback.LookupCNAME(ctx, host)
+	}
+	msg, err := p.Query(ctx, host, TypeA)
+	if err != nil {
+		return "", lookupError(host, err)
+	}
+	if msg.Rcode == RcodeNXDomain {
+		return "", notFound(host)
+	}
+
+	canonical := normalizeName(host)
+	for hop := 0; hop < 16; hop++ {
+		next := ""
+		for _, rr := range msg.Answers {
+			if rr.Type == TypeCNAME && rr.Name == canonical {
+				next = rr.Target
+				break
+			}
+		}
+		if next == "" {
+			break
+		}
+		canonical = next
+	}
+	return canonical + ".", nil
+}
+
+// LookupMX returns the MX records of a domain sorted by preference
+func (p *Pool) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if p.empty() {
+		return p.fallback.LookupMX(ctx, name)
+	}
+	records, err := p.records(ctx, name, TypeMX)
+	if err != nil {
+		return nil, err
+	}
+	mx := make([]*net.MX, 0, len(records))
+	for _, rr := range records {
+		mx = append(mx, &net.MX{Host: rr.Target + ".", Pref: rr.Pref})
+	}
+	sort.Slice(mx, func(i, j int) bool { return mx[i].Pref < mx[j].Pref })
+	return mx, nil
+}
+
+// LookupTXT returns the TXT records of a domain with each record's strings joined
+func (p *Pool) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if p.empty() {
+		return p.fallback.LookupTXT(ctx, name)
+	}
+	records, err := p.records(ctx, name, TypeTXT)
+	if err != nil {
+		return nil, err
+	}
+	txt := make([]string, 0, len(records))
+	for _, rr := range records {
+		txt = append(txt, strings.Join(rr.Text, ""))
+	}
+	return txt, nil
+}
+
+// LookupNS returns the NS records of a domain
+func (p *Pool) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	if p.empty() {
+		return p.fallback.LookupNS(ctx, name)
+	}
+	records, err := p.records(ctx, name, TypeNS)
+	if err != nil {
+		return nil, err
+	}
+	ns := make([]*net.NS, 0, len(records))
+	for _, rr := range records {
+		ns = append(ns, &net.NS{Host: rr.Target + "."})
+	}
+	return ns, nil
+}
+
+// LookupSRV looks up _service._proto.name, or name directly when service and proto are empty
+func (p *Pool) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	if p.empty() {
+		return p.fallback.LookupSRV(ctx, service, proto, name)
+	}
+	target := name
+	if service != "" || proto != "" {
+		target = "_" + service + "._" + proto + "." + name
+	}
+	records, err := p.records(ctx, target, TypeSRV)
+	if err != nil {
+		return "", nil, err
+	}
+	srv := make([]*net.SRV, 0, len(records))
+	for _, rr := range records {
+		srv = append(srv, &net.SRV{Target: rr.Target + ".", Port: rr.Port, Priority: rr.Priority, Weight: rr.Weight})
+	}
+	sort.Slice(srv, func(i, j int) bool {
+		if srv[i].Priority != srv[j].Priority {
+			return srv[i].Priority < srv[j].Priority
+		}
+		return srv[i].Weight > srv[j].Weight
+	})
+	return normalizeName(target) + ".", srv, nil
+}
+
+// LookupAddr returns the PTR names for an IP address
+func (p *Pool) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	if p.empty() {
+		return p.fallback.LookupAddr(ctx, addr)
+	}
+	arpa, err := reverseName(addr)
+	if err != nil {
+		return nil, err
+	}
+	records, err := p.records(ctx, arpa, TypePTR)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(records))
+	for _, rr := range records {
+		names = append(names, rr.Target+".")
+	}
+	if len(names) == 0 {
+		return nil, notFound(addr)
+	}
+	return names, nil
+}
+
+func reverseName(addr string) (string, error) {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return "", &net.DNSError{Err: "unrecognized address", Name: addr}
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return net.IPv4(v4[3], v4[2], v4[1], v4[0]).String() + ".in-addr.arpa", nil
+	}
+	const hexDigits = "0123456789abcdef"
+	b := make([]byte, 0, 64+8)
+	for i := len(ip) - 1; i >= 0; i-- {
+		b = append(b, hexDigits[ip[i]&0x0F], '.', hexDigits[ip[i]>>4], '.')
+	}
+	return string(b) + "ip6.arpa", nil
+}
@@ -0,0 +1,390 @@
+package dnsresolver
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Record types used by the lookups in this package
+const (
+	TypeA     uint16 = 1
+	TypeNS    uint16 = 2
+	TypeCNAME uint16 = 5
+	TypeSOA   uint16 = 6
+	TypePTR   uint16 = 12
+	TypeMX    uint16 = 15
+	TypeTXT   uint16 = 16
+	TypeAAAA  uint16 = 28
+	TypeSRV   uint16 = 33
+	typeOPT   uint16 = 41
+	TypeNSEC  uint16 = 47
+	TypeNSEC3 uint16 = 50
+	TypeAXFR  uint16 = 252
+
+	classINET uint16 = 1
+)
+
+// Response codes a resolver can return
+const (
+	RcodeSuccess  = 0
+	RcodeFormErr  = 1
+	RcodeServFail = 2
+	RcodeNXDomain = 3
+	RcodeRefused  = 5
+)
+
+const (
+	headerLen = 12
+	// Advertised EDNS0 buffer size, small enough to avoid fragmentation
+	ednsBufferSize = 1232
+	maxNameLen     = 255
+	maxPointerHops = 32
+)
+
+var errMalformed = errors.New("malformed DNS message")
+
+// NSEC3 owner names and next hashed owners are written in base32hex without padding
+var nsec3Encoding = base32.HexEncoding.WithPadding(base32.NoPadding)
+
+// Record is a decoded resource record. Names are lower case without the trailing dot.
+// Target is the name a record points at: the primary name server for SOA, the next owner
+// name for NSEC and the next hashed owner for NSEC3.
+type Record struct {
+	Name       string
+	Type       uint16
+	TTL        uint32
+	IP         net.IP
+	Target     string
+	Pref       uint16
+	Priority   uint16
+	Weight     uint16
+	Port       uint16
+	Text       []string
+	Serial     uint32
+	Types      []uint16
+	Iterations uint16
+	Salt       string
+}
+
+// Message is the part of a DNS response the pool and the direct queries need
+type Message struct {
+	ID                 uint16
+	Response           bool
+	Authoritative      bool
+	Truncated          bool
+	RecursionAvailable bool
+	Rcode              int
+	Question           string
+	QType              uint16
+	Answers            []Record
+	Authority          []Record
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+}
+
+func appendName(b []byte, name string) ([]byte, error) {
+	name = normalizeName(name)
+	if len(name)+1 > maxNameLen {
+		return nil, fmt.Errorf("name too long: %s", name)
+	}
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if label == "" || len(label) > 63 {
+				return nil, fmt.Errorf("invalid label in name %q", name)
+			}
+			b = append(b, byte(len(label)))
+			b = append(b, label...)
+		}
+	}
+	return append(b, 0), nil
+}
+
+// buildQuery encodes a query for a single question with an EDNS0 OPT record. Queries to
+// name servers rather than resolvers leave recursion off, and dnssec sets the DO bit so
+// NSEC and NSEC3 records come back with negative answers.
+func buildQuery(id uint16, name string, qtype uint16, recursive, dnssec bool) ([]byte, error) {
+	b := make([]byte, headerLen, 512)
+	binary.BigEndian.PutUint16(b[0:], id)
+	if recursive {
+		binary.BigEndian.PutUint16(b[2:], 0x0100) // RD
+	}
+	binary.BigEndian.PutUint16(b[4:], 1)  // QDCOUNT
+	binary.BigEndian.PutUint16(b[10:], 1) // ARCOUNT (OPT)
+
+	var err error
+	if b, err = appendName(b, name); err != nil {
+		return nil, err
+	}
+	b = binary.BigEndian.AppendUint16(b, qtype)
+	b = binary.BigEndian.AppendUint16(b, classINET)
+
+	// OPT pseudo-record: root name, type, UDP payload size, extended rcode/flags, empty rdata
+	var ednsFlags uint32
+	if dnssec {
+		ednsFlags = 0x8000 // DO
+	}
+	b = append(b, 0)
+	b = binary.BigEndian.AppendUint16(b, typeOPT)
+	b = binary.BigEndian.AppendUint16(b, ednsBufferSize)
+	b = binary.BigEndian.AppendUint32(b, ednsFlags)
+	b = binary.BigEndian.AppendUint16(b, 0)
+	return b, nil
+}
+
+// readName decodes a possibly compressed name starting at off and returns the offset after it
+func readName(msg []byte, off int) (string, int, error) {
+	var labels []string
+	end := -1
+	hops := 0
+	length := 0
+
+	for {
+		if off >= len(msg) {
+			return "", 0, errMalformed
+		}
+		c := int(msg[off])
+		switch c & 0xC0 {
+		case 0x00:
+			if c == 0 {
+				if end < 0 {
+					end = off + 1
+				}
+				return strings.ToLower(strings.Join(labels, ".")), end, nil
+			}
+			if off+1+c > len(msg) {
+				return "", 0, errMalformed
+			}
+			length += c + 1
+			if length > maxNameLen {
+				return "", 0, errMalformed
+			}
+			labels = append(labels, string(msg[off+1:off+1+c]))
+			off += 1 + c
+		case 0xC0:
+			if off+1 >= len(msg) {
+				return "", 0, errMalformed
+			}
+			if end < 0 {
+				end = off + 2
+			}
+			hops++
+			if hops > maxPointerHops {
+				return "", 0, errMalformed
+			}
+			off = int(binary.BigEndian.Uint16(msg[off:]) & 0x3FFF)
+		default:
+			return "", 0, errMalformed
+		}
+	}
+}
+
+// parseMessage decodes the header, the first question and the answer and authority sections
+// of a response
+func parseMessage(msg []byte) (*Message, error) {
+	if len(msg) < headerLen {
+		return nil, errMalformed
+	}
+	flags := binary.BigEndian.Uint16(msg[2:])
+	m := &Message{
+		ID:                 binary.BigEndian.Uint16(msg[0:]),
+		Response:           flags&0x8000 != 0,
+		Authoritative:      flags&0x0400 != 0,
+		Truncated:          flags&0x0200 != 0,
+		RecursionAvailable: flags&0x0080 != 0,
+		Rcode:              int(flags & 0x000F),
+	}
+	qdcount := int(binary.BigEndian.Uint16(msg[4:]))
+	ancount := int(binary.BigEndian.Uint16(msg[6:]))
+	nscount := int(binary.BigEndian.Uint16(msg[8:]))
+
+	off := headerLen
+	for i := 0; i < qdcount; i++ {
+		name, next, err := readName(msg, off)
+		if err != nil || next+4 > len(msg) {
+			return nil, errMalformed
+		}
+		if i == 0 {
+			m.Question = name
+			m.QType = binary.BigEndian.Uint16(msg[next:])
+		}
+		off = next + 4
+	}
+
+	var err error
+	if m.Answers, off, err = parseRecords(msg, off, ancount); err != nil {
+		return nil, err
+	}
+	if m.Authority, _, err = parseRecords(msg, off, nscount); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// parseRecords decodes count resource records starting at off and returns the offset after them
+func parseRecords(msg []byte, off, count int) ([]Record, int, error) {
+	var records []Record
+	for i := 0; i < count; i++ {
+		name, next, err := readName(msg, off)
+		if err != nil || next+10 > len(msg) {
+			return nil, 0, errMalformed
+		}
+		rr := Record{
+			Name: name,
+			Type: binary.BigEndian.Uint16(msg[next:]),
+			TTL:  binary.BigEndian.Uint32(msg[next+4:]),
+		}
+		class := binary.BigEndian.Uint16(msg[next+2:])
+		rdlen := int(binary.BigEndian.Uint16(msg[next+8:]))
+		rdata := next + 10
+		if rdata+rdlen > len(msg) {
+			return nil, 0, errMalformed
+		}
+		off = rdata + rdlen
+		if class != classINET {
+			continue
+		}
+
+		if err := decodeRData(msg, rdata, rdlen, &rr); err != nil {
+			return nil, 0, err
+		}
+		records = append(records, rr)
+	}
+	return records, off, nil
+}
+
+// readTypeBitmap decodes the window blocks listing the types present at an NSEC or NSEC3 owner
+func readTypeBitmap(b []byte) ([]uint16, error) {
+	var types []uint16
+	for i := 0; i < len(b); {
+		if i+2 > len(b) {
+			return nil, errMalformed
+		}
+		window, length := int(b[i]), int(b[i+1])
+		if length == 0 || length > 32 || i+2+length > len(b) {
+			return nil, errMalformed
+		}
+		for j, octet := range b[i+2 : i+2+length] {
+			for bit := 0; bit < 8; bit++ {
+				if octet&(0x80>>bit) != 0 {
+					types = append(types, uint16(window<<8|j*8+bit))
+				}
+			}
+		}
+		i += 2 + length
+	}
+	return types, nil
+}
+
+func decodeRData(msg []byte, off, length int, rr *Record) error {
+	rdata := msg[off : off+length]
+	var err error
+
+	switch rr.Type {
+	case TypeA:
+		if length != net.IPv4len {
+			return errMalformed
+		}
+		rr.IP = net.IP(append([]byte(nil), rdata...))
+	case TypeAAAA:
+		if length != net.IPv6len {
+			return errMalformed
+		}
+		rr.IP = net.IP(append([]byte(nil), rdata...))
+	case TypeCNAME, TypeNS, TypePTR:
+		rr.Target, _, err = readName(msg, off)
+	case TypeMX:
+		if length < 3 {
+			return errMalformed
+		}
+		rr.Pref = binary.BigEndian.Uint16(rdata)
+		rr.Target, _, err = readName(msg, off+2)
+	case TypeSRV:
+		if length < 7 {
+			return errMalformed
+		}
+		rr.Priority = binary.BigEndian.Uint16(rdata)
+		rr.Weight = binary.BigEndian.Uint16(rdata[2:])
+		rr.Port = binary.BigEndian.Uint16(rdata[4:])
+		rr.Target, _, err = readName(msg, off+6)
+	case TypeTXT:
+		for i := 0; i < len(rdata); {
+			n := int(rdata[i])
+			if i+1+n > len(rdata) {
+				return errMalformed
+			}
+			rr.Text = append(rr.Text, string(rdata[i+1:i+1+n]))
+			i += 1 + n
+		}
+	case TypeSOA:
+		var next int
+		if rr.Target, next, err = readName(msg, off); err != nil {
+			return err
+		}
+		if _, next, err = readName(msg, next); err != nil {
+			return err
+		}
+		if next+4 > off+length {
+			return errMalformed
+		}
+		rr.Serial = binary.BigEndian.Uint32(msg[next:])
+	case TypeNSEC:
+		// The next owner name is never compressed, so it ends inside the rdata
+		var next int
+		if rr.Target, next, err = readName(rdata, 0); err != nil {
+			return err
+		}
+		rr.Types, err = readTypeBitmap(rdata[next:])
+	case TypeNSEC3:
+		if length < 5 {
+			return errMalformed
+		}
+		rr.Iterations = binary.BigEndian.Uint16(rdata[2:])
+		saltLen := int(rdata[4])
+		if 5+saltLen+1 > length {
+			return errMalformed
+		}
+		rr.Salt = hex.EncodeToString(rdata[5 : 5+saltLen])
+		hashStart := 5 + saltLen + 1
+		hashLen := int(rdata[5+saltLen])
+		if hashStart+hashLen > length {
+			return errMalformed
+		}
+		rr.Target = strings.ToLower(nsec3Encoding.EncodeToString(rdata[hashStart : hashStart+hashLen]))
+		rr.Types, err = readTypeBitmap(rdata[hashStart+hashLen:])
+	}
+	return err
+}
+
+// answerChain keeps only the answers that belong to the queried name or the CNAME chain
+// starting at it. Anything else in the answer section is out of bailiwick for this query
+// and is how cache poisoning attempts usually show up.
+func answerChain(question string, answers []Record) []Record {
+	names := map[string]bool{question: true}
+	for hop := 0; hop < 16; hop++ {
+		changed := false
+		for _, rr := range answers {
+			if rr.Type == TypeCNAME && names[rr.Name] && !names[rr.Target] {
+				names[rr.Target] = true
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	var chain []Record
+	for _, rr := range answers {
+		if names[rr.Name] {
+			chain = append(chain, rr)
+		}
+	}
+	return chain
+}
@@ -0,0 +1,563 @@
+// Package dnsresolver resolves names against a managed pool of upstream DNS resolvers.
+// Each resolver is rate limited and health checked, failed queries are retried on another
+// resolver, and answers are validated so that a resolver which hijacks NXDOMAIN, sinkholes
+// names or returns out-of-bailiwick records is caught instead of trusted.
+package dnsresolver
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Options controls how the pool queries its resolvers
+type Options struct {
+	// Timeout for a single query to a single resolver
+	Timeout time.Duration
+	// Additional resolvers tried after a timeout, SERVFAIL or REFUSED
+	Retries int
+	// Queries per second allowed against each resolver
+	QPS int
+	// Consecutive failures before a resolver is taken out of rotation until the next health check
+	MaxConsecutiveFailures int
+	// Ask a second resolver before trusting NXDOMAIN
+	ConfirmNXDomain bool
+	// Domain used by health checks. It must resolve and must not have a wildcard record.
+	CanaryDomain string
+}
+
+// DefaultOptions returns the settings used by the server's shared pool
+func DefaultOptions() Options {
+	return Options{
+		Timeout:                2 * time.Second,
+		Retries:                2,
+		QPS:                    20,
+		MaxConsecutiveFailures: 5,
+		ConfirmNXDomain:        true,
+		CanaryDomain:           "example.com",
+	}
+}
+
+// ResolverStatus is a snapshot of one resolver's health and statistics
+type ResolverStatus struct {
+	Address      string     `json:"address"`
+	Healthy      bool       `json:"healthy"`
+	Lying        bool       `json:"lying"`
+	Queries      int64      `json:"queries"`
+	Failures     int64      `json:"failures"`
+	AvgLatencyMs float64    `json:"avg_latency_ms"`
+	LastError    string     `json:"last_error,omitempty"`
+	LastChecked  *time.Time `json:"last_checked,omitempty"`
+}
+
+var (
+	// ErrNoResolvers is returned when every resolver has been tried or none are configured
+	ErrNoResolvers = errors.New("no usable DNS resolvers")
+	errIDMismatch  = errors.New("response does not match query")
+)
+
+type rcodeError int
+
+func (e rcodeError) Error() string {
+	switch int(e) {
+	case RcodeServFail:
+		return "server failure"
+	case RcodeRefused:
+		return "query refused"
+	case RcodeFormErr:
+		return "format error"
+	}
+	return "rcode " + strconv.Itoa(int(e))
+}
+
+// rateLimiter spaces queries evenly at a fixed rate
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func newRateLimiter(qps int) *rateLimiter {
+	if qps <= 0 {
+		return &rateLimiter{}
+	}
+	return &rateLimiter{interval: time.Second / time.Duration(qps)}
+}
+
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l.interval == 0 {
+		return nil
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type resolver struct {
+	address string
+	limiter *rateLimiter
+
+	mu                  sync.Mutex
+	healthy             bool
+	lying               bool
+	consecutiveFailures int
+	queries             int64
+	failures            int64
+	totalLatency        time.Duration
+	lastError           string
+	lastChecked         time.Time
+}
+
+func (r *resolver) usable() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.healthy && !r.lying
+}
+
+func (r *resolver) recordSuccess(latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries++
+	r.totalLatency += latency
+	r.consecutiveFailures = 0
+	r.healthy = true
+}
+
+func (r *resolver) recordFailure(err error, maxFailures int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries++
+	r.failures++
+	r.consecutiveFailures++
+	r.lastError = err.Error()
+	if maxFailures > 0 && r.consecutiveFailures >= maxFailures {
+		r.healthy = false
+	}
+}
+
+func (r *resolver) status() ResolverStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := ResolverStatus{
+		Address:   r.address,
+		Healthy:   r.healthy,
+		Lying:     r.lying,
+		Queries:   r.queries,
+		Failures:  r.failures,
+		LastError: r.lastError,
+	}
+	if succeeded := r.queries - r.failures; succeeded > 0 {
+		s.AvgLatencyMs = float64(r.totalLatency.Microseconds()) / float64(succeeded) / 1000
+	}
+	if !r.lastChecked.IsZero() {
+		checked := r.lastChecked
+		s.LastChecked = &checked
+	}
+	return s
+}
+
+// exchange sends one recursive query and keeps only the answers in the queried name's chain
+func (r *resolver) exchange(ctx context.Context, name string, qtype uint16, timeout time.Duration) (*Message, time.Duration, error) {
+	msg, latency, err := r.send(ctx, name, qtype, timeout, true, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	msg.Answers = answerChain(msg.Question, msg.Answers)
+	return msg, latency, nil
+}
+
+// send sends one query over UDP, falling back to TCP when the answer is truncated
+func (r *resolver) send(ctx context.Context, name string, qtype uint16, timeout time.Duration, recursive, dnssec bool) (*Message, time.Duration, error) {
+	var idBytes [2]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return nil, 0, err
+	}
+	id := binary.BigEndian.Uint16(idBytes[:])
+	query, err := buildQuery(id, name, qtype, recursive, dnssec)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	start := time.Now()
+	msg, err := r.exchangeUDP(ctx, query, id, deadline)
+	if err == nil && msg.Truncated {
+		msg, err = r.exchangeTCP(ctx, query, id, deadline)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if !msg.Response || msg.Question != normalizeName(name) || msg.QType != qtype {
+		return nil, 0, errIDMismatch
+	}
+	return msg, time.Since(start), nil
+}
+
+func (r *resolver) exchangeUDP(ctx context.Context, query []byte, id uint16, deadline time.Time) (*Message, error) {
+	d := net.Dialer{Deadline: deadline}
+	conn, err := d.DialContext(ctx, "udp", r.address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(deadline)
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 4096)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		msg, err := parseMessage(buf[:n])
+		// Responses with another ID are ignored rather than accepted, which is the
+		// cheapest defence against off-path spoofing
+		if err != nil || msg.ID != id {
+			continue
+		}
+		return msg, nil
+	}
+}
+
+func (r *resolver) exchangeTCP(ctx context.Context, query []byte, id uint16, deadline time.Time) (*Message, error) {
+	d := net.Dialer{Deadline: deadline}
+	conn, err := d.DialContext(ctx, "tcp", r.address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(deadline)
+
+	framed := binary.BigEndian.AppendUint16(nil, uint16(len(query)))
+	if _, err := conn.Write(append(framed, query...)); err != nil {
+		return nil, err
+	}
+
+	var lenBuf [2]byte
+	if _, err := io.ReadFull(conn, lenBuf[:]); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, binary.BigEndian.Uint16(lenBuf[:]))
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		return nil, err
+	}
+	msg, err := parseMessage(buf)
+	if err != nil {
+		return nil, err
+	}
+	if msg.ID != id {
+		return nil, errIDMismatch
+	}
+	return msg, nil
+}
+
+// Pool spreads queries across a set of resolvers
+type Pool struct {
+	opts      Options
+	mu        sync.RWMutex
+	resolvers []*resolver
+	next      uint32
+	fallback  *net.Resolver
+}
+
+// NewPool creates a pool from resolver addresses. Invalid addresses are skipped.
+func NewPool(addresses []string, opts Options) *Pool {
+	p := &Pool{
+		opts:     opts,
+		fallback: &net.Resolver{PreferGo: true},
+	}
+	p.SetResolvers(addresses)
+	return p
+}
+
+// NormalizeAddress turns "8.8.8.8", "2001:4860:4860::8888" or "1.1.1.1:5353" into host:port.
+// Resolvers must be IP addresses so resolving them never depends on DNS.
+func NormalizeAddress(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host, port = address, "53"
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return "", fmt.Errorf("resolver %q is not an IP address", address)
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return "", fmt.Errorf("resolver %q has an invalid port", address)
+	}
+	return net.JoinHostPort(ip.String(), port), nil
+}
+
+// SetResolvers replaces the resolver list, keeping statistics for addresses already in the pool
+func (p *Pool) SetResolvers(addresses []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	existing := make(map[string]*resolver)
+	for _, r := range p.resolvers {
+		existing[r.address] = r
+	}
+
+	var resolvers []*resolver
+	seen := make(map[string]bool)
+	for _, address := range addresses {
+		normalized, err := NormalizeAddress(address)
+		if err != nil || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		if r, ok := existing[normalized]; ok {
+			resolvers = append(resolvers, r)
+			continue
+		}
+		resolvers = append(resolvers, &resolver{
+			address: normalized,
+			limiter: newRateLimiter(p.opts.QPS),
+			healthy: true,
+		})
+	}
+	p.resolvers = resolvers
+}
+
+// Status returns a snapshot of every resolver in the pool
+func (p *Pool) Status() []ResolverStatus {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	statuses := make([]ResolverStatus, 0, len(p.resolvers))
+	for _, r := range p.resolvers {
+		statuses = append(statuses, r.status())
+	}
+	return statuses
+}
+
+// Addresses returns the usable resolvers in host:port form
+func (p *Pool) Addresses() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	var addresses []string
+	for _, r := range p.resolvers {
+		if r.usable() {
+			addresses = append(addresses, r.address)
+		}
+	}
+	return addresses
+}
+
+func (p *Pool) empty() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.resolvers) == 0
+}
+
+// pick returns the next resolver in round-robin order that has not been tried yet,
+// preferring healthy ones. Unhealthy resolvers are still used once every healthy
+// one is exhausted so a pool never goes dark after a network blip.
+func (p *Pool) pick(tried map[*resolver]bool) *resolver {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	n := len(p.resolvers)
+	if n == 0 {
+		return nil
+	}
+	start := int(atomic.AddUint32(&p.next, 1))
+	var fallback *resolver
+	for i := 0; i < n; i++ {
+		r := p.resolvers[(start+i)%n]
+		if tried[r] {
+			continue
+		}
+		if r.usable() {
+			return r
+		}
+		r.mu.Lock()
+		lying := r.lying
+		r.mu.Unlock()
+		if fallback == nil && !lying {
+			fallback = r
+		}
+	}
+	return fallback
+}
+
+// sinkholed reports whether an answer points at an address filtering resolvers use to block names
+func sinkholed(msg *Message) bool {
+	for _, rr := range msg.Answers {
+		if rr.IP != nil && (rr.IP.IsUnspecified() || rr.IP.IsLoopback()) {
+			return true
+		}
+	}
+	return false
+}
+
+// Query resolves one name and record type, retrying on other resolvers when a resolver
+// fails and cross-checking NXDOMAIN and sinkholed answers before returning them
+func (p *Pool) Query(ctx context.Context, name string, qtype uint16) (*Message, error) {
+	name = normalizeName(name)
+	attempts := 1 + p.opts.Retries
+	tried := make(map[*resolver]bool)
+
+	var lastErr error
+	var unconfirmed *Message
+	var unconfirmedBy *resolver
+
+	for attempt := 0; attempt < attempts; attempt++ {
+		r := p.pick(tried)
+		if r == nil {
+			break
+		}
+		tried[r] = true
+
+		if err := r.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+		msg, latency, err := r.exchange(ctx, name, qtype, p.opts.Timeout)
+		if err != nil {
+			r.recordFailure(err, p.opts.MaxConsecutiveFailures)
+			lastErr = err
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		switch msg.Rcode {
+		case RcodeSuccess, RcodeNXDomain:
+		default:
+			r.recordFailure(rcodeError(msg.Rcode), p.opts.MaxConsecutiveFailures)
+			lastErr = rcodeError(msg.Rcode)
+			continue
+		}
+		r.recordSuccess(latency)
+
+		needsConfirmation := (msg.Rcode == RcodeNXDomain && p.opts.ConfirmNXDomain) || sinkholed(msg)
+		if unconfirmed == nil && needsConfirmation && attempt+1 < attempts {
+			unconfirmed, unconfirmedBy = msg, r
+			continue
+		}
+
+		if unconfirmed == nil {
+			return msg, nil
+		}
+		// The second opinion resolved a name the first resolver claimed doesn't exist or
+		// sinkholed, so the first resolver's answer is counted against it
+		if !needsConfirmation {
+			unconfirmedBy.recordFailure(fmt.Errorf("inconsistent answer for %s", name), p.opts.MaxConsecutiveFailures)
+			return msg, nil
+		}
+		if sinkholed(unconfirmed) && !sinkholed(msg) {
+			return msg, nil
+		}
+		return unconfirmed, nil
+	}
+
+	if unconfirmed != nil {
+		return unconfirmed, nil
+	}
+	if lastErr == nil {
+		lastErr = ErrNoResolvers
+	}
+	return nil, lastErr
+}
+
+func randomLabel() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return "ars0n-" + hex.EncodeToString(b)
+}
+
+// HealthCheck queries every resolver directly. A resolver is healthy when it answers the
+// canary domain, and lying when it returns records for a random name under the canary.
+func (p *Pool) HealthCheck(ctx context.Context) []ResolverStatus {
+	p.mu.RLock()
+	resolvers := append([]*resolver(nil), p.resolvers...)
+	p.mu.RUnlock()
+
+	canary := normalizeName(p.opts.CanaryDomain)
+	var wg sync.WaitGroup
+	for _, r := range resolvers {
+		wg.Add(1)
+		go func(r *resolver) {
+			defer wg.Done()
+			p.checkResolver(ctx, r, canary)
+		}(r)
+	}
+	wg.Wait()
+
+	return p.Status()
+}
+
+func (p *Pool) checkResolver(ctx context.Context, r *resolver, canary string) {
+	var healthy, lying bool
+	var checkErr error
+	var latency time.Duration
+
+	msg, lat, err := r.exchange(ctx, canary, TypeA, p.opts.Timeout)
+	switch {
+	case err != nil:
+		checkErr = err
+	case msg.Rcode != RcodeSuccess:
+		checkErr = fmt.Errorf("%s returned %v", canary, rcodeError(msg.Rcode))
+	case len(msg.Answers) == 0:
+		checkErr = fmt.Errorf("no answers for %s", canary)
+	default:
+		healthy = true
+		latency = lat
+	}
+
+	if healthy {
+		probe := randomLabel() + "." + canary
+		msg, _, err := r.exchange(ctx, probe, TypeA, p.opts.Timeout)
+		if err == nil && msg.Rcode == RcodeSuccess && len(msg.Answers) > 0 {
+			lying = true
+			checkErr = fmt.Errorf("returned records for non-existent name %s", probe)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.healthy = healthy
+	r.lying = lying
+	r.lastChecked = time.Now()
+	if checkErr != nil {
+		r.lastError = checkErr.Error()
+	} else {
+		r.lastError = ""
+		r.consecutiveFailures = 0
+		r.queries++
+		r.totalLatency += latency
+	}
+}
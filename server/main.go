@@ -8,6 +8,7 @@ import (
 	"io"
 	"log"
 	"math"
+	"net"
 	"net/http"
 	"os"
 	"os/exec"
@@ -15,6 +16,7 @@ import (
 	"strings"
 	"time"
 
+	"ars0n-framework-v2-server/openapi"
 	"ars0n-framework-v2-server/utils"
 
 	"github.com/google/uuid"
@@ -30,6 +32,18 @@ func min(a, b int) int {
 }
 
 func main() {
+	// "openapi" prints the API specification without connecting to the database
+	if len(os.Args) > 1 && os.Args[1] == "openapi" {
+		spec, err := buildOpenAPISpec(newRouter())
+		if err != nil {
+			log.Fatalf("Failed to build OpenAPI spec: %v", err)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(spec)
+		return
+	}
+
 	connStr := os.Getenv("DATABASE_URL")
 	if connStr == "" {
 		log.Fatal("Environment variable DATABASE_URL is not set")
@@ -55,11 +69,21 @@ func main() {
 	defer dbPool.Close()
 
 	createTables()
+	go utils.MigrateInlineScreenshots()
+
+	r := newRouter()
 
+	log.Println("API server started on :8443")
+	http.ListenAndServe(":8443", r)
+}
+
+// newRouter registers every API route
+func newRouter() *mux.Router {
 	r := mux.NewRouter()
 
 	// Apply CORS middleware first
 	r.Use(corsMiddleware)
+	r.Use(openapi.NormalizeResponses)
 
 	// Define routes
 	r.HandleFunc("/scopetarget/add", utils.CreateScopeTarget).Methods("POST", "OPTIONS")
@@ -90,6 +114,7 @@ func main() {
 	r.HandleFunc("/gau/run", utils.RunGauScan).Methods("POST", "OPTIONS")
 	r.HandleFunc("/gau/{scanID}", utils.GetGauScanStatus).Methods("GET", "OPTIONS")
 	r.HandleFunc("/scopetarget/{id}/scans/gau", utils.GetGauScansForScopeTarget).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/urls", utils.GetURLCorpus).Methods("GET", "OPTIONS")
 	r.HandleFunc("/sublist3r/run", utils.RunSublist3rScan).Methods("POST", "OPTIONS")
 	r.HandleFunc("/sublist3r/{scan_id}", utils.GetSublist3rScanStatus).Methods("GET", "OPTIONS")
 	r.HandleFunc("/scopetarget/{id}/scans/sublist3r", utils.GetSublist3rScansForScopeTarget).Methods("GET", "OPTIONS")
@@ -121,6 +146,64 @@ func main() {
 	r.HandleFunc("/scopetarget/{id}/scans/subfinder", utils.GetSubfinderScansForScopeTarget).Methods("GET", "OPTIONS")
 	r.HandleFunc("/consolidate-subdomains/{id}", utils.HandleConsolidateSubdomains).Methods("GET", "OPTIONS")
 	r.HandleFunc("/consolidated-subdomains/{id}", utils.GetConsolidatedSubdomains).Methods("GET", "OPTIONS")
+	r.HandleFunc("/wildcard-dns-zones/{id}", utils.GetWildcardDNSZones).Methods("GET", "OPTIONS")
+	r.HandleFunc("/subdomain-takeover/run", utils.RunSubdomainTakeoverScan).Methods("POST", "OPTIONS")
+	r.HandleFunc("/subdomain-takeover/{scan_id}", utils.GetSubdomainTakeoverScanStatus).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/scans/subdomain-takeover", utils.GetSubdomainTakeoverScansForScopeTarget).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/subdomain-takeover-findings", utils.GetSubdomainTakeoverFindings).Methods("GET", "OPTIONS")
+	r.HandleFunc("/dns-audit/run", utils.RunDNSAuditScan).Methods("POST", "OPTIONS")
+	r.HandleFunc("/dns-audit/{scan_id}", utils.GetDNSAuditScanStatus).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/scans/dns-audit", utils.GetDNSAuditScansForScopeTarget).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/dns-audit-findings", utils.GetDNSAuditFindings).Methods("GET", "OPTIONS")
+	r.HandleFunc("/email-security/run", utils.RunEmailSecurityScan).Methods("POST", "OPTIONS")
+	r.HandleFunc("/email-security/{scan_id}", utils.GetEmailSecurityScanStatus).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/scans/email-security", utils.GetEmailSecurityScansForScopeTarget).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/email-security", utils.GetEmailSecuritySummary).Methods("GET", "OPTIONS")
+	r.HandleFunc("/parameter-mining/run", utils.RunParameterMiningScan).Methods("POST", "OPTIONS")
+	r.HandleFunc("/parameter-mining/{scan_id}", utils.GetParameterMiningScanStatus).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/scans/parameter-mining", utils.GetParameterMiningScansForScopeTarget).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/parameters", utils.GetURLParameters).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/parameters/export", utils.ExportURLParameters).Methods("GET", "OPTIONS")
+	r.HandleFunc("/js-analysis/run", utils.RunJSAnalysisScan).Methods("POST", "OPTIONS")
+	r.HandleFunc("/js-analysis/{scan_id}", utils.GetJSAnalysisScanStatus).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/scans/js-analysis", utils.GetJSAnalysisScansForScopeTarget).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/js-files", utils.GetJSFiles).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/js-secrets", utils.GetJSSecrets).Methods("GET", "OPTIONS")
+	r.HandleFunc("/js-files/{id}/sources", utils.GetJSSourceFiles).Methods("GET", "OPTIONS")
+	r.HandleFunc("/js-files/{id}/sources/download", utils.DownloadJSSourceFiles).Methods("GET", "OPTIONS")
+	r.HandleFunc("/content-discovery/run", utils.RunContentDiscoveryScan).Methods("POST", "OPTIONS")
+	r.HandleFunc("/content-discovery/{scan_id}", utils.GetContentDiscoveryScanStatus).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/scans/content-discovery", utils.GetContentDiscoveryScansForScopeTarget).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/content-discovery", utils.GetContentDiscoveryResults).Methods("GET", "OPTIONS")
+	r.HandleFunc("/content-wordlists", utils.GetContentWordlists).Methods("GET", "OPTIONS")
+	r.HandleFunc("/content-wordlists", utils.UploadContentWordlist).Methods("POST", "OPTIONS")
+	r.HandleFunc("/content-wordlists/{id}", utils.UpdateContentWordlistTags).Methods("PUT", "OPTIONS")
+	r.HandleFunc("/content-wordlists/{id}", utils.DeleteContentWordlist).Methods("DELETE", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/technologies", utils.GetTechnologyInventory).Methods("GET", "OPTIONS")
+	r.HandleFunc("/fingerprint", utils.FingerprintURL).Methods("POST", "OPTIONS")
+	r.HandleFunc("/fingerprint-rules", utils.GetFingerprintRuleFiles).Methods("GET", "OPTIONS")
+	r.HandleFunc("/fingerprint-rules", utils.UploadFingerprintRuleFile).Methods("POST", "OPTIONS")
+	r.HandleFunc("/fingerprint-rules/{id}", utils.DeleteFingerprintRuleFile).Methods("DELETE", "OPTIONS")
+	r.HandleFunc("/web-fingerprint/run", utils.RunWebFingerprintScan).Methods("POST", "OPTIONS")
+	r.HandleFunc("/web-fingerprint/{scan_id}", utils.GetWebFingerprintScanStatus).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/scans/web-fingerprint", utils.GetWebFingerprintScansForScopeTarget).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/web-fingerprints", utils.GetWebFingerprints).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/web-fingerprints/clusters", utils.GetWebFingerprintClusters).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/web-fingerprints/shodan-match", utils.MatchShodanExport).Methods("POST", "OPTIONS")
+	r.HandleFunc("/screenshots/{id}", utils.GetScreenshotImage).Methods("GET", "OPTIONS")
+	r.HandleFunc("/screenshots/{id}/thumbnail", utils.GetScreenshotThumbnail).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/screenshots", utils.GetScreenshots).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/screenshots/clusters", utils.GetScreenshotClusters).Methods("GET", "OPTIONS")
+	r.HandleFunc("/screenshot/run", utils.RunScreenshotScan).Methods("POST", "OPTIONS")
+	r.HandleFunc("/screenshot/{scan_id}", utils.GetScreenshotScanStatus).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/scans/screenshot", utils.GetScreenshotScansForScopeTarget).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/response-history", utils.GetResponseTimeline).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/response-changes", utils.GetResponseChanges).Methods("GET", "OPTIONS")
+	r.HandleFunc("/response-snapshots/{id}/body", utils.GetResponseSnapshotBody).Methods("GET", "OPTIONS")
+	r.HandleFunc("/security-headers/run", utils.RunSecurityHeaderScan).Methods("POST", "OPTIONS")
+	r.HandleFunc("/security-headers/{scan_id}", utils.GetSecurityHeaderScanStatus).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/scans/security-headers", utils.GetSecurityHeaderScansForScopeTarget).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/security-headers", utils.GetSecurityHeaderSummary).Methods("GET", "OPTIONS")
 	r.HandleFunc("/consolidate-company-domains/{id}", utils.HandleConsolidateCompanyDomains).Methods("GET", "OPTIONS")
 	r.HandleFunc("/consolidated-company-domains/{id}", utils.GetConsolidatedCompanyDomains).Methods("GET", "OPTIONS")
 	r.HandleFunc("/consolidate-network-ranges/{id}", utils.HandleConsolidateNetworkRanges).Methods("GET", "OPTIONS")
@@ -137,6 +220,12 @@ func main() {
 	r.HandleFunc("/cewl-urls/run", utils.RunCeWLScansForUrls).Methods("POST", "OPTIONS")
 	r.HandleFunc("/cewl-wordlist/run", utils.RunShuffleDNSWithWordlist).Methods("POST", "OPTIONS")
 	r.HandleFunc("/cewl-wordlist/{scan_id}", utils.GetShuffleDNSScanStatus).Methods("GET", "OPTIONS")
+	r.HandleFunc("/permutations/run", utils.RunPermutationScan).Methods("POST", "OPTIONS")
+	r.HandleFunc("/permutations/{scan_id}", utils.GetPermutationScanStatus).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/scans/permutations", utils.GetPermutationScansForScopeTarget).Methods("GET", "OPTIONS")
+	r.HandleFunc("/recursive-enum/run", utils.RunRecursiveEnumerationScan).Methods("POST", "OPTIONS")
+	r.HandleFunc("/recursive-enum/{scan_id}", utils.GetRecursiveEnumerationScanStatus).Methods("GET", "OPTIONS")
+	r.HandleFunc("/scopetarget/{id}/scans/recursive-enum", utils.GetRecursiveEnumerationScansForScopeTarget).Methods("GET", "OPTIONS")
 	r.HandleFunc("/api/scope-targets/{id}/shufflednscustom-scans", utils.GetShuffleDNSCustomScansForScopeTarget).Methods("GET", "OPTIONS")
 	r.HandleFunc("/gospider/run", utils.RunGoSpiderScan).Methods("POST", "OPTIONS")
 	r.HandleFunc("/gospider/{scan_id}", utils.GetGoSpiderScanStatus).Methods("GET", "OPTIONS")
@@ -192,6 +281,11 @@ func main() {
 	r.HandleFunc("/api/ai-api-keys", createAiAPIKey).Methods("POST", "OPTIONS")
 	r.HandleFunc("/api/ai-api-keys/{id}", updateAiAPIKey).Methods("PUT", "OPTIONS")
 	r.HandleFunc("/api/ai-api-keys/{id}", deleteAiAPIKey).Methods("DELETE", "OPTIONS")
+
+	// DNS resolver pool routes
+	r.HandleFunc("/api/dns-resolvers", utils.GetDNSResolvers).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/dns-resolvers", utils.UpdateDNSResolvers).Methods("PUT", "OPTIONS")
+	r.HandleFunc("/api/dns-resolvers/health-check", utils.RunDNSResolverHealthCheck).Methods("POST", "OPTIONS")
 	r.HandleFunc("/securitytrails-company/run", utils.RunSecurityTrailsCompanyScan).Methods("POST", "OPTIONS")
 	r.HandleFunc("/securitytrails-company/status/{scan_id}", utils.GetSecurityTrailsCompanyScanStatus).Methods("GET", "OPTIONS")
 	r.HandleFunc("/scopetarget/{id}/scans/securitytrails-company", utils.GetSecurityTrailsCompanyScansForScopeTarget).Methods("GET", "OPTIONS")
@@ -213,6 +307,9 @@ func main() {
 	r.HandleFunc("/scopetarget/{id}/scans/ip-port", utils.GetIPPortScansForScopeTarget).Methods("GET", "OPTIONS")
 	r.HandleFunc("/ip-port-scan/{scan_id}/live-web-servers", utils.GetLiveWebServers).Methods("GET", "OPTIONS")
 	r.HandleFunc("/ip-port-scan/{scan_id}/discovered-ips", utils.GetDiscoveredIPs).Methods("GET", "OPTIONS")
+	r.HandleFunc("/ip-port-scan/{scan_id}/services", utils.GetDiscoveredServices).Methods("GET", "OPTIONS")
+	r.HandleFunc("/ip-port-scan-config/{scope_target_id}", utils.GetIPPortScanConfig).Methods("GET", "OPTIONS")
+	r.HandleFunc("/ip-port-scan-config/{scope_target_id}", utils.SaveIPPortScanConfig).Methods("POST", "OPTIONS")
 
 	// Company domain management routes
 	r.HandleFunc("/api/company-domains/{scope_target_id}/{tool}", getCompanyDomainsByTool).Methods("GET", "OPTIONS")
@@ -267,11 +364,31 @@ func main() {
 	// Katana Company scope target-based routes (all scans)
 	r.HandleFunc("/katana-company/target/{scope_target_id}/cloud-assets", utils.GetKatanaCompanyCloudAssetsByTarget).Methods("GET", "OPTIONS")
 
+	// Report generation routes
+	r.HandleFunc("/api/report-templates", utils.GetReportTemplates).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/report-templates", utils.CreateReportTemplate).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/report-templates/{id}", utils.UpdateReportTemplate).Methods("PUT", "OPTIONS")
+	r.HandleFunc("/api/report-templates/{id}", utils.DeleteReportTemplate).Methods("DELETE", "OPTIONS")
+	r.HandleFunc("/api/reports/generate", utils.GenerateReport).Methods("POST", "OPTIONS")
+
+	// Findings export routes
+	r.HandleFunc("/api/findings/{scope_target_id}", utils.GetScopeTargetFindings).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/findings/{scope_target_id}/sarif", utils.HandleSARIFExport).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/findings/{scope_target_id}/defectdojo", utils.HandleDefectDojoExport).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/findings/{scope_target_id}/defectdojo/import", utils.HandleDefectDojoImport).Methods("POST", "OPTIONS")
+
+	// Burp Suite integration routes
+	r.HandleFunc("/api/burp/status", utils.GetBurpSuiteStatus).Methods("GET", "OPTIONS")
+	r.HandleFunc("/api/burp/send", utils.SendTargetURLsToBurp).Methods("POST", "OPTIONS")
+	r.HandleFunc("/api/burp/scan/{task_id}", utils.GetBurpScanStatus).Methods("GET", "OPTIONS")
+
 	// Live web servers count route
 	r.HandleFunc("/scope-target/{scope_target_id}/live-web-servers-count", getLiveWebServersCount).Methods("GET", "OPTIONS")
 
-	log.Println("API server started on :8443")
-	http.ListenAndServe(":8443", r)
+	// API specification route
+	r.HandleFunc("/api/openapi.json", serveOpenAPISpec(r)).Methods("GET", "OPTIONS")
+
+	return r
 }
 
 func corsMiddleware(next http.Handler) http.Handler {
@@ -312,7 +429,9 @@ func getUserSettings(w http.ResponseWriter, r *http.Request) {
 			burp_proxy_port,
 			burp_api_ip,
 			burp_api_port,
-			burp_api_key
+			burp_api_key,
+			COALESCE(burp_proxy_enabled, false),
+			COALESCE(gau_providers, 'wayback')
 		FROM user_settings
 		LIMIT 1
 	`)
@@ -322,6 +441,8 @@ func getUserSettings(w http.ResponseWriter, r *http.Request) {
 		cewlRateLimit, gospiderRateLimit, subdomainizerRateLimit, nucleiScreenshotRateLimit,
 		burpProxyPort, burpApiPort int
 	var customUserAgent, customHeader, burpProxyIP, burpApiIP, burpApiKey sql.NullString
+	var burpProxyEnabled bool
+	var gauProviders string
 
 	err := row.Scan(
 		&amassRateLimit,
@@ -342,6 +463,8 @@ func getUserSettings(w http.ResponseWriter, r *http.Request) {
 		&burpApiIP,
 		&burpApiPort,
 		&burpApiKey,
+		&burpProxyEnabled,
+		&gauProviders,
 	)
 
 	if err != nil {
@@ -366,6 +489,8 @@ func getUserSettings(w http.ResponseWriter, r *http.Request) {
 			"burp_api_ip":                  "127.0.0.1",
 			"burp_api_port":                1337,
 			"burp_api_key":                 "",
+			"burp_proxy_enabled":           false,
+			"gau_providers":                "wayback",
 		}
 	} else {
 		settings = map[string]interface{}{
@@ -387,6 +512,8 @@ func getUserSettings(w http.ResponseWriter, r *http.Request) {
 			"burp_api_ip":                  burpApiIP.String,
 			"burp_api_port":                burpApiPort,
 			"burp_api_key":                 burpApiKey.String,
+			"burp_proxy_enabled":           burpProxyEnabled,
+			"gau_providers":                gauProviders,
 		}
 	}
 
@@ -429,6 +556,8 @@ func updateUserSettings(w http.ResponseWriter, r *http.Request) {
 			burp_api_ip = $16,
 			burp_api_port = $17,
 			burp_api_key = $18,
+			burp_proxy_enabled = $19,
+			gau_providers = $20,
 			updated_at = NOW()
 	`,
 		getIntSetting(settings, "amass_rate_limit", 10),
@@ -449,6 +578,8 @@ func updateUserSettings(w http.ResponseWriter, r *http.Request) {
 		getStringSetting(settings, "burp_api_ip", "127.0.0.1"),
 		getIntSetting(settings, "burp_api_port", 1337),
 		getStringSetting(settings, "burp_api_key", ""),
+		getBoolSetting(settings, "burp_proxy_enabled", false),
+		getStringSetting(settings, "gau_providers", "wayback"),
 	)
 
 	if err != nil {
@@ -490,6 +621,21 @@ func getStringSetting(settings map[string]interface{}, key string, defaultValue
 	return defaultValue
 }
 
+// Helper function to get boolean settings with default values
+func getBoolSetting(settings map[string]interface{}, key string, defaultValue bool) bool {
+	if val, ok := settings[key]; ok {
+		switch v := val.(type) {
+		case bool:
+			return v
+		case string:
+			if boolVal, err := strconv.ParseBool(v); err == nil {
+				return boolVal
+			}
+		}
+	}
+	return defaultValue
+}
+
 // getAutoScanState retrieves the current auto scan state for a target
 func getAutoScanState(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -712,7 +858,7 @@ func updateAutoScanState(w http.ResponseWriter, r *http.Request) {
 
 func getAutoScanConfig(w http.ResponseWriter, r *http.Request) {
 	row := dbPool.QueryRow(context.Background(), `
-		SELECT amass, sublist3r, assetfinder, gau, ctl, subfinder, consolidate_httpx_round1, shuffledns, cewl, consolidate_httpx_round2, gospider, subdomainizer, consolidate_httpx_round3, nuclei_screenshot, metadata, max_consolidated_subdomains, max_live_web_servers
+		SELECT amass, sublist3r, assetfinder, gau, ctl, subfinder, consolidate_httpx_round1, recursive_enumeration, shuffledns, cewl, permutations, consolidate_httpx_round2, gospider, subdomainizer, consolidate_httpx_round3, nuclei_screenshot, metadata, max_consolidated_subdomains, max_live_web_servers, max_permutation_candidates, recursive_max_depth, recursive_max_zones
 		FROM auto_scan_config
 		LIMIT 1
 	`)
@@ -724,8 +870,10 @@ func getAutoScanConfig(w http.ResponseWriter, r *http.Request) {
 		Ctl                       bool `json:"ctl"`
 		Subfinder                 bool `json:"subfinder"`
 		ConsolidateHttpxRound1    bool `json:"consolidate_httpx_round1"`
+		RecursiveEnumeration      bool `json:"recursive_enumeration"`
 		Shuffledns                bool `json:"shuffledns"`
 		Cewl                      bool `json:"cewl"`
+		Permutations              bool `json:"permutations"`
 		ConsolidateHttpxRound2    bool `json:"consolidate_httpx_round2"`
 		Gospider                  bool `json:"gospider"`
 		Subdomainizer             bool `json:"subdomainizer"`
@@ -734,6 +882,9 @@ func getAutoScanConfig(w http.ResponseWriter, r *http.Request) {
 		Metadata                  bool `json:"metadata"`
 		MaxConsolidatedSubdomains int  `json:"maxConsolidatedSubdomains"`
 		MaxLiveWebServers         int  `json:"maxLiveWebServers"`
+		MaxPermutationCandidates  int  `json:"maxPermutationCandidates"`
+		RecursiveMaxDepth         int  `json:"recursiveMaxDepth"`
+		RecursiveMaxZones         int  `json:"recursiveMaxZones"`
 	}
 	err := row.Scan(
 		&config.Amass,
@@ -743,8 +894,10 @@ func getAutoScanConfig(w http.ResponseWriter, r *http.Request) {
 		&config.Ctl,
 		&config.Subfinder,
 		&config.ConsolidateHttpxRound1,
+		&config.RecursiveEnumeration,
 		&config.Shuffledns,
 		&config.Cewl,
+		&config.Permutations,
 		&config.ConsolidateHttpxRound2,
 		&config.Gospider,
 		&config.Subdomainizer,
@@ -753,6 +906,9 @@ func getAutoScanConfig(w http.ResponseWriter, r *http.Request) {
 		&config.Metadata,
 		&config.MaxConsolidatedSubdomains,
 		&config.MaxLiveWebServers,
+		&config.MaxPermutationCandidates,
+		&config.RecursiveMaxDepth,
+		&config.RecursiveMaxZones,
 	)
 	if err != nil {
 		// Return defaults if not found
@@ -764,8 +920,10 @@ func getAutoScanConfig(w http.ResponseWriter, r *http.Request) {
 			Ctl                       bool `json:"ctl"`
 			Subfinder                 bool `json:"subfinder"`
 			ConsolidateHttpxRound1    bool `json:"consolidate_httpx_round1"`
+			RecursiveEnumeration      bool `json:"recursive_enumeration"`
 			Shuffledns                bool `json:"shuffledns"`
 			Cewl                      bool `json:"cewl"`
+			Permutations              bool `json:"permutations"`
 			ConsolidateHttpxRound2    bool `json:"consolidate_httpx_round2"`
 			Gospider                  bool `json:"gospider"`
 			Subdomainizer             bool `json:"subdomainizer"`
@@ -774,8 +932,11 @@ func getAutoScanConfig(w http.ResponseWriter, r *http.Request) {
 			Metadata                  bool `json:"metadata"`
 			MaxConsolidatedSubdomains int  `json:"maxConsolidatedSubdomains"`
 			MaxLiveWebServers         int  `json:"maxLiveWebServers"`
+			MaxPermutationCandidates  int  `json:"maxPermutationCandidates"`
+			RecursiveMaxDepth         int  `json:"recursiveMaxDepth"`
+			RecursiveMaxZones         int  `json:"recursiveMaxZones"`
 		}{
-			Amass: true, Sublist3r: true, Assetfinder: true, Gau: true, Ctl: true, Subfinder: true, ConsolidateHttpxRound1: true, Shuffledns: true, Cewl: true, ConsolidateHttpxRound2: true, Gospider: true, Subdomainizer: true, ConsolidateHttpxRound3: true, NucleiScreenshot: true, Metadata: true, MaxConsolidatedSubdomains: 2500, MaxLiveWebServers: 500,
+			Amass: true, Sublist3r: true, Assetfinder: true, Gau: true, Ctl: true, Subfinder: true, ConsolidateHttpxRound1: true, RecursiveEnumeration: false, Shuffledns: true, Cewl: true, Permutations: true, ConsolidateHttpxRound2: true, Gospider: true, Subdomainizer: true, ConsolidateHttpxRound3: true, NucleiScreenshot: true, Metadata: true, MaxConsolidatedSubdomains: 2500, MaxLiveWebServers: 500, MaxPermutationCandidates: 50000, RecursiveMaxDepth: 2, RecursiveMaxZones: 10,
 		}
 	}
 	log.Printf("[AutoScanConfig] GET: %+v", config)
@@ -792,8 +953,10 @@ func updateAutoScanConfig(w http.ResponseWriter, r *http.Request) {
 		Ctl                       bool `json:"ctl"`
 		Subfinder                 bool `json:"subfinder"`
 		ConsolidateHttpxRound1    bool `json:"consolidate_httpx_round1"`
+		RecursiveEnumeration      bool `json:"recursive_enumeration"`
 		Shuffledns                bool `json:"shuffledns"`
 		Cewl                      bool `json:"cewl"`
+		Permutations              bool `json:"permutations"`
 		ConsolidateHttpxRound2    bool `json:"consolidate_httpx_round2"`
 		Gospider                  bool `json:"gospider"`
 		Subdomainizer             bool `json:"subdomainizer"`
@@ -802,6 +965,9 @@ func updateAutoScanConfig(w http.ResponseWriter, r *http.Request) {
 		Metadata                  bool `json:"metadata"`
 		MaxConsolidatedSubdomains int  `json:"maxConsolidatedSubdomains"`
 		MaxLiveWebServers         int  `json:"maxLiveWebServers"`
+		MaxPermutationCandidates  int  `json:"maxPermutationCandidates"`
+		RecursiveMaxDepth         int  `json:"recursiveMaxDepth"`
+		RecursiveMaxZones         int  `json:"recursiveMaxZones"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -817,16 +983,21 @@ func updateAutoScanConfig(w http.ResponseWriter, r *http.Request) {
 			ctl = $5,
 			subfinder = $6,
 			consolidate_httpx_round1 = $7,
-			shuffledns = $8,
-			cewl = $9,
-			consolidate_httpx_round2 = $10,
-			gospider = $11,
-			subdomainizer = $12,
-			consolidate_httpx_round3 = $13,
-			nuclei_screenshot = $14,
-			metadata = $15,
-			max_consolidated_subdomains = $16,
-			max_live_web_servers = $17,
+			recursive_enumeration = $8,
+			shuffledns = $9,
+			cewl = $10,
+			permutations = $11,
+			consolidate_httpx_round2 = $12,
+			gospider = $13,
+			subdomainizer = $14,
+			consolidate_httpx_round3 = $15,
+			nuclei_screenshot = $16,
+			metadata = $17,
+			max_consolidated_subdomains = $18,
+			max_live_web_servers = $19,
+			max_permutation_candidates = $20,
+			recursive_max_depth = $21,
+			recursive_max_zones = $22,
 			updated_at = NOW()
 		WHERE id = (SELECT id FROM auto_scan_config LIMIT 1)
 	`,
@@ -837,8 +1008,10 @@ func updateAutoScanConfig(w http.ResponseWriter, r *http.Request) {
 		config.Ctl,
 		config.Subfinder,
 		config.ConsolidateHttpxRound1,
+		config.RecursiveEnumeration,
 		config.Shuffledns,
 		config.Cewl,
+		config.Permutations,
 		config.ConsolidateHttpxRound2,
 		config.Gospider,
 		config.Subdomainizer,
@@ -847,6 +1020,9 @@ func updateAutoScanConfig(w http.ResponseWriter, r *http.Request) {
 		config.Metadata,
 		config.MaxConsolidatedSubdomains,
 		config.MaxLiveWebServers,
+		config.MaxPermutationCandidates,
+		config.RecursiveMaxDepth,
+		config.RecursiveMaxZones,
 	)
 	if err != nil {
 		http.Error(w, "Failed to update config", http.StatusInternalServerError)
@@ -2899,7 +3075,7 @@ func getLiveWebServerDomains(scopeTargetID string) ([]string, error) {
 		if err := rows.Scan(&url); err == nil {
 			// Extract domain from URL
 			if url != "" {
-				if domain := extractDomainFromURL(url); domain != "" && !isIPv4Address(domain) {
+				if domain := extractDomainFromURL(url); domain != "" && !isIPAddress(domain) {
 					domainMap[domain] = true
 				}
 			}
@@ -2924,6 +3100,12 @@ func extractDomainFromURL(urlStr string) string {
 	parts := strings.Split(urlStr, "/")
 	if len(parts) >= 3 {
 		hostPart := parts[2]
+		// Bracketed IPv6 literal, e.g. http://[2001:db8::1]:8443/
+		if strings.HasPrefix(hostPart, "[") {
+			if end := strings.Index(hostPart, "]"); end != -1 {
+				return hostPart[1:end]
+			}
+		}
 		// Remove port if present
 		if colonIndex := strings.Index(hostPart, ":"); colonIndex != -1 {
 			hostPart = hostPart[:colonIndex]
@@ -2933,11 +3115,9 @@ func extractDomainFromURL(urlStr string) string {
 	return ""
 }
 
-// Helper function to check if a string is an IPv4 address
-func isIPv4Address(s string) bool {
-	return strings.Contains(s, ".") &&
-		len(strings.Split(s, ".")) == 4 &&
-		!strings.Contains(s, " ")
+// Helper function to check if a string is an IPv4 or IPv6 address
+func isIPAddress(s string) bool {
+	return net.ParseIP(s) != nil
 }
 
 func getKatanaCompanyConfig(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,254 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gorilla/mux"
+)
+
+// Endpoint documents the request and response of a handler. Request and Response hold a zero
+// value of the body type (e.g. utils.AmassScanStatus{} or []utils.ResponsePayload{}). A nil
+// Request means the handler reads no JSON body; a nil Response needs ContentType or NoContent.
+type Endpoint struct {
+	OperationID string
+	Summary     string
+	Description string
+	Request     interface{}
+	Response    interface{}
+	Query       []QueryParam
+	// ContentType overrides the response media type for downloads (e.g. "text/markdown")
+	ContentType string
+	// Status overrides the success status code (defaults to 200)
+	Status int
+	// NoContent documents a success response without a body. A 204 status implies it.
+	NoContent bool
+}
+
+// QueryParam documents a query string parameter. Type defaults to "string".
+type QueryParam struct {
+	Name        string
+	Description string
+	Type        string
+	Required    bool
+}
+
+// Endpoints maps either a handler function name ("RunAmassScan") or a route ("POST /amass/run")
+// to its documentation. Route keys take precedence so handlers mounted twice can be told apart.
+type Endpoints map[string]Endpoint
+
+// ErrorResponse is the error envelope returned to clients that request MediaType
+type ErrorResponse struct {
+	Error  string `json:"error"`
+	Status int    `json:"status"`
+}
+
+var (
+	pathParamPattern = regexp.MustCompile(`\{([^}:]+)(:[^}]+)?\}`)
+	closureSuffix    = regexp.MustCompile(`(\.func\d+)+$`)
+)
+
+// Build walks every route registered on the router and returns the OpenAPI document for it.
+// It fails on a route whose success response is not documented, so every operation in the
+// spec has a typed body, a download media type or no content.
+func Build(r *mux.Router, info Info, endpoints Endpoints) (*Document, error) {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Servers: []Server{{URL: "http://127.0.0.1:8443", Description: "Local ars0n framework API"}},
+		Paths:   make(map[string]*PathItem),
+	}
+	schemas := newSchemaRegistry()
+	errorSchema := schemas.schemaFor(ErrorResponse{})
+
+	operationIDs := make(map[string]string)
+	tags := make(map[string]bool)
+
+	err := r.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		pathTemplate, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return nil
+		}
+
+		handlerName := handlerFuncName(route.GetHandler())
+		path := pathParamPattern.ReplaceAllString(pathTemplate, "{$1}")
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = &PathItem{}
+		}
+
+		for _, method := range methods {
+			if method == http.MethodOptions {
+				continue
+			}
+
+			endpoint, ok := endpoints[method+" "+path]
+			if !ok {
+				endpoint = endpoints[handlerName]
+			}
+
+			op := &Operation{
+				OperationID: endpoint.OperationID,
+				Summary:     endpoint.Summary,
+				Description: endpoint.Description,
+				Tags:        []string{pathTag(path)},
+				Responses:   make(map[string]*Response),
+			}
+			if op.OperationID == "" {
+				op.OperationID = exportedName(handlerName)
+			}
+			if op.Summary == "" {
+				op.Summary = humanize(op.OperationID)
+			}
+			if existing, dup := operationIDs[op.OperationID]; dup {
+				return fmt.Errorf("operation ID %s is used by both %s and %s %s", op.OperationID, existing, method, path)
+			}
+			operationIDs[op.OperationID] = method + " " + path
+			tags[op.Tags[0]] = true
+
+			for _, match := range pathParamPattern.FindAllStringSubmatch(pathTemplate, -1) {
+				op.Parameters = append(op.Parameters, Parameter{
+					Name:     match[1],
+					In:       "path",
+					Required: true,
+					Schema:   &Schema{Type: "string"},
+				})
+			}
+			for _, q := range endpoint.Query {
+				paramType := q.Type
+				if paramType == "" {
+					paramType = "string"
+				}
+				op.Parameters = append(op.Parameters, Parameter{
+					Name:        q.Name,
+					In:          "query",
+					Description: q.Description,
+					Required:    q.Required,
+					Schema:      &Schema{Type: paramType},
+				})
+			}
+
+			if endpoint.Request != nil {
+				op.RequestBody = &RequestBody{
+					Required: true,
+					Content:  map[string]*MediaItem{"application/json": {Schema: schemas.schemaFor(endpoint.Request)}},
+				}
+			}
+
+			status := endpoint.Status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			success := &Response{Description: http.StatusText(status)}
+			switch {
+			case status == http.StatusNoContent || endpoint.NoContent:
+			case endpoint.ContentType != "":
+				success.Content = map[string]*MediaItem{endpoint.ContentType: {Schema: &Schema{Type: "string", Format: "binary"}}}
+			case endpoint.Response != nil:
+				schema := schemas.schemaFor(endpoint.Response)
+				if reflect.DeepEqual(*schema, Schema{}) {
+					return fmt.Errorf("%s %s (%s) documents a response body without a fixed shape", method, path, handlerName)
+				}
+				success.Content = map[string]*MediaItem{"application/json": {Schema: schema}}
+			default:
+				return fmt.Errorf("%s %s (%s) has no documented response body", method, path, handlerName)
+			}
+			op.Responses[fmt.Sprintf("%d", status)] = success
+			op.Responses["default"] = &Response{Ref: "#/components/responses/Error"}
+
+			if !doc.Paths[path].setOperation(method, op) {
+				return fmt.Errorf("unsupported method %s on %s", method, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: name})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+
+	doc.Components = Components{
+		Schemas: schemas.schemas,
+		Responses: map[string]*Response{
+			"Error": {
+				Description: "Error. Clients that send `Accept: " + MediaType + "` receive the JSON envelope below; other clients receive the message as plain text.",
+				Content:     map[string]*MediaItem{"application/json": {Schema: errorSchema}},
+			},
+		},
+	}
+
+	return doc, nil
+}
+
+// handlerFuncName returns the bare function name of a route handler, e.g. "RunAmassScan"
+func handlerFuncName(h http.Handler) string {
+	if h == nil {
+		return ""
+	}
+	v := reflect.ValueOf(h)
+	if v.Kind() != reflect.Func {
+		return reflect.Indirect(v).Type().Name()
+	}
+	fn := runtime.FuncForPC(v.Pointer())
+	if fn == nil {
+		return ""
+	}
+	name := closureSuffix.ReplaceAllString(fn.Name(), "")
+	return name[strings.LastIndex(name, ".")+1:]
+}
+
+// pathTag groups operations by the first meaningful path segment
+func pathTag(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 1 && segments[0] == "api" {
+		segments = segments[1:]
+	}
+	return segments[0]
+}
+
+func exportedName(name string) string {
+	if name == "" {
+		return name
+	}
+	runes := []rune(name)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+// humanize turns an operation ID such as "GetAmassScanStatus" into "Get amass scan status"
+func humanize(operationID string) string {
+	var words []string
+	runes := []rune(operationID)
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		if i == len(runes) || (unicode.IsUpper(runes[i]) && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1])))) {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	for i := 1; i < len(words); i++ {
+		if !isAcronym(words[i]) {
+			words[i] = strings.ToLower(words[i])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func isAcronym(word string) bool {
+	return len(word) > 1 && strings.ToUpper(word) == word
+}
@@ -0,0 +1,337 @@
+// Command clientgen generates the typed Go client in server/client from the OpenAPI spec.
+//
+//	go run ./openapi/clientgen -spec openapi/openapi.json -out client/client_gen.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"go/token"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"ars0n-framework-v2-server/openapi"
+)
+
+// initialisms are upper-cased when converting snake_case JSON names to Go identifiers
+var initialisms = map[string]bool{
+	"ai": true, "api": true, "asn": true, "aws": true, "cidr": true, "cname": true, "cpe": true,
+	"css": true, "cve": true, "cvss": true, "cwe": true, "dns": true, "fqdn": true, "gcp": true,
+	"html": true, "http": true, "https": true, "id": true, "ids": true, "ip": true, "ips": true,
+	"ipv4": true, "ipv6": true, "js": true, "json": true, "mx": true, "ns": true, "ptr": true,
+	"roi": true, "sarif": true, "soa": true, "sql": true, "srv": true, "ssl": true, "tls": true,
+	"ttl": true, "txt": true, "uri": true, "url": true, "urls": true, "uuid": true,
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+func main() {
+	specPath := flag.String("spec", "openapi/openapi.json", "path to the OpenAPI spec")
+	outPath := flag.String("out", "client/client_gen.go", "path of the generated Go file")
+	pkg := flag.String("package", "client", "package name of the generated file")
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatalf("Failed to read spec: %v", err)
+	}
+	var doc openapi.Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		log.Fatalf("Failed to parse spec: %v", err)
+	}
+
+	src, err := generate(&doc, *pkg)
+	if err != nil {
+		log.Fatalf("Failed to generate client: %v", err)
+	}
+	if err := os.WriteFile(*outPath, src, 0644); err != nil {
+		log.Fatalf("Failed to write client: %v", err)
+	}
+}
+
+type operation struct {
+	method string
+	path   string
+	op     *openapi.Operation
+}
+
+func generate(doc *openapi.Document, pkg string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by openapi/clientgen from the OpenAPI spec. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	fmt.Fprintf(&buf, "import (\n\t\"context\"\n\t\"encoding/json\"\n\t\"net/http\"\n\t\"net/url\"\n\t\"time\"\n)\n\n")
+	fmt.Fprintf(&buf, "var (\n\t_ = json.RawMessage(nil)\n\t_ = time.Time{}\n\t_ = url.Values{}\n)\n\n")
+
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		writeStruct(&buf, name, doc.Components.Schemas[name])
+	}
+
+	var ops []operation
+	for path, item := range doc.Paths {
+		for method, op := range item.Operations() {
+			ops = append(ops, operation{method: method, path: path, op: op})
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].op.OperationID < ops[j].op.OperationID })
+	for _, o := range ops {
+		if err := writeOperation(&buf, o); err != nil {
+			return nil, err
+		}
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return buf.Bytes(), fmt.Errorf("generated code does not compile: %v", err)
+	}
+	return src, nil
+}
+
+func writeStruct(buf *bytes.Buffer, name string, schema *openapi.Schema) {
+	required := make(map[string]bool)
+	for _, r := range schema.Required {
+		required[r] = true
+	}
+
+	props := make([]string, 0, len(schema.Properties))
+	for prop := range schema.Properties {
+		props = append(props, prop)
+	}
+	sort.Strings(props)
+
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, prop := range props {
+		propSchema := schema.Properties[prop]
+		tag := prop
+		if !required[prop] || propSchema.Nullable {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(buf, "\t%s %s `json:\"%s\"`\n", exportedIdent(prop), goType(propSchema), tag)
+	}
+	fmt.Fprintf(buf, "}\n\n")
+}
+
+// goType maps a schema to a Go type. Nullable scalars and references become pointers.
+func goType(s *openapi.Schema) string {
+	if s == nil {
+		return "json.RawMessage"
+	}
+	if ref := s.RefName(); ref != "" {
+		if s.Nullable {
+			return "*" + ref
+		}
+		return ref
+	}
+
+	var t string
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			t = "time.Time"
+		case "byte":
+			return "[]byte"
+		default:
+			t = "string"
+		}
+	case "integer":
+		t = "int"
+	case "number":
+		t = "float64"
+	case "boolean":
+		t = "bool"
+	case "array":
+		return "[]" + goType(s.Items)
+	case "object":
+		if s.AdditionalProperties != nil {
+			return "map[string]" + goType(s.AdditionalProperties)
+		}
+		return "json.RawMessage"
+	default:
+		return "json.RawMessage"
+	}
+	if s.Nullable {
+		return "*" + t
+	}
+	return t
+}
+
+func writeOperation(buf *bytes.Buffer, o operation) error {
+	op := o.op
+	name := op.OperationID
+
+	var args []string
+	pathExpr := goPathExpr(o.path, &args)
+
+	var queryParams []openapi.Parameter
+	for _, p := range op.Parameters {
+		if p.In == "query" {
+			queryParams = append(queryParams, p)
+		}
+	}
+
+	paramsType := ""
+	if len(queryParams) > 0 {
+		paramsType = name + "Params"
+		fmt.Fprintf(buf, "// %s holds the query parameters of %s\n", paramsType, name)
+		fmt.Fprintf(buf, "type %s struct {\n", paramsType)
+		for _, p := range queryParams {
+			if p.Description != "" {
+				fmt.Fprintf(buf, "\t// %s\n", p.Description)
+			}
+			fmt.Fprintf(buf, "\t%s string\n", exportedIdent(p.Name))
+		}
+		fmt.Fprintf(buf, "}\n\n")
+		args = append(args, "params *"+paramsType)
+	}
+
+	if op.RequestBody != nil {
+		bodyType := "interface{}"
+		if media, ok := op.RequestBody.Content["application/json"]; ok {
+			bodyType = goType(media.Schema)
+			if bodyType == "json.RawMessage" {
+				bodyType = "interface{}"
+			}
+		}
+		args = append(args, "body "+bodyType)
+	}
+
+	status, resp := successResponse(op)
+	if resp == nil {
+		return fmt.Errorf("operation %s has no success response", name)
+	}
+
+	// Work out the Go return type and how the response is decoded
+	kind, retType := "json", "json.RawMessage"
+	if media, ok := resp.Content["application/json"]; ok {
+		retType = goType(media.Schema)
+		if media.Schema.RefName() != "" && !media.Schema.Nullable {
+			retType = "*" + retType
+		}
+	} else if len(resp.Content) > 0 {
+		kind, retType = "raw", "[]byte"
+	} else {
+		kind, retType = "none", ""
+	}
+	if status == http.StatusNoContent {
+		kind, retType = "none", ""
+	}
+
+	fmt.Fprintf(buf, "// %s calls %s %s.\n//\n// %s.\n", name, o.method, o.path, op.Summary)
+	if op.Description != "" {
+		fmt.Fprintf(buf, "// %s\n", op.Description)
+	}
+
+	signature := fmt.Sprintf("func (c *Client) %s(%s)", name, strings.Join(append([]string{"ctx context.Context"}, args...), ", "))
+	if retType == "" {
+		fmt.Fprintf(buf, "%s error {\n", signature)
+	} else {
+		fmt.Fprintf(buf, "%s (%s, error) {\n", signature, retType)
+	}
+
+	queryArg := "nil"
+	if paramsType != "" {
+		fmt.Fprintf(buf, "\tquery := url.Values{}\n\tif params != nil {\n")
+		for _, p := range queryParams {
+			field := exportedIdent(p.Name)
+			fmt.Fprintf(buf, "\t\tif params.%s != \"\" {\n\t\t\tquery.Set(%q, params.%s)\n\t\t}\n", field, p.Name, field)
+		}
+		fmt.Fprintf(buf, "\t}\n")
+		queryArg = "query"
+	}
+	bodyArg := "nil"
+	if op.RequestBody != nil {
+		bodyArg = "body"
+	}
+
+	method := "http.Method" + strings.ToUpper(o.method[:1]) + strings.ToLower(o.method[1:])
+	switch kind {
+	case "none":
+		fmt.Fprintf(buf, "\treturn c.do(ctx, %s, %s, %s, %s, nil)\n", method, pathExpr, queryArg, bodyArg)
+	case "raw":
+		fmt.Fprintf(buf, "\treturn c.doRaw(ctx, %s, %s, %s, %s)\n", method, pathExpr, queryArg, bodyArg)
+	default:
+		outType := strings.TrimPrefix(retType, "*")
+		fmt.Fprintf(buf, "\tvar out %s\n", outType)
+		fmt.Fprintf(buf, "\tif err := c.do(ctx, %s, %s, %s, %s, &out); err != nil {\n", method, pathExpr, queryArg, bodyArg)
+		if strings.HasPrefix(retType, "*") {
+			fmt.Fprintf(buf, "\t\treturn nil, err\n\t}\n\treturn &out, nil\n")
+		} else {
+			fmt.Fprintf(buf, "\t\treturn nil, err\n\t}\n\treturn out, nil\n")
+		}
+	}
+	fmt.Fprintf(buf, "}\n\n")
+	return nil
+}
+
+func successResponse(op *openapi.Operation) (int, *openapi.Response) {
+	for code := 200; code < 300; code++ {
+		if resp, ok := op.Responses[fmt.Sprintf("%d", code)]; ok {
+			return code, resp
+		}
+	}
+	return 0, nil
+}
+
+// goPathExpr turns "/amass/{scan_id}/dns" into `"/amass/" + url.PathEscape(scanID) + "/dns"` and
+// appends the path arguments to args
+func goPathExpr(path string, args *[]string) string {
+	var parts []string
+	last := 0
+	for _, loc := range pathParamPattern.FindAllStringSubmatchIndex(path, -1) {
+		if loc[0] > last {
+			parts = append(parts, fmt.Sprintf("%q", path[last:loc[0]]))
+		}
+		arg := paramIdent(path[loc[2]:loc[3]])
+		*args = append(*args, arg+" string")
+		parts = append(parts, "url.PathEscape("+arg+")")
+		last = loc[1]
+	}
+	if last < len(path) {
+		parts = append(parts, fmt.Sprintf("%q", path[last:]))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// exportedIdent converts snake_case or camelCase JSON names to exported Go identifiers
+func exportedIdent(name string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' || r == '.' }) {
+		if initialisms[strings.ToLower(word)] {
+			b.WriteString(strings.ToUpper(word))
+			continue
+		}
+		b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+	}
+	ident := b.String()
+	if ident == "" || !token.IsIdentifier(ident) {
+		ident = "Field" + ident
+	}
+	return ident
+}
+
+// paramIdent converts a path parameter name to an unexported Go identifier
+func paramIdent(name string) string {
+	ident := exportedIdent(name)
+	words := strings.FieldsFunc(name, func(r rune) bool { return r == '_' || r == '-' })
+	if len(words) > 0 && initialisms[strings.ToLower(words[0])] {
+		ident = strings.ToLower(words[0]) + ident[len(words[0]):]
+	} else {
+		ident = strings.ToLower(ident[:1]) + ident[1:]
+	}
+	if token.IsKeyword(ident) {
+		ident += "Param"
+	}
+	return ident
+}
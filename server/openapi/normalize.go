@@ -0,0 +1,136 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// nullWrapperKeys are the value fields of the database/sql null types as encoding/json emits them
+var nullWrapperKeys = []string{"String", "Int16", "Int32", "Int64", "Float64", "Bool", "Time", "Byte"}
+
+// NormalizeResponses rewrites responses for clients that send MediaType in the Accept header:
+// sql.Null* wrappers ({"String": "x", "Valid": true}) become plain values or null, and error
+// responses become an ErrorResponse envelope. Other clients, like the web UI, are untouched.
+func NormalizeResponses(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept"), MediaType) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		status := rec.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		body := rec.body.Bytes()
+		header := w.Header()
+
+		if status >= http.StatusBadRequest {
+			envelope := ErrorResponse{Error: errorMessage(body, status), Status: status}
+			header.Del("Content-Length")
+			header.Set("Content-Type", "application/json")
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(envelope)
+			return
+		}
+
+		if isJSONResponse(header.Get("Content-Type"), body) {
+			decoder := json.NewDecoder(bytes.NewReader(body))
+			decoder.UseNumber()
+			var payload interface{}
+			if err := decoder.Decode(&payload); err == nil {
+				if normalized, err := json.Marshal(normalizeNulls(payload)); err == nil {
+					body = append(normalized, '\n')
+					header.Set("Content-Type", "application/json")
+					header.Set("Content-Length", strconv.Itoa(len(body)))
+				}
+			}
+		}
+
+		w.WriteHeader(status)
+		w.Write(body)
+	})
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if rec.status == 0 {
+		rec.status = status
+	}
+}
+
+func (rec *responseRecorder) Write(p []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.body.Write(p)
+}
+
+// isJSONResponse also accepts untyped bodies since several handlers encode JSON without setting a
+// Content-Type, which net/http then sniffs as text/plain
+func isJSONResponse(contentType string, body []byte) bool {
+	if strings.Contains(contentType, "json") {
+		return true
+	}
+	if contentType != "" && !strings.HasPrefix(contentType, "text/plain") {
+		return false
+	}
+	trimmed := bytes.TrimSpace(body)
+	return len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') && json.Valid(trimmed)
+}
+
+// errorMessage extracts a message from a plain-text http.Error body or a JSON {"error": ...} body
+func errorMessage(body []byte, status int) string {
+	var payload map[string]interface{}
+	if json.Unmarshal(body, &payload) == nil {
+		for _, key := range []string{"error", "message"} {
+			if msg, ok := payload[key].(string); ok && msg != "" {
+				return msg
+			}
+		}
+	}
+	if msg := strings.TrimSpace(string(body)); msg != "" {
+		return msg
+	}
+	return http.StatusText(status)
+}
+
+func normalizeNulls(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		if len(value) == 2 {
+			if valid, ok := value["Valid"].(bool); ok {
+				for _, key := range nullWrapperKeys {
+					if inner, ok := value[key]; ok {
+						if !valid {
+							return nil
+						}
+						return normalizeNulls(inner)
+					}
+				}
+			}
+		}
+		for key, inner := range value {
+			value[key] = normalizeNulls(inner)
+		}
+		return value
+	case []interface{}:
+		for i, inner := range value {
+			value[i] = normalizeNulls(inner)
+		}
+		return value
+	default:
+		return v
+	}
+}
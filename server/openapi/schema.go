@@ -0,0 +1,175 @@
+package openapi
+
+import (
+	"database/sql"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	byteSliceType  = reflect.TypeOf([]byte{})
+
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// nullableTypes maps the database/sql null wrappers to the scalar they are normalized to
+var nullableTypes = map[reflect.Type]Schema{
+	reflect.TypeOf(sql.NullString{}):  {Type: "string"},
+	reflect.TypeOf(sql.NullInt16{}):   {Type: "integer", Format: "int32"},
+	reflect.TypeOf(sql.NullInt32{}):   {Type: "integer", Format: "int32"},
+	reflect.TypeOf(sql.NullInt64{}):   {Type: "integer", Format: "int64"},
+	reflect.TypeOf(sql.NullFloat64{}): {Type: "number", Format: "double"},
+	reflect.TypeOf(sql.NullBool{}):    {Type: "boolean"},
+	reflect.TypeOf(sql.NullTime{}):    {Type: "string", Format: "date-time"},
+}
+
+// schemaRegistry turns Go types into schemas, registering named structs as components
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: make(map[string]*Schema),
+		names:   make(map[reflect.Type]string),
+	}
+}
+
+// schemaFor returns the schema for the type of v, or nil when v is nil
+func (sr *schemaRegistry) schemaFor(v interface{}) *Schema {
+	if v == nil {
+		return nil
+	}
+	return sr.schemaForType(reflect.TypeOf(v))
+}
+
+func (sr *schemaRegistry) schemaForType(t reflect.Type) *Schema {
+	if scalar, ok := nullableTypes[t]; ok {
+		scalar.Nullable = true
+		return &scalar
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case rawMessageType:
+		return &Schema{}
+	case byteSliceType:
+		return &Schema{Type: "string", Format: "byte"}
+	}
+
+	// Types with their own encoding (uuid.UUID, pgtype values, ...) cannot be described by reflection
+	if t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface {
+		if t.Implements(jsonMarshalerType) {
+			return &Schema{}
+		}
+		if t.Implements(textMarshalerType) {
+			return &Schema{Type: "string"}
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		elem := sr.schemaForType(t.Elem())
+		if elem.Ref != "" {
+			return &Schema{AllOf: []*Schema{elem}, Nullable: true}
+		}
+		elem.Nullable = true
+		return elem
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: sr.schemaForType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: sr.schemaForType(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return sr.structSchema(t)
+		}
+		return sr.componentRef(t)
+	default:
+		// interface{} and anything else without a fixed shape
+		return &Schema{}
+	}
+}
+
+// componentRef registers a named struct under components/schemas and returns a reference to it
+func (sr *schemaRegistry) componentRef(t reflect.Type) *Schema {
+	if name, ok := sr.names[t]; ok {
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	name := t.Name()
+	if _, taken := sr.schemas[name]; taken {
+		// Same type name in two packages, e.g. utils.GauScanStatus and main.GauScanStatus
+		pkg := t.PkgPath()
+		pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+		name = strings.ToUpper(pkg[:1]) + pkg[1:] + name
+	}
+
+	// Register before descending so recursive types terminate
+	sr.names[t] = name
+	sr.schemas[name] = &Schema{}
+	*sr.schemas[name] = *sr.structSchema(t)
+
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (sr *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	sr.addStructFields(schema, t)
+	return schema
+}
+
+func (sr *schemaRegistry) addStructFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// Untagged embedded structs are flattened the same way encoding/json does
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				sr.addStructFields(schema, ft)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = sr.schemaForType(field.Type)
+
+		if !strings.Contains(opts, "omitempty") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package openapi
+
+// MediaType is the versioned media type API consumers send in the Accept header to receive
+// normalized responses (no sql.Null* wrappers) and JSON error envelopes.
+const MediaType = "application/vnd.ars0n.v1+json"
+
+// Document is the subset of the OpenAPI 3.0 object model used by the framework
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Servers    []Server             `json:"servers,omitempty"`
+	Tags       []Tag                `json:"tags,omitempty"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// PathItem holds the operations registered for a single path template
+type PathItem struct {
+	Get    *Operation `json:"get,omitempty"`
+	Post   *Operation `json:"post,omitempty"`
+	Put    *Operation `json:"put,omitempty"`
+	Patch  *Operation `json:"patch,omitempty"`
+	Delete *Operation `json:"delete,omitempty"`
+}
+
+// Operations returns the path item's operations keyed by upper-case HTTP method
+func (p *PathItem) Operations() map[string]*Operation {
+	ops := make(map[string]*Operation)
+	for method, op := range map[string]*Operation{
+		"GET":    p.Get,
+		"POST":   p.Post,
+		"PUT":    p.Put,
+		"PATCH":  p.Patch,
+		"DELETE": p.Delete,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+func (p *PathItem) setOperation(method string, op *Operation) bool {
+	switch method {
+	case "GET":
+		p.Get = op
+	case "POST":
+		p.Post = op
+	case "PUT":
+		p.Put = op
+	case "PATCH":
+		p.Patch = op
+	case "DELETE":
+		p.Delete = op
+	default:
+		return false
+	}
+	return true
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaItem `json:"content"`
+}
+
+type Response struct {
+	Ref         string                `json:"$ref,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Content     map[string]*MediaItem `json:"content,omitempty"`
+}
+
+type MediaItem struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas   map[string]*Schema   `json:"schemas"`
+	Responses map[string]*Response `json:"responses,omitempty"`
+}
+
+// Schema is a JSON schema as used by OpenAPI 3.0
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+}
+
+// RefName returns the component name referenced by the schema, or an empty string
+func (s *Schema) RefName() string {
+	const prefix = "#/components/schemas/"
+	if s == nil {
+		return ""
+	}
+	if s.Ref != "" && len(s.Ref) > len(prefix) && s.Ref[:len(prefix)] == prefix {
+		return s.Ref[len(prefix):]
+	}
+	if len(s.AllOf) == 1 {
+		return s.AllOf[0].RefName()
+	}
+	return ""
+}
@@ -0,0 +1,1092 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"ars0n-framework-v2-server/dnsresolver"
+	"ars0n-framework-v2-server/models"
+	"ars0n-framework-v2-server/openapi"
+	"ars0n-framework-v2-server/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// Request and response bodies shared by many handlers that decode or encode them inline
+
+type DomainScanRequest struct {
+	FQDN              string `json:"fqdn"`
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+}
+
+// GauScanRequest starts a GAU scan. providers overrides the archives selected in settings:
+// wayback, commoncrawl, otx and urlscan
+type GauScanRequest struct {
+	FQDN              string   `json:"fqdn"`
+	Providers         []string `json:"providers,omitempty"`
+	AutoScanSessionID string   `json:"auto_scan_session_id,omitempty"`
+}
+
+type CompanyScanRequest struct {
+	CompanyName       string `json:"company_name"`
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+}
+
+type ScopeTargetScanRequest struct {
+	ScopeTargetID     string `json:"scope_target_id"`
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+}
+
+// IPPortScanRequest starts an IP/Port scan with the scope target's saved scan profile. A port
+// profile in the request overrides the saved one: web, top-100, top-1000, full or custom with a
+// custom_ports list such as "22,80,8000-8100"
+type IPPortScanRequest struct {
+	ScopeTargetID     string `json:"scope_target_id"`
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+	PortProfile       string `json:"port_profile,omitempty"`
+	CustomPorts       string `json:"custom_ports,omitempty"`
+}
+
+type DomainsScanRequest struct {
+	Domains           []string `json:"domains"`
+	AutoScanSessionID string   `json:"auto_scan_session_id,omitempty"`
+}
+
+type AutoScanSessionRequest struct {
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+}
+
+type MetabigorASNScanRequest struct {
+	ASNNumber         string `json:"asn_number"`
+	ScanType          string `json:"scan_type"`
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+}
+
+type MetabigorIPIntelligenceRequest struct {
+	IPAddresses       []string `json:"ip_addresses"`
+	ScanType          string   `json:"scan_type"`
+	AutoScanSessionID string   `json:"auto_scan_session_id,omitempty"`
+}
+
+type CompanyMetaDataScanRequest struct {
+	ScopeTargetID string `json:"scope_target_id"`
+	IPPortScanID  string `json:"ip_port_scan_id"`
+}
+
+type URLsScanRequest struct {
+	URLs []string `json:"urls"`
+}
+
+type WordlistScanRequest struct {
+	Wordlist string `json:"wordlist"`
+}
+
+// RecursiveEnumerationScanRequest starts recursive enumeration. The limits override the auto
+// scan config, and min_children is how many known names a sub-zone without its own NS or SOA
+// needs to be picked.
+type RecursiveEnumerationScanRequest struct {
+	FQDN              string `json:"fqdn"`
+	MaxDepth          int    `json:"max_depth,omitempty"`
+	MaxZones          int    `json:"max_zones,omitempty"`
+	MinChildren       int    `json:"min_children,omitempty"`
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+}
+
+// PermutationScanRequest starts a permutation scan. max_candidates overrides the cap from the
+// auto scan config.
+type PermutationScanRequest struct {
+	FQDN              string `json:"fqdn"`
+	MaxCandidates     int    `json:"max_candidates,omitempty"`
+	AutoScanSessionID string `json:"auto_scan_session_id,omitempty"`
+}
+
+// DNSAuditScanRequest starts a DNS audit. domains overrides the root domain of a wildcard
+// target or the company domains dnsx found name servers for.
+type DNSAuditScanRequest struct {
+	ScopeTargetID string   `json:"scope_target_id"`
+	Domains       []string `json:"domains,omitempty"`
+}
+
+// EmailSecurityScanRequest starts an email security analysis. domains overrides the root
+// domain of a wildcard target or the consolidated company domains.
+type EmailSecurityScanRequest struct {
+	ScopeTargetID string   `json:"scope_target_id"`
+	Domains       []string `json:"domains,omitempty"`
+}
+
+// ContentDiscoveryScanRequest starts content discovery. urls defaults to the scope target's
+// live target URLs and wordlist_ids to the wordlists matching each URL's technologies.
+type ContentDiscoveryScanRequest struct {
+	ScopeTargetID string   `json:"scope_target_id"`
+	URLs          []string `json:"urls,omitempty"`
+	MaxDepth      *int     `json:"max_depth,omitempty"`
+	WordlistIDs   []string `json:"wordlist_ids,omitempty"`
+}
+
+type ContentWordlistRequest struct {
+	Name  string   `json:"name"`
+	Tags  []string `json:"tags"`
+	Words []string `json:"words"`
+}
+
+type ContentWordlistTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+type FingerprintURLRequest struct {
+	URL string `json:"url"`
+}
+
+type ScreenshotScanRequest struct {
+	ScopeTargetID  string   `json:"scope_target_id"`
+	URLs           []string `json:"urls,omitempty"`
+	ViewportWidth  int      `json:"viewport_width,omitempty"`
+	ViewportHeight int      `json:"viewport_height,omitempty"`
+	FullPage       bool     `json:"full_page,omitempty"`
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"`
+	Concurrency    int      `json:"concurrency,omitempty"`
+}
+
+// SecurityHeaderScanRequest starts a security header analysis. urls overrides the live web
+// servers of the scope target.
+type SecurityHeaderScanRequest struct {
+	ScopeTargetID string   `json:"scope_target_id"`
+	URLs          []string `json:"urls,omitempty"`
+}
+
+type ROIScoreRequest struct {
+	ROIScore int `json:"roi_score"`
+}
+
+type DNSResolversRequest struct {
+	Resolvers []string `json:"resolvers"`
+}
+
+type ScopeTargetDomainRequest struct {
+	ScopeTargetID string `json:"scope_target_id"`
+	Domain        string `json:"domain"`
+}
+
+type BurpSendRequest struct {
+	ScopeTargetID      string   `json:"scope_target_id"`
+	TargetURLIDs       []string `json:"target_url_ids"`
+	Mode               string   `json:"mode,omitempty"`
+	ScanConfigurations []string `json:"scan_configurations,omitempty"`
+}
+
+type BurpSendResponse struct {
+	Mode    string                 `json:"mode"`
+	Results []utils.BurpSendResult `json:"results"`
+}
+
+type BurpStatusResponse struct {
+	ProxyEnabled   bool   `json:"proxy_enabled"`
+	ProxyAddress   string `json:"proxy_address"`
+	ProxyReachable bool   `json:"proxy_reachable"`
+	APIReachable   bool   `json:"api_reachable"`
+	APIError       string `json:"api_error"`
+}
+
+type ScanStartedResponse struct {
+	ScanID string `json:"scan_id"`
+}
+
+type SessionStartedResponse struct {
+	SessionID string `json:"session_id"`
+}
+
+type MessageResponse struct {
+	Message string `json:"message"`
+}
+
+type StatusResponse struct {
+	Status string `json:"status"`
+}
+
+type CountResponse struct {
+	Count int `json:"count"`
+}
+
+type CloudDomainsResponse struct {
+	AWSDomains   []string `json:"aws_domains"`
+	GCPDomains   []string `json:"gcp_domains"`
+	AzureDomains []string `json:"azure_domains"`
+}
+
+type AttackSurfaceAssetsResponse struct {
+	Assets []utils.AttackSurfaceAsset `json:"assets"`
+	Total  int                        `json:"total"`
+}
+
+type UserSettings struct {
+	AmassRateLimit            int    `json:"amass_rate_limit"`
+	HttpxRateLimit            int    `json:"httpx_rate_limit"`
+	SubfinderRateLimit        int    `json:"subfinder_rate_limit"`
+	GauRateLimit              int    `json:"gau_rate_limit"`
+	Sublist3rRateLimit        int    `json:"sublist3r_rate_limit"`
+	CTLRateLimit              int    `json:"ctl_rate_limit"`
+	ShufflednsRateLimit       int    `json:"shuffledns_rate_limit"`
+	CeWLRateLimit             int    `json:"cewl_rate_limit"`
+	GospiderRateLimit         int    `json:"gospider_rate_limit"`
+	SubdomainizerRateLimit    int    `json:"subdomainizer_rate_limit"`
+	NucleiScreenshotRateLimit int    `json:"nuclei_screenshot_rate_limit"`
+	CustomUserAgent           string `json:"custom_user_agent"`
+	CustomHeader              string `json:"custom_header"`
+	BurpProxyIP               string `json:"burp_proxy_ip"`
+	BurpProxyPort             int    `json:"burp_proxy_port"`
+	BurpAPIIP                 string `json:"burp_api_ip"`
+	BurpAPIPort               int    `json:"burp_api_port"`
+	BurpAPIKey                string `json:"burp_api_key"`
+	BurpProxyEnabled          bool   `json:"burp_proxy_enabled"`
+	GauProviders              string `json:"gau_providers"`
+}
+
+type SuccessResponse struct {
+	Success bool `json:"success"`
+}
+
+type AutoScanConfig struct {
+	Amass                     bool `json:"amass"`
+	Sublist3r                 bool `json:"sublist3r"`
+	Assetfinder               bool `json:"assetfinder"`
+	Gau                       bool `json:"gau"`
+	Ctl                       bool `json:"ctl"`
+	Subfinder                 bool `json:"subfinder"`
+	ConsolidateHttpxRound1    bool `json:"consolidate_httpx_round1"`
+	RecursiveEnumeration      bool `json:"recursive_enumeration"`
+	Shuffledns                bool `json:"shuffledns"`
+	Cewl                      bool `json:"cewl"`
+	Permutations              bool `json:"permutations"`
+	ConsolidateHttpxRound2    bool `json:"consolidate_httpx_round2"`
+	Gospider                  bool `json:"gospider"`
+	Subdomainizer             bool `json:"subdomainizer"`
+	ConsolidateHttpxRound3    bool `json:"consolidate_httpx_round3"`
+	NucleiScreenshot          bool `json:"nuclei_screenshot"`
+	Metadata                  bool `json:"metadata"`
+	MaxConsolidatedSubdomains int  `json:"maxConsolidatedSubdomains"`
+	MaxLiveWebServers         int  `json:"maxLiveWebServers"`
+	MaxPermutationCandidates  int  `json:"maxPermutationCandidates"`
+	RecursiveMaxDepth         int  `json:"recursiveMaxDepth"`
+	RecursiveMaxZones         int  `json:"recursiveMaxZones"`
+}
+
+type AutoScanSessionStartRequest struct {
+	ScopeTargetID  string         `json:"scope_target_id"`
+	ConfigSnapshot AutoScanConfig `json:"config_snapshot"`
+}
+
+type AutoScanStateRequest struct {
+	CurrentStep string `json:"current_step"`
+	IsPaused    bool   `json:"is_paused"`
+	IsCancelled bool   `json:"is_cancelled"`
+}
+
+type AutoScanFinalStatsRequest struct {
+	FinalConsolidatedSubdomains *int   `json:"final_consolidated_subdomains"`
+	FinalLiveWebServers         *int   `json:"final_live_web_servers"`
+	ScopeTargetID               string `json:"scope_target_id"`
+}
+
+type ConsolidatedSubdomainsResponse struct {
+	Count            int      `json:"count"`
+	Subdomains       []string `json:"subdomains"`
+	WildcardFiltered int      `json:"wildcard_filtered"`
+}
+
+// TargetURL lists the commonly used fields of a live web server returned by the target-urls route
+type TargetURL struct {
+	ID            string   `json:"id"`
+	URL           string   `json:"url"`
+	ScopeTargetID string   `json:"scope_target_id"`
+	StatusCode    int      `json:"status_code"`
+	Title         string   `json:"title"`
+	WebServer     string   `json:"web_server"`
+	Technologies  []string `json:"technologies"`
+	ContentLength int      `json:"content_length"`
+	ROIScore      int      `json:"roi_score"`
+	CreatedAt     string   `json:"created_at"`
+}
+
+type CompanyDomainsConfig struct {
+	Domains                []string `json:"domains"`
+	IncludeWildcardResults bool     `json:"include_wildcard_results"`
+	WildcardDomains        []string `json:"wildcard_domains"`
+}
+
+type AmassIntelConfig struct {
+	NetworkRanges []string `json:"network_ranges"`
+}
+
+type ConfigSavedResponse struct {
+	Success  bool   `json:"success"`
+	ConfigID string `json:"config_id"`
+	Message  string `json:"message"`
+}
+
+type CloudEnumConfig struct {
+	Keywords            []string            `json:"keywords"`
+	Threads             int                 `json:"threads"`
+	EnabledPlatforms    map[string]bool     `json:"enabled_platforms"`
+	CustomDNSServer     string              `json:"custom_dns_server"`
+	DNSResolverMode     string              `json:"dns_resolver_mode"`
+	ResolverConfig      string              `json:"resolver_config"`
+	AdditionalResolvers string              `json:"additional_resolvers"`
+	MutationsFilePath   string              `json:"mutations_file_path"`
+	BruteFilePath       string              `json:"brute_file_path"`
+	ResolverFilePath    string              `json:"resolver_file_path"`
+	SelectedServices    map[string][]string `json:"selected_services"`
+	SelectedRegions     map[string][]string `json:"selected_regions"`
+}
+
+type KatanaCompanyConfig struct {
+	ID                      string    `json:"id"`
+	ScopeTargetID           string    `json:"scope_target_id"`
+	SelectedDomains         []string  `json:"selected_domains"`
+	IncludeWildcardResults  bool      `json:"include_wildcard_results"`
+	SelectedWildcardDomains []string  `json:"selected_wildcard_domains"`
+	SelectedLiveWebServers  []string  `json:"selected_live_web_servers"`
+	CreatedAt               time.Time `json:"created_at"`
+	UpdatedAt               time.Time `json:"updated_at"`
+}
+
+// NucleiConfig lists the saved targets and templates. created_at is null until a config is saved.
+type NucleiConfig struct {
+	Targets           []string                 `json:"targets"`
+	Templates         []string                 `json:"templates"`
+	Severities        []string                 `json:"severities"`
+	UploadedTemplates []map[string]interface{} `json:"uploaded_templates"`
+	CreatedAt         *time.Time               `json:"created_at"`
+}
+
+type NucleiScan struct {
+	ScanID        string    `json:"scan_id"`
+	Status        string    `json:"status"`
+	Targets       []string  `json:"targets"`
+	Templates     []string  `json:"templates"`
+	Result        string    `json:"result"`
+	Error         string    `json:"error"`
+	CreatedAt     time.Time `json:"created_at"`
+	ExecutionTime string    `json:"execution_time"`
+}
+
+type NucleiScanStatus struct {
+	ScanID        string    `json:"scan_id"`
+	Status        string    `json:"status"`
+	Result        string    `json:"result"`
+	Error         string    `json:"error"`
+	ExecutionTime string    `json:"execution_time"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type NucleiScanStartedResponse struct {
+	ScanID  string `json:"scan_id"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+}
+
+type AmassEnumCompanyScan struct {
+	ID            string    `json:"id"`
+	ScanID        string    `json:"scan_id"`
+	Domains       []string  `json:"domains"`
+	Status        string    `json:"status"`
+	Result        string    `json:"result"`
+	Error         string    `json:"error"`
+	Stdout        string    `json:"stdout"`
+	Stderr        string    `json:"stderr"`
+	Command       string    `json:"command"`
+	ExecutionTime string    `json:"execution_time"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type AmassEnumCloudDomain struct {
+	ID            string    `json:"id"`
+	RootDomain    string    `json:"root_domain"`
+	Domain        string    `json:"domain"`
+	Type          string    `json:"type"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastScannedAt time.Time `json:"last_scanned_at"`
+}
+
+type AmassEnumRawResult struct {
+	ID            string    `json:"id"`
+	Domain        string    `json:"domain"`
+	RawOutput     string    `json:"raw_output"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastScannedAt time.Time `json:"last_scanned_at"`
+}
+
+type KatanaCompanyCloudAsset struct {
+	ID            string    `json:"id"`
+	RootDomain    string    `json:"root_domain"`
+	Domain        string    `json:"domain"`
+	URL           string    `json:"url"`
+	Type          string    `json:"type"`
+	Service       string    `json:"service"`
+	Description   string    `json:"description"`
+	SourceURL     string    `json:"source_url"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastScannedAt time.Time `json:"last_scanned_at"`
+}
+
+type IPPortScansResponse struct {
+	Scans []utils.IPPortScan `json:"scans"`
+	Count int                `json:"count"`
+}
+
+type CompanyMetaDataScan struct {
+	ScanID        string `json:"scan_id"`
+	ScopeTargetID string `json:"scope_target_id"`
+	IPPortScanID  string `json:"ip_port_scan_id"`
+	Status        string `json:"status"`
+	ErrorMessage  string `json:"error_message"`
+	ExecutionTime string `json:"execution_time"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+// CompanyMetaDataResult is a target URL found by a company metadata scan, with its TLS and DNS details
+type CompanyMetaDataResult struct {
+	ID                  string   `json:"id"`
+	URL                 string   `json:"url"`
+	ScopeTargetID       string   `json:"scope_target_id"`
+	StatusCode          int      `json:"status_code"`
+	Title               string   `json:"title"`
+	WebServer           string   `json:"web_server"`
+	Technologies        []string `json:"technologies"`
+	ContentLength       int      `json:"content_length"`
+	FindingsJSON        string   `json:"findings_json"`
+	KatanaResults       string   `json:"katana_results"`
+	FfufResults         string   `json:"ffuf_results"`
+	HTTPResponse        string   `json:"http_response"`
+	HTTPResponseHeaders string   `json:"http_response_headers"`
+	HasDeprecatedTLS    bool     `json:"has_deprecated_tls"`
+	HasExpiredSSL       bool     `json:"has_expired_ssl"`
+	HasMismatchedSSL    bool     `json:"has_mismatched_ssl"`
+	HasRevokedSSL       bool     `json:"has_revoked_ssl"`
+	HasSelfSignedSSL    bool     `json:"has_self_signed_ssl"`
+	HasUntrustedRootSSL bool     `json:"has_untrusted_root_ssl"`
+	DNSARecords         []string `json:"dns_a_records"`
+	DNSAAAARecords      []string `json:"dns_aaaa_records"`
+	DNSCNAMERecords     []string `json:"dns_cname_records"`
+	DNSMXRecords        []string `json:"dns_mx_records"`
+	DNSTXTRecords       []string `json:"dns_txt_records"`
+	DNSNSRecords        []string `json:"dns_ns_records"`
+	DNSPTRRecords       []string `json:"dns_ptr_records"`
+	DNSSRVRecords       []string `json:"dns_srv_records"`
+	ROIScore            float64  `json:"roi_score"`
+	CreatedAt           string   `json:"created_at"`
+	ScreenshotID        string   `json:"screenshot_id"`
+}
+
+type ConsolidatedCompanyDomainsResponse struct {
+	Count   int      `json:"count"`
+	Domains []string `json:"domains"`
+}
+
+type ConsolidatedNetworkRangesResponse struct {
+	Count         int                              `json:"count"`
+	NetworkRanges []utils.ConsolidatedNetworkRange `json:"network_ranges"`
+}
+
+type CompanyDomainsResponse struct {
+	Domains []string `json:"domains"`
+	Count   int      `json:"count"`
+}
+
+// CompanyDomain is a domain added by hand for Google dorking or reverse whois
+type CompanyDomain struct {
+	ID            string    `json:"id"`
+	ScopeTargetID string    `json:"scope_target_id"`
+	Domain        string    `json:"domain"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type CompanyDomainCreatedResponse struct {
+	ID            string `json:"id"`
+	ScopeTargetID string `json:"scope_target_id"`
+	Domain        string `json:"domain"`
+	Success       bool   `json:"success"`
+}
+
+type SuccessMessageResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+}
+
+type DeletedDomainsResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+type DeletedCountResponse struct {
+	Message      string `json:"message"`
+	DeletedCount int64  `json:"deleted_count"`
+}
+
+type AiAPIKeyRequest struct {
+	Provider  string                 `json:"provider"`
+	KeyName   string                 `json:"api_key_name"`
+	KeyValues map[string]interface{} `json:"key_values"`
+}
+
+// AiAPIKey is a stored AI provider key. Every key value but the last four characters is masked.
+type AiAPIKey struct {
+	ID        string                 `json:"id"`
+	Provider  string                 `json:"provider"`
+	KeyName   string                 `json:"api_key_name"`
+	KeyValues map[string]interface{} `json:"key_values"`
+	CreatedAt time.Time              `json:"created_at"`
+	UpdatedAt time.Time              `json:"updated_at"`
+}
+
+// APIKeyRequest stores a tool API key. Censys takes app_id and app_secret, every other tool api_key.
+type APIKeyRequest struct {
+	ToolName  string `json:"tool_name"`
+	KeyName   string `json:"api_key_name"`
+	KeyValues struct {
+		APIKey    string `json:"api_key"`
+		AppID     string `json:"app_id"`
+		AppSecret string `json:"app_secret"`
+	} `json:"key_values"`
+}
+
+// AutoScanState is the step an auto scan is on. A target that never ran one is IDLE and has no id.
+type AutoScanState struct {
+	ID            string     `json:"id,omitempty"`
+	ScopeTargetID string     `json:"scope_target_id"`
+	CurrentStep   string     `json:"current_step"`
+	IsPaused      bool       `json:"is_paused"`
+	IsCancelled   bool       `json:"is_cancelled"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+type AutoScanStateResponse struct {
+	Success       bool   `json:"success"`
+	ScopeTargetID string `json:"scope_target_id"`
+	CurrentStep   string `json:"current_step"`
+	IsPaused      bool   `json:"is_paused"`
+	IsCancelled   bool   `json:"is_cancelled"`
+}
+
+type AutoScanSession struct {
+	ID                          string          `json:"id"`
+	ScopeTargetID               string          `json:"scope_target_id"`
+	ConfigSnapshot              AutoScanConfig  `json:"config_snapshot"`
+	Status                      string          `json:"status"`
+	StartedAt                   time.Time       `json:"started_at"`
+	EndedAt                     *time.Time      `json:"ended_at"`
+	StepsRun                    json.RawMessage `json:"steps_run"`
+	ErrorMessage                *string         `json:"error_message"`
+	FinalConsolidatedSubdomains *int            `json:"final_consolidated_subdomains"`
+	FinalLiveWebServers         *int            `json:"final_live_web_servers"`
+}
+
+type AutoScanSessionStatusResponse struct {
+	Success bool   `json:"success"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+type DatabaseImportResponse struct {
+	Message              string `json:"message"`
+	ImportedScopeTargets int    `json:"imported_scope_targets"`
+	ImportedTables       int    `json:"imported_tables"`
+	TotalRecords         int    `json:"total_records"`
+}
+
+// ExportFileSummary counts the records per table of an export file without importing it
+type ExportFileSummary struct {
+	Metadata          utils.ExportMetadata   `json:"metadata"`
+	ScopeTargets      int                    `json:"scope_targets"`
+	Tables            map[string]int         `json:"tables"`
+	AmassEnumAnalysis map[string]interface{} `json:"amass_enum_analysis"`
+}
+
+type ExportScopeTarget struct {
+	ID          string    `json:"id"`
+	Type        string    `json:"type"`
+	ScopeTarget string    `json:"scope_target"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// DefectDojoExport is a Generic Findings Import document
+type DefectDojoExport struct {
+	Findings []map[string]interface{} `json:"findings"`
+}
+
+// DefectDojoImportResponse counts the stored updates and those without a unique_id_from_tool,
+// and lists the IDs that match no local finding
+type DefectDojoImportResponse struct {
+	Applied   int      `json:"applied"`
+	Skipped   int      `json:"skipped"`
+	Unmatched []string `json:"unmatched"`
+}
+
+// domainScan documents the run/status/list trio shared by the single-domain tools
+func domainScan(endpoints openapi.Endpoints, run, status, list string, scan interface{}) {
+	endpoints[run] = openapi.Endpoint{Request: DomainScanRequest{}, Response: ScanStartedResponse{}}
+	endpoints[status] = openapi.Endpoint{Response: scan}
+	endpoints[list] = openapi.Endpoint{Response: sliceOf(scan)}
+}
+
+// companyScan documents the run/status/list trio shared by the company tools
+func companyScan(endpoints openapi.Endpoints, run, status, list string, scan interface{}) {
+	endpoints[run] = openapi.Endpoint{Request: CompanyScanRequest{}, Response: ScanStartedResponse{}}
+	endpoints[status] = openapi.Endpoint{Response: scan}
+	endpoints[list] = openapi.Endpoint{Response: sliceOf(scan)}
+}
+
+func sliceOf(v interface{}) interface{} {
+	return reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(v)), 0, 0).Interface()
+}
+
+// apiEndpoints documents request and response bodies for the routes registered in newRouter.
+// Every route must be listed with its response body, download type or NoContent, or the spec
+// fails to build.
+func apiEndpoints() openapi.Endpoints {
+	endpoints := openapi.Endpoints{
+		// Scope targets
+		"CreateScopeTarget":         {Request: utils.RequestPayload{}, Response: MessageResponse{}},
+		"ReadScopeTarget":           {Response: []utils.ResponsePayload{}},
+		"DeleteScopeTarget":         {Response: MessageResponse{}},
+		"ActivateScopeTarget":       {Response: MessageResponse{}},
+		"GetAllScansForScopeTarget": {Response: []utils.ScanSummary{}},
+
+		// Amass
+		"GetDNSRecords":       {Response: []utils.DNSRecord{}},
+		"GetIPs":              {Response: []string{}},
+		"GetSubdomains":       {Response: []string{}},
+		"GetCloudDomains":     {Response: CloudDomainsResponse{}},
+		"GetServiceProviders": {Response: []utils.ServiceProviderResponse{}},
+		"GetASNs":             {Response: []utils.ASNResponse{}},
+		"GetSubnets":          {Response: []utils.SubnetResponse{}},
+
+		"GetIntelNetworkRanges":       {Response: []utils.IntelNetworkRangeResponse{}},
+		"GetIntelASNData":             {Response: []utils.IntelASNResponse{}},
+		"DeleteIntelNetworkRange":     {Response: MessageResponse{}},
+		"DeleteAllIntelNetworkRanges": {Response: DeletedCountResponse{}},
+		"GetMetabigorNetworkRanges":   {Response: []utils.MetabigorNetworkRange{}},
+		"GetMetabigorASNData":         {Response: []utils.MetabigorASNData{}},
+
+		"DeleteMetabigorNetworkRange":     {Response: MessageResponse{}},
+		"DeleteAllMetabigorNetworkRanges": {Response: DeletedCountResponse{}},
+		"RunMetabigorNetdScan":            {Request: CompanyScanRequest{}, Response: ScanStartedResponse{}},
+		"RunMetabigorASNScan":             {Request: MetabigorASNScanRequest{}, Response: ScanStartedResponse{}},
+		"RunMetabigorIPIntelligence":      {Request: MetabigorIPIntelligenceRequest{}, Response: ScanStartedResponse{}},
+		"GetMetabigorIPIntelligence":      {Response: []utils.MetabigorIPIntelligence{}},
+
+		// Wordlist based brute forcing
+		"RunCeWLScansForUrls":       {Request: URLsScanRequest{}, Response: ScanStartedResponse{}},
+		"RunShuffleDNSWithWordlist": {Request: WordlistScanRequest{}, Response: ScanStartedResponse{}},
+		"GET /cewl-wordlist/{scan_id}": {
+			OperationID: "GetCeWLWordlistScanStatus",
+			Response:    utils.ShuffleDNSScanStatus{},
+		},
+		"GetShuffleDNSCustomScansForScopeTarget": {Response: []utils.ShuffleDNSScanStatus{}},
+		"RunPermutationScan":                     {Request: PermutationScanRequest{}, Response: ScanStartedResponse{}},
+		"GetPermutationScanStatus":               {Response: utils.PermutationScanStatus{}},
+		"GetPermutationScansForScopeTarget":      {Response: []utils.PermutationScanStatus{}},
+
+		// Recursive enumeration of sub-zones
+		"RunRecursiveEnumerationScan":                {Request: RecursiveEnumerationScanRequest{}, Response: ScanStartedResponse{}},
+		"GetRecursiveEnumerationScanStatus":          {Response: utils.RecursiveEnumerationScan{}},
+		"GetRecursiveEnumerationScansForScopeTarget": {Response: []utils.RecursiveEnumerationScan{}},
+
+		// Attack surface
+		"ConsolidateAttackSurface":    {Response: utils.ConsolidationResult{}},
+		"GetAttackSurfaceAssetCounts": {Response: map[string]int{}},
+		"GetAttackSurfaceAssets":      {Response: AttackSurfaceAssetsResponse{}},
+
+		// Consolidation and live web servers
+		"HandleConsolidateSubdomains": {Response: ConsolidatedSubdomainsResponse{}},
+		"GetConsolidatedSubdomains":   {Response: ConsolidatedSubdomainsResponse{}},
+		"GetWildcardDNSZones":         {Response: []utils.WildcardDNSZone{}},
+		"GetTargetURLsForScopeTarget": {Response: []TargetURL{}},
+
+		// Subdomain takeover
+		"RunSubdomainTakeoverScan":                {Request: ScopeTargetScanRequest{}, Response: ScanStartedResponse{}},
+		"GetSubdomainTakeoverScanStatus":          {Response: utils.SubdomainTakeoverScan{}},
+		"GetSubdomainTakeoverScansForScopeTarget": {Response: []utils.SubdomainTakeoverScan{}},
+		"GetSubdomainTakeoverFindings":            {Response: []utils.SubdomainTakeoverFinding{}},
+
+		// URL corpus
+		"GetURLCorpus": {
+			Response: utils.CorpusURLPage{},
+			Query: []openapi.QueryParam{
+				{Name: "page", Description: "Page number, starting at 1", Type: "integer"},
+				{Name: "page_size", Description: "URLs per page, up to 1000 (default 100)", Type: "integer"},
+				{Name: "category", Description: "One of: api, static, javascript, interesting_file, parameterized, page"},
+				{Name: "host", Description: "Only URLs on this host"},
+				{Name: "extension", Description: "Only URLs with this file extension"},
+				{Name: "param", Description: "Only URLs that take this query parameter"},
+				{Name: "search", Description: "Case insensitive match against the path pattern and example URL"},
+			},
+		},
+
+		// DNS audit
+		"RunDNSAuditScan":                {Request: DNSAuditScanRequest{}, Response: ScanStartedResponse{}},
+		"GetDNSAuditScanStatus":          {Response: utils.DNSAuditScan{}},
+		"GetDNSAuditScansForScopeTarget": {Response: []utils.DNSAuditScan{}},
+		"GetDNSAuditFindings":            {Response: []utils.DNSAuditFinding{}},
+
+		// Email security
+		"RunEmailSecurityScan":                {Request: EmailSecurityScanRequest{}, Response: ScanStartedResponse{}},
+		"GetEmailSecurityScanStatus":          {Response: utils.EmailSecurityScan{}},
+		"GetEmailSecurityScansForScopeTarget": {Response: []utils.EmailSecurityScan{}},
+		"GetEmailSecuritySummary":             {Response: utils.EmailSecuritySummary{}},
+
+		// Parameter mining
+		"RunParameterMiningScan":                {Request: ScopeTargetScanRequest{}, Response: ScanStartedResponse{}},
+		"GetParameterMiningScanStatus":          {Response: utils.ParameterMiningScan{}},
+		"GetParameterMiningScansForScopeTarget": {Response: []utils.ParameterMiningScan{}},
+		"GetURLParameters": {
+			Response: []utils.URLParameter{},
+			Query: []openapi.QueryParam{
+				{Name: "host", Description: "Only parameters seen on this host"},
+				{Name: "tag", Description: "One of: ssrf, open_redirect, lfi, idor, sqli"},
+				{Name: "name", Description: "Only parameters with this name"},
+			},
+		},
+		"ExportURLParameters": {
+			ContentType: "application/octet-stream",
+			Description: "Downloads the parameter inventory. The urls format lists one URL per parameter with its value set to FUZZ.",
+			Query: []openapi.QueryParam{
+				{Name: "format", Description: "urls (default), csv or json"},
+				{Name: "host", Description: "Only parameters seen on this host"},
+				{Name: "tag", Description: "One of: ssrf, open_redirect, lfi, idor, sqli"},
+				{Name: "name", Description: "Only parameters with this name"},
+			},
+		},
+
+		// JavaScript analysis
+		"RunJSAnalysisScan":                {Request: ScopeTargetScanRequest{}, Response: ScanStartedResponse{}},
+		"GetJSAnalysisScanStatus":          {Response: utils.JSAnalysisScan{}},
+		"GetJSAnalysisScansForScopeTarget": {Response: []utils.JSAnalysisScan{}},
+		"GetJSFiles": {
+			Response: []utils.JSFile{},
+			Query:    []openapi.QueryParam{{Name: "changed", Description: "Only files whose content changed between scans", Type: "boolean"}},
+		},
+		"GetJSSecrets":     {Response: []utils.JSSecret{}},
+		"GetJSSourceFiles": {Response: []utils.JSSourceFile{}},
+		"DownloadJSSourceFiles": {
+			ContentType: "application/zip",
+			Description: "Zip archive of the sources reconstructed from the file's source map.",
+		},
+
+		// Content discovery
+		"RunContentDiscoveryScan":                {Request: ContentDiscoveryScanRequest{}, Response: ScanStartedResponse{}},
+		"GetContentDiscoveryScanStatus":          {Response: utils.ContentDiscoveryScan{}},
+		"GetContentDiscoveryScansForScopeTarget": {Response: []utils.ContentDiscoveryScan{}},
+		"GetContentDiscoveryResults": {
+			Response: []utils.ContentDiscoveryResult{},
+			Query: []openapi.QueryParam{
+				{Name: "base_url", Description: "Only results found below this target URL"},
+				{Name: "status", Description: "Only results with this status code", Type: "integer"},
+			},
+		},
+		"GetContentWordlists": {Response: []utils.ContentWordlist{}},
+		"UploadContentWordlist": {
+			Request:     ContentWordlistRequest{},
+			Response:    utils.ContentWordlist{},
+			Description: "Also accepts a multipart/form-data upload with file, name and comma separated tags fields.",
+		},
+		"UpdateContentWordlistTags": {Request: ContentWordlistTagsRequest{}, Response: utils.ContentWordlist{}},
+		"DeleteContentWordlist":     {Status: http.StatusNoContent},
+
+		// Technology fingerprinting
+		"GetTechnologyInventory": {
+			Response: []utils.TechnologyInventoryEntry{},
+			Query:    []openapi.QueryParam{{Name: "category", Description: "Only technologies in this category, e.g. CMS"}},
+		},
+		"FingerprintURL":          {Request: FingerprintURLRequest{}, Response: []utils.DetectedTechnology{}},
+		"GetFingerprintRuleFiles": {Response: []utils.FingerprintRuleFile{}},
+		"UploadFingerprintRuleFile": {
+			Response:    utils.FingerprintRuleFile{},
+			Description: "Accepts a Wappalyzer-format rule file as the JSON body, named by the name query parameter, or as a multipart/form-data upload with file and name fields.",
+			Query:       []openapi.QueryParam{{Name: "name", Description: "Rule file name, replacing a file of the same name"}},
+		},
+		"DeleteFingerprintRuleFile": {Status: http.StatusNoContent},
+
+		// Web fingerprinting
+		"RunWebFingerprintScan":                {Request: ScopeTargetScanRequest{}, Response: ScanStartedResponse{}},
+		"GetWebFingerprintScanStatus":          {Response: utils.WebFingerprintScan{}},
+		"GetWebFingerprintScansForScopeTarget": {Response: []utils.WebFingerprintScan{}},
+		"GetWebFingerprints":                   {Response: []utils.WebFingerprint{}},
+		"GetWebFingerprintClusters": {
+			Response: []utils.WebFingerprintCluster{},
+			Query: []openapi.QueryParam{
+				{Name: "by", Description: "Fingerprint to group by: favicon, html_structure, tls_cert, ja3s or all (default)"},
+				{Name: "min_size", Description: "Smallest group returned (default 2)", Type: "integer"},
+			},
+		},
+		"MatchShodanExport": {
+			Response:    []utils.ShodanFaviconMatch{},
+			Description: "Accepts a decompressed Shodan download, one JSON banner per line, as the request body or as a multipart/form-data upload with a file field.",
+		},
+
+		// Screenshot storage
+		"GetScreenshotImage": {
+			ContentType: "image/png",
+			Description: "Serves the stored capture in its original format, usually PNG.",
+		},
+		"GetScreenshotThumbnail": {ContentType: "image/jpeg"},
+		"GetScreenshots":         {Response: []utils.Screenshot{}},
+		"GetScreenshotClusters": {
+			Response: []utils.ScreenshotCluster{},
+			Query: []openapi.QueryParam{
+				{Name: "threshold", Description: "Largest perceptual hash distance in bits treated as similar (default 8)", Type: "integer"},
+				{Name: "min_size", Description: "Smallest group returned (default 2)", Type: "integer"},
+			},
+		},
+		"RunScreenshotScan": {
+			Request:     ScreenshotScanRequest{},
+			Response:    ScanStartedResponse{},
+			Description: "Captures the given URLs, or every live target URL of the scope target, in the headless Chrome container. Defaults: 1280x800 viewport, 30 second timeout, 5 pages at a time.",
+		},
+		"GetScreenshotScanStatus":          {Response: utils.ScreenshotScan{}},
+		"GetScreenshotScansForScopeTarget": {Response: []utils.ScreenshotScan{}},
+
+		// Response history
+		"GetResponseTimeline": {
+			Response: []utils.ResponseSnapshot{},
+			Query:    []openapi.QueryParam{{Name: "url", Description: "Target URL whose history is returned"}},
+		},
+		"GetResponseChanges": {
+			Response: []utils.ResponseSnapshot{},
+			Query: []openapi.QueryParam{
+				{Name: "since", Description: "Only changes first seen at or after this RFC 3339 time"},
+				{Name: "all", Description: "Set to true to include changes below the meaningful threshold", Type: "boolean"},
+			},
+		},
+		"GetResponseSnapshotBody": {
+			ContentType: "text/plain",
+			Description: "Serves the archived body as plain text whatever its original content type.",
+		},
+
+		// Security headers
+		"RunSecurityHeaderScan": {
+			Request:     SecurityHeaderScanRequest{},
+			Response:    ScanStartedResponse{},
+			Description: "Requests every live web server from an arbitrary Origin, then from null and look-alike origins, and grades its CSP, HSTS, framing, CORS and cookie flags. URLs that do not answer are graded from the headers stored by the metadata scan.",
+		},
+		"GetSecurityHeaderScanStatus":          {Response: utils.SecurityHeaderScan{}},
+		"GetSecurityHeaderScansForScopeTarget": {Response: []utils.SecurityHeaderScan{}},
+		"GetSecurityHeaderSummary":             {Response: utils.SecurityHeaderSummary{}},
+
+		// Screenshots, metadata and investigation
+		"RunNucleiScreenshotScan": {Request: AutoScanSessionRequest{}, Response: ScanStartedResponse{}},
+		"POST /scopetarget/{id}/nuclei-screenshot/run": {
+			OperationID: "RunNucleiScreenshotScanForScopeTarget",
+			Request:     AutoScanSessionRequest{},
+			Response:    ScanStartedResponse{},
+		},
+		"GetNucleiScreenshotScanStatus":          {Response: utils.NucleiScreenshotStatus{}},
+		"GetNucleiScreenshotScansForScopeTarget": {Response: []utils.NucleiScreenshotStatus{}},
+		"RunMetaDataScan":                        {Request: ScopeTargetScanRequest{}, Response: ScanStartedResponse{}},
+		"GetMetaDataScanStatus":                  {Response: utils.MetaDataStatus{}},
+		"GetMetaDataScansForScopeTarget":         {Response: []utils.MetaDataStatus{}},
+		"RunCompanyMetaDataScan":                 {Request: CompanyMetaDataScanRequest{}, Response: ScanStartedResponse{}},
+		"RunInvestigateScan":                     {Request: ScopeTargetScanRequest{}, Response: ScanStartedResponse{}},
+		"GetInvestigateScanStatus":               {Response: utils.InvestigateStatus{}},
+		"GetInvestigateScansForScopeTarget":      {Response: []utils.InvestigateStatus{}},
+		"UpdateTargetURLROIScore":                {Request: ROIScoreRequest{}, NoContent: true},
+
+		// Settings and data management
+		"getUserSettings":      {Response: UserSettings{}},
+		"updateUserSettings":   {Request: UserSettings{}, Response: SuccessResponse{}},
+		"HandleExportData":     {Request: utils.ExportRequest{}, ContentType: "application/octet-stream"},
+		"HandleDatabaseExport": {Request: utils.DatabaseExportRequest{}, ContentType: "application/octet-stream"},
+		"HandleDatabaseImport": {
+			Response:    DatabaseImportResponse{},
+			Description: "Accepts a multipart/form-data upload of a database export file.",
+		},
+		"HandleDatabaseImportURL":         {Request: utils.DatabaseImportURLRequest{}, Response: DatabaseImportResponse{}},
+		"DebugExportFile":                 {Request: utils.DatabaseImportRequest{}, Response: ExportFileSummary{}},
+		"GetScopeTargetsForExport":        {Response: []ExportScopeTarget{}},
+		"startAutoScanSession":            {Request: AutoScanSessionStartRequest{}, Response: SessionStartedResponse{}},
+		"getAutoScanSession":              {Response: AutoScanSession{}},
+		"listAutoScanSessions":            {Response: []AutoScanSession{}},
+		"cancelAutoScanSession":           {Response: AutoScanSessionStatusResponse{}},
+		"getAutoScanConfig":               {Response: AutoScanConfig{}},
+		"updateAutoScanConfig":            {Request: AutoScanConfig{}, Response: AutoScanConfig{}},
+		"getAutoScanState":                {Response: AutoScanState{}},
+		"updateAutoScanState":             {Request: AutoScanStateRequest{}, Response: AutoScanStateResponse{}},
+		"updateAutoScanSessionFinalStats": {Request: AutoScanFinalStatsRequest{}, Response: AutoScanSessionStatusResponse{}},
+		"getCompanyDomainsByTool":         {Response: CompanyDomainsResponse{}},
+		"deleteCompanyDomainFromTool":     {Response: SuccessMessageResponse{}},
+		"deleteAllCompanyDomainsFromTool": {Response: DeletedDomainsResponse{}},
+		"createGoogleDorkingDomain":       {Request: ScopeTargetDomainRequest{}, Response: CompanyDomainCreatedResponse{}, Status: http.StatusCreated},
+		"getGoogleDorkingDomains":         {Response: []CompanyDomain{}},
+		"deleteGoogleDorkingDomain":       {Response: SuccessMessageResponse{}},
+		"createReverseWhoisDomain":        {Request: ScopeTargetDomainRequest{}, Response: CompanyDomainCreatedResponse{}, Status: http.StatusCreated},
+		"getReverseWhoisDomains":          {Response: []CompanyDomain{}},
+		"deleteReverseWhoisDomain":        {Response: SuccessMessageResponse{}},
+		"getAPIKeys":                      {Response: []models.APIKey{}},
+		"createAPIKey":                    {Request: APIKeyRequest{}, Status: http.StatusCreated, NoContent: true},
+		"updateAPIKey":                    {Response: MessageResponse{}},
+		"deleteAPIKey":                    {Response: MessageResponse{}},
+		"getAiAPIKeys":                    {Response: []AiAPIKey{}},
+		"createAiAPIKey":                  {Request: AiAPIKeyRequest{}, Status: http.StatusCreated, NoContent: true},
+		"updateAiAPIKey":                  {Response: MessageResponse{}},
+		"deleteAiAPIKey":                  {Response: MessageResponse{}},
+
+		// DNS resolver pool
+		"GetDNSResolvers":           {Response: []dnsresolver.ResolverStatus{}},
+		"UpdateDNSResolvers":        {Request: DNSResolversRequest{}, Response: []dnsresolver.ResolverStatus{}},
+		"RunDNSResolverHealthCheck": {Response: []dnsresolver.ResolverStatus{}},
+
+		// IP/Port scans
+		"RunIPPortScan":         {Request: IPPortScanRequest{}, Response: ScanStartedResponse{}},
+		"GetIPPortScanStatus":   {Response: utils.IPPortScan{}},
+		"GetLiveWebServers":     {Response: []utils.LiveWebServer{}},
+		"GetDiscoveredIPs":      {Response: []utils.DiscoveredIP{}},
+		"GetDiscoveredServices": {Response: []utils.DiscoveredService{}},
+		"GetIPPortScanConfig":   {Response: utils.IPPortScanConfig{}},
+		"SaveIPPortScanConfig":  {Request: utils.IPPortScanConfig{}, Response: utils.IPPortScanConfig{}},
+
+		// Company scans keyed by scope target
+		"RunAmassEnumCompanyScan":                {Request: DomainsScanRequest{}, Response: ScanStartedResponse{}},
+		"GetAmassEnumCompanyScanStatus":          {Response: AmassEnumCompanyScan{}},
+		"GetAmassEnumCompanyScansForScopeTarget": {Response: []AmassEnumCompanyScan{}},
+		"GetAmassEnumCloudDomains":               {Response: []AmassEnumCloudDomain{}},
+		"GetAmassEnumRawResults":                 {Response: []AmassEnumRawResult{}},
+		"getAmassEnumConfig":                     {Response: CompanyDomainsConfig{}},
+		"saveAmassEnumConfig":                    {Request: CompanyDomainsConfig{}, Response: ConfigSavedResponse{}},
+		"getAmassIntelConfig":                    {Response: AmassIntelConfig{}},
+		"saveAmassIntelConfig":                   {Request: AmassIntelConfig{}, Response: ConfigSavedResponse{}},
+		"getDNSxConfig":                          {Response: CompanyDomainsConfig{}},
+		"saveDNSxConfig":                         {Request: CompanyDomainsConfig{}, Response: ConfigSavedResponse{}},
+		"GetIPPortScansForScopeTarget":           {Response: IPPortScansResponse{}},
+		"GetCompanyMetaDataScansForIPPortScan":   {Response: []CompanyMetaDataScan{}},
+		"GetCompanyMetaDataResults":              {Response: []CompanyMetaDataResult{}},
+		"HandleConsolidateCompanyDomains":        {Response: ConsolidatedCompanyDomainsResponse{}},
+		"GetConsolidatedCompanyDomains":          {Response: ConsolidatedCompanyDomainsResponse{}},
+		"HandleConsolidateNetworkRanges":         {Response: ConsolidatedNetworkRangesResponse{}},
+		"GetConsolidatedNetworkRanges":           {Response: ConsolidatedNetworkRangesResponse{}},
+		"GetKatanaCompanyCloudAssetsByTarget":    {Response: []KatanaCompanyCloudAsset{}},
+		"getCloudEnumConfig":                     {Response: CloudEnumConfig{}},
+		"getKatanaCompanyConfig":                 {Response: KatanaCompanyConfig{}},
+		"getNucleiConfig":                        {Response: NucleiConfig{}},
+		"startNucleiScan":                        {Response: NucleiScanStartedResponse{}},
+		"getNucleiScanStatus":                    {Response: NucleiScanStatus{}},
+		"getNucleiScansForScopeTarget":           {Response: []NucleiScan{}},
+		"RunDNSxCompanyScan":                     {Request: DomainsScanRequest{}, Response: ScanStartedResponse{}},
+		"GetDNSxCompanyScanStatus":               {Response: utils.DNSxScanStatus{}},
+		"GetDNSxCompanyScansForScopeTarget":      {Response: []utils.DNSxScanStatus{}},
+		"GetDNSxDNSRecords":                      {Response: []utils.DNSxDNSRecord{}},
+		"GetDNSxRawResults":                      {Response: []utils.DNSxRawResult{}},
+		"RunKatanaCompanyScan":                   {Request: DomainsScanRequest{}, Response: ScanStartedResponse{}},
+		"GetKatanaCompanyScanStatus":             {Response: utils.KatanaCompanyScanStatus{}},
+		"GetKatanaCompanyScansForScopeTarget":    {Response: []utils.KatanaCompanyScanStatus{}},
+		"saveCloudEnumConfig":                    {Response: StatusResponse{}},
+		"saveKatanaCompanyConfig":                {Response: StatusResponse{}},
+		"saveNucleiConfig":                       {Response: StatusResponse{}},
+		"buildWordlistFromDomains":               {ContentType: "text/plain"},
+
+		// Reports and findings
+		"GetReportTemplates":   {Response: []utils.ReportTemplate{}},
+		"CreateReportTemplate": {Request: utils.ReportTemplate{}, Response: utils.ReportTemplate{}, Status: http.StatusCreated},
+		"UpdateReportTemplate": {Request: utils.ReportTemplate{}, Response: utils.ReportTemplate{}},
+		"DeleteReportTemplate": {Status: http.StatusNoContent},
+		"GenerateReport": {
+			Request:     utils.ReportRequest{},
+			ContentType: "application/octet-stream",
+			Description: "Returns the rendered report as a Markdown or HTML attachment.",
+		},
+		"GetScopeTargetFindings": {
+			Response: []utils.ExportableFinding{},
+			Query:    []openapi.QueryParam{{Name: "sources", Description: "Comma separated finding sources: nuclei, tls, services, takeover, dns, email, js"}},
+		},
+		"HandleSARIFExport": {
+			ContentType: "application/sarif+json",
+			Description: "SARIF 2.1.0 log with one run per finding source.",
+			Query:       []openapi.QueryParam{{Name: "sources", Description: "Comma separated finding sources: nuclei, tls, services, takeover, dns, email, js"}},
+		},
+		"HandleDefectDojoExport": {
+			Response:    DefectDojoExport{},
+			Description: "DefectDojo Generic Findings Import document.",
+			Query:       []openapi.QueryParam{{Name: "sources", Description: "Comma separated finding sources: nuclei, tls, services, takeover, dns, email, js"}},
+		},
+		"HandleDefectDojoImport": {
+			Response:    DefectDojoImportResponse{},
+			Description: "Accepts a DefectDojo findings export and stores the triage status of matching findings. IDs that match no local finding are returned as unmatched.",
+		},
+
+		// Burp Suite
+		"GetBurpSuiteStatus":   {Response: BurpStatusResponse{}},
+		"SendTargetURLsToBurp": {Request: BurpSendRequest{}, Response: BurpSendResponse{}},
+		"GetBurpScanStatus":    {Response: utils.BurpScanStatus{}},
+
+		"getLiveWebServersCount": {Response: CountResponse{}},
+		"serveOpenAPISpec":       {Summary: "Get the OpenAPI specification", OperationID: "GetOpenAPISpec", Response: map[string]interface{}{}},
+	}
+
+	domainScan(endpoints, "RunAmassScan", "GetAmassScanStatus", "GetAmassScansForScopeTarget", utils.AmassScanStatus{})
+	domainScan(endpoints, "RunHttpxScan", "GetHttpxScanStatus", "GetHttpxScansForScopeTarget", utils.HttpxScanStatus{})
+	domainScan(endpoints, "RunGauScan", "GetGauScanStatus", "GetGauScansForScopeTarget", utils.GauScanStatus{})
+	endpoints["RunGauScan"] = openapi.Endpoint{Request: GauScanRequest{}, Response: ScanStartedResponse{}}
+	domainScan(endpoints, "RunSublist3rScan", "GetSublist3rScanStatus", "GetSublist3rScansForScopeTarget", utils.Sublist3rScanStatus{})
+	domainScan(endpoints, "RunAssetfinderScan", "GetAssetfinderScanStatus", "GetAssetfinderScansForScopeTarget", utils.AssetfinderScanStatus{})
+	domainScan(endpoints, "RunCTLScan", "GetCTLScanStatus", "GetCTLScansForScopeTarget", utils.CTLScanStatus{})
+	domainScan(endpoints, "RunSubfinderScan", "GetSubfinderScanStatus", "GetSubfinderScansForScopeTarget", utils.SubfinderScanStatus{})
+	domainScan(endpoints, "RunShuffleDNSScan", "GetShuffleDNSScanStatus", "GetShuffleDNSScansForScopeTarget", utils.ShuffleDNSScanStatus{})
+	domainScan(endpoints, "RunCeWLScan", "GetCeWLScanStatus", "GetCeWLScansForScopeTarget", utils.CeWLScanStatus{})
+	domainScan(endpoints, "RunGoSpiderScan", "GetGoSpiderScanStatus", "GetGoSpiderScansForScopeTarget", utils.GoSpiderScanStatus{})
+	domainScan(endpoints, "RunSubdomainizerScan", "GetSubdomainizerScanStatus", "GetSubdomainizerScansForScopeTarget", utils.SubdomainizerScanStatus{})
+
+	companyScan(endpoints, "RunAmassIntelScan", "GetAmassIntelScanStatus", "GetAmassIntelScansForScopeTarget", utils.AmassIntelScanStatus{})
+	companyScan(endpoints, "RunCTLCompanyScan", "GetCTLCompanyScanStatus", "GetCTLCompanyScansForScopeTarget", utils.CTLCompanyScanStatus{})
+	companyScan(endpoints, "RunCloudEnumScan", "GetCloudEnumScanStatus", "GetCloudEnumScansForScopeTarget", utils.CloudEnumScanStatus{})
+	companyScan(endpoints, "RunMetabigorCompanyScan", "GetMetabigorCompanyScanStatus", "GetMetabigorCompanyScansForScopeTarget", utils.MetabigorCompanyScanStatus{})
+	companyScan(endpoints, "RunSecurityTrailsCompanyScan", "GetSecurityTrailsCompanyScanStatus", "GetSecurityTrailsCompanyScansForScopeTarget", utils.SecurityTrailsCompanyScanStatus{})
+	companyScan(endpoints, "RunCensysCompanyScan", "GetCensysCompanyScanStatus", "GetCensysCompanyScansForScopeTarget", utils.CensysCompanyScanStatus{})
+	companyScan(endpoints, "RunShodanCompanyScan", "GetShodanCompanyScanStatus", "GetShodanCompanyScansForScopeTarget", utils.ShodanCompanyScanStatus{})
+	companyScan(endpoints, "RunGitHubReconScan", "GetGitHubReconScanStatus", "GetGitHubReconScansForScopeTarget", utils.GitHubReconScanStatus{})
+
+	return endpoints
+}
+
+// buildOpenAPISpec documents every route registered on the router
+func buildOpenAPISpec(r *mux.Router) (*openapi.Document, error) {
+	return openapi.Build(r, openapi.Info{
+		Title:   "Ars0n Framework API",
+		Version: "2.0.0",
+		Description: "REST API of the Ars0n Framework. Send `Accept: " + openapi.MediaType + "` to receive " +
+			"normalized responses (nullable values as JSON null) and JSON error envelopes.",
+	}, apiEndpoints())
+}
+
+// serveOpenAPISpec serves the spec for the router, building it on first request so it covers
+// every route registered after this handler
+func serveOpenAPISpec(r *mux.Router) http.HandlerFunc {
+	var once sync.Once
+	var spec []byte
+	var specErr error
+
+	return func(w http.ResponseWriter, req *http.Request) {
+		once.Do(func() {
+			doc, err := buildOpenAPISpec(r)
+			if err != nil {
+				specErr = err
+				return
+			}
+			spec, specErr = json.MarshalIndent(doc, "", "  ")
+		})
+		if specErr != nil {
+			log.Printf("[ERROR] Failed to build OpenAPI spec: %v", specErr)
+			http.Error(w, "Failed to build OpenAPI spec", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(spec)
+	}
+}
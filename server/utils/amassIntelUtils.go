@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"log"
+	"net"
 	"net/http"
 	"os/exec"
 	"regexp"
@@ -146,7 +147,7 @@ func ParseAndStoreIntelNetworkResults(scanID, companyName, result string) {
 	log.Printf("[INFO] Processing %d lines of Intel output", len(lines))
 
 	asnPattern := regexp.MustCompile(`^ASN:\s*(\d+)\s*-\s*(.+?)\s*-\s*(.+)$`)
-	cidrPattern := regexp.MustCompile(`^\s+(\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}/\d{1,2})\s*$`)
+	cidrPattern := regexp.MustCompile(`^\s+([0-9a-fA-F:.]+/\d{1,3})\s*$`)
 
 	var currentASN, currentDescription, currentOrganization string
 
@@ -173,6 +174,11 @@ func ParseAndStoreIntelNetworkResults(scanID, companyName, result string) {
 			matches := cidrPattern.FindStringSubmatch(originalLine)
 			if len(matches) == 2 {
 				cidrBlock := matches[1]
+				// Amass lists both IPv4 and IPv6 netblocks under an ASN
+				if _, _, err := net.ParseCIDR(cidrBlock); err != nil {
+					log.Printf("[DEBUG] Skipping invalid network range: %s", cidrBlock)
+					continue
+				}
 
 				asn := ""
 				if currentASN != "" {
@@ -602,34 +602,16 @@ func ExecuteAndParseAmassScan(scanID, domain string) {
 	rateLimit := GetAmassRateLimit()
 	log.Printf("[INFO] Using rate limit of %d for Amass scan", rateLimit)
 
-	cmd := exec.Command(
-		"docker", "run", "--rm",
+	args := []string{
+		"run", "--rm",
 		"caffix/amass",
 		"enum", "-active", "-alts", "-brute", "-nocolor",
 		"-min-for-recursive", "2", "-timeout", "60",
 		"-d", domain,
-		"-r", "8.8.8.8",
-		"-r", "1.1.1.1",
-		"-r", "9.9.9.9",
-		"-r", "64.6.64.6",
-		"-r", "208.67.222.222",
-		"-r", "208.67.220.220",
-		"-r", "8.26.56.26",
-		"-r", "8.20.247.20",
-		"-r", "185.228.168.9",
-		"-r", "185.228.169.9",
-		"-r", "76.76.19.19",
-		"-r", "76.223.122.150",
-		"-r", "198.101.242.72",
-		"-r", "176.103.130.130",
-		"-r", "176.103.130.131",
-		"-r", "94.140.14.14",
-		"-r", "94.140.15.15",
-		"-r", "1.0.0.1",
-		"-r", "77.88.8.8",
-		"-r", "77.88.8.1",
-		"-rqps", fmt.Sprintf("%d", rateLimit),
-	)
+	}
+	args = append(args, GetAmassResolverArgs()...)
+	args = append(args, "-rqps", fmt.Sprintf("%d", rateLimit))
+	cmd := exec.Command("docker", args...)
 
 	log.Printf("[INFO] Executing command: %s", cmd.String())
 
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const defaultBlobStorePath = "/data/blobs"
+
+// ErrBlobNotFound is returned by a BlobStore when no blob is stored under a key
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore keeps binary artifacts such as screenshots out of the database. Keys are
+// slash-separated relative paths, e.g. screenshots/<scope target id>/<sha256>.png.
+type BlobStore interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+	Delete(key string) error
+	// DeletePrefix removes every blob whose key starts with prefix followed by a slash
+	DeletePrefix(prefix string) error
+}
+
+// LocalBlobStore stores blobs as files under a root directory
+type LocalBlobStore struct {
+	Root string
+}
+
+var (
+	blobStoreOnce    sync.Once
+	defaultBlobStore BlobStore
+)
+
+// blobStore returns the store configured by BLOB_STORE_PATH, a local directory that should be
+// a mounted volume so blobs survive container rebuilds
+func blobStore() BlobStore {
+	blobStoreOnce.Do(func() {
+		root := os.Getenv("BLOB_STORE_PATH")
+		if root == "" {
+			root = defaultBlobStorePath
+		}
+		defaultBlobStore = &LocalBlobStore{Root: root}
+	})
+	return defaultBlobStore
+}
+
+// path maps a key to a file under Root, rejecting keys that would escape it
+func (s *LocalBlobStore) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if cleaned == "/" || strings.Contains(key, "\\") {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.Root, filepath.FromSlash(cleaned)), nil
+}
+
+func (s *LocalBlobStore) Put(key string, data []byte) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create blob directory: %v", err)
+	}
+
+	// Write to a temporary file first so readers never see a partial blob
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob: %v", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write blob: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write blob: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to store blob: %v", err)
+	}
+	return nil
+}
+
+func (s *LocalBlobStore) Get(key string) ([]byte, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(target)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return data, err
+}
+
+func (s *LocalBlobStore) Delete(key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalBlobStore) DeletePrefix(prefix string) error {
+	target, err := s.path(prefix)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(target)
+}
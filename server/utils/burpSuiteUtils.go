@@ -0,0 +1,441 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// BurpSendResult describes what happened to a single URL pushed to Burp Suite
+type BurpSendResult struct {
+	URL          string `json:"url"`
+	ProxiedCode  int    `json:"proxied_status_code,omitempty"`
+	ProxyError   string `json:"proxy_error,omitempty"`
+	ScanTaskID   string `json:"scan_task_id,omitempty"`
+	ScanError    string `json:"scan_error,omitempty"`
+	SentToProxy  bool   `json:"sent_to_proxy"`
+	ScanLaunched bool   `json:"scan_launched"`
+}
+
+// BurpScanStatus is the progress of a Burp scan task and the issues it has found so far
+type BurpScanStatus struct {
+	TaskID        string          `json:"task_id"`
+	ScanStatus    string          `json:"scan_status"`
+	Progress      int             `json:"progress"`
+	RequestsMade  int             `json:"requests_made"`
+	NetworkErrors int             `json:"network_errors"`
+	Issues        []BurpScanIssue `json:"issues"`
+	Errors        []string        `json:"errors"`
+}
+
+// BurpScanIssue is one issue Burp reported for a scan task
+type BurpScanIssue struct {
+	Name       string `json:"name"`
+	Severity   string `json:"severity"`
+	Confidence string `json:"confidence"`
+	URL        string `json:"url"`
+}
+
+// IsBurpProxyEnabled reports whether tool traffic should be routed through the Burp proxy
+func IsBurpProxyEnabled() bool {
+	var enabled bool
+	err := dbPool.QueryRow(context.Background(), `
+		SELECT COALESCE(burp_proxy_enabled, false)
+		FROM user_settings
+		LIMIT 1
+	`).Scan(&enabled)
+	if err != nil {
+		log.Printf("[ERROR] Failed to fetch Burp Suite proxy toggle: %v", err)
+		return false
+	}
+	return enabled
+}
+
+// GetBurpProxyURL returns the proxy URL for the tool containers, or an empty string when
+// proxying is disabled. Loopback addresses are rewritten to the Docker host because the
+// tools run in their own containers.
+func GetBurpProxyURL() string {
+	if !IsBurpProxyEnabled() {
+		return ""
+	}
+	ip, port := GetBurpSuiteProxySettings()
+	return fmt.Sprintf("http://%s", net.JoinHostPort(containerReachableHost(ip), strconv.Itoa(port)))
+}
+
+// burpProxyURLForAPI returns the proxy URL as seen from the API server itself
+func burpProxyURLForAPI() string {
+	ip, port := GetBurpSuiteProxySettings()
+	return fmt.Sprintf("http://%s", net.JoinHostPort(apiReachableHost(ip), strconv.Itoa(port)))
+}
+
+// containerReachableHost maps loopback addresses to the Docker host gateway
+func containerReachableHost(host string) string {
+	if isLoopbackHost(host) {
+		return "host.docker.internal"
+	}
+	return host
+}
+
+// apiReachableHost only rewrites loopback addresses when the API itself runs inside Docker
+func apiReachableHost(host string) string {
+	if isLoopbackHost(host) {
+		if _, err := os.Stat("/.dockerenv"); err == nil {
+			return "host.docker.internal"
+		}
+	}
+	return host
+}
+
+func isLoopbackHost(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// burpAPIBaseURL builds the Burp REST API base URL from the stored settings
+func burpAPIBaseURL() string {
+	ip, port, apiKey := GetBurpSuiteAPISettings()
+	return buildBurpAPIBaseURL(apiReachableHost(ip), port, apiKey)
+}
+
+// buildBurpAPIBaseURL returns the versioned REST API root, including the API key path segment
+// when one is set
+func buildBurpAPIBaseURL(host string, port int, apiKey string) string {
+	base := fmt.Sprintf("http://%s", net.JoinHostPort(host, strconv.Itoa(port)))
+	if apiKey != "" {
+		base += "/" + url.PathEscape(apiKey)
+	}
+	return base + "/v0.1"
+}
+
+// GetBurpSuiteStatus checks that the configured Burp proxy and REST API are reachable
+func GetBurpSuiteStatus(w http.ResponseWriter, r *http.Request) {
+	proxyIP, proxyPort := GetBurpSuiteProxySettings()
+	proxyAddress := net.JoinHostPort(apiReachableHost(proxyIP), strconv.Itoa(proxyPort))
+
+	proxyReachable := false
+	if conn, err := net.DialTimeout("tcp", proxyAddress, 3*time.Second); err == nil {
+		conn.Close()
+		proxyReachable = true
+	}
+
+	apiReachable := false
+	apiError := ""
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Get(burpAPIBaseURL() + "/")
+	if err != nil {
+		apiError = err.Error()
+	} else {
+		resp.Body.Close()
+		switch resp.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			apiError = "Burp REST API rejected the configured API key"
+		default:
+			apiReachable = true
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"proxy_enabled":   IsBurpProxyEnabled(),
+		"proxy_address":   proxyAddress,
+		"proxy_reachable": proxyReachable,
+		"api_reachable":   apiReachable,
+		"api_error":       apiError,
+	})
+}
+
+// SendTargetURLsToBurp pushes selected target URLs into Burp Suite. "sitemap" replays a GET request
+// through the Burp proxy so the URL shows up in the site map, "scan" launches a scan via the REST API
+// and "both" does both.
+func SendTargetURLsToBurp(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ScopeTargetID      string   `json:"scope_target_id"`
+		TargetURLIDs       []string `json:"target_url_ids"`
+		Mode               string   `json:"mode"`
+		ScanConfigurations []string `json:"scan_configurations"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ScopeTargetID == "" || len(payload.TargetURLIDs) == 0 {
+		http.Error(w, "Invalid request body. scope_target_id and target_url_ids are required.", http.StatusBadRequest)
+		return
+	}
+	if payload.Mode == "" {
+		payload.Mode = "both"
+	}
+	if payload.Mode != "sitemap" && payload.Mode != "scan" && payload.Mode != "both" {
+		http.Error(w, "mode must be one of 'sitemap', 'scan' or 'both'", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT url FROM target_urls WHERE scope_target_id = $1::uuid AND id = ANY($2::uuid[]) ORDER BY url`,
+		payload.ScopeTargetID, payload.TargetURLIDs)
+	if err != nil {
+		log.Printf("[BURP] [ERROR] Failed to query target URLs: %v", err)
+		http.Error(w, "Failed to get target URLs", http.StatusInternalServerError)
+		return
+	}
+	var urls []string
+	for rows.Next() {
+		var u string
+		if err := rows.Scan(&u); err == nil {
+			urls = append(urls, u)
+		}
+	}
+	rows.Close()
+
+	if len(urls) == 0 {
+		http.Error(w, "No matching target URLs found", http.StatusNotFound)
+		return
+	}
+
+	log.Printf("[BURP] [INFO] Sending %d target URLs to Burp Suite (mode: %s)", len(urls), payload.Mode)
+
+	results := make([]BurpSendResult, len(urls))
+	for i, u := range urls {
+		results[i].URL = u
+	}
+
+	if payload.Mode == "sitemap" || payload.Mode == "both" {
+		customUserAgent, customHeader := GetCustomHTTPSettings()
+		sendURLsThroughBurpProxy(burpProxyURLForAPI(), customUserAgent, customHeader, results)
+	}
+
+	if payload.Mode == "scan" || payload.Mode == "both" {
+		taskID, err := launchBurpScan(burpAPIBaseURL(), urls, payload.ScanConfigurations)
+		for i := range results {
+			if err != nil {
+				results[i].ScanError = err.Error()
+				continue
+			}
+			results[i].ScanLaunched = true
+			results[i].ScanTaskID = taskID
+		}
+		if err != nil {
+			log.Printf("[BURP] [ERROR] Failed to launch Burp scan: %v", err)
+		} else {
+			log.Printf("[BURP] [INFO] Launched Burp scan task %s for %d URLs", taskID, len(urls))
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mode":    payload.Mode,
+		"results": results,
+	})
+}
+
+// sendURLsThroughBurpProxy replays a GET for each URL through the proxy so Burp records it,
+// with the custom user agent and header from settings
+func sendURLsThroughBurpProxy(proxyURL, customUserAgent, customHeader string, results []BurpSendResult) {
+	proxy, err := url.Parse(proxyURL)
+	if err != nil {
+		for i := range results {
+			results[i].ProxyError = fmt.Sprintf("invalid proxy URL: %v", err)
+		}
+		return
+	}
+
+	client := &http.Client{
+		Timeout: 15 * time.Second,
+		Transport: &http.Transport{
+			Proxy:             http.ProxyURL(proxy),
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	for i := range results {
+		req, err := http.NewRequest("GET", results[i].URL, nil)
+		if err != nil {
+			results[i].ProxyError = err.Error()
+			continue
+		}
+		if customUserAgent != "" {
+			req.Header.Set("User-Agent", customUserAgent)
+		}
+		if name, value, ok := strings.Cut(customHeader, ":"); ok {
+			req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			results[i].ProxyError = err.Error()
+			continue
+		}
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+
+		results[i].SentToProxy = true
+		results[i].ProxiedCode = resp.StatusCode
+	}
+}
+
+// launchBurpScan starts a Burp scan for the given URLs and returns the task ID from the Location header
+func launchBurpScan(apiBaseURL string, urls []string, scanConfigurations []string) (string, error) {
+	body := map[string]interface{}{"urls": urls}
+
+	// Restrict the scan to the selected URLs' origins so Burp does not wander out of scope
+	var includeRules []map[string]string
+	seenOrigins := make(map[string]bool)
+	for _, u := range urls {
+		parsed, err := url.Parse(u)
+		if err != nil || parsed.Host == "" {
+			continue
+		}
+		origin := fmt.Sprintf("%s://%s", parsed.Scheme, parsed.Host)
+		if !seenOrigins[origin] {
+			seenOrigins[origin] = true
+			includeRules = append(includeRules, map[string]string{"rule": origin, "type": "SimpleScopeDef"})
+		}
+	}
+	if len(includeRules) > 0 {
+		body["scope"] = map[string]interface{}{"include": includeRules, "type": "SimpleScope"}
+	}
+
+	if len(scanConfigurations) > 0 {
+		var configs []map[string]string
+		for _, name := range scanConfigurations {
+			configs = append(configs, map[string]string{"name": name, "type": "NamedConfiguration"})
+		}
+		body["scan_configurations"] = configs
+	}
+
+	bodyJSON, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Post(apiBaseURL+"/scan", "application/json", bytes.NewReader(bodyJSON))
+	if err != nil {
+		return "", fmt.Errorf("failed to reach Burp REST API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("Burp REST API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return strings.TrimSpace(resp.Header.Get("Location")), nil
+}
+
+// fetchBurpScanStatus reads a scan task from the REST API. The status code of a rejected
+// request is returned along with the error.
+func fetchBurpScanStatus(apiBaseURL, taskID string) (*BurpScanStatus, int, error) {
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(apiBaseURL + "/scan/" + url.PathEscape(taskID))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to reach Burp REST API: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, resp.StatusCode, fmt.Errorf("Burp REST API returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	var task struct {
+		TaskID      string `json:"task_id"`
+		ScanStatus  string `json:"scan_status"`
+		ScanMetrics struct {
+			Progress           int `json:"crawl_and_audit_progress"`
+			CrawlRequestsMade  int `json:"crawl_requests_made"`
+			AuditRequestsMade  int `json:"audit_requests_made"`
+			CrawlNetworkErrors int `json:"crawl_network_errors"`
+			AuditNetworkErrors int `json:"audit_network_errors"`
+		} `json:"scan_metrics"`
+		IssueEvents []struct {
+			Type  string `json:"type"`
+			Issue struct {
+				Name       string `json:"name"`
+				Severity   string `json:"severity"`
+				Confidence string `json:"confidence"`
+				Origin     string `json:"origin"`
+				Path       string `json:"path"`
+			} `json:"issue"`
+		} `json:"issue_events"`
+		EventLog []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"event_log"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&task); err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("invalid scan status from Burp REST API: %v", err)
+	}
+
+	status := &BurpScanStatus{
+		TaskID:        task.TaskID,
+		ScanStatus:    task.ScanStatus,
+		Progress:      task.ScanMetrics.Progress,
+		RequestsMade:  task.ScanMetrics.CrawlRequestsMade + task.ScanMetrics.AuditRequestsMade,
+		NetworkErrors: task.ScanMetrics.CrawlNetworkErrors + task.ScanMetrics.AuditNetworkErrors,
+		Issues:        []BurpScanIssue{},
+		Errors:        []string{},
+	}
+	if status.TaskID == "" {
+		status.TaskID = taskID
+	}
+	for _, event := range task.IssueEvents {
+		if event.Type != "issue_found" {
+			continue
+		}
+		status.Issues = append(status.Issues, BurpScanIssue{
+			Name:       event.Issue.Name,
+			Severity:   event.Issue.Severity,
+			Confidence: event.Issue.Confidence,
+			URL:        event.Issue.Origin + event.Issue.Path,
+		})
+	}
+	for _, event := range task.EventLog {
+		if event.Type == "error" {
+			status.Errors = append(status.Errors, event.Message)
+		}
+	}
+	return status, resp.StatusCode, nil
+}
+
+// GetBurpScanStatus returns the status of a Burp scan task launched by SendTargetURLsToBurp
+func GetBurpScanStatus(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["task_id"]
+	if taskID == "" {
+		http.Error(w, "Task ID is required", http.StatusBadRequest)
+		return
+	}
+
+	status, statusCode, err := fetchBurpScanStatus(burpAPIBaseURL(), taskID)
+	if err != nil {
+		log.Printf("[BURP] [ERROR] Failed to fetch Burp scan status for task %s: %v", taskID, err)
+		switch statusCode {
+		case http.StatusNotFound:
+			http.Error(w, "Burp scan task not found", http.StatusNotFound)
+		case http.StatusUnauthorized, http.StatusForbidden:
+			http.Error(w, "Burp REST API rejected the configured API key", http.StatusBadGateway)
+		default:
+			http.Error(w, "Failed to get scan status from Burp REST API", http.StatusBadGateway)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
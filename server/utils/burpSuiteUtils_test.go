@@ -0,0 +1,197 @@
+package utils
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// burpAPIBaseFor points the REST API base URL at a stub server
+func burpAPIBaseFor(t *testing.T, server *httptest.Server, apiKey string) string {
+	t.Helper()
+	parsed, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	host, port, _ := net.SplitHostPort(parsed.Host)
+	portNum, _ := strconv.Atoi(port)
+	return buildBurpAPIBaseURL(host, portNum, apiKey)
+}
+
+func TestLaunchBurpScan(t *testing.T) {
+	var path string
+	var body struct {
+		URLs  []string `json:"urls"`
+		Scope struct {
+			Type    string `json:"type"`
+			Include []struct {
+				Rule string `json:"rule"`
+				Type string `json:"type"`
+			} `json:"include"`
+		} `json:"scope"`
+		ScanConfigurations []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"scan_configurations"`
+	}
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.Method + " " + r.URL.EscapedPath()
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid scan request body: %v", err)
+		}
+		w.Header().Set("Location", "17")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer api.Close()
+
+	urls := []string{"https://app.example.com/login", "https://app.example.com/admin", "http://api.example.com:8080/v1"}
+	taskID, err := launchBurpScan(burpAPIBaseFor(t, api, "s3cret key"), urls, []string{"Audit checks - light active"})
+	if err != nil {
+		t.Fatalf("launch failed: %v", err)
+	}
+	if taskID != "17" {
+		t.Errorf("got task ID %q, want 17", taskID)
+	}
+	if path != "POST /s3cret%20key/v0.1/scan" {
+		t.Errorf("got request %q", path)
+	}
+	if !reflect.DeepEqual(body.URLs, urls) {
+		t.Errorf("sent urls %v", body.URLs)
+	}
+	var rules []string
+	for _, rule := range body.Scope.Include {
+		if rule.Type != "SimpleScopeDef" {
+			t.Errorf("scope rule %s has type %s", rule.Rule, rule.Type)
+		}
+		rules = append(rules, rule.Rule)
+	}
+	if want := []string{"https://app.example.com", "http://api.example.com:8080"}; body.Scope.Type != "SimpleScope" || !reflect.DeepEqual(rules, want) {
+		t.Errorf("got %s scope %v, want %v", body.Scope.Type, rules, want)
+	}
+	if len(body.ScanConfigurations) != 1 || body.ScanConfigurations[0].Name != "Audit checks - light active" || body.ScanConfigurations[0].Type != "NamedConfiguration" {
+		t.Errorf("got scan configurations %+v", body.ScanConfigurations)
+	}
+
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}))
+	defer rejecting.Close()
+	if _, err := launchBurpScan(burpAPIBaseFor(t, rejecting, "wrong"), urls, nil); err == nil {
+		t.Error("rejected launch returned no error")
+	}
+}
+
+func TestBuildBurpAPIBaseURL(t *testing.T) {
+	if got := buildBurpAPIBaseURL("127.0.0.1", 1337, ""); got != "http://127.0.0.1:1337/v0.1" {
+		t.Errorf("without key got %s", got)
+	}
+	if got := buildBurpAPIBaseURL("::1", 1337, "a/b"); got != "http://[::1]:1337/a%2Fb/v0.1" {
+		t.Errorf("with key got %s", got)
+	}
+}
+
+func TestSendURLsThroughBurpProxy(t *testing.T) {
+	var mu sync.Mutex
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		// A proxied request names the absolute URL rather than just a path
+		proxied = append(proxied, r.RequestURI)
+		if r.Header.Get("User-Agent") != "bounty-hunter" || r.Header.Get("X-Bug-Bounty") != "researcher" {
+			t.Errorf("request for %s has headers %v", r.RequestURI, r.Header)
+		}
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer proxy.Close()
+
+	// .invalid names never resolve, so these can only be reached through the proxy
+	results := []BurpSendResult{
+		{URL: "http://app.example.invalid/login"},
+		{URL: "http://app.example.invalid/missing"},
+	}
+	sendURLsThroughBurpProxy(proxy.URL, "bounty-hunter", "X-Bug-Bounty: researcher", results)
+
+	for _, result := range results {
+		if !result.SentToProxy || result.ProxyError != "" {
+			t.Errorf("%s not sent to the proxy: %s", result.URL, result.ProxyError)
+		}
+	}
+	if results[0].ProxiedCode != http.StatusOK || results[1].ProxiedCode != http.StatusNotFound {
+		t.Errorf("got proxied codes %d and %d", results[0].ProxiedCode, results[1].ProxiedCode)
+	}
+	if want := []string{"http://app.example.invalid/login", "http://app.example.invalid/missing"}; !reflect.DeepEqual(proxied, want) {
+		t.Errorf("proxy saw %v, want %v", proxied, want)
+	}
+
+	proxy.Close()
+	results = []BurpSendResult{{URL: "http://app.example.invalid/login"}}
+	sendURLsThroughBurpProxy(proxy.URL, "", "", results)
+	if results[0].SentToProxy || results[0].ProxyError == "" {
+		t.Errorf("send through a stopped proxy gave %+v", results[0])
+	}
+}
+
+func TestFetchBurpScanStatus(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v0.1/scan/17" {
+			http.Error(w, "Not Found", http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{
+			"task_id": "17",
+			"scan_status": "crawling",
+			"scan_metrics": {
+				"crawl_and_audit_progress": 42,
+				"crawl_requests_made": 120,
+				"audit_requests_made": 30,
+				"crawl_network_errors": 2,
+				"audit_network_errors": 1,
+				"issue_events": 2
+			},
+			"issue_events": [
+				{"id": "1", "type": "issue_found", "issue": {"name": "Cross-site scripting (reflected)", "severity": "high", "confidence": "certain", "origin": "https://app.example.com", "path": "/search"}},
+				{"id": "2", "type": "issue_found", "issue": {"name": "Strict transport security not enforced", "severity": "low", "confidence": "certain", "origin": "https://app.example.com", "path": "/"}}
+			],
+			"event_log": [
+				{"id": "1", "type": "info", "message": "Crawl started"},
+				{"id": "2", "type": "error", "message": "Connection refused: api.example.com:8080"}
+			]
+		}`))
+	}))
+	defer api.Close()
+	base := burpAPIBaseFor(t, api, "")
+
+	status, code, err := fetchBurpScanStatus(base, "17")
+	if err != nil || code != http.StatusOK {
+		t.Fatalf("fetch failed with %d: %v", code, err)
+	}
+	want := &BurpScanStatus{
+		TaskID:        "17",
+		ScanStatus:    "crawling",
+		Progress:      42,
+		RequestsMade:  150,
+		NetworkErrors: 3,
+		Issues: []BurpScanIssue{
+			{Name: "Cross-site scripting (reflected)", Severity: "high", Confidence: "certain", URL: "https://app.example.com/search"},
+			{Name: "Strict transport security not enforced", Severity: "low", Confidence: "certain", URL: "https://app.example.com/"},
+		},
+		Errors: []string{"Connection refused: api.example.com:8080"},
+	}
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("got %+v, want %+v", status, want)
+	}
+
+	status, code, err = fetchBurpScanStatus(base, "99")
+	if err == nil || status != nil || code != http.StatusNotFound {
+		t.Errorf("unknown task gave %v with %d: %v", status, code, err)
+	}
+}
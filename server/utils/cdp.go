@@ -0,0 +1,354 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultChromeDevToolsURL = "http://ars0n-framework-v2-chrome-1:9222"
+	// Full-page captures come back base64 encoded in a single message
+	cdpMaxMessageSize = 256 << 20
+	websocketGUID     = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+)
+
+// chromeDevToolsURL is the DevTools HTTP endpoint of the headless Chrome container,
+// overridable with CHROME_DEVTOOLS_URL
+func chromeDevToolsURL() string {
+	if devToolsURL := os.Getenv("CHROME_DEVTOOLS_URL"); devToolsURL != "" {
+		return strings.TrimSuffix(devToolsURL, "/")
+	}
+	return defaultChromeDevToolsURL
+}
+
+// websocketConn is a minimal RFC 6455 client, enough to speak the DevTools protocol
+type websocketConn struct {
+	conn    net.Conn
+	reader  *bufio.Reader
+	writeMu sync.Mutex
+}
+
+// dialWebsocket opens a websocket to address (host:port) for the given path. Chrome rejects
+// DevTools requests whose Host header is neither an IP nor localhost, so localhost is sent
+// whatever name the container is reached by.
+func dialWebsocket(ctx context.Context, address, path string) (*websocketConn, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	key := base64.StdEncoding.EncodeToString(nonce)
+	handshake := "GET " + path + " HTTP/1.1\r\n" +
+		"Host: localhost\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if _, err := conn.Write([]byte(handshake)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("websocket handshake failed: %v", err)
+	}
+	resp.Body.Close()
+	accept := sha1.Sum([]byte(key + websocketGUID))
+	if resp.StatusCode != http.StatusSwitchingProtocols ||
+		resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(accept[:]) {
+		conn.Close()
+		return nil, fmt.Errorf("websocket handshake rejected: %s", resp.Status)
+	}
+	conn.SetDeadline(time.Time{})
+
+	return &websocketConn{conn: conn, reader: reader}, nil
+}
+
+// writeFrame sends a single masked frame, as clients must
+func (c *websocketConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	header := []byte{0x80 | opcode}
+	switch {
+	case len(payload) < 126:
+		header = append(header, 0x80|byte(len(payload)))
+	case len(payload) <= 0xffff:
+		header = append(header, 0x80|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(len(payload)))
+	default:
+		header = append(header, 0x80|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(len(payload)))
+	}
+	mask := make([]byte, 4)
+	rand.Read(mask)
+	header = append(header, mask...)
+
+	masked := make([]byte, len(payload))
+	for i, b := range payload {
+		masked[i] = b ^ mask[i%4]
+	}
+	_, err := c.conn.Write(append(header, masked...))
+	return err
+}
+
+// readMessage returns the next text or binary message, joining fragments and answering pings
+func (c *websocketConn) readMessage() ([]byte, error) {
+	var message []byte
+	for {
+		header := make([]byte, 2)
+		if _, err := io.ReadFull(c.reader, header); err != nil {
+			return nil, err
+		}
+		fin, opcode := header[0]&0x80 != 0, header[0]&0x0f
+		length := uint64(header[1] & 0x7f)
+		switch length {
+		case 126:
+			extended := make([]byte, 2)
+			if _, err := io.ReadFull(c.reader, extended); err != nil {
+				return nil, err
+			}
+			length = uint64(binary.BigEndian.Uint16(extended))
+		case 127:
+			extended := make([]byte, 8)
+			if _, err := io.ReadFull(c.reader, extended); err != nil {
+				return nil, err
+			}
+			length = binary.BigEndian.Uint64(extended)
+		}
+		var mask []byte
+		if header[1]&0x80 != 0 {
+			mask = make([]byte, 4)
+			if _, err := io.ReadFull(c.reader, mask); err != nil {
+				return nil, err
+			}
+		}
+		if length > cdpMaxMessageSize || uint64(len(message))+length > cdpMaxMessageSize {
+			return nil, fmt.Errorf("websocket message exceeds %d bytes", cdpMaxMessageSize)
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(c.reader, payload); err != nil {
+			return nil, err
+		}
+		for i := range mask {
+			for j := i; j < len(payload); j += 4 {
+				payload[j] ^= mask[i]
+			}
+		}
+
+		switch opcode {
+		case 0x8:
+			c.writeFrame(0x8, nil)
+			return nil, io.EOF
+		case 0x9:
+			if err := c.writeFrame(0xa, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case 0xa:
+			continue
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+func (c *websocketConn) Close() error {
+	c.writeFrame(0x8, nil)
+	return c.conn.Close()
+}
+
+// cdpMessage is a DevTools protocol command response or event
+type cdpMessage struct {
+	ID        int64           `json:"id,omitempty"`
+	SessionID string          `json:"sessionId,omitempty"`
+	Method    string          `json:"method,omitempty"`
+	Params    json.RawMessage `json:"params,omitempty"`
+	Result    json.RawMessage `json:"result,omitempty"`
+	Error     *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// cdpBrowser is a connection to the browser target. Pages are driven through flattened
+// sessions on the same connection, each with its own event handler.
+type cdpBrowser struct {
+	ws       *websocketConn
+	mu       sync.Mutex
+	nextID   int64
+	pending  map[int64]chan cdpMessage
+	handlers map[string]func(method string, params json.RawMessage)
+	closed   chan struct{}
+	err      error
+}
+
+// connectChrome attaches to the browser target of the headless Chrome container
+func connectChrome(ctx context.Context) (*cdpBrowser, error) {
+	base, err := url.Parse(chromeDevToolsURL())
+	if err != nil {
+		return nil, fmt.Errorf("invalid Chrome DevTools URL: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", base.String()+"/json/version", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Host = "localhost"
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Chrome is not reachable at %s: %v", base, err)
+	}
+	defer resp.Body.Close()
+	var version struct {
+		Browser              string `json:"Browser"`
+		WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&version); err != nil || version.WebSocketDebuggerURL == "" {
+		return nil, fmt.Errorf("unexpected /json/version response from %s", base)
+	}
+	debuggerURL, err := url.Parse(version.WebSocketDebuggerURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid debugger URL %q", version.WebSocketDebuggerURL)
+	}
+
+	address := base.Host
+	if base.Port() == "" {
+		address = net.JoinHostPort(base.Hostname(), "80")
+	}
+	ws, err := dialWebsocket(ctx, address, debuggerURL.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	browser := &cdpBrowser{
+		ws:       ws,
+		pending:  make(map[int64]chan cdpMessage),
+		handlers: make(map[string]func(string, json.RawMessage)),
+		closed:   make(chan struct{}),
+	}
+	go browser.readLoop()
+	return browser, nil
+}
+
+func (b *cdpBrowser) readLoop() {
+	defer close(b.closed)
+	for {
+		data, err := b.ws.readMessage()
+		if err != nil {
+			b.mu.Lock()
+			b.err = err
+			b.mu.Unlock()
+			return
+		}
+		var message cdpMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			continue
+		}
+
+		b.mu.Lock()
+		if message.ID != 0 {
+			if ch, ok := b.pending[message.ID]; ok {
+				delete(b.pending, message.ID)
+				ch <- message
+			}
+			b.mu.Unlock()
+			continue
+		}
+		handler := b.handlers[message.SessionID]
+		b.mu.Unlock()
+		if handler != nil {
+			handler(message.Method, message.Params)
+		}
+	}
+}
+
+// call sends a command, to the browser when sessionID is empty, and decodes its result
+func (b *cdpBrowser) call(ctx context.Context, sessionID, method string, params interface{}, result interface{}) error {
+	b.mu.Lock()
+	b.nextID++
+	id := b.nextID
+	ch := make(chan cdpMessage, 1)
+	b.pending[id] = ch
+	b.mu.Unlock()
+
+	request := map[string]interface{}{"id": id, "method": method}
+	if params != nil {
+		request["params"] = params
+	}
+	if sessionID != "" {
+		request["sessionId"] = sessionID
+	}
+	payload, err := json.Marshal(request)
+	if err == nil {
+		err = b.ws.writeFrame(0x1, payload)
+	}
+	if err != nil {
+		b.mu.Lock()
+		delete(b.pending, id)
+		b.mu.Unlock()
+		return err
+	}
+
+	select {
+	case message := <-ch:
+		if message.Error != nil {
+			return fmt.Errorf("%s: %s", method, message.Error.Message)
+		}
+		if result != nil && len(message.Result) > 0 {
+			return json.Unmarshal(message.Result, result)
+		}
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		delete(b.pending, id)
+		b.mu.Unlock()
+		return fmt.Errorf("%s: %v", method, ctx.Err())
+	case <-b.closed:
+		if b.err != nil {
+			return fmt.Errorf("%s: connection to Chrome lost: %v", method, b.err)
+		}
+		return errors.New(method + ": connection to Chrome closed")
+	}
+}
+
+// handle routes the events of a session to handler, or stops routing them when handler is nil
+func (b *cdpBrowser) handle(sessionID string, handler func(method string, params json.RawMessage)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if handler == nil {
+		delete(b.handlers, sessionID)
+		return
+	}
+	b.handlers[sessionID] = handler
+}
+
+func (b *cdpBrowser) Close() error {
+	return b.ws.Close()
+}
@@ -701,7 +701,7 @@ func GetLiveWebServerDomainsForTool(scopeTargetID string) ([]string, error) {
 		var hostname, url string
 		if err := rows.Scan(&hostname, &url); err == nil {
 			// Add hostname if it's a valid domain (not an IP)
-			if hostname != "" && !isIPv4Address(hostname) {
+			if hostname != "" && !isIPAddress(hostname) {
 				if !domainSet[hostname] {
 					domainSet[hostname] = true
 					domains = append(domains, hostname)
@@ -710,7 +710,7 @@ func GetLiveWebServerDomainsForTool(scopeTargetID string) ([]string, error) {
 
 			// Extract domain from URL
 			if url != "" {
-				if domain := extractDomainFromURL(url); domain != "" && !isIPv4Address(domain) {
+				if domain := extractDomainFromURL(url); domain != "" && !isIPAddress(domain) {
 					if !domainSet[domain] {
 						domainSet[domain] = true
 						domains = append(domains, domain)
@@ -779,13 +779,7 @@ func DeleteAllLiveWebServerDomainsFromTool(scopeTargetID string) (int64, error)
 	return result.RowsAffected(), nil
 }
 
-// Helper functions for live web server domain extraction
-func isIPv4Address(s string) bool {
-	return strings.Contains(s, ".") &&
-		len(strings.Split(s, ".")) == 4 &&
-		!strings.Contains(s, " ")
-}
-
+// Helper function for live web server domain extraction
 func extractDomainFromURL(urlStr string) string {
 	if !strings.HasPrefix(urlStr, "http://") && !strings.HasPrefix(urlStr, "https://") {
 		urlStr = "http://" + urlStr
@@ -795,6 +789,12 @@ func extractDomainFromURL(urlStr string) string {
 	parts := strings.Split(urlStr, "/")
 	if len(parts) >= 3 {
 		hostPart := parts[2]
+		// Bracketed IPv6 literal, e.g. http://[2001:db8::1]:8443/
+		if strings.HasPrefix(hostPart, "[") {
+			if end := strings.Index(hostPart, "]"); end != -1 {
+				return hostPart[1:end]
+			}
+		}
 		// Remove port if present
 		if colonIndex := strings.Index(hostPart, ":"); colonIndex != -1 {
 			hostPart = hostPart[:colonIndex]
@@ -47,6 +47,7 @@ type AttackSurfaceAsset struct {
 	Title               *string                `json:"title,omitempty"`
 	WebServer           *string                `json:"web_server,omitempty"`
 	Technologies        []string               `json:"technologies,omitempty"`
+	TechnologyDetails   []DetectedTechnology   `json:"technology_details,omitempty"`
 	ContentLength       *int                   `json:"content_length,omitempty"`
 	ResponseTime        *float64               `json:"response_time_ms,omitempty"`
 	ScreenshotPath      *string                `json:"screenshot_path,omitempty"`
@@ -54,6 +55,12 @@ type AttackSurfaceAsset struct {
 	HTTPResponseHeaders map[string]interface{} `json:"http_response_headers,omitempty"`
 	FindingsJSON        map[string]interface{} `json:"findings_json,omitempty"`
 
+	// Network Service fields
+	ServiceName    *string `json:"service_name,omitempty"`
+	ServiceProduct *string `json:"service_product,omitempty"`
+	ServiceVersion *string `json:"service_version,omitempty"`
+	ServiceBanner  *string `json:"service_banner,omitempty"`
+
 	// Cloud Asset fields
 	CloudProvider    *string `json:"cloud_provider,omitempty"`
 	CloudServiceType *string `json:"cloud_service_type,omitempty"`
@@ -100,8 +107,9 @@ type AttackSurfaceAsset struct {
 	CreatedAt   time.Time `json:"created_at"`
 
 	// Related data
-	DNSRecords    []AttackSurfaceDNSRecord `json:"dns_records,omitempty"`
-	Relationships []AssetRelationship      `json:"relationships,omitempty"`
+	DNSRecords       []AttackSurfaceDNSRecord   `json:"dns_records,omitempty"`
+	Relationships    []AssetRelationship        `json:"relationships,omitempty"`
+	TakeoverFindings []SubdomainTakeoverFinding `json:"takeover_findings,omitempty"`
 }
 
 type AttackSurfaceDNSRecord struct {
@@ -128,6 +136,7 @@ type ConsolidationResult struct {
 	NetworkRanges      int                  `json:"network_ranges"`
 	IPAddresses        int                  `json:"ip_addresses"`
 	LiveWebServers     int                  `json:"live_web_servers"`
+	NetworkServices    int                  `json:"network_services"`
 	CloudAssets        int                  `json:"cloud_assets"`
 	FQDNs              int                  `json:"fqdns"`
 	TotalRelationships int                  `json:"total_relationships"`
@@ -195,6 +204,22 @@ func ConsolidateAttackSurface(w http.ResponseWriter, r *http.Request) {
 	}
 	log.Printf("[ATTACK SURFACE] Consolidated %d live web servers", liveWebServers)
 
+	fingerprinted, err := fingerprintConsolidatedWebServers(scopeTargetID)
+	if err != nil {
+		log.Printf("[ATTACK SURFACE] Error fingerprinting live web servers: %v", err)
+	} else {
+		log.Printf("[ATTACK SURFACE] Fingerprinted technologies on %d live web servers", fingerprinted)
+	}
+
+	log.Printf("[ATTACK SURFACE] Consolidating network services...")
+	networkServices, err := consolidateNetworkServices(scopeTargetID)
+	if err != nil {
+		log.Printf("Error consolidating network services: %v", err)
+		http.Error(w, "Failed to consolidate network services", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[ATTACK SURFACE] Consolidated %d network services", networkServices)
+
 	log.Printf("[ATTACK SURFACE] Consolidating cloud assets...")
 	cloudAssets, err := consolidateCloudAssets(scopeTargetID)
 	if err != nil {
@@ -251,6 +276,7 @@ func ConsolidateAttackSurface(w http.ResponseWriter, r *http.Request) {
 		NetworkRanges:      networkRanges,
 		IPAddresses:        ipAddresses,
 		LiveWebServers:     liveWebServers,
+		NetworkServices:    networkServices,
 		CloudAssets:        cloudAssets,
 		FQDNs:              fqdns,
 		TotalRelationships: relationshipCount,
@@ -267,6 +293,7 @@ func ConsolidateAttackSurface(w http.ResponseWriter, r *http.Request) {
 	log.Printf("[ATTACK SURFACE]   • Network Ranges: %d", networkRanges)
 	log.Printf("[ATTACK SURFACE]   • IP Addresses: %d", ipAddresses)
 	log.Printf("[ATTACK SURFACE]   • Live Web Servers: %d", liveWebServers)
+	log.Printf("[ATTACK SURFACE]   • Network Services: %d", networkServices)
 	log.Printf("[ATTACK SURFACE]   • Cloud Assets: %d", cloudAssets)
 	log.Printf("[ATTACK SURFACE]   • FQDNs: %d", fqdns)
 	log.Printf("[ATTACK SURFACE]   • Asset Relationships: %d", relationshipCount)
@@ -287,6 +314,18 @@ func GetAttackSurfaceAssetCounts(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	counts, err := fetchAttackSurfaceAssetCounts(scopeTargetID)
+	if err != nil {
+		log.Printf("Error querying attack surface asset counts: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	json.NewEncoder(w).Encode(counts)
+}
+
+// fetchAttackSurfaceAssetCounts returns the number of consolidated assets per asset type
+func fetchAttackSurfaceAssetCounts(scopeTargetID string) (map[string]int, error) {
 	query := `
 		SELECT 
 			asset_type,
@@ -298,9 +337,7 @@ func GetAttackSurfaceAssetCounts(w http.ResponseWriter, r *http.Request) {
 
 	rows, err := dbPool.Query(context.Background(), query, scopeTargetID)
 	if err != nil {
-		log.Printf("Error querying attack surface asset counts: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -309,6 +346,7 @@ func GetAttackSurfaceAssetCounts(w http.ResponseWriter, r *http.Request) {
 		"network_ranges":   0,
 		"ip_addresses":     0,
 		"live_web_servers": 0,
+		"network_services": 0,
 		"cloud_assets":     0,
 		"fqdns":            0,
 	}
@@ -331,6 +369,8 @@ func GetAttackSurfaceAssetCounts(w http.ResponseWriter, r *http.Request) {
 			counts["ip_addresses"] = count
 		case "live_web_server":
 			counts["live_web_servers"] = count
+		case "network_service":
+			counts["network_services"] = count
 		case "cloud_asset":
 			counts["cloud_assets"] = count
 		case "fqdn":
@@ -339,12 +379,10 @@ func GetAttackSurfaceAssetCounts(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := rows.Err(); err != nil {
-		log.Printf("Error iterating attack surface asset count rows: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 
-	json.NewEncoder(w).Encode(counts)
+	return counts, nil
 }
 
 func clearExistingAttackSurfaceData(scopeTargetID string) error {
@@ -619,7 +657,7 @@ func consolidateNetworkRanges(scopeTargetID string) (int, error) {
 			JOIN amass_intel_scans ais ON inr.scan_id = ais.scan_id
 			WHERE ais.scope_target_id = $1::uuid AND ais.status = 'success'
 				AND inr.cidr_block IS NOT NULL AND inr.cidr_block != ''
-				AND inr.cidr_block ~ '^(\d{1,3}\.){3}\d{1,3}/\d{1,2}$'
+				AND (inr.cidr_block ~ '^(\d{1,3}\.){3}\d{1,3}/\d{1,2}$' OR inr.cidr_block ~ '^([0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}/\d{1,3}$')
 			
 			UNION ALL
 			
@@ -635,7 +673,7 @@ func consolidateNetworkRanges(scopeTargetID string) (int, error) {
 			JOIN metabigor_company_scans mcs ON mnr.scan_id = mcs.scan_id
 			WHERE mcs.scope_target_id = $1::uuid AND mcs.status = 'success'
 				AND mnr.cidr_block IS NOT NULL AND mnr.cidr_block != ''
-				AND mnr.cidr_block ~ '^(\d{1,3}\.){3}\d{1,3}/\d{1,2}$'
+				AND (mnr.cidr_block ~ '^(\d{1,3}\.){3}\d{1,3}/\d{1,2}$' OR mnr.cidr_block ~ '^([0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}/\d{1,3}$')
 			
 			UNION ALL
 			
@@ -650,13 +688,13 @@ func consolidateNetworkRanges(scopeTargetID string) (int, error) {
 			FROM consolidated_network_ranges cnr
 			WHERE cnr.scope_target_id = $1::uuid
 				AND cnr.cidr_block IS NOT NULL AND cnr.cidr_block != ''
-				AND cnr.cidr_block ~ '^(\d{1,3}\.){3}\d{1,3}/\d{1,2}$'
+				AND (cnr.cidr_block ~ '^(\d{1,3}\.){3}\d{1,3}/\d{1,2}$' OR cnr.cidr_block ~ '^([0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}/\d{1,3}$')
 			
 			UNION ALL
 			
 			-- 4. Amass Enum company scan raw results (extract CIDR blocks)
 			SELECT 
-				unnest(regexp_matches(result, '\b(?:[0-9]{1,3}\.){3}[0-9]{1,3}/[0-9]{1,2}\b', 'g')) as cidr_block,
+				unnest(regexp_matches(result, '\y(?:(?:[0-9]{1,3}\.){3}[0-9]{1,3}/[0-9]{1,2}|[0-9a-fA-F]{1,4}(?::[0-9a-fA-F]{0,4}){2,7}/[0-9]{1,3})\y', 'g')) as cidr_block,
 				NULL as asn_number,
 				'Unknown' as asn_organization,
 				'Discovered by Amass Enum' as asn_description,
@@ -669,7 +707,7 @@ func consolidateNetworkRanges(scopeTargetID string) (int, error) {
 			
 			-- 5. Wildcard Amass scans for company root domains
 			SELECT 
-				unnest(regexp_matches(result, '\b(?:[0-9]{1,3}\.){3}[0-9]{1,3}/[0-9]{1,2}\b', 'g')) as cidr_block,
+				unnest(regexp_matches(result, '\y(?:(?:[0-9]{1,3}\.){3}[0-9]{1,3}/[0-9]{1,2}|[0-9a-fA-F]{1,4}(?::[0-9a-fA-F]{0,4}){2,7}/[0-9]{1,3})\y', 'g')) as cidr_block,
 				NULL as asn_number,
 				'Unknown' as asn_organization,
 				'Discovered by Wildcard Amass' as asn_description,
@@ -688,6 +726,7 @@ func consolidateNetworkRanges(scopeTargetID string) (int, error) {
 		SELECT DISTINCT ON (nrd.cidr_block)
 			$1::uuid, 'network_range', nrd.cidr_block, nrd.cidr_block,
 			nrd.asn_number, nrd.asn_organization, nrd.asn_description, nrd.asn_country,
+			-- IPv6 ranges are too large for an INTEGER and keep a NULL subnet size
 			CASE 
 				WHEN nrd.cidr_block ~ '^(\d{1,3}\.){3}\d{1,3}/\d{1,2}$' AND 
 					 SPLIT_PART(nrd.cidr_block, '/', 2) ~ '^\d+$' AND
@@ -833,11 +872,11 @@ func consolidateIPAddresses(scopeTargetID string) (int, error) {
 			FROM target_urls tu
 			WHERE tu.scope_target_id = $1::uuid
 			AND tu.ip_address IS NOT NULL AND tu.ip_address != ''
-			AND tu.ip_address ~ '^(\d{1,3}\.){3}\d{1,3}$'
+			AND (tu.ip_address ~ '^(\d{1,3}\.){3}\d{1,3}$' OR tu.ip_address ~ '^([0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}$')
 
 			UNION
 
-			-- 4. DNS A records from Amass scans (validate before casting)
+			-- 4. DNS A and AAAA records from Amass scans (validate before casting)
 			SELECT DISTINCT
 				dr.record::inet as ip_address,
 				'dns_a_record' as source_type,
@@ -845,19 +884,21 @@ func consolidateIPAddresses(scopeTargetID string) (int, error) {
 			FROM dns_records dr
 			JOIN amass_scans ams ON dr.scan_id = ams.scan_id
 			WHERE ams.scope_target_id = $1::uuid AND ams.status = 'success'
-			AND dr.record_type = 'A' AND dr.record ~ '^(\d{1,3}\.){3}\d{1,3}$'
+			AND ((dr.record_type = 'A' AND dr.record ~ '^(\d{1,3}\.){3}\d{1,3}$')
+				OR (dr.record_type = 'AAAA' AND dr.record ~ '^([0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}$'))
 			AND dr.record IS NOT NULL AND dr.record != ''
 
 			UNION
 
-			-- 5. DNS A records from DNSx company scans (validate before casting)
+			-- 5. DNS A and AAAA records from DNSx company scans (validate before casting)
 			SELECT DISTINCT
 				dcdr.record::inet as ip_address,
 				'dnsx_a_record' as source_type,
 				ARRAY[dcdr.record]::text[] as source_ips
 			FROM dnsx_company_dns_records dcdr
-			WHERE dcdr.scope_target_id = $1::uuid AND dcdr.record_type = 'A'
-			AND dcdr.record ~ '^(\d{1,3}\.){3}\d{1,3}$'
+			WHERE dcdr.scope_target_id = $1::uuid
+			AND ((dcdr.record_type = 'A' AND dcdr.record ~ '^(\d{1,3}\.){3}\d{1,3}$')
+				OR (dcdr.record_type = 'AAAA' AND dcdr.record ~ '^([0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}$'))
 			AND dcdr.record IS NOT NULL AND dcdr.record != ''
 
 			UNION
@@ -872,6 +913,8 @@ func consolidateIPAddresses(scopeTargetID string) (int, error) {
 					CASE
 						WHEN line ~ '^https?://(\d{1,3}\.){3}\d{1,3}' THEN
 							substring(line from 'https?://(\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3})')
+						WHEN line ~ '^https?://\[[0-9a-fA-F:]+\]' THEN
+							substring(line from 'https?://\[([0-9a-fA-F:]+)\]')
 						ELSE NULL
 					END as extracted_ip
 				FROM (
@@ -882,7 +925,7 @@ func consolidateIPAddresses(scopeTargetID string) (int, error) {
 				) httpx_lines
 			) httpx_ips
 			WHERE extracted_ip IS NOT NULL AND extracted_ip != ''
-			AND extracted_ip ~ '^(\d{1,3}\.){3}\d{1,3}$'
+			AND (extracted_ip ~ '^(\d{1,3}\.){3}\d{1,3}$' OR extracted_ip ~ '^([0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}$')
 
 			UNION
 
@@ -892,8 +935,9 @@ func consolidateIPAddresses(scopeTargetID string) (int, error) {
 				'amass_enum_dns' as source_type,
 				ARRAY[aecdr.record]::text[] as source_ips
 			FROM amass_enum_company_dns_records aecdr
-			WHERE aecdr.scope_target_id = $1::uuid AND aecdr.record_type = 'A'
-			AND aecdr.record ~ '^(\d{1,3}\.){3}\d{1,3}$'
+			WHERE aecdr.scope_target_id = $1::uuid
+			AND ((aecdr.record_type = 'A' AND aecdr.record ~ '^(\d{1,3}\.){3}\d{1,3}$')
+				OR (aecdr.record_type = 'AAAA' AND aecdr.record ~ '^([0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}$'))
 			AND aecdr.record IS NOT NULL AND aecdr.record != ''
 		),
 		ip_enriched_data AS (
@@ -905,8 +949,8 @@ func consolidateIPAddresses(scopeTargetID string) (int, error) {
 				mnr.country as asn_country,
 				ARRAY_AGG(DISTINCT lws.hostname) FILTER (WHERE lws.hostname IS NOT NULL) as hostnames,
 				ARRAY_AGG(DISTINCT ptr_record) FILTER (WHERE ptr_record IS NOT NULL) as ptr_records,
-				ARRAY_AGG(DISTINCT dcdr.record) FILTER (WHERE dcdr.record_type = 'A' AND dcdr.record = host(ip.ip_address)) as dnsx_a_records,
-				ARRAY_AGG(DISTINCT aedr.record) FILTER (WHERE aedr.record_type = 'A' AND aedr.record = host(ip.ip_address)) as amass_a_records,
+				ARRAY_AGG(DISTINCT dcdr.record) FILTER (WHERE dcdr.record_type IN ('A', 'AAAA') AND dcdr.record = host(ip.ip_address)) as dnsx_a_records,
+				ARRAY_AGG(DISTINCT aedr.record) FILTER (WHERE aedr.record_type IN ('A', 'AAAA') AND aedr.record = host(ip.ip_address)) as amass_a_records,
 				ARRAY_AGG(DISTINCT ip.source_type) as httpx_sources
 			FROM comprehensive_ip_data ip
 			LEFT JOIN metabigor_network_ranges mnr ON ip.ip_address << mnr.cidr_block::inet
@@ -1049,6 +1093,8 @@ func consolidateLiveWebServers(scopeTargetID string) (int, error) {
 				lws.protocol,
 				lws.url,
 				CASE 
+					WHEN lws.url ~ '^https?://\[' THEN
+						substring(lws.url from '^https?://\[([0-9a-fA-F:]+)\]')
 					WHEN lws.url LIKE 'http://%' THEN 
 						CASE 
 							WHEN position(':' in substring(lws.url from 8)) > 0 THEN 
@@ -1097,9 +1143,15 @@ func consolidateLiveWebServers(scopeTargetID string) (int, error) {
 				CASE 
 					WHEN httpx_data.url ~ '^https?://(\d{1,3}\.){3}\d{1,3}' THEN
 						substring(httpx_data.url from 'https?://(\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3})')
+					WHEN httpx_data.url ~ '^https?://\[[0-9a-fA-F:]+\]' THEN
+						substring(httpx_data.url from 'https?://\[([0-9a-fA-F:]+)\]')
 					ELSE NULL
 				END as ip_address,
 				CASE 
+					WHEN httpx_data.url ~ '^https?://\[[0-9a-fA-F:]+\]:\d+' THEN
+						substring(httpx_data.url from '\]:(\d+)')::int
+					WHEN httpx_data.url ~ '^https?://\[' THEN
+						CASE WHEN httpx_data.url LIKE 'https://%' THEN 443 ELSE 80 END
 					WHEN httpx_data.url ~ ':(\d+)' THEN
 						substring(httpx_data.url from ':(\d+)')::int
 					WHEN httpx_data.url LIKE 'https://%' THEN 443
@@ -1113,6 +1165,8 @@ func consolidateLiveWebServers(scopeTargetID string) (int, error) {
 				END as protocol,
 				httpx_data.url,
 				CASE 
+					WHEN httpx_data.url ~ '^https?://\[' THEN
+						substring(httpx_data.url from '^https?://\[([0-9a-fA-F:]+)\]')
 					WHEN httpx_data.url LIKE 'http://%' THEN 
 						CASE 
 							WHEN position(':' in substring(httpx_data.url from 8)) > 0 THEN 
@@ -1188,6 +1242,10 @@ func consolidateLiveWebServers(scopeTargetID string) (int, error) {
 				'target_url' as asset_subtype,
 				tu.ip_address,
 				CASE 
+					WHEN tu.url ~ '^https?://\[[0-9a-fA-F:]+\]:\d+' THEN
+						substring(tu.url from '\]:(\d+)')::int
+					WHEN tu.url ~ '^https?://\[' THEN
+						CASE WHEN tu.url LIKE 'https://%' THEN 443 ELSE 80 END
 					WHEN tu.url ~ ':(\d+)' THEN
 						substring(tu.url from ':(\d+)')::int
 					WHEN tu.url LIKE 'https://%' THEN 443
@@ -1201,6 +1259,8 @@ func consolidateLiveWebServers(scopeTargetID string) (int, error) {
 				END as protocol,
 				tu.url,
 				CASE 
+					WHEN tu.url ~ '^https?://\[' THEN
+						substring(tu.url from '^https?://\[([0-9a-fA-F:]+)\]')
 					WHEN tu.url LIKE 'http://%' THEN 
 						CASE 
 							WHEN position(':' in substring(tu.url from 8)) > 0 THEN 
@@ -1225,7 +1285,8 @@ func consolidateLiveWebServers(scopeTargetID string) (int, error) {
 				END as technologies,
 				tu.content_length::bigint as content_length,
 				NULL::double precision as response_time_ms,
-				tu.screenshot as screenshot_path,
+				(SELECT '/screenshots/' || s.id::text FROM screenshots s
+				 WHERE s.scope_target_id = tu.scope_target_id AND s.url = tu.url) as screenshot_path,
 				NULL::jsonb as ssl_info,
 				NULL::jsonb as http_response_headers,
 				NULL::jsonb as findings_json
@@ -1240,6 +1301,10 @@ func consolidateLiveWebServers(scopeTargetID string) (int, error) {
 				'wildcard_target_url' as asset_subtype,
 				tu.ip_address,
 				CASE 
+					WHEN tu.url ~ '^https?://\[[0-9a-fA-F:]+\]:\d+' THEN
+						substring(tu.url from '\]:(\d+)')::int
+					WHEN tu.url ~ '^https?://\[' THEN
+						CASE WHEN tu.url LIKE 'https://%' THEN 443 ELSE 80 END
 					WHEN tu.url ~ ':(\d+)' THEN
 						substring(tu.url from ':(\d+)')::int
 					WHEN tu.url LIKE 'https://%' THEN 443
@@ -1253,6 +1318,8 @@ func consolidateLiveWebServers(scopeTargetID string) (int, error) {
 				END as protocol,
 				tu.url,
 				CASE 
+					WHEN tu.url ~ '^https?://\[' THEN
+						substring(tu.url from '^https?://\[([0-9a-fA-F:]+)\]')
 					WHEN tu.url LIKE 'http://%' THEN 
 						CASE 
 							WHEN position(':' in substring(tu.url from 8)) > 0 THEN 
@@ -1277,7 +1344,8 @@ func consolidateLiveWebServers(scopeTargetID string) (int, error) {
 				END as technologies,
 				tu.content_length::bigint as content_length,
 				NULL::double precision as response_time_ms,
-				tu.screenshot as screenshot_path,
+				(SELECT '/screenshots/' || s.id::text FROM screenshots s
+				 WHERE s.scope_target_id = tu.scope_target_id AND s.url = tu.url) as screenshot_path,
 				NULL::jsonb as ssl_info,
 				NULL::jsonb as http_response_headers,
 				NULL::jsonb as findings_json
@@ -1452,6 +1520,42 @@ func consolidateLiveWebServers(scopeTargetID string) (int, error) {
 	return insertedCount, nil
 }
 
+// consolidateNetworkServices adds the non-HTTP services fingerprinted by IP/Port scans, keeping
+// the most recent result for each IP and port
+func consolidateNetworkServices(scopeTargetID string) (int, error) {
+	query := `
+		INSERT INTO consolidated_attack_surface_assets (
+			scope_target_id, asset_type, asset_subtype, asset_identifier,
+			ip_address, domain, port, protocol, service_name, service_product, service_version, service_banner
+		)
+		SELECT DISTINCT ON (asset_identifier)
+			$1::uuid, 'network_service', ds.detection_method,
+			host(ds.ip_address) || ':' || ds.port::text || '/' || ds.transport as asset_identifier,
+			host(ds.ip_address), ds.hostname, ds.port, ds.transport,
+			ds.service, NULLIF(ds.product, ''), NULLIF(ds.version, ''), NULLIF(ds.banner, '')
+		FROM discovered_services ds
+		JOIN ip_port_scans ips ON ds.scan_id = ips.scan_id
+		WHERE ips.scope_target_id = $1::uuid AND ips.status = 'success'
+		ORDER BY asset_identifier, ds.discovered_at DESC
+		ON CONFLICT (scope_target_id, asset_type, asset_identifier) DO UPDATE SET
+			asset_subtype = EXCLUDED.asset_subtype,
+			domain = EXCLUDED.domain,
+			service_name = EXCLUDED.service_name,
+			service_product = EXCLUDED.service_product,
+			service_version = EXCLUDED.service_version,
+			service_banner = EXCLUDED.service_banner,
+			last_updated = NOW()
+	`
+
+	result, err := dbPool.Exec(context.Background(), query, scopeTargetID)
+	if err != nil {
+		log.Printf("[NETWORK SERVICE CONSOLIDATION] Error inserting consolidated network services: %v", err)
+		return 0, err
+	}
+
+	return int(result.RowsAffected()), nil
+}
+
 func consolidateCloudAssets(scopeTargetID string) (int, error) {
 	log.Printf("[CLOUD ASSET CONSOLIDATION] Starting cloud asset consolidation for scope target: %s", scopeTargetID)
 
@@ -2078,6 +2182,8 @@ func consolidateFQDNs(scopeTargetID string) (int, error) {
 				)
 			)
 			AND subdomain ~ '^[a-zA-Z0-9]([a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])?)*$'
+			-- Skip names that only resolve through a wildcard record
+			AND NOT COALESCE(cs.is_wildcard, false)
 			
 			UNION ALL
 			
@@ -2628,6 +2734,13 @@ func consolidateFQDNs(scopeTargetID string) (int, error) {
 			AND asset_type = 'cloud_asset'
 			AND asset_identifier IS NOT NULL
 		)
+		-- Filter out wildcard DNS answers flagged during subdomain consolidation, whichever source reported them
+		AND NOT EXISTS (
+			SELECT 1
+			FROM consolidated_subdomains wcs
+			WHERE wcs.subdomain = enhanced_fqdn_sources.fqdn
+			AND wcs.is_wildcard = true
+		)
 		-- Filter out common infrastructure/cloud domains that are not company-specific
 		AND fqdn NOT LIKE '%.awsdns-%'
 		AND fqdn NOT LIKE 'ns-%.awsdns-%'
@@ -2740,9 +2853,10 @@ func createAssetRelationships(scopeTargetID string) (int, error) {
 			AND ip.asset_type = 'ip_address'
 			AND nr.cidr_block IS NOT NULL 
 			AND ip.ip_address IS NOT NULL
-			AND nr.cidr_block ~ '^(\d{1,3}\.){3}\d{1,3}/\d{1,2}$'
-			AND ip.ip_address ~ '^(\d{1,3}\.){3}\d{1,3}$'
-			AND ip.ip_address::inet <<= nr.cidr_block::cidr
+			AND (nr.cidr_block ~ '^(\d{1,3}\.){3}\d{1,3}/\d{1,2}$' OR nr.cidr_block ~ '^([0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}/\d{1,3}$')
+			AND (ip.ip_address ~ '^(\d{1,3}\.){3}\d{1,3}$' OR ip.ip_address ~ '^([0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}$')
+			-- Compare as inet so ranges written with host bits set (10.0.0.1/24) still match
+			AND ip.ip_address::inet <<= nr.cidr_block::inet
 		ON CONFLICT (parent_asset_id, child_asset_id, relationship_type) DO NOTHING
 	`
 
@@ -2863,9 +2977,10 @@ func createComprehensiveAssetRelationships(scopeTargetID string) (int, error) {
 			AND ip.asset_type = 'ip_address'
 			AND nr.cidr_block IS NOT NULL 
 			AND ip.ip_address IS NOT NULL
-			AND nr.cidr_block ~ '^(\d{1,3}\.){3}\d{1,3}/\d{1,2}$'
-			AND ip.ip_address ~ '^(\d{1,3}\.){3}\d{1,3}$'
-			AND ip.ip_address::inet <<= nr.cidr_block::cidr
+			AND (nr.cidr_block ~ '^(\d{1,3}\.){3}\d{1,3}/\d{1,2}$' OR nr.cidr_block ~ '^([0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}/\d{1,3}$')
+			AND (ip.ip_address ~ '^(\d{1,3}\.){3}\d{1,3}$' OR ip.ip_address ~ '^([0-9a-fA-F]{0,4}:){2,7}[0-9a-fA-F]{0,4}$')
+			-- Compare as inet so ranges written with host bits set (10.0.0.1/24) still match
+			AND ip.ip_address::inet <<= nr.cidr_block::inet
 		ON CONFLICT (parent_asset_id, child_asset_id, relationship_type) DO NOTHING
 	`
 
@@ -3025,6 +3140,34 @@ func createComprehensiveAssetRelationships(scopeTargetID string) (int, error) {
 	totalRelationships += liveWebServerToCloudCount
 	log.Printf("[RELATIONSHIP MAPPING] Created %d Live Web Server -> Cloud Asset relationships", liveWebServerToCloudCount)
 
+	// 8. Network Services -> IP Addresses (via IP matching)
+	log.Printf("[RELATIONSHIP MAPPING] Creating Network Service -> IP Address relationships...")
+	networkServiceToIPQuery := `
+		INSERT INTO consolidated_attack_surface_relationships (
+			parent_asset_id, child_asset_id, relationship_type
+		)
+		SELECT DISTINCT 
+			ip.id, svc.id, 'hosts'
+		FROM consolidated_attack_surface_assets ip
+		JOIN consolidated_attack_surface_assets svc ON ip.scope_target_id = svc.scope_target_id
+		WHERE ip.scope_target_id = $1::uuid 
+			AND ip.asset_type = 'ip_address'
+			AND svc.asset_type = 'network_service'
+			AND ip.ip_address IS NOT NULL
+			AND svc.ip_address IS NOT NULL
+			AND ip.ip_address = svc.ip_address
+		ON CONFLICT (parent_asset_id, child_asset_id, relationship_type) DO NOTHING
+	`
+
+	networkServiceToIPResult, err := dbPool.Exec(context.Background(), networkServiceToIPQuery, scopeTargetID)
+	if err != nil {
+		log.Printf("[RELATIONSHIP MAPPING] Error creating Network Service -> IP Address relationships: %v", err)
+		return totalRelationships, err
+	}
+	networkServiceToIPCount := int(networkServiceToIPResult.RowsAffected())
+	totalRelationships += networkServiceToIPCount
+	log.Printf("[RELATIONSHIP MAPPING] Created %d Network Service -> IP Address relationships", networkServiceToIPCount)
+
 	// Log final summary
 	log.Printf("[RELATIONSHIP MAPPING] ✅ RELATIONSHIP MAPPING COMPLETE!")
 	log.Printf("[RELATIONSHIP MAPPING] Summary for scope target %s:", scopeTargetID)
@@ -3035,6 +3178,7 @@ func createComprehensiveAssetRelationships(scopeTargetID string) (int, error) {
 	log.Printf("[RELATIONSHIP MAPPING]   • Live Web Server -> FQDN: %d", liveWebServerToFQDNCount)
 	log.Printf("[RELATIONSHIP MAPPING]   • Live Web Server -> IP Address: %d", liveWebServerToIPCount)
 	log.Printf("[RELATIONSHIP MAPPING]   • Live Web Server -> Cloud Asset: %d", liveWebServerToCloudCount)
+	log.Printf("[RELATIONSHIP MAPPING]   • Network Service -> IP Address: %d", networkServiceToIPCount)
 	log.Printf("[RELATIONSHIP MAPPING]   • Total Relationships: %d", totalRelationships)
 
 	return totalRelationships, nil
@@ -3059,11 +3203,16 @@ func fetchConsolidatedAssets(scopeTargetID string) ([]AttackSurfaceAsset, error)
 			COALESCE(title, '') as title, 
 			COALESCE(web_server, '') as web_server, 
 			COALESCE(technologies, ARRAY[]::text[]) as technologies, 
+			technology_details,
 			content_length,
 			response_time_ms, 
 			COALESCE(screenshot_path, '') as screenshot_path, 
 			ssl_info, http_response_headers,
 			findings_json, 
+			COALESCE(service_name, '') as service_name, 
+			COALESCE(service_product, '') as service_product, 
+			COALESCE(service_version, '') as service_version, 
+			COALESCE(service_banner, '') as service_banner, 
 			COALESCE(cloud_provider, '') as cloud_provider, 
 			COALESCE(cloud_service_type, '') as cloud_service_type,
 			COALESCE(cloud_region, '') as cloud_region, 
@@ -3114,12 +3263,13 @@ func fetchConsolidatedAssets(scopeTargetID string) ([]AttackSurfaceAsset, error)
 	for rows.Next() {
 		var asset AttackSurfaceAsset
 		var technologies []string
-		var sslInfo, httpHeaders, findings []byte
+		var sslInfo, httpHeaders, findings, technologyDetails []byte
 		var whoisInfo, sslCertificate, soaRecord []byte
 
 		// Variables for nullable fields
 		var assetSubtype, asnNumber, asnOrganization, asnDescription, asnCountry string
 		var cidrBlock, ipAddress, ipType, url, domain, title, webServer, screenshotPath string
+		var serviceName, serviceProduct, serviceVersion, serviceBanner string
 		var cloudProvider, cloudServiceType, cloudRegion, fqdn, rootDomain, subdomain, registrar string
 		var sslIssuer, sslSubject, sslVersion, sslCipherSuite, spfRecord, dkimRecord, dmarcRecord string
 		var nameServers, status, sslProtocols, resolvedIPs, mailServers []string
@@ -3130,9 +3280,9 @@ func fetchConsolidatedAssets(scopeTargetID string) ([]AttackSurfaceAsset, error)
 			&asset.ID, &asset.ScopeTargetID, &asset.AssetType, &asset.AssetIdentifier, &assetSubtype,
 			&asnNumber, &asnOrganization, &asnDescription, &asnCountry,
 			&cidrBlock, &ipAddress, &ipType, &url, &domain, &asset.Port, &asset.Protocol,
-			&asset.StatusCode, &title, &webServer, &technologies, &asset.ContentLength,
+			&asset.StatusCode, &title, &webServer, &technologies, &technologyDetails, &asset.ContentLength,
 			&asset.ResponseTime, &screenshotPath, &sslInfo, &httpHeaders,
-			&findings, &cloudProvider, &cloudServiceType,
+			&findings, &serviceName, &serviceProduct, &serviceVersion, &serviceBanner, &cloudProvider, &cloudServiceType,
 			&cloudRegion, &fqdn, &rootDomain, &subdomain, &registrar, &asset.CreationDate,
 			&asset.ExpirationDate, &asset.UpdatedDate, &nameServers, &status, &whoisInfo,
 			&sslCertificate, &asset.SSLExpiryDate, &sslIssuer, &sslSubject, &sslVersion,
@@ -3186,6 +3336,18 @@ func fetchConsolidatedAssets(scopeTargetID string) ([]AttackSurfaceAsset, error)
 		if screenshotPath != "" {
 			asset.ScreenshotPath = &screenshotPath
 		}
+		if serviceName != "" {
+			asset.ServiceName = &serviceName
+		}
+		if serviceProduct != "" {
+			asset.ServiceProduct = &serviceProduct
+		}
+		if serviceVersion != "" {
+			asset.ServiceVersion = &serviceVersion
+		}
+		if serviceBanner != "" {
+			asset.ServiceBanner = &serviceBanner
+		}
 		if cloudProvider != "" {
 			asset.CloudProvider = &cloudProvider
 		}
@@ -3231,6 +3393,9 @@ func fetchConsolidatedAssets(scopeTargetID string) ([]AttackSurfaceAsset, error)
 
 		// Assign arrays
 		asset.Technologies = technologies
+		if len(technologyDetails) > 0 {
+			json.Unmarshal(technologyDetails, &asset.TechnologyDetails)
+		}
 		asset.NameServers = nameServers
 		asset.Status = status
 		asset.SSLProtocols = sslProtocols
@@ -3335,6 +3500,21 @@ func GetAttackSurfaceAssets(w http.ResponseWriter, r *http.Request) {
 		assets[i].Relationships = relationships
 	}
 
+	// Takeover findings are matched on the FQDN since assets are recreated on every consolidation
+	if takeovers, err := fetchSubdomainTakeoverFindings(scopeTargetID); err != nil {
+		log.Printf("Error fetching subdomain takeover findings for scope target %s: %v", scopeTargetID, err)
+	} else if len(takeovers) > 0 {
+		byFQDN := make(map[string][]SubdomainTakeoverFinding)
+		for _, takeover := range takeovers {
+			byFQDN[takeover.FQDN] = append(byFQDN[takeover.FQDN], takeover)
+		}
+		for i := range assets {
+			if assets[i].AssetType == "fqdn" {
+				assets[i].TakeoverFindings = byFQDN[strings.ToLower(assets[i].AssetIdentifier)]
+			}
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
 		"assets": assets,
@@ -3666,7 +3846,7 @@ func getASNInfoFast(domain string) (string, string) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	resolver := &net.Resolver{}
+	resolver := SharedDNSPool()
 	ips, err := resolver.LookupIPAddr(ctx, domain)
 	if err != nil || len(ips) == 0 {
 		return "", ""
@@ -3751,7 +3931,7 @@ func getDNSInfoFast(domain string) ([]string, map[string]interface{}) {
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
-	resolver := &net.Resolver{}
+	resolver := SharedDNSPool()
 	var resolvedIPs []string
 	dnsInfo := make(map[string]interface{})
 
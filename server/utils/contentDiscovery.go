@@ -0,0 +1,939 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	contentDiscoveryWorkers   = 40
+	contentDiscoveryTimeout   = 10 * time.Second
+	contentDiscoveryBodyLimit = 1 << 20
+	contentDiscoveryMaxDepth  = 3
+	// A directory yielding more hits than this is answering everything and calibration missed it
+	contentDiscoveryMaxHitsPerDir = 500
+	contentDiscoveryMaxDirs       = 50
+	contentWordlistMaxWords       = 500000
+	contentWordlistUploadLimit    = 50 << 20
+	// Seeded from the ffuf container on first use so existing installs keep their wordlist
+	defaultContentWordlist     = "ffuf-wordlist-5000"
+	defaultContentWordlistPath = "/wordlists/ffuf-wordlist-5000.txt"
+	generalWordlistTag         = "general"
+)
+
+// contentDiscoveryIgnoredStatus are never reported, whatever calibration says
+var contentDiscoveryIgnoredStatus = map[int]bool{400: true, 404: true, 429: true, 502: true, 503: true, 504: true}
+
+var technologyTokenPattern = regexp.MustCompile(`[a-z0-9]+`)
+
+// contentDiscoveryEndpointsColumn rebuilds the ffuf_results shape the client expects from the
+// content discovery results of a target URL, falling back to results stored by older versions
+const contentDiscoveryEndpointsColumn = `COALESCE((
+				SELECT jsonb_build_object('endpoints', jsonb_agg(jsonb_build_object(
+					'path', cdr.path, 'status', cdr.status_code, 'size', cdr.content_length,
+					'words', cdr.words, 'lines', cdr.lines) ORDER BY cdr.path))
+				FROM content_discovery_results cdr
+				WHERE cdr.scope_target_id = target_urls.scope_target_id AND cdr.base_url = target_urls.url
+				HAVING COUNT(*) > 0), ffuf_results) AS ffuf_results`
+
+// ContentWordlist is an uploaded wordlist. Lists tagged general, or not tagged at all, are used
+// against every URL; other tags name the technologies a list is meant for.
+type ContentWordlist struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Tags      []string  `json:"tags"`
+	WordCount int       `json:"word_count"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ContentDiscoveryScan brute-forces paths on a scope target's live web servers
+type ContentDiscoveryScan struct {
+	ID            string    `json:"id"`
+	ScanID        string    `json:"scan_id"`
+	ScopeTargetID string    `json:"scope_target_id"`
+	Status        string    `json:"status"`
+	MaxDepth      int       `json:"max_depth"`
+	URLsScanned   int       `json:"urls_scanned"`
+	RequestsSent  int       `json:"requests_sent"`
+	ResultsCount  int       `json:"results_count"`
+	Error         *string   `json:"error"`
+	ExecTime      *string   `json:"execution_time"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ContentDiscoveryResult is a path that answered differently from the calibrated not-found
+// response of its directory. Path is relative to BaseURL and Depth counts the directories
+// recursed into to reach it.
+type ContentDiscoveryResult struct {
+	ID               string    `json:"id"`
+	ScanID           *string   `json:"scan_id"`
+	ScopeTargetID    string    `json:"scope_target_id"`
+	BaseURL          string    `json:"base_url"`
+	URL              string    `json:"url"`
+	Path             string    `json:"path"`
+	StatusCode       int       `json:"status_code"`
+	ContentLength    int       `json:"content_length"`
+	Words            int       `json:"words"`
+	Lines            int       `json:"lines"`
+	ContentType      string    `json:"content_type"`
+	RedirectLocation string    `json:"redirect_location"`
+	Depth            int       `json:"depth"`
+	IsDirectory      bool      `json:"is_directory"`
+	Wordlist         string    `json:"wordlist"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// contentWord is a candidate path and the wordlist it came from
+type contentWord struct {
+	word     string
+	wordlist string
+}
+
+// contentResponse is the part of a response used to tell real content from soft-404s
+type contentResponse struct {
+	status      int
+	size        int
+	words       int
+	lines       int
+	contentType string
+	location    string
+}
+
+// contentProber sends the discovery requests. Redirects are never followed so directories
+// can be recognised by their redirect to the trailing slash form.
+type contentProber struct {
+	client      *http.Client
+	userAgent   string
+	headerName  string
+	headerValue string
+	requests    int64
+}
+
+func newContentProber() *contentProber {
+	transport := &http.Transport{
+		TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+		MaxIdleConnsPerHost: contentDiscoveryWorkers,
+	}
+	if IsBurpProxyEnabled() {
+		if proxyURL, err := url.Parse(burpProxyURLForAPI()); err == nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+
+	prober := &contentProber{
+		client: &http.Client{
+			Timeout:   contentDiscoveryTimeout,
+			Transport: transport,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+	customUserAgent, customHeader := GetCustomHTTPSettings()
+	prober.userAgent = customUserAgent
+	if name, value, ok := strings.Cut(customHeader, ":"); ok {
+		prober.headerName, prober.headerValue = strings.TrimSpace(name), strings.TrimSpace(value)
+	}
+	return prober
+}
+
+func (p *contentProber) probe(target string) (contentResponse, error) {
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return contentResponse{}, err
+	}
+	if p.userAgent != "" {
+		req.Header.Set("User-Agent", p.userAgent)
+	}
+	if p.headerName != "" {
+		req.Header.Set(p.headerName, p.headerValue)
+	}
+
+	atomic.AddInt64(&p.requests, 1)
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return contentResponse{}, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, contentDiscoveryBodyLimit))
+	if err != nil {
+		return contentResponse{}, err
+	}
+
+	return contentResponse{
+		status:      resp.StatusCode,
+		size:        len(body),
+		words:       len(strings.Fields(string(body))),
+		lines:       strings.Count(string(body), "\n") + 1,
+		contentType: resp.Header.Get("Content-Type"),
+		location:    resp.Header.Get("Location"),
+	}, nil
+}
+
+func randomContentToken() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// calibrate records how a directory answers for paths that cannot exist: a bare name, a file
+// and a directory. Locations have the probed name replaced by FUZZ so redirects that echo the
+// requested path compare equal.
+func (p *contentProber) calibrate(dirURL string) []contentResponse {
+	var baseline []contentResponse
+	for _, suffix := range []string{"", ".html", "/"} {
+		name := randomContentToken() + suffix
+		resp, err := p.probe(dirURL + name)
+		if err != nil {
+			continue
+		}
+		resp.location = strings.ReplaceAll(resp.location, name, "FUZZ")
+		baseline = append(baseline, resp)
+	}
+	return baseline
+}
+
+// isSoftNotFound reports whether a response for word looks like one of the calibrated
+// not-found responses. Sizes can vary with the reflected path, so equal word and line counts
+// are enough for a match.
+func isSoftNotFound(resp contentResponse, word string, baseline []contentResponse) bool {
+	if contentDiscoveryIgnoredStatus[resp.status] {
+		return true
+	}
+	location := strings.ReplaceAll(resp.location, word, "FUZZ")
+	for _, b := range baseline {
+		if b.status != resp.status {
+			continue
+		}
+		if b.location != "" || location != "" {
+			if b.location == location {
+				return true
+			}
+			continue
+		}
+		if b.size == resp.size || (b.words == resp.words && b.lines == resp.lines) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDirectoryResponse reports whether target is a directory, either because it was requested
+// with a trailing slash and exists or because it redirects to its trailing slash form
+func isDirectoryResponse(target string, resp contentResponse) bool {
+	if strings.HasSuffix(target, "/") {
+		return resp.status < 300 || resp.status == 401 || resp.status == 403
+	}
+	if resp.status < 300 || resp.status >= 400 || resp.location == "" {
+		return false
+	}
+	requested, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	ref, err := url.Parse(resp.location)
+	if err != nil {
+		return false
+	}
+	redirected := requested.ResolveReference(ref)
+	return redirected.Host == requested.Host && redirected.Path == requested.Path+"/"
+}
+
+// discoverDirectory tries every word below dirURL and returns the hits that differ from the
+// directory's calibrated not-found responses
+func (p *contentProber) discoverDirectory(dirURL string, words []contentWord) []ContentDiscoveryResult {
+	baseline := p.calibrate(dirURL)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var results []ContentDiscoveryResult
+	semaphore := make(chan struct{}, contentDiscoveryWorkers)
+	for _, w := range words {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(w contentWord) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			target := dirURL + w.word
+			resp, err := p.probe(target)
+			if err != nil || isSoftNotFound(resp, w.word, baseline) {
+				return
+			}
+			mu.Lock()
+			results = append(results, ContentDiscoveryResult{
+				URL:              target,
+				StatusCode:       resp.status,
+				ContentLength:    resp.size,
+				Words:            resp.words,
+				Lines:            resp.lines,
+				ContentType:      resp.contentType,
+				RedirectLocation: resp.location,
+				IsDirectory:      isDirectoryResponse(target, resp),
+				Wordlist:         w.wordlist,
+			})
+			mu.Unlock()
+		}(w)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// discoverContent brute-forces baseURL with words, recursing into directories it finds until
+// maxDepth levels below the base URL
+func (p *contentProber) discoverContent(baseURL string, words []contentWord, maxDepth int) []ContentDiscoveryResult {
+	root := strings.TrimSuffix(baseURL, "/") + "/"
+	type directory struct {
+		url   string
+		depth int
+	}
+	queue := []directory{{root, 0}}
+	queued := map[string]bool{root: true}
+
+	var results []ContentDiscoveryResult
+	for len(queue) > 0 {
+		dir := queue[0]
+		queue = queue[1:]
+
+		hits := p.discoverDirectory(dir.url, words)
+		if len(hits) > contentDiscoveryMaxHitsPerDir {
+			log.Printf("[CONTENT DISCOVERY] [WARN] %s returned %d hits, treating it as a catch-all and discarding them",
+				dir.url, len(hits))
+			continue
+		}
+		sort.Slice(hits, func(i, j int) bool { return hits[i].URL < hits[j].URL })
+
+		for _, hit := range hits {
+			hit.BaseURL = baseURL
+			hit.Path = strings.TrimPrefix(hit.URL, root)
+			hit.Depth = dir.depth
+			results = append(results, hit)
+
+			subdir := strings.TrimSuffix(hit.URL, "/") + "/"
+			if hit.IsDirectory && dir.depth < maxDepth && !queued[subdir] && len(queued) < contentDiscoveryMaxDirs {
+				queued[subdir] = true
+				queue = append(queue, directory{subdir, dir.depth + 1})
+			}
+		}
+	}
+	return results
+}
+
+// parseWordlist returns the distinct words of a wordlist in their original order, skipping
+// blank lines and comments and dropping leading slashes
+func parseWordlist(r io.Reader) []string {
+	seen := make(map[string]bool)
+	var words []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() && len(words) < contentWordlistMaxWords {
+		word := strings.TrimLeft(strings.TrimSpace(scanner.Text()), "/")
+		if word == "" || strings.HasPrefix(word, "#") || seen[word] {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	return words
+}
+
+func normalizeWordlistTags(tags []string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !containsString(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// wordlistMatchesTechnologies reports whether a wordlist should be used against a URL running
+// technologies. Technologies are matched by name, version stripped, or by any word of the name,
+// so a php tag matches "PHP:8.1" and an iis tag matches "Microsoft IIS:10.0".
+func wordlistMatchesTechnologies(tags []string, technologies []string) bool {
+	if len(tags) == 0 || containsString(tags, generalWordlistTag) {
+		return true
+	}
+	for _, technology := range technologies {
+		name, _, _ := strings.Cut(strings.ToLower(technology), ":")
+		name = strings.TrimSpace(name)
+		tokens := technologyTokenPattern.FindAllString(name, -1)
+		for _, tag := range tags {
+			if tag == name || containsString(tokens, tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ensureDefaultContentWordlist imports the wordlist shipped with the ffuf container the first
+// time content discovery runs. Once seeded it is left alone, so a user who deletes or edits it
+// keeps their change.
+func ensureDefaultContentWordlist() {
+	var seeded, exists bool
+	err := dbPool.QueryRow(context.Background(), `
+		SELECT COALESCE((SELECT default_wordlist_seeded FROM user_settings LIMIT 1), false),
+		       EXISTS(SELECT 1 FROM content_wordlists WHERE name = $1)`, defaultContentWordlist).Scan(&seeded, &exists)
+	if err != nil || seeded {
+		return
+	}
+	if exists {
+		markDefaultContentWordlistSeeded()
+		return
+	}
+
+	output, err := exec.Command("docker", "exec", "ars0n-framework-v2-ffuf-1", "cat", defaultContentWordlistPath).Output()
+	if err != nil {
+		log.Printf("[CONTENT DISCOVERY] [WARN] Failed to read default wordlist from ffuf container: %v", err)
+		return
+	}
+	words := parseWordlist(strings.NewReader(string(output)))
+	if _, err := saveContentWordlist(defaultContentWordlist, []string{generalWordlistTag}, words); err != nil {
+		log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to store default wordlist: %v", err)
+		return
+	}
+	markDefaultContentWordlistSeeded()
+	log.Printf("[CONTENT DISCOVERY] [INFO] Imported default wordlist with %d words", len(words))
+}
+
+func markDefaultContentWordlistSeeded() {
+	if _, err := dbPool.Exec(context.Background(), `UPDATE user_settings SET default_wordlist_seeded = true`); err != nil {
+		log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to record default wordlist as seeded: %v", err)
+	}
+}
+
+// saveContentWordlist creates a wordlist or replaces the words and tags of the one with the
+// same name
+func saveContentWordlist(name string, tags []string, words []string) (ContentWordlist, error) {
+	var list ContentWordlist
+	err := dbPool.QueryRow(context.Background(), `
+		INSERT INTO content_wordlists (name, tags, word_count, words)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name) DO UPDATE SET
+			tags = EXCLUDED.tags,
+			word_count = EXCLUDED.word_count,
+			words = EXCLUDED.words,
+			updated_at = NOW()
+		RETURNING id, name, tags, word_count, created_at, updated_at`,
+		name, normalizeWordlistTags(tags), len(words), strings.Join(words, "\n")).Scan(
+		&list.ID, &list.Name, &list.Tags, &list.WordCount, &list.CreatedAt, &list.UpdatedAt)
+	return list, err
+}
+
+// selectContentWords merges the words of the wordlists chosen for a URL. With explicit
+// wordlistIDs those lists are used as they are, otherwise lists are picked by the technologies
+// detected on the URL. A word is only tried once, credited to the first list containing it.
+func selectContentWords(wordlistIDs []string, technologies []string) ([]contentWord, error) {
+	query := `SELECT name, tags, words FROM content_wordlists ORDER BY created_at ASC`
+	args := []interface{}{}
+	if len(wordlistIDs) > 0 {
+		query = `SELECT name, tags, words FROM content_wordlists WHERE id = ANY($1::uuid[]) ORDER BY created_at ASC`
+		args = append(args, wordlistIDs)
+	}
+	rows, err := dbPool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wordlists: %v", err)
+	}
+	defer rows.Close()
+
+	seen := make(map[string]bool)
+	var words []contentWord
+	for rows.Next() {
+		var name, content string
+		var tags []string
+		if err := rows.Scan(&name, &tags, &content); err != nil {
+			return nil, fmt.Errorf("failed to read wordlist: %v", err)
+		}
+		if len(wordlistIDs) == 0 && !wordlistMatchesTechnologies(tags, technologies) {
+			continue
+		}
+		for _, word := range strings.Split(content, "\n") {
+			if word != "" && !seen[word] {
+				seen[word] = true
+				words = append(words, contentWord{strings.ReplaceAll(word, " ", "%20"), name})
+			}
+		}
+	}
+	return words, rows.Err()
+}
+
+// runContentDiscovery discovers content on one URL with the wordlists matching its detected
+// technologies and replaces the URL's stored results
+func runContentDiscovery(prober *contentProber, scanID, scopeTargetID, baseURL string, maxDepth int, wordlistIDs []string) (int, error) {
+	var technologies []string
+	dbPool.QueryRow(context.Background(),
+		`SELECT COALESCE(technologies, '{}') FROM target_urls WHERE url = $1 AND scope_target_id = $2`,
+		baseURL, scopeTargetID).Scan(&technologies)
+
+	words, err := selectContentWords(wordlistIDs, technologies)
+	if err != nil {
+		return 0, err
+	}
+	if len(words) == 0 {
+		return 0, fmt.Errorf("no wordlists available")
+	}
+	log.Printf("[CONTENT DISCOVERY] [INFO] Scanning %s with %d words (technologies: %v)", baseURL, len(words), technologies)
+
+	results := prober.discoverContent(baseURL, words, maxDepth)
+	if err := saveContentDiscoveryResults(scanID, scopeTargetID, baseURL, results); err != nil {
+		return 0, err
+	}
+	return len(results), nil
+}
+
+// ExecuteContentDiscovery runs content discovery against a single URL without recursion, as
+// part of a metadata scan
+func ExecuteContentDiscovery(baseURL string, scopeTargetID string) error {
+	log.Printf("[CONTENT DISCOVERY] [INFO] Starting content discovery for URL: %s", baseURL)
+	startTime := time.Now()
+	ensureDefaultContentWordlist()
+
+	count, err := runContentDiscovery(newContentProber(), "", scopeTargetID, baseURL, 0, nil)
+	if err != nil {
+		return err
+	}
+	log.Printf("[CONTENT DISCOVERY] [INFO] Completed content discovery for URL %s in %s. Found %d endpoints.",
+		baseURL, time.Since(startTime), count)
+	return nil
+}
+
+func saveContentDiscoveryResults(scanID, scopeTargetID, baseURL string, results []ContentDiscoveryResult) error {
+	tx, err := dbPool.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(),
+		`DELETE FROM content_discovery_results WHERE scope_target_id = $1 AND base_url = $2`,
+		scopeTargetID, baseURL); err != nil {
+		return fmt.Errorf("failed to delete old results for %s: %v", baseURL, err)
+	}
+
+	batch := &pgx.Batch{}
+	for _, result := range results {
+		batch.Queue(`
+			INSERT INTO content_discovery_results
+				(scan_id, scope_target_id, base_url, url, path, status_code, content_length, words, lines,
+				 content_type, redirect_location, depth, is_directory, wordlist)
+			VALUES (NULLIF($1, '')::uuid, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			ON CONFLICT (scope_target_id, url) DO UPDATE SET
+				scan_id = EXCLUDED.scan_id,
+				base_url = EXCLUDED.base_url,
+				path = EXCLUDED.path,
+				status_code = EXCLUDED.status_code,
+				content_length = EXCLUDED.content_length,
+				words = EXCLUDED.words,
+				lines = EXCLUDED.lines,
+				content_type = EXCLUDED.content_type,
+				redirect_location = EXCLUDED.redirect_location,
+				depth = EXCLUDED.depth,
+				is_directory = EXCLUDED.is_directory,
+				wordlist = EXCLUDED.wordlist,
+				created_at = NOW()`,
+			scanID, scopeTargetID, baseURL, result.URL, result.Path, result.StatusCode, result.ContentLength,
+			result.Words, result.Lines, result.ContentType, result.RedirectLocation, result.Depth,
+			result.IsDirectory, result.Wordlist)
+	}
+	if err := tx.SendBatch(context.Background(), batch).Close(); err != nil {
+		return fmt.Errorf("failed to store results for %s: %v", baseURL, err)
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func RunContentDiscoveryScan(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ScopeTargetID string   `json:"scope_target_id"`
+		URLs          []string `json:"urls"`
+		MaxDepth      *int     `json:"max_depth"`
+		WordlistIDs   []string `json:"wordlist_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ScopeTargetID == "" {
+		http.Error(w, "Invalid request body. `scope_target_id` is required.", http.StatusBadRequest)
+		return
+	}
+
+	maxDepth := 1
+	if payload.MaxDepth != nil {
+		maxDepth = *payload.MaxDepth
+	}
+	if maxDepth < 0 || maxDepth > contentDiscoveryMaxDepth {
+		http.Error(w, fmt.Sprintf("`max_depth` must be between 0 and %d", contentDiscoveryMaxDepth), http.StatusBadRequest)
+		return
+	}
+	for _, id := range payload.WordlistIDs {
+		if _, err := uuid.Parse(id); err != nil {
+			http.Error(w, "Invalid wordlist ID", http.StatusBadRequest)
+			return
+		}
+	}
+
+	scanID := uuid.New().String()
+	_, err := dbPool.Exec(context.Background(),
+		`INSERT INTO content_discovery_scans (scan_id, scope_target_id, status, max_depth) VALUES ($1, $2, $3, $4)`,
+		scanID, payload.ScopeTargetID, "pending", maxDepth)
+	if err != nil {
+		log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to create scan record: %v", err)
+		http.Error(w, "Failed to create scan record.", http.StatusInternalServerError)
+		return
+	}
+
+	go ExecuteContentDiscoveryScan(scanID, payload.ScopeTargetID, payload.URLs, maxDepth, payload.WordlistIDs)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"scan_id": scanID})
+}
+
+// ExecuteContentDiscoveryScan runs content discovery against the given URLs, or every live
+// target URL of the scope target when none are given
+func ExecuteContentDiscoveryScan(scanID, scopeTargetID string, urls []string, maxDepth int, wordlistIDs []string) {
+	log.Printf("[CONTENT DISCOVERY] [INFO] Starting content discovery for scope target %s (scan ID: %s)", scopeTargetID, scanID)
+	startTime := time.Now()
+	updateContentDiscoveryScan(scanID, "processing", ContentDiscoveryScan{}, "", "")
+	ensureDefaultContentWordlist()
+
+	if len(urls) == 0 {
+		rows, err := dbPool.Query(context.Background(), `
+			SELECT url FROM target_urls
+			WHERE scope_target_id = $1 AND no_longer_live = false
+			ORDER BY url`, scopeTargetID)
+		if err != nil {
+			log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to get target URLs: %v", err)
+			updateContentDiscoveryScan(scanID, "error", ContentDiscoveryScan{}, err.Error(), time.Since(startTime).String())
+			return
+		}
+		for rows.Next() {
+			var targetURL string
+			if rows.Scan(&targetURL) == nil {
+				urls = append(urls, targetURL)
+			}
+		}
+		rows.Close()
+	}
+
+	prober := newContentProber()
+	var counts ContentDiscoveryScan
+	var lastErr error
+	for i, baseURL := range urls {
+		log.Printf("[CONTENT DISCOVERY] [INFO] Scanning URL %d/%d: %s", i+1, len(urls), baseURL)
+		count, err := runContentDiscovery(prober, scanID, scopeTargetID, baseURL, maxDepth, wordlistIDs)
+		if err != nil {
+			log.Printf("[CONTENT DISCOVERY] [WARN] Content discovery failed for %s: %v", baseURL, err)
+			lastErr = err
+			continue
+		}
+		counts.URLsScanned++
+		counts.ResultsCount += count
+		counts.RequestsSent = int(atomic.LoadInt64(&prober.requests))
+		updateContentDiscoveryScan(scanID, "processing", counts, "", "")
+	}
+	counts.RequestsSent = int(atomic.LoadInt64(&prober.requests))
+
+	execTime := time.Since(startTime).String()
+	if counts.URLsScanned == 0 && lastErr != nil {
+		updateContentDiscoveryScan(scanID, "error", counts, lastErr.Error(), execTime)
+		return
+	}
+	updateContentDiscoveryScan(scanID, "success", counts, "", execTime)
+	log.Printf("[CONTENT DISCOVERY] [INFO] Scan %s completed in %s: %d URLs, %d requests, %d results",
+		scanID, execTime, counts.URLsScanned, counts.RequestsSent, counts.ResultsCount)
+}
+
+func updateContentDiscoveryScan(scanID, status string, counts ContentDiscoveryScan, errorMessage, execTime string) {
+	_, err := dbPool.Exec(context.Background(), `
+		UPDATE content_discovery_scans
+		SET status = $1, urls_scanned = $2, requests_sent = $3, results_count = $4,
+		    error = NULLIF($5, ''), execution_time = NULLIF($6, '')
+		WHERE scan_id = $7`,
+		status, counts.URLsScanned, counts.RequestsSent, counts.ResultsCount, errorMessage, execTime, scanID)
+	if err != nil {
+		log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to update scan status: %v", err)
+	}
+}
+
+const contentDiscoveryScanColumns = `id, scan_id, scope_target_id, status, max_depth, urls_scanned,
+	requests_sent, results_count, error, execution_time, created_at`
+
+func scanContentDiscoveryScan(row interface{ Scan(...interface{}) error }) (ContentDiscoveryScan, error) {
+	var scan ContentDiscoveryScan
+	err := row.Scan(
+		&scan.ID,
+		&scan.ScanID,
+		&scan.ScopeTargetID,
+		&scan.Status,
+		&scan.MaxDepth,
+		&scan.URLsScanned,
+		&scan.RequestsSent,
+		&scan.ResultsCount,
+		&scan.Error,
+		&scan.ExecTime,
+		&scan.CreatedAt,
+	)
+	return scan, err
+}
+
+func GetContentDiscoveryScanStatus(w http.ResponseWriter, r *http.Request) {
+	scanID := mux.Vars(r)["scan_id"]
+
+	scan, err := scanContentDiscoveryScan(dbPool.QueryRow(context.Background(),
+		`SELECT `+contentDiscoveryScanColumns+` FROM content_discovery_scans WHERE scan_id = $1`, scanID))
+	if err != nil {
+		http.Error(w, "Scan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scan)
+}
+
+func GetContentDiscoveryScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT `+contentDiscoveryScanColumns+` FROM content_discovery_scans WHERE scope_target_id = $1 ORDER BY created_at DESC`,
+		scopeTargetID)
+	if err != nil {
+		log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to get scans for target %s: %v", scopeTargetID, err)
+		http.Error(w, "Failed to get scans", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	scans := []ContentDiscoveryScan{}
+	for rows.Next() {
+		scan, err := scanContentDiscoveryScan(rows)
+		if err != nil {
+			log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to scan row: %v", err)
+			continue
+		}
+		scans = append(scans, scan)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scans)
+}
+
+// GetContentDiscoveryResults returns the discovered content of a scope target, optionally
+// limited to one base URL and one status code
+func GetContentDiscoveryResults(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+	query := r.URL.Query()
+	status := 0
+	if raw := query.Get("status"); raw != "" {
+		var err error
+		if status, err = strconv.Atoi(raw); err != nil {
+			http.Error(w, "Invalid status", http.StatusBadRequest)
+			return
+		}
+	}
+
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, scan_id, scope_target_id, base_url, url, path, status_code, content_length, words, lines,
+		       COALESCE(content_type, ''), COALESCE(redirect_location, ''), depth, is_directory,
+		       COALESCE(wordlist, ''), created_at
+		FROM content_discovery_results
+		WHERE scope_target_id = $1::uuid
+		  AND ($2 = '' OR base_url = $2)
+		  AND ($3 = 0 OR status_code = $3)
+		ORDER BY base_url ASC, path ASC`, scopeTargetID, query.Get("base_url"), status)
+	if err != nil {
+		log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to get results: %v", err)
+		http.Error(w, "Failed to get content discovery results", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	results := []ContentDiscoveryResult{}
+	for rows.Next() {
+		var result ContentDiscoveryResult
+		if err := rows.Scan(&result.ID, &result.ScanID, &result.ScopeTargetID, &result.BaseURL, &result.URL,
+			&result.Path, &result.StatusCode, &result.ContentLength, &result.Words, &result.Lines,
+			&result.ContentType, &result.RedirectLocation, &result.Depth, &result.IsDirectory,
+			&result.Wordlist, &result.CreatedAt); err != nil {
+			log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to scan result: %v", err)
+			continue
+		}
+		results = append(results, result)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
+
+func GetContentWordlists(w http.ResponseWriter, r *http.Request) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, name, tags, word_count, created_at, updated_at
+		FROM content_wordlists
+		ORDER BY name ASC`)
+	if err != nil {
+		log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to get wordlists: %v", err)
+		http.Error(w, "Failed to get wordlists", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	lists := []ContentWordlist{}
+	for rows.Next() {
+		var list ContentWordlist
+		if err := rows.Scan(&list.ID, &list.Name, &list.Tags, &list.WordCount, &list.CreatedAt, &list.UpdatedAt); err != nil {
+			log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to scan wordlist: %v", err)
+			continue
+		}
+		lists = append(lists, list)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lists)
+}
+
+// UploadContentWordlist stores a wordlist sent either as a multipart form with file, name and
+// comma separated tags fields, or as JSON. Duplicate words and comments are removed and a
+// wordlist with the same name is replaced.
+func UploadContentWordlist(w http.ResponseWriter, r *http.Request) {
+	var name string
+	var tags, words []string
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(contentWordlistUploadLimit); err != nil {
+			http.Error(w, "Failed to parse form data", http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Failed to get uploaded file", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+
+		name = strings.TrimSpace(r.FormValue("name"))
+		if name == "" {
+			name = strings.TrimSuffix(header.Filename, ".txt")
+		}
+		tags = strings.Split(r.FormValue("tags"), ",")
+		words = parseWordlist(file)
+	} else {
+		var payload struct {
+			Name  string   `json:"name"`
+			Tags  []string `json:"tags"`
+			Words []string `json:"words"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		name = strings.TrimSpace(payload.Name)
+		tags = payload.Tags
+		words = parseWordlist(strings.NewReader(strings.Join(payload.Words, "\n")))
+	}
+
+	if name == "" {
+		http.Error(w, "Wordlist name is required", http.StatusBadRequest)
+		return
+	}
+	if len(words) == 0 {
+		http.Error(w, "Wordlist is empty", http.StatusBadRequest)
+		return
+	}
+
+	list, err := saveContentWordlist(name, tags, words)
+	if err != nil {
+		log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to store wordlist %s: %v", name, err)
+		http.Error(w, "Failed to store wordlist", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[CONTENT DISCOVERY] [INFO] Stored wordlist %s with %d words (tags: %v)", list.Name, list.WordCount, list.Tags)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// UpdateContentWordlistTags replaces the technology tags of a wordlist
+func UpdateContentWordlistTags(w http.ResponseWriter, r *http.Request) {
+	wordlistID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(wordlistID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+	var payload struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var list ContentWordlist
+	err := dbPool.QueryRow(context.Background(), `
+		UPDATE content_wordlists SET tags = $1, updated_at = NOW()
+		WHERE id = $2
+		RETURNING id, name, tags, word_count, created_at, updated_at`,
+		normalizeWordlistTags(payload.Tags), wordlistID).Scan(
+		&list.ID, &list.Name, &list.Tags, &list.WordCount, &list.CreatedAt, &list.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		http.Error(w, "Wordlist not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to update wordlist tags: %v", err)
+		http.Error(w, "Failed to update wordlist", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+func DeleteContentWordlist(w http.ResponseWriter, r *http.Request) {
+	wordlistID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(wordlistID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	result, err := dbPool.Exec(context.Background(), `DELETE FROM content_wordlists WHERE id = $1`, wordlistID)
+	if err != nil {
+		log.Printf("[CONTENT DISCOVERY] [ERROR] Failed to delete wordlist: %v", err)
+		http.Error(w, "Failed to delete wordlist", http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		http.Error(w, "Wordlist not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -33,6 +34,8 @@ type ExportData struct {
 	ExportMetadata ExportMetadata                      `json:"export_metadata"`
 	ScopeTargets   []map[string]interface{}            `json:"scope_targets"`
 	TableData      map[string][]map[string]interface{} `json:"table_data"`
+	// Screenshot images and thumbnails from the blob store, keyed by blob key
+	Blobs map[string][]byte `json:"blobs,omitempty"`
 }
 
 type ExportMetadata struct {
@@ -122,6 +125,18 @@ var exportTableQueries = map[string]string{
 		FROM cewl_scans 
 		WHERE scope_target_id = ANY($1)`,
 
+	"recursive_enumeration_scans": `
+		SELECT id, scan_id, domain, status, result, error, execution_time, max_depth, max_zones,
+		       zones, new_count, created_at, scope_target_id, auto_scan_session_id
+		FROM recursive_enumeration_scans 
+		WHERE scope_target_id = ANY($1)`,
+
+	"permutation_scans": `
+		SELECT id, scan_id, domain, status, result, error, command, execution_time,
+		       candidate_count, truncated, created_at, scope_target_id, auto_scan_session_id
+		FROM permutation_scans 
+		WHERE scope_target_id = ANY($1)`,
+
 	"gospider_scans": `
 		SELECT id, scan_id, domain, status, result, error, stdout, stderr, command,
 		       execution_time, created_at, scope_target_id, auto_scan_session_id
@@ -153,15 +168,153 @@ var exportTableQueries = map[string]string{
 		       has_self_signed_ssl, has_untrusted_root_ssl, has_wildcard_tls, findings_json,
 		       http_response, http_response_headers, dns_a_records, dns_aaaa_records,
 		       dns_cname_records, dns_mx_records, dns_txt_records, dns_ns_records,
-		       dns_ptr_records, dns_srv_records, katana_results, ffuf_results, roi_score, ip_address
+		       dns_ptr_records, dns_srv_records, katana_results, ffuf_results, roi_score, ip_address,
+		       technology_details
 		FROM target_urls 
 		WHERE scope_target_id = ANY($1)`,
 
 	"consolidated_subdomains": `
-		SELECT id, scope_target_id, subdomain, created_at
+		SELECT id, scope_target_id, subdomain, is_wildcard, wildcard_zone, created_at
 		FROM consolidated_subdomains 
 		WHERE scope_target_id = ANY($1)`,
 
+	"subdomain_takeover_scans": `
+		SELECT id, scan_id, scope_target_id, status, candidates_checked, vulnerable_count, error, execution_time, created_at
+		FROM subdomain_takeover_scans 
+		WHERE scope_target_id = ANY($1)`,
+
+	"subdomain_takeover_findings": `
+		SELECT id, scan_id, scope_target_id, fqdn, cname, service, status, severity, nxdomain,
+		       http_status, matched_signature, last_checked
+		FROM subdomain_takeover_findings 
+		WHERE scope_target_id = ANY($1)`,
+
+	"dns_audit_scans": `
+		SELECT id, scan_id, scope_target_id, status, domains, result, findings_count,
+		       discovered_count, new_count, error, execution_time, created_at
+		FROM dns_audit_scans 
+		WHERE scope_target_id = ANY($1)`,
+
+	"dns_audit_findings": `
+		SELECT id, scan_id, scope_target_id, domain, nameserver, nameserver_ip, check_type,
+		       severity, title, details, evidence, created_at
+		FROM dns_audit_findings 
+		WHERE scope_target_id = ANY($1)`,
+
+	"email_security_scans": `
+		SELECT id, scan_id, scope_target_id, status, domains_checked, findings_count, error, execution_time, created_at
+		FROM email_security_scans 
+		WHERE scope_target_id = ANY($1)`,
+
+	"email_security_results": `
+		SELECT id, scan_id, scope_target_id, domain, grade, score, mx_records, spf_record, spf_lookups,
+		       dmarc_record, dmarc_policy, dkim_selectors, mta_sts_mode, tls_rpt_record, findings, checked_at
+		FROM email_security_results 
+		WHERE scope_target_id = ANY($1)`,
+
+	"urls": `
+		SELECT id, scope_target_id, host, scheme, pattern, example_url, extension, params, param_values,
+		       categories, hit_count, source, scan_id, first_seen, last_seen
+		FROM urls 
+		WHERE scope_target_id = ANY($1)`,
+
+	"parameter_mining_scans": `
+		SELECT id, scan_id, scope_target_id, status, urls_processed, parameters_count, tagged_count, error, execution_time, created_at
+		FROM parameter_mining_scans 
+		WHERE scope_target_id = ANY($1)`,
+
+	"url_parameters": `
+		SELECT id, scan_id, scope_target_id, host, name, param_values, source_urls, sources, tags, occurrences, created_at
+		FROM url_parameters 
+		WHERE scope_target_id = ANY($1)`,
+
+	"js_analysis_scans": `
+		SELECT id, scan_id, scope_target_id, status, files_analyzed, endpoints_count, secrets_count,
+		       source_maps_count, changed_count, error, execution_time, created_at
+		FROM js_analysis_scans 
+		WHERE scope_target_id = ANY($1)`,
+
+	"js_files": `
+		SELECT id, scope_target_id, url, page_url, sha256, previous_sha256, size, endpoints, source_map_url,
+		       source_files, secrets_count, change_count, scan_id, first_seen, last_seen, last_changed
+		FROM js_files 
+		WHERE scope_target_id = ANY($1)`,
+
+	"js_secrets": `
+		SELECT id, scan_id, scope_target_id, js_url, source_path, rule, severity, match, line, context, created_at
+		FROM js_secrets 
+		WHERE scope_target_id = ANY($1)`,
+
+	"js_source_files": `
+		SELECT id, scope_target_id, js_url, path, content, size, scan_id, created_at
+		FROM js_source_files 
+		WHERE scope_target_id = ANY($1)`,
+
+	"content_discovery_scans": `
+		SELECT id, scan_id, scope_target_id, status, max_depth, urls_scanned, requests_sent, results_count,
+		       error, execution_time, created_at
+		FROM content_discovery_scans 
+		WHERE scope_target_id = ANY($1)`,
+
+	"content_discovery_results": `
+		SELECT id, scan_id, scope_target_id, base_url, url, path, status_code, content_length, words, lines,
+		       content_type, redirect_location, depth, is_directory, wordlist, created_at
+		FROM content_discovery_results 
+		WHERE scope_target_id = ANY($1)`,
+
+	"web_fingerprint_scans": `
+		SELECT id, scan_id, scope_target_id, status, targets_count, fingerprinted_count, error, execution_time, created_at
+		FROM web_fingerprint_scans 
+		WHERE scope_target_id = ANY($1)`,
+
+	"web_fingerprints": `
+		SELECT id, scan_id, scope_target_id, url, source, title, favicon_url, favicon_hash, favicon_md5,
+		       html_structure_hash, html_tag_count, tls_cert_sha256, tls_cert_subject, tls_cert_issuer,
+		       tls_cert_not_after, tls_server_hello, ja3s, created_at, updated_at
+		FROM web_fingerprints 
+		WHERE scope_target_id = ANY($1)`,
+
+	"screenshot_scans": `
+		SELECT id, scan_id, scope_target_id, status, options, urls_count, captured_count, failures,
+		       error, execution_time, created_at
+		FROM screenshot_scans 
+		WHERE scope_target_id = ANY($1)`,
+
+	"screenshots": `
+		SELECT id, scope_target_id, url, format, width, height, size_bytes, sha256, phash, final_url,
+		       page_title, status_code, console_errors, viewport_width, viewport_height, full_page,
+		       blob_key, thumbnail_key, created_at, updated_at
+		FROM screenshots 
+		WHERE scope_target_id = ANY($1)`,
+
+	"response_bodies": `
+		SELECT rb.sha256, rb.size_bytes, rb.compressed_size, rb.content, rb.created_at
+		FROM response_bodies rb
+		WHERE rb.sha256 IN (SELECT body_sha256 FROM response_snapshots WHERE scope_target_id = ANY($1))`,
+
+	"response_snapshots": `
+		SELECT id, scope_target_id, url, source, status_code, title, content_length, headers, body_sha256,
+		       previous_snapshot_id, body_similarity, changes, meaningful_change, times_seen,
+		       first_seen_at, last_seen_at
+		FROM response_snapshots 
+		WHERE scope_target_id = ANY($1)`,
+
+	"security_header_scans": `
+		SELECT id, scan_id, scope_target_id, status, urls_checked, findings_count, error, execution_time, created_at
+		FROM security_header_scans 
+		WHERE scope_target_id = ANY($1)`,
+
+	"security_header_results": `
+		SELECT id, scan_id, scope_target_id, url, final_url, source, status_code, grade, score, headers,
+		       cors_probes, findings, checked_at
+		FROM security_header_results 
+		WHERE scope_target_id = ANY($1)`,
+
+	"wildcard_dns_zones": `
+		SELECT id, scope_target_id, zone, is_wildcard, wildcard_ips, wildcard_cnames, filtered_count, checked_at
+		FROM wildcard_dns_zones 
+		WHERE scope_target_id = ANY($1)`,
+
 	"consolidated_company_domains": `
 		SELECT id, scope_target_id, domain, source, created_at
 		FROM consolidated_company_domains 
@@ -187,8 +340,9 @@ var exportTableQueries = map[string]string{
 		       asn_organization, asn_description, asn_country, cidr_block, subnet_size,
 		       responsive_ip_count, responsive_port_count, ip_address, ip_type, dnsx_a_records,
 		       amass_a_records, httpx_sources, url, domain, port, protocol, status_code, title,
-		       web_server, technologies, content_length, response_time_ms, screenshot_path,
-		       ssl_info, http_response_headers, findings_json, cloud_provider, cloud_service_type,
+		       web_server, technologies, technology_details, content_length, response_time_ms, screenshot_path,
+		       ssl_info, http_response_headers, findings_json, service_name, service_product,
+		       service_version, service_banner, cloud_provider, cloud_service_type,
 		       cloud_region, fqdn, root_domain, subdomain, registrar, creation_date, expiration_date,
 		       updated_date, name_servers, status, whois_info, ssl_certificate, ssl_expiry_date,
 		       ssl_issuer, ssl_subject, ssl_version, ssl_cipher_suite, ssl_protocols, resolved_ips,
@@ -207,8 +361,9 @@ var exportTableQueries = map[string]string{
 
 	"ip_port_scans": `
 		SELECT id, scan_id, scope_target_id, status, total_network_ranges, processed_network_ranges,
-		       total_ips_discovered, total_ports_scanned, live_web_servers_found, error_message,
-		       command, execution_time, created_at, auto_scan_session_id
+		       total_ips_discovered, total_ports_scanned, live_web_servers_found, services_found,
+		       port_profile, custom_ports, total_ips_probed, truncated_ranges, ips_skipped, scan_config,
+		       error_message, command, execution_time, created_at, auto_scan_session_id
 		FROM ip_port_scans 
 		WHERE scope_target_id = ANY($1)`,
 
@@ -290,12 +445,19 @@ var exportTableQueries = map[string]string{
 	"live_web_servers": `
 		SELECT lws.id, lws.scan_id, lws.ip_address, lws.hostname, lws.port, lws.protocol,
 		       lws.url, lws.status_code, lws.title, lws.server_header, lws.content_length,
-		       lws.technologies, lws.response_time_ms, lws.screenshot_path, lws.ssl_info,
+		       lws.technologies, lws.technology_details, lws.response_time_ms, lws.screenshot_path, lws.ssl_info,
 		       lws.http_response_headers, lws.findings_json, lws.last_checked
 		FROM live_web_servers lws
 		JOIN ip_port_scans ips ON lws.scan_id = ips.scan_id
 		WHERE ips.scope_target_id = ANY($1)`,
 
+	"discovered_services": `
+		SELECT ds.id, ds.scan_id, ds.ip_address, ds.hostname, ds.port, ds.transport, ds.service,
+		       ds.product, ds.version, ds.banner, ds.is_tls, ds.detection_method, ds.discovered_at
+		FROM discovered_services ds
+		JOIN ip_port_scans ips ON ds.scan_id = ips.scan_id
+		WHERE ips.scope_target_id = ANY($1)`,
+
 	"metabigor_network_ranges": `
 		SELECT mnr.id, mnr.scan_id, mnr.cidr_block, mnr.asn, mnr.organization, 
 		       mnr.country, mnr.scan_type, mnr.created_at
@@ -403,6 +565,13 @@ var exportTableQueries = map[string]string{
 		FROM nuclei_configs 
 		WHERE scope_target_id = ANY($1)`,
 
+	"ip_port_scan_configs": `
+		SELECT id, scope_target_id, max_ips_per_range, ip_sampling, max_concurrent_ips, max_concurrent_ports,
+		       host_probe_timeout_ms, port_scan_timeout_ms, web_service_timeout_ms, banner_timeout_ms,
+		       port_profile, custom_ports, host_discovery, discovery_ports, created_at, updated_at
+		FROM ip_port_scan_configs 
+		WHERE scope_target_id = ANY($1)`,
+
 	// Basic scan data tables (dns_records, ips, subdomains, etc. are linked to scans by scan_id)
 	"dns_records": `
 		SELECT dr.id, dr.scan_id, dr.record, dr.record_type, dr.created_at
@@ -775,6 +944,9 @@ func exportDatabaseData(scopeTargetIDs []string) (*ExportData, error) {
 		}
 	}
 
+	// Screenshots are only useful with their images, which live in the blob store
+	totalRecords -= exportScreenshotBlobs(exportData)
+
 	// Second pass: ensure parent domain records exist for all child records
 	log.Printf("[INFO] Ensuring parent domain records are complete...")
 	if err := ensureParentDomainRecords(exportData, scopeTargetIDs); err != nil {
@@ -998,7 +1170,78 @@ func rowsToMaps(rows pgx.Rows) ([]map[string]interface{}, error) {
 	return results, nil
 }
 
+// exportScreenshotBlobs adds the image and thumbnail of every exported screenshot to the export.
+// Rows whose blobs can't be read are dropped, as they could not be restored, and their count
+// is returned.
+func exportScreenshotBlobs(exportData *ExportData) int {
+	screenshots := exportData.TableData["screenshots"]
+	if len(screenshots) == 0 {
+		return 0
+	}
+
+	exportData.Blobs = make(map[string][]byte)
+	store := blobStore()
+	var kept []map[string]interface{}
+	for _, record := range screenshots {
+		blobKey, _ := record["blob_key"].(string)
+		thumbnailKey, _ := record["thumbnail_key"].(string)
+		image, err := store.Get(blobKey)
+		if err != nil {
+			log.Printf("[WARN] Skipping screenshot of %v without its image %s: %v", record["url"], blobKey, err)
+			continue
+		}
+		thumbnail, err := store.Get(thumbnailKey)
+		if err != nil {
+			log.Printf("[WARN] Skipping screenshot of %v without its thumbnail %s: %v", record["url"], thumbnailKey, err)
+			continue
+		}
+		exportData.Blobs[blobKey] = image
+		exportData.Blobs[thumbnailKey] = thumbnail
+		kept = append(kept, record)
+	}
+
+	if len(kept) == 0 {
+		delete(exportData.TableData, "screenshots")
+	} else {
+		exportData.TableData["screenshots"] = kept
+	}
+	log.Printf("[INFO] Exported %d screenshot blobs", len(exportData.Blobs))
+	return len(screenshots) - len(kept)
+}
+
+// importScreenshotBlobs writes back the blobs of the imported screenshots. Blobs no imported
+// row refers to are ignored.
+func importScreenshotBlobs(exportData *ExportData) error {
+	store := blobStore()
+	written := 0
+	for _, record := range exportData.TableData["screenshots"] {
+		for _, column := range []string{"blob_key", "thumbnail_key"} {
+			key, _ := record[column].(string)
+			data, ok := exportData.Blobs[key]
+			if key == "" || !ok {
+				continue
+			}
+			if !strings.HasPrefix(key, "screenshots/") {
+				return fmt.Errorf("invalid screenshot blob key %q", key)
+			}
+			if err := store.Put(key, data); err != nil {
+				return fmt.Errorf("failed to restore screenshot blob %s: %v", key, err)
+			}
+			written++
+		}
+	}
+	if written > 0 {
+		log.Printf("[INFO] Restored %d screenshot blobs", written)
+	}
+	return nil
+}
+
 func importDatabaseData(exportData *ExportData) error {
+	// Blobs go first so no imported screenshot row points at a missing file
+	if err := importScreenshotBlobs(exportData); err != nil {
+		return err
+	}
+
 	tx, err := dbPool.Begin(context.Background())
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %v", err)
@@ -1123,7 +1366,7 @@ func importTableData(tx pgx.Tx, tableData map[string][]map[string]interface{}) e
 		"amass_scans", "amass_intel_scans", "amass_enum_company_scans",
 		"httpx_scans", "gau_scans", "sublist3r_scans", "assetfinder_scans",
 		"ctl_scans", "subfinder_scans", "shuffledns_scans", "shufflednscustom_scans",
-		"cewl_scans", "gospider_scans", "subdomainizer_scans",
+		"cewl_scans", "permutation_scans", "recursive_enumeration_scans", "gospider_scans", "subdomainizer_scans",
 		"nuclei_screenshots", "metadata_scans", "nuclei_scans",
 
 		// Company scanning tools
@@ -1141,7 +1384,7 @@ func importTableData(tx pgx.Tx, tableData map[string][]map[string]interface{}) e
 		"metabigor_network_ranges",
 		"amass_enum_cloud_domains", "amass_enum_dns_records", "amass_enum_raw_results",
 		"dnsx_dns_records", "dnsx_raw_results",
-		"discovered_live_ips", "live_web_servers",
+		"discovered_live_ips", "live_web_servers", "discovered_services",
 
 		// Domain-centric result tables
 		"dnsx_company_domain_results", "amass_enum_company_domain_results",
@@ -1153,7 +1396,15 @@ func importTableData(tx pgx.Tx, tableData map[string][]map[string]interface{}) e
 		// Target URLs and consolidated data
 		"target_urls",
 		"consolidated_subdomains", "consolidated_company_domains", "consolidated_network_ranges",
-		"google_dorking_domains", "reverse_whois_domains",
+		"google_dorking_domains", "reverse_whois_domains", "wildcard_dns_zones",
+		"subdomain_takeover_scans", "subdomain_takeover_findings", "dns_audit_scans", "dns_audit_findings",
+		"email_security_scans", "email_security_results", "urls", "parameter_mining_scans", "url_parameters",
+		"js_analysis_scans", "js_files", "js_secrets", "js_source_files",
+		"content_discovery_scans", "content_discovery_results",
+		"web_fingerprint_scans", "web_fingerprints",
+		"screenshot_scans", "screenshots",
+		"response_bodies", "response_snapshots",
+		"security_header_scans", "security_header_results",
 
 		// Attack surface assets (parent)
 		"consolidated_attack_surface_assets",
@@ -1164,7 +1415,7 @@ func importTableData(tx pgx.Tx, tableData map[string][]map[string]interface{}) e
 
 		// Configuration tables (can be imported any time after scope_targets)
 		"amass_enum_configs", "amass_intel_configs", "dnsx_configs",
-		"katana_company_configs", "cloud_enum_configs", "nuclei_configs",
+		"katana_company_configs", "cloud_enum_configs", "nuclei_configs", "ip_port_scan_configs",
 	}
 
 	for _, tableName := range tableOrder {
@@ -1239,10 +1490,35 @@ func importTableRecords(tx pgx.Tx, tableName string, records []map[string]interf
 	return nil
 }
 
+// Tables whose rows are identified by something other than an id column
+var importConflictColumns = map[string]string{
+	"response_bodies": "sha256",
+}
+
+// Binary columns, which the JSON export file carries base64 encoded
+var importByteaColumns = map[string][]string{
+	"response_bodies": {"content"},
+}
+
 func importSingleRecord(tx pgx.Tx, tableName string, record map[string]interface{}) error {
 	// Convert UUID fields
 	record = convertRecordUUIDs(record)
 
+	for _, column := range importByteaColumns[tableName] {
+		if encoded, ok := record[column].(string); ok {
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return fmt.Errorf("invalid %s value: %v", column, err)
+			}
+			record[column] = decoded
+		}
+	}
+
+	conflictColumn := "id"
+	if column, ok := importConflictColumns[tableName]; ok {
+		conflictColumn = column
+	}
+
 	var columns []string
 	var placeholders []string
 	var values []interface{}
@@ -1269,10 +1545,11 @@ func importSingleRecord(tx pgx.Tx, tableName string, record map[string]interface
 	// Build the upsert query
 	query := fmt.Sprintf(`
 		INSERT INTO %s (%s) VALUES (%s)
-		ON CONFLICT (id) DO UPDATE SET %s`,
+		ON CONFLICT (%s) DO UPDATE SET %s`,
 		tableName,
 		strings.Join(columns, ", "),
 		strings.Join(placeholders, ", "),
+		conflictColumn,
 		strings.Join(updateClauses, ", "))
 
 	_, execErr := tx.Exec(context.Background(), query, values...)
@@ -0,0 +1,681 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ars0n-framework-v2-server/dnsresolver"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	dnsAuditDomainConcurrency = 10
+	dnsAuditDomainTimeout     = 5 * time.Minute
+	dnsAuditQueryTimeout      = 5 * time.Second
+	dnsAuditTransferTimeout   = 60 * time.Second
+	// A name server has to miss this many SOA queries in a row to be reported unreachable
+	dnsAuditQueryAttempts = 3
+	// Limits per domain so a zone with dozens of name servers doesn't stall the scan
+	dnsAuditMaxNameServers  = 13
+	dnsAuditAddressesPerNS  = 4
+	dnsAuditMaxWalkNames    = 10000
+	dnsAuditNSEC3Probes     = 20
+	dnsAuditEvidenceSamples = 50
+	// Queried with RD set to find name servers that recurse for anyone
+	dnsAuditRecursionProbe = "example.com"
+)
+
+// DNSAuditScan tests the authoritative name servers of a scope target's domains. Result holds
+// the FQDNs learned from zone transfers and NSEC walks, one per line, for consolidation.
+type DNSAuditScan struct {
+	ID              string    `json:"id"`
+	ScanID          string    `json:"scan_id"`
+	ScopeTargetID   string    `json:"scope_target_id"`
+	Status          string    `json:"status"`
+	Domains         []string  `json:"domains"`
+	Result          *string   `json:"result"`
+	FindingsCount   int       `json:"findings_count"`
+	DiscoveredCount int       `json:"discovered_count"`
+	NewCount        int       `json:"new_count"`
+	Error           *string   `json:"error"`
+	ExecTime        *string   `json:"execution_time"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// DNSAuditFinding is a misconfiguration found on one name server of a domain. CheckType is one
+// of zone_transfer, nsec_walk, nsec3_hashes, open_recursion, lame_delegation, ns_unreachable,
+// dangling_ns or serial_mismatch.
+type DNSAuditFinding struct {
+	ID            string          `json:"id"`
+	ScanID        string          `json:"scan_id"`
+	ScopeTargetID string          `json:"scope_target_id"`
+	Domain        string          `json:"domain"`
+	Nameserver    string          `json:"nameserver"`
+	NameserverIP  string          `json:"nameserver_ip"`
+	CheckType     string          `json:"check_type"`
+	Severity      string          `json:"severity"`
+	Title         string          `json:"title"`
+	Details       string          `json:"details"`
+	Evidence      json.RawMessage `json:"evidence,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// dnsAuditResult is what auditing one domain produced
+type dnsAuditResult struct {
+	findings   []*DNSAuditFinding
+	discovered []string
+}
+
+func (r *dnsAuditResult) add(domain, nameserver, ip, checkType, severity, title, details string, evidence interface{}) {
+	finding := &DNSAuditFinding{
+		Domain:       domain,
+		Nameserver:   nameserver,
+		NameserverIP: ip,
+		CheckType:    checkType,
+		Severity:     severity,
+		Title:        title,
+		Details:      details,
+	}
+	if evidence != nil {
+		if data, err := json.Marshal(evidence); err == nil {
+			finding.Evidence = data
+		}
+	}
+	r.findings = append(r.findings, finding)
+}
+
+// dnsAuditDomains returns the domains to audit for a scope target and the name servers dnsx
+// already recorded for them. Wildcard targets audit their root domain; company targets audit
+// the domains dnsx found NS records for, or every consolidated company domain when there are none.
+func dnsAuditDomains(scopeTargetID string) ([]string, map[string][]string, error) {
+	var targetType, scopeTarget string
+	err := dbPool.QueryRow(context.Background(),
+		`SELECT type, scope_target FROM scope_targets WHERE id = $1`, scopeTargetID).Scan(&targetType, &scopeTarget)
+	if err != nil {
+		return nil, nil, fmt.Errorf("scope target not found: %v", err)
+	}
+
+	nameservers := make(map[string][]string)
+	switch targetType {
+	case "Wildcard":
+		return []string{normalizeDNSName(strings.TrimPrefix(scopeTarget, "*."))}, nameservers, nil
+	case "Company":
+	default:
+		return nil, nil, fmt.Errorf("DNS audit is not supported for %s scope targets", targetType)
+	}
+
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT root_domain, record
+		FROM dnsx_company_dns_records
+		WHERE scope_target_id = $1 AND record_type = 'NS'`, scopeTargetID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get dnsx NS records: %v", err)
+	}
+	for rows.Next() {
+		var domain, record string
+		if err := rows.Scan(&domain, &record); err == nil {
+			domain = normalizeDNSName(domain)
+			nameservers[domain] = append(nameservers[domain], normalizeDNSName(record))
+		}
+	}
+	rows.Close()
+
+	var domains []string
+	for domain := range nameservers {
+		domains = append(domains, domain)
+	}
+	if len(domains) == 0 {
+		rows, err := dbPool.Query(context.Background(),
+			`SELECT domain FROM consolidated_company_domains WHERE scope_target_id = $1`, scopeTargetID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get company domains: %v", err)
+		}
+		for rows.Next() {
+			var domain string
+			if err := rows.Scan(&domain); err == nil {
+				domains = append(domains, normalizeDNSName(domain))
+			}
+		}
+		rows.Close()
+	}
+	sort.Strings(domains)
+	return domains, nameservers, nil
+}
+
+func RunDNSAuditScan(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ScopeTargetID string   `json:"scope_target_id"`
+		Domains       []string `json:"domains,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ScopeTargetID == "" {
+		http.Error(w, "Invalid request body. `scope_target_id` is required.", http.StatusBadRequest)
+		return
+	}
+
+	domains, nameservers, err := dnsAuditDomains(payload.ScopeTargetID)
+	if err != nil {
+		log.Printf("[DNS AUDIT] [ERROR] %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(payload.Domains) > 0 {
+		domains = nil
+		for _, domain := range payload.Domains {
+			if domain = normalizeDNSName(domain); domain != "" {
+				domains = append(domains, domain)
+			}
+		}
+	}
+	if len(domains) == 0 {
+		http.Error(w, "No domains to audit. Run dnsx or consolidate company domains first.", http.StatusBadRequest)
+		return
+	}
+
+	domainsJSON, _ := json.Marshal(domains)
+	scanID := uuid.New().String()
+	_, err = dbPool.Exec(context.Background(),
+		`INSERT INTO dns_audit_scans (scan_id, scope_target_id, status, domains) VALUES ($1, $2, $3, $4)`,
+		scanID, payload.ScopeTargetID, "pending", domainsJSON)
+	if err != nil {
+		log.Printf("[DNS AUDIT] [ERROR] Failed to create scan record: %v", err)
+		http.Error(w, "Failed to create scan record.", http.StatusInternalServerError)
+		return
+	}
+
+	go ExecuteDNSAuditScan(scanID, payload.ScopeTargetID, domains, nameservers)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"scan_id": scanID})
+}
+
+// ExecuteDNSAuditScan audits every domain's name servers, stores the findings and records the
+// names learned from transfers and NSEC walks
+func ExecuteDNSAuditScan(scanID, scopeTargetID string, domains []string, nameservers map[string][]string) {
+	log.Printf("[DNS AUDIT] [INFO] Starting DNS audit of %d domains for scope target %s (scan ID: %s)", len(domains), scopeTargetID, scanID)
+	startTime := time.Now()
+	updateDNSAuditScan(scanID, "processing", "", "", 0, 0, 0, "")
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var findings []*DNSAuditFinding
+	discovered := make(map[string]bool)
+	semaphore := make(chan struct{}, dnsAuditDomainConcurrency)
+
+	for _, domain := range domains {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(domain string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), dnsAuditDomainTimeout)
+			defer cancel()
+			result := auditDomain(ctx, domain, nameservers[domain])
+			log.Printf("[DNS AUDIT] [INFO] %s: %d findings, %d names discovered", domain, len(result.findings), len(result.discovered))
+
+			mu.Lock()
+			findings = append(findings, result.findings...)
+			for _, name := range result.discovered {
+				discovered[name] = true
+			}
+			mu.Unlock()
+		}(domain)
+	}
+	wg.Wait()
+
+	if err := saveDNSAuditFindings(scanID, scopeTargetID, findings); err != nil {
+		log.Printf("[DNS AUDIT] [ERROR] Failed to save findings: %v", err)
+		updateDNSAuditScan(scanID, "error", "", err.Error(), 0, 0, 0, time.Since(startTime).String())
+		return
+	}
+
+	known := make(map[string]bool)
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT subdomain FROM consolidated_subdomains WHERE scope_target_id = $1`, scopeTargetID)
+	if err == nil {
+		for rows.Next() {
+			var subdomain string
+			if rows.Scan(&subdomain) == nil {
+				known[strings.ToLower(subdomain)] = true
+			}
+		}
+		rows.Close()
+	}
+
+	results := make([]string, 0, len(discovered))
+	newCount := 0
+	for name := range discovered {
+		results = append(results, name)
+		if !known[name] {
+			newCount++
+		}
+	}
+	sort.Strings(results)
+
+	execTime := time.Since(startTime).String()
+	updateDNSAuditScan(scanID, "success", strings.Join(results, "\n"), "", len(findings), len(results), newCount, execTime)
+	log.Printf("[DNS AUDIT] [INFO] Scan %s completed in %s: %d findings, %d names discovered, %d new", scanID, execTime, len(findings), len(results), newCount)
+}
+
+// auditDomain checks each authoritative name server of a domain for lame delegation, open
+// recursion and zone transfers, compares their SOA serials and tries to enumerate the zone
+// through NSEC or NSEC3 on the first server that answers authoritatively
+func auditDomain(ctx context.Context, domain string, knownNameservers []string) *dnsAuditResult {
+	result := &dnsAuditResult{}
+	pool := SharedDNSPool()
+	opts := dnsresolver.QueryOptions{Timeout: dnsAuditQueryTimeout}
+
+	seen := make(map[string]bool)
+	var nameservers []string
+	addNameserver := func(ns string) {
+		ns = normalizeDNSName(ns)
+		if ns != "" && !seen[ns] && len(nameservers) < dnsAuditMaxNameServers {
+			seen[ns] = true
+			nameservers = append(nameservers, ns)
+		}
+	}
+	if records, err := pool.LookupNS(ctx, domain); err == nil {
+		for _, ns := range records {
+			addNameserver(ns.Host)
+		}
+	} else {
+		log.Printf("[DNS AUDIT] [WARN] NS lookup for %s failed: %v", domain, err)
+	}
+	for _, ns := range knownNameservers {
+		addNameserver(ns)
+	}
+	if len(nameservers) == 0 {
+		return result
+	}
+
+	discovered := make(map[string]bool)
+	serials := make(map[string]uint32)
+	authoritative := ""
+
+	for _, ns := range nameservers {
+		addrs, err := pool.LookupIPAddr(ctx, ns)
+		if err != nil {
+			var dnsErr *net.DNSError
+			if errors.As(err, &dnsErr) && dnsErr.IsNotFound && nameServerMissing(ctx, pool, ns) {
+				result.add(domain, ns, "", "dangling_ns", "high", "Name server does not exist",
+					fmt.Sprintf("%s is delegated to %s, which has no address records. If the name server's domain can be registered, whoever registers it controls %s.", domain, ns, domain),
+					nil)
+			} else {
+				log.Printf("[DNS AUDIT] [WARN] Failed to resolve name server %s: %v", ns, err)
+			}
+			continue
+		}
+		if len(addrs) > dnsAuditAddressesPerNS {
+			addrs = addrs[:dnsAuditAddressesPerNS]
+		}
+
+		for _, addr := range addrs {
+			ip := addr.IP.String()
+
+			serial, ok := checkAuthoritative(ctx, result, domain, ns, ip, opts)
+			if !ok {
+				continue
+			}
+			serials[ns+" ("+ip+")"] = serial
+			if authoritative == "" {
+				authoritative = ip
+			}
+
+			checkOpenRecursion(ctx, result, domain, ns, ip, opts)
+
+			// A transfer that breaks off part way still leaked the records sent so far
+			records, err := dnsresolver.Transfer(ctx, ip, domain, dnsAuditTransferTimeout)
+			if len(records) == 0 {
+				continue
+			}
+			if err != nil {
+				log.Printf("[DNS AUDIT] [WARN] Zone transfer of %s from %s stopped early: %v", domain, ip, err)
+			}
+			names := make(map[string]bool)
+			for _, rr := range records {
+				names[rr.Name] = true
+				if isDiscoveredName(rr.Name, domain) {
+					discovered[rr.Name] = true
+				}
+			}
+			result.add(domain, ns, ip, "zone_transfer", "high", "Zone transfer allowed",
+				fmt.Sprintf("%s (%s) answered an AXFR request for %s with %d records covering %d names.", ns, ip, domain, len(records), len(names)),
+				map[string]interface{}{"record_count": len(records), "names": sampleNames(names)})
+		}
+	}
+
+	if distinct := distinctSerials(serials); distinct > 1 {
+		result.add(domain, "", "", "serial_mismatch", "low", "Name servers serve different zone versions",
+			fmt.Sprintf("The authoritative name servers of %s returned %d different SOA serials, so some of them are not being updated.", domain, distinct),
+			map[string]interface{}{"serials": serials})
+	}
+
+	if authoritative != "" {
+		for _, name := range checkDNSSECEnumeration(ctx, result, domain, authoritative, opts) {
+			discovered[name] = true
+		}
+	}
+
+	for name := range discovered {
+		result.discovered = append(result.discovered, name)
+	}
+	sort.Strings(result.discovered)
+	return result
+}
+
+// nameServerMissing reports whether the name of a name server does not exist. Only a confirmed
+// NXDOMAIN counts: a name that exists without address records is broken, but it belongs to a
+// live zone and can't be registered by someone else.
+func nameServerMissing(ctx context.Context, pool *dnsresolver.Pool, ns string) bool {
+	msg, err := pool.Query(ctx, ns, dnsresolver.TypeA)
+	return err == nil && msg.Rcode == dnsresolver.RcodeNXDomain
+}
+
+var dnsAuditRcodeNames = map[int]string{
+	dnsresolver.RcodeFormErr:  "FORMERR",
+	dnsresolver.RcodeServFail: "SERVFAIL",
+	dnsresolver.RcodeNXDomain: "NXDOMAIN",
+	dnsresolver.RcodeRefused:  "REFUSED",
+}
+
+// checkAuthoritative asks a name server for the domain's SOA without recursion. A server that
+// refuses, fails or answers without the AA flag is a lame delegation. One that doesn't answer
+// at all is retried and then reported as unreachable, since packet loss looks the same.
+func checkAuthoritative(ctx context.Context, result *dnsAuditResult, domain, ns, ip string, opts dnsresolver.QueryOptions) (uint32, bool) {
+	lame := func(reason string) {
+		result.add(domain, ns, ip, "lame_delegation", "medium", "Lame delegation",
+			fmt.Sprintf("%s is listed as a name server for %s but %s (%s).", ns, domain, reason, ip), nil)
+	}
+
+	var msg *dnsresolver.Message
+	var err error
+	for attempt := 0; attempt < dnsAuditQueryAttempts; attempt++ {
+		msg, err = dnsresolver.Exchange(ctx, ip, domain, dnsresolver.TypeSOA, opts)
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+	}
+	if err != nil {
+		result.add(domain, ns, ip, "ns_unreachable", "low", "Name server unreachable",
+			fmt.Sprintf("%s is listed as a name server for %s but %s did not answer %d SOA queries: %v.", ns, domain, ip, dnsAuditQueryAttempts, err), nil)
+		return 0, false
+	}
+	if msg.Rcode != dnsresolver.RcodeSuccess {
+		lame("answered " + dnsAuditRcodeNames[msg.Rcode])
+		return 0, false
+	}
+	if !msg.Authoritative {
+		lame("is not authoritative for the zone")
+		return 0, false
+	}
+	for _, rr := range msg.Answers {
+		if rr.Type == dnsresolver.TypeSOA && rr.Name == domain {
+			return rr.Serial, true
+		}
+	}
+	lame("returned no SOA record for the zone")
+	return 0, false
+}
+
+// checkOpenRecursion asks a name server to resolve a name outside the zone. An authoritative
+// server that does so recurses for anyone and can be used for cache snooping and amplification.
+func checkOpenRecursion(ctx context.Context, result *dnsAuditResult, domain, ns, ip string, opts dnsresolver.QueryOptions) {
+	opts.Recursive = true
+	msg, err := dnsresolver.Exchange(ctx, ip, dnsAuditRecursionProbe, dnsresolver.TypeA, opts)
+	if err != nil || msg.Rcode != dnsresolver.RcodeSuccess || !msg.RecursionAvailable || msg.Authoritative {
+		return
+	}
+	for _, rr := range msg.Answers {
+		if rr.Type == dnsresolver.TypeA {
+			result.add(domain, ns, ip, "open_recursion", "medium", "Open recursive resolver",
+				fmt.Sprintf("%s (%s) resolved %s on behalf of an outside client. Authoritative servers should not offer recursion.", ns, ip, dnsAuditRecursionProbe),
+				map[string]interface{}{"probe": dnsAuditRecursionProbe, "answer": rr.IP.String()})
+			return
+		}
+	}
+}
+
+// checkDNSSECEnumeration walks the NSEC chain of a signed zone, or collects NSEC3 hashes when
+// the zone uses hashed denial, and returns the names found by walking
+func checkDNSSECEnumeration(ctx context.Context, result *dnsAuditResult, domain, ip string, opts dnsresolver.QueryOptions) []string {
+	names, err := dnsresolver.WalkNSEC(ctx, ip, domain, dnsAuditMaxWalkNames, opts)
+	if errors.Is(err, dnsresolver.ErrNotWalkable) {
+		log.Printf("[DNS AUDIT] [INFO] %s uses synthesized NSEC records", domain)
+		return nil
+	}
+	if len(names) > 1 {
+		var discovered []string
+		nameSet := make(map[string]bool)
+		for _, name := range names {
+			nameSet[name] = true
+			if isDiscoveredName(name, domain) {
+				discovered = append(discovered, name)
+			}
+		}
+		details := fmt.Sprintf("The NSEC chain of %s can be walked, which lists every name in the zone. %d names were enumerated.", domain, len(names))
+		if err != nil {
+			details += " The walk stopped early: " + err.Error()
+		}
+		result.add(domain, "", ip, "nsec_walk", "medium", "Zone enumerable through NSEC walking", details,
+			map[string]interface{}{"name_count": len(names), "names": sampleNames(nameSet)})
+		return discovered
+	}
+
+	chain, err := dnsresolver.CollectNSEC3(ctx, ip, domain, dnsAuditNSEC3Probes, opts)
+	if err != nil || chain == nil || len(chain.Hashes) == 0 {
+		return nil
+	}
+	result.add(domain, "", ip, "nsec3_hashes", "info", "NSEC3 hashes collected",
+		fmt.Sprintf("%s uses NSEC3 with %d iterations. %d hashed names were collected and can be cracked offline to recover names in the zone.", domain, chain.Iterations, len(chain.Hashes)),
+		chain)
+	return nil
+}
+
+// isDiscoveredName reports whether a name from a zone is a hostname below the domain. Wildcards
+// and underscore labels such as _dmarc or SRV owners are skipped.
+func isDiscoveredName(name, domain string) bool {
+	if name == domain || !strings.HasSuffix(name, "."+domain) {
+		return false
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "*" || strings.HasPrefix(label, "_") {
+			return false
+		}
+	}
+	return true
+}
+
+func sampleNames(names map[string]bool) []string {
+	sample := make([]string, 0, len(names))
+	for name := range names {
+		sample = append(sample, name)
+	}
+	sort.Strings(sample)
+	if len(sample) > dnsAuditEvidenceSamples {
+		sample = sample[:dnsAuditEvidenceSamples]
+	}
+	return sample
+}
+
+func distinctSerials(serials map[string]uint32) int {
+	distinct := make(map[uint32]bool)
+	for _, serial := range serials {
+		distinct[serial] = true
+	}
+	return len(distinct)
+}
+
+// saveDNSAuditFindings replaces the scope target's findings with the latest results
+func saveDNSAuditFindings(scanID, scopeTargetID string, findings []*DNSAuditFinding) error {
+	tx, err := dbPool.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(), `DELETE FROM dns_audit_findings WHERE scope_target_id = $1`, scopeTargetID); err != nil {
+		return fmt.Errorf("failed to delete old findings: %v", err)
+	}
+
+	for _, f := range findings {
+		var evidence interface{}
+		if len(f.Evidence) > 0 {
+			evidence = []byte(f.Evidence)
+		}
+		_, err := tx.Exec(context.Background(), `
+			INSERT INTO dns_audit_findings
+				(scan_id, scope_target_id, domain, nameserver, nameserver_ip, check_type, severity, title, details, evidence)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (scope_target_id, domain, nameserver, nameserver_ip, check_type) DO NOTHING`,
+			scanID, scopeTargetID, f.Domain, f.Nameserver, f.NameserverIP, f.CheckType, f.Severity, f.Title, f.Details, evidence)
+		if err != nil {
+			return fmt.Errorf("failed to insert %s finding for %s: %v", f.CheckType, f.Domain, err)
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func updateDNSAuditScan(scanID, status, result, errorMessage string, findingsCount, discoveredCount, newCount int, execTime string) {
+	_, err := dbPool.Exec(context.Background(), `
+		UPDATE dns_audit_scans
+		SET status = $1, result = NULLIF($2, ''), error = NULLIF($3, ''), findings_count = $4,
+		    discovered_count = $5, new_count = $6, execution_time = NULLIF($7, '')
+		WHERE scan_id = $8`,
+		status, result, errorMessage, findingsCount, discoveredCount, newCount, execTime, scanID)
+	if err != nil {
+		log.Printf("[DNS AUDIT] [ERROR] Failed to update scan status: %v", err)
+	}
+}
+
+const dnsAuditScanColumns = `id, scan_id, scope_target_id, status, domains, result, findings_count,
+	discovered_count, new_count, error, execution_time, created_at`
+
+func scanDNSAuditScan(row interface{ Scan(...interface{}) error }) (DNSAuditScan, error) {
+	var scan DNSAuditScan
+	var domainsJSON []byte
+	err := row.Scan(
+		&scan.ID,
+		&scan.ScanID,
+		&scan.ScopeTargetID,
+		&scan.Status,
+		&domainsJSON,
+		&scan.Result,
+		&scan.FindingsCount,
+		&scan.DiscoveredCount,
+		&scan.NewCount,
+		&scan.Error,
+		&scan.ExecTime,
+		&scan.CreatedAt,
+	)
+	scan.Domains = []string{}
+	if err == nil && len(domainsJSON) > 0 {
+		json.Unmarshal(domainsJSON, &scan.Domains)
+	}
+	return scan, err
+}
+
+func GetDNSAuditScanStatus(w http.ResponseWriter, r *http.Request) {
+	scanID := mux.Vars(r)["scan_id"]
+
+	scan, err := scanDNSAuditScan(dbPool.QueryRow(context.Background(),
+		`SELECT `+dnsAuditScanColumns+` FROM dns_audit_scans WHERE scan_id = $1`, scanID))
+	if err != nil {
+		http.Error(w, "Scan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scan)
+}
+
+func GetDNSAuditScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT `+dnsAuditScanColumns+` FROM dns_audit_scans WHERE scope_target_id = $1 ORDER BY created_at DESC`,
+		scopeTargetID)
+	if err != nil {
+		log.Printf("[DNS AUDIT] [ERROR] Failed to get scans for target %s: %v", scopeTargetID, err)
+		http.Error(w, "Failed to get scans", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	scans := []DNSAuditScan{}
+	for rows.Next() {
+		scan, err := scanDNSAuditScan(rows)
+		if err != nil {
+			log.Printf("[DNS AUDIT] [ERROR] Failed to scan row: %v", err)
+			continue
+		}
+		scans = append(scans, scan)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scans)
+}
+
+func fetchDNSAuditFindings(scopeTargetID string) ([]DNSAuditFinding, error) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, scan_id, scope_target_id, domain, nameserver, nameserver_ip, check_type, severity,
+		       title, details, evidence, created_at
+		FROM dns_audit_findings
+		WHERE scope_target_id = $1::uuid
+		ORDER BY CASE severity WHEN 'critical' THEN 0 WHEN 'high' THEN 1 WHEN 'medium' THEN 2 WHEN 'low' THEN 3 ELSE 4 END,
+		         domain ASC, nameserver ASC`, scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	findings := []DNSAuditFinding{}
+	for rows.Next() {
+		var f DNSAuditFinding
+		var evidence []byte
+		if err := rows.Scan(&f.ID, &f.ScanID, &f.ScopeTargetID, &f.Domain, &f.Nameserver, &f.NameserverIP,
+			&f.CheckType, &f.Severity, &f.Title, &f.Details, &evidence, &f.CreatedAt); err != nil {
+			log.Printf("[DNS AUDIT] [ERROR] Failed to scan finding: %v", err)
+			continue
+		}
+		if len(evidence) > 0 {
+			f.Evidence = evidence
+		}
+		findings = append(findings, f)
+	}
+	return findings, rows.Err()
+}
+
+// GetDNSAuditFindings returns the latest DNS audit findings for a scope target
+func GetDNSAuditFindings(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	findings, err := fetchDNSAuditFindings(scopeTargetID)
+	if err != nil {
+		log.Printf("[DNS AUDIT] [ERROR] Failed to get findings: %v", err)
+		http.Error(w, "Failed to get DNS audit findings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(findings)
+}
@@ -0,0 +1,152 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"ars0n-framework-v2-server/dnsresolver"
+	"ars0n-framework-v2-server/dnsresolver/dnstest"
+)
+
+func startDNSStub(t *testing.T, handler dnstest.Handler) string {
+	t.Helper()
+	server, err := dnstest.NewServer(handler)
+	if err != nil {
+		t.Fatalf("failed to start DNS stub: %v", err)
+	}
+	t.Cleanup(server.Close)
+	return server.Addr
+}
+
+func soaResponse(authoritative bool) []dnstest.Response {
+	return []dnstest.Response{{Authoritative: authoritative, Answers: []dnsresolver.Record{
+		{Name: "example.com", Type: dnsresolver.TypeSOA, Target: "ns1.example.com", Serial: 2024010101},
+	}}}
+}
+
+func checkTypes(result *dnsAuditResult) []string {
+	var types []string
+	for _, f := range result.findings {
+		types = append(types, f.CheckType)
+	}
+	return types
+}
+
+func TestCheckAuthoritative(t *testing.T) {
+	opts := dnsresolver.QueryOptions{Timeout: 200 * time.Millisecond}
+
+	tests := []struct {
+		name    string
+		handler dnstest.Handler
+		ok      bool
+		finding string
+	}{
+		{
+			name:    "authoritative",
+			handler: func(q dnstest.Query) []dnstest.Response { return soaResponse(true) },
+			ok:      true,
+		},
+		{
+			name:    "not authoritative",
+			handler: func(q dnstest.Query) []dnstest.Response { return soaResponse(false) },
+			finding: "lame_delegation",
+		},
+		{
+			name: "refused",
+			handler: func(q dnstest.Query) []dnstest.Response {
+				return []dnstest.Response{{Rcode: dnsresolver.RcodeRefused}}
+			},
+			finding: "lame_delegation",
+		},
+		{
+			name:    "timeout",
+			handler: func(q dnstest.Query) []dnstest.Response { return nil },
+			finding: "ns_unreachable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := startDNSStub(t, tt.handler)
+			result := &dnsAuditResult{}
+			serial, ok := checkAuthoritative(context.Background(), result, "example.com", "ns1.example.com", addr, opts)
+			if ok != tt.ok {
+				t.Fatalf("got ok %v, want %v", ok, tt.ok)
+			}
+			if ok && serial != 2024010101 {
+				t.Errorf("got serial %d", serial)
+			}
+			types := checkTypes(result)
+			if tt.finding == "" && len(types) > 0 {
+				t.Errorf("unexpected findings %v", types)
+			}
+			if tt.finding != "" && (len(types) != 1 || types[0] != tt.finding) {
+				t.Errorf("got findings %v, want %s", types, tt.finding)
+			}
+		})
+	}
+
+	t.Run("single lost packet", func(t *testing.T) {
+		var queries int32
+		addr := startDNSStub(t, func(q dnstest.Query) []dnstest.Response {
+			if atomic.AddInt32(&queries, 1) == 1 {
+				return nil
+			}
+			return soaResponse(true)
+		})
+		result := &dnsAuditResult{}
+		if _, ok := checkAuthoritative(context.Background(), result, "example.com", "ns1.example.com", addr, opts); !ok || len(result.findings) > 0 {
+			t.Errorf("got ok %v and findings %v after one dropped query", ok, checkTypes(result))
+		}
+	})
+}
+
+func TestCheckOpenRecursion(t *testing.T) {
+	opts := dnsresolver.QueryOptions{Timeout: 200 * time.Millisecond}
+
+	open := startDNSStub(t, func(q dnstest.Query) []dnstest.Response {
+		if !q.Recursive {
+			return []dnstest.Response{{Rcode: dnsresolver.RcodeRefused}}
+		}
+		return []dnstest.Response{{RecursionAvailable: true, Answers: []dnsresolver.Record{
+			{Name: q.Name, Type: dnsresolver.TypeA, IP: net.ParseIP("93.184.216.34")},
+		}}}
+	})
+	result := &dnsAuditResult{}
+	checkOpenRecursion(context.Background(), result, "example.com", "ns1.example.com", open, opts)
+	if types := checkTypes(result); len(types) != 1 || types[0] != "open_recursion" {
+		t.Errorf("open resolver gave findings %v", types)
+	}
+
+	closed := startDNSStub(t, func(q dnstest.Query) []dnstest.Response {
+		return []dnstest.Response{{Rcode: dnsresolver.RcodeRefused}}
+	})
+	result = &dnsAuditResult{}
+	checkOpenRecursion(context.Background(), result, "example.com", "ns1.example.com", closed, opts)
+	if len(result.findings) > 0 {
+		t.Errorf("closed server gave findings %v", checkTypes(result))
+	}
+}
+
+func TestNameServerMissing(t *testing.T) {
+	addr := startDNSStub(t, func(q dnstest.Query) []dnstest.Response {
+		if q.Name == "ns1.expired-dns.com" {
+			return []dnstest.Response{{Rcode: dnsresolver.RcodeNXDomain, RecursionAvailable: true}}
+		}
+		// The name exists but has no address records
+		return []dnstest.Response{{RecursionAvailable: true}}
+	})
+	options := dnsresolver.DefaultOptions()
+	options.Timeout = 200 * time.Millisecond
+	pool := dnsresolver.NewPool([]string{addr}, options)
+
+	if !nameServerMissing(context.Background(), pool, "ns1.expired-dns.com") {
+		t.Error("NXDOMAIN name server not reported missing")
+	}
+	if nameServerMissing(context.Background(), pool, "ns1.example.com") {
+		t.Error("name server without addresses reported missing")
+	}
+}
@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"ars0n-framework-v2-server/dnsresolver"
+)
+
+const (
+	dnsHealthCheckTimeout  = 30 * time.Second
+	dnsHealthCheckInterval = 15 * time.Minute
+)
+
+// DefaultDNSResolvers seeds the dns_resolvers table the first time the shared pool is loaded.
+// They are the public resolvers Amass used to be started with.
+var DefaultDNSResolvers = []string{
+	"8.8.8.8",
+	"8.8.4.4",
+	"1.1.1.1",
+	"1.0.0.1",
+	"9.9.9.9",
+	"149.112.112.112",
+	"64.6.64.6",
+	"64.6.65.6",
+	"208.67.222.222",
+	"208.67.220.220",
+	"8.26.56.26",
+	"8.20.247.20",
+	"185.228.168.9",
+	"185.228.169.9",
+	"76.76.19.19",
+	"76.223.122.150",
+	"76.223.100.101",
+	"198.101.242.72",
+	"176.103.130.130",
+	"176.103.130.131",
+	"94.140.14.14",
+	"94.140.15.15",
+	"77.88.8.8",
+	"77.88.8.1",
+}
+
+var (
+	sharedDNSPool     *dnsresolver.Pool
+	sharedDNSPoolOnce sync.Once
+)
+
+// SharedDNSPool returns the resolver pool used for all in-process DNS lookups. It is loaded
+// from dns_resolvers on first use and health checked in the background every 15 minutes.
+func SharedDNSPool() *dnsresolver.Pool {
+	sharedDNSPoolOnce.Do(func() {
+		sharedDNSPool = dnsresolver.NewPool(nil, dnsresolver.DefaultOptions())
+		addresses, err := loadDNSResolverAddresses()
+		if err != nil {
+			log.Printf("[DNS RESOLVERS] [ERROR] Failed to load resolvers, using defaults: %v", err)
+			addresses = DefaultDNSResolvers
+		} else if len(addresses) == 0 {
+			addresses = seedDNSResolvers()
+		}
+		sharedDNSPool.SetResolvers(addresses)
+		go watchDNSResolverHealth()
+	})
+	return sharedDNSPool
+}
+
+func loadDNSResolverAddresses() ([]string, error) {
+	rows, err := dbPool.Query(context.Background(), `SELECT address FROM dns_resolvers ORDER BY created_at, address`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	addresses := []string{}
+	for rows.Next() {
+		var address string
+		if err := rows.Scan(&address); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, address)
+	}
+	return addresses, rows.Err()
+}
+
+// seedDNSResolvers stores the default resolvers the first time the pool is loaded
+func seedDNSResolvers() []string {
+	addresses := []string{}
+	for _, resolver := range DefaultDNSResolvers {
+		address, err := dnsresolver.NormalizeAddress(resolver)
+		if err != nil {
+			continue
+		}
+		if _, err := dbPool.Exec(context.Background(), `INSERT INTO dns_resolvers (address) VALUES ($1) ON CONFLICT (address) DO NOTHING`, address); err != nil {
+			log.Printf("[DNS RESOLVERS] [ERROR] Failed to seed resolver %s: %v", address, err)
+		}
+		addresses = append(addresses, address)
+	}
+	log.Printf("[DNS RESOLVERS] [INFO] Seeded %d default resolvers", len(addresses))
+	return addresses
+}
+
+// watchDNSResolverHealth health checks the shared pool now and then on a ticker, so resolvers
+// that start failing or lying are taken out of rotation and recovered ones come back
+func watchDNSResolverHealth() {
+	ticker := time.NewTicker(dnsHealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		runDNSResolverHealthCheck()
+		<-ticker.C
+	}
+}
+
+// runDNSResolverHealthCheck checks every resolver in the shared pool and stores the results
+func runDNSResolverHealthCheck() []dnsresolver.ResolverStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), dnsHealthCheckTimeout)
+	defer cancel()
+
+	statuses := SharedDNSPool().HealthCheck(ctx)
+	usable := 0
+	for _, status := range statuses {
+		if status.Healthy && !status.Lying {
+			usable++
+		}
+		if status.Lying {
+			log.Printf("[DNS RESOLVERS] [WARN] Resolver %s answers for non-existent names and was taken out of rotation", status.Address)
+		}
+		_, err := dbPool.Exec(context.Background(), `
+			UPDATE dns_resolvers
+			SET healthy = $2, lying = $3, avg_latency_ms = $4, last_error = NULLIF($5, ''), last_checked = $6
+			WHERE address = $1`,
+			status.Address, status.Healthy, status.Lying, status.AvgLatencyMs, status.LastError, status.LastChecked)
+		if err != nil {
+			log.Printf("[DNS RESOLVERS] [ERROR] Failed to save health of %s: %v", status.Address, err)
+		}
+	}
+	log.Printf("[DNS RESOLVERS] [INFO] Health check complete: %d of %d resolvers usable", usable, len(statuses))
+	return statuses
+}
+
+// GetAmassResolverArgs returns the "-r" arguments for the resolvers currently in rotation
+func GetAmassResolverArgs() []string {
+	addresses := SharedDNSPool().Addresses()
+	if len(addresses) == 0 {
+		addresses = DefaultDNSResolvers
+	}
+
+	var args []string
+	for _, address := range addresses {
+		if host, port, err := net.SplitHostPort(address); err == nil && port == "53" {
+			address = host
+		}
+		args = append(args, "-r", address)
+	}
+	return args
+}
+
+// GetDNSResolvers lists the configured resolvers with their health and query statistics
+func GetDNSResolvers(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(SharedDNSPool().Status())
+}
+
+// UpdateDNSResolvers replaces the resolver list. Resolvers must be IP addresses, optionally
+// with a port.
+func UpdateDNSResolvers(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Resolvers []string `json:"resolvers"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	addresses := []string{}
+	seen := make(map[string]bool)
+	for _, resolver := range payload.Resolvers {
+		if strings.TrimSpace(resolver) == "" {
+			continue
+		}
+		address, err := dnsresolver.NormalizeAddress(resolver)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !seen[address] {
+			seen[address] = true
+			addresses = append(addresses, address)
+		}
+	}
+	if len(addresses) == 0 {
+		http.Error(w, "At least one resolver is required", http.StatusBadRequest)
+		return
+	}
+
+	tx, err := dbPool.Begin(context.Background())
+	if err != nil {
+		log.Printf("[DNS RESOLVERS] [ERROR] Failed to begin transaction: %v", err)
+		http.Error(w, "Failed to update DNS resolvers", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(), `DELETE FROM dns_resolvers WHERE NOT (address = ANY($1))`, addresses); err != nil {
+		log.Printf("[DNS RESOLVERS] [ERROR] Failed to remove resolvers: %v", err)
+		http.Error(w, "Failed to update DNS resolvers", http.StatusInternalServerError)
+		return
+	}
+	for _, address := range addresses {
+		if _, err := tx.Exec(context.Background(), `INSERT INTO dns_resolvers (address) VALUES ($1) ON CONFLICT (address) DO NOTHING`, address); err != nil {
+			log.Printf("[DNS RESOLVERS] [ERROR] Failed to add resolver %s: %v", address, err)
+			http.Error(w, "Failed to update DNS resolvers", http.StatusInternalServerError)
+			return
+		}
+	}
+	if err := tx.Commit(context.Background()); err != nil {
+		log.Printf("[DNS RESOLVERS] [ERROR] Failed to commit resolvers: %v", err)
+		http.Error(w, "Failed to update DNS resolvers", http.StatusInternalServerError)
+		return
+	}
+
+	SharedDNSPool().SetResolvers(addresses)
+	log.Printf("[DNS RESOLVERS] [INFO] Resolver list updated to %d resolvers", len(addresses))
+
+	statuses := runDNSResolverHealthCheck()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statuses)
+}
+
+// RunDNSResolverHealthCheck checks every resolver against the canary domain and returns the results
+func RunDNSResolverHealthCheck(w http.ResponseWriter, r *http.Request) {
+	statuses := runDNSResolverHealthCheck()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statuses)
+}
@@ -0,0 +1,956 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	emailSecurityDomainConcurrency = 10
+	emailSecurityDomainTimeout     = 2 * time.Minute
+	emailSecurityPolicyTimeout     = 10 * time.Second
+	emailSecurityPolicyLimit       = 64 * 1024
+	// RFC 7208 limits an SPF evaluation to 10 lookups and 2 lookups that return nothing
+	spfMaxLookups     = 10
+	spfMaxVoidLookups = 2
+	// Includes are followed this deep, which is enough to count well past the limit
+	spfMaxDepth = 10
+)
+
+// Selectors used by the common mail providers and marketing platforms. DKIM keys can only be
+// found by guessing, so a domain signing with a custom selector shows up as having no key.
+var commonDKIMSelectors = []string{
+	"default", "dkim", "mail", "smtp", "email", "k1", "k2", "k3", "s1", "s2", "selector1", "selector2",
+	"google", "key1", "key2", "sig1", "fm1", "fm2", "fm3", "mxvault", "mandrill", "mailjet", "zoho",
+	"protonmail", "protonmail2", "protonmail3", "everlytickey1", "everlytickey2", "hs1", "hs2", "cm", "m1",
+}
+
+// Points taken off a domain's score for each finding
+var emailSecurityDeductions = map[string]int{
+	"critical": 40,
+	"high":     25,
+	"medium":   10,
+	"low":      3,
+}
+
+// EmailSecurityFinding is one graded issue in a domain's mail configuration
+type EmailSecurityFinding struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Title    string `json:"title"`
+	Details  string `json:"details"`
+}
+
+// DKIMSelector is a DKIM key published under one of the common selectors
+type DKIMSelector struct {
+	Selector string `json:"selector"`
+	KeyType  string `json:"key_type"`
+	KeyBits  int    `json:"key_bits,omitempty"`
+	Revoked  bool   `json:"revoked"`
+}
+
+// EmailSecurityResult is the mail security posture of one root domain
+type EmailSecurityResult struct {
+	ID            string                 `json:"id"`
+	ScanID        string                 `json:"scan_id"`
+	ScopeTargetID string                 `json:"scope_target_id"`
+	Domain        string                 `json:"domain"`
+	Grade         string                 `json:"grade"`
+	Score         int                    `json:"score"`
+	MXRecords     []string               `json:"mx_records"`
+	SPFRecord     *string                `json:"spf_record"`
+	SPFLookups    int                    `json:"spf_lookups"`
+	DMARCRecord   *string                `json:"dmarc_record"`
+	DMARCPolicy   *string                `json:"dmarc_policy"`
+	DKIMSelectors []DKIMSelector         `json:"dkim_selectors"`
+	MTASTSMode    *string                `json:"mta_sts_mode"`
+	TLSRPTRecord  *string                `json:"tls_rpt_record"`
+	Findings      []EmailSecurityFinding `json:"findings"`
+	CheckedAt     time.Time              `json:"checked_at"`
+}
+
+type EmailSecurityScan struct {
+	ID             string    `json:"id"`
+	ScanID         string    `json:"scan_id"`
+	ScopeTargetID  string    `json:"scope_target_id"`
+	Status         string    `json:"status"`
+	DomainsChecked int       `json:"domains_checked"`
+	FindingsCount  int       `json:"findings_count"`
+	Error          *string   `json:"error"`
+	ExecTime       *string   `json:"execution_time"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// EmailSecuritySummary rolls the latest results of a scope target up by grade and severity
+type EmailSecuritySummary struct {
+	ScopeTargetID string                `json:"scope_target_id"`
+	Domains       int                   `json:"domains"`
+	AverageScore  int                   `json:"average_score"`
+	Grades        map[string]int        `json:"grades"`
+	Severities    map[string]int        `json:"severities"`
+	LatestScan    *EmailSecurityScan    `json:"latest_scan"`
+	Results       []EmailSecurityResult `json:"results"`
+}
+
+func (r *EmailSecurityResult) add(check, severity, title, details string) {
+	r.Findings = append(r.Findings, EmailSecurityFinding{Check: check, Severity: severity, Title: title, Details: details})
+}
+
+// grade scores the result from its findings
+func (r *EmailSecurityResult) grade() {
+	score := 100
+	for _, f := range r.Findings {
+		score -= emailSecurityDeductions[f.Severity]
+	}
+	if score < 0 {
+		score = 0
+	}
+	r.Score = score
+	r.Grade = letterGrade(score)
+}
+
+// letterGrade maps a score out of 100 to a letter grade
+func letterGrade(score int) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 65:
+		return "C"
+	case score >= 50:
+		return "D"
+	default:
+		return "F"
+	}
+}
+
+// emailSecurityDomains returns the root domains to analyze: the root domain of a wildcard target
+// or the consolidated domains of a company
+func emailSecurityDomains(scopeTargetID string) ([]string, error) {
+	var targetType, scopeTarget string
+	err := dbPool.QueryRow(context.Background(),
+		`SELECT type, scope_target FROM scope_targets WHERE id = $1`, scopeTargetID).Scan(&targetType, &scopeTarget)
+	if err != nil {
+		return nil, fmt.Errorf("scope target not found: %v", err)
+	}
+
+	switch targetType {
+	case "Wildcard":
+		return []string{normalizeDNSName(strings.TrimPrefix(scopeTarget, "*."))}, nil
+	case "Company":
+	default:
+		return nil, fmt.Errorf("email security analysis is not supported for %s scope targets", targetType)
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT domain FROM consolidated_company_domains WHERE scope_target_id = $1 ORDER BY domain`, scopeTargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get company domains: %v", err)
+	}
+	defer rows.Close()
+
+	var domains []string
+	for rows.Next() {
+		var domain string
+		if err := rows.Scan(&domain); err == nil {
+			domains = append(domains, normalizeDNSName(domain))
+		}
+	}
+	return domains, rows.Err()
+}
+
+func RunEmailSecurityScan(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ScopeTargetID string   `json:"scope_target_id"`
+		Domains       []string `json:"domains,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ScopeTargetID == "" {
+		http.Error(w, "Invalid request body. `scope_target_id` is required.", http.StatusBadRequest)
+		return
+	}
+
+	domains, err := emailSecurityDomains(payload.ScopeTargetID)
+	if err != nil {
+		log.Printf("[EMAIL SECURITY] [ERROR] %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(payload.Domains) > 0 {
+		domains = nil
+		for _, domain := range payload.Domains {
+			if domain = normalizeDNSName(domain); domain != "" {
+				domains = append(domains, domain)
+			}
+		}
+	}
+	if len(domains) == 0 {
+		http.Error(w, "No domains to analyze. Consolidate company domains first.", http.StatusBadRequest)
+		return
+	}
+
+	scanID := uuid.New().String()
+	_, err = dbPool.Exec(context.Background(),
+		`INSERT INTO email_security_scans (scan_id, scope_target_id, status) VALUES ($1, $2, $3)`,
+		scanID, payload.ScopeTargetID, "pending")
+	if err != nil {
+		log.Printf("[EMAIL SECURITY] [ERROR] Failed to create scan record: %v", err)
+		http.Error(w, "Failed to create scan record.", http.StatusInternalServerError)
+		return
+	}
+
+	go ExecuteEmailSecurityScan(scanID, payload.ScopeTargetID, domains)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"scan_id": scanID})
+}
+
+// ExecuteEmailSecurityScan analyzes every domain, replaces the stored results and copies the
+// records it found onto the matching FQDN assets
+func ExecuteEmailSecurityScan(scanID, scopeTargetID string, domains []string) {
+	log.Printf("[EMAIL SECURITY] [INFO] Analyzing %d domains for scope target %s (scan ID: %s)", len(domains), scopeTargetID, scanID)
+	startTime := time.Now()
+	updateEmailSecurityScan(scanID, "processing", 0, 0, "", "")
+
+	checker := newEmailSecurityChecker()
+	results := make([]*EmailSecurityResult, len(domains))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, emailSecurityDomainConcurrency)
+
+	for i, domain := range domains {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, domain string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), emailSecurityDomainTimeout)
+			defer cancel()
+			results[i] = checker.analyze(ctx, domain)
+			log.Printf("[EMAIL SECURITY] [INFO] %s: grade %s (%d findings)", domain, results[i].Grade, len(results[i].Findings))
+		}(i, domain)
+	}
+	wg.Wait()
+
+	findingsCount := 0
+	for _, result := range results {
+		findingsCount += len(result.Findings)
+	}
+
+	if err := saveEmailSecurityResults(scanID, scopeTargetID, results); err != nil {
+		log.Printf("[EMAIL SECURITY] [ERROR] Failed to save results: %v", err)
+		updateEmailSecurityScan(scanID, "error", len(domains), findingsCount, err.Error(), time.Since(startTime).String())
+		return
+	}
+
+	execTime := time.Since(startTime).String()
+	updateEmailSecurityScan(scanID, "success", len(domains), findingsCount, "", execTime)
+	log.Printf("[EMAIL SECURITY] [INFO] Scan %s completed in %s: %d domains, %d findings", scanID, execTime, len(domains), findingsCount)
+}
+
+type emailSecurityChecker struct {
+	client *http.Client
+}
+
+func newEmailSecurityChecker() *emailSecurityChecker {
+	return &emailSecurityChecker{
+		client: &http.Client{
+			Timeout: emailSecurityPolicyTimeout,
+			// RFC 8461 forbids following redirects when fetching the policy
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// lookupEmailTXT returns the TXT records at name. A name that doesn't exist has no records.
+func lookupEmailTXT(ctx context.Context, name string) ([]string, error) {
+	records, err := SharedDNSPool().LookupTXT(ctx, name)
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return nil, nil
+	}
+	return records, err
+}
+
+// recordsWithPrefix returns the records that start with a version tag such as "v=spf1"
+func recordsWithPrefix(records []string, prefix string) []string {
+	var matched []string
+	for _, record := range records {
+		record = strings.TrimSpace(record)
+		if len(record) >= len(prefix) && strings.EqualFold(record[:len(prefix)], prefix) &&
+			(len(record) == len(prefix) || record[len(prefix)] == ' ' || record[len(prefix)] == ';') {
+			matched = append(matched, record)
+		}
+	}
+	return matched
+}
+
+func (c *emailSecurityChecker) analyze(ctx context.Context, domain string) *EmailSecurityResult {
+	result := &EmailSecurityResult{
+		Domain:        domain,
+		MXRecords:     []string{},
+		DKIMSelectors: []DKIMSelector{},
+		Findings:      []EmailSecurityFinding{},
+		CheckedAt:     time.Now(),
+	}
+
+	receivesMail := c.checkMX(ctx, result)
+	c.checkSPF(ctx, result, receivesMail)
+	c.checkDMARC(ctx, result)
+	if receivesMail {
+		c.checkDKIM(ctx, result)
+		c.checkMTASTS(ctx, result)
+		c.checkTLSRPT(ctx, result)
+	}
+
+	result.grade()
+	return result
+}
+
+// checkMX records the domain's mail servers and reports whether it accepts mail. A null MX
+// (RFC 7505) means it explicitly does not.
+func (c *emailSecurityChecker) checkMX(ctx context.Context, result *EmailSecurityResult) bool {
+	records, err := SharedDNSPool().LookupMX(ctx, result.Domain)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+			result.add("mx", "info", "MX lookup failed", fmt.Sprintf("The MX records of %s could not be looked up: %v", result.Domain, err))
+		}
+		return false
+	}
+	for _, mx := range records {
+		host := normalizeDNSName(mx.Host)
+		if host == "" {
+			continue
+		}
+		result.MXRecords = append(result.MXRecords, host)
+	}
+	return len(result.MXRecords) > 0
+}
+
+// spfEvaluation is what following an SPF record and its includes turned up
+type spfEvaluation struct {
+	lookups      int
+	voidLookups  int
+	allQualifier string
+	hasRedirect  bool
+	usesPtr      bool
+	problems     []string
+	visited      map[string]bool
+}
+
+func (c *emailSecurityChecker) checkSPF(ctx context.Context, result *EmailSecurityResult, receivesMail bool) {
+	records, err := lookupEmailTXT(ctx, result.Domain)
+	if err != nil {
+		result.add("spf", "info", "SPF lookup failed", fmt.Sprintf("The TXT records of %s could not be looked up: %v", result.Domain, err))
+		return
+	}
+	spf := recordsWithPrefix(records, "v=spf1")
+	switch {
+	case len(spf) == 0 && receivesMail:
+		result.add("spf", "high", "No SPF record",
+			fmt.Sprintf("%s receives mail but publishes no SPF record, so receivers cannot tell which servers may send as it.", result.Domain))
+		return
+	case len(spf) == 0:
+		result.add("spf", "medium", "No SPF record",
+			fmt.Sprintf("%s does not send mail but has no SPF record. Publishing \"v=spf1 -all\" stops it being used in spoofed mail.", result.Domain))
+		return
+	case len(spf) > 1:
+		result.add("spf", "high", "Multiple SPF records",
+			fmt.Sprintf("%s publishes %d SPF records. Receivers treat this as a permanent error and SPF fails for all mail.", result.Domain, len(spf)))
+	}
+	result.SPFRecord = &spf[0]
+
+	eval := &spfEvaluation{visited: map[string]bool{result.Domain: true}}
+	c.walkSPF(ctx, eval, spf[0], 0)
+	result.SPFLookups = eval.lookups
+
+	if eval.lookups > spfMaxLookups {
+		result.add("spf", "high", fmt.Sprintf("SPF exceeds %d lookups", spfMaxLookups),
+			fmt.Sprintf("Evaluating the SPF record of %s takes %d DNS lookups. Receivers stop at %d with a permanent error, so SPF fails for all mail.", result.Domain, eval.lookups, spfMaxLookups))
+	}
+	if eval.voidLookups > spfMaxVoidLookups {
+		result.add("spf", "medium", "Too many SPF void lookups",
+			fmt.Sprintf("%d lookups in the SPF record of %s return no records. Receivers may fail SPF after %d.", eval.voidLookups, result.Domain, spfMaxVoidLookups))
+	}
+	for _, problem := range eval.problems {
+		result.add("spf", "medium", "Broken SPF include", problem)
+	}
+	if eval.usesPtr {
+		result.add("spf", "low", "SPF uses the ptr mechanism",
+			"The ptr mechanism is deprecated, slow and ignored by some receivers.")
+	}
+
+	switch eval.allQualifier {
+	case "+":
+		result.add("spf", "high", "SPF allows any sender (+all)",
+			fmt.Sprintf("The SPF record of %s ends in +all, which authorizes every server on the internet to send as it.", result.Domain))
+	case "?":
+		result.add("spf", "medium", "SPF is neutral (?all)",
+			fmt.Sprintf("The SPF record of %s ends in ?all, so mail from unlisted servers is neither passed nor failed.", result.Domain))
+	case "~":
+		result.add("spf", "low", "SPF soft fail (~all)",
+			fmt.Sprintf("The SPF record of %s ends in ~all. Mail from unlisted servers is only marked, and is rejected only if DMARC enforces.", result.Domain))
+	case "":
+		if !eval.hasRedirect {
+			result.add("spf", "medium", "SPF has no all mechanism",
+				fmt.Sprintf("The SPF record of %s has no all mechanism, so mail from unlisted servers gets a neutral result.", result.Domain))
+		}
+	}
+}
+
+// walkSPF counts the DNS lookups in an SPF record, following includes and redirects. Only the
+// top level record's all mechanism, or that of the record it redirects to, decides what happens
+// to unlisted senders.
+func (c *emailSecurityChecker) walkSPF(ctx context.Context, eval *spfEvaluation, record string, depth int) {
+	redirect := ""
+	hasAll := false
+	for _, term := range strings.Fields(record)[1:] {
+		qualifier := "+"
+		if strings.ContainsRune("+-~?", rune(term[0])) {
+			qualifier = term[:1]
+			term = term[1:]
+		}
+		name, value := term, ""
+		if idx := strings.IndexAny(term, ":=/"); idx >= 0 {
+			name, value = term[:idx], strings.TrimLeft(term[idx:], ":=")
+		}
+
+		switch strings.ToLower(name) {
+		case "include":
+			eval.lookups++
+			c.followSPF(ctx, eval, "include", value, depth+1)
+		case "redirect":
+			redirect = value
+		case "a", "mx", "exists":
+			eval.lookups++
+		case "ptr":
+			eval.lookups++
+			eval.usesPtr = true
+		case "all":
+			hasAll = true
+			if depth == 0 {
+				eval.allQualifier = qualifier
+			}
+		}
+	}
+
+	// A redirect is ignored when the record has an all mechanism
+	if redirect != "" && !hasAll {
+		eval.lookups++
+		if depth == 0 {
+			eval.hasRedirect = true
+		}
+		c.followSPF(ctx, eval, "redirect", redirect, depth)
+	}
+}
+
+// followSPF fetches the SPF record of an include or redirect target and walks it. A redirect
+// target is walked at the same depth since it replaces the record that points at it.
+func (c *emailSecurityChecker) followSPF(ctx context.Context, eval *spfEvaluation, mechanism, target string, depth int) {
+	target = normalizeDNSName(target)
+	if target == "" || strings.Contains(target, "%") {
+		// Macros depend on the sender and can't be expanded ahead of time
+		return
+	}
+	if depth > spfMaxDepth || eval.lookups > spfMaxLookups*3 {
+		return
+	}
+	if eval.visited[target] {
+		eval.problems = append(eval.problems, fmt.Sprintf("%s:%s loops back to a record that is already being evaluated.", mechanism, target))
+		return
+	}
+	eval.visited[target] = true
+	defer delete(eval.visited, target)
+
+	records, err := lookupEmailTXT(ctx, target)
+	if err != nil {
+		return
+	}
+	spf := recordsWithPrefix(records, "v=spf1")
+	if len(spf) == 0 {
+		eval.voidLookups++
+		eval.problems = append(eval.problems, fmt.Sprintf("%s:%s points at a name without an SPF record, which is a permanent error.", mechanism, target))
+		return
+	}
+	c.walkSPF(ctx, eval, spf[0], depth)
+}
+
+// parseTagList splits a DMARC, DKIM, MTA-STS or TLS-RPT record into its tags
+func parseTagList(record string) map[string]string {
+	tags := make(map[string]string)
+	for _, part := range strings.Split(record, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		tags[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return tags
+}
+
+func (c *emailSecurityChecker) checkDMARC(ctx context.Context, result *EmailSecurityResult) {
+	records, err := lookupEmailTXT(ctx, "_dmarc."+result.Domain)
+	if err != nil {
+		result.add("dmarc", "info", "DMARC lookup failed", fmt.Sprintf("The DMARC record of %s could not be looked up: %v", result.Domain, err))
+		return
+	}
+	dmarc := recordsWithPrefix(records, "v=DMARC1")
+	switch {
+	case len(dmarc) == 0:
+		result.add("dmarc", "high", "No DMARC record",
+			fmt.Sprintf("%s publishes no DMARC record, so receivers apply no policy to mail that fails SPF and DKIM and the owner gets no reports.", result.Domain))
+		return
+	case len(dmarc) > 1:
+		result.add("dmarc", "high", "Multiple DMARC records",
+			fmt.Sprintf("%s publishes %d DMARC records. Receivers ignore DMARC when there is more than one.", result.Domain, len(dmarc)))
+	}
+	result.DMARCRecord = &dmarc[0]
+
+	tags := parseTagList(dmarc[0])
+	policy := strings.ToLower(tags["p"])
+	switch policy {
+	case "reject", "quarantine":
+	case "none":
+		result.add("dmarc", "medium", "DMARC policy is p=none",
+			fmt.Sprintf("The DMARC policy of %s only monitors. Spoofed mail that fails DMARC is still delivered.", result.Domain))
+	default:
+		result.add("dmarc", "high", "DMARC record has no valid policy",
+			fmt.Sprintf("The DMARC record of %s has p=%q. Receivers ignore a record without a valid p tag.", result.Domain, tags["p"]))
+	}
+	if policy != "" {
+		result.DMARCPolicy = &policy
+	}
+
+	if sp := strings.ToLower(tags["sp"]); sp == "none" && policy != "none" {
+		result.add("dmarc", "medium", "DMARC subdomain policy is sp=none",
+			fmt.Sprintf("%s enforces DMARC but sp=none lets mail spoofing its subdomains through.", result.Domain))
+	}
+	if pct, ok := tags["pct"]; ok {
+		if n, err := strconv.Atoi(pct); err == nil && n < 100 {
+			result.add("dmarc", "low", "DMARC applies to part of the mail",
+				fmt.Sprintf("pct=%d means the DMARC policy of %s is applied to only %d%% of failing mail.", n, result.Domain, n))
+		}
+	}
+	if tags["rua"] == "" {
+		result.add("dmarc", "low", "No DMARC aggregate reports",
+			fmt.Sprintf("The DMARC record of %s has no rua tag, so nobody receives reports about who sends mail as the domain.", result.Domain))
+	}
+}
+
+// checkDKIM looks for keys under the common selectors and flags weak ones
+func (c *emailSecurityChecker) checkDKIM(ctx context.Context, result *EmailSecurityResult) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, selector := range commonDKIMSelectors {
+		wg.Add(1)
+		go func(selector string) {
+			defer wg.Done()
+			records, err := lookupEmailTXT(ctx, selector+"._domainkey."+result.Domain)
+			if err != nil {
+				return
+			}
+			for _, record := range records {
+				if key, ok := parseDKIMKey(selector, record); ok {
+					mu.Lock()
+					result.DKIMSelectors = append(result.DKIMSelectors, key)
+					mu.Unlock()
+					return
+				}
+			}
+		}(selector)
+	}
+	wg.Wait()
+	sort.Slice(result.DKIMSelectors, func(i, j int) bool {
+		return result.DKIMSelectors[i].Selector < result.DKIMSelectors[j].Selector
+	})
+
+	if len(result.DKIMSelectors) == 0 {
+		result.add("dkim", "info", "No DKIM key on common selectors",
+			fmt.Sprintf("None of %d common DKIM selectors has a key for %s. The domain may sign with a custom selector.", len(commonDKIMSelectors), result.Domain))
+		return
+	}
+	for _, key := range result.DKIMSelectors {
+		if key.Revoked || key.KeyType != "rsa" || key.KeyBits == 0 {
+			continue
+		}
+		switch {
+		case key.KeyBits < 1024:
+			result.add("dkim", "high", "Weak DKIM key",
+				fmt.Sprintf("The DKIM key under selector %s of %s is %d-bit RSA, which can be factored to forge signatures.", key.Selector, result.Domain, key.KeyBits))
+		case key.KeyBits < 2048:
+			result.add("dkim", "low", "Short DKIM key",
+				fmt.Sprintf("The DKIM key under selector %s of %s is %d-bit RSA. 2048 bits is recommended.", key.Selector, result.Domain, key.KeyBits))
+		}
+	}
+}
+
+// parseDKIMKey reads the key type and size from a DKIM key record. An empty p tag means the
+// key has been revoked.
+func parseDKIMKey(selector, record string) (DKIMSelector, bool) {
+	tags := parseTagList(record)
+	encoded, ok := tags["p"]
+	if !ok {
+		return DKIMSelector{}, false
+	}
+	key := DKIMSelector{Selector: selector, KeyType: strings.ToLower(tags["k"])}
+	if key.KeyType == "" {
+		key.KeyType = "rsa"
+	}
+	encoded = strings.Join(strings.Fields(encoded), "")
+	if encoded == "" {
+		key.Revoked = true
+		return key, true
+	}
+
+	der, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return key, true
+	}
+	switch key.KeyType {
+	case "rsa":
+		if pub, err := x509.ParsePKIXPublicKey(der); err == nil {
+			if rsaKey, ok := pub.(*rsa.PublicKey); ok {
+				key.KeyBits = rsaKey.N.BitLen()
+			}
+		} else if rsaKey, err := x509.ParsePKCS1PublicKey(der); err == nil {
+			key.KeyBits = rsaKey.N.BitLen()
+		}
+	case "ed25519":
+		if len(der) == ed25519.PublicKeySize {
+			key.KeyBits = 256
+		}
+	}
+	return key, true
+}
+
+// checkMTASTS checks the MTA-STS record and fetches the policy it announces
+func (c *emailSecurityChecker) checkMTASTS(ctx context.Context, result *EmailSecurityResult) {
+	records, err := lookupEmailTXT(ctx, "_mta-sts."+result.Domain)
+	if err != nil {
+		return
+	}
+	if len(recordsWithPrefix(records, "v=STSv1")) == 0 {
+		result.add("mta_sts", "info", "No MTA-STS policy",
+			fmt.Sprintf("%s does not publish MTA-STS, so sending servers may deliver its mail over unencrypted or downgraded connections.", result.Domain))
+		return
+	}
+
+	policy, err := c.fetchMTASTSPolicy(ctx, result.Domain)
+	if err != nil {
+		result.add("mta_sts", "medium", "MTA-STS policy cannot be fetched",
+			fmt.Sprintf("%s announces MTA-STS but its policy could not be fetched: %v. Senders treat the domain as having no policy.", result.Domain, err))
+		return
+	}
+
+	mode := strings.ToLower(policy["mode"])
+	result.MTASTSMode = &mode
+	switch mode {
+	case "enforce":
+	case "testing":
+		result.add("mta_sts", "low", "MTA-STS in testing mode",
+			fmt.Sprintf("The MTA-STS policy of %s is in testing mode, so failed TLS connections are only reported.", result.Domain))
+	default:
+		result.add("mta_sts", "low", "MTA-STS not enforced",
+			fmt.Sprintf("The MTA-STS policy of %s has mode %q and is not enforced.", result.Domain, policy["mode"]))
+	}
+
+	patterns := strings.Fields(policy["mx"])
+	var uncovered []string
+	for _, mx := range result.MXRecords {
+		if !mtaSTSMatches(patterns, mx) {
+			uncovered = append(uncovered, mx)
+		}
+	}
+	if len(uncovered) > 0 {
+		result.add("mta_sts", "medium", "MX hosts missing from MTA-STS policy",
+			fmt.Sprintf("The MTA-STS policy of %s does not list %s. Enforcing senders will refuse to deliver to them.", result.Domain, strings.Join(uncovered, ", ")))
+	}
+}
+
+// fetchMTASTSPolicy downloads the policy from the mta-sts host. Lines are "key: value" and mx
+// may repeat, so its values are joined with spaces.
+func (c *emailSecurityChecker) fetchMTASTSPolicy(ctx context.Context, domain string) (map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", "https://mta-sts."+domain+"/.well-known/mta-sts.txt", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+
+	policy := make(map[string]string)
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, emailSecurityPolicyLimit))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if key == "mx" && policy[key] != "" {
+			value = policy[key] + " " + value
+		}
+		policy[key] = value
+	}
+	if !strings.EqualFold(policy["version"], "STSv1") {
+		return nil, fmt.Errorf("policy has no \"version: STSv1\" line")
+	}
+	return policy, nil
+}
+
+// mtaSTSMatches reports whether an MX host matches one of the policy's mx patterns. A leading
+// "*." matches exactly one label.
+func mtaSTSMatches(patterns []string, host string) bool {
+	for _, pattern := range patterns {
+		pattern = normalizeDNSName(pattern)
+		if pattern == host {
+			return true
+		}
+		if strings.HasPrefix(pattern, "*.") {
+			if idx := strings.Index(host, "."); idx > 0 && host[idx+1:] == pattern[2:] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (c *emailSecurityChecker) checkTLSRPT(ctx context.Context, result *EmailSecurityResult) {
+	records, err := lookupEmailTXT(ctx, "_smtp._tls."+result.Domain)
+	if err != nil {
+		return
+	}
+	tlsrpt := recordsWithPrefix(records, "v=TLSRPTv1")
+	if len(tlsrpt) == 0 {
+		result.add("tls_rpt", "info", "No TLS-RPT record",
+			fmt.Sprintf("%s does not publish TLS-RPT, so it gets no reports when senders fail to connect to its mail servers securely.", result.Domain))
+		return
+	}
+	result.TLSRPTRecord = &tlsrpt[0]
+	if parseTagList(tlsrpt[0])["rua"] == "" {
+		result.add("tls_rpt", "low", "TLS-RPT record has no report address",
+			fmt.Sprintf("The TLS-RPT record of %s has no rua tag, so no reports are sent.", result.Domain))
+	}
+}
+
+// saveEmailSecurityResults replaces the scope target's results with the latest ones and fills
+// in the mail records of the matching FQDN assets
+func saveEmailSecurityResults(scanID, scopeTargetID string, results []*EmailSecurityResult) error {
+	tx, err := dbPool.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(), `DELETE FROM email_security_results WHERE scope_target_id = $1`, scopeTargetID); err != nil {
+		return fmt.Errorf("failed to delete old results: %v", err)
+	}
+
+	for _, r := range results {
+		dkimJSON, _ := json.Marshal(r.DKIMSelectors)
+		findingsJSON, _ := json.Marshal(r.Findings)
+		_, err := tx.Exec(context.Background(), `
+			INSERT INTO email_security_results
+				(scan_id, scope_target_id, domain, grade, score, mx_records, spf_record, spf_lookups,
+				 dmarc_record, dmarc_policy, dkim_selectors, mta_sts_mode, tls_rpt_record, findings, checked_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+			ON CONFLICT (scope_target_id, domain) DO NOTHING`,
+			scanID, scopeTargetID, r.Domain, r.Grade, r.Score, r.MXRecords, r.SPFRecord, r.SPFLookups,
+			r.DMARCRecord, r.DMARCPolicy, dkimJSON, r.MTASTSMode, r.TLSRPTRecord, findingsJSON, r.CheckedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert result for %s: %v", r.Domain, err)
+		}
+
+		var dkimRecord *string
+		for _, key := range r.DKIMSelectors {
+			if !key.Revoked {
+				record := fmt.Sprintf("%s._domainkey.%s (%s)", key.Selector, r.Domain, key.KeyType)
+				dkimRecord = &record
+				break
+			}
+		}
+		_, err = tx.Exec(context.Background(), `
+			UPDATE consolidated_attack_surface_assets
+			SET spf_record = $3, dmarc_record = $4, dkim_record = $5, mx_records = $6, mail_servers = $6
+			WHERE scope_target_id = $1 AND asset_type = 'fqdn' AND lower(asset_identifier) = $2`,
+			scopeTargetID, r.Domain, r.SPFRecord, r.DMARCRecord, dkimRecord, r.MXRecords)
+		if err != nil {
+			return fmt.Errorf("failed to update asset records for %s: %v", r.Domain, err)
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func updateEmailSecurityScan(scanID, status string, domainsChecked, findingsCount int, errorMessage, execTime string) {
+	_, err := dbPool.Exec(context.Background(), `
+		UPDATE email_security_scans
+		SET status = $1, domains_checked = $2, findings_count = $3,
+		    error = NULLIF($4, ''), execution_time = NULLIF($5, '')
+		WHERE scan_id = $6`,
+		status, domainsChecked, findingsCount, errorMessage, execTime, scanID)
+	if err != nil {
+		log.Printf("[EMAIL SECURITY] [ERROR] Failed to update scan status: %v", err)
+	}
+}
+
+const emailSecurityScanColumns = `id, scan_id, scope_target_id, status, domains_checked, findings_count, error, execution_time, created_at`
+
+func scanEmailSecurityScan(row interface{ Scan(...interface{}) error }) (EmailSecurityScan, error) {
+	var scan EmailSecurityScan
+	err := row.Scan(&scan.ID, &scan.ScanID, &scan.ScopeTargetID, &scan.Status, &scan.DomainsChecked,
+		&scan.FindingsCount, &scan.Error, &scan.ExecTime, &scan.CreatedAt)
+	return scan, err
+}
+
+func GetEmailSecurityScanStatus(w http.ResponseWriter, r *http.Request) {
+	scanID := mux.Vars(r)["scan_id"]
+
+	scan, err := scanEmailSecurityScan(dbPool.QueryRow(context.Background(),
+		`SELECT `+emailSecurityScanColumns+` FROM email_security_scans WHERE scan_id = $1`, scanID))
+	if err != nil {
+		http.Error(w, "Scan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scan)
+}
+
+func GetEmailSecurityScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT `+emailSecurityScanColumns+` FROM email_security_scans WHERE scope_target_id = $1 ORDER BY created_at DESC`,
+		scopeTargetID)
+	if err != nil {
+		log.Printf("[EMAIL SECURITY] [ERROR] Failed to get scans for target %s: %v", scopeTargetID, err)
+		http.Error(w, "Failed to get scans", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	scans := []EmailSecurityScan{}
+	for rows.Next() {
+		scan, err := scanEmailSecurityScan(rows)
+		if err != nil {
+			log.Printf("[EMAIL SECURITY] [ERROR] Failed to scan row: %v", err)
+			continue
+		}
+		scans = append(scans, scan)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scans)
+}
+
+func fetchEmailSecurityResults(scopeTargetID string) ([]EmailSecurityResult, error) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, scan_id, scope_target_id, domain, grade, score, mx_records, spf_record, spf_lookups,
+		       dmarc_record, dmarc_policy, dkim_selectors, mta_sts_mode, tls_rpt_record, findings, checked_at
+		FROM email_security_results
+		WHERE scope_target_id = $1::uuid
+		ORDER BY score ASC, domain ASC`, scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []EmailSecurityResult{}
+	for rows.Next() {
+		var r EmailSecurityResult
+		var dkimJSON, findingsJSON []byte
+		if err := rows.Scan(&r.ID, &r.ScanID, &r.ScopeTargetID, &r.Domain, &r.Grade, &r.Score, &r.MXRecords,
+			&r.SPFRecord, &r.SPFLookups, &r.DMARCRecord, &r.DMARCPolicy, &dkimJSON, &r.MTASTSMode,
+			&r.TLSRPTRecord, &findingsJSON, &r.CheckedAt); err != nil {
+			log.Printf("[EMAIL SECURITY] [ERROR] Failed to scan result: %v", err)
+			continue
+		}
+		if r.MXRecords == nil {
+			r.MXRecords = []string{}
+		}
+		r.DKIMSelectors = []DKIMSelector{}
+		json.Unmarshal(dkimJSON, &r.DKIMSelectors)
+		r.Findings = []EmailSecurityFinding{}
+		json.Unmarshal(findingsJSON, &r.Findings)
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// GetEmailSecuritySummary returns the graded email security results of every domain of a scope
+// target along with grade and severity totals
+func GetEmailSecuritySummary(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	results, err := fetchEmailSecurityResults(scopeTargetID)
+	if err != nil {
+		log.Printf("[EMAIL SECURITY] [ERROR] Failed to get results: %v", err)
+		http.Error(w, "Failed to get email security results", http.StatusInternalServerError)
+		return
+	}
+
+	summary := EmailSecuritySummary{
+		ScopeTargetID: scopeTargetID,
+		Domains:       len(results),
+		Grades:        map[string]int{"A": 0, "B": 0, "C": 0, "D": 0, "F": 0},
+		Severities:    map[string]int{"critical": 0, "high": 0, "medium": 0, "low": 0, "info": 0},
+		Results:       results,
+	}
+	totalScore := 0
+	for _, result := range results {
+		summary.Grades[result.Grade]++
+		totalScore += result.Score
+		for _, f := range result.Findings {
+			summary.Severities[f.Severity]++
+		}
+	}
+	if len(results) > 0 {
+		summary.AverageScore = totalScore / len(results)
+	}
+
+	scan, err := scanEmailSecurityScan(dbPool.QueryRow(context.Background(),
+		`SELECT `+emailSecurityScanColumns+` FROM email_security_scans WHERE scope_target_id = $1 ORDER BY created_at DESC LIMIT 1`,
+		scopeTargetID))
+	if err == nil {
+		summary.LatestScan = &scan
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
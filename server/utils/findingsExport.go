@@ -0,0 +1,910 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// FindingTriage mirrors the DefectDojo finding status flags for a single finding
+type FindingTriage struct {
+	Active       bool      `json:"active"`
+	Verified     bool      `json:"verified"`
+	FalseP       bool      `json:"false_p"`
+	Duplicate    bool      `json:"duplicate"`
+	OutOfScope   bool      `json:"out_of_scope"`
+	RiskAccepted bool      `json:"risk_accepted"`
+	IsMitigated  bool      `json:"is_mitigated"`
+	Notes        string    `json:"notes,omitempty"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ExportableFinding is a finding from any supported source together with its triage state
+type ExportableFinding struct {
+	ReportFinding
+	Fingerprint string         `json:"fingerprint"`
+	Triage      *FindingTriage `json:"triage,omitempty"`
+}
+
+type tlsFindingDefinition struct {
+	Column      string
+	TemplateID  string
+	Name        string
+	Severity    string
+	Description string
+	Mitigation  string
+}
+
+// TLS issues recorded on target_urls by the Nuclei SSL templates
+var tlsFindingDefinitions = []tlsFindingDefinition{
+	{"has_deprecated_tls", "deprecated-tls", "Deprecated TLS Protocol Supported", "low",
+		"The server accepts connections using TLS 1.0 or TLS 1.1, which are deprecated and affected by known weaknesses.",
+		"Disable TLS 1.0 and TLS 1.1 and only offer TLS 1.2 or newer."},
+	{"has_expired_ssl", "expired-ssl", "Expired TLS Certificate", "medium",
+		"The TLS certificate presented by the server has expired.",
+		"Renew the certificate and configure automated renewal."},
+	{"has_mismatched_ssl", "mismatched-ssl-certificate", "Mismatched TLS Certificate", "low",
+		"The TLS certificate presented by the server is not valid for the requested hostname.",
+		"Issue a certificate whose subject alternative names cover the hostname."},
+	{"has_revoked_ssl", "revoked-ssl-certificate", "Revoked TLS Certificate", "medium",
+		"The TLS certificate presented by the server has been revoked by its issuer.",
+		"Replace the revoked certificate."},
+	{"has_self_signed_ssl", "self-signed-ssl", "Self-Signed TLS Certificate", "low",
+		"The server presents a self-signed TLS certificate that clients cannot validate.",
+		"Use a certificate issued by a trusted certificate authority."},
+	{"has_untrusted_root_ssl", "untrusted-root-certificate", "Untrusted Root Certificate", "low",
+		"The TLS certificate chains to a root certificate authority that is not publicly trusted.",
+		"Use a certificate issued by a publicly trusted certificate authority."},
+}
+
+type exposedServiceDefinition struct {
+	Name     string
+	Severity string
+}
+
+// Services that should not normally be reachable from the internet, keyed by the service name
+// the port scan fingerprints
+var exposedServiceDefinitions = map[string]exposedServiceDefinition{
+	"docker":        {"Docker Engine API", "critical"},
+	"elasticsearch": {"Elasticsearch", "high"},
+	"memcached":     {"Memcached", "medium"},
+	"microsoft-ds":  {"SMB", "medium"},
+	"mongodb":       {"MongoDB", "high"},
+	"mssql":         {"Microsoft SQL Server", "medium"},
+	"mysql":         {"MySQL", "medium"},
+	"postgresql":    {"PostgreSQL", "medium"},
+	"rdp":           {"Remote Desktop", "medium"},
+	"redis":         {"Redis", "high"},
+	"telnet":        {"Telnet", "high"},
+	"vnc":           {"VNC", "medium"},
+}
+
+var sarifSecuritySeverity = map[string]string{
+	"critical": "9.5",
+	"high":     "8.0",
+	"medium":   "5.5",
+	"low":      "3.0",
+	"info":     "0.0",
+	"unknown":  "0.0",
+}
+
+// findingFingerprint returns a stable identifier used as unique_id_from_tool and SARIF fingerprint
+func findingFingerprint(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// collectExportableFindings gathers nuclei, TLS, exposed-service, subdomain takeover, DNS audit, email security and JavaScript secret findings for a scope target
+func collectExportableFindings(scopeTargetID string, sources []string) ([]ExportableFinding, error) {
+	enabled := map[string]bool{"nuclei": true, "tls": true, "services": true, "takeover": true, "dns": true, "email": true, "js": true}
+	if len(sources) > 0 {
+		enabled = toStringSet(sources)
+	}
+
+	var findings []ExportableFinding
+
+	if enabled["nuclei"] {
+		nucleiFindings, err := fetchNucleiFindingsForScopeTarget(scopeTargetID, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch nuclei findings: %v", err)
+		}
+		seen := make(map[string]bool)
+		for _, f := range nucleiFindings {
+			fingerprint := findingFingerprint("nuclei", f.TemplateID, f.MatchedAt)
+			// Newer scans are returned first, so older duplicates of the same match are skipped
+			if seen[fingerprint] {
+				continue
+			}
+			seen[fingerprint] = true
+			findings = append(findings, ExportableFinding{ReportFinding: f, Fingerprint: fingerprint})
+		}
+	}
+
+	if enabled["tls"] {
+		tlsFindings, err := fetchTLSFindings(scopeTargetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch TLS findings: %v", err)
+		}
+		findings = append(findings, tlsFindings...)
+	}
+
+	if enabled["services"] {
+		serviceFindings, err := fetchExposedServiceFindings(scopeTargetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch exposed service findings: %v", err)
+		}
+		findings = append(findings, serviceFindings...)
+	}
+
+	if enabled["takeover"] {
+		takeoverFindings, err := fetchTakeoverExportFindings(scopeTargetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch subdomain takeover findings: %v", err)
+		}
+		findings = append(findings, takeoverFindings...)
+	}
+
+	if enabled["dns"] {
+		dnsFindings, err := fetchDNSAuditExportFindings(scopeTargetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch DNS audit findings: %v", err)
+		}
+		findings = append(findings, dnsFindings...)
+	}
+
+	if enabled["email"] {
+		emailFindings, err := fetchEmailSecurityExportFindings(scopeTargetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch email security findings: %v", err)
+		}
+		findings = append(findings, emailFindings...)
+	}
+
+	if enabled["js"] {
+		jsFindings, err := fetchJSSecretExportFindings(scopeTargetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch JavaScript secret findings: %v", err)
+		}
+		findings = append(findings, jsFindings...)
+	}
+
+	triage, err := fetchFindingTriage(scopeTargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch triage state: %v", err)
+	}
+	for i := range findings {
+		if t, ok := triage[findings[i].Fingerprint]; ok {
+			findings[i].Triage = &t
+		}
+	}
+
+	return findings, nil
+}
+
+func fetchTLSFindings(scopeTargetID string) ([]ExportableFinding, error) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, url, has_deprecated_tls, has_expired_ssl, has_mismatched_ssl,
+		       has_revoked_ssl, has_self_signed_ssl, has_untrusted_root_ssl, updated_at
+		FROM target_urls
+		WHERE scope_target_id = $1::uuid
+		  AND (has_deprecated_tls OR has_expired_ssl OR has_mismatched_ssl
+		       OR has_revoked_ssl OR has_self_signed_ssl OR has_untrusted_root_ssl)
+		ORDER BY url ASC`, scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var findings []ExportableFinding
+	for rows.Next() {
+		var id, targetURL string
+		flags := make([]bool, len(tlsFindingDefinitions))
+		var updatedAt time.Time
+		if err := rows.Scan(&id, &targetURL, &flags[0], &flags[1], &flags[2], &flags[3], &flags[4], &flags[5], &updatedAt); err != nil {
+			log.Printf("[FINDINGS EXPORT] [ERROR] Failed to scan TLS row: %v", err)
+			continue
+		}
+
+		host := targetURL
+		if parsed, err := url.Parse(targetURL); err == nil && parsed.Host != "" {
+			host = parsed.Host
+		}
+
+		for i, def := range tlsFindingDefinitions {
+			if !flags[i] {
+				continue
+			}
+			findings = append(findings, ExportableFinding{
+				ReportFinding: ReportFinding{
+					ID:          fmt.Sprintf("tls:%s:%s", id, def.TemplateID),
+					Source:      "tls",
+					TemplateID:  def.TemplateID,
+					Name:        def.Name,
+					Severity:    def.Severity,
+					Description: def.Description,
+					Host:        host,
+					MatchedAt:   targetURL,
+					Tags:        []string{"ssl", "tls"},
+					Timestamp:   updatedAt.UTC().Format(time.RFC3339),
+				},
+				Fingerprint: findingFingerprint("tls", def.TemplateID, targetURL),
+			})
+		}
+	}
+	return findings, rows.Err()
+}
+
+func fetchExposedServiceFindings(scopeTargetID string) ([]ExportableFinding, error) {
+	services := make([]string, 0, len(exposedServiceDefinitions))
+	for service := range exposedServiceDefinitions {
+		services = append(services, service)
+	}
+
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT DISTINCT ON (ds.ip_address, ds.port, ds.transport)
+		       host(ds.ip_address), ds.port, ds.transport, ds.service,
+		       COALESCE(ds.product, ''), COALESCE(ds.version, ''),
+		       COALESCE(ds.detection_method, ''), ds.discovered_at
+		FROM discovered_services ds
+		JOIN ip_port_scans ips ON ds.scan_id = ips.scan_id
+		WHERE ips.scope_target_id = $1::uuid AND ds.service = ANY($2::text[])
+		ORDER BY ds.ip_address, ds.port, ds.transport, ds.discovered_at DESC`, scopeTargetID, services)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var findings []ExportableFinding
+	for rows.Next() {
+		var ip, transport, service, product, version, detectionMethod string
+		var port int
+		var discoveredAt time.Time
+		if err := rows.Scan(&ip, &port, &transport, &service, &product, &version, &detectionMethod, &discoveredAt); err != nil {
+			log.Printf("[FINDINGS EXPORT] [ERROR] Failed to scan exposed service row: %v", err)
+			continue
+		}
+
+		def := exposedServiceDefinitions[service]
+		description := fmt.Sprintf("%s is reachable on %s %s port %d.", def.Name, ip, transport, port)
+		if product != "" {
+			description += fmt.Sprintf(" The service identified itself as %s.", strings.TrimSpace(product+" "+version))
+		}
+		if detectionMethod == "port" {
+			description += " The service was named from the port number because it did not answer a protocol probe."
+		}
+		endpoint := net.JoinHostPort(ip, strconv.Itoa(port))
+
+		findings = append(findings, ExportableFinding{
+			ReportFinding: ReportFinding{
+				ID:          fmt.Sprintf("service:%s", endpoint),
+				Source:      "services",
+				TemplateID:  "exposed-" + strings.ToLower(strings.ReplaceAll(def.Name, " ", "-")),
+				Name:        fmt.Sprintf("Exposed %s Service", def.Name),
+				Severity:    def.Severity,
+				Description: description,
+				Host:        endpoint,
+				MatchedAt:   fmt.Sprintf("%s://%s", service, endpoint),
+				Tags:        []string{"exposure", "network", service},
+				Timestamp:   discoveredAt.UTC().Format(time.RFC3339),
+			},
+			Fingerprint: findingFingerprint("services", ip, strconv.Itoa(port)),
+		})
+	}
+	return findings, rows.Err()
+}
+
+func fetchTakeoverExportFindings(scopeTargetID string) ([]ExportableFinding, error) {
+	takeovers, err := fetchSubdomainTakeoverFindings(scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []ExportableFinding
+	for _, t := range takeovers {
+		name := fmt.Sprintf("Subdomain Takeover (%s)", t.Service)
+		description := fmt.Sprintf("%s is a CNAME for %s, which is served by %s but no longer claimed.", t.FQDN, t.CNAME, t.Service)
+		if t.Status == "potential" {
+			name = "Dangling CNAME Record"
+			description = fmt.Sprintf("%s is a CNAME for %s, which does not resolve. The target may be claimable.", t.FQDN, t.CNAME)
+		}
+		var extracted []string
+		if t.MatchedSignature != nil {
+			extracted = []string{*t.MatchedSignature}
+		}
+
+		findings = append(findings, ExportableFinding{
+			ReportFinding: ReportFinding{
+				ID:          fmt.Sprintf("takeover:%s", t.ID),
+				Source:      "takeover",
+				TemplateID:  takeoverTemplateID(t.Service),
+				Name:        name,
+				Severity:    t.Severity,
+				Description: description,
+				Host:        t.FQDN,
+				MatchedAt:   t.FQDN,
+				Tags:        []string{"takeover", "dns"},
+				References:  []string{takeoverReference},
+				Extracted:   extracted,
+				Timestamp:   t.LastChecked.UTC().Format(time.RFC3339),
+			},
+			Fingerprint: findingFingerprint("takeover", t.FQDN, t.CNAME),
+		})
+	}
+	return findings, nil
+}
+
+func fetchDNSAuditExportFindings(scopeTargetID string) ([]ExportableFinding, error) {
+	audits, err := fetchDNSAuditFindings(scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []ExportableFinding
+	for _, f := range audits {
+		host := f.Domain
+		if f.Nameserver != "" {
+			host = f.Nameserver
+		} else if f.NameserverIP != "" {
+			host = f.NameserverIP
+		}
+
+		findings = append(findings, ExportableFinding{
+			ReportFinding: ReportFinding{
+				ID:          fmt.Sprintf("dns:%s", f.ID),
+				Source:      "dns",
+				TemplateID:  "dns-" + strings.ReplaceAll(f.CheckType, "_", "-"),
+				Name:        f.Title,
+				Severity:    f.Severity,
+				Description: f.Details,
+				Host:        host,
+				MatchedAt:   f.Domain,
+				Tags:        []string{"dns", "misconfig"},
+				Timestamp:   f.CreatedAt.UTC().Format(time.RFC3339),
+			},
+			Fingerprint: findingFingerprint("dns", f.Domain, f.Nameserver, f.NameserverIP, f.CheckType),
+		})
+	}
+	return findings, nil
+}
+
+// fetchEmailSecurityExportFindings exports the graded email findings. Informational ones such
+// as a missing TLS-RPT record are left out.
+func fetchEmailSecurityExportFindings(scopeTargetID string) ([]ExportableFinding, error) {
+	results, err := fetchEmailSecurityResults(scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []ExportableFinding
+	for _, r := range results {
+		for _, f := range r.Findings {
+			if f.Severity == "info" {
+				continue
+			}
+			fingerprint := findingFingerprint("email", r.Domain, f.Check, f.Title)
+			findings = append(findings, ExportableFinding{
+				ReportFinding: ReportFinding{
+					ID:          fmt.Sprintf("email:%s", fingerprint),
+					Source:      "email",
+					TemplateID:  "email-" + strings.ReplaceAll(f.Check, "_", "-"),
+					Name:        f.Title,
+					Severity:    f.Severity,
+					Description: f.Details,
+					Host:        r.Domain,
+					MatchedAt:   r.Domain,
+					Tags:        []string{"email", "dns", "misconfig"},
+					Timestamp:   r.CheckedAt.UTC().Format(time.RFC3339),
+				},
+				Fingerprint: fingerprint,
+			})
+		}
+	}
+	return findings, nil
+}
+
+func fetchJSSecretExportFindings(scopeTargetID string) ([]ExportableFinding, error) {
+	secrets, err := fetchJSSecrets(scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []ExportableFinding
+	for _, s := range secrets {
+		host := s.JSURL
+		if u, err := url.Parse(s.JSURL); err == nil && u.Host != "" {
+			host = u.Host
+		}
+		location := s.JSURL
+		if s.SourcePath != "" {
+			location = fmt.Sprintf("%s (%s)", s.JSURL, s.SourcePath)
+		}
+
+		findings = append(findings, ExportableFinding{
+			ReportFinding: ReportFinding{
+				ID:          fmt.Sprintf("js:%s", s.ID),
+				Source:      "js",
+				TemplateID:  "js-secret-" + strings.ReplaceAll(s.Rule, "_", "-"),
+				Name:        "Hard-coded " + strings.ReplaceAll(s.Rule, "_", " ") + " in JavaScript",
+				Severity:    s.Severity,
+				Description: fmt.Sprintf("A %s was found on line %d of %s: %s", strings.ReplaceAll(s.Rule, "_", " "), s.Line, location, s.Context),
+				Host:        host,
+				MatchedAt:   s.JSURL,
+				Tags:        []string{"js", "secret", "exposure"},
+				Timestamp:   s.CreatedAt.UTC().Format(time.RFC3339),
+			},
+			Fingerprint: findingFingerprint("js", s.JSURL, s.SourcePath, s.Rule, s.Match),
+		})
+	}
+	return findings, nil
+}
+
+func fetchFindingTriage(scopeTargetID string) (map[string]FindingTriage, error) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT fingerprint, active, verified, false_p, duplicate, out_of_scope,
+		       risk_accepted, is_mitigated, COALESCE(notes, ''), updated_at
+		FROM finding_triage
+		WHERE scope_target_id = $1::uuid`, scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	triage := make(map[string]FindingTriage)
+	for rows.Next() {
+		var fingerprint string
+		var t FindingTriage
+		if err := rows.Scan(&fingerprint, &t.Active, &t.Verified, &t.FalseP, &t.Duplicate,
+			&t.OutOfScope, &t.RiskAccepted, &t.IsMitigated, &t.Notes, &t.UpdatedAt); err != nil {
+			log.Printf("[FINDINGS EXPORT] [ERROR] Failed to scan triage row: %v", err)
+			continue
+		}
+		triage[fingerprint] = t
+	}
+	return triage, rows.Err()
+}
+
+func parseFindingSources(r *http.Request) []string {
+	raw := r.URL.Query().Get("sources")
+	if raw == "" {
+		return nil
+	}
+	var sources []string
+	for _, s := range strings.Split(raw, ",") {
+		if s = strings.ToLower(strings.TrimSpace(s)); s != "" {
+			sources = append(sources, s)
+		}
+	}
+	return sources
+}
+
+// GetScopeTargetFindings returns normalized findings with their triage state
+func GetScopeTargetFindings(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["scope_target_id"]
+	if scopeTargetID == "" {
+		http.Error(w, "scope_target_id is required", http.StatusBadRequest)
+		return
+	}
+
+	findings, err := collectExportableFindings(scopeTargetID, parseFindingSources(r))
+	if err != nil {
+		log.Printf("[FINDINGS EXPORT] [ERROR] %v", err)
+		http.Error(w, "Failed to collect findings", http.StatusInternalServerError)
+		return
+	}
+	if findings == nil {
+		findings = []ExportableFinding{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(findings)
+}
+
+// HandleSARIFExport exports findings for a scope target as a SARIF 2.1.0 log with one run per source
+func HandleSARIFExport(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["scope_target_id"]
+	if scopeTargetID == "" {
+		http.Error(w, "scope_target_id is required", http.StatusBadRequest)
+		return
+	}
+
+	findings, err := collectExportableFindings(scopeTargetID, parseFindingSources(r))
+	if err != nil {
+		log.Printf("[FINDINGS EXPORT] [ERROR] %v", err)
+		http.Error(w, "Failed to collect findings", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[FINDINGS EXPORT] [INFO] Exporting %d findings as SARIF for scope target %s", len(findings), scopeTargetID)
+
+	w.Header().Set("Content-Type", "application/sarif+json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=ars0n-findings-%s.sarif", time.Now().Format("20060102-150405")))
+	json.NewEncoder(w).Encode(buildSARIFLog(findings))
+}
+
+func buildSARIFLog(findings []ExportableFinding) map[string]interface{} {
+	toolNames := map[string]string{
+		"nuclei":   "Nuclei",
+		"tls":      "ars0n TLS checks",
+		"services": "ars0n exposed services",
+		"takeover": "ars0n subdomain takeover",
+		"dns":      "ars0n DNS audit",
+		"email":    "ars0n email security",
+		"js":       "ars0n JavaScript analysis",
+	}
+	sourceOrder := []string{"nuclei", "tls", "services", "takeover", "dns", "email", "js"}
+
+	bySource := make(map[string][]ExportableFinding)
+	for _, f := range findings {
+		bySource[f.Source] = append(bySource[f.Source], f)
+	}
+
+	runs := []interface{}{}
+	for _, source := range sourceOrder {
+		sourceFindings := bySource[source]
+		if len(sourceFindings) == 0 {
+			continue
+		}
+
+		var rules []interface{}
+		ruleIndex := make(map[string]int)
+		var results []interface{}
+
+		for _, f := range sourceFindings {
+			idx, ok := ruleIndex[f.TemplateID]
+			if !ok {
+				idx = len(rules)
+				ruleIndex[f.TemplateID] = idx
+				rule := map[string]interface{}{
+					"id":                   f.TemplateID,
+					"name":                 f.Name,
+					"shortDescription":     map[string]string{"text": f.Name},
+					"defaultConfiguration": map[string]string{"level": sarifLevel(f.Severity)},
+					"properties": map[string]interface{}{
+						"tags":              append([]string{"security"}, f.Tags...),
+						"security-severity": sarifSecuritySeverity[f.Severity],
+					},
+				}
+				if f.Description != "" {
+					rule["fullDescription"] = map[string]string{"text": f.Description}
+				}
+				if len(f.References) > 0 {
+					rule["helpUri"] = f.References[0]
+				}
+				rules = append(rules, rule)
+			}
+
+			message := f.Name
+			if len(f.Extracted) > 0 {
+				message = fmt.Sprintf("%s: %s", f.Name, strings.Join(f.Extracted, ", "))
+			}
+			result := map[string]interface{}{
+				"ruleId":    f.TemplateID,
+				"ruleIndex": idx,
+				"level":     sarifLevel(f.Severity),
+				"message":   map[string]string{"text": message},
+				"locations": []interface{}{
+					map[string]interface{}{
+						"physicalLocation": map[string]interface{}{
+							"artifactLocation": map[string]string{"uri": f.MatchedAt},
+						},
+					},
+				},
+				"partialFingerprints": map[string]string{"ars0nFindingHash/v1": f.Fingerprint},
+				"properties": map[string]interface{}{
+					"severity": f.Severity,
+					"host":     f.Host,
+				},
+			}
+			if f.Triage != nil && (f.Triage.FalseP || f.Triage.RiskAccepted || f.Triage.OutOfScope || f.Triage.IsMitigated) {
+				result["suppressions"] = []interface{}{
+					map[string]string{
+						"kind":          "external",
+						"status":        "accepted",
+						"justification": triageStatusLabel(*f.Triage),
+					},
+				}
+			}
+			results = append(results, result)
+		}
+
+		runs = append(runs, map[string]interface{}{
+			"tool": map[string]interface{}{
+				"driver": map[string]interface{}{
+					"name":           toolNames[source],
+					"informationUri": "https://github.com/R-s0n/ars0n-framework-v2",
+					"rules":          rules,
+				},
+			},
+			"results": results,
+		})
+	}
+
+	return map[string]interface{}{
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"version": "2.1.0",
+		"runs":    runs,
+	}
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+func triageStatusLabel(t FindingTriage) string {
+	switch {
+	case t.FalseP:
+		return "false positive"
+	case t.OutOfScope:
+		return "out of scope"
+	case t.RiskAccepted:
+		return "risk accepted"
+	case t.IsMitigated:
+		return "mitigated"
+	case t.Duplicate:
+		return "duplicate"
+	case t.Verified:
+		return "verified"
+	case t.Active:
+		return "active"
+	default:
+		return "inactive"
+	}
+}
+
+// HandleDefectDojoExport exports findings in the DefectDojo generic findings import format
+func HandleDefectDojoExport(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["scope_target_id"]
+	if scopeTargetID == "" {
+		http.Error(w, "scope_target_id is required", http.StatusBadRequest)
+		return
+	}
+
+	findings, err := collectExportableFindings(scopeTargetID, parseFindingSources(r))
+	if err != nil {
+		log.Printf("[FINDINGS EXPORT] [ERROR] %v", err)
+		http.Error(w, "Failed to collect findings", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[FINDINGS EXPORT] [INFO] Exporting %d findings as DefectDojo JSON for scope target %s", len(findings), scopeTargetID)
+
+	ddFindings := make([]map[string]interface{}, 0, len(findings))
+	for _, f := range findings {
+		ddFindings = append(ddFindings, defectDojoFinding(f))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=ars0n-findings-defectdojo-%s.json", time.Now().Format("20060102-150405")))
+	json.NewEncoder(w).Encode(map[string]interface{}{"findings": ddFindings})
+}
+
+func defectDojoFinding(f ExportableFinding) map[string]interface{} {
+	description := f.Description
+	if description == "" {
+		description = f.Name
+	}
+	if len(f.Extracted) > 0 {
+		description += "\n\n**Extracted results:**\n- " + strings.Join(f.Extracted, "\n- ")
+	}
+	if f.CurlCommand != "" {
+		description += "\n\n**Reproduction:**\n```\n" + f.CurlCommand + "\n```"
+	}
+
+	date := time.Now().UTC().Format("2006-01-02")
+	if ts, err := time.Parse(time.RFC3339, f.Timestamp); err == nil {
+		date = ts.UTC().Format("2006-01-02")
+	}
+
+	triage := FindingTriage{Active: true}
+	if f.Triage != nil {
+		triage = *f.Triage
+	}
+
+	finding := map[string]interface{}{
+		"title":               fmt.Sprintf("%s - %s", f.Name, f.Host),
+		"description":         description,
+		"severity":            defectDojoSeverity(f.Severity),
+		"date":                date,
+		"references":          strings.Join(f.References, "\n"),
+		"unique_id_from_tool": f.Fingerprint,
+		"vuln_id_from_tool":   f.TemplateID,
+		"service":             f.Source,
+		"active":              triage.Active,
+		"verified":            triage.Verified,
+		"false_p":             triage.FalseP,
+		"duplicate":           triage.Duplicate,
+		"out_of_scope":        triage.OutOfScope,
+		"risk_accepted":       triage.RiskAccepted,
+		"is_mitigated":        triage.IsMitigated,
+		"static_finding":      false,
+		"dynamic_finding":     true,
+	}
+	if endpoint := defectDojoEndpoint(f.MatchedAt); endpoint != nil {
+		finding["endpoints"] = []interface{}{endpoint}
+	}
+	return finding
+}
+
+func defectDojoSeverity(severity string) string {
+	switch severity {
+	case "critical":
+		return "Critical"
+	case "high":
+		return "High"
+	case "medium":
+		return "Medium"
+	case "low":
+		return "Low"
+	default:
+		return "Info"
+	}
+}
+
+func defectDojoEndpoint(target string) map[string]interface{} {
+	parsed, err := url.Parse(target)
+	if err != nil || parsed.Hostname() == "" {
+		host, port, splitErr := net.SplitHostPort(target)
+		if splitErr != nil {
+			if target == "" {
+				return nil
+			}
+			return map[string]interface{}{"host": target}
+		}
+		endpoint := map[string]interface{}{"host": host}
+		if p, err := strconv.Atoi(port); err == nil {
+			endpoint["port"] = p
+		}
+		return endpoint
+	}
+
+	endpoint := map[string]interface{}{
+		"protocol": parsed.Scheme,
+		"host":     parsed.Hostname(),
+	}
+	if port := parsed.Port(); port != "" {
+		if p, err := strconv.Atoi(port); err == nil {
+			endpoint["port"] = p
+		}
+	}
+	if path := strings.TrimPrefix(parsed.EscapedPath(), "/"); path != "" {
+		endpoint["path"] = path
+	}
+	if parsed.RawQuery != "" {
+		endpoint["query"] = parsed.RawQuery
+	}
+	return endpoint
+}
+
+// HandleDefectDojoImport applies DefectDojo finding status updates back onto local findings.
+// It accepts either the generic {"findings": [...]} shape or a DefectDojo API page ({"results": [...]}),
+// matching entries on unique_id_from_tool. Entries that match no local finding are not stored and
+// are returned as unmatched.
+func HandleDefectDojoImport(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["scope_target_id"]
+	if scopeTargetID == "" {
+		http.Error(w, "scope_target_id is required", http.StatusBadRequest)
+		return
+	}
+
+	type ddStatusUpdate struct {
+		UniqueIDFromTool string  `json:"unique_id_from_tool"`
+		Active           *bool   `json:"active"`
+		Verified         *bool   `json:"verified"`
+		FalseP           *bool   `json:"false_p"`
+		Duplicate        *bool   `json:"duplicate"`
+		OutOfScope       *bool   `json:"out_of_scope"`
+		RiskAccepted     *bool   `json:"risk_accepted"`
+		IsMitigated      *bool   `json:"is_mitigated"`
+		Notes            *string `json:"notes"`
+	}
+	var payload struct {
+		Findings []ddStatusUpdate `json:"findings"`
+		Results  []ddStatusUpdate `json:"results"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	updates := append(payload.Findings, payload.Results...)
+	if len(updates) == 0 {
+		http.Error(w, "No findings in request body", http.StatusBadRequest)
+		return
+	}
+
+	current, err := fetchFindingTriage(scopeTargetID)
+	if err != nil {
+		log.Printf("[FINDINGS IMPORT] [ERROR] Failed to load triage state: %v", err)
+		http.Error(w, "Failed to load triage state", http.StatusInternalServerError)
+		return
+	}
+
+	findings, err := collectExportableFindings(scopeTargetID, nil)
+	if err != nil {
+		log.Printf("[FINDINGS IMPORT] [ERROR] %v", err)
+		http.Error(w, "Failed to collect findings", http.StatusInternalServerError)
+		return
+	}
+	known := make(map[string]bool, len(findings))
+	for _, f := range findings {
+		known[f.Fingerprint] = true
+	}
+
+	applied, skipped := 0, 0
+	unmatched := []string{}
+	for _, u := range updates {
+		if u.UniqueIDFromTool == "" {
+			skipped++
+			continue
+		}
+		if !known[u.UniqueIDFromTool] {
+			unmatched = append(unmatched, u.UniqueIDFromTool)
+			continue
+		}
+
+		t, ok := current[u.UniqueIDFromTool]
+		if !ok {
+			t = FindingTriage{Active: true}
+		}
+		for _, field := range []struct {
+			src *bool
+			dst *bool
+		}{
+			{u.Active, &t.Active}, {u.Verified, &t.Verified}, {u.FalseP, &t.FalseP},
+			{u.Duplicate, &t.Duplicate}, {u.OutOfScope, &t.OutOfScope},
+			{u.RiskAccepted, &t.RiskAccepted}, {u.IsMitigated, &t.IsMitigated},
+		} {
+			if field.src != nil {
+				*field.dst = *field.src
+			}
+		}
+		if u.Notes != nil {
+			t.Notes = *u.Notes
+		}
+
+		_, err := dbPool.Exec(context.Background(), `
+			INSERT INTO finding_triage (scope_target_id, fingerprint, active, verified, false_p, duplicate,
+				out_of_scope, risk_accepted, is_mitigated, notes, source, updated_at)
+			VALUES ($1::uuid, $2, $3, $4, $5, $6, $7, $8, $9, $10, 'defectdojo', NOW())
+			ON CONFLICT (scope_target_id, fingerprint) DO UPDATE SET
+				active = EXCLUDED.active, verified = EXCLUDED.verified, false_p = EXCLUDED.false_p,
+				duplicate = EXCLUDED.duplicate, out_of_scope = EXCLUDED.out_of_scope,
+				risk_accepted = EXCLUDED.risk_accepted, is_mitigated = EXCLUDED.is_mitigated,
+				notes = EXCLUDED.notes, source = EXCLUDED.source, updated_at = NOW()`,
+			scopeTargetID, u.UniqueIDFromTool, t.Active, t.Verified, t.FalseP, t.Duplicate,
+			t.OutOfScope, t.RiskAccepted, t.IsMitigated, t.Notes)
+		if err != nil {
+			log.Printf("[FINDINGS IMPORT] [ERROR] Failed to store triage for %s: %v", u.UniqueIDFromTool, err)
+			skipped++
+			continue
+		}
+		applied++
+	}
+
+	log.Printf("[FINDINGS IMPORT] [INFO] Applied %d DefectDojo status updates for scope target %s (%d skipped, %d unmatched)",
+		applied, scopeTargetID, skipped, len(unmatched))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"applied":   applied,
+		"skipped":   skipped,
+		"unmatched": unmatched,
+	})
+}
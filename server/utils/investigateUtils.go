@@ -132,7 +132,7 @@ func ExecuteInvestigateScan(scanID, scopeTargetID string) {
 		result := InvestigateResult{Domain: domain}
 
 		// Get IP address
-		if ips, err := net.LookupIP(domain); err == nil && len(ips) > 0 {
+		if ips, err := SharedDNSPool().LookupIP(context.Background(), "ip", domain); err == nil && len(ips) > 0 {
 			result.IPAddress = ips[0].String()
 		} else {
 			log.Printf("[WARN] Failed to resolve IP for %s: %v", domain, err)
@@ -208,7 +208,7 @@ func getSSLInfo(domain string) *SSLInfo {
 }
 
 func getASNInfo(domain string) *InvestigateASN {
-	ips, err := net.LookupIP(domain)
+	ips, err := SharedDNSPool().LookupIP(context.Background(), "ip", domain)
 	if err != nil || len(ips) == 0 {
 		log.Printf("[WARN] Failed to resolve IP for %s: %v", domain, err)
 		return nil
@@ -0,0 +1,300 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+)
+
+// How addresses are picked when a range holds more than max_ips_per_range addresses
+const (
+	IPSamplingFirst  = "first"
+	IPSamplingRandom = "random"
+	IPSamplingSpread = "spread"
+)
+
+// How the IP/Port scan decides an address is worth port scanning
+const (
+	HostDiscoveryTCP     = "tcp"      // A discovery port accepts or refuses the connection
+	HostDiscoveryTCPOpen = "tcp-open" // A discovery port accepts the connection, for networks that reset every address
+	HostDiscoveryNone    = "none"     // Port scan every sampled address
+)
+
+// Upper bound for max_ips_per_range, one goroutine is started per sampled address
+const maxIPsPerRangeLimit = 65536
+
+// IPPortScanConfig is the saved IP/Port scan profile of a scope target
+type IPPortScanConfig struct {
+	MaxIPsPerRange      int        `json:"max_ips_per_range"`
+	IPSampling          string     `json:"ip_sampling"`
+	MaxConcurrentIPs    int        `json:"max_concurrent_ips"`
+	MaxConcurrentPorts  int        `json:"max_concurrent_ports"`
+	HostProbeTimeoutMs  int        `json:"host_probe_timeout_ms"`
+	PortScanTimeoutMs   int        `json:"port_scan_timeout_ms"`
+	WebServiceTimeoutMs int        `json:"web_service_timeout_ms"`
+	BannerTimeoutMs     int        `json:"banner_timeout_ms"`
+	PortProfile         string     `json:"port_profile"`
+	CustomPorts         string     `json:"custom_ports"`
+	HostDiscovery       string     `json:"host_discovery"`
+	DiscoveryPorts      string     `json:"discovery_ports"`
+	UpdatedAt           *time.Time `json:"updated_at,omitempty"`
+}
+
+func defaultIPPortScanConfig() IPPortScanConfig {
+	defaults := getDefaultScanConfig()
+	return IPPortScanConfig{
+		MaxIPsPerRange:      defaults.MaxIPsPerRange,
+		IPSampling:          defaults.IPSampling,
+		MaxConcurrentIPs:    defaults.MaxConcurrentIPs,
+		MaxConcurrentPorts:  defaults.MaxConcurrentPorts,
+		HostProbeTimeoutMs:  int(defaults.HostProbeTimeout / time.Millisecond),
+		PortScanTimeoutMs:   int(defaults.PortScanTimeout / time.Millisecond),
+		WebServiceTimeoutMs: int(defaults.WebServiceTimeout / time.Millisecond),
+		BannerTimeoutMs:     int(defaults.BannerTimeout / time.Millisecond),
+		PortProfile:         PortProfileWeb,
+		HostDiscovery:       defaults.HostDiscovery,
+	}
+}
+
+// validate fills unset fields with the defaults and rejects values the scan cannot run with
+func (c *IPPortScanConfig) validate() error {
+	defaults := defaultIPPortScanConfig()
+	if c.MaxIPsPerRange == 0 {
+		c.MaxIPsPerRange = defaults.MaxIPsPerRange
+	}
+	if c.IPSampling == "" {
+		c.IPSampling = defaults.IPSampling
+	}
+	if c.MaxConcurrentIPs == 0 {
+		c.MaxConcurrentIPs = defaults.MaxConcurrentIPs
+	}
+	if c.MaxConcurrentPorts == 0 {
+		c.MaxConcurrentPorts = defaults.MaxConcurrentPorts
+	}
+	if c.HostProbeTimeoutMs == 0 {
+		c.HostProbeTimeoutMs = defaults.HostProbeTimeoutMs
+	}
+	if c.PortScanTimeoutMs == 0 {
+		c.PortScanTimeoutMs = defaults.PortScanTimeoutMs
+	}
+	if c.WebServiceTimeoutMs == 0 {
+		c.WebServiceTimeoutMs = defaults.WebServiceTimeoutMs
+	}
+	if c.BannerTimeoutMs == 0 {
+		c.BannerTimeoutMs = defaults.BannerTimeoutMs
+	}
+	if c.PortProfile == "" {
+		c.PortProfile = defaults.PortProfile
+	}
+	if c.PortProfile != PortProfileCustom {
+		c.CustomPorts = ""
+	}
+	if c.HostDiscovery == "" {
+		c.HostDiscovery = defaults.HostDiscovery
+	}
+
+	if c.MaxIPsPerRange < 1 || c.MaxIPsPerRange > maxIPsPerRangeLimit {
+		return fmt.Errorf("max_ips_per_range must be between 1 and %d", maxIPsPerRangeLimit)
+	}
+	switch c.IPSampling {
+	case IPSamplingFirst, IPSamplingRandom, IPSamplingSpread:
+	default:
+		return fmt.Errorf("unknown ip_sampling %q", c.IPSampling)
+	}
+	if c.MaxConcurrentIPs < 1 || c.MaxConcurrentIPs > 1000 {
+		return fmt.Errorf("max_concurrent_ips must be between 1 and 1000")
+	}
+	if c.MaxConcurrentPorts < 1 || c.MaxConcurrentPorts > 500 {
+		return fmt.Errorf("max_concurrent_ports must be between 1 and 500")
+	}
+	for name, ms := range map[string]int{
+		"host_probe_timeout_ms":  c.HostProbeTimeoutMs,
+		"port_scan_timeout_ms":   c.PortScanTimeoutMs,
+		"web_service_timeout_ms": c.WebServiceTimeoutMs,
+		"banner_timeout_ms":      c.BannerTimeoutMs,
+	} {
+		if ms < 100 || ms > 60000 {
+			return fmt.Errorf("%s must be between 100 and 60000", name)
+		}
+	}
+	if _, err := resolvePortProfile(c.PortProfile, c.CustomPorts); err != nil {
+		return err
+	}
+	switch c.HostDiscovery {
+	case HostDiscoveryTCP, HostDiscoveryTCPOpen, HostDiscoveryNone:
+	default:
+		return fmt.Errorf("unknown host_discovery %q", c.HostDiscovery)
+	}
+	if c.DiscoveryPorts != "" {
+		if _, err := parsePortSpec(c.DiscoveryPorts); err != nil {
+			return fmt.Errorf("discovery_ports: %v", err)
+		}
+	}
+	return nil
+}
+
+// scanConfig converts a validated profile into the settings the scan runs with
+func (c IPPortScanConfig) scanConfig() ScanConfig {
+	config := getDefaultScanConfig()
+	config.MaxIPsPerRange = c.MaxIPsPerRange
+	config.IPSampling = c.IPSampling
+	config.MaxConcurrentIPs = c.MaxConcurrentIPs
+	config.MaxConcurrentPorts = c.MaxConcurrentPorts
+	config.HostProbeTimeout = time.Duration(c.HostProbeTimeoutMs) * time.Millisecond
+	config.PortScanTimeout = time.Duration(c.PortScanTimeoutMs) * time.Millisecond
+	config.WebServiceTimeout = time.Duration(c.WebServiceTimeoutMs) * time.Millisecond
+	config.BannerTimeout = time.Duration(c.BannerTimeoutMs) * time.Millisecond
+	config.HostDiscovery = c.HostDiscovery
+	if c.DiscoveryPorts != "" {
+		config.DiscoveryPorts = mustParsePortSpec(c.DiscoveryPorts)
+	}
+	return config
+}
+
+// getIPPortScanConfig returns the saved profile of a scope target, or the defaults if none was saved
+func getIPPortScanConfig(scopeTargetID string) (IPPortScanConfig, error) {
+	config := defaultIPPortScanConfig()
+
+	query := `SELECT max_ips_per_range, ip_sampling, max_concurrent_ips, max_concurrent_ports,
+			  host_probe_timeout_ms, port_scan_timeout_ms, web_service_timeout_ms, banner_timeout_ms,
+			  port_profile, COALESCE(custom_ports, ''), host_discovery, COALESCE(discovery_ports, ''), updated_at
+			  FROM ip_port_scan_configs WHERE scope_target_id = $1`
+
+	var updatedAt time.Time
+	err := dbPool.QueryRow(context.Background(), query, scopeTargetID).Scan(
+		&config.MaxIPsPerRange, &config.IPSampling, &config.MaxConcurrentIPs, &config.MaxConcurrentPorts,
+		&config.HostProbeTimeoutMs, &config.PortScanTimeoutMs, &config.WebServiceTimeoutMs, &config.BannerTimeoutMs,
+		&config.PortProfile, &config.CustomPorts, &config.HostDiscovery, &config.DiscoveryPorts, &updatedAt)
+	if err == pgx.ErrNoRows {
+		return defaultIPPortScanConfig(), nil
+	}
+	if err != nil {
+		return config, err
+	}
+	config.UpdatedAt = &updatedAt
+	return config, nil
+}
+
+// GetIPPortScanConfig returns the saved IP/Port scan profile of a scope target
+func GetIPPortScanConfig(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["scope_target_id"]
+	if scopeTargetID == "" {
+		http.Error(w, "Scope target ID is required", http.StatusBadRequest)
+		return
+	}
+
+	createIPPortScanTables()
+
+	config, err := getIPPortScanConfig(scopeTargetID)
+	if err != nil {
+		log.Printf("[IP-PORT-SCAN] [ERROR] Failed to fetch scan profile: %v", err)
+		http.Error(w, "Failed to fetch configuration", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(config)
+}
+
+// SaveIPPortScanConfig validates and stores the IP/Port scan profile of a scope target
+func SaveIPPortScanConfig(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["scope_target_id"]
+	if scopeTargetID == "" {
+		http.Error(w, "Scope target ID is required", http.StatusBadRequest)
+		return
+	}
+
+	var config IPPortScanConfig
+	if err := json.NewDecoder(r.Body).Decode(&config); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := config.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	createIPPortScanTables()
+
+	query := `INSERT INTO ip_port_scan_configs (scope_target_id, max_ips_per_range, ip_sampling, max_concurrent_ips,
+			  max_concurrent_ports, host_probe_timeout_ms, port_scan_timeout_ms, web_service_timeout_ms, banner_timeout_ms,
+			  port_profile, custom_ports, host_discovery, discovery_ports, created_at, updated_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW())
+			  ON CONFLICT (scope_target_id) DO UPDATE SET
+			  max_ips_per_range = EXCLUDED.max_ips_per_range, ip_sampling = EXCLUDED.ip_sampling,
+			  max_concurrent_ips = EXCLUDED.max_concurrent_ips, max_concurrent_ports = EXCLUDED.max_concurrent_ports,
+			  host_probe_timeout_ms = EXCLUDED.host_probe_timeout_ms, port_scan_timeout_ms = EXCLUDED.port_scan_timeout_ms,
+			  web_service_timeout_ms = EXCLUDED.web_service_timeout_ms, banner_timeout_ms = EXCLUDED.banner_timeout_ms,
+			  port_profile = EXCLUDED.port_profile, custom_ports = EXCLUDED.custom_ports,
+			  host_discovery = EXCLUDED.host_discovery, discovery_ports = EXCLUDED.discovery_ports,
+			  updated_at = NOW()
+			  RETURNING updated_at`
+
+	var updatedAt time.Time
+	err := dbPool.QueryRow(context.Background(), query, scopeTargetID, config.MaxIPsPerRange, config.IPSampling,
+		config.MaxConcurrentIPs, config.MaxConcurrentPorts, config.HostProbeTimeoutMs, config.PortScanTimeoutMs,
+		config.WebServiceTimeoutMs, config.BannerTimeoutMs, config.PortProfile, config.CustomPorts,
+		config.HostDiscovery, config.DiscoveryPorts).Scan(&updatedAt)
+	if err != nil {
+		log.Printf("[IP-PORT-SCAN] [ERROR] Failed to save scan profile: %v", err)
+		http.Error(w, "Failed to save configuration", http.StatusInternalServerError)
+		return
+	}
+	config.UpdatedAt = &updatedAt
+
+	log.Printf("[IP-PORT-SCAN] [INFO] Saved scan profile for scope target %s: %d IPs per range (%s), %s discovery, %s ports",
+		scopeTargetID, config.MaxIPsPerRange, config.IPSampling, config.HostDiscovery, config.PortProfile)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(config)
+}
+
+// sampleIndices picks up to limit of the offsets 0..total-1 with the given strategy, in ascending order
+func sampleIndices(total, limit int, strategy string) []int {
+	if total <= 0 {
+		return nil
+	}
+	if limit <= 0 || total <= limit {
+		indices := make([]int, total)
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	}
+
+	indices := make([]int, 0, limit)
+	switch strategy {
+	case IPSamplingSpread:
+		// Evenly spaced through the range so every part of it gets some coverage
+		for i := 0; i < limit; i++ {
+			indices = append(indices, int(int64(i)*int64(total)/int64(limit)))
+		}
+	case IPSamplingRandom:
+		if total <= 4*limit {
+			indices = append(indices, rand.Perm(total)[:limit]...)
+		} else {
+			picked := make(map[int]bool, limit)
+			for len(indices) < limit {
+				idx := rand.Intn(total)
+				if !picked[idx] {
+					picked[idx] = true
+					indices = append(indices, idx)
+				}
+			}
+		}
+		sort.Ints(indices)
+	default:
+		for i := 0; i < limit; i++ {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
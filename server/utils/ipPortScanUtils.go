@@ -3,15 +3,19 @@ package utils
 import (
 	"context"
 	"crypto/tls"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,36 +23,44 @@ import (
 )
 
 type IPPortScan struct {
-	ID                  string    `json:"id"`
-	ScanID              string    `json:"scan_id"`
-	ScopeTargetID       string    `json:"scope_target_id"`
-	Status              string    `json:"status"`
-	TotalNetworkRanges  int       `json:"total_network_ranges"`
-	ProcessedRanges     int       `json:"processed_network_ranges"`
-	TotalIPsDiscovered  int       `json:"total_ips_discovered"`
-	TotalPortsScanned   int       `json:"total_ports_scanned"`
-	LiveWebServersFound int       `json:"live_web_servers_found"`
-	ErrorMessage        string    `json:"error_message,omitempty"`
-	ExecutionTime       string    `json:"execution_time,omitempty"`
-	CreatedAt           time.Time `json:"created_at"`
-	AutoScanSessionID   string    `json:"auto_scan_session_id,omitempty"`
+	ID                  string            `json:"id"`
+	ScanID              string            `json:"scan_id"`
+	ScopeTargetID       string            `json:"scope_target_id"`
+	Status              string            `json:"status"`
+	TotalNetworkRanges  int               `json:"total_network_ranges"`
+	ProcessedRanges     int               `json:"processed_network_ranges"`
+	TotalIPsDiscovered  int               `json:"total_ips_discovered"`
+	TotalPortsScanned   int               `json:"total_ports_scanned"`
+	LiveWebServersFound int               `json:"live_web_servers_found"`
+	ServicesFound       int               `json:"services_found"`
+	PortProfile         string            `json:"port_profile"`
+	CustomPorts         string            `json:"custom_ports,omitempty"`
+	TotalIPsProbed      int               `json:"total_ips_probed"`
+	TruncatedRanges     int               `json:"truncated_ranges"`
+	IPsSkipped          int64             `json:"ips_skipped"`
+	ScanConfig          *IPPortScanConfig `json:"scan_config,omitempty"`
+	ErrorMessage        string            `json:"error_message,omitempty"`
+	ExecutionTime       string            `json:"execution_time,omitempty"`
+	CreatedAt           time.Time         `json:"created_at"`
+	AutoScanSessionID   string            `json:"auto_scan_session_id,omitempty"`
 }
 
 type LiveWebServer struct {
-	ID            string    `json:"id"`
-	ScanID        string    `json:"scan_id"`
-	IPAddress     string    `json:"ip_address"`
-	Hostname      string    `json:"hostname,omitempty"`
-	Port          int       `json:"port"`
-	Protocol      string    `json:"protocol"`
-	URL           string    `json:"url"`
-	StatusCode    *int      `json:"status_code,omitempty"`
-	Title         string    `json:"title,omitempty"`
-	ServerHeader  string    `json:"server_header,omitempty"`
-	ContentLength *int64    `json:"content_length,omitempty"`
-	Technologies  []string  `json:"technologies,omitempty"`
-	ResponseTime  *float64  `json:"response_time_ms,omitempty"`
-	LastChecked   time.Time `json:"last_checked"`
+	ID                string               `json:"id"`
+	ScanID            string               `json:"scan_id"`
+	IPAddress         string               `json:"ip_address"`
+	Hostname          string               `json:"hostname,omitempty"`
+	Port              int                  `json:"port"`
+	Protocol          string               `json:"protocol"`
+	URL               string               `json:"url"`
+	StatusCode        *int                 `json:"status_code,omitempty"`
+	Title             string               `json:"title,omitempty"`
+	ServerHeader      string               `json:"server_header,omitempty"`
+	ContentLength     *int64               `json:"content_length,omitempty"`
+	Technologies      []string             `json:"technologies,omitempty"`
+	TechnologyDetails []DetectedTechnology `json:"technology_details,omitempty"`
+	ResponseTime      *float64             `json:"response_time_ms,omitempty"`
+	LastChecked       time.Time            `json:"last_checked"`
 }
 
 type DiscoveredIP struct {
@@ -68,12 +80,20 @@ type ScanConfig struct {
 	HostProbeTimeout   time.Duration `json:"host_probe_timeout"`
 	PortScanTimeout    time.Duration `json:"port_scan_timeout"`
 	WebServiceTimeout  time.Duration `json:"web_service_timeout"`
+	BannerTimeout      time.Duration `json:"banner_timeout"`
+	IPSampling         string        `json:"ip_sampling"`
+	HostDiscovery      string        `json:"host_discovery"`
+	DiscoveryPorts     []int         `json:"discovery_ports"`
 }
 
-// Common ports to probe for host discovery
-var hostDiscoveryPorts = []int{80, 443, 22, 21, 25, 53, 110, 995, 993, 143}
+// Common ports to probe for host discovery. A refused connection counts as well, so hosts
+// without any of these ports open are still found as long as they answer with a reset.
+var hostDiscoveryPorts = []int{
+	80, 443, 22, 21, 25, 53, 110, 995, 993, 143,
+	23, 135, 139, 445, 3389, 8080, 8443, 3306, 5432, 1433,
+}
 
-// Common web ports for detailed scanning
+// Common web ports, scanned by the default "web" port profile
 var webPorts = []int{
 	80, 443, 8080, 8443, 8000, 8001, 8008, 8888,
 	9000, 9001, 9080, 9443, 3000, 3001, 4000, 4001,
@@ -90,6 +110,10 @@ func getDefaultScanConfig() ScanConfig {
 		HostProbeTimeout:   1 * time.Second, // Per port connection timeout
 		PortScanTimeout:    1 * time.Second, // Per port connection timeout
 		WebServiceTimeout:  5 * time.Second, // Per HTTP request timeout
+		BannerTimeout:      3 * time.Second, // Per banner grab or service probe
+		IPSampling:         IPSamplingFirst,
+		HostDiscovery:      HostDiscoveryTCP,
+		DiscoveryPorts:     hostDiscoveryPorts,
 	}
 }
 
@@ -99,6 +123,8 @@ func RunIPPortScan(w http.ResponseWriter, r *http.Request) {
 	var payload struct {
 		ScopeTargetID     string  `json:"scope_target_id" binding:"required"`
 		AutoScanSessionID *string `json:"auto_scan_session_id,omitempty"`
+		PortProfile       string  `json:"port_profile,omitempty"`
+		CustomPorts       string  `json:"custom_ports,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ScopeTargetID == "" {
@@ -107,26 +133,47 @@ func RunIPPortScan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("[IP-PORT-SCAN] [INFO] Processing IP/Port scan for scope target: %s", payload.ScopeTargetID)
+	// Create tables if they don't exist
+	createIPPortScanTables()
+
+	// Start from the scope target's saved scan profile, a port profile in the request overrides its port list
+	profile, err := getIPPortScanConfig(payload.ScopeTargetID)
+	if err != nil {
+		log.Printf("[IP-PORT-SCAN] [ERROR] Failed to load scan profile: %v", err)
+		http.Error(w, "Failed to load scan profile", http.StatusInternalServerError)
+		return
+	}
+	if payload.PortProfile != "" {
+		profile.PortProfile = payload.PortProfile
+		profile.CustomPorts = payload.CustomPorts
+	}
+	if err := profile.validate(); err != nil {
+		log.Printf("[IP-PORT-SCAN] [ERROR] Invalid scan profile: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	ports, _ := resolvePortProfile(profile.PortProfile, profile.CustomPorts)
+	profile.UpdatedAt = nil
+	profileJSON, _ := json.Marshal(profile)
+
+	log.Printf("[IP-PORT-SCAN] [INFO] Processing IP/Port scan for scope target: %s (port profile %s, %d ports, %d IPs per range, %s sampling, %s discovery)",
+		payload.ScopeTargetID, profile.PortProfile, len(ports), profile.MaxIPsPerRange, profile.IPSampling, profile.HostDiscovery)
 
 	scanID := uuid.New().String()
 	log.Printf("[IP-PORT-SCAN] [INFO] Generated new scan ID: %s", scanID)
 
-	// Create tables if they don't exist
-	createIPPortScanTables()
-
 	// Insert scan record
 	var insertQuery string
 	var args []interface{}
 	if payload.AutoScanSessionID != nil && *payload.AutoScanSessionID != "" {
-		insertQuery = `INSERT INTO ip_port_scans (scan_id, scope_target_id, status, auto_scan_session_id) VALUES ($1, $2, $3, $4)`
-		args = []interface{}{scanID, payload.ScopeTargetID, "pending", *payload.AutoScanSessionID}
+		insertQuery = `INSERT INTO ip_port_scans (scan_id, scope_target_id, status, port_profile, custom_ports, scan_config, auto_scan_session_id) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+		args = []interface{}{scanID, payload.ScopeTargetID, "pending", profile.PortProfile, profile.CustomPorts, profileJSON, *payload.AutoScanSessionID}
 	} else {
-		insertQuery = `INSERT INTO ip_port_scans (scan_id, scope_target_id, status) VALUES ($1, $2, $3)`
-		args = []interface{}{scanID, payload.ScopeTargetID, "pending"}
+		insertQuery = `INSERT INTO ip_port_scans (scan_id, scope_target_id, status, port_profile, custom_ports, scan_config) VALUES ($1, $2, $3, $4, $5, $6)`
+		args = []interface{}{scanID, payload.ScopeTargetID, "pending", profile.PortProfile, profile.CustomPorts, profileJSON}
 	}
 
-	_, err := dbPool.Exec(context.Background(), insertQuery, args...)
+	_, err = dbPool.Exec(context.Background(), insertQuery, args...)
 	if err != nil {
 		log.Printf("[IP-PORT-SCAN] [ERROR] Failed to create scan record: %v", err)
 		http.Error(w, "Failed to create scan record", http.StatusInternalServerError)
@@ -134,14 +181,14 @@ func RunIPPortScan(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Start the scan in background
-	go ExecuteIPPortScan(scanID, payload.ScopeTargetID)
+	go ExecuteIPPortScan(scanID, payload.ScopeTargetID, ports, profile.scanConfig())
 
 	w.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(w).Encode(map[string]string{"scan_id": scanID})
 }
 
 // Execute the complete IP/Port scan process
-func ExecuteIPPortScan(scanID, scopeTargetID string) {
+func ExecuteIPPortScan(scanID, scopeTargetID string, ports []int, config ScanConfig) {
 	log.Printf("[IP-PORT-SCAN] [INFO] Starting IP/Port scan execution for scope target: %s", scopeTargetID)
 	startTime := time.Now()
 
@@ -160,30 +207,38 @@ func ExecuteIPPortScan(scanID, scopeTargetID string) {
 	log.Printf("[IP-PORT-SCAN] [INFO] Found %d consolidated network ranges", len(networkRanges))
 
 	// Update scan with total ranges
-	updateIPPortScanProgress(scanID, "discovering_ips", len(networkRanges), 0, 0, 0, 0)
+	updateIPPortScanProgress(scanID, "discovering_ips", len(networkRanges), 0, 0, 0, 0, 0)
 
 	// Phase 1: Discover live IPs
-	liveIPs, err := discoverLiveIPs(scanID, networkRanges)
+	liveIPs, err := discoverLiveIPs(scanID, scopeTargetID, networkRanges, config)
 	if err != nil {
 		updateIPPortScanStatus(scanID, "error", fmt.Sprintf("IP discovery failed: %v", err))
 		return
 	}
 
 	log.Printf("[IP-PORT-SCAN] [INFO] Discovered %d live IPs", len(liveIPs))
-	updateIPPortScanProgress(scanID, "port_scanning", len(networkRanges), len(networkRanges), len(liveIPs), 0, 0)
+	updateIPPortScanProgress(scanID, "port_scanning", len(networkRanges), len(networkRanges), len(liveIPs), 0, 0, 0)
 
-	// Phase 2: Port scan for web services
-	liveWebServers, err := discoverLiveWebServers(scanID, liveIPs)
+	// Phase 2: Port scan and fingerprint the open ports
+	liveWebServers, services, respondingIPs, err := scanLiveHosts(scanID, liveIPs, ports, config)
 	if err != nil {
 		updateIPPortScanStatus(scanID, "error", fmt.Sprintf("Port scanning failed: %v", err))
 		return
 	}
 
-	log.Printf("[IP-PORT-SCAN] [INFO] Found %d live web servers", len(liveWebServers))
+	// Without a discovery phase only the addresses that had an open port are recorded as live
+	totalPortsScanned := len(liveIPs) * len(ports)
+	if config.HostDiscovery == HostDiscoveryNone {
+		for _, ip := range respondingIPs {
+			insertDiscoveredIP(scanID, ip, networkRangeContaining(ip, networkRanges))
+		}
+		liveIPs = respondingIPs
+	}
+
+	log.Printf("[IP-PORT-SCAN] [INFO] Found %d live web servers and %d other services", len(liveWebServers), len(services))
 
 	// Update final status
-	totalPortsScanned := len(liveIPs) * len(webPorts)
-	updateIPPortScanProgress(scanID, "success", len(networkRanges), len(networkRanges), len(liveIPs), totalPortsScanned, len(liveWebServers))
+	updateIPPortScanProgress(scanID, "success", len(networkRanges), len(networkRanges), len(liveIPs), totalPortsScanned, len(liveWebServers), len(services))
 	updateIPPortScanExecutionTime(scanID, time.Since(startTime).String())
 
 	log.Printf("[IP-PORT-SCAN] [INFO] IP/Port scan completed in %s", time.Since(startTime).String())
@@ -220,14 +275,25 @@ func getConsolidatedNetworkRanges(scopeTargetID string) ([]ConsolidatedNetworkRa
 }
 
 // Discover live IPs using TCP connect probes
-func discoverLiveIPs(scanID string, networkRanges []ConsolidatedNetworkRange) ([]string, error) {
+func discoverLiveIPs(scanID, scopeTargetID string, networkRanges []ConsolidatedNetworkRange, config ScanConfig) ([]string, error) {
 	log.Printf("[IP-PORT-SCAN] [INFO] Starting IP discovery for %d network ranges", len(networkRanges))
 
-	config := getDefaultScanConfig()
 	var allLiveIPs []string
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 	totalIPsToScan := 0
+	truncatedRanges := 0
+	var ipsSkipped int64
+
+	// IPv6 ranges are far too large to sweep, so only addresses already seen for this target are probed
+	var knownIPv6Hosts []net.IP
+	for _, networkRange := range networkRanges {
+		if strings.Contains(networkRange.CIDRBlock, ":") {
+			knownIPv6Hosts = getKnownIPv6Hosts(scopeTargetID)
+			log.Printf("[IP-PORT-SCAN] [INFO] Found %d known IPv6 addresses to match against IPv6 ranges", len(knownIPv6Hosts))
+			break
+		}
+	}
 
 	// Semaphore to limit concurrent operations
 	semaphore := make(chan struct{}, config.MaxConcurrentIPs)
@@ -242,18 +308,35 @@ func discoverLiveIPs(scanID string, networkRanges []ConsolidatedNetworkRange) ([
 			continue
 		}
 
-		// Generate all IPs in the range
-		ips := generateIPsFromCIDR(ipNet)
-		log.Printf("[IP-PORT-SCAN] [DEBUG] Generated %d IPs from CIDR %s", len(ips), networkRange.CIDRBlock)
+		// Pick the addresses to probe, at most MaxIPsPerRange of them
+		var ips []string
+		var rangeSize int
+		if ipNet.IP.To4() != nil {
+			ips, rangeSize = sampleIPv4Range(ipNet, config.MaxIPsPerRange, config.IPSampling)
+		} else {
+			candidates := ipv6ProbeTargets(ipNet, knownIPv6Hosts)
+			rangeSize = len(candidates)
+			for _, idx := range sampleIndices(rangeSize, config.MaxIPsPerRange, config.IPSampling) {
+				ips = append(ips, candidates[idx])
+			}
+		}
+		log.Printf("[IP-PORT-SCAN] [DEBUG] Selected %d of %d IPs from CIDR %s", len(ips), rangeSize, networkRange.CIDRBlock)
 
-		// Limit the number of IPs to scan per range
-		if len(ips) > config.MaxIPsPerRange {
-			log.Printf("[IP-PORT-SCAN] [INFO] Limiting scan to first %d IPs in range %s", config.MaxIPsPerRange, networkRange.CIDRBlock)
-			ips = ips[:config.MaxIPsPerRange]
+		if len(ips) < rangeSize {
+			truncatedRanges++
+			ipsSkipped += int64(rangeSize - len(ips))
+			log.Printf("[IP-PORT-SCAN] [INFO] Range %s holds %d addresses, probing %d (%s sampling)", networkRange.CIDRBlock, rangeSize, len(ips), config.IPSampling)
 		}
 
 		// Probe each IP
 		totalIPsToScan += len(ips)
+
+		// Without host discovery every selected address goes straight to the port scan
+		if config.HostDiscovery == HostDiscoveryNone {
+			allLiveIPs = append(allLiveIPs, ips...)
+			continue
+		}
+
 		log.Printf("[IP-PORT-SCAN] [DEBUG] Starting to probe %d IPs in range %s", len(ips), networkRange.CIDRBlock)
 		for ipIdx, ip := range ips {
 			wg.Add(1)
@@ -266,7 +349,7 @@ func discoverLiveIPs(scanID string, networkRanges []ConsolidatedNetworkRange) ([
 					log.Printf("[IP-PORT-SCAN] [DEBUG] Probing IP %d/%d in range %s: %s", idx+1, len(ips), cidr, ipAddr)
 				}
 
-				if isHostAlive(ipAddr, config.HostProbeTimeout) {
+				if isHostAlive(ipAddr, config.DiscoveryPorts, config.HostProbeTimeout, config.HostDiscovery == HostDiscoveryTCP) {
 					mu.Lock()
 					allLiveIPs = append(allLiveIPs, ipAddr)
 					mu.Unlock()
@@ -279,7 +362,9 @@ func discoverLiveIPs(scanID string, networkRanges []ConsolidatedNetworkRange) ([
 		}
 	}
 
-	log.Printf("[IP-PORT-SCAN] [INFO] Total IPs to scan across all ranges: %d", totalIPsToScan)
+	log.Printf("[IP-PORT-SCAN] [INFO] Total IPs to scan across all ranges: %d (%d ranges truncated, %d IPs skipped)", totalIPsToScan, truncatedRanges, ipsSkipped)
+	updateIPPortScanTruncation(scanID, totalIPsToScan, truncatedRanges, ipsSkipped)
+
 	log.Printf("[IP-PORT-SCAN] [DEBUG] Waiting for all IP discovery goroutines to complete...")
 	wg.Wait()
 	log.Printf("[IP-PORT-SCAN] [DEBUG] All IP discovery goroutines completed. Found %d live IPs before deduplication", len(allLiveIPs))
@@ -291,84 +376,168 @@ func discoverLiveIPs(scanID string, networkRanges []ConsolidatedNetworkRange) ([
 	return uniqueIPs, nil
 }
 
-// Check if a host is alive by trying to connect to common ports
-func isHostAlive(ip string, timeout time.Duration) bool {
-	// Use the timeout directly per port - no division needed
-	// Each port gets the full timeout (1 second)
+// Check if a host is alive by connecting to the discovery ports in parallel. A port that accepts
+// the connection proves the host is up, and so does one that actively refuses it when countRefused is set.
+func isHostAlive(ip string, ports []int, timeout time.Duration, countRefused bool) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	results := make(chan bool, len(ports))
+	for _, port := range ports {
+		go func(p int) {
+			var dialer net.Dialer
+			conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip, strconv.Itoa(p)))
+			if err == nil {
+				conn.Close()
+				results <- true
+				return
+			}
+			results <- countRefused && errors.Is(err, syscall.ECONNREFUSED)
+		}(port)
+	}
 
-	for _, port := range hostDiscoveryPorts {
-		address := fmt.Sprintf("%s:%d", ip, port)
-		conn, err := net.DialTimeout("tcp", address, timeout)
-		if err == nil {
-			conn.Close()
+	for range ports {
+		if <-results {
 			return true // Host is alive
 		}
 	}
 	return false
 }
 
-// Generate all IP addresses from a CIDR block
-func generateIPsFromCIDR(ipNet *net.IPNet) []string {
-	var ips []string
-
-	// Get the network address
-	ip := ipNet.IP
-
-	// Convert to 4-byte representation
-	if ip.To4() == nil {
-		// IPv6 not supported for now
-		return ips
+// sampleIPv4Range picks up to limit host addresses of an IPv4 range and returns them along with
+// the number of host addresses in the range. The network and broadcast addresses are skipped
+// except in /31 and /32 ranges, where every address is a host.
+func sampleIPv4Range(ipNet *net.IPNet, limit int, strategy string) ([]string, int) {
+	ones, bits := ipNet.Mask.Size()
+	if bits != 32 {
+		return nil, 0 // Invalid mask
 	}
 
-	ip = ip.To4()
-	mask := ipNet.Mask
+	base := binary.BigEndian.Uint32(ipNet.IP.To4())
+	rangeSize := 1 << (bits - ones)
+	if rangeSize > 2 {
+		base++
+		rangeSize -= 2
+	}
 
-	// Calculate network size
-	ones, bits := mask.Size()
-	if bits != 32 {
-		return ips // Invalid mask
+	var ips []string
+	for _, offset := range sampleIndices(rangeSize, limit, strategy) {
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, base+uint32(offset))
+		ips = append(ips, ip.String())
 	}
+	return ips, rangeSize
+}
 
-	// For large networks, limit to avoid memory issues
-	networkSize := 1 << (bits - ones)
-	maxIPs := 65536 // Limit to ~65k IPs max
-	if networkSize > maxIPs {
-		networkSize = maxIPs
+// networkRangeContaining returns the first consolidated range that holds an address
+func networkRangeContaining(ip string, networkRanges []ConsolidatedNetworkRange) string {
+	addr := net.ParseIP(ip)
+	for _, networkRange := range networkRanges {
+		if _, ipNet, err := net.ParseCIDR(networkRange.CIDRBlock); err == nil && ipNet.Contains(addr) {
+			return networkRange.CIDRBlock
+		}
 	}
+	return ""
+}
 
-	// Generate IPs
-	for i := 1; i < networkSize-1; i++ { // Skip network and broadcast
-		newIP := make(net.IP, 4)
-		copy(newIP, ip)
+// Largest IPv6 prefix that is enumerated in full, a /120 holds 256 addresses
+const maxIPv6SweepBits = 8
 
-		// Add offset
-		carry := i
-		for j := 3; j >= 0 && carry > 0; j-- {
-			sum := int(newIP[j]) + carry
-			newIP[j] = byte(sum & 0xFF)
-			carry = sum >> 8
-		}
+// ipv6ProbeTargets returns the addresses to probe in an IPv6 range. Tiny ranges are enumerated,
+// anything larger (a /64 has 2^64 addresses) is limited to hosts already known from AAAA records,
+// httpx results and earlier consolidation.
+func ipv6ProbeTargets(ipNet *net.IPNet, knownHosts []net.IP) []string {
+	var ips []string
 
-		// Check if still in network
-		if ipNet.Contains(newIP) {
+	ones, bits := ipNet.Mask.Size()
+	if bits == 128 && bits-ones <= maxIPv6SweepBits {
+		base := ipNet.IP.To16()
+		for i := 0; i < 1<<(bits-ones); i++ {
+			// The all-zero address is the subnet-router anycast address, except in a /127
+			// point-to-point link or a single /128 address
+			if i == 0 && bits-ones >= 2 {
+				continue
+			}
+			newIP := make(net.IP, 16)
+			copy(newIP, base)
+			newIP[15] += byte(i)
 			ips = append(ips, newIP.String())
 		}
+		return ips
 	}
 
+	for _, host := range knownHosts {
+		if ipNet.Contains(host) {
+			ips = append(ips, host.String())
+		}
+	}
 	return ips
 }
 
-// Port scan live IPs for web services
-func discoverLiveWebServers(scanID string, liveIPs []string) ([]LiveWebServer, error) {
-	log.Printf("[IP-PORT-SCAN] [INFO] Starting port scanning for %d live IPs", len(liveIPs))
+// getKnownIPv6Hosts collects the IPv6 addresses seen for a scope target by earlier scans
+func getKnownIPv6Hosts(scopeTargetID string) []net.IP {
+	query := `
+		SELECT record FROM dnsx_company_dns_records
+		WHERE scope_target_id = $1::uuid AND record_type = 'AAAA'
+		UNION
+		SELECT record FROM amass_enum_company_dns_records
+		WHERE scope_target_id = $1::uuid AND record_type = 'AAAA'
+		UNION
+		SELECT dr.record FROM dns_records dr
+		JOIN amass_scans ams ON dr.scan_id = ams.scan_id
+		WHERE ams.scope_target_id = $1::uuid AND dr.record_type = 'AAAA'
+		UNION
+		SELECT ip_address FROM target_urls
+		WHERE scope_target_id = $1::uuid AND ip_address LIKE '%:%'
+		UNION
+		SELECT unnest(dns_aaaa_records) FROM target_urls
+		WHERE scope_target_id = $1::uuid AND dns_aaaa_records IS NOT NULL
+		UNION
+		SELECT ip_address FROM consolidated_attack_surface_assets
+		WHERE scope_target_id = $1::uuid AND asset_type = 'ip_address' AND ip_address LIKE '%:%'`
+
+	rows, err := dbPool.Query(context.Background(), query, scopeTargetID)
+	if err != nil {
+		log.Printf("[IP-PORT-SCAN] [ERROR] Failed to query known IPv6 hosts: %v", err)
+		return nil
+	}
+	defer rows.Close()
+
+	seen := make(map[string]bool)
+	var hosts []net.IP
+	for rows.Next() {
+		var record *string
+		if err := rows.Scan(&record); err != nil || record == nil {
+			continue
+		}
+		ip := net.ParseIP(strings.TrimSpace(*record))
+		if ip == nil || ip.To4() != nil || seen[ip.String()] {
+			continue
+		}
+		seen[ip.String()] = true
+		hosts = append(hosts, ip)
+	}
+	return hosts
+}
+
+// Port scan live IPs and fingerprint every open port as a web server or another service. The IPs
+// with at least one open port are returned as well.
+func scanLiveHosts(scanID string, liveIPs []string, ports []int, config ScanConfig) ([]LiveWebServer, []DiscoveredService, []string, error) {
+	log.Printf("[IP-PORT-SCAN] [INFO] Starting port scanning of %d ports for %d live IPs", len(ports), len(liveIPs))
 
-	config := getDefaultScanConfig()
 	var allWebServers []LiveWebServer
+	var allServices []DiscoveredService
+	var respondingIPs []string
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
-	// Semaphore to limit concurrent port scans
-	semaphore := make(chan struct{}, config.MaxConcurrentPorts)
+	// Scan fewer IPs at once when each IP gets more parallel connections
+	portConcurrency := portScanConcurrency(len(ports))
+	ipConcurrency := config.MaxConcurrentPorts * 10 / portConcurrency
+	if ipConcurrency < 1 {
+		ipConcurrency = 1
+	}
+	semaphore := make(chan struct{}, ipConcurrency)
 
 	for ipIdx, ip := range liveIPs {
 		wg.Add(1)
@@ -379,12 +548,15 @@ func discoverLiveWebServers(scanID string, liveIPs []string) ([]LiveWebServer, e
 
 			log.Printf("[IP-PORT-SCAN] [DEBUG] Port scanning IP %d/%d: %s", idx+1, len(liveIPs), ipAddr)
 
-			// Scan web ports
-			openPorts := scanTCPPorts(ipAddr, webPorts, config.PortScanTimeout)
+			openPorts := scanTCPPorts(ipAddr, ports, config.PortScanTimeout, portConcurrency)
+			if len(openPorts) > 0 {
+				mu.Lock()
+				respondingIPs = append(respondingIPs, ipAddr)
+				mu.Unlock()
+			}
 
-			// Check each open port for web services
 			for _, port := range openPorts {
-				webServer := checkForWebService(scanID, ipAddr, port, config.WebServiceTimeout)
+				webServer, service := probeOpenPort(scanID, ipAddr, port, config)
 				if webServer != nil {
 					mu.Lock()
 					allWebServers = append(allWebServers, *webServer)
@@ -393,6 +565,14 @@ func discoverLiveWebServers(scanID string, liveIPs []string) ([]LiveWebServer, e
 					// Store in database
 					insertLiveWebServer(scanID, *webServer)
 				}
+				if service != nil {
+					mu.Lock()
+					allServices = append(allServices, *service)
+					mu.Unlock()
+
+					insertDiscoveredService(scanID, *service)
+					log.Printf("[IP-PORT-SCAN] [DEBUG] %s:%d is %s %s %s (%s)", ipAddr, port, service.Service, service.Product, service.Version, service.DetectionMethod)
+				}
 			}
 
 		}(ipIdx, ip)
@@ -400,18 +580,34 @@ func discoverLiveWebServers(scanID string, liveIPs []string) ([]LiveWebServer, e
 
 	wg.Wait()
 
-	log.Printf("[IP-PORT-SCAN] [INFO] Total live web servers found: %d", len(allWebServers))
-	return allWebServers, nil
+	log.Printf("[IP-PORT-SCAN] [INFO] Total live web servers found: %d, other services found: %d", len(allWebServers), len(allServices))
+	return allWebServers, allServices, respondingIPs, nil
+}
+
+// Identify what runs on an open port. Services that talk first or answer a protocol probe are
+// fingerprinted directly; silent ports are checked for HTTP and otherwise named by port number.
+// Elasticsearch is both a service and a web server.
+func probeOpenPort(scanID, ipAddr string, port int, config ScanConfig) (*LiveWebServer, *DiscoveredService) {
+	service := fingerprintService(scanID, ipAddr, port, config.BannerTimeout)
+	if service != nil && service.Service != "elasticsearch" {
+		return nil, service
+	}
+
+	webServer := checkForWebService(scanID, ipAddr, port, config.WebServiceTimeout)
+	if webServer == nil && service == nil {
+		service = wellKnownService(scanID, ipAddr, port)
+	}
+	return webServer, service
 }
 
 // TCP port scanner using connect() method
-func scanTCPPorts(ip string, ports []int, timeout time.Duration) []int {
+func scanTCPPorts(ip string, ports []int, timeout time.Duration, concurrency int) []int {
 	var openPorts []int
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
 	// Semaphore for port scanning concurrency
-	semaphore := make(chan struct{}, 10) // Max 10 concurrent connections per IP
+	semaphore := make(chan struct{}, concurrency)
 
 	for _, port := range ports {
 		wg.Add(1)
@@ -420,7 +616,7 @@ func scanTCPPorts(ip string, ports []int, timeout time.Duration) []int {
 			semaphore <- struct{}{}        // Acquire
 			defer func() { <-semaphore }() // Release
 
-			address := fmt.Sprintf("%s:%d", ip, p)
+			address := net.JoinHostPort(ip, strconv.Itoa(p))
 			conn, err := net.DialTimeout("tcp", address, timeout)
 			if err == nil {
 				conn.Close()
@@ -440,7 +636,7 @@ func checkForWebService(scanID, ipAddr string, port int, timeout time.Duration)
 	protocols := []string{"http", "https"}
 
 	for _, protocol := range protocols {
-		url := fmt.Sprintf("%s://%s:%d", protocol, ipAddr, port)
+		url := fmt.Sprintf("%s://%s", protocol, net.JoinHostPort(ipAddr, strconv.Itoa(port)))
 
 		// Custom HTTP client with short timeout
 		client := &http.Client{
@@ -492,15 +688,17 @@ func checkForWebService(scanID, ipAddr string, port int, timeout time.Duration)
 			webServer.ContentLength = &resp.ContentLength
 		}
 
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, fingerprintBodyLimit))
+
 		// Try to extract page title
-		if title := extractPageTitle(resp); title != "" {
+		if title := extractPageTitle(resp.Header.Get("Content-Type"), body); title != "" {
 			webServer.Title = title
 		}
 
-		// Detect technologies from headers
-		technologies := detectTechnologies(resp)
-		if len(technologies) > 0 {
-			webServer.Technologies = technologies
+		// Fingerprint technologies from headers, cookies and page content
+		if technologies := DetectTechnologies(url, resp.Header, body); len(technologies) > 0 {
+			webServer.Technologies = technologyNames(technologies)
+			webServer.TechnologyDetails = technologies
 		}
 
 		return webServer
@@ -509,16 +707,13 @@ func checkForWebService(scanID, ipAddr string, port int, timeout time.Duration)
 	return nil
 }
 
-// Extract page title from HTTP response
-func extractPageTitle(resp *http.Response) string {
-	contentType := resp.Header.Get("Content-Type")
+// Extract page title from an HTML response body
+func extractPageTitle(contentType string, body []byte) string {
 	if contentType == "" || !strings.Contains(strings.ToLower(contentType), "text/html") {
 		return ""
 	}
-
-	body, err := io.ReadAll(io.LimitReader(resp.Body, 8192)) // Read first 8KB only
-	if err != nil {
-		return ""
+	if len(body) > 8192 {
+		body = body[:8192] // Look at the first 8KB only
 	}
 
 	titleRegex := regexp.MustCompile(`(?i)<title[^>]*>([^<]+)</title>`)
@@ -530,43 +725,6 @@ func extractPageTitle(resp *http.Response) string {
 	return ""
 }
 
-// Detect technologies from HTTP headers
-func detectTechnologies(resp *http.Response) []string {
-	var technologies []string
-
-	// Check Server header
-	if server := resp.Header.Get("Server"); server != "" {
-		technologies = append(technologies, server)
-	}
-
-	// Check X-Powered-By header
-	if poweredBy := resp.Header.Get("X-Powered-By"); poweredBy != "" {
-		technologies = append(technologies, poweredBy)
-	}
-
-	// Check other technology indicators
-	for header, values := range resp.Header {
-		switch strings.ToLower(header) {
-		case "x-aspnet-version":
-			if len(values) > 0 {
-				technologies = append(technologies, fmt.Sprintf("ASP.NET %s", values[0]))
-			}
-		case "x-generator":
-			if len(values) > 0 {
-				technologies = append(technologies, values[0])
-			}
-		case "x-drupal-cache":
-			technologies = append(technologies, "Drupal")
-		case "x-powered-cms":
-			if len(values) > 0 {
-				technologies = append(technologies, values[0])
-			}
-		}
-	}
-
-	return technologies
-}
-
 // Database helper functions
 func createIPPortScanTables() {
 	tables := []string{
@@ -584,7 +742,10 @@ func createIPPortScanTables() {
 			command TEXT,
 			execution_time TEXT,
 			created_at TIMESTAMP DEFAULT NOW(),
-			auto_scan_session_id UUID REFERENCES auto_scan_sessions(id) ON DELETE SET NULL
+			auto_scan_session_id UUID REFERENCES auto_scan_sessions(id) ON DELETE SET NULL,
+			port_profile TEXT DEFAULT 'web',
+			custom_ports TEXT,
+			services_found INT DEFAULT 0
 		);`,
 		`CREATE TABLE IF NOT EXISTS discovered_live_ips (
 			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
@@ -613,11 +774,53 @@ func createIPPortScanTables() {
 			last_checked TIMESTAMP DEFAULT NOW(),
 			UNIQUE(scan_id, ip_address, port, protocol)
 		);`,
+		`CREATE TABLE IF NOT EXISTS discovered_services (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scan_id UUID REFERENCES ip_port_scans(scan_id) ON DELETE CASCADE,
+			ip_address INET NOT NULL,
+			hostname TEXT,
+			port INT NOT NULL,
+			transport VARCHAR(10) NOT NULL DEFAULT 'tcp',
+			service VARCHAR(50) NOT NULL,
+			product TEXT,
+			version TEXT,
+			banner TEXT,
+			is_tls BOOLEAN DEFAULT false,
+			detection_method VARCHAR(20),
+			discovered_at TIMESTAMP DEFAULT NOW(),
+			UNIQUE(scan_id, ip_address, port, transport)
+		);`,
 		`CREATE INDEX IF NOT EXISTS idx_discovered_live_ips_scan_id ON discovered_live_ips(scan_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_live_web_servers_scan_id ON live_web_servers(scan_id);`,
 		`CREATE INDEX IF NOT EXISTS idx_live_web_servers_ip_port ON live_web_servers(ip_address, port);`,
+		`CREATE INDEX IF NOT EXISTS idx_discovered_services_scan_id ON discovered_services(scan_id);`,
 		`ALTER TABLE discovered_live_ips ADD COLUMN IF NOT EXISTS hostname TEXT;`,
 		`ALTER TABLE live_web_servers ADD COLUMN IF NOT EXISTS hostname TEXT;`,
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS port_profile TEXT DEFAULT 'web';`,
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS custom_ports TEXT;`,
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS services_found INT DEFAULT 0;`,
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS total_ips_probed INT DEFAULT 0;`,
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS truncated_ranges INT DEFAULT 0;`,
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS ips_skipped BIGINT DEFAULT 0;`,
+		`ALTER TABLE ip_port_scans ADD COLUMN IF NOT EXISTS scan_config JSONB;`,
+		`CREATE TABLE IF NOT EXISTS ip_port_scan_configs (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			scope_target_id UUID NOT NULL UNIQUE REFERENCES scope_targets(id) ON DELETE CASCADE,
+			max_ips_per_range INT NOT NULL DEFAULT 254,
+			ip_sampling VARCHAR(20) NOT NULL DEFAULT 'first',
+			max_concurrent_ips INT NOT NULL DEFAULT 50,
+			max_concurrent_ports INT NOT NULL DEFAULT 20,
+			host_probe_timeout_ms INT NOT NULL DEFAULT 1000,
+			port_scan_timeout_ms INT NOT NULL DEFAULT 1000,
+			web_service_timeout_ms INT NOT NULL DEFAULT 5000,
+			banner_timeout_ms INT NOT NULL DEFAULT 3000,
+			port_profile TEXT NOT NULL DEFAULT 'web',
+			custom_ports TEXT,
+			host_discovery VARCHAR(20) NOT NULL DEFAULT 'tcp',
+			discovery_ports TEXT,
+			created_at TIMESTAMP DEFAULT NOW(),
+			updated_at TIMESTAMP DEFAULT NOW()
+		);`,
 	}
 
 	for _, tableQuery := range tables {
@@ -647,19 +850,20 @@ func insertLiveWebServer(scanID string, webServer LiveWebServer) {
 		webServer.Hostname = resolveHostname(webServer.IPAddress)
 	}
 
-	query := `INSERT INTO live_web_servers (scan_id, ip_address, hostname, port, protocol, url, status_code, title, server_header, content_length, technologies, response_time_ms) 
-			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	query := `INSERT INTO live_web_servers (scan_id, ip_address, hostname, port, protocol, url, status_code, title, server_header, content_length, technologies, technology_details, response_time_ms) 
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 			  ON CONFLICT (scan_id, ip_address, port, protocol) DO UPDATE SET
 			  hostname = EXCLUDED.hostname, status_code = EXCLUDED.status_code, title = EXCLUDED.title, server_header = EXCLUDED.server_header,
-			  content_length = EXCLUDED.content_length, technologies = EXCLUDED.technologies, 
+			  content_length = EXCLUDED.content_length, technologies = EXCLUDED.technologies, technology_details = EXCLUDED.technology_details,
 			  response_time_ms = EXCLUDED.response_time_ms, last_checked = NOW()`
 
 	technologiesJSON, _ := json.Marshal(webServer.Technologies)
+	technologyDetailsJSON, _ := json.Marshal(webServer.TechnologyDetails)
 
 	_, err := dbPool.Exec(context.Background(), query,
 		scanID, webServer.IPAddress, webServer.Hostname, webServer.Port, webServer.Protocol, webServer.URL,
 		webServer.StatusCode, webServer.Title, webServer.ServerHeader, webServer.ContentLength,
-		technologiesJSON, webServer.ResponseTime)
+		technologiesJSON, technologyDetailsJSON, webServer.ResponseTime)
 	if err != nil {
 		log.Printf("[IP-PORT-SCAN] [ERROR] Failed to insert live web server: %v", err)
 	} else if webServer.Hostname != "" {
@@ -675,22 +879,31 @@ func updateIPPortScanStatus(scanID, status, errorMessage string) {
 	}
 }
 
-func updateIPPortScanProgress(scanID string, status string, totalRanges, processedRanges, totalIPs, totalPorts, liveServers int) {
+func updateIPPortScanProgress(scanID string, status string, totalRanges, processedRanges, totalIPs, totalPorts, liveServers, services int) {
 	query := `UPDATE ip_port_scans SET 
 			  status = $1, 
 			  total_network_ranges = $2, 
 			  processed_network_ranges = $3, 
 			  total_ips_discovered = $4, 
 			  total_ports_scanned = $5, 
-			  live_web_servers_found = $6 
-			  WHERE scan_id = $7`
+			  live_web_servers_found = $6,
+			  services_found = $7
+			  WHERE scan_id = $8`
 
-	_, err := dbPool.Exec(context.Background(), query, status, totalRanges, processedRanges, totalIPs, totalPorts, liveServers, scanID)
+	_, err := dbPool.Exec(context.Background(), query, status, totalRanges, processedRanges, totalIPs, totalPorts, liveServers, services, scanID)
 	if err != nil {
 		log.Printf("[IP-PORT-SCAN] [ERROR] Failed to update scan progress: %v", err)
 	}
 }
 
+func updateIPPortScanTruncation(scanID string, totalIPsProbed, truncatedRanges int, ipsSkipped int64) {
+	query := `UPDATE ip_port_scans SET total_ips_probed = $1, truncated_ranges = $2, ips_skipped = $3 WHERE scan_id = $4`
+	_, err := dbPool.Exec(context.Background(), query, totalIPsProbed, truncatedRanges, ipsSkipped, scanID)
+	if err != nil {
+		log.Printf("[IP-PORT-SCAN] [ERROR] Failed to update truncation counts: %v", err)
+	}
+}
+
 func updateIPPortScanExecutionTime(scanID, executionTime string) {
 	query := `UPDATE ip_port_scans SET execution_time = $1 WHERE scan_id = $2`
 	_, err := dbPool.Exec(context.Background(), query, executionTime, scanID)
@@ -715,16 +928,7 @@ func removeDuplicateIPs(ips []string) []string {
 
 // Resolve hostname for an IP address with timeout
 func resolveHostname(ipAddr string) string {
-	// Set a timeout for DNS resolution
-	resolver := &net.Resolver{
-		PreferGo: true,
-		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
-			d := net.Dialer{
-				Timeout: 2 * time.Second, // 2 second timeout for DNS resolution
-			}
-			return d.DialContext(ctx, network, address)
-		},
-	}
+	resolver := SharedDNSPool()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -753,7 +957,9 @@ func GetIPPortScanStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	query := `SELECT scan_id, scope_target_id, status, total_network_ranges, processed_network_ranges, 
-			  total_ips_discovered, total_ports_scanned, live_web_servers_found, error_message, 
+			  total_ips_discovered, total_ports_scanned, live_web_servers_found, COALESCE(services_found, 0),
+			  COALESCE(port_profile, 'web'), COALESCE(custom_ports, ''), COALESCE(total_ips_probed, 0),
+			  COALESCE(truncated_ranges, 0), COALESCE(ips_skipped, 0), scan_config, error_message, 
 			  execution_time, created_at, auto_scan_session_id FROM ip_port_scans WHERE scan_id = $1`
 
 	var scan IPPortScan
@@ -763,7 +969,9 @@ func GetIPPortScanStatus(w http.ResponseWriter, r *http.Request) {
 	err := dbPool.QueryRow(context.Background(), query, scanID).Scan(
 		&scan.ScanID, &scan.ScopeTargetID, &scan.Status, &scan.TotalNetworkRanges,
 		&scan.ProcessedRanges, &scan.TotalIPsDiscovered, &scan.TotalPortsScanned,
-		&scan.LiveWebServersFound, &errorMessage, &executionTime,
+		&scan.LiveWebServersFound, &scan.ServicesFound, &scan.PortProfile, &scan.CustomPorts,
+		&scan.TotalIPsProbed, &scan.TruncatedRanges, &scan.IPsSkipped, &scan.ScanConfig,
+		&errorMessage, &executionTime,
 		&scan.CreatedAt, &autoScanSessionID)
 
 	if err != nil {
@@ -856,7 +1064,9 @@ func GetIPPortScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
 	}
 
 	query := `SELECT scan_id, scope_target_id, status, total_network_ranges, processed_network_ranges,
-			  total_ips_discovered, total_ports_scanned, live_web_servers_found, error_message,
+			  total_ips_discovered, total_ports_scanned, live_web_servers_found, COALESCE(services_found, 0),
+			  COALESCE(port_profile, 'web'), COALESCE(custom_ports, ''), COALESCE(total_ips_probed, 0),
+			  COALESCE(truncated_ranges, 0), COALESCE(ips_skipped, 0), scan_config, error_message,
 			  execution_time, created_at, auto_scan_session_id FROM ip_port_scans 
 			  WHERE scope_target_id = $1 ORDER BY created_at DESC`
 
@@ -876,7 +1086,9 @@ func GetIPPortScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
 		var executionTime *string
 		err := rows.Scan(&scan.ScanID, &scan.ScopeTargetID, &scan.Status, &scan.TotalNetworkRanges,
 			&scan.ProcessedRanges, &scan.TotalIPsDiscovered, &scan.TotalPortsScanned,
-			&scan.LiveWebServersFound, &errorMessage, &executionTime,
+			&scan.LiveWebServersFound, &scan.ServicesFound, &scan.PortProfile, &scan.CustomPorts,
+			&scan.TotalIPsProbed, &scan.TruncatedRanges, &scan.IPsSkipped, &scan.ScanConfig,
+			&errorMessage, &executionTime,
 			&scan.CreatedAt, &autoScanSessionID)
 		if err != nil {
 			log.Printf("[IP-PORT-SCAN] [ERROR] Error scanning IP/Port scan row: %v", err)
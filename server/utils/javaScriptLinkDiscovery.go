@@ -186,6 +186,11 @@ func executeAndParseGoSpiderScan(scanID, domain string) {
 			cmd.Args = append(cmd.Args, "--header", customHeader)
 		}
 
+		// Route traffic through Burp Suite if enabled
+		if burpProxy := GetBurpProxyURL(); burpProxy != "" {
+			cmd.Args = append(cmd.Args, "-p", burpProxy)
+		}
+
 		commands = append(commands, cmd.String())
 		log.Printf("[DEBUG] Executing command: %s", cmd.String())
 
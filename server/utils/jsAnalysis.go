@@ -0,0 +1,860 @@
+package utils
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	jsAnalysisWorkers  = 10
+	jsAnalysisMaxFiles = 1000
+	jsPageBodyLimit    = 2 << 20
+	jsFileBodyLimit    = 10 << 20
+	jsSourceMapLimit   = 25 << 20
+	// Reconstructed sources kept per source map; node_modules heavy bundles can list thousands
+	jsMaxSourceFiles    = 2000
+	jsMaxSourceFileSize = 2 << 20
+	jsMaxEndpoints      = 500
+	jsSecretContext     = 40
+)
+
+// jsSecretRule detects one kind of hard-coded credential. Group selects the capture group
+// holding the secret, 0 meaning the whole match.
+type jsSecretRule struct {
+	Name     string
+	Severity string
+	Pattern  *regexp.Regexp
+	Group    int
+}
+
+var jsSecretRules = []jsSecretRule{
+	{"aws_access_key_id", "high", regexp.MustCompile(`\b((?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[0-9A-Z]{16})\b`), 1},
+	{"aws_secret_access_key", "critical", regexp.MustCompile(`(?i)aws.{0,20}?(?:secret|private).{0,20}?['"]([0-9a-zA-Z/+]{40})['"]`), 1},
+	{"google_api_key", "medium", regexp.MustCompile(`\bAIza[0-9A-Za-z\-_]{35}\b`), 0},
+	{"google_oauth_client_id", "low", regexp.MustCompile(`\b[0-9]+-[0-9A-Za-z_]{32}\.apps\.googleusercontent\.com\b`), 0},
+	{"slack_token", "high", regexp.MustCompile(`\bxox[baprs]-[0-9A-Za-z-]{10,72}\b`), 0},
+	{"slack_webhook", "high", regexp.MustCompile(`https://hooks\.slack\.com/services/T[0-9A-Za-z_]+/B[0-9A-Za-z_]+/[0-9A-Za-z_]+`), 0},
+	{"github_token", "high", regexp.MustCompile(`\bgh[pousr]_[0-9A-Za-z]{36,255}\b`), 0},
+	{"stripe_secret_key", "critical", regexp.MustCompile(`\b[rs]k_live_[0-9a-zA-Z]{24,99}\b`), 0},
+	{"private_key", "critical", regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP |ENCRYPTED )?PRIVATE KEY`), 0},
+	{"jwt", "medium", regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}`), 0},
+}
+
+var (
+	scriptSrcPattern    = regexp.MustCompile(`(?i)<script[^>]+src\s*=\s*["']?([^"'\s>]+)`)
+	sourceMappingRegexp = regexp.MustCompile(`(?m)^\s*//[#@]\s*sourceMappingURL\s*=\s*(\S+)\s*$`)
+	jsFullURLPattern    = regexp.MustCompile(`https?://[A-Za-z0-9.\-]+(?::[0-9]+)?(?:/[^\s"'<>\\` + "`" + `)]*)?`)
+	jsPathPattern       = regexp.MustCompile(`["'` + "`" + `]((?:/|\.\./|\./)[A-Za-z0-9_\-./{}:%?=&~+@$]{1,300}|[A-Za-z0-9_\-]{1,50}/[A-Za-z0-9_\-./{}:%?=&~+@$]{1,300}\.(?:php|asp|aspx|jsp|json|action|do|html|txt|xml|cgi))["'` + "`" + `]`)
+)
+
+// JSAnalysisScan downloads the JavaScript referenced by a scope target's live web servers
+type JSAnalysisScan struct {
+	ID              string    `json:"id"`
+	ScanID          string    `json:"scan_id"`
+	ScopeTargetID   string    `json:"scope_target_id"`
+	Status          string    `json:"status"`
+	FilesAnalyzed   int       `json:"files_analyzed"`
+	EndpointsCount  int       `json:"endpoints_count"`
+	SecretsCount    int       `json:"secrets_count"`
+	SourceMapsCount int       `json:"source_maps_count"`
+	ChangedCount    int       `json:"changed_count"`
+	Error           *string   `json:"error"`
+	ExecTime        *string   `json:"execution_time"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// JSFile is a JavaScript file as last downloaded. PreviousSHA256 and LastChanged are set once
+// the file's content has changed between scans.
+type JSFile struct {
+	ID             string     `json:"id"`
+	ScopeTargetID  string     `json:"scope_target_id"`
+	URL            string     `json:"url"`
+	PageURL        string     `json:"page_url"`
+	SHA256         string     `json:"sha256"`
+	PreviousSHA256 *string    `json:"previous_sha256"`
+	Size           int        `json:"size"`
+	Endpoints      []string   `json:"endpoints"`
+	SourceMapURL   *string    `json:"source_map_url"`
+	SourceFiles    int        `json:"source_files"`
+	SecretsCount   int        `json:"secrets_count"`
+	ChangeCount    int        `json:"change_count"`
+	ScanID         string     `json:"scan_id"`
+	FirstSeen      time.Time  `json:"first_seen"`
+	LastSeen       time.Time  `json:"last_seen"`
+	LastChanged    *time.Time `json:"last_changed"`
+}
+
+// JSSecret is a credential matched in a JavaScript file or in a source reconstructed from its
+// source map, in which case SourcePath names that source
+type JSSecret struct {
+	ID            string    `json:"id"`
+	ScanID        string    `json:"scan_id"`
+	ScopeTargetID string    `json:"scope_target_id"`
+	JSURL         string    `json:"js_url"`
+	SourcePath    string    `json:"source_path"`
+	Rule          string    `json:"rule"`
+	Severity      string    `json:"severity"`
+	Match         string    `json:"match"`
+	Line          int       `json:"line"`
+	Context       string    `json:"context"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// JSSourceFile is one original source recovered from a source map
+type JSSourceFile struct {
+	ID            string    `json:"id"`
+	ScopeTargetID string    `json:"scope_target_id"`
+	JSURL         string    `json:"js_url"`
+	Path          string    `json:"path"`
+	Size          int       `json:"size"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// jsAnalysisResult is what analysing one JavaScript file produced
+type jsAnalysisResult struct {
+	file        JSFile
+	sourceFiles map[string]string
+	secrets     []JSSecret
+}
+
+// newScanHTTPClient returns a client for requests the API makes to targets directly. Redirects
+// are followed up to three times and certificate errors are ignored.
+func newScanHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+			MaxIdleConnsPerHost: 4,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 3 {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+}
+
+// fetchWithCustomHeaders GETs a URL with the custom user agent and header from settings,
+// reading at most limit bytes of the body
+func fetchWithCustomHeaders(client *http.Client, rawURL string, limit int64) (*http.Response, []byte, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	customUserAgent, customHeader := GetCustomHTTPSettings()
+	if customUserAgent != "" {
+		req.Header.Set("User-Agent", customUserAgent)
+	}
+	if name, value, ok := strings.Cut(customHeader, ":"); ok {
+		req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	return resp, body, err
+}
+
+// collectJSURLs finds the scripts loaded by each live target URL plus the .js files Katana
+// crawled, keeping only those on in-scope hosts. The value is the page that referenced it.
+func collectJSURLs(scopeTargetID string, client *http.Client) (map[string]string, error) {
+	inScope, err := parameterScope(scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT url, katana_results FROM target_urls
+		WHERE scope_target_id = $1 AND no_longer_live = false`, scopeTargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target URLs: %v", err)
+	}
+	var pages []string
+	jsURLs := make(map[string]string)
+	var mu sync.Mutex
+	addJS := func(rawURL, pageURL string) {
+		u, err := url.Parse(rawURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || !inScope(strings.ToLower(u.Hostname())) {
+			return
+		}
+		u.Fragment = ""
+		mu.Lock()
+		defer mu.Unlock()
+		if _, exists := jsURLs[u.String()]; !exists && len(jsURLs) < jsAnalysisMaxFiles {
+			jsURLs[u.String()] = pageURL
+		}
+	}
+	for rows.Next() {
+		var pageURL string
+		var katanaJSON []byte
+		if err := rows.Scan(&pageURL, &katanaJSON); err != nil {
+			continue
+		}
+		pages = append(pages, pageURL)
+		var crawled []string
+		if json.Unmarshal(katanaJSON, &crawled) == nil {
+			for _, crawledURL := range crawled {
+				if u, err := url.Parse(crawledURL); err == nil && strings.HasSuffix(strings.ToLower(u.Path), ".js") {
+					addJS(crawledURL, pageURL)
+				}
+			}
+		}
+	}
+	rows.Close()
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, jsAnalysisWorkers)
+	for _, pageURL := range pages {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(pageURL string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			resp, body, err := fetchWithCustomHeaders(client, pageURL, jsPageBodyLimit)
+			if err != nil {
+				log.Printf("[JS ANALYSIS] [WARN] Failed to fetch %s: %v", pageURL, err)
+				return
+			}
+			base := resp.Request.URL
+			for _, match := range scriptSrcPattern.FindAllStringSubmatch(string(body), -1) {
+				if ref, err := url.Parse(strings.TrimSpace(match[1])); err == nil {
+					addJS(base.ResolveReference(ref).String(), pageURL)
+				}
+			}
+		}(pageURL)
+	}
+	wg.Wait()
+
+	return jsURLs, nil
+}
+
+// extractJSEndpoints returns the full URLs and API-looking paths referenced in JavaScript
+func extractJSEndpoints(content string) []string {
+	seen := make(map[string]bool)
+	var endpoints []string
+	add := func(endpoint string) {
+		if len(endpoints) >= jsMaxEndpoints || seen[endpoint] {
+			return
+		}
+		seen[endpoint] = true
+		endpoints = append(endpoints, endpoint)
+	}
+
+	for _, match := range jsFullURLPattern.FindAllString(content, -1) {
+		add(strings.TrimRight(match, ".,;"))
+	}
+	for _, match := range jsPathPattern.FindAllStringSubmatch(content, -1) {
+		endpoint := match[1]
+		// Skip protocol relative URLs, comment markers and bare directory dots
+		if strings.HasPrefix(endpoint, "//") || strings.Trim(endpoint, "./") == "" {
+			continue
+		}
+		add(endpoint)
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
+
+// findJSSecrets applies the secret rules to a file's content
+func findJSSecrets(content, jsURL, sourcePath string) []JSSecret {
+	var secrets []JSSecret
+	seen := make(map[string]bool)
+	for _, rule := range jsSecretRules {
+		for _, loc := range rule.Pattern.FindAllStringSubmatchIndex(content, -1) {
+			start, end := loc[2*rule.Group], loc[2*rule.Group+1]
+			match := content[start:end]
+			if seen[rule.Name+match] {
+				continue
+			}
+			seen[rule.Name+match] = true
+
+			contextStart, contextEnd := start-jsSecretContext, end+jsSecretContext
+			if contextStart < 0 {
+				contextStart = 0
+			}
+			if contextEnd > len(content) {
+				contextEnd = len(content)
+			}
+			secrets = append(secrets, JSSecret{
+				JSURL:      jsURL,
+				SourcePath: sourcePath,
+				Rule:       rule.Name,
+				Severity:   rule.Severity,
+				Match:      match,
+				Line:       strings.Count(content[:start], "\n") + 1,
+				Context:    strings.ToValidUTF8(content[contextStart:contextEnd], ""),
+			})
+		}
+	}
+	return secrets
+}
+
+// sourceMapLocation returns where a file's source map should be, from the SourceMap header or
+// the sourceMappingURL comment, falling back to the file's URL with .map appended
+func sourceMapLocation(jsURL string, resp *http.Response, content string) string {
+	ref := resp.Header.Get("SourceMap")
+	if ref == "" {
+		ref = resp.Header.Get("X-SourceMap")
+	}
+	if ref == "" {
+		if matches := sourceMappingRegexp.FindAllStringSubmatch(content, -1); len(matches) > 0 {
+			ref = matches[len(matches)-1][1]
+		}
+	}
+	if strings.HasPrefix(ref, "data:") {
+		return ""
+	}
+	base, err := url.Parse(jsURL)
+	if err != nil {
+		return ""
+	}
+	if ref == "" {
+		u := *base
+		u.RawQuery = ""
+		u.Path += ".map"
+		return u.String()
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	return base.ResolveReference(refURL).String()
+}
+
+// cleanSourcePath turns a source map entry such as webpack:///./src/app.js into a relative path
+func cleanSourcePath(source string) string {
+	if i := strings.Index(source, "://"); i >= 0 {
+		source = source[i+3:]
+	}
+	source = strings.ReplaceAll(source, "\\", "/")
+	cleaned := path.Clean("/" + source)
+	return strings.TrimPrefix(cleaned, "/")
+}
+
+// fetchSourceMap downloads a source map and returns the original sources it embeds
+func fetchSourceMap(client *http.Client, mapURL string) (map[string]string, error) {
+	resp, body, err := fetchWithCustomHeaders(client, mapURL, jsSourceMapLimit)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+
+	var sourceMap struct {
+		Version        int       `json:"version"`
+		SourceRoot     string    `json:"sourceRoot"`
+		Sources        []string  `json:"sources"`
+		SourcesContent []*string `json:"sourcesContent"`
+	}
+	if err := json.Unmarshal(body, &sourceMap); err != nil {
+		return nil, fmt.Errorf("not a source map: %v", err)
+	}
+	if len(sourceMap.Sources) == 0 {
+		return nil, fmt.Errorf("source map lists no sources")
+	}
+
+	files := make(map[string]string)
+	for i, source := range sourceMap.Sources {
+		if len(files) >= jsMaxSourceFiles {
+			break
+		}
+		if i >= len(sourceMap.SourcesContent) || sourceMap.SourcesContent[i] == nil {
+			continue
+		}
+		content := *sourceMap.SourcesContent[i]
+		if len(content) > jsMaxSourceFileSize {
+			content = content[:jsMaxSourceFileSize]
+		}
+		sourcePath := cleanSourcePath(source)
+		if sourceMap.SourceRoot != "" {
+			sourcePath = cleanSourcePath(path.Join(cleanSourcePath(sourceMap.SourceRoot), sourcePath))
+		}
+		if sourcePath == "" {
+			continue
+		}
+		files[sourcePath] = strings.ToValidUTF8(content, "")
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("source map does not embed source content")
+	}
+	return files, nil
+}
+
+// analyzeJSFile downloads one file, extracts its endpoints and secrets and reconstructs its
+// sources when a source map is exposed
+func analyzeJSFile(client *http.Client, jsURL, pageURL string) (*jsAnalysisResult, error) {
+	resp, body, err := fetchWithCustomHeaders(client, jsURL, jsFileBodyLimit)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	if contentType := strings.ToLower(resp.Header.Get("Content-Type")); strings.Contains(contentType, "text/html") {
+		return nil, fmt.Errorf("served HTML instead of JavaScript")
+	}
+
+	sum := sha256.Sum256(body)
+	content := string(body)
+	result := &jsAnalysisResult{
+		file: JSFile{
+			URL:       jsURL,
+			PageURL:   pageURL,
+			SHA256:    hex.EncodeToString(sum[:]),
+			Size:      len(body),
+			Endpoints: extractJSEndpoints(content),
+		},
+		secrets: findJSSecrets(content, jsURL, ""),
+	}
+
+	// Most files have no map, so a failed fetch of the guessed .map URL is expected
+	if mapURL := sourceMapLocation(jsURL, resp, content); mapURL != "" {
+		if files, err := fetchSourceMap(client, mapURL); err == nil {
+			result.file.SourceMapURL = &mapURL
+			result.sourceFiles = files
+			for sourcePath, source := range files {
+				result.secrets = append(result.secrets, findJSSecrets(source, jsURL, sourcePath)...)
+			}
+		}
+	}
+	result.file.SourceFiles = len(result.sourceFiles)
+	result.file.SecretsCount = len(result.secrets)
+	return result, nil
+}
+
+func RunJSAnalysisScan(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ScopeTargetID string `json:"scope_target_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ScopeTargetID == "" {
+		http.Error(w, "Invalid request body. `scope_target_id` is required.", http.StatusBadRequest)
+		return
+	}
+
+	scanID := uuid.New().String()
+	_, err := dbPool.Exec(context.Background(),
+		`INSERT INTO js_analysis_scans (scan_id, scope_target_id, status) VALUES ($1, $2, $3)`,
+		scanID, payload.ScopeTargetID, "pending")
+	if err != nil {
+		log.Printf("[JS ANALYSIS] [ERROR] Failed to create scan record: %v", err)
+		http.Error(w, "Failed to create scan record.", http.StatusInternalServerError)
+		return
+	}
+
+	go ExecuteJSAnalysisScan(scanID, payload.ScopeTargetID)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"scan_id": scanID})
+}
+
+// ExecuteJSAnalysisScan analyses every in-scope JavaScript file of a scope target and records
+// which files changed since they were last downloaded
+func ExecuteJSAnalysisScan(scanID, scopeTargetID string) {
+	log.Printf("[JS ANALYSIS] [INFO] Starting JavaScript analysis for scope target %s (scan ID: %s)", scopeTargetID, scanID)
+	startTime := time.Now()
+	updateJSAnalysisScan(scanID, "processing", JSAnalysisScan{}, "", "")
+
+	client := newScanHTTPClient(30 * time.Second)
+	jsURLs, err := collectJSURLs(scopeTargetID, client)
+	if err != nil {
+		log.Printf("[JS ANALYSIS] [ERROR] %v", err)
+		updateJSAnalysisScan(scanID, "error", JSAnalysisScan{}, err.Error(), time.Since(startTime).String())
+		return
+	}
+	log.Printf("[JS ANALYSIS] [INFO] Found %d JavaScript files", len(jsURLs))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var results []*jsAnalysisResult
+	semaphore := make(chan struct{}, jsAnalysisWorkers)
+	for jsURL, pageURL := range jsURLs {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(jsURL, pageURL string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			result, err := analyzeJSFile(client, jsURL, pageURL)
+			if err != nil {
+				log.Printf("[JS ANALYSIS] [WARN] Skipping %s: %v", jsURL, err)
+				return
+			}
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}(jsURL, pageURL)
+	}
+	wg.Wait()
+
+	counts, err := saveJSAnalysisResults(scanID, scopeTargetID, results)
+	if err != nil {
+		log.Printf("[JS ANALYSIS] [ERROR] Failed to save results: %v", err)
+		updateJSAnalysisScan(scanID, "error", JSAnalysisScan{}, err.Error(), time.Since(startTime).String())
+		return
+	}
+
+	execTime := time.Since(startTime).String()
+	updateJSAnalysisScan(scanID, "success", counts, "", execTime)
+	log.Printf("[JS ANALYSIS] [INFO] Scan %s completed in %s: %d files, %d endpoints, %d secrets, %d source maps, %d changed",
+		scanID, execTime, counts.FilesAnalyzed, counts.EndpointsCount, counts.SecretsCount, counts.SourceMapsCount, counts.ChangedCount)
+}
+
+// saveJSAnalysisResults upserts the analysed files, comparing hashes with the previous scan,
+// replaces the reconstructed sources of files with a source map and replaces the scope
+// target's secrets with the latest matches
+func saveJSAnalysisResults(scanID, scopeTargetID string, results []*jsAnalysisResult) (JSAnalysisScan, error) {
+	var counts JSAnalysisScan
+	tx, err := dbPool.Begin(context.Background())
+	if err != nil {
+		return counts, fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(), `DELETE FROM js_secrets WHERE scope_target_id = $1`, scopeTargetID); err != nil {
+		return counts, fmt.Errorf("failed to delete old secrets: %v", err)
+	}
+
+	for _, result := range results {
+		f := result.file
+		// NOW() is fixed for the transaction, so last_changed equals it only for files changed here
+		var changed bool
+		err := tx.QueryRow(context.Background(), `
+			INSERT INTO js_files
+				(scope_target_id, url, page_url, sha256, size, endpoints, source_map_url, source_files, secrets_count, scan_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			ON CONFLICT (scope_target_id, url) DO UPDATE SET
+				previous_sha256 = CASE WHEN js_files.sha256 <> EXCLUDED.sha256 THEN js_files.sha256 ELSE js_files.previous_sha256 END,
+				last_changed = CASE WHEN js_files.sha256 <> EXCLUDED.sha256 THEN NOW() ELSE js_files.last_changed END,
+				change_count = js_files.change_count + CASE WHEN js_files.sha256 <> EXCLUDED.sha256 THEN 1 ELSE 0 END,
+				sha256 = EXCLUDED.sha256,
+				page_url = EXCLUDED.page_url,
+				size = EXCLUDED.size,
+				endpoints = EXCLUDED.endpoints,
+				source_map_url = EXCLUDED.source_map_url,
+				source_files = EXCLUDED.source_files,
+				secrets_count = EXCLUDED.secrets_count,
+				scan_id = EXCLUDED.scan_id,
+				last_seen = NOW()
+			RETURNING last_changed IS NOT NULL AND last_changed = NOW()`,
+			scopeTargetID, f.URL, f.PageURL, f.SHA256, f.Size, f.Endpoints, f.SourceMapURL, f.SourceFiles,
+			f.SecretsCount, scanID).Scan(&changed)
+		if err != nil {
+			return counts, fmt.Errorf("failed to store %s: %v", f.URL, err)
+		}
+
+		counts.FilesAnalyzed++
+		counts.EndpointsCount += len(f.Endpoints)
+		counts.SecretsCount += len(result.secrets)
+		if changed {
+			counts.ChangedCount++
+		}
+
+		if len(result.sourceFiles) > 0 {
+			counts.SourceMapsCount++
+			if _, err := tx.Exec(context.Background(),
+				`DELETE FROM js_source_files WHERE scope_target_id = $1 AND js_url = $2`, scopeTargetID, f.URL); err != nil {
+				return counts, fmt.Errorf("failed to delete old sources of %s: %v", f.URL, err)
+			}
+			batch := &pgx.Batch{}
+			for sourcePath, content := range result.sourceFiles {
+				batch.Queue(`
+					INSERT INTO js_source_files (scope_target_id, js_url, path, content, size, scan_id)
+					VALUES ($1, $2, $3, $4, $5, $6)
+					ON CONFLICT (scope_target_id, js_url, path) DO NOTHING`,
+					scopeTargetID, f.URL, sourcePath, content, len(content), scanID)
+			}
+			if err := tx.SendBatch(context.Background(), batch).Close(); err != nil {
+				return counts, fmt.Errorf("failed to store sources of %s: %v", f.URL, err)
+			}
+		}
+
+		for _, s := range result.secrets {
+			_, err := tx.Exec(context.Background(), `
+				INSERT INTO js_secrets
+					(scan_id, scope_target_id, js_url, source_path, rule, severity, match, line, context)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				ON CONFLICT (scope_target_id, js_url, source_path, rule, match) DO NOTHING`,
+				scanID, scopeTargetID, s.JSURL, s.SourcePath, s.Rule, s.Severity, s.Match, s.Line, s.Context)
+			if err != nil {
+				return counts, fmt.Errorf("failed to store %s secret in %s: %v", s.Rule, s.JSURL, err)
+			}
+		}
+	}
+
+	return counts, tx.Commit(context.Background())
+}
+
+func updateJSAnalysisScan(scanID, status string, counts JSAnalysisScan, errorMessage, execTime string) {
+	_, err := dbPool.Exec(context.Background(), `
+		UPDATE js_analysis_scans
+		SET status = $1, files_analyzed = $2, endpoints_count = $3, secrets_count = $4,
+		    source_maps_count = $5, changed_count = $6, error = NULLIF($7, ''), execution_time = NULLIF($8, '')
+		WHERE scan_id = $9`,
+		status, counts.FilesAnalyzed, counts.EndpointsCount, counts.SecretsCount, counts.SourceMapsCount,
+		counts.ChangedCount, errorMessage, execTime, scanID)
+	if err != nil {
+		log.Printf("[JS ANALYSIS] [ERROR] Failed to update scan status: %v", err)
+	}
+}
+
+const jsAnalysisScanColumns = `id, scan_id, scope_target_id, status, files_analyzed, endpoints_count,
+	secrets_count, source_maps_count, changed_count, error, execution_time, created_at`
+
+func scanJSAnalysisScan(row interface{ Scan(...interface{}) error }) (JSAnalysisScan, error) {
+	var scan JSAnalysisScan
+	err := row.Scan(
+		&scan.ID,
+		&scan.ScanID,
+		&scan.ScopeTargetID,
+		&scan.Status,
+		&scan.FilesAnalyzed,
+		&scan.EndpointsCount,
+		&scan.SecretsCount,
+		&scan.SourceMapsCount,
+		&scan.ChangedCount,
+		&scan.Error,
+		&scan.ExecTime,
+		&scan.CreatedAt,
+	)
+	return scan, err
+}
+
+func GetJSAnalysisScanStatus(w http.ResponseWriter, r *http.Request) {
+	scanID := mux.Vars(r)["scan_id"]
+
+	scan, err := scanJSAnalysisScan(dbPool.QueryRow(context.Background(),
+		`SELECT `+jsAnalysisScanColumns+` FROM js_analysis_scans WHERE scan_id = $1`, scanID))
+	if err != nil {
+		http.Error(w, "Scan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scan)
+}
+
+func GetJSAnalysisScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT `+jsAnalysisScanColumns+` FROM js_analysis_scans WHERE scope_target_id = $1 ORDER BY created_at DESC`,
+		scopeTargetID)
+	if err != nil {
+		log.Printf("[JS ANALYSIS] [ERROR] Failed to get scans for target %s: %v", scopeTargetID, err)
+		http.Error(w, "Failed to get scans", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	scans := []JSAnalysisScan{}
+	for rows.Next() {
+		scan, err := scanJSAnalysisScan(rows)
+		if err != nil {
+			log.Printf("[JS ANALYSIS] [ERROR] Failed to scan row: %v", err)
+			continue
+		}
+		scans = append(scans, scan)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scans)
+}
+
+// GetJSFiles returns the JavaScript files of a scope target. With changed=true only files whose
+// content has changed between scans are returned, most recently changed first.
+func GetJSFiles(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+	changedOnly := r.URL.Query().Get("changed") == "true"
+
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, scope_target_id, url, page_url, sha256, previous_sha256, size, endpoints, source_map_url,
+		       source_files, secrets_count, change_count, scan_id, first_seen, last_seen, last_changed
+		FROM js_files
+		WHERE scope_target_id = $1::uuid AND (NOT $2 OR last_changed IS NOT NULL)
+		ORDER BY last_changed DESC NULLS LAST, url ASC`, scopeTargetID, changedOnly)
+	if err != nil {
+		log.Printf("[JS ANALYSIS] [ERROR] Failed to get JS files: %v", err)
+		http.Error(w, "Failed to get JS files", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	files := []JSFile{}
+	for rows.Next() {
+		var f JSFile
+		if err := rows.Scan(&f.ID, &f.ScopeTargetID, &f.URL, &f.PageURL, &f.SHA256, &f.PreviousSHA256, &f.Size,
+			&f.Endpoints, &f.SourceMapURL, &f.SourceFiles, &f.SecretsCount, &f.ChangeCount, &f.ScanID,
+			&f.FirstSeen, &f.LastSeen, &f.LastChanged); err != nil {
+			log.Printf("[JS ANALYSIS] [ERROR] Failed to scan JS file: %v", err)
+			continue
+		}
+		files = append(files, f)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(files)
+}
+
+func fetchJSSecrets(scopeTargetID string) ([]JSSecret, error) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, scan_id, scope_target_id, js_url, source_path, rule, severity, match, line, context, created_at
+		FROM js_secrets
+		WHERE scope_target_id = $1::uuid
+		ORDER BY CASE severity WHEN 'critical' THEN 0 WHEN 'high' THEN 1 WHEN 'medium' THEN 2 WHEN 'low' THEN 3 ELSE 4 END,
+		         js_url ASC, rule ASC`, scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	secrets := []JSSecret{}
+	for rows.Next() {
+		var s JSSecret
+		if err := rows.Scan(&s.ID, &s.ScanID, &s.ScopeTargetID, &s.JSURL, &s.SourcePath, &s.Rule, &s.Severity,
+			&s.Match, &s.Line, &s.Context, &s.CreatedAt); err != nil {
+			log.Printf("[JS ANALYSIS] [ERROR] Failed to scan secret: %v", err)
+			continue
+		}
+		secrets = append(secrets, s)
+	}
+	return secrets, rows.Err()
+}
+
+// GetJSSecrets returns the secrets found by the latest JavaScript analysis
+func GetJSSecrets(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	secrets, err := fetchJSSecrets(scopeTargetID)
+	if err != nil {
+		log.Printf("[JS ANALYSIS] [ERROR] Failed to get secrets: %v", err)
+		http.Error(w, "Failed to get JS secrets", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(secrets)
+}
+
+// GetJSSourceFiles lists the sources reconstructed from a JavaScript file's source map
+func GetJSSourceFiles(w http.ResponseWriter, r *http.Request) {
+	fileID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(fileID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT s.id, s.scope_target_id, s.js_url, s.path, s.size, s.created_at
+		FROM js_source_files s
+		JOIN js_files f ON f.scope_target_id = s.scope_target_id AND f.url = s.js_url
+		WHERE f.id = $1::uuid
+		ORDER BY s.path ASC`, fileID)
+	if err != nil {
+		log.Printf("[JS ANALYSIS] [ERROR] Failed to get source files: %v", err)
+		http.Error(w, "Failed to get source files", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	files := []JSSourceFile{}
+	for rows.Next() {
+		var f JSSourceFile
+		if err := rows.Scan(&f.ID, &f.ScopeTargetID, &f.JSURL, &f.Path, &f.Size, &f.CreatedAt); err != nil {
+			log.Printf("[JS ANALYSIS] [ERROR] Failed to scan source file: %v", err)
+			continue
+		}
+		files = append(files, f)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(files)
+}
+
+// DownloadJSSourceFiles returns the sources reconstructed from a JavaScript file's source map
+// as a zip archive laid out like the original source tree
+func DownloadJSSourceFiles(w http.ResponseWriter, r *http.Request) {
+	fileID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(fileID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	var jsURL string
+	err := dbPool.QueryRow(context.Background(), `SELECT url FROM js_files WHERE id = $1`, fileID).Scan(&jsURL)
+	if err != nil {
+		http.Error(w, "JS file not found", http.StatusNotFound)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT s.path, s.content
+		FROM js_source_files s
+		JOIN js_files f ON f.scope_target_id = s.scope_target_id AND f.url = s.js_url
+		WHERE f.id = $1::uuid
+		ORDER BY s.path ASC`, fileID)
+	if err != nil {
+		log.Printf("[JS ANALYSIS] [ERROR] Failed to get source files: %v", err)
+		http.Error(w, "Failed to get source files", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	name := "sources"
+	if u, err := url.Parse(jsURL); err == nil && path.Base(u.Path) != "/" && path.Base(u.Path) != "." {
+		name = strings.TrimSuffix(path.Base(u.Path), ".js")
+	}
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s-sources.zip", name))
+
+	archive := zip.NewWriter(w)
+	for rows.Next() {
+		var sourcePath, content string
+		if err := rows.Scan(&sourcePath, &content); err != nil {
+			continue
+		}
+		entry, err := archive.Create(sourcePath)
+		if err != nil {
+			log.Printf("[JS ANALYSIS] [ERROR] Failed to add %s to archive: %v", sourcePath, err)
+			continue
+		}
+		io.WriteString(entry, content)
+	}
+	archive.Close()
+}
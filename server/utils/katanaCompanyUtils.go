@@ -172,6 +172,10 @@ func ExecuteKatanaCompanyScan(scanID string, domains []string, scopeTargetID str
 			"-rl", "10",
 		)
 
+		if burpProxy := GetBurpProxyURL(); burpProxy != "" {
+			cmd.Args = append(cmd.Args, "-proxy", burpProxy)
+		}
+
 		commandsExecuted = append(commandsExecuted, cmd.String())
 		log.Printf("[KATANA-COMPANY] [INFO] Executing command: %s", cmd.String())
 
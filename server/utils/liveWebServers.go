@@ -189,7 +189,7 @@ func ExecuteAndParseHttpxScan(scanID, domain string) {
 	// Get consolidated subdomains
 	log.Printf("[DEBUG] Fetching consolidated subdomains from database")
 	rows, err := dbPool.Query(context.Background(),
-		`SELECT subdomain FROM consolidated_subdomains WHERE scope_target_id = $1`,
+		`SELECT subdomain FROM consolidated_subdomains WHERE scope_target_id = $1 AND NOT is_wildcard`,
 		scopeTargetID)
 	if err != nil {
 		log.Printf("[ERROR] Failed to get consolidated subdomains: %v", err)
@@ -266,6 +266,11 @@ func ExecuteAndParseHttpxScan(scanID, domain string) {
 		dockerCmd = append(dockerCmd, "-H", customHeader)
 	}
 
+	// Route traffic through Burp Suite if enabled
+	if burpProxy := GetBurpProxyURL(); burpProxy != "" {
+		dockerCmd = append(dockerCmd, "-http-proxy", burpProxy)
+	}
+
 	// Add output file parameter
 	dockerCmd = append(dockerCmd, "-o", filepath.Join("/tmp", fmt.Sprintf("httpx-%s", scanID), "httpx-output.json"))
 
@@ -607,6 +612,42 @@ func ConsolidateSubdomains(scopeTargetID string) ([]string, error) {
 				LIMIT 1`,
 			table: "shuffledns_custom",
 		},
+		{
+			query: `
+				SELECT result 
+				FROM permutation_scans 
+				WHERE scope_target_id = $1 
+					AND status = 'success' 
+					AND result IS NOT NULL 
+					AND result != '' 
+				ORDER BY created_at DESC 
+				LIMIT 1`,
+			table: "permutations",
+		},
+		{
+			query: `
+				SELECT result 
+				FROM recursive_enumeration_scans 
+				WHERE scope_target_id = $1 
+					AND status = 'success' 
+					AND result IS NOT NULL 
+					AND result != '' 
+				ORDER BY created_at DESC 
+				LIMIT 1`,
+			table: "recursive_enum",
+		},
+		{
+			query: `
+				SELECT result 
+				FROM dns_audit_scans 
+				WHERE scope_target_id = $1 
+					AND status = 'success' 
+					AND result IS NOT NULL 
+					AND result != '' 
+				ORDER BY created_at DESC 
+				LIMIT 1`,
+			table: "dns_audit",
+		},
 		{
 			query: `
 				SELECT result 
@@ -716,6 +757,10 @@ func ConsolidateSubdomains(scopeTargetID string) ([]string, error) {
 	}
 	log.Printf("[INFO] Total unique subdomains found: %d", len(consolidatedSubdomains))
 
+	// Names that only resolve because their parent zone has a wildcard record are kept
+	// but flagged, so they stay out of httpx input and attack surface FQDNs
+	wildcardSubdomains := DetectWildcardSubdomains(scopeTargetID, baseDomain, consolidatedSubdomains)
+
 	// Update database
 	tx, err := dbPool.Begin(context.Background())
 	if err != nil {
@@ -728,21 +773,40 @@ func ConsolidateSubdomains(scopeTargetID string) ([]string, error) {
 		return nil, fmt.Errorf("failed to delete old consolidated subdomains: %v", err)
 	}
 
+	var validSubdomains []string
 	for _, subdomain := range consolidatedSubdomains {
+		wildcardZone, isWildcard := wildcardSubdomains[subdomain]
 		_, err = tx.Exec(context.Background(),
-			`INSERT INTO consolidated_subdomains (scope_target_id, subdomain) VALUES ($1, $2)
+			`INSERT INTO consolidated_subdomains (scope_target_id, subdomain, is_wildcard, wildcard_zone) VALUES ($1, $2, $3, NULLIF($4, ''))
 			ON CONFLICT (scope_target_id, subdomain) DO NOTHING`,
-			scopeTargetID, subdomain)
+			scopeTargetID, subdomain, isWildcard, wildcardZone)
 		if err != nil {
 			return nil, fmt.Errorf("failed to insert consolidated subdomain: %v", err)
 		}
+		if !isWildcard {
+			validSubdomains = append(validSubdomains, subdomain)
+		}
 	}
 
 	if err := tx.Commit(context.Background()); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %v", err)
 	}
 
-	return consolidatedSubdomains, nil
+	log.Printf("[INFO] %d subdomains kept after filtering %d wildcard answers", len(validSubdomains), len(wildcardSubdomains))
+	return validSubdomains, nil
+}
+
+// countWildcardSubdomains returns how many consolidated subdomains were flagged as wildcard answers
+func countWildcardSubdomains(scopeTargetID string) int {
+	var count int
+	err := dbPool.QueryRow(context.Background(),
+		`SELECT COUNT(*) FROM consolidated_subdomains WHERE scope_target_id = $1 AND is_wildcard = true`,
+		scopeTargetID).Scan(&count)
+	if err != nil {
+		log.Printf("[ERROR] Failed to count wildcard subdomains: %v", err)
+		return 0
+	}
+	return count
 }
 
 // HandleConsolidateSubdomains handles the HTTP request to consolidate subdomains
@@ -761,8 +825,9 @@ func HandleConsolidateSubdomains(w http.ResponseWriter, r *http.Request) {
 	}
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"count":      len(consolidatedSubdomains),
-		"subdomains": consolidatedSubdomains,
+		"count":             len(consolidatedSubdomains),
+		"subdomains":        consolidatedSubdomains,
+		"wildcard_filtered": countWildcardSubdomains(scopeTargetID),
 	})
 }
 
@@ -775,7 +840,7 @@ func GetConsolidatedSubdomains(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	query := `SELECT subdomain FROM consolidated_subdomains WHERE scope_target_id = $1 ORDER BY subdomain ASC`
+	query := `SELECT subdomain FROM consolidated_subdomains WHERE scope_target_id = $1 AND NOT is_wildcard ORDER BY subdomain ASC`
 	rows, err := dbPool.Query(context.Background(), query, scopeTargetID)
 	if err != nil {
 		http.Error(w, "Failed to get consolidated subdomains", http.StatusInternalServerError)
@@ -793,8 +858,9 @@ func GetConsolidatedSubdomains(w http.ResponseWriter, r *http.Request) {
 	}
 
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"count":      len(subdomains),
-		"subdomains": subdomains,
+		"count":             len(subdomains),
+		"subdomains":        subdomains,
+		"wildcard_filtered": countWildcardSubdomains(scopeTargetID),
 	})
 }
 
@@ -919,7 +985,7 @@ func GetTargetURLsForScopeTarget(w http.ResponseWriter, r *http.Request) {
 			content_length, 
 			findings_json, 
 			katana_results, 
-			ffuf_results,
+			` + contentDiscoveryEndpointsColumn + `,
 			http_response,
 			http_response_headers,
 			has_deprecated_tls,
@@ -938,7 +1004,8 @@ func GetTargetURLsForScopeTarget(w http.ResponseWriter, r *http.Request) {
 			dns_srv_records,
 			roi_score,
 			created_at,
-			screenshot
+			(SELECT s.id::text FROM screenshots s
+			 WHERE s.scope_target_id = target_urls.scope_target_id AND s.url = target_urls.url) AS screenshot_id
 		FROM target_urls 
 		WHERE scope_target_id = $1 
 		ORDER BY roi_score DESC, created_at DESC`
@@ -983,7 +1050,7 @@ func GetTargetURLsForScopeTarget(w http.ResponseWriter, r *http.Request) {
 			dnsSRVRecords       []string
 			roiScore            float64
 			createdAt           time.Time
-			screenshot          sql.NullString
+			screenshotID        sql.NullString
 		)
 
 		err := rows.Scan(
@@ -1016,7 +1083,7 @@ func GetTargetURLsForScopeTarget(w http.ResponseWriter, r *http.Request) {
 			&dnsSRVRecords,
 			&roiScore,
 			&createdAt,
-			&screenshot,
+			&screenshotID,
 		)
 		if err != nil {
 			log.Printf("[ERROR] Failed to scan row: %v", err)
@@ -1053,7 +1120,10 @@ func GetTargetURLsForScopeTarget(w http.ResponseWriter, r *http.Request) {
 			"dns_srv_records":        dnsSRVRecords,
 			"roi_score":              roiScore,
 			"created_at":             createdAt.Format(time.RFC3339),
-			"screenshot":             nullStringToString(screenshot),
+			"screenshot_id":          nullStringToString(screenshotID),
+		}
+		if screenshotID.Valid {
+			targetURL["screenshot_url"], targetURL["thumbnail_url"] = screenshotLinks(screenshotID.String)
 		}
 
 		targetURLs = append(targetURLs, targetURL)
@@ -9,12 +9,10 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -51,21 +49,6 @@ type DNSResults struct {
 	SRVRecords   []string
 }
 
-type FfufResult struct {
-	Input struct {
-		FUZZ string `json:"FUZZ"`
-	} `json:"input"`
-	Position         int    `json:"position"`
-	Status           int    `json:"status"`
-	Length           int    `json:"length"`
-	Words            int    `json:"words"`
-	Lines            int    `json:"lines"`
-	ContentType      string `json:"content-type"`
-	RedirectLocation string `json:"redirectlocation"`
-	Url              string `json:"url"`
-	Duration         int64  `json:"duration"`
-}
-
 func NormalizeURL(url string) string {
 	// Fix double colon issue
 	url = strings.ReplaceAll(url, "https:://", "https://")
@@ -250,6 +233,10 @@ func ExecuteAndParseMetaDataScan(scanID, domain string) {
 			"p", "15",
 		)
 
+		if burpProxy := GetBurpProxyURL(); burpProxy != "" {
+			katanaCmd.Args = append(katanaCmd.Args, "-proxy", burpProxy)
+		}
+
 		katanaCmd.WaitDelay = 30 * time.Second
 
 		var stdout, stderr bytes.Buffer
@@ -538,11 +525,11 @@ func ExecuteAndParseMetaDataScan(scanID, domain string) {
 		return
 	}
 
-	// Run ffuf scan for each URL
-	log.Printf("[INFO] Starting ffuf scans for all URLs")
+	// Run content discovery for each URL
+	log.Printf("[INFO] Starting content discovery for all URLs")
 	for baseURL := range katanaResults {
-		if err := ExecuteFfufScan(baseURL, scopeTargetID); err != nil {
-			log.Printf("[ERROR] Failed to run ffuf scan for URL %s: %v", baseURL, err)
+		if err := ExecuteContentDiscovery(baseURL, scopeTargetID); err != nil {
+			log.Printf("[ERROR] Failed to run content discovery for URL %s: %v", baseURL, err)
 			continue
 		}
 	}
@@ -631,10 +618,20 @@ func ExecuteAndParseNucleiTechScan(urls []string, scopeTargetID string) error {
 		}
 		log.Printf("[DEBUG] Marshaled headers JSON (length: %d): %s", len(headersJSON), string(headersJSON))
 
+		// Fingerprint technologies and merge them with those httpx reported
+		detected := DetectTechnologies(urlStr, resp.Header, body)
+		var existingTechnologies []string
+		dbPool.QueryRow(context.Background(),
+			`SELECT COALESCE(technologies, '{}') FROM target_urls WHERE url = $1 AND scope_target_id = $2`,
+			urlStr, scopeTargetID).Scan(&existingTechnologies)
+		technologies := mergeTechnologyNames(existingTechnologies, detected)
+		technologyDetailsJSON, _ := json.Marshal(detected)
+		log.Printf("[DEBUG] Fingerprinted %d technologies for URL %s", len(detected), urlStr)
+
 		// Store response data in database using UPSERT
 		_, err = dbPool.Exec(context.Background(),
-			`INSERT INTO target_urls (url, scope_target_id, status_code, title, content_length, http_response, http_response_headers, created_at)
-			 VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, NOW())
+			`INSERT INTO target_urls (url, scope_target_id, status_code, title, content_length, http_response, http_response_headers, technologies, technology_details, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7::jsonb, $8, $9::jsonb, NOW())
 			 ON CONFLICT (url, scope_target_id)
 			 DO UPDATE SET 
 			     status_code = EXCLUDED.status_code,
@@ -642,6 +639,8 @@ func ExecuteAndParseNucleiTechScan(urls []string, scopeTargetID string) error {
 			     content_length = EXCLUDED.content_length,
 			     http_response = EXCLUDED.http_response,
 			     http_response_headers = EXCLUDED.http_response_headers,
+			     technologies = EXCLUDED.technologies,
+			     technology_details = EXCLUDED.technology_details,
 			     updated_at = NOW()`,
 			urlStr,
 			scopeTargetID,
@@ -649,12 +648,19 @@ func ExecuteAndParseNucleiTechScan(urls []string, scopeTargetID string) error {
 			extractTitle(sanitizedBody),
 			len(body),
 			sanitizedBody,
-			string(headersJSON))
+			string(headersJSON),
+			technologies,
+			string(technologyDetailsJSON))
 		if err != nil {
 			log.Printf("[ERROR] Failed to store response data for URL %s: %v", urlStr, err)
 			continue
 		}
 		log.Printf("[INFO] Successfully stored response data for URL %s with %d headers", urlStr, len(headers))
+
+		// Keep the response in the URL's history so changes between runs can be reviewed
+		if err := ArchiveResponse(scopeTargetID, urlStr, "metadata", resp.StatusCode, resp.Header, body); err != nil {
+			log.Printf("[WARN] Failed to archive response for URL %s: %v", urlStr, err)
+		}
 	}
 
 	// Create a temporary file for URLs
@@ -817,22 +823,7 @@ func PerformDNSLookups(hostname string) DNSResults {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Create a custom resolver with shorter timeout
-	resolver := &net.Resolver{
-		PreferGo: true,
-		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
-			d := net.Dialer{
-				Timeout: 500 * time.Millisecond,
-			}
-			// Try Docker's internal DNS first
-			conn, err := d.DialContext(ctx, network, "127.0.0.11:53")
-			if err == nil {
-				return conn, nil
-			}
-			// Fall back to Google DNS only
-			return d.DialContext(ctx, network, "8.8.8.8:53")
-		},
-	}
+	resolver := SharedDNSPool()
 
 	// A and AAAA records
 	log.Printf("[DEBUG] Looking up A/AAAA records for %s", hostname)
@@ -1110,137 +1101,6 @@ func GanitizeResponse(input []byte) string {
 	return str
 }
 
-func ExecuteFfufScan(url string, scopeTargetID string) error {
-	log.Printf("[INFO] Starting ffuf scan for URL: %s", url)
-	startTime := time.Now()
-
-	// Create a temporary directory for output
-	tempDir := filepath.Join("/tmp", fmt.Sprintf("ffuf-%s", uuid.New().String()))
-	if err := os.MkdirAll(tempDir, 0755); err != nil {
-		return fmt.Errorf("failed to create temp directory: %v", err)
-	}
-	defer os.RemoveAll(tempDir)
-
-	// Copy wordlist to container
-	copyCmd := exec.Command(
-		"docker", "exec",
-		"ars0n-framework-v2-ffuf-1",
-		"cp",
-		"/wordlists/ffuf-wordlist-5000.txt",
-		"/wordlist.txt",
-	)
-
-	var copyStderr bytes.Buffer
-	copyCmd.Stderr = &copyStderr
-	if err := copyCmd.Run(); err != nil {
-		log.Printf("[ERROR] Failed to copy wordlist in container. Command: %s, Error: %v, Stderr: %s",
-			copyCmd.String(), err, copyStderr.String())
-		return fmt.Errorf("failed to copy wordlist in container: %v (stderr: %s)", err, copyStderr.String())
-	}
-	log.Printf("[DEBUG] Successfully copied wordlist in container")
-
-	// Verify wordlist exists in container
-	checkCmd := exec.Command(
-		"docker", "exec",
-		"ars0n-framework-v2-ffuf-1",
-		"ls", "-l", "/wordlist.txt",
-	)
-	if out, err := checkCmd.CombinedOutput(); err != nil {
-		log.Printf("[ERROR] Wordlist not found in container. Output: %s, Error: %v", string(out), err)
-		return fmt.Errorf("wordlist not found in container: %v", err)
-	} else {
-		log.Printf("[DEBUG] Wordlist verified in container: %s", string(out))
-	}
-
-	// Run ffuf scan only on the base target URL
-	fuzzyURL := fmt.Sprintf("%s/FUZZ", url)
-	cmd := exec.Command(
-		"docker", "exec",
-		"ars0n-framework-v2-ffuf-1",
-		"ffuf",
-		"-w", "/wordlist.txt",
-		"-u", fuzzyURL,
-		"-mc", "all",
-		"-o", "/output.json",
-		"-of", "json",
-		"-ac",
-		"-c",
-		"-r",
-		"-t", "50",
-	)
-
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-
-	log.Printf("[DEBUG] Running ffuf command: %s", cmd.String())
-	if err := cmd.Run(); err != nil {
-		log.Printf("[ERROR] ffuf scan failed for URL %s: %v\nStderr: %s",
-			url, err, stderr.String())
-		return fmt.Errorf("ffuf scan failed: %v", err)
-	}
-	log.Printf("[INFO] Completed ffuf scan for URL: %s", url)
-
-	// Read and parse results
-	outputCmd := exec.Command(
-		"docker", "exec",
-		"ars0n-framework-v2-ffuf-1",
-		"cat", "/output.json",
-	)
-	resultBytes, err := outputCmd.Output()
-	if err != nil {
-		log.Printf("[ERROR] Failed to read ffuf results file: %v", err)
-		return fmt.Errorf("failed to read ffuf results: %v", err)
-	}
-	log.Printf("[DEBUG] Read %d bytes from results file", len(resultBytes))
-
-	var results struct {
-		Results []FfufResult `json:"results"`
-	}
-	if err := json.Unmarshal(resultBytes, &results); err != nil {
-		log.Printf("[ERROR] Failed to parse ffuf results JSON: %v\nContent: %s", err, string(resultBytes))
-		return fmt.Errorf("failed to parse ffuf results: %v", err)
-	}
-	log.Printf("[DEBUG] Successfully parsed %d results from JSON", len(results.Results))
-
-	// Filter and format results
-	var endpoints []map[string]interface{}
-	for _, result := range results.Results {
-		endpoint := map[string]interface{}{
-			"path":   result.Input.FUZZ,
-			"status": result.Status,
-			"size":   result.Length,
-			"words":  result.Words,
-			"lines":  result.Lines,
-		}
-		endpoints = append(endpoints, endpoint)
-	}
-
-	// Store results in database
-	ffufResults := map[string]interface{}{
-		"endpoints": endpoints,
-	}
-	ffufResultsJSON, err := json.Marshal(ffufResults)
-	if err != nil {
-		log.Printf("[ERROR] Failed to marshal ffuf results to JSON: %v", err)
-		return fmt.Errorf("failed to marshal ffuf results: %v", err)
-	}
-	log.Printf("[DEBUG] Storing ffuf results in database: %s", string(ffufResultsJSON))
-
-	_, err = dbPool.Exec(context.Background(),
-		`UPDATE target_urls 
-		 SET ffuf_results = $1::jsonb 
-		 WHERE url = $2 AND scope_target_id = $3`,
-		string(ffufResultsJSON), url, scopeTargetID)
-	if err != nil {
-		log.Printf("[ERROR] Failed to store ffuf results in database: %v", err)
-		return fmt.Errorf("failed to store ffuf results: %v", err)
-	}
-	log.Printf("[INFO] Successfully stored ffuf results in database for URL %s. Scan completed in %s. Found %d endpoints.",
-		url, time.Since(startTime), len(endpoints))
-
-	return nil
-}
-
 func RunCompanyMetaDataScan(w http.ResponseWriter, r *http.Request) {
 	var payload struct {
 		ScopeTargetID string `json:"scope_target_id" binding:"required"`
@@ -1337,6 +1197,10 @@ func ExecuteAndParseCompanyMetaDataScan(scanID, scopeTargetID, ipPortScanID stri
 			"p", "15",
 		)
 
+		if burpProxy := GetBurpProxyURL(); burpProxy != "" {
+			katanaCmd.Args = append(katanaCmd.Args, "-proxy", burpProxy)
+		}
+
 		katanaCmd.WaitDelay = 30 * time.Second
 
 		var stdout, stderr bytes.Buffer
@@ -1442,17 +1306,17 @@ func ExecuteAndParseCompanyMetaDataScan(scanID, scopeTargetID, ipPortScanID stri
 		katanaResults[url] = crawledURLs
 	}
 
-	// Run Ffuf scans for each URL
-	log.Printf("[INFO] Starting Ffuf scans for Company metadata - Total URLs to scan: %d", len(liveWebServers))
-	completedFfuf := 0
+	// Run content discovery for each URL
+	log.Printf("[INFO] Starting content discovery for Company metadata - Total URLs to scan: %d", len(liveWebServers))
+	completedDiscovery := 0
 	for _, url := range liveWebServers {
-		completedFfuf++
-		log.Printf("[INFO] Running Ffuf scan for URL: %s (%d/%d)", url, completedFfuf, len(liveWebServers))
-		if err := ExecuteFfufScan(url, scopeTargetID); err != nil {
-			log.Printf("[WARN] Ffuf scan failed for URL %s (%d/%d): %v", url, completedFfuf, len(liveWebServers), err)
+		completedDiscovery++
+		log.Printf("[INFO] Running content discovery for URL: %s (%d/%d)", url, completedDiscovery, len(liveWebServers))
+		if err := ExecuteContentDiscovery(url, scopeTargetID); err != nil {
+			log.Printf("[WARN] Content discovery failed for URL %s (%d/%d): %v", url, completedDiscovery, len(liveWebServers), err)
 			continue
 		}
-		log.Printf("[INFO] Completed Ffuf scan for URL: %s (%d/%d)", url, completedFfuf, len(liveWebServers))
+		log.Printf("[INFO] Completed content discovery for URL: %s (%d/%d)", url, completedDiscovery, len(liveWebServers))
 	}
 
 	// Execute Nuclei tech scan using the same logic as regular metadata scan
@@ -1592,7 +1456,7 @@ func GetCompanyMetaDataResults(w http.ResponseWriter, r *http.Request) {
 			content_length, 
 			findings_json, 
 			katana_results, 
-			ffuf_results,
+			` + contentDiscoveryEndpointsColumn + `,
 			http_response,
 			http_response_headers,
 			has_deprecated_tls,
@@ -1611,7 +1475,8 @@ func GetCompanyMetaDataResults(w http.ResponseWriter, r *http.Request) {
 			dns_srv_records,
 			roi_score,
 			created_at,
-			screenshot
+			(SELECT s.id::text FROM screenshots s
+			 WHERE s.scope_target_id = target_urls.scope_target_id AND s.url = target_urls.url) AS screenshot_id
 		FROM target_urls 
 		WHERE scope_target_id = $1 
 		AND url IN (
@@ -1659,7 +1524,7 @@ func GetCompanyMetaDataResults(w http.ResponseWriter, r *http.Request) {
 			dnsSRVRecords       []string
 			roiScore            float64
 			createdAt           time.Time
-			screenshot          sql.NullString
+			screenshotID        sql.NullString
 		)
 
 		err := rows.Scan(
@@ -1669,7 +1534,7 @@ func GetCompanyMetaDataResults(w http.ResponseWriter, r *http.Request) {
 			&hasMismatchedSSL, &hasRevokedSSL, &hasSelfSignedSSL, &hasUntrustedRootSSL,
 			&dnsARecords, &dnsAAAARecords, &dnsCNAMERecords, &dnsMXRecords,
 			&dnsTXTRecords, &dnsNSRecords, &dnsPTRRecords, &dnsSRVRecords,
-			&roiScore, &createdAt, &screenshot,
+			&roiScore, &createdAt, &screenshotID,
 		)
 		if err != nil {
 			log.Printf("[ERROR] Failed to scan Company metadata result row: %v", err)
@@ -1706,7 +1571,10 @@ func GetCompanyMetaDataResults(w http.ResponseWriter, r *http.Request) {
 			"dns_srv_records":        dnsSRVRecords,
 			"roi_score":              roiScore,
 			"created_at":             createdAt.Format(time.RFC3339),
-			"screenshot":             nullStringToString(screenshot),
+			"screenshot_id":          nullStringToString(screenshotID),
+		}
+		if screenshotID.Valid {
+			targetURL["screenshot_url"], targetURL["thumbnail_url"] = screenshotLinks(screenshotID.String)
 		}
 
 		targetURLs = append(targetURLs, targetURL)
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -53,12 +55,13 @@ func convertAttackSurfaceAssetsToTargets(assetIDs []string, scopeTargetID string
 	for _, assetID := range assetIDs {
 		var assetType, assetIdentifier string
 		var asnNumber, cidrBlock, ipAddress, url, fqdn *string
+		var port *int
 
 		err := dbPool.QueryRow(context.Background(), `
-			SELECT asset_type, asset_identifier, asn_number, cidr_block, ip_address, url, fqdn
+			SELECT asset_type, asset_identifier, asn_number, cidr_block, ip_address, url, fqdn, port
 			FROM consolidated_attack_surface_assets 
 			WHERE id = $1 AND scope_target_id = $2
-		`, assetID, scopeTargetID).Scan(&assetType, &assetIdentifier, &asnNumber, &cidrBlock, &ipAddress, &url, &fqdn)
+		`, assetID, scopeTargetID).Scan(&assetType, &assetIdentifier, &asnNumber, &cidrBlock, &ipAddress, &url, &fqdn, &port)
 
 		if err != nil {
 			log.Printf("[WARN] Failed to get asset %s: %v", assetID, err)
@@ -90,6 +93,12 @@ func convertAttackSurfaceAssetsToTargets(assetIDs []string, scopeTargetID string
 				targets = append(targets, *url)
 				log.Printf("[DEBUG] Added live web server target: %s", *url)
 			}
+		case "network_service":
+			if ipAddress != nil && port != nil {
+				target := net.JoinHostPort(*ipAddress, strconv.Itoa(*port))
+				targets = append(targets, target)
+				log.Printf("[DEBUG] Added network service target: %s", target)
+			}
 		case "fqdn":
 			if fqdn != nil {
 				targets = append(targets, *fqdn)
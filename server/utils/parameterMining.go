@@ -0,0 +1,662 @@
+package utils
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// Kept per parameter so a session token seen on every page doesn't bloat the row
+	maxParameterValues     = 20
+	maxParameterSourceURLs = 10
+	maxParameterValueLen   = 256
+	parameterBatchSize     = 1000
+	// Placeholder put in place of the parameter value by the urls export, as ffuf expects
+	parameterFuzzMarker = "FUZZ"
+)
+
+// Parameter tags describe the vulnerability class a parameter is worth testing for
+const (
+	ParameterTagSSRF         = "ssrf"
+	ParameterTagOpenRedirect = "open_redirect"
+	ParameterTagLFI          = "lfi"
+	ParameterTagIDOR         = "idor"
+	ParameterTagSQLi         = "sqli"
+)
+
+var ParameterTags = []string{ParameterTagSSRF, ParameterTagOpenRedirect, ParameterTagLFI, ParameterTagIDOR, ParameterTagSQLi}
+
+var (
+	ssrfParamNames = toStringSet([]string{
+		"url", "uri", "u", "link", "href", "src", "source", "dest", "destination", "target", "host", "hostname",
+		"domain", "site", "server", "proxy", "callback", "callback_url", "webhook", "webhook_url", "feed", "rss",
+		"fetch", "load", "remote", "endpoint", "api", "image", "image_url", "img", "img_url", "avatar", "icon",
+		"preview", "imageurl", "uri_path", "resource", "address", "service", "wsdl", "xml", "download",
+	})
+	redirectParamNames = toStringSet([]string{
+		"redirect", "redirect_uri", "redirect_url", "redirecturl", "redirecturi", "redir", "return", "return_url",
+		"returnurl", "return_to", "returnto", "returnpath", "next", "next_url", "goto", "go", "continue", "forward",
+		"out", "rurl", "r", "dest", "destination", "target", "to", "url", "uri", "u", "checkout_url",
+		"success_url", "cancel_url", "failure_url", "login_url", "logout", "logout_url", "back", "backurl",
+		"origin", "referer", "ref", "jump", "callback", "from",
+	})
+	lfiParamNames = toStringSet([]string{
+		"file", "filename", "file_name", "filepath", "path", "pathname", "folder", "dir", "directory", "doc",
+		"document", "page", "template", "tpl", "include", "inc", "require", "layout", "view", "content",
+		"style", "theme", "lang", "language", "locale", "conf", "config", "module", "load", "read", "pdf",
+		"download", "attachment", "log", "show", "display", "root", "location",
+	})
+	idorParamNames = toStringSet([]string{
+		"id", "uid", "user", "userid", "user_id", "account", "accountid", "account_id", "customer",
+		"customer_id", "customerid", "order", "order_id", "orderid", "invoice", "invoice_id", "profile",
+		"profile_id", "doc_id", "document_id", "file_id", "pid", "member", "member_id", "number", "no", "num",
+		"group", "group_id", "org", "org_id", "report_id", "ticket", "ticket_id", "key", "ref",
+	})
+	sqliParamNames = toStringSet([]string{
+		"id", "select", "report", "role", "update", "query", "user", "name", "sort", "sort_by", "sortby",
+		"order", "order_by", "orderby", "where", "search", "q", "category", "cat", "column", "col", "field",
+		"table", "from", "filter", "limit", "offset", "keyword", "results", "sel", "type", "process", "row",
+		"view", "string", "item", "page_id", "product", "product_id", "pid", "dir", "group_by", "having",
+	})
+
+	numericValuePattern = regexp.MustCompile(`^-?[0-9]+$`)
+	uuidValuePattern    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	fileValuePattern    = regexp.MustCompile(`(?i)(\.\./|\.\.\\|^/|\.(php|asp|aspx|jsp|html?|txt|xml|json|ini|conf|log|pdf|inc|tpl)$)`)
+	hostValuePattern    = regexp.MustCompile(`^(?i)([a-z0-9-]+\.)+[a-z]{2,}(:[0-9]+)?(/.*)?$`)
+	crawledURLPattern   = regexp.MustCompile(`https?://[^\s<>"'\\]+`)
+)
+
+// ParameterMiningScan collects the query parameters seen in crawled and archived URLs
+type ParameterMiningScan struct {
+	ID              string    `json:"id"`
+	ScanID          string    `json:"scan_id"`
+	ScopeTargetID   string    `json:"scope_target_id"`
+	Status          string    `json:"status"`
+	URLsProcessed   int       `json:"urls_processed"`
+	ParametersCount int       `json:"parameters_count"`
+	TaggedCount     int       `json:"tagged_count"`
+	Error           *string   `json:"error"`
+	ExecTime        *string   `json:"execution_time"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// URLParameter is one query parameter name on a host. Tags lists the vulnerability classes
+// the name or its observed values suggest; Sources lists where the URLs came from (katana,
+// gospider and gau).
+type URLParameter struct {
+	ID            string    `json:"id"`
+	ScanID        string    `json:"scan_id"`
+	ScopeTargetID string    `json:"scope_target_id"`
+	Host          string    `json:"host"`
+	Name          string    `json:"name"`
+	Values        []string  `json:"values"`
+	SourceURLs    []string  `json:"source_urls"`
+	Sources       []string  `json:"sources"`
+	Tags          []string  `json:"tags"`
+	Occurrences   int       `json:"occurrences"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// parameterInventory gathers parameters keyed by host and name while URLs are read
+type parameterInventory struct {
+	params  map[string]*URLParameter
+	seen    map[string]bool
+	urls    int
+	inScope func(host string) bool
+}
+
+func newParameterInventory(inScope func(string) bool) *parameterInventory {
+	return &parameterInventory{params: make(map[string]*URLParameter), seen: make(map[string]bool), inScope: inScope}
+}
+
+func (inv *parameterInventory) add(rawURL, source string) {
+	rawURL = strings.TrimRight(strings.TrimSpace(rawURL), `.,;)]}'"`)
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.RawQuery == "" {
+		return
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" || !inv.inScope(host) {
+		return
+	}
+	if inv.seen[source+" "+rawURL] {
+		return
+	}
+	inv.seen[source+" "+rawURL] = true
+	inv.urls++
+	inv.addParams(host, rawURL, source, u.Query())
+}
+
+// addCorpusPattern records a URL corpus entry. Its stored values were merged from every archived
+// URL sharing the pattern, so they stand in for the example URL's own query.
+func (inv *parameterInventory) addCorpusPattern(exampleURL string, paramValues map[string][]string, source string) {
+	u, err := url.Parse(exampleURL)
+	if err != nil {
+		return
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" || !inv.inScope(host) || inv.seen[source+" "+exampleURL] {
+		return
+	}
+	inv.seen[source+" "+exampleURL] = true
+	inv.urls++
+
+	query := u.Query()
+	for name, values := range paramValues {
+		query[name] = values
+	}
+	inv.addParams(host, exampleURL, source, query)
+}
+
+// addParams merges the values of each query parameter into the inventory, with rawURL as the
+// URL they were seen on
+func (inv *parameterInventory) addParams(host, rawURL, source string, query url.Values) {
+	for name, values := range query {
+		if name == "" || len(name) > 100 {
+			continue
+		}
+		key := host + " " + name
+		param, exists := inv.params[key]
+		if !exists {
+			param = &URLParameter{Host: host, Name: name, Values: []string{}, SourceURLs: []string{}, Sources: []string{}}
+			inv.params[key] = param
+		}
+		param.Occurrences++
+		for _, value := range values {
+			if len(value) > maxParameterValueLen {
+				value = value[:maxParameterValueLen]
+			}
+			if len(param.Values) < maxParameterValues && !containsString(param.Values, value) {
+				param.Values = append(param.Values, value)
+			}
+		}
+		if len(param.SourceURLs) < maxParameterSourceURLs && !containsString(param.SourceURLs, rawURL) {
+			param.SourceURLs = append(param.SourceURLs, rawURL)
+		}
+		if !containsString(param.Sources, source) {
+			param.Sources = append(param.Sources, source)
+		}
+	}
+}
+
+// tagParameter applies the name and value heuristics. Names are matched case insensitively,
+// and names ending in _id or Id count as identifiers for IDOR when their values are numeric.
+func tagParameter(name string, values []string) []string {
+	lower := strings.ToLower(name)
+	var numeric, uuids, urls, paths, hosts int
+	for _, value := range values {
+		lowerValue := strings.ToLower(value)
+		switch {
+		case numericValuePattern.MatchString(value):
+			numeric++
+		case uuidValuePattern.MatchString(value):
+			uuids++
+		case strings.HasPrefix(lowerValue, "http://"), strings.HasPrefix(lowerValue, "https://"), strings.HasPrefix(value, "//"):
+			urls++
+		case fileValuePattern.MatchString(value):
+			paths++
+		case hostValuePattern.MatchString(value):
+			hosts++
+		}
+	}
+	idLike := strings.HasSuffix(lower, "_id") || strings.HasSuffix(lower, "-id") || strings.HasSuffix(name, "Id") || strings.HasSuffix(name, "ID")
+
+	var tags []string
+	if ssrfParamNames[lower] || urls > 0 || hosts > 0 {
+		tags = append(tags, ParameterTagSSRF)
+	}
+	if redirectParamNames[lower] || urls > 0 {
+		tags = append(tags, ParameterTagOpenRedirect)
+	}
+	if lfiParamNames[lower] || paths > 0 {
+		tags = append(tags, ParameterTagLFI)
+	}
+	if (idorParamNames[lower] || idLike) && (numeric > 0 || uuids > 0) {
+		tags = append(tags, ParameterTagIDOR)
+	}
+	if sqliParamNames[lower] || (numeric > 0 && numeric == len(values)) {
+		tags = append(tags, ParameterTagSQLi)
+	}
+	return tags
+}
+
+// parameterScope returns a filter for the hosts whose parameters belong to the scope target:
+// the wildcard root domain and its subdomains, plus any host with a live target URL
+func parameterScope(scopeTargetID string) (func(string) bool, error) {
+	var targetType, scopeTarget string
+	err := dbPool.QueryRow(context.Background(),
+		`SELECT type, scope_target FROM scope_targets WHERE id = $1`, scopeTargetID).Scan(&targetType, &scopeTarget)
+	if err != nil {
+		return nil, fmt.Errorf("scope target not found: %v", err)
+	}
+
+	hosts := make(map[string]bool)
+	rows, err := dbPool.Query(context.Background(), `SELECT url FROM target_urls WHERE scope_target_id = $1`, scopeTargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target URLs: %v", err)
+	}
+	for rows.Next() {
+		var targetURL string
+		if rows.Scan(&targetURL) == nil {
+			if u, err := url.Parse(targetURL); err == nil {
+				hosts[strings.ToLower(u.Hostname())] = true
+			}
+		}
+	}
+	rows.Close()
+
+	root := ""
+	switch targetType {
+	case "Wildcard":
+		root = normalizeDNSName(strings.TrimPrefix(scopeTarget, "*."))
+	case "URL":
+		if u, err := url.Parse(scopeTarget); err == nil && u.Hostname() != "" {
+			hosts[strings.ToLower(u.Hostname())] = true
+		}
+	}
+
+	return func(host string) bool {
+		if hosts[host] {
+			return true
+		}
+		return root != "" && (host == root || strings.HasSuffix(host, "."+root))
+	}, nil
+}
+
+// collectParameterURLs feeds the inventory with Katana results from target_urls, the output of
+// the latest GoSpider scan and the parameter values stored with the GAU URL corpus
+func collectParameterURLs(scopeTargetID string, inv *parameterInventory) error {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT katana_results FROM target_urls
+		WHERE scope_target_id = $1 AND katana_results IS NOT NULL`, scopeTargetID)
+	if err != nil {
+		return fmt.Errorf("failed to get Katana results: %v", err)
+	}
+	for rows.Next() {
+		var katanaJSON []byte
+		if err := rows.Scan(&katanaJSON); err != nil {
+			continue
+		}
+		var crawled []string
+		if err := json.Unmarshal(katanaJSON, &crawled); err != nil {
+			continue
+		}
+		for _, crawledURL := range crawled {
+			inv.add(crawledURL, "katana")
+		}
+	}
+	rows.Close()
+
+	var gospiderOutput string
+	err = dbPool.QueryRow(context.Background(), `
+		SELECT COALESCE(stdout, '') FROM gospider_scans
+		WHERE scope_target_id = $1 AND status = 'success'
+		ORDER BY created_at DESC
+		LIMIT 1`, scopeTargetID).Scan(&gospiderOutput)
+	if err != nil && err != pgx.ErrNoRows {
+		return fmt.Errorf("failed to get GoSpider output: %v", err)
+	}
+	for _, line := range strings.Split(gospiderOutput, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "===") {
+			continue
+		}
+		var gospiderResult struct {
+			Output string `json:"output"`
+		}
+		if json.Unmarshal([]byte(line), &gospiderResult) == nil && gospiderResult.Output != "" {
+			line = gospiderResult.Output
+		}
+		for _, match := range crawledURLPattern.FindAllString(line, -1) {
+			inv.add(match, "gospider")
+		}
+	}
+
+	rows, err = dbPool.Query(context.Background(), `
+		SELECT example_url, COALESCE(param_values, '{}') FROM urls
+		WHERE scope_target_id = $1 AND $2 = ANY(categories)`, scopeTargetID, URLCategoryParameterized)
+	if err != nil {
+		return fmt.Errorf("failed to get GAU URLs: %v", err)
+	}
+	for rows.Next() {
+		var exampleURL string
+		var paramValuesJSON []byte
+		if rows.Scan(&exampleURL, &paramValuesJSON) != nil {
+			continue
+		}
+		var paramValues map[string][]string
+		json.Unmarshal(paramValuesJSON, &paramValues)
+		inv.addCorpusPattern(exampleURL, paramValues, "gau")
+	}
+	rows.Close()
+	return nil
+}
+
+func RunParameterMiningScan(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ScopeTargetID string `json:"scope_target_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ScopeTargetID == "" {
+		http.Error(w, "Invalid request body. `scope_target_id` is required.", http.StatusBadRequest)
+		return
+	}
+
+	scanID := uuid.New().String()
+	_, err := dbPool.Exec(context.Background(),
+		`INSERT INTO parameter_mining_scans (scan_id, scope_target_id, status) VALUES ($1, $2, $3)`,
+		scanID, payload.ScopeTargetID, "pending")
+	if err != nil {
+		log.Printf("[PARAMS] [ERROR] Failed to create scan record: %v", err)
+		http.Error(w, "Failed to create scan record.", http.StatusInternalServerError)
+		return
+	}
+
+	go ExecuteParameterMiningScan(scanID, payload.ScopeTargetID)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"scan_id": scanID})
+}
+
+// ExecuteParameterMiningScan rebuilds the parameter inventory of a scope target
+func ExecuteParameterMiningScan(scanID, scopeTargetID string) {
+	log.Printf("[PARAMS] [INFO] Starting parameter mining for scope target %s (scan ID: %s)", scopeTargetID, scanID)
+	startTime := time.Now()
+	updateParameterMiningScan(scanID, "processing", 0, 0, 0, "", "")
+
+	inScope, err := parameterScope(scopeTargetID)
+	if err != nil {
+		log.Printf("[PARAMS] [ERROR] %v", err)
+		updateParameterMiningScan(scanID, "error", 0, 0, 0, err.Error(), time.Since(startTime).String())
+		return
+	}
+	inv := newParameterInventory(inScope)
+	if err := collectParameterURLs(scopeTargetID, inv); err != nil {
+		log.Printf("[PARAMS] [ERROR] %v", err)
+		updateParameterMiningScan(scanID, "error", 0, 0, 0, err.Error(), time.Since(startTime).String())
+		return
+	}
+
+	params := make([]*URLParameter, 0, len(inv.params))
+	tagged := 0
+	for _, param := range inv.params {
+		param.Tags = tagParameter(param.Name, param.Values)
+		if param.Tags == nil {
+			param.Tags = []string{}
+		} else {
+			tagged++
+		}
+		sort.Strings(param.Sources)
+		params = append(params, param)
+	}
+
+	if err := saveURLParameters(scanID, scopeTargetID, params); err != nil {
+		log.Printf("[PARAMS] [ERROR] Failed to save parameters: %v", err)
+		updateParameterMiningScan(scanID, "error", inv.urls, 0, 0, err.Error(), time.Since(startTime).String())
+		return
+	}
+
+	execTime := time.Since(startTime).String()
+	updateParameterMiningScan(scanID, "success", inv.urls, len(params), tagged, "", execTime)
+	log.Printf("[PARAMS] [INFO] Scan %s completed in %s: %d parameters (%d tagged) from %d URLs", scanID, execTime, len(params), tagged, inv.urls)
+}
+
+// saveURLParameters replaces the scope target's parameter inventory with the latest results
+func saveURLParameters(scanID, scopeTargetID string, params []*URLParameter) error {
+	tx, err := dbPool.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(), `DELETE FROM url_parameters WHERE scope_target_id = $1`, scopeTargetID); err != nil {
+		return fmt.Errorf("failed to delete old parameters: %v", err)
+	}
+
+	for start := 0; start < len(params); start += parameterBatchSize {
+		end := start + parameterBatchSize
+		if end > len(params) {
+			end = len(params)
+		}
+		batch := &pgx.Batch{}
+		for _, p := range params[start:end] {
+			batch.Queue(`
+				INSERT INTO url_parameters
+					(scan_id, scope_target_id, host, name, param_values, source_urls, sources, tags, occurrences)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+				ON CONFLICT (scope_target_id, host, name) DO NOTHING`,
+				scanID, scopeTargetID, p.Host, p.Name, p.Values, p.SourceURLs, p.Sources, p.Tags, p.Occurrences)
+		}
+		if err := tx.SendBatch(context.Background(), batch).Close(); err != nil {
+			return fmt.Errorf("failed to insert parameters: %v", err)
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func updateParameterMiningScan(scanID, status string, urlsProcessed, parametersCount, taggedCount int, errorMessage, execTime string) {
+	_, err := dbPool.Exec(context.Background(), `
+		UPDATE parameter_mining_scans
+		SET status = $1, urls_processed = $2, parameters_count = $3, tagged_count = $4,
+		    error = NULLIF($5, ''), execution_time = NULLIF($6, '')
+		WHERE scan_id = $7`,
+		status, urlsProcessed, parametersCount, taggedCount, errorMessage, execTime, scanID)
+	if err != nil {
+		log.Printf("[PARAMS] [ERROR] Failed to update scan status: %v", err)
+	}
+}
+
+const parameterMiningScanColumns = `id, scan_id, scope_target_id, status, urls_processed, parameters_count,
+	tagged_count, error, execution_time, created_at`
+
+func scanParameterMiningScan(row interface{ Scan(...interface{}) error }) (ParameterMiningScan, error) {
+	var scan ParameterMiningScan
+	err := row.Scan(
+		&scan.ID,
+		&scan.ScanID,
+		&scan.ScopeTargetID,
+		&scan.Status,
+		&scan.URLsProcessed,
+		&scan.ParametersCount,
+		&scan.TaggedCount,
+		&scan.Error,
+		&scan.ExecTime,
+		&scan.CreatedAt,
+	)
+	return scan, err
+}
+
+func GetParameterMiningScanStatus(w http.ResponseWriter, r *http.Request) {
+	scanID := mux.Vars(r)["scan_id"]
+
+	scan, err := scanParameterMiningScan(dbPool.QueryRow(context.Background(),
+		`SELECT `+parameterMiningScanColumns+` FROM parameter_mining_scans WHERE scan_id = $1`, scanID))
+	if err != nil {
+		http.Error(w, "Scan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scan)
+}
+
+func GetParameterMiningScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT `+parameterMiningScanColumns+` FROM parameter_mining_scans WHERE scope_target_id = $1 ORDER BY created_at DESC`,
+		scopeTargetID)
+	if err != nil {
+		log.Printf("[PARAMS] [ERROR] Failed to get scans for target %s: %v", scopeTargetID, err)
+		http.Error(w, "Failed to get scans", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	scans := []ParameterMiningScan{}
+	for rows.Next() {
+		scan, err := scanParameterMiningScan(rows)
+		if err != nil {
+			log.Printf("[PARAMS] [ERROR] Failed to scan row: %v", err)
+			continue
+		}
+		scans = append(scans, scan)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scans)
+}
+
+// fetchURLParameters returns the parameter inventory of a scope target, optionally limited to
+// a host, a tag or a parameter name
+func fetchURLParameters(scopeTargetID, host, tag, name string) ([]URLParameter, error) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, scan_id, scope_target_id, host, name, param_values, source_urls, sources, tags,
+		       occurrences, created_at
+		FROM url_parameters
+		WHERE scope_target_id = $1::uuid
+		  AND ($2 = '' OR host = $2)
+		  AND ($3 = '' OR $3 = ANY(tags))
+		  AND ($4 = '' OR name = $4)
+		ORDER BY cardinality(tags) DESC, host ASC, name ASC`,
+		scopeTargetID, strings.ToLower(host), tag, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	params := []URLParameter{}
+	for rows.Next() {
+		var p URLParameter
+		if err := rows.Scan(&p.ID, &p.ScanID, &p.ScopeTargetID, &p.Host, &p.Name, &p.Values, &p.SourceURLs,
+			&p.Sources, &p.Tags, &p.Occurrences, &p.CreatedAt); err != nil {
+			log.Printf("[PARAMS] [ERROR] Failed to scan parameter: %v", err)
+			continue
+		}
+		params = append(params, p)
+	}
+	return params, rows.Err()
+}
+
+// parameterFilters reads and validates the host, tag and name query parameters
+func parameterFilters(w http.ResponseWriter, r *http.Request) (string, string, string, string, bool) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return "", "", "", "", false
+	}
+	query := r.URL.Query()
+	tag := query.Get("tag")
+	if tag != "" && !toStringSet(ParameterTags)[tag] {
+		http.Error(w, "Unknown tag. Use one of: "+strings.Join(ParameterTags, ", "), http.StatusBadRequest)
+		return "", "", "", "", false
+	}
+	return scopeTargetID, query.Get("host"), tag, query.Get("name"), true
+}
+
+// GetURLParameters returns the parameter inventory of a scope target, with the most heavily
+// tagged parameters first
+func GetURLParameters(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID, host, tag, name, ok := parameterFilters(w, r)
+	if !ok {
+		return
+	}
+
+	params, err := fetchURLParameters(scopeTargetID, host, tag, name)
+	if err != nil {
+		log.Printf("[PARAMS] [ERROR] Failed to get parameters: %v", err)
+		http.Error(w, "Failed to get parameters", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(params)
+}
+
+// fuzzURL returns the source URL with the value of one parameter replaced by the fuzz marker
+func fuzzURL(rawURL, name string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	query := u.Query()
+	if _, ok := query[name]; !ok {
+		return ""
+	}
+	query.Set(name, parameterFuzzMarker)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// ExportURLParameters downloads the parameter inventory for manual testing. The urls format
+// has one URL per parameter with its value set to FUZZ, ready for ffuf or sqlmap; csv and json
+// carry the full inventory.
+func ExportURLParameters(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID, host, tag, name, ok := parameterFilters(w, r)
+	if !ok {
+		return
+	}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "urls"
+	}
+	if format != "urls" && format != "csv" && format != "json" {
+		http.Error(w, "Unknown format. Use one of: urls, csv, json", http.StatusBadRequest)
+		return
+	}
+
+	params, err := fetchURLParameters(scopeTargetID, host, tag, name)
+	if err != nil {
+		log.Printf("[PARAMS] [ERROR] Failed to get parameters for export: %v", err)
+		http.Error(w, "Failed to export parameters", http.StatusInternalServerError)
+		return
+	}
+
+	filename := fmt.Sprintf("ars0n-parameters-%s", time.Now().Format("20060102-150405"))
+	switch format {
+	case "json":
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.json", filename))
+		json.NewEncoder(w).Encode(params)
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.csv", filename))
+		writer := csv.NewWriter(w)
+		writer.Write([]string{"host", "name", "tags", "occurrences", "sources", "values", "source_urls"})
+		for _, p := range params {
+			writer.Write([]string{p.Host, p.Name, strings.Join(p.Tags, ";"), fmt.Sprint(p.Occurrences),
+				strings.Join(p.Sources, ";"), strings.Join(p.Values, ";"), strings.Join(p.SourceURLs, " ")})
+		}
+		writer.Flush()
+	default:
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s.txt", filename))
+		seen := make(map[string]bool)
+		for _, p := range params {
+			for _, sourceURL := range p.SourceURLs {
+				if fuzzed := fuzzURL(sourceURL, p.Name); fuzzed != "" && !seen[fuzzed] {
+					seen[fuzzed] = true
+					fmt.Fprintln(w, fuzzed)
+					break
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,451 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ars0n-framework-v2-server/dnsresolver"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// Used when neither the request nor the auto scan config sets the limits
+	defaultRecursiveMaxDepth = 2
+	defaultRecursiveMaxZones = 10
+	// A sub-zone needs this many known children to be enumerated without its own NS or SOA
+	defaultRecursiveMinChildren = 3
+	// Concurrent NS/SOA lookups while picking sub-zones
+	recursiveZoneCheckConcurrency = 20
+	recursiveZoneCheckTimeout     = 5 * time.Second
+)
+
+// RecursiveZone is a sub-zone picked for enumeration and what the passive sources found in it
+type RecursiveZone struct {
+	Zone       string   `json:"zone"`
+	Depth      int      `json:"depth"`
+	Children   int      `json:"children"`
+	Delegated  bool     `json:"delegated"`
+	Discovered int      `json:"discovered"`
+	New        int      `json:"new"`
+	Errors     []string `json:"errors,omitempty"`
+}
+
+// RecursiveEnumerationScan runs the passive sources against sub-zones of a wildcard target.
+// Result holds the subdomains found in those sub-zones, one per line, for consolidation.
+type RecursiveEnumerationScan struct {
+	ID                string          `json:"id"`
+	ScanID            string          `json:"scan_id"`
+	Domain            string          `json:"domain"`
+	Status            string          `json:"status"`
+	Result            *string         `json:"result"`
+	Error             *string         `json:"error"`
+	ExecTime          *string         `json:"execution_time"`
+	MaxDepth          int             `json:"max_depth"`
+	MaxZones          int             `json:"max_zones"`
+	Zones             []RecursiveZone `json:"zones"`
+	NewCount          int             `json:"new_count"`
+	CreatedAt         time.Time       `json:"created_at"`
+	ScopeTargetID     string          `json:"scope_target_id"`
+	AutoScanSessionID *string         `json:"auto_scan_session_id"`
+}
+
+// recursiveSource is a passive source that can be pointed at any zone
+type recursiveSource struct {
+	name string
+	run  func(zone string) ([]string, error)
+}
+
+var recursiveSources = []recursiveSource{
+	{"subfinder", func(zone string) ([]string, error) { return runSubdomainCommand(subfinderCommand(zone)) }},
+	{"assetfinder", func(zone string) ([]string, error) { return runSubdomainCommand(assetfinderCommand(zone)) }},
+	{"ctl", fetchCTLSubdomains},
+}
+
+// runSubdomainCommand runs a tool that prints one subdomain per line
+func runSubdomainCommand(cmd *exec.Cmd) ([]string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.Fields(stdout.String()), nil
+}
+
+// countSubZoneChildren counts the known names below every sub-zone of baseDomain at the given depth
+func countSubZoneChildren(names []string, baseDomain string, depth int) map[string]int {
+	children := make(map[string]int)
+	for _, name := range names {
+		if !strings.HasSuffix(name, "."+baseDomain) {
+			continue
+		}
+		labels := strings.Split(strings.TrimSuffix(name, "."+baseDomain), ".")
+		if len(labels) <= depth {
+			continue
+		}
+		zone := strings.Join(labels[len(labels)-depth:], ".") + "." + baseDomain
+		children[zone]++
+	}
+	return children
+}
+
+// hasOwnZone reports whether a name answers NS or SOA queries for itself, meaning it is
+// a delegated zone or the apex of one
+func hasOwnZone(ctx context.Context, pool *dnsresolver.Pool, zone string) bool {
+	for _, qtype := range []uint16{dnsresolver.TypeSOA, dnsresolver.TypeNS} {
+		msg, err := pool.Query(ctx, zone, qtype)
+		if err != nil || msg.Rcode != dnsresolver.RcodeSuccess {
+			continue
+		}
+		for _, rr := range msg.Answers {
+			if rr.Type == qtype && rr.Name == zone {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pickSubZones returns the unscanned sub-zones at depth worth enumerating, delegated zones first
+// and then by how many children they have
+func pickSubZones(names []string, baseDomain string, depth, minChildren int, scanned map[string]bool) []RecursiveZone {
+	children := countSubZoneChildren(names, baseDomain, depth)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var zones []RecursiveZone
+	semaphore := make(chan struct{}, recursiveZoneCheckConcurrency)
+	pool := SharedDNSPool()
+
+	for zone, count := range children {
+		if scanned[zone] {
+			continue
+		}
+		wg.Add(1)
+		go func(zone string, count int) {
+			defer wg.Done()
+			semaphore <- struct{}{}        // Acquire
+			defer func() { <-semaphore }() // Release
+
+			ctx, cancel := context.WithTimeout(context.Background(), recursiveZoneCheckTimeout)
+			defer cancel()
+			delegated := hasOwnZone(ctx, pool, zone)
+			if !delegated && count < minChildren {
+				return
+			}
+			mu.Lock()
+			zones = append(zones, RecursiveZone{Zone: zone, Depth: depth, Children: count, Delegated: delegated})
+			mu.Unlock()
+		}(zone, count)
+	}
+	wg.Wait()
+
+	sort.Slice(zones, func(i, j int) bool {
+		if zones[i].Delegated != zones[j].Delegated {
+			return zones[i].Delegated
+		}
+		if zones[i].Children != zones[j].Children {
+			return zones[i].Children > zones[j].Children
+		}
+		return zones[i].Zone < zones[j].Zone
+	})
+	return zones
+}
+
+// enumerateSubZone runs every passive source against a zone and returns the names found below it
+func enumerateSubZone(zone *RecursiveZone) []string {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	found := make(map[string]bool)
+
+	for _, source := range recursiveSources {
+		wg.Add(1)
+		go func(source recursiveSource) {
+			defer wg.Done()
+			names, err := source.run(zone.Zone)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("[RECURSIVE ENUM] [WARN] %s failed for %s: %v", source.name, zone.Zone, err)
+				zone.Errors = append(zone.Errors, fmt.Sprintf("%s: %v", source.name, err))
+				return
+			}
+			for _, name := range names {
+				name = strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(name), "*."), "."))
+				if strings.HasSuffix(name, "."+zone.Zone) {
+					found[name] = true
+				}
+			}
+		}(source)
+	}
+	wg.Wait()
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	zone.Discovered = len(names)
+	return names
+}
+
+// recursiveEnumerationLimits reads the depth and sub-zone budget from the auto scan config
+func recursiveEnumerationLimits() (int, int) {
+	maxDepth, maxZones := defaultRecursiveMaxDepth, defaultRecursiveMaxZones
+	var depth, zones int
+	err := dbPool.QueryRow(context.Background(),
+		`SELECT recursive_max_depth, recursive_max_zones FROM auto_scan_config LIMIT 1`).Scan(&depth, &zones)
+	if err == nil {
+		if depth > 0 {
+			maxDepth = depth
+		}
+		if zones > 0 {
+			maxZones = zones
+		}
+	}
+	return maxDepth, maxZones
+}
+
+func RunRecursiveEnumerationScan(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		FQDN              string  `json:"fqdn"`
+		MaxDepth          int     `json:"max_depth,omitempty"`
+		MaxZones          int     `json:"max_zones,omitempty"`
+		MinChildren       int     `json:"min_children,omitempty"`
+		AutoScanSessionID *string `json:"auto_scan_session_id,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.FQDN == "" {
+		http.Error(w, "Invalid request body. `fqdn` is required.", http.StatusBadRequest)
+		return
+	}
+
+	domain := payload.FQDN
+	var scopeTargetID string
+	err := dbPool.QueryRow(context.Background(),
+		`SELECT id FROM scope_targets WHERE type = 'Wildcard' AND scope_target = $1`,
+		"*."+domain).Scan(&scopeTargetID)
+	if err != nil {
+		log.Printf("[RECURSIVE ENUM] [ERROR] No matching wildcard scope target found for domain %s", domain)
+		http.Error(w, "No matching wildcard scope target found.", http.StatusBadRequest)
+		return
+	}
+
+	maxDepth, maxZones := recursiveEnumerationLimits()
+	if payload.MaxDepth > 0 {
+		maxDepth = payload.MaxDepth
+	}
+	if payload.MaxZones > 0 {
+		maxZones = payload.MaxZones
+	}
+	minChildren := defaultRecursiveMinChildren
+	if payload.MinChildren > 0 {
+		minChildren = payload.MinChildren
+	}
+
+	scanID := uuid.New().String()
+	var sessionID interface{}
+	if payload.AutoScanSessionID != nil && *payload.AutoScanSessionID != "" {
+		sessionID = *payload.AutoScanSessionID
+	}
+	_, err = dbPool.Exec(context.Background(), `
+		INSERT INTO recursive_enumeration_scans (scan_id, domain, status, max_depth, max_zones, scope_target_id, auto_scan_session_id)
+		VALUES ($1, $2, 'pending', $3, $4, $5, $6)`,
+		scanID, domain, maxDepth, maxZones, scopeTargetID, sessionID)
+	if err != nil {
+		log.Printf("[RECURSIVE ENUM] [ERROR] Failed to create scan record: %v", err)
+		http.Error(w, "Failed to create scan record.", http.StatusInternalServerError)
+		return
+	}
+
+	go ExecuteRecursiveEnumerationScan(scanID, domain, scopeTargetID, maxDepth, maxZones, minChildren)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"scan_id": scanID})
+}
+
+// ExecuteRecursiveEnumerationScan walks down the target one label at a time. At each depth it
+// picks sub-zones with their own NS or SOA, or with enough known children, runs the passive
+// sources against them and adds what they find before looking one level deeper.
+func ExecuteRecursiveEnumerationScan(scanID, domain, scopeTargetID string, maxDepth, maxZones, minChildren int) {
+	log.Printf("[RECURSIVE ENUM] [INFO] Starting recursive enumeration for %s (scan ID: %s, depth: %d, zones: %d)", domain, scanID, maxDepth, maxZones)
+	startTime := time.Now()
+	domain = strings.ToLower(domain)
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT subdomain FROM consolidated_subdomains WHERE scope_target_id = $1 AND NOT COALESCE(is_wildcard, false)`,
+		scopeTargetID)
+	if err != nil {
+		log.Printf("[RECURSIVE ENUM] [ERROR] Failed to get consolidated subdomains: %v", err)
+		updateRecursiveEnumerationScan(scanID, "error", "", fmt.Sprintf("Failed to get consolidated subdomains: %v", err), nil, 0, time.Since(startTime).String())
+		return
+	}
+	known := make(map[string]bool)
+	var names []string
+	for rows.Next() {
+		var subdomain string
+		if err := rows.Scan(&subdomain); err == nil {
+			subdomain = strings.ToLower(subdomain)
+			if !known[subdomain] {
+				known[subdomain] = true
+				names = append(names, subdomain)
+			}
+		}
+	}
+	rows.Close()
+
+	if _, err := dbPool.Exec(context.Background(),
+		`UPDATE recursive_enumeration_scans SET status = 'processing' WHERE scan_id = $1`, scanID); err != nil {
+		log.Printf("[RECURSIVE ENUM] [ERROR] Failed to update scan status: %v", err)
+	}
+
+	scanned := make(map[string]bool)
+	zones := []RecursiveZone{}
+	discovered := make(map[string]bool)
+
+	for depth := 1; depth <= maxDepth && len(zones) < maxZones; depth++ {
+		candidates := pickSubZones(names, domain, depth, minChildren, scanned)
+		log.Printf("[RECURSIVE ENUM] [INFO] Depth %d: %d sub-zones qualify", depth, len(candidates))
+		if len(candidates) == 0 {
+			break
+		}
+
+		for _, zone := range candidates {
+			if len(zones) >= maxZones {
+				log.Printf("[RECURSIVE ENUM] [INFO] Sub-zone budget of %d reached", maxZones)
+				break
+			}
+			scanned[zone.Zone] = true
+			for _, name := range enumerateSubZone(&zone) {
+				if !known[name] {
+					known[name] = true
+					names = append(names, name)
+					zone.New++
+				}
+				discovered[name] = true
+			}
+			log.Printf("[RECURSIVE ENUM] [INFO] %s: %d found, %d new", zone.Zone, zone.Discovered, zone.New)
+			zones = append(zones, zone)
+		}
+	}
+
+	results := make([]string, 0, len(discovered))
+	for name := range discovered {
+		results = append(results, name)
+	}
+	sort.Strings(results)
+
+	newCount := 0
+	for _, zone := range zones {
+		newCount += zone.New
+	}
+
+	execTime := time.Since(startTime).String()
+	status := "success"
+	errMsg := ""
+	if len(zones) == 0 {
+		status, errMsg = "completed", "No sub-zones qualified for recursive enumeration"
+	} else if len(results) == 0 {
+		status, errMsg = "completed", "No subdomains found in the enumerated sub-zones"
+	}
+	log.Printf("[RECURSIVE ENUM] [INFO] Recursive enumeration for %s finished in %s: %d sub-zones, %d subdomains, %d new", domain, execTime, len(zones), len(results), newCount)
+	updateRecursiveEnumerationScan(scanID, status, strings.Join(results, "\n"), errMsg, zones, newCount, execTime)
+}
+
+func updateRecursiveEnumerationScan(scanID, status, result, errMsg string, zones []RecursiveZone, newCount int, execTime string) {
+	if zones == nil {
+		zones = []RecursiveZone{}
+	}
+	zonesJSON, _ := json.Marshal(zones)
+	_, err := dbPool.Exec(context.Background(), `
+		UPDATE recursive_enumeration_scans
+		SET status = $1, result = NULLIF($2, ''), error = NULLIF($3, ''), zones = $4, new_count = $5, execution_time = $6
+		WHERE scan_id = $7`,
+		status, result, errMsg, zonesJSON, newCount, execTime, scanID)
+	if err != nil {
+		log.Printf("[RECURSIVE ENUM] [ERROR] Failed to update scan status for %s: %v", scanID, err)
+	}
+}
+
+const recursiveEnumerationScanColumns = `id, scan_id, domain, status, result, error, execution_time,
+	max_depth, max_zones, zones, new_count, created_at, scope_target_id, auto_scan_session_id`
+
+func scanRecursiveEnumerationScan(row interface{ Scan(...interface{}) error }) (RecursiveEnumerationScan, error) {
+	var scan RecursiveEnumerationScan
+	var zonesJSON []byte
+	err := row.Scan(
+		&scan.ID,
+		&scan.ScanID,
+		&scan.Domain,
+		&scan.Status,
+		&scan.Result,
+		&scan.Error,
+		&scan.ExecTime,
+		&scan.MaxDepth,
+		&scan.MaxZones,
+		&zonesJSON,
+		&scan.NewCount,
+		&scan.CreatedAt,
+		&scan.ScopeTargetID,
+		&scan.AutoScanSessionID,
+	)
+	scan.Zones = []RecursiveZone{}
+	if err == nil && len(zonesJSON) > 0 {
+		json.Unmarshal(zonesJSON, &scan.Zones)
+	}
+	return scan, err
+}
+
+func GetRecursiveEnumerationScanStatus(w http.ResponseWriter, r *http.Request) {
+	scanID := mux.Vars(r)["scan_id"]
+	row := dbPool.QueryRow(context.Background(), `SELECT `+recursiveEnumerationScanColumns+` FROM recursive_enumeration_scans WHERE scan_id = $1`, scanID)
+	scan, err := scanRecursiveEnumerationScan(row)
+	if err != nil {
+		http.Error(w, "Scan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scan)
+}
+
+func GetRecursiveEnumerationScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if scopeTargetID == "" {
+		http.Error(w, "Scope target ID is required", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT `+recursiveEnumerationScanColumns+` FROM recursive_enumeration_scans WHERE scope_target_id = $1 ORDER BY created_at DESC`,
+		scopeTargetID)
+	if err != nil {
+		log.Printf("[RECURSIVE ENUM] [ERROR] Failed to get scans: %v", err)
+		http.Error(w, "Failed to get scans", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	scans := []RecursiveEnumerationScan{}
+	for rows.Next() {
+		scan, err := scanRecursiveEnumerationScan(rows)
+		if err != nil {
+			log.Printf("[RECURSIVE ENUM] [ERROR] Failed to scan row: %v", err)
+			continue
+		}
+		scans = append(scans, scan)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scans)
+}
@@ -0,0 +1,870 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+)
+
+// ReportTemplate represents a user-editable report template
+type ReportTemplate struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Format    string    `json:"format"`
+	Preset    string    `json:"preset,omitempty"`
+	Content   string    `json:"content"`
+	BuiltIn   bool      `json:"built_in"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReportRequest describes which scope target data should be rendered into a report
+type ReportRequest struct {
+	ScopeTargetID      string   `json:"scope_target_id"`
+	Title              string   `json:"title"`
+	Format             string   `json:"format"`
+	Preset             string   `json:"preset"`
+	TemplateID         string   `json:"template_id"`
+	AssetIDs           []string `json:"asset_ids"`
+	TargetURLIDs       []string `json:"target_url_ids"`
+	NucleiScanIDs      []string `json:"nuclei_scan_ids"`
+	FindingIDs         []string `json:"finding_ids"`
+	Severities         []string `json:"severities"`
+	IncludeScreenshots bool     `json:"include_screenshots"`
+}
+
+// ReportFinding is a normalized finding that can be rendered into a report
+type ReportFinding struct {
+	ID          string   `json:"id"`
+	Source      string   `json:"source"`
+	TemplateID  string   `json:"template_id"`
+	Name        string   `json:"name"`
+	Severity    string   `json:"severity"`
+	Description string   `json:"description,omitempty"`
+	Host        string   `json:"host"`
+	MatchedAt   string   `json:"matched_at"`
+	Tags        []string `json:"tags,omitempty"`
+	References  []string `json:"references,omitempty"`
+	Extracted   []string `json:"extracted_results,omitempty"`
+	CurlCommand string   `json:"curl_command,omitempty"`
+	Timestamp   string   `json:"timestamp,omitempty"`
+}
+
+// ReportTargetURL is the subset of a target URL that is rendered into a report
+type ReportTargetURL struct {
+	ID            string
+	URL           string
+	StatusCode    int
+	Title         string
+	WebServer     string
+	Technologies  []string
+	ContentLength int
+	ROIScore      int
+	Screenshot    string
+}
+
+// ReportData is the value passed to report templates
+type ReportData struct {
+	Title              string
+	GeneratedAt        time.Time
+	ScopeTargetID      string
+	ScopeTarget        string
+	ScopeTargetType    string
+	ScopeTargetMode    string
+	AssetCounts        map[string]int
+	TotalAssets        int
+	SeverityCounts     map[string]int
+	TotalFindings      int
+	Assets             []AttackSurfaceAsset
+	TargetURLs         []ReportTargetURL
+	Findings           []ReportFinding
+	IncludeScreenshots bool
+}
+
+var reportSeverityOrder = map[string]int{
+	"critical": 0,
+	"high":     1,
+	"medium":   2,
+	"low":      3,
+	"info":     4,
+	"unknown":  5,
+}
+
+// GetReportTemplates returns the built-in presets followed by all user templates
+func GetReportTemplates(w http.ResponseWriter, r *http.Request) {
+	templates := builtInReportTemplates()
+
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, name, format, COALESCE(preset, ''), content, created_at, updated_at
+		FROM report_templates
+		ORDER BY name ASC`)
+	if err != nil {
+		log.Printf("[REPORT] [ERROR] Failed to query report templates: %v", err)
+		http.Error(w, "Failed to get report templates", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t ReportTemplate
+		if err := rows.Scan(&t.ID, &t.Name, &t.Format, &t.Preset, &t.Content, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			log.Printf("[REPORT] [ERROR] Failed to scan report template row: %v", err)
+			continue
+		}
+		templates = append(templates, t)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(templates)
+}
+
+// CreateReportTemplate stores a new user template after checking that it parses
+func CreateReportTemplate(w http.ResponseWriter, r *http.Request) {
+	var payload ReportTemplate
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateReportTemplate(payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := dbPool.QueryRow(context.Background(), `
+		INSERT INTO report_templates (name, format, preset, content)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
+		RETURNING id, created_at, updated_at`,
+		payload.Name, payload.Format, payload.Preset, payload.Content).Scan(&payload.ID, &payload.CreatedAt, &payload.UpdatedAt)
+	if err != nil {
+		log.Printf("[REPORT] [ERROR] Failed to insert report template: %v", err)
+		http.Error(w, "Failed to save report template", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(payload)
+}
+
+// UpdateReportTemplate replaces the name, format and content of a user template
+func UpdateReportTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID := mux.Vars(r)["id"]
+	if templateID == "" {
+		http.Error(w, "Template ID is required", http.StatusBadRequest)
+		return
+	}
+
+	var payload ReportTemplate
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := validateReportTemplate(payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	payload.ID = templateID
+	err := dbPool.QueryRow(context.Background(), `
+		UPDATE report_templates
+		SET name = $1, format = $2, preset = NULLIF($3, ''), content = $4, updated_at = NOW()
+		WHERE id = $5
+		RETURNING created_at, updated_at`,
+		payload.Name, payload.Format, payload.Preset, payload.Content, templateID).Scan(&payload.CreatedAt, &payload.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		http.Error(w, "Report template not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Printf("[REPORT] [ERROR] Failed to update report template %s: %v", templateID, err)
+		http.Error(w, "Failed to update report template", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(payload)
+}
+
+// DeleteReportTemplate removes a user template
+func DeleteReportTemplate(w http.ResponseWriter, r *http.Request) {
+	templateID := mux.Vars(r)["id"]
+	if templateID == "" {
+		http.Error(w, "Template ID is required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := dbPool.Exec(context.Background(), `DELETE FROM report_templates WHERE id = $1`, templateID)
+	if err != nil {
+		log.Printf("[REPORT] [ERROR] Failed to delete report template %s: %v", templateID, err)
+		http.Error(w, "Failed to delete report template", http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		http.Error(w, "Report template not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GenerateReport renders the selected findings and assets of a scope target as Markdown or HTML
+func GenerateReport(w http.ResponseWriter, r *http.Request) {
+	var req ReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ScopeTargetID == "" {
+		http.Error(w, "Invalid request body. scope_target_id is required.", http.StatusBadRequest)
+		return
+	}
+
+	tmpl, err := resolveReportTemplate(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("[REPORT] [INFO] Generating %s report for scope target %s using template %q", tmpl.Format, req.ScopeTargetID, tmpl.Name)
+
+	data, err := buildReportData(req)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			http.Error(w, "Scope target not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("[REPORT] [ERROR] Failed to collect report data: %v", err)
+		http.Error(w, "Failed to collect report data", http.StatusInternalServerError)
+		return
+	}
+
+	rendered, err := renderReport(tmpl, data)
+	if err != nil {
+		log.Printf("[REPORT] [ERROR] Failed to render report: %v", err)
+		http.Error(w, fmt.Sprintf("Failed to render report: %v", err), http.StatusUnprocessableEntity)
+		return
+	}
+
+	extension, contentType := "md", "text/markdown; charset=utf-8"
+	if tmpl.Format == "html" {
+		extension, contentType = "html", "text/html; charset=utf-8"
+	}
+	filename := fmt.Sprintf("%s-report-%s.%s", sanitizeReportFilename(data.ScopeTarget), time.Now().Format("20060102-150405"), extension)
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	w.Write(rendered)
+}
+
+func validateReportTemplate(t ReportTemplate) error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if t.Format != "markdown" && t.Format != "html" {
+		return fmt.Errorf("format must be either 'markdown' or 'html'")
+	}
+	if t.Preset != "" && t.Preset != "bug-bounty" && t.Preset != "pentest" {
+		return fmt.Errorf("preset must be either 'bug-bounty' or 'pentest'")
+	}
+	if strings.TrimSpace(t.Content) == "" {
+		return fmt.Errorf("content is required")
+	}
+	if _, err := parseReportTemplate(t); err != nil {
+		return fmt.Errorf("template does not parse: %v", err)
+	}
+	return nil
+}
+
+// resolveReportTemplate picks a stored template when template_id is set, otherwise a built-in preset
+func resolveReportTemplate(req ReportRequest) (ReportTemplate, error) {
+	if req.TemplateID != "" {
+		var t ReportTemplate
+		err := dbPool.QueryRow(context.Background(), `
+			SELECT id, name, format, COALESCE(preset, ''), content, created_at, updated_at
+			FROM report_templates WHERE id = $1`, req.TemplateID).Scan(
+			&t.ID, &t.Name, &t.Format, &t.Preset, &t.Content, &t.CreatedAt, &t.UpdatedAt)
+		if err != nil {
+			return t, fmt.Errorf("report template %s not found", req.TemplateID)
+		}
+		return t, nil
+	}
+
+	preset := req.Preset
+	if preset == "" {
+		preset = "bug-bounty"
+	}
+	format := req.Format
+	if format == "" {
+		format = "markdown"
+	}
+
+	for _, t := range builtInReportTemplates() {
+		if t.Preset == preset && t.Format == format {
+			return t, nil
+		}
+	}
+	return ReportTemplate{}, fmt.Errorf("no built-in template for preset %q and format %q", preset, format)
+}
+
+func buildReportData(req ReportRequest) (ReportData, error) {
+	data := ReportData{
+		Title:              req.Title,
+		GeneratedAt:        time.Now().UTC(),
+		ScopeTargetID:      req.ScopeTargetID,
+		IncludeScreenshots: req.IncludeScreenshots,
+	}
+
+	err := dbPool.QueryRow(context.Background(),
+		`SELECT scope_target, type, mode FROM scope_targets WHERE id = $1::uuid`,
+		req.ScopeTargetID).Scan(&data.ScopeTarget, &data.ScopeTargetType, &data.ScopeTargetMode)
+	if err != nil {
+		return data, err
+	}
+	if data.Title == "" {
+		data.Title = fmt.Sprintf("Security Assessment - %s", data.ScopeTarget)
+	}
+
+	data.AssetCounts, err = fetchAttackSurfaceAssetCounts(req.ScopeTargetID)
+	if err != nil {
+		return data, fmt.Errorf("failed to count attack surface assets: %v", err)
+	}
+	for _, count := range data.AssetCounts {
+		data.TotalAssets += count
+	}
+
+	if len(req.AssetIDs) > 0 {
+		assets, err := fetchConsolidatedAssets(req.ScopeTargetID)
+		if err != nil {
+			return data, fmt.Errorf("failed to fetch attack surface assets: %v", err)
+		}
+		selected := toStringSet(req.AssetIDs)
+		for _, asset := range assets {
+			if selected[asset.ID] {
+				data.Assets = append(data.Assets, asset)
+			}
+		}
+	}
+
+	if len(req.TargetURLIDs) > 0 {
+		data.TargetURLs, err = fetchReportTargetURLs(req.ScopeTargetID, req.TargetURLIDs, req.IncludeScreenshots)
+		if err != nil {
+			return data, fmt.Errorf("failed to fetch target URLs: %v", err)
+		}
+	}
+
+	findings, err := fetchNucleiFindingsForScopeTarget(req.ScopeTargetID, req.NucleiScanIDs)
+	if err != nil {
+		return data, fmt.Errorf("failed to fetch findings: %v", err)
+	}
+	data.Findings = filterReportFindings(findings, req.FindingIDs, req.Severities)
+
+	data.SeverityCounts = map[string]int{"critical": 0, "high": 0, "medium": 0, "low": 0, "info": 0}
+	for _, f := range data.Findings {
+		data.SeverityCounts[f.Severity]++
+	}
+	data.TotalFindings = len(data.Findings)
+
+	return data, nil
+}
+
+func fetchReportTargetURLs(scopeTargetID string, ids []string, includeScreenshots bool) ([]ReportTargetURL, error) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, url, COALESCE(status_code, 0), COALESCE(title, ''), COALESCE(web_server, ''),
+		       COALESCE(technologies, ARRAY[]::text[]), COALESCE(content_length, 0), COALESCE(roi_score, 0)
+		FROM target_urls
+		WHERE scope_target_id = $1::uuid AND id = ANY($2::uuid[])
+		ORDER BY roi_score DESC, url ASC`, scopeTargetID, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var targetURLs []ReportTargetURL
+	for rows.Next() {
+		var t ReportTargetURL
+		if err := rows.Scan(&t.ID, &t.URL, &t.StatusCode, &t.Title, &t.WebServer,
+			&t.Technologies, &t.ContentLength, &t.ROIScore); err != nil {
+			log.Printf("[REPORT] [ERROR] Failed to scan target URL row: %v", err)
+			continue
+		}
+		targetURLs = append(targetURLs, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if includeScreenshots {
+		for i := range targetURLs {
+			targetURLs[i].Screenshot = loadScreenshotBase64(scopeTargetID, targetURLs[i].URL)
+		}
+	}
+	return targetURLs, nil
+}
+
+// fetchNucleiFindingsForScopeTarget flattens the stored results of successful Nuclei scans.
+// Finding IDs have the form "nuclei:<scan_id>:<index>" and are stable for a given scan.
+func fetchNucleiFindingsForScopeTarget(scopeTargetID string, scanIDs []string) ([]ReportFinding, error) {
+	query := `
+		SELECT scan_id, result FROM nuclei_scans
+		WHERE scope_target_id = $1::uuid AND status = 'success' AND result IS NOT NULL`
+	args := []interface{}{scopeTargetID}
+	if len(scanIDs) > 0 {
+		query += ` AND scan_id = ANY($2::uuid[])`
+		args = append(args, scanIDs)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := dbPool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var findings []ReportFinding
+	for rows.Next() {
+		var scanID, result string
+		if err := rows.Scan(&scanID, &result); err != nil {
+			log.Printf("[REPORT] [ERROR] Failed to scan Nuclei result row: %v", err)
+			continue
+		}
+
+		var nucleiFindings []NucleiFinding
+		if err := json.Unmarshal([]byte(result), &nucleiFindings); err != nil {
+			log.Printf("[REPORT] [WARN] Failed to parse Nuclei results for scan %s: %v", scanID, err)
+			continue
+		}
+
+		for idx, nf := range nucleiFindings {
+			matchedAt := nf.MatchedAt
+			if matchedAt == "" {
+				matchedAt = nf.Matched
+			}
+			severity := strings.ToLower(nf.Info.Severity)
+			if _, ok := reportSeverityOrder[severity]; !ok {
+				severity = "unknown"
+			}
+			findings = append(findings, ReportFinding{
+				ID:          fmt.Sprintf("nuclei:%s:%d", scanID, idx),
+				Source:      "nuclei",
+				TemplateID:  nf.TemplateID,
+				Name:        nf.Info.Name,
+				Severity:    severity,
+				Description: nf.Info.Description,
+				Host:        nf.Host,
+				MatchedAt:   matchedAt,
+				Tags:        nf.Info.Tags,
+				References:  nf.Info.Reference,
+				Extracted:   nf.Extracted,
+				CurlCommand: nf.CurlCommand,
+				Timestamp:   nf.Timestamp,
+			})
+		}
+	}
+	return findings, rows.Err()
+}
+
+func filterReportFindings(findings []ReportFinding, ids []string, severities []string) []ReportFinding {
+	selectedIDs := toStringSet(ids)
+	selectedSeverities := make(map[string]bool, len(severities))
+	for _, severity := range severities {
+		selectedSeverities[strings.ToLower(strings.TrimSpace(severity))] = true
+	}
+
+	var filtered []ReportFinding
+	for _, f := range findings {
+		if len(selectedIDs) > 0 && !selectedIDs[f.ID] {
+			continue
+		}
+		if len(selectedSeverities) > 0 && !selectedSeverities[f.Severity] {
+			continue
+		}
+		filtered = append(filtered, f)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		return reportSeverityOrder[filtered[i].Severity] < reportSeverityOrder[filtered[j].Severity]
+	})
+	return filtered
+}
+
+func toStringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[strings.TrimSpace(v)] = true
+	}
+	return set
+}
+
+func sanitizeReportFilename(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '.':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "scope-target"
+	}
+	return b.String()
+}
+
+func reportFuncMap() map[string]interface{} {
+	return map[string]interface{}{
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"title": func(s string) string {
+			if s == "" {
+				return s
+			}
+			return strings.ToUpper(s[:1]) + s[1:]
+		},
+		"join": strings.Join,
+		"add":  func(a, b int) int { return a + b },
+		"date": func(t time.Time) string { return t.Format("2006-01-02 15:04 MST") },
+		"deref": func(s *string) string {
+			if s == nil {
+				return ""
+			}
+			return *s
+		},
+		"derefInt": func(i *int) int {
+			if i == nil {
+				return 0
+			}
+			return *i
+		},
+		"count": func(counts map[string]int, key string) int { return counts[key] },
+	}
+}
+
+// reportExecutor is satisfied by both text/template and html/template templates
+type reportExecutor interface {
+	Execute(wr io.Writer, data interface{}) error
+}
+
+// parseReportTemplate parses with html/template for HTML output so that asset data is escaped
+func parseReportTemplate(t ReportTemplate) (reportExecutor, error) {
+	if t.Format == "html" {
+		funcs := htmltemplate.FuncMap(reportFuncMap())
+		funcs["screenshotURI"] = func(b64 string) htmltemplate.URL {
+			return htmltemplate.URL("data:image/png;base64," + b64)
+		}
+		return htmltemplate.New(t.Name).Funcs(funcs).Parse(t.Content)
+	}
+
+	funcs := texttemplate.FuncMap(reportFuncMap())
+	funcs["screenshotURI"] = func(b64 string) string {
+		return "data:image/png;base64," + b64
+	}
+	return texttemplate.New(t.Name).Funcs(funcs).Parse(t.Content)
+}
+
+func renderReport(t ReportTemplate, data ReportData) ([]byte, error) {
+	parsed, err := parseReportTemplate(t)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := parsed.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func builtInReportTemplates() []ReportTemplate {
+	return []ReportTemplate{
+		{ID: "builtin-bug-bounty-markdown", Name: "Bug Bounty Submission (Markdown)", Format: "markdown", Preset: "bug-bounty", Content: bugBountyMarkdownTemplate, BuiltIn: true},
+		{ID: "builtin-bug-bounty-html", Name: "Bug Bounty Submission (HTML)", Format: "html", Preset: "bug-bounty", Content: bugBountyHTMLTemplate, BuiltIn: true},
+		{ID: "builtin-pentest-markdown", Name: "Penetration Test Report (Markdown)", Format: "markdown", Preset: "pentest", Content: pentestMarkdownTemplate, BuiltIn: true},
+		{ID: "builtin-pentest-html", Name: "Penetration Test Report (HTML)", Format: "html", Preset: "pentest", Content: pentestHTMLTemplate, BuiltIn: true},
+	}
+}
+
+const bugBountyMarkdownTemplate = `# {{.Title}}
+
+**Target:** {{.ScopeTarget}} ({{.ScopeTargetType}})
+**Date:** {{date .GeneratedAt}}
+
+{{range $i, $f := .Findings}}
+## {{add $i 1}}. {{$f.Name}}
+
+**Severity:** {{upper $f.Severity}}
+**Affected asset:** ` + "`{{$f.MatchedAt}}`" + `
+**Detected by:** {{$f.Source}} ({{$f.TemplateID}})
+
+### Summary
+{{if $f.Description}}{{$f.Description}}{{else}}_Describe the issue here._{{end}}
+
+### Steps to reproduce
+{{if $f.CurlCommand}}
+` + "```" + `
+{{$f.CurlCommand}}
+` + "```" + `
+{{else}}
+1. Browse to ` + "`{{$f.MatchedAt}}`" + `
+{{end}}
+{{if $f.Extracted}}
+### Evidence
+{{range $f.Extracted}}- ` + "`{{.}}`" + `
+{{end}}{{end}}
+### Impact
+_Describe the security impact._
+{{if $f.References}}
+### References
+{{range $f.References}}- {{.}}
+{{end}}{{end}}
+{{else}}
+_No findings selected._
+{{end}}
+{{if .TargetURLs}}
+## Affected endpoints
+{{range .TargetURLs}}
+### {{.URL}}
+- Status: {{.StatusCode}}{{if .Title}}
+- Title: {{.Title}}{{end}}{{if .WebServer}}
+- Server: {{.WebServer}}{{end}}{{if .Technologies}}
+- Technologies: {{join .Technologies ", "}}{{end}}
+{{if .Screenshot}}
+![Screenshot of {{.URL}}]({{screenshotURI .Screenshot}})
+{{end}}{{end}}{{end}}
+`
+
+const pentestMarkdownTemplate = `# {{.Title}}
+
+| | |
+|---|---|
+| Scope target | {{.ScopeTarget}} |
+| Target type | {{.ScopeTargetType}} |
+| Mode | {{.ScopeTargetMode}} |
+| Report date | {{date .GeneratedAt}} |
+
+## Executive Summary
+
+The assessment identified **{{.TotalAssets}}** attack surface assets and **{{.TotalFindings}}** findings.
+
+### Attack surface
+
+| Asset type | Count |
+|---|---|
+| ASNs | {{count .AssetCounts "asns"}} |
+| Network ranges | {{count .AssetCounts "network_ranges"}} |
+| IP addresses | {{count .AssetCounts "ip_addresses"}} |
+| Live web servers | {{count .AssetCounts "live_web_servers"}} |
+| Network services | {{count .AssetCounts "network_services"}} |
+| Cloud assets | {{count .AssetCounts "cloud_assets"}} |
+| FQDNs | {{count .AssetCounts "fqdns"}} |
+
+### Findings by severity
+
+| Severity | Count |
+|---|---|
+| Critical | {{count .SeverityCounts "critical"}} |
+| High | {{count .SeverityCounts "high"}} |
+| Medium | {{count .SeverityCounts "medium"}} |
+| Low | {{count .SeverityCounts "low"}} |
+| Informational | {{count .SeverityCounts "info"}} |
+
+## Findings
+{{range $i, $f := .Findings}}
+### {{add $i 1}}. [{{upper $f.Severity}}] {{$f.Name}}
+
+- **Asset:** ` + "`{{$f.MatchedAt}}`" + `
+- **Host:** {{$f.Host}}
+- **Check:** {{$f.Source}} / {{$f.TemplateID}}{{if $f.Tags}}
+- **Tags:** {{join $f.Tags ", "}}{{end}}
+
+{{if $f.Description}}{{$f.Description}}{{end}}
+{{if $f.Extracted}}
+**Evidence**
+{{range $f.Extracted}}- ` + "`{{.}}`" + `
+{{end}}{{end}}{{if $f.CurlCommand}}
+**Reproduction**
+
+` + "```" + `
+{{$f.CurlCommand}}
+` + "```" + `
+{{end}}{{if $f.References}}
+**References**
+{{range $f.References}}- {{.}}
+{{end}}{{end}}{{else}}
+_No findings were selected for this report._
+{{end}}
+{{if .Assets}}
+## Asset Inventory
+
+| Type | Identifier | Details |
+|---|---|---|
+{{range .Assets}}| {{.AssetType}} | {{.AssetIdentifier}} | {{if .Title}}{{deref .Title}}{{else if .ASNOrganization}}{{deref .ASNOrganization}}{{else if .CloudProvider}}{{deref .CloudProvider}}{{end}} |
+{{end}}{{end}}
+{{if .TargetURLs}}
+## Web Endpoints
+
+| URL | Status | Title | Technologies |
+|---|---|---|---|
+{{range .TargetURLs}}| {{.URL}} | {{.StatusCode}} | {{.Title}} | {{join .Technologies ", "}} |
+{{end}}
+{{if .IncludeScreenshots}}
+### Screenshots
+{{range .TargetURLs}}{{if .Screenshot}}
+#### {{.URL}}
+![Screenshot of {{.URL}}]({{screenshotURI .Screenshot}})
+{{end}}{{end}}{{end}}{{end}}
+`
+
+const reportHTMLStyle = `<style>
+  body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; max-width: 960px; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; }
+  h1 { border-bottom: 2px solid #1f2328; padding-bottom: .3rem; }
+  h2 { border-bottom: 1px solid #d0d7de; padding-bottom: .2rem; margin-top: 2rem; }
+  table { border-collapse: collapse; width: 100%; margin: 1rem 0; }
+  th, td { border: 1px solid #d0d7de; padding: .35rem .6rem; text-align: left; vertical-align: top; }
+  th { background: #f6f8fa; }
+  code, pre { font-family: ui-monospace, Menlo, Consolas, monospace; font-size: .85em; }
+  pre { background: #f6f8fa; padding: .75rem; overflow-x: auto; white-space: pre-wrap; word-break: break-all; }
+  .finding { border: 1px solid #d0d7de; border-radius: 6px; padding: 0 1rem 1rem; margin: 1rem 0; page-break-inside: avoid; }
+  .sev { display: inline-block; padding: 0 .5rem; border-radius: 4px; color: #fff; font-weight: 600; font-size: .8em; }
+  .sev-critical { background: #8b0000; } .sev-high { background: #d1242f; } .sev-medium { background: #d4a72c; }
+  .sev-low { background: #1a7f37; } .sev-info, .sev-unknown { background: #57606a; }
+  img.screenshot { max-width: 100%; border: 1px solid #d0d7de; }
+  @page { size: A4; margin: 18mm; }
+  @media print { body { margin: 0; max-width: none; } h2 { page-break-after: avoid; } .screenshot-block { page-break-inside: avoid; } }
+</style>`
+
+const bugBountyHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+` + reportHTMLStyle + `
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p><strong>Target:</strong> {{.ScopeTarget}} ({{.ScopeTargetType}})<br><strong>Date:</strong> {{date .GeneratedAt}}</p>
+{{range $i, $f := .Findings}}
+<div class="finding">
+  <h2>{{add $i 1}}. {{$f.Name}} <span class="sev sev-{{$f.Severity}}">{{upper $f.Severity}}</span></h2>
+  <p><strong>Affected asset:</strong> <code>{{$f.MatchedAt}}</code><br><strong>Detected by:</strong> {{$f.Source}} ({{$f.TemplateID}})</p>
+  <h3>Summary</h3>
+  <p>{{if $f.Description}}{{$f.Description}}{{else}}<em>Describe the issue here.</em>{{end}}</p>
+  <h3>Steps to reproduce</h3>
+  {{if $f.CurlCommand}}<pre>{{$f.CurlCommand}}</pre>{{else}}<ol><li>Browse to <code>{{$f.MatchedAt}}</code></li></ol>{{end}}
+  {{if $f.Extracted}}<h3>Evidence</h3><ul>{{range $f.Extracted}}<li><code>{{.}}</code></li>{{end}}</ul>{{end}}
+  <h3>Impact</h3>
+  <p><em>Describe the security impact.</em></p>
+  {{if $f.References}}<h3>References</h3><ul>{{range $f.References}}<li>{{.}}</li>{{end}}</ul>{{end}}
+</div>
+{{else}}
+<p><em>No findings selected.</em></p>
+{{end}}
+{{if .TargetURLs}}
+<h2>Affected endpoints</h2>
+{{range .TargetURLs}}
+<div class="screenshot-block">
+  <h3>{{.URL}}</h3>
+  <p>Status: {{.StatusCode}}{{if .Title}} &middot; Title: {{.Title}}{{end}}{{if .WebServer}} &middot; Server: {{.WebServer}}{{end}}</p>
+  {{if .Technologies}}<p>Technologies: {{join .Technologies ", "}}</p>{{end}}
+  {{if .Screenshot}}<img class="screenshot" alt="Screenshot of {{.URL}}" src="{{screenshotURI .Screenshot}}">{{end}}
+</div>
+{{end}}
+{{end}}
+</body>
+</html>
+`
+
+const pentestHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+` + reportHTMLStyle + `
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<table>
+  <tr><th>Scope target</th><td>{{.ScopeTarget}}</td></tr>
+  <tr><th>Target type</th><td>{{.ScopeTargetType}}</td></tr>
+  <tr><th>Mode</th><td>{{.ScopeTargetMode}}</td></tr>
+  <tr><th>Report date</th><td>{{date .GeneratedAt}}</td></tr>
+</table>
+
+<h2>Executive Summary</h2>
+<p>The assessment identified <strong>{{.TotalAssets}}</strong> attack surface assets and <strong>{{.TotalFindings}}</strong> findings.</p>
+<table>
+  <tr><th>Asset type</th><th>Count</th></tr>
+  <tr><td>ASNs</td><td>{{count .AssetCounts "asns"}}</td></tr>
+  <tr><td>Network ranges</td><td>{{count .AssetCounts "network_ranges"}}</td></tr>
+  <tr><td>IP addresses</td><td>{{count .AssetCounts "ip_addresses"}}</td></tr>
+  <tr><td>Live web servers</td><td>{{count .AssetCounts "live_web_servers"}}</td></tr>
+  <tr><td>Network services</td><td>{{count .AssetCounts "network_services"}}</td></tr>
+  <tr><td>Cloud assets</td><td>{{count .AssetCounts "cloud_assets"}}</td></tr>
+  <tr><td>FQDNs</td><td>{{count .AssetCounts "fqdns"}}</td></tr>
+</table>
+<table>
+  <tr><th>Severity</th><th>Count</th></tr>
+  <tr><td><span class="sev sev-critical">CRITICAL</span></td><td>{{count .SeverityCounts "critical"}}</td></tr>
+  <tr><td><span class="sev sev-high">HIGH</span></td><td>{{count .SeverityCounts "high"}}</td></tr>
+  <tr><td><span class="sev sev-medium">MEDIUM</span></td><td>{{count .SeverityCounts "medium"}}</td></tr>
+  <tr><td><span class="sev sev-low">LOW</span></td><td>{{count .SeverityCounts "low"}}</td></tr>
+  <tr><td><span class="sev sev-info">INFO</span></td><td>{{count .SeverityCounts "info"}}</td></tr>
+</table>
+
+<h2>Findings</h2>
+{{range $i, $f := .Findings}}
+<div class="finding">
+  <h3>{{add $i 1}}. {{$f.Name}} <span class="sev sev-{{$f.Severity}}">{{upper $f.Severity}}</span></h3>
+  <table>
+    <tr><th>Asset</th><td><code>{{$f.MatchedAt}}</code></td></tr>
+    <tr><th>Host</th><td>{{$f.Host}}</td></tr>
+    <tr><th>Check</th><td>{{$f.Source}} / {{$f.TemplateID}}</td></tr>
+    {{if $f.Tags}}<tr><th>Tags</th><td>{{join $f.Tags ", "}}</td></tr>{{end}}
+  </table>
+  {{if $f.Description}}<p>{{$f.Description}}</p>{{end}}
+  {{if $f.Extracted}}<h4>Evidence</h4><ul>{{range $f.Extracted}}<li><code>{{.}}</code></li>{{end}}</ul>{{end}}
+  {{if $f.CurlCommand}}<h4>Reproduction</h4><pre>{{$f.CurlCommand}}</pre>{{end}}
+  {{if $f.References}}<h4>References</h4><ul>{{range $f.References}}<li>{{.}}</li>{{end}}</ul>{{end}}
+</div>
+{{else}}
+<p><em>No findings were selected for this report.</em></p>
+{{end}}
+
+{{if .Assets}}
+<h2>Asset Inventory</h2>
+<table>
+  <tr><th>Type</th><th>Identifier</th><th>Details</th></tr>
+  {{range .Assets}}<tr><td>{{.AssetType}}</td><td>{{.AssetIdentifier}}</td><td>{{if .Title}}{{deref .Title}}{{else if .ASNOrganization}}{{deref .ASNOrganization}}{{else if .CloudProvider}}{{deref .CloudProvider}}{{end}}</td></tr>
+  {{end}}
+</table>
+{{end}}
+
+{{if .TargetURLs}}
+<h2>Web Endpoints</h2>
+<table>
+  <tr><th>URL</th><th>Status</th><th>Title</th><th>Technologies</th></tr>
+  {{range .TargetURLs}}<tr><td>{{.URL}}</td><td>{{.StatusCode}}</td><td>{{.Title}}</td><td>{{join .Technologies ", "}}</td></tr>
+  {{end}}
+</table>
+{{if .IncludeScreenshots}}
+<h2>Screenshots</h2>
+{{range .TargetURLs}}{{if .Screenshot}}
+<div class="screenshot-block">
+  <h3>{{.URL}}</h3>
+  <img class="screenshot" alt="Screenshot of {{.URL}}" src="{{screenshotURI .Screenshot}}">
+</div>
+{{end}}{{end}}
+{{end}}
+{{end}}
+</body>
+</html>
+`
@@ -0,0 +1,474 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	responseArchiveBodyLimit = 5 << 20
+	// Bodies at least this similar to the previous response are treated as the same page with
+	// rotating tokens, timestamps or nonces
+	meaningfulBodySimilarity = 0.9
+	bodyShingleSize          = 3
+	responseChangesPageSize  = 200
+)
+
+// Headers that differ on nearly every request and say nothing about what is deployed
+var volatileResponseHeaders = map[string]bool{
+	"Age":              true,
+	"Cf-Ray":           true,
+	"Content-Length":   true,
+	"Date":             true,
+	"Etag":             true,
+	"Expires":          true,
+	"Last-Modified":    true,
+	"Nel":              true,
+	"Report-To":        true,
+	"Server-Timing":    true,
+	"Set-Cookie":       true,
+	"Via":              true,
+	"X-Amz-Cf-Id":      true,
+	"X-Amz-Cf-Pop":     true,
+	"X-Amzn-Requestid": true,
+	"X-Amzn-Trace-Id":  true,
+	"X-Cache":          true,
+	"X-Cache-Hits":     true,
+	"X-Correlation-Id": true,
+	"X-Request-Id":     true,
+	"X-Runtime":        true,
+	"X-Served-By":      true,
+	"X-Timer":          true,
+	"X-Trace-Id":       true,
+}
+
+// CSP nonces are regenerated on every response
+var cspNoncePattern = regexp.MustCompile(`'nonce-[^']*'`)
+
+// ResponseChange is one difference between a response and the previous one for the URL
+type ResponseChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// ResponseSnapshot is a distinct response of a URL. Identical responses on later runs only
+// extend LastSeenAt; bodies are stored once per hash, gzip compressed.
+type ResponseSnapshot struct {
+	ID                 string              `json:"id"`
+	ScopeTargetID      string              `json:"scope_target_id"`
+	URL                string              `json:"url"`
+	Source             string              `json:"source"`
+	StatusCode         int                 `json:"status_code"`
+	Title              string              `json:"title"`
+	ContentLength      int                 `json:"content_length"`
+	Headers            map[string][]string `json:"headers"`
+	BodySHA256         string              `json:"body_sha256"`
+	PreviousSnapshotID *string             `json:"previous_snapshot_id"`
+	BodySimilarity     *float64            `json:"body_similarity"`
+	Changes            []ResponseChange    `json:"changes"`
+	MeaningfulChange   bool                `json:"meaningful_change"`
+	TimesSeen          int                 `json:"times_seen"`
+	FirstSeenAt        time.Time           `json:"first_seen_at"`
+	LastSeenAt         time.Time           `json:"last_seen_at"`
+}
+
+// bodyShingles hashes every run of bodyShingleSize consecutive words of a body
+func bodyShingles(body []byte) map[uint64]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(string(body)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	shingles := make(map[uint64]struct{})
+	for i := 0; i+bodyShingleSize <= len(words) || (i == 0 && len(words) > 0); i++ {
+		end := i + bodyShingleSize
+		if end > len(words) {
+			end = len(words)
+		}
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:end], " ")))
+		shingles[h.Sum64()] = struct{}{}
+	}
+	return shingles
+}
+
+// bodySimilarity is the Jaccard similarity of the word shingles of two bodies, from 0 for
+// unrelated content to 1 for the same text
+func bodySimilarity(a, b []byte) float64 {
+	shinglesA, shinglesB := bodyShingles(a), bodyShingles(b)
+	if len(shinglesA) == 0 && len(shinglesB) == 0 {
+		if bytes.Equal(a, b) {
+			return 1
+		}
+		return 0
+	}
+	shared := 0
+	for shingle := range shinglesA {
+		if _, ok := shinglesB[shingle]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(shinglesA)+len(shinglesB)-shared)
+}
+
+// diffResponseHeaders lists headers added, removed or changed between two responses, ignoring
+// volatile ones
+func diffResponseHeaders(before, after map[string][]string) []ResponseChange {
+	names := make(map[string]bool)
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		if !volatileResponseHeaders[http.CanonicalHeaderKey(name)] {
+			sorted = append(sorted, name)
+		}
+	}
+	sort.Strings(sorted)
+
+	var changes []ResponseChange
+	for _, name := range sorted {
+		oldValue, hadOld := before[name]
+		newValue, hasNew := after[name]
+		oldJoined, newJoined := strings.Join(oldValue, ", "), strings.Join(newValue, ", ")
+		switch {
+		case hadOld && hasNew && cspNoncePattern.ReplaceAllString(oldJoined, "") == cspNoncePattern.ReplaceAllString(newJoined, ""):
+		case !hadOld:
+			changes = append(changes, ResponseChange{Field: "header:" + name, After: newJoined})
+		case !hasNew:
+			changes = append(changes, ResponseChange{Field: "header:" + name, Before: oldJoined})
+		case oldJoined != newJoined:
+			changes = append(changes, ResponseChange{Field: "header:" + name, Before: oldJoined, After: newJoined})
+		}
+	}
+	return changes
+}
+
+func compressBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// loadResponseBody returns the decompressed body stored under a hash
+func loadResponseBody(sha string) ([]byte, error) {
+	var compressed []byte
+	if err := dbPool.QueryRow(context.Background(),
+		`SELECT content FROM response_bodies WHERE sha256 = $1`, sha).Scan(&compressed); err != nil {
+		return nil, err
+	}
+	reader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// ArchiveResponse records a response of a URL in its history. A response identical to the
+// latest one, once volatile headers are ignored, only bumps that snapshot's last seen time;
+// anything else becomes a new snapshot listing what changed and whether the change matters.
+func ArchiveResponse(scopeTargetID, pageURL, source string, statusCode int, header http.Header, body []byte) error {
+	if len(body) > responseArchiveBodyLimit {
+		body = body[:responseArchiveBodyLimit]
+	}
+	sum := sha256.Sum256(body)
+	bodySHA := hex.EncodeToString(sum[:])
+	title := extractTitle(SanitizeResponse(body))
+	headers := map[string][]string(header.Clone())
+	if headers == nil {
+		headers = map[string][]string{}
+	}
+
+	var previous struct {
+		id         string
+		statusCode int
+		title      string
+		headers    map[string][]string
+		bodySHA    string
+	}
+	var previousHeaders []byte
+	err := dbPool.QueryRow(context.Background(), `
+		SELECT id, status_code, COALESCE(title, ''), headers, body_sha256
+		FROM response_snapshots
+		WHERE scope_target_id = $1 AND url = $2
+		ORDER BY first_seen_at DESC
+		LIMIT 1`, scopeTargetID, pageURL).Scan(
+		&previous.id, &previous.statusCode, &previous.title, &previousHeaders, &previous.bodySHA)
+	hasPrevious := err == nil
+	if err != nil && err != pgx.ErrNoRows {
+		return fmt.Errorf("failed to get previous response: %v", err)
+	}
+	json.Unmarshal(previousHeaders, &previous.headers)
+
+	changes := []ResponseChange{}
+	meaningful := false
+	var similarity *float64
+	if hasPrevious {
+		if previous.statusCode != statusCode {
+			changes = append(changes, ResponseChange{Field: "status", Before: strconv.Itoa(previous.statusCode), After: strconv.Itoa(statusCode)})
+			meaningful = true
+		}
+		if previous.title != title {
+			changes = append(changes, ResponseChange{Field: "title", Before: previous.title, After: title})
+			meaningful = true
+		}
+		if headerChanges := diffResponseHeaders(previous.headers, headers); len(headerChanges) > 0 {
+			changes = append(changes, headerChanges...)
+			meaningful = true
+		}
+		if previous.bodySHA != bodySHA {
+			// Without the previous body there is nothing to compare against, so the similarity
+			// stays unknown rather than counting as a rewrite
+			if previousBody, err := loadResponseBody(previous.bodySHA); err == nil {
+				score := bodySimilarity(previousBody, body)
+				similarity = &score
+				if score < meaningfulBodySimilarity {
+					meaningful = true
+				}
+			} else {
+				log.Printf("[RESPONSE ARCHIVE] [WARN] Previous body %s for %s is missing: %v", previous.bodySHA, pageURL, err)
+			}
+			changes = append(changes, ResponseChange{Field: "body", Before: previous.bodySHA, After: bodySHA})
+		}
+
+		// Same response as last time, nothing to store
+		if len(changes) == 0 {
+			_, err := dbPool.Exec(context.Background(),
+				`UPDATE response_snapshots SET last_seen_at = NOW(), times_seen = times_seen + 1 WHERE id = $1`,
+				previous.id)
+			return err
+		}
+	}
+
+	compressed, err := compressBody(body)
+	if err != nil {
+		return fmt.Errorf("failed to compress body: %v", err)
+	}
+	headersJSON, _ := json.Marshal(headers)
+	changesJSON, _ := json.Marshal(changes)
+	var previousID *string
+	if hasPrevious {
+		previousID = &previous.id
+	}
+
+	tx, err := dbPool.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	batch := &pgx.Batch{}
+	batch.Queue(`
+		INSERT INTO response_bodies (sha256, size_bytes, compressed_size, content)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (sha256) DO NOTHING`,
+		bodySHA, len(body), len(compressed), compressed)
+	batch.Queue(`
+		INSERT INTO response_snapshots
+			(scope_target_id, url, source, status_code, title, content_length, headers, body_sha256,
+			 previous_snapshot_id, body_similarity, changes, meaningful_change)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, $10, $11, $12)`,
+		scopeTargetID, pageURL, source, statusCode, title, len(body), headersJSON, bodySHA,
+		previousID, similarity, changesJSON, meaningful)
+	if err := tx.SendBatch(context.Background(), batch).Close(); err != nil {
+		return fmt.Errorf("failed to store response: %v", err)
+	}
+	if err := tx.Commit(context.Background()); err != nil {
+		return err
+	}
+
+	if meaningful {
+		log.Printf("[RESPONSE ARCHIVE] [INFO] %s changed: %d differences", pageURL, len(changes))
+	}
+	return nil
+}
+
+// pruneResponseBodies deletes bodies no snapshot refers to anymore
+func pruneResponseBodies() {
+	_, err := dbPool.Exec(context.Background(), `
+		DELETE FROM response_bodies b
+		WHERE NOT EXISTS (SELECT 1 FROM response_snapshots s WHERE s.body_sha256 = b.sha256)`)
+	if err != nil {
+		log.Printf("[RESPONSE ARCHIVE] [ERROR] Failed to prune response bodies: %v", err)
+	}
+}
+
+const responseSnapshotColumns = `id, scope_target_id, url, source, status_code, COALESCE(title, ''), content_length,
+	headers, body_sha256, previous_snapshot_id, body_similarity, changes, meaningful_change, times_seen,
+	first_seen_at, last_seen_at`
+
+func scanResponseSnapshot(row interface{ Scan(...interface{}) error }) (ResponseSnapshot, error) {
+	var snapshot ResponseSnapshot
+	var headers, changes []byte
+	err := row.Scan(
+		&snapshot.ID,
+		&snapshot.ScopeTargetID,
+		&snapshot.URL,
+		&snapshot.Source,
+		&snapshot.StatusCode,
+		&snapshot.Title,
+		&snapshot.ContentLength,
+		&headers,
+		&snapshot.BodySHA256,
+		&snapshot.PreviousSnapshotID,
+		&snapshot.BodySimilarity,
+		&changes,
+		&snapshot.MeaningfulChange,
+		&snapshot.TimesSeen,
+		&snapshot.FirstSeenAt,
+		&snapshot.LastSeenAt,
+	)
+	snapshot.Headers = map[string][]string{}
+	json.Unmarshal(headers, &snapshot.Headers)
+	snapshot.Changes = []ResponseChange{}
+	json.Unmarshal(changes, &snapshot.Changes)
+	return snapshot, err
+}
+
+func queryResponseSnapshots(query string, args ...interface{}) ([]ResponseSnapshot, error) {
+	rows, err := dbPool.Query(context.Background(), query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snapshots := []ResponseSnapshot{}
+	for rows.Next() {
+		snapshot, err := scanResponseSnapshot(rows)
+		if err != nil {
+			log.Printf("[RESPONSE ARCHIVE] [ERROR] Failed to scan snapshot: %v", err)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	return snapshots, rows.Err()
+}
+
+// GetResponseTimeline returns the distinct responses of one URL, newest first
+func GetResponseTimeline(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+	pageURL := r.URL.Query().Get("url")
+	if pageURL == "" {
+		http.Error(w, "`url` query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	snapshots, err := queryResponseSnapshots(
+		`SELECT `+responseSnapshotColumns+` FROM response_snapshots
+		 WHERE scope_target_id = $1 AND url = $2 ORDER BY first_seen_at DESC`,
+		scopeTargetID, pageURL)
+	if err != nil {
+		log.Printf("[RESPONSE ARCHIVE] [ERROR] Failed to get timeline for %s: %v", pageURL, err)
+		http.Error(w, "Failed to get response timeline", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshots)
+}
+
+// GetResponseChanges lists the responses of a scope target that differ from the previous one
+// for their URL, newest first. Only meaningful changes are listed unless all=true; since
+// (RFC 3339) limits them to recent runs.
+func GetResponseChanges(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	query := `SELECT ` + responseSnapshotColumns + ` FROM response_snapshots
+		WHERE scope_target_id = $1 AND previous_snapshot_id IS NOT NULL`
+	args := []interface{}{scopeTargetID}
+	if r.URL.Query().Get("all") != "true" {
+		query += ` AND meaningful_change = true`
+	}
+	if since := r.URL.Query().Get("since"); since != "" {
+		sinceTime, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			http.Error(w, "Invalid `since`. Use an RFC 3339 timestamp.", http.StatusBadRequest)
+			return
+		}
+		args = append(args, sinceTime)
+		query += ` AND first_seen_at >= $2`
+	}
+	query += ` ORDER BY first_seen_at DESC LIMIT ` + strconv.Itoa(responseChangesPageSize)
+
+	snapshots, err := queryResponseSnapshots(query, args...)
+	if err != nil {
+		log.Printf("[RESPONSE ARCHIVE] [ERROR] Failed to get changes: %v", err)
+		http.Error(w, "Failed to get response changes", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snapshots)
+}
+
+// GetResponseSnapshotBody serves the archived body of a snapshot as plain text, never with
+// its original content type, so archived pages cannot run scripts in the UI's origin
+func GetResponseSnapshotBody(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(id); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	var bodySHA string
+	err := dbPool.QueryRow(context.Background(),
+		`SELECT body_sha256 FROM response_snapshots WHERE id = $1`, id).Scan(&bodySHA)
+	if err == pgx.ErrNoRows {
+		http.Error(w, "Snapshot not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "Failed to get snapshot", http.StatusInternalServerError)
+		return
+	}
+
+	body, err := loadResponseBody(bodySHA)
+	if err == pgx.ErrNoRows {
+		http.Error(w, "Body not archived", http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Printf("[RESPONSE ARCHIVE] [ERROR] Failed to read body %s: %v", bodySHA, err)
+		http.Error(w, "Failed to read body", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("ETag", `"`+bodySHA+`"`)
+	w.Write(body)
+}
@@ -104,6 +104,8 @@ func DeleteScopeTarget(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
+	DeleteScreenshotBlobs(id)
+	pruneResponseBodies()
 
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(map[string]string{"message": "Request deleted successfully"})
@@ -0,0 +1,595 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"log"
+	"math"
+	"math/bits"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	thumbnailWidth  = 320
+	thumbnailHeight = 200
+	// Perceptual hashes this many bits apart or fewer are treated as the same page
+	defaultScreenshotSimilarity = 8
+	inlineScreenshotBatchSize   = 50
+)
+
+// Screenshot is a stored page capture. The image and its thumbnail live in the blob store,
+// only their keys and the perceptual hash are kept in the database.
+type Screenshot struct {
+	ID             string    `json:"id"`
+	ScopeTargetID  string    `json:"scope_target_id"`
+	URL            string    `json:"url"`
+	Title          string    `json:"title"`
+	Format         string    `json:"format"`
+	Width          int       `json:"width"`
+	Height         int       `json:"height"`
+	SizeBytes      int       `json:"size_bytes"`
+	SHA256         string    `json:"sha256"`
+	PHash          string    `json:"phash"`
+	FinalURL       string    `json:"final_url"`
+	PageTitle      string    `json:"page_title"`
+	StatusCode     int       `json:"status_code"`
+	ConsoleErrors  []string  `json:"console_errors"`
+	ViewportWidth  int       `json:"viewport_width"`
+	ViewportHeight int       `json:"viewport_height"`
+	FullPage       bool      `json:"full_page"`
+	ImageURL       string    `json:"image_url"`
+	ThumbnailURL   string    `json:"thumbnail_url"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	BlobKey        string    `json:"-"`
+	ThumbnailKey   string    `json:"-"`
+}
+
+// ScreenshotCluster is a group of visually similar screenshots
+type ScreenshotCluster struct {
+	PHash       string       `json:"phash"`
+	Count       int          `json:"count"`
+	Screenshots []Screenshot `json:"screenshots"`
+}
+
+// Identical captures share one blob, so writing a blob and recording it must not interleave
+// with another capture deciding that blob is unreferenced and deleting it. Locks are striped
+// by blob key.
+var screenshotBlobLocks [64]sync.Mutex
+
+func screenshotBlobLock(blobKey string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(blobKey))
+	return &screenshotBlobLocks[h.Sum32()%uint32(len(screenshotBlobLocks))]
+}
+
+// screenshotLinks returns the API paths serving a screenshot and its thumbnail
+func screenshotLinks(id string) (string, string) {
+	return "/screenshots/" + id, "/screenshots/" + id + "/thumbnail"
+}
+
+// grayscaleGrid averages the luminance of img into a size x size grid
+func grayscaleGrid(img image.Image, bounds image.Rectangle, size int) [][]float64 {
+	grid := make([][]float64, size)
+	counts := make([][]int, size)
+	for i := range grid {
+		grid[i] = make([]float64, size)
+		counts[i] = make([]int, size)
+	}
+	width, height := bounds.Dx(), bounds.Dy()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := (y - bounds.Min.Y) * size / height
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			col := (x - bounds.Min.X) * size / width
+			r, g, b, _ := img.At(x, y).RGBA()
+			grid[row][col] += 0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)
+			counts[row][col]++
+		}
+	}
+	for i := range grid {
+		for j := range grid[i] {
+			if counts[i][j] > 0 {
+				grid[i][j] /= float64(counts[i][j])
+			}
+		}
+	}
+	return grid
+}
+
+// perceptualHash is the 64-bit DCT pHash of an image: the low frequencies of a 32x32 grayscale
+// reduction, each bit set when its coefficient is above the median. Re-rendered pages with the
+// same layout land a few bits apart even when text, dates or ads differ.
+func perceptualHash(img image.Image) uint64 {
+	const size, low = 32, 8
+	grid := grayscaleGrid(img, img.Bounds(), size)
+
+	coefficients := make([]float64, 0, low*low)
+	for u := 0; u < low; u++ {
+		for v := 0; v < low; v++ {
+			var sum float64
+			for x := 0; x < size; x++ {
+				for y := 0; y < size; y++ {
+					sum += grid[x][y] *
+						math.Cos(float64(2*x+1)*float64(u)*math.Pi/(2*size)) *
+						math.Cos(float64(2*y+1)*float64(v)*math.Pi/(2*size))
+				}
+			}
+			coefficients = append(coefficients, sum)
+		}
+	}
+
+	// The DC term only measures overall brightness so it is left out of the median
+	sorted := append([]float64(nil), coefficients[1:]...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for i, coefficient := range coefficients {
+		if coefficient > median {
+			hash |= 1 << uint(63-i)
+		}
+	}
+	return hash
+}
+
+// screenshotThumbnail crops the top of a capture to the thumbnail aspect ratio, so full-page
+// captures show what is above the fold, and scales it down with area averaging
+func screenshotThumbnail(img image.Image) ([]byte, error) {
+	bounds := img.Bounds()
+	cropHeight := bounds.Dx() * thumbnailHeight / thumbnailWidth
+	if cropHeight > bounds.Dy() {
+		cropHeight = bounds.Dy()
+	}
+	source := image.Rect(bounds.Min.X, bounds.Min.Y, bounds.Max.X, bounds.Min.Y+cropHeight)
+
+	width, height := thumbnailWidth, thumbnailHeight
+	if source.Dx() < width {
+		width = source.Dx()
+	}
+	if height = source.Dy() * width / source.Dx(); height < 1 {
+		height = 1
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	for ty := 0; ty < height; ty++ {
+		y0 := source.Min.Y + ty*source.Dy()/height
+		y1 := source.Min.Y + (ty+1)*source.Dy()/height
+		for tx := 0; tx < width; tx++ {
+			x0 := source.Min.X + tx*source.Dx()/width
+			x1 := source.Min.X + (tx+1)*source.Dx()/width
+			var r, g, b, a, n uint64
+			for y := y0; y < y1 || y == y0; y++ {
+				for x := x0; x < x1 || x == x0; x++ {
+					pr, pg, pb, pa := img.At(x, y).RGBA()
+					r, g, b, a, n = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa), n+1
+				}
+			}
+			offset := thumbnail.PixOffset(tx, ty)
+			thumbnail.Pix[offset] = uint8(r / n >> 8)
+			thumbnail.Pix[offset+1] = uint8(g / n >> 8)
+			thumbnail.Pix[offset+2] = uint8(b / n >> 8)
+			thumbnail.Pix[offset+3] = uint8(a / n >> 8)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, thumbnail, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// StoreScreenshot writes a capture and its thumbnail to the blob store and records it for the
+// URL, replacing the previous capture. Identical captures share one blob.
+func StoreScreenshot(scopeTargetID, pageURL string, data []byte) (*Screenshot, error) {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode screenshot: %v", err)
+	}
+	thumbnail, err := screenshotThumbnail(img)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create thumbnail: %v", err)
+	}
+
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	screenshot := &Screenshot{
+		ScopeTargetID: scopeTargetID,
+		URL:           pageURL,
+		Format:        format,
+		Width:         img.Bounds().Dx(),
+		Height:        img.Bounds().Dy(),
+		SizeBytes:     len(data),
+		SHA256:        digest,
+		PHash:         fmt.Sprintf("%016x", perceptualHash(img)),
+		BlobKey:       fmt.Sprintf("screenshots/%s/%s.%s", scopeTargetID, digest, format),
+		ThumbnailKey:  fmt.Sprintf("screenshots/%s/%s_thumb.jpg", scopeTargetID, digest),
+	}
+
+	lock := screenshotBlobLock(screenshot.BlobKey)
+	lock.Lock()
+	store := blobStore()
+	if err := store.Put(screenshot.BlobKey, data); err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+	if err := store.Put(screenshot.ThumbnailKey, thumbnail); err != nil {
+		lock.Unlock()
+		return nil, err
+	}
+
+	var previousKey, previousThumbnailKey sql.NullString
+	dbPool.QueryRow(context.Background(),
+		`SELECT blob_key, thumbnail_key FROM screenshots WHERE scope_target_id = $1 AND url = $2`,
+		scopeTargetID, pageURL).Scan(&previousKey, &previousThumbnailKey)
+
+	err = dbPool.QueryRow(context.Background(), `
+		INSERT INTO screenshots
+			(scope_target_id, url, format, width, height, size_bytes, sha256, phash, blob_key, thumbnail_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (scope_target_id, url) DO UPDATE SET
+			format = EXCLUDED.format,
+			width = EXCLUDED.width,
+			height = EXCLUDED.height,
+			size_bytes = EXCLUDED.size_bytes,
+			sha256 = EXCLUDED.sha256,
+			phash = EXCLUDED.phash,
+			blob_key = EXCLUDED.blob_key,
+			thumbnail_key = EXCLUDED.thumbnail_key,
+			updated_at = NOW()
+		RETURNING id, created_at, updated_at`,
+		scopeTargetID, pageURL, screenshot.Format, screenshot.Width, screenshot.Height, screenshot.SizeBytes,
+		screenshot.SHA256, screenshot.PHash, screenshot.BlobKey, screenshot.ThumbnailKey,
+	).Scan(&screenshot.ID, &screenshot.CreatedAt, &screenshot.UpdatedAt)
+	lock.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to record screenshot: %v", err)
+	}
+	screenshot.ImageURL, screenshot.ThumbnailURL = screenshotLinks(screenshot.ID)
+
+	if previousKey.String != screenshot.BlobKey {
+		deleteUnreferencedBlobs(previousKey.String, previousThumbnailKey.String)
+	}
+	return screenshot, nil
+}
+
+// deleteUnreferencedBlobs removes a replaced screenshot and its thumbnail when no other capture
+// still points at them. It holds the blob's lock so a capture storing the same image again
+// can't record it between the check and the delete.
+func deleteUnreferencedBlobs(blobKey, thumbnailKey string) {
+	if blobKey == "" {
+		return
+	}
+	lock := screenshotBlobLock(blobKey)
+	lock.Lock()
+	defer lock.Unlock()
+
+	for _, key := range []string{blobKey, thumbnailKey} {
+		if key == "" {
+			continue
+		}
+		var referenced bool
+		err := dbPool.QueryRow(context.Background(),
+			`SELECT EXISTS(SELECT 1 FROM screenshots WHERE blob_key = $1 OR thumbnail_key = $1)`, key).Scan(&referenced)
+		if err != nil || referenced {
+			continue
+		}
+		if err := blobStore().Delete(key); err != nil {
+			log.Printf("[SCREENSHOTS] [WARN] Failed to delete blob %s: %v", key, err)
+		}
+	}
+}
+
+// DeleteScreenshotBlobs removes every stored capture of a scope target
+func DeleteScreenshotBlobs(scopeTargetID string) {
+	if err := blobStore().DeletePrefix("screenshots/" + scopeTargetID); err != nil {
+		log.Printf("[SCREENSHOTS] [WARN] Failed to delete screenshots of scope target %s: %v", scopeTargetID, err)
+	}
+}
+
+// loadScreenshotBase64 returns the capture of a URL base64 encoded for embedding, or "" when
+// there is none
+func loadScreenshotBase64(scopeTargetID, pageURL string) string {
+	var key string
+	err := dbPool.QueryRow(context.Background(),
+		`SELECT blob_key FROM screenshots WHERE scope_target_id = $1::uuid AND url = $2`,
+		scopeTargetID, pageURL).Scan(&key)
+	if err != nil {
+		return ""
+	}
+	data, err := blobStore().Get(key)
+	if err != nil {
+		log.Printf("[SCREENSHOTS] [WARN] Failed to read screenshot of %s: %v", pageURL, err)
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// MigrateInlineScreenshots moves screenshots still stored base64 encoded in
+// target_urls.screenshot into the blob store and clears the column
+func MigrateInlineScreenshots() {
+	migrated := 0
+	for {
+		rows, err := dbPool.Query(context.Background(), `
+			SELECT id, scope_target_id, url, screenshot FROM target_urls
+			WHERE screenshot IS NOT NULL AND screenshot <> ''
+			LIMIT $1`, inlineScreenshotBatchSize)
+		if err != nil {
+			log.Printf("[SCREENSHOTS] [ERROR] Failed to query inline screenshots: %v", err)
+			return
+		}
+
+		type inlineScreenshot struct{ id, scopeTargetID, url, data string }
+		var batch []inlineScreenshot
+		for rows.Next() {
+			var s inlineScreenshot
+			if err := rows.Scan(&s.id, &s.scopeTargetID, &s.url, &s.data); err == nil {
+				batch = append(batch, s)
+			}
+		}
+		rows.Close()
+		if len(batch) == 0 {
+			break
+		}
+
+		for _, s := range batch {
+			if data, err := base64.StdEncoding.DecodeString(s.data); err != nil {
+				log.Printf("[SCREENSHOTS] [WARN] Dropping undecodable screenshot of %s: %v", s.url, err)
+			} else if _, err := StoreScreenshot(s.scopeTargetID, s.url, data); err != nil {
+				log.Printf("[SCREENSHOTS] [WARN] Dropping screenshot of %s: %v", s.url, err)
+			} else {
+				migrated++
+			}
+			// Cleared whatever happened so a broken screenshot is not retried forever
+			if _, err := dbPool.Exec(context.Background(),
+				`UPDATE target_urls SET screenshot = NULL WHERE id = $1`, s.id); err != nil {
+				log.Printf("[SCREENSHOTS] [ERROR] Failed to clear inline screenshot of %s: %v", s.url, err)
+				return
+			}
+		}
+	}
+	if migrated > 0 {
+		log.Printf("[SCREENSHOTS] [INFO] Moved %d inline screenshots to the blob store", migrated)
+	}
+}
+
+const screenshotColumns = `s.id, s.scope_target_id, s.url, COALESCE(tu.title, ''), s.format, s.width, s.height,
+	s.size_bytes, s.sha256, s.phash, COALESCE(s.final_url, ''), COALESCE(s.page_title, ''),
+	COALESCE(s.status_code, 0), COALESCE(s.console_errors, '[]'::jsonb), COALESCE(s.viewport_width, 0),
+	COALESCE(s.viewport_height, 0), COALESCE(s.full_page, false), s.blob_key, s.thumbnail_key,
+	s.created_at, s.updated_at`
+
+const screenshotFrom = `FROM screenshots s
+	LEFT JOIN target_urls tu ON tu.scope_target_id = s.scope_target_id AND tu.url = s.url`
+
+func scanScreenshot(row interface{ Scan(...interface{}) error }) (Screenshot, error) {
+	var s Screenshot
+	var consoleErrors []byte
+	err := row.Scan(
+		&s.ID,
+		&s.ScopeTargetID,
+		&s.URL,
+		&s.Title,
+		&s.Format,
+		&s.Width,
+		&s.Height,
+		&s.SizeBytes,
+		&s.SHA256,
+		&s.PHash,
+		&s.FinalURL,
+		&s.PageTitle,
+		&s.StatusCode,
+		&consoleErrors,
+		&s.ViewportWidth,
+		&s.ViewportHeight,
+		&s.FullPage,
+		&s.BlobKey,
+		&s.ThumbnailKey,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	s.ConsoleErrors = []string{}
+	json.Unmarshal(consoleErrors, &s.ConsoleErrors)
+	s.ImageURL, s.ThumbnailURL = screenshotLinks(s.ID)
+	return s, err
+}
+
+func fetchScreenshots(scopeTargetID string) ([]Screenshot, error) {
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT `+screenshotColumns+` `+screenshotFrom+` WHERE s.scope_target_id = $1::uuid ORDER BY s.url ASC`,
+		scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	screenshots := []Screenshot{}
+	for rows.Next() {
+		s, err := scanScreenshot(rows)
+		if err != nil {
+			log.Printf("[SCREENSHOTS] [ERROR] Failed to scan screenshot: %v", err)
+			continue
+		}
+		screenshots = append(screenshots, s)
+	}
+	return screenshots, rows.Err()
+}
+
+// clusterScreenshots groups screenshots whose perceptual hashes are within threshold bits of
+// each other, transitively, keeping groups of at least minSize, largest first
+func clusterScreenshots(screenshots []Screenshot, threshold, minSize int) []ScreenshotCluster {
+	hashes := make([]uint64, len(screenshots))
+	for i, s := range screenshots {
+		hashes[i], _ = strconv.ParseUint(s.PHash, 16, 64)
+	}
+
+	parent := make([]int, len(screenshots))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i := range screenshots {
+		for j := i + 1; j < len(screenshots); j++ {
+			if bits.OnesCount64(hashes[i]^hashes[j]) <= threshold {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := make(map[int][]Screenshot)
+	var roots []int
+	for i, s := range screenshots {
+		root := find(i)
+		if _, exists := groups[root]; !exists {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], s)
+	}
+
+	clusters := []ScreenshotCluster{}
+	for _, root := range roots {
+		if len(groups[root]) >= minSize {
+			clusters = append(clusters, ScreenshotCluster{
+				PHash:       screenshots[root].PHash,
+				Count:       len(groups[root]),
+				Screenshots: groups[root],
+			})
+		}
+	}
+	sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].Count > clusters[j].Count })
+	return clusters
+}
+
+func GetScreenshots(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	screenshots, err := fetchScreenshots(scopeTargetID)
+	if err != nil {
+		log.Printf("[SCREENSHOTS] [ERROR] Failed to get screenshots: %v", err)
+		http.Error(w, "Failed to get screenshots", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(screenshots)
+}
+
+// GetScreenshotClusters groups a scope target's screenshots into visually similar pages.
+// threshold is the largest perceptual hash distance in bits treated as similar (default 8) and
+// min_size the smallest group returned (default 2, 1 lists unique pages too).
+func GetScreenshotClusters(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	threshold := defaultScreenshotSimilarity
+	if raw := r.URL.Query().Get("threshold"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 || value > 64 {
+			http.Error(w, "Invalid `threshold`. Use a number of bits between 0 and 64.", http.StatusBadRequest)
+			return
+		}
+		threshold = value
+	}
+	minSize := 2
+	if raw := r.URL.Query().Get("min_size"); raw != "" {
+		if size, err := strconv.Atoi(raw); err == nil && size > 0 {
+			minSize = size
+		}
+	}
+
+	screenshots, err := fetchScreenshots(scopeTargetID)
+	if err != nil {
+		log.Printf("[SCREENSHOTS] [ERROR] Failed to get screenshots: %v", err)
+		http.Error(w, "Failed to get screenshots", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(clusterScreenshots(screenshots, threshold, minSize))
+}
+
+func serveScreenshotBlob(w http.ResponseWriter, r *http.Request, thumbnail bool) {
+	id := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(id); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	screenshot, err := scanScreenshot(dbPool.QueryRow(context.Background(),
+		`SELECT `+screenshotColumns+` `+screenshotFrom+` WHERE s.id = $1`, id))
+	if err == pgx.ErrNoRows {
+		http.Error(w, "Screenshot not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Printf("[SCREENSHOTS] [ERROR] Failed to get screenshot %s: %v", id, err)
+		http.Error(w, "Failed to get screenshot", http.StatusInternalServerError)
+		return
+	}
+
+	key, contentType := screenshot.BlobKey, "image/"+screenshot.Format
+	if thumbnail {
+		key, contentType = screenshot.ThumbnailKey, "image/jpeg"
+	}
+	data, err := blobStore().Get(key)
+	if err == ErrBlobNotFound {
+		http.Error(w, "Screenshot file not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		log.Printf("[SCREENSHOTS] [ERROR] Failed to read blob %s: %v", key, err)
+		http.Error(w, "Failed to read screenshot", http.StatusInternalServerError)
+		return
+	}
+
+	// A new capture of the URL keeps the screenshot ID, so clients revalidate against the content hash
+	etag := `"` + screenshot.SHA256 + `"`
+	if thumbnail {
+		etag = `"` + screenshot.SHA256 + `-thumb"`
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(data)
+}
+
+func GetScreenshotImage(w http.ResponseWriter, r *http.Request) {
+	serveScreenshotBlob(w, r, false)
+}
+
+func GetScreenshotThumbnail(w http.ResponseWriter, r *http.Request) {
+	serveScreenshotBlob(w, r, true)
+}
@@ -1,16 +1,14 @@
 package utils
 
 import (
-	"bytes"
 	"context"
 	"database/sql"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
-	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -89,16 +87,13 @@ func RunNucleiScreenshotScan(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// ExecuteAndParseNucleiScreenshotScan runs the Nuclei screenshot scan and processes its results
+// ExecuteAndParseNucleiScreenshotScan captures the httpx results of a scope target with the
+// headless Chrome worker. It keeps the nuclei_screenshots scan records the UI and auto scan
+// poll; the nuclei headless template it used to run is no longer involved.
 func ExecuteAndParseNucleiScreenshotScan(scanID, domain string) {
-	log.Printf("[INFO] Starting Nuclei screenshot scan execution for scan ID: %s", scanID)
+	log.Printf("[INFO] Starting screenshot scan execution for scan ID: %s", scanID)
 	startTime := time.Now()
 
-	// Get custom HTTP settings
-	customUserAgent, customHeader := GetCustomHTTPSettings()
-	log.Printf("[DEBUG] Custom User Agent: %s", customUserAgent)
-	log.Printf("[DEBUG] Custom Header: %s", customHeader)
-
 	// Get scope target ID and latest httpx results
 	var scopeTargetID string
 	err := dbPool.QueryRow(context.Background(),
@@ -151,142 +146,84 @@ func ExecuteAndParseNucleiScreenshotScan(scanID, domain string) {
 		return
 	}
 
-	// Build base command
-	cmdArgs := []string{
-		"docker", "exec", "ars0n-framework-v2-nuclei-1",
-		"bash", "-c",
-	}
-
-	nucleiCmd := fmt.Sprintf("echo '%s' > /urls.txt && nuclei -t /root/nuclei-templates/headless/screenshot.yaml -list /urls.txt -headless", strings.Join(urls, "\n"))
-
-	// Add custom headers if specified
-	if customHeader != "" {
-		nucleiCmd += fmt.Sprintf(" -H '%s'", customHeader)
-	}
-
-	// Add custom user agent via header if specified
-	if customUserAgent != "" {
-		nucleiCmd += fmt.Sprintf(" -H 'User-Agent: %s'", customUserAgent)
-	}
-
-	cmdArgs = append(cmdArgs, nucleiCmd)
-	cmd := exec.Command(cmdArgs[0], cmdArgs[1:]...)
-	log.Printf("[INFO] Prepared Nuclei command for scan ID %s: %s", scanID, cmd.String())
-
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	// Execute the command
-	log.Printf("[INFO] Executing Nuclei command for scan ID: %s", scanID)
-	err = cmd.Run()
-	if err != nil {
-		log.Printf("[ERROR] Nuclei command failed for scan ID %s: %v", scanID, err)
-		UpdateNucleiScreenshotScanStatus(
-			scanID,
-			"error",
-			stdout.String(),
-			fmt.Sprintf("Command failed: %v\nStderr: %s", err, stderr.String()),
-			cmd.String(),
-			time.Since(startTime).String(),
-		)
-		return
-	}
-
-	log.Printf("[INFO] Successfully completed Nuclei scan for scan ID: %s", scanID)
-	log.Printf("[INFO] Scan duration for %s: %s", scanID, time.Since(startTime).String())
-
-	// Read and process screenshot files
-	var results []string
-	screenshotFiles, err := exec.Command("docker", "exec", "ars0n-framework-v2-nuclei-1", "ls", "/app/screenshots/").Output()
-	if err != nil {
-		log.Printf("[ERROR] Failed to list screenshot files for scan ID %s: %v", scanID, err)
-		UpdateNucleiScreenshotScanStatus(
-			scanID,
-			"error",
-			"",
-			fmt.Sprintf("Failed to list screenshot files: %v", err),
-			cmd.String(),
-			time.Since(startTime).String(),
-		)
-		return
-	}
-
-	log.Printf("[DEBUG] Found screenshot files: %s", string(screenshotFiles))
-	fileList := strings.Split(string(screenshotFiles), "\n")
-	log.Printf("[DEBUG] Processing %d screenshot files", len(fileList))
-
-	for _, file := range fileList {
-		if file == "" || !strings.HasSuffix(file, ".png") {
-			log.Printf("[DEBUG] Skipping invalid file: %s", file)
-			continue
-		}
-
-		log.Printf("[DEBUG] Processing screenshot file: %s", file)
-
-		// Read the screenshot file
-		imgData, err := exec.Command("docker", "exec", "ars0n-framework-v2-nuclei-1", "cat", "/app/screenshots/"+file).Output()
-		if err != nil {
-			log.Printf("[WARN] Failed to read screenshot file %s: %v", file, err)
-			continue
-		}
-		log.Printf("[DEBUG] Read screenshot file, size: %d bytes", len(imgData))
-
-		// Convert the URL-safe filename back to a real URL
-		url := strings.TrimSuffix(file, ".png")
-		url = strings.ReplaceAll(url, "__", "://")
-		url = strings.ReplaceAll(url, "_", ".")
-
-		// Normalize the URL
-		url = NormalizeURL(url)
-
-		// Skip URLs with encoded characters that are nuclei test paths
-		if strings.Contains(url, "%") {
-			log.Printf("[DEBUG] Skipping nuclei test URL with encoded characters: %s", url)
-			continue
+	// Capture the URLs with the headless Chrome worker
+	options := ScreenshotOptions{}.withDefaults()
+	command := fmt.Sprintf("headless Chrome capture (viewport %dx%d, timeout %ds, concurrency %d)",
+		options.ViewportWidth, options.ViewportHeight, options.TimeoutSeconds, options.Concurrency)
+	log.Printf("[INFO] Capturing %d URLs for scan ID %s with %s via %s", len(urls), scanID, command, chromeDevToolsURL())
+
+	var mu sync.Mutex
+	var results, failures []string
+	err = CapturePages(urls, options, func(capture PageCapture) {
+		var screenshot *Screenshot
+		if capture.Error == "" {
+			var storeErr error
+			if screenshot, storeErr = UpdateTargetURLFromScreenshot(scopeTargetID, capture.URL, capture.Image); storeErr != nil {
+				capture.Error = storeErr.Error()
+			} else if storeErr = recordPageDetails(screenshot, capture, options); storeErr != nil {
+				log.Printf("[WARN] %v", storeErr)
+			}
 		}
 
-		log.Printf("[DEBUG] Processing screenshot for URL: %s", url)
-
-		// Update target URL with screenshot
-		screenshot := base64.StdEncoding.EncodeToString(imgData)
-		log.Printf("[DEBUG] Base64 encoded screenshot size: %d bytes", len(screenshot))
-		if err := UpdateTargetURLFromScreenshot(url, screenshot); err != nil {
-			log.Printf("[WARN] Failed to update target URL screenshot for %s: %v", url, err)
+		mu.Lock()
+		defer mu.Unlock()
+		if capture.Error != "" {
+			log.Printf("[WARN] Failed to capture screenshot for %s: %s", capture.URL, capture.Error)
+			failures = append(failures, fmt.Sprintf("%s: %s", capture.URL, capture.Error))
+			return
 		}
 
 		// Create the result object for the scan results
 		result := struct {
-			Matched    string `json:"matched"`
-			Screenshot string `json:"screenshot"`
-			Timestamp  string `json:"timestamp"`
+			Matched       string   `json:"matched"`
+			FinalURL      string   `json:"final_url"`
+			Title         string   `json:"title"`
+			StatusCode    int      `json:"status_code"`
+			ConsoleErrors []string `json:"console_errors"`
+			ScreenshotID  string   `json:"screenshot_id"`
+			ImageURL      string   `json:"image_url"`
+			ThumbnailURL  string   `json:"thumbnail_url"`
+			PHash         string   `json:"phash"`
+			Timestamp     string   `json:"timestamp"`
 		}{
-			Matched:    url,
-			Screenshot: screenshot,
-			Timestamp:  time.Now().Format(time.RFC3339),
+			Matched:       capture.URL,
+			FinalURL:      capture.FinalURL,
+			Title:         capture.Title,
+			StatusCode:    capture.StatusCode,
+			ConsoleErrors: capture.ConsoleErrors,
+			ScreenshotID:  screenshot.ID,
+			ImageURL:      screenshot.ImageURL,
+			ThumbnailURL:  screenshot.ThumbnailURL,
+			PHash:         screenshot.PHash,
+			Timestamp:     time.Now().Format(time.RFC3339),
 		}
 
 		// Convert to JSON and add to results
 		jsonResult, err := json.Marshal(result)
 		if err != nil {
-			log.Printf("[WARN] Failed to marshal screenshot result for %s: %v", url, err)
-			continue
+			log.Printf("[WARN] Failed to marshal screenshot result for %s: %v", capture.URL, err)
+			return
 		}
 		results = append(results, string(jsonResult))
+	})
+	if err != nil {
+		log.Printf("[ERROR] Screenshot capture failed for scan ID %s: %v", scanID, err)
+		UpdateNucleiScreenshotScanStatus(scanID, "error", "", err.Error(), command, time.Since(startTime).String())
+		return
 	}
 
+	log.Printf("[INFO] Successfully completed screenshot scan for scan ID: %s", scanID)
+	log.Printf("[INFO] Scan duration for %s: %s", scanID, time.Since(startTime).String())
+
 	// Update scan status with results
 	UpdateNucleiScreenshotScanStatus(
 		scanID,
 		"success",
 		strings.Join(results, "\n"),
-		stderr.String(),
-		cmd.String(),
+		strings.Join(failures, "\n"),
+		command,
 		time.Since(startTime).String(),
 	)
-
-	// Clean up screenshots in the container
-	exec.Command("docker", "exec", "ars0n-framework-v2-nuclei-1", "rm", "-rf", "/app/screenshots/*").Run()
 }
 
 // UpdateNucleiScreenshotScanStatus updates the status of a Nuclei screenshot scan
@@ -412,10 +349,10 @@ func GetNucleiScreenshotScansForScopeTarget(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(scans)
 }
 
-// UpdateTargetURLFromScreenshot updates the screenshot for a target URL
-func UpdateTargetURLFromScreenshot(url, screenshot string) error {
+// UpdateTargetURLFromScreenshot stores the screenshot of a scope target's URL in the blob store
+func UpdateTargetURLFromScreenshot(scopeTargetID, url string, imgData []byte) (*Screenshot, error) {
 	log.Printf("[DEBUG] Updating screenshot for URL: %s", url)
-	log.Printf("[DEBUG] Screenshot data length: %d", len(screenshot))
+	log.Printf("[DEBUG] Screenshot data length: %d", len(imgData))
 
 	// Normalize the URL
 	url = NormalizeURL(url)
@@ -424,33 +361,31 @@ func UpdateTargetURLFromScreenshot(url, screenshot string) error {
 	// Check if target URL exists
 	var existingID string
 	err := dbPool.QueryRow(context.Background(),
-		`SELECT id FROM target_urls WHERE url = $1`,
-		url).Scan(&existingID)
+		`SELECT id FROM target_urls WHERE url = $1 AND scope_target_id = $2`,
+		url, scopeTargetID).Scan(&existingID)
 
 	if err == pgx.ErrNoRows {
 		log.Printf("[WARN] No target URL found for %s, cannot update screenshot", url)
-		return fmt.Errorf("no target URL found for %s", url)
+		return nil, fmt.Errorf("no target URL found for %s", url)
 	} else if err != nil {
 		log.Printf("[ERROR] Error checking for existing target URL: %v", err)
-		return fmt.Errorf("error checking for existing target URL: %v", err)
+		return nil, fmt.Errorf("error checking for existing target URL: %v", err)
 	}
 
 	log.Printf("[DEBUG] Found existing target URL with ID: %s", existingID)
 
-	// Update the screenshot
-	result, err := dbPool.Exec(context.Background(),
-		`UPDATE target_urls SET 
-			screenshot = $1,
-			updated_at = NOW()
-		WHERE id = $2`,
-		screenshot, existingID)
-
+	screenshot, err := StoreScreenshot(scopeTargetID, url, imgData)
 	if err != nil {
-		log.Printf("[ERROR] Failed to update target URL screenshot: %v", err)
-		return fmt.Errorf("failed to update target URL screenshot: %v", err)
+		log.Printf("[ERROR] Failed to store target URL screenshot: %v", err)
+		return nil, fmt.Errorf("failed to store target URL screenshot: %v", err)
+	}
+
+	if _, err := dbPool.Exec(context.Background(),
+		`UPDATE target_urls SET screenshot = NULL, updated_at = NOW() WHERE id = $1`,
+		existingID); err != nil {
+		log.Printf("[WARN] Failed to update target URL %s: %v", existingID, err)
 	}
 
-	rowsAffected := result.RowsAffected()
-	log.Printf("[DEBUG] Successfully updated screenshot for URL: %s (rows affected: %d)", url, rowsAffected)
-	return nil
+	log.Printf("[DEBUG] Successfully stored screenshot %s for URL: %s (phash %s)", screenshot.ID, url, screenshot.PHash)
+	return screenshot, nil
 }
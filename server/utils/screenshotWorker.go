@@ -0,0 +1,569 @@
+package utils
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	defaultViewportWidth     = 1280
+	defaultViewportHeight    = 800
+	defaultScreenshotTimeout = 30
+	defaultScreenshotWorkers = 5
+	maxScreenshotWorkers     = 20
+	maxViewportDimension     = 4096
+	// Chrome cannot capture textures taller than this in one screenshot
+	maxFullPageHeight       = 16384
+	screenshotSettleDelay   = 500 * time.Millisecond
+	maxConsoleErrorsPerPage = 50
+)
+
+// ScreenshotOptions control how pages are captured
+type ScreenshotOptions struct {
+	ViewportWidth  int  `json:"viewport_width"`
+	ViewportHeight int  `json:"viewport_height"`
+	FullPage       bool `json:"full_page"`
+	TimeoutSeconds int  `json:"timeout_seconds"`
+	Concurrency    int  `json:"concurrency"`
+}
+
+// withDefaults fills unset options and clamps the rest to sane bounds
+func (o ScreenshotOptions) withDefaults() ScreenshotOptions {
+	if o.ViewportWidth <= 0 {
+		o.ViewportWidth = defaultViewportWidth
+	}
+	if o.ViewportHeight <= 0 {
+		o.ViewportHeight = defaultViewportHeight
+	}
+	if o.ViewportWidth > maxViewportDimension {
+		o.ViewportWidth = maxViewportDimension
+	}
+	if o.ViewportHeight > maxViewportDimension {
+		o.ViewportHeight = maxViewportDimension
+	}
+	if o.TimeoutSeconds <= 0 {
+		o.TimeoutSeconds = defaultScreenshotTimeout
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = defaultScreenshotWorkers
+	}
+	if o.Concurrency > maxScreenshotWorkers {
+		o.Concurrency = maxScreenshotWorkers
+	}
+	return o
+}
+
+// PageCapture is what a headless Chrome visit to a URL produced
+type PageCapture struct {
+	URL           string   `json:"url"`
+	FinalURL      string   `json:"final_url"`
+	Title         string   `json:"title"`
+	StatusCode    int      `json:"status_code"`
+	ConsoleErrors []string `json:"console_errors"`
+	Image         []byte   `json:"-"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// pageEvents collects what a page reports while it loads
+type pageEvents struct {
+	mu            sync.Mutex
+	frameID       string
+	statusCode    int
+	consoleErrors []string
+	loaded        chan struct{}
+	loadOnce      sync.Once
+}
+
+func (e *pageEvents) addConsoleError(message string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if len(e.consoleErrors) < maxConsoleErrorsPerPage {
+		e.consoleErrors = append(e.consoleErrors, message)
+	}
+}
+
+func (e *pageEvents) handle(method string, params json.RawMessage) {
+	switch method {
+	case "Page.loadEventFired":
+		e.loadOnce.Do(func() { close(e.loaded) })
+	case "Network.responseReceived":
+		var event struct {
+			Type     string `json:"type"`
+			FrameID  string `json:"frameId"`
+			Response struct {
+				Status int `json:"status"`
+			} `json:"response"`
+		}
+		if json.Unmarshal(params, &event) == nil && event.Type == "Document" {
+			e.mu.Lock()
+			// Redirects are followed inside Chrome, the last document response of the main frame wins
+			if e.frameID == "" || event.FrameID == e.frameID {
+				e.statusCode = event.Response.Status
+			}
+			e.mu.Unlock()
+		}
+	case "Runtime.consoleAPICalled":
+		var event struct {
+			Type string `json:"type"`
+			Args []struct {
+				Value       interface{} `json:"value"`
+				Description string      `json:"description"`
+			} `json:"args"`
+		}
+		if json.Unmarshal(params, &event) == nil && (event.Type == "error" || event.Type == "assert") {
+			var parts []string
+			for _, arg := range event.Args {
+				if arg.Description != "" {
+					parts = append(parts, arg.Description)
+				} else if arg.Value != nil {
+					parts = append(parts, fmt.Sprint(arg.Value))
+				}
+			}
+			e.addConsoleError(strings.Join(parts, " "))
+		}
+	case "Runtime.exceptionThrown":
+		var event struct {
+			ExceptionDetails struct {
+				Text      string `json:"text"`
+				Exception struct {
+					Description string `json:"description"`
+				} `json:"exception"`
+			} `json:"exceptionDetails"`
+		}
+		if json.Unmarshal(params, &event) == nil {
+			message := event.ExceptionDetails.Exception.Description
+			if message == "" {
+				message = event.ExceptionDetails.Text
+			}
+			e.addConsoleError(message)
+		}
+	case "Log.entryAdded":
+		var event struct {
+			Entry struct {
+				Level string `json:"level"`
+				Text  string `json:"text"`
+				URL   string `json:"url"`
+			} `json:"entry"`
+		}
+		if json.Unmarshal(params, &event) == nil && event.Entry.Level == "error" {
+			message := event.Entry.Text
+			if event.Entry.URL != "" {
+				message += " (" + event.Entry.URL + ")"
+			}
+			e.addConsoleError(message)
+		}
+	}
+}
+
+// capturePage loads a URL in a fresh incognito browser context and screenshots it
+func capturePage(browser *cdpBrowser, pageURL string, options ScreenshotOptions, userAgent, customHeader, proxyURL string) (capture PageCapture) {
+	capture = PageCapture{URL: pageURL, ConsoleErrors: []string{}}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(options.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	fail := func(err error) PageCapture {
+		capture.Error = err.Error()
+		return capture
+	}
+
+	contextParams := map[string]interface{}{"disposeOnDetach": true}
+	if proxyURL != "" {
+		contextParams["proxyServer"] = proxyURL
+	}
+	var browserContext struct {
+		BrowserContextID string `json:"browserContextId"`
+	}
+	if err := browser.call(ctx, "", "Target.createBrowserContext", contextParams, &browserContext); err != nil {
+		return fail(err)
+	}
+	defer browser.call(context.Background(), "", "Target.disposeBrowserContext",
+		map[string]string{"browserContextId": browserContext.BrowserContextID}, nil)
+
+	var target struct {
+		TargetID string `json:"targetId"`
+	}
+	if err := browser.call(ctx, "", "Target.createTarget", map[string]interface{}{
+		"url":              "about:blank",
+		"browserContextId": browserContext.BrowserContextID,
+	}, &target); err != nil {
+		return fail(err)
+	}
+	var attached struct {
+		SessionID string `json:"sessionId"`
+	}
+	if err := browser.call(ctx, "", "Target.attachToTarget", map[string]interface{}{
+		"targetId": target.TargetID,
+		"flatten":  true,
+	}, &attached); err != nil {
+		return fail(err)
+	}
+	session := attached.SessionID
+
+	events := &pageEvents{loaded: make(chan struct{})}
+	browser.handle(session, events.handle)
+	defer browser.handle(session, nil)
+
+	headers := map[string]string{}
+	if name, value, ok := strings.Cut(customHeader, ":"); ok && strings.TrimSpace(name) != "" {
+		headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	setup := []struct {
+		method string
+		params interface{}
+	}{
+		{"Page.enable", nil},
+		{"Runtime.enable", nil},
+		{"Log.enable", nil},
+		{"Network.enable", nil},
+		{"Security.setIgnoreCertificateErrors", map[string]bool{"ignore": true}},
+		{"Emulation.setDeviceMetricsOverride", map[string]interface{}{
+			"width":             options.ViewportWidth,
+			"height":            options.ViewportHeight,
+			"deviceScaleFactor": 1,
+			"mobile":            false,
+		}},
+		{"Network.setExtraHTTPHeaders", map[string]interface{}{"headers": headers}},
+	}
+	if userAgent != "" {
+		setup = append(setup, struct {
+			method string
+			params interface{}
+		}{"Network.setUserAgentOverride", map[string]string{"userAgent": userAgent}})
+	}
+	for _, step := range setup {
+		if err := browser.call(ctx, session, step.method, step.params, nil); err != nil {
+			return fail(err)
+		}
+	}
+
+	var navigation struct {
+		FrameID   string `json:"frameId"`
+		ErrorText string `json:"errorText"`
+	}
+	if err := browser.call(ctx, session, "Page.navigate", map[string]string{"url": pageURL}, &navigation); err != nil {
+		return fail(err)
+	}
+	if navigation.ErrorText != "" {
+		return fail(fmt.Errorf("navigation failed: %s", navigation.ErrorText))
+	}
+	events.mu.Lock()
+	events.frameID = navigation.FrameID
+	events.mu.Unlock()
+
+	// Pages that never fire load (long polling, hung third-party scripts) are captured as they
+	// are once most of the timeout has passed
+	loadDeadline := time.Duration(options.TimeoutSeconds) * time.Second * 3 / 4
+	select {
+	case <-events.loaded:
+		time.Sleep(screenshotSettleDelay)
+	case <-time.After(loadDeadline):
+		log.Printf("[SCREENSHOT] [DEBUG] Load event not fired for %s, capturing anyway", pageURL)
+	case <-ctx.Done():
+		return fail(ctx.Err())
+	}
+
+	var evaluation struct {
+		Result struct {
+			Value string `json:"value"`
+		} `json:"result"`
+	}
+	if err := browser.call(ctx, session, "Runtime.evaluate", map[string]interface{}{
+		"expression": `JSON.stringify({url: location.href, title: document.title,
+			height: Math.max(document.body ? document.body.scrollHeight : 0, document.documentElement.scrollHeight)})`,
+		"returnByValue": true,
+	}, &evaluation); err != nil {
+		return fail(err)
+	}
+	var page struct {
+		URL    string `json:"url"`
+		Title  string `json:"title"`
+		Height int    `json:"height"`
+	}
+	json.Unmarshal([]byte(evaluation.Result.Value), &page)
+	capture.FinalURL = page.URL
+	capture.Title = strings.TrimSpace(page.Title)
+
+	screenshotParams := map[string]interface{}{"format": "png"}
+	if options.FullPage && page.Height > options.ViewportHeight {
+		height := page.Height
+		if height > maxFullPageHeight {
+			height = maxFullPageHeight
+		}
+		screenshotParams["captureBeyondViewport"] = true
+		screenshotParams["clip"] = map[string]interface{}{
+			"x": 0, "y": 0, "width": options.ViewportWidth, "height": height, "scale": 1,
+		}
+	}
+	var screenshot struct {
+		Data string `json:"data"`
+	}
+	if err := browser.call(ctx, session, "Page.captureScreenshot", screenshotParams, &screenshot); err != nil {
+		return fail(err)
+	}
+	image, err := base64.StdEncoding.DecodeString(screenshot.Data)
+	if err != nil {
+		return fail(fmt.Errorf("invalid screenshot data: %v", err))
+	}
+	capture.Image = image
+
+	events.mu.Lock()
+	capture.StatusCode = events.statusCode
+	capture.ConsoleErrors = append(capture.ConsoleErrors, events.consoleErrors...)
+	events.mu.Unlock()
+	return capture
+}
+
+// CapturePages screenshots URLs in the headless Chrome container, options.Concurrency at a
+// time, calling onCapture as each page finishes. Requests carry the custom user agent and
+// header and go through the Burp proxy when it is enabled.
+func CapturePages(urls []string, options ScreenshotOptions, onCapture func(PageCapture)) error {
+	options = options.withDefaults()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	browser, err := connectChrome(ctx)
+	cancel()
+	if err != nil {
+		return err
+	}
+	defer browser.Close()
+
+	userAgent, customHeader := GetCustomHTTPSettings()
+	proxyURL := GetBurpProxyURL()
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, options.Concurrency)
+	for _, pageURL := range urls {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(pageURL string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			onCapture(capturePage(browser, pageURL, options, userAgent, customHeader, proxyURL))
+		}(pageURL)
+	}
+	wg.Wait()
+	return nil
+}
+
+// recordPageDetails stores what the page reported alongside its screenshot
+func recordPageDetails(screenshot *Screenshot, capture PageCapture, options ScreenshotOptions) error {
+	consoleErrors, _ := json.Marshal(capture.ConsoleErrors)
+	_, err := dbPool.Exec(context.Background(), `
+		UPDATE screenshots
+		SET final_url = NULLIF($1, ''), page_title = NULLIF($2, ''), status_code = NULLIF($3, 0),
+		    console_errors = $4, viewport_width = $5, viewport_height = $6, full_page = $7
+		WHERE id = $8`,
+		capture.FinalURL, capture.Title, capture.StatusCode, consoleErrors,
+		options.ViewportWidth, options.ViewportHeight, options.FullPage, screenshot.ID)
+	if err != nil {
+		return fmt.Errorf("failed to record page details for %s: %v", capture.URL, err)
+	}
+	screenshot.FinalURL = capture.FinalURL
+	screenshot.PageTitle = capture.Title
+	screenshot.StatusCode = capture.StatusCode
+	screenshot.ConsoleErrors = capture.ConsoleErrors
+	screenshot.ViewportWidth = options.ViewportWidth
+	screenshot.ViewportHeight = options.ViewportHeight
+	screenshot.FullPage = options.FullPage
+	return nil
+}
+
+// ScreenshotScan captures the live target URLs of a scope target
+type ScreenshotScan struct {
+	ID            string            `json:"id"`
+	ScanID        string            `json:"scan_id"`
+	ScopeTargetID string            `json:"scope_target_id"`
+	Status        string            `json:"status"`
+	Options       ScreenshotOptions `json:"options"`
+	URLsCount     int               `json:"urls_count"`
+	CapturedCount int               `json:"captured_count"`
+	Failures      []PageCapture     `json:"failures"`
+	Error         *string           `json:"error"`
+	ExecTime      *string           `json:"execution_time"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+func RunScreenshotScan(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ScopeTargetID string   `json:"scope_target_id"`
+		URLs          []string `json:"urls"`
+		ScreenshotOptions
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ScopeTargetID == "" {
+		http.Error(w, "Invalid request body. `scope_target_id` is required.", http.StatusBadRequest)
+		return
+	}
+	options := payload.ScreenshotOptions.withDefaults()
+	optionsJSON, _ := json.Marshal(options)
+
+	scanID := uuid.New().String()
+	_, err := dbPool.Exec(context.Background(),
+		`INSERT INTO screenshot_scans (scan_id, scope_target_id, status, options) VALUES ($1, $2, $3, $4)`,
+		scanID, payload.ScopeTargetID, "pending", optionsJSON)
+	if err != nil {
+		log.Printf("[SCREENSHOT] [ERROR] Failed to create scan record: %v", err)
+		http.Error(w, "Failed to create scan record.", http.StatusInternalServerError)
+		return
+	}
+
+	go ExecuteScreenshotScan(scanID, payload.ScopeTargetID, payload.URLs, options)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"scan_id": scanID})
+}
+
+// ExecuteScreenshotScan captures the given URLs, or every live target URL of the scope target
+// when none are given
+func ExecuteScreenshotScan(scanID, scopeTargetID string, urls []string, options ScreenshotOptions) {
+	log.Printf("[SCREENSHOT] [INFO] Starting screenshot scan %s for scope target %s", scanID, scopeTargetID)
+	startTime := time.Now()
+	updateScreenshotScan(scanID, "processing", 0, 0, nil, "", "")
+
+	if len(urls) == 0 {
+		rows, err := dbPool.Query(context.Background(),
+			`SELECT url FROM target_urls WHERE scope_target_id = $1::uuid AND no_longer_live = false ORDER BY url`,
+			scopeTargetID)
+		if err != nil {
+			log.Printf("[SCREENSHOT] [ERROR] Failed to get target URLs: %v", err)
+			updateScreenshotScan(scanID, "error", 0, 0, nil, err.Error(), time.Since(startTime).String())
+			return
+		}
+		for rows.Next() {
+			var targetURL string
+			if rows.Scan(&targetURL) == nil {
+				urls = append(urls, targetURL)
+			}
+		}
+		rows.Close()
+	}
+	if len(urls) == 0 {
+		updateScreenshotScan(scanID, "error", 0, 0, nil, "No live target URLs to capture", time.Since(startTime).String())
+		return
+	}
+
+	var mu sync.Mutex
+	captured := 0
+	failures := []PageCapture{}
+	err := CapturePages(urls, options, func(capture PageCapture) {
+		if capture.Error == "" {
+			if screenshot, err := StoreScreenshot(scopeTargetID, capture.URL, capture.Image); err != nil {
+				capture.Error = err.Error()
+			} else if err := recordPageDetails(screenshot, capture, options); err != nil {
+				log.Printf("[SCREENSHOT] [WARN] %v", err)
+			}
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if capture.Error != "" {
+			log.Printf("[SCREENSHOT] [WARN] Failed to capture %s: %s", capture.URL, capture.Error)
+			failures = append(failures, capture)
+			return
+		}
+		captured++
+	})
+	if err != nil {
+		log.Printf("[SCREENSHOT] [ERROR] Screenshot scan %s failed: %v", scanID, err)
+		updateScreenshotScan(scanID, "error", len(urls), 0, nil, err.Error(), time.Since(startTime).String())
+		return
+	}
+
+	execTime := time.Since(startTime).String()
+	updateScreenshotScan(scanID, "success", len(urls), captured, failures, "", execTime)
+	log.Printf("[SCREENSHOT] [INFO] Scan %s completed in %s: %d of %d pages captured", scanID, execTime, captured, len(urls))
+}
+
+func updateScreenshotScan(scanID, status string, urlsCount, capturedCount int, failures []PageCapture, errorMessage, execTime string) {
+	failuresJSON, _ := json.Marshal(failures)
+	if failures == nil {
+		failuresJSON = []byte("[]")
+	}
+	_, err := dbPool.Exec(context.Background(), `
+		UPDATE screenshot_scans
+		SET status = $1, urls_count = $2, captured_count = $3, failures = $4,
+		    error = NULLIF($5, ''), execution_time = NULLIF($6, '')
+		WHERE scan_id = $7`,
+		status, urlsCount, capturedCount, failuresJSON, errorMessage, execTime, scanID)
+	if err != nil {
+		log.Printf("[SCREENSHOT] [ERROR] Failed to update scan status: %v", err)
+	}
+}
+
+const screenshotScanColumns = `id, scan_id, scope_target_id, status, options, urls_count, captured_count,
+	failures, error, execution_time, created_at`
+
+func scanScreenshotScan(row interface{ Scan(...interface{}) error }) (ScreenshotScan, error) {
+	var scan ScreenshotScan
+	var options, failures []byte
+	err := row.Scan(
+		&scan.ID,
+		&scan.ScanID,
+		&scan.ScopeTargetID,
+		&scan.Status,
+		&options,
+		&scan.URLsCount,
+		&scan.CapturedCount,
+		&failures,
+		&scan.Error,
+		&scan.ExecTime,
+		&scan.CreatedAt,
+	)
+	json.Unmarshal(options, &scan.Options)
+	scan.Failures = []PageCapture{}
+	json.Unmarshal(failures, &scan.Failures)
+	return scan, err
+}
+
+func GetScreenshotScanStatus(w http.ResponseWriter, r *http.Request) {
+	scanID := mux.Vars(r)["scan_id"]
+
+	scan, err := scanScreenshotScan(dbPool.QueryRow(context.Background(),
+		`SELECT `+screenshotScanColumns+` FROM screenshot_scans WHERE scan_id = $1`, scanID))
+	if err != nil {
+		http.Error(w, "Scan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scan)
+}
+
+func GetScreenshotScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT `+screenshotScanColumns+` FROM screenshot_scans WHERE scope_target_id = $1 ORDER BY created_at DESC`,
+		scopeTargetID)
+	if err != nil {
+		log.Printf("[SCREENSHOT] [ERROR] Failed to get scans for target %s: %v", scopeTargetID, err)
+		http.Error(w, "Failed to get scans", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	scans := []ScreenshotScan{}
+	for rows.Next() {
+		scan, err := scanScreenshotScan(rows)
+		if err != nil {
+			log.Printf("[SCREENSHOT] [ERROR] Failed to scan row: %v", err)
+			continue
+		}
+		scans = append(scans, scan)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scans)
+}
@@ -0,0 +1,872 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	securityHeaderWorkers   = 10
+	securityHeaderTimeout   = 15 * time.Second
+	securityHeaderBodyLimit = 64 * 1024
+	// HSTS max-age below 180 days leaves long gaps between visits unprotected, and the preload
+	// list only accepts a year or more together with includeSubDomains
+	hstsMinMaxAge     = 180 * 24 * 60 * 60
+	hstsPreloadMaxAge = 365 * 24 * 60 * 60
+)
+
+// Checks a security header finding can belong to
+const (
+	SecurityCheckCSP     = "csp"
+	SecurityCheckHSTS    = "hsts"
+	SecurityCheckFraming = "framing"
+	SecurityCheckCORS    = "cors"
+	SecurityCheckCookies = "cookies"
+)
+
+var SecurityHeaderChecks = []string{SecurityCheckCSP, SecurityCheckHSTS, SecurityCheckFraming, SecurityCheckCORS, SecurityCheckCookies}
+
+// Response headers kept with each result so findings can be checked against what was served
+var securityHeaderNames = []string{
+	"Content-Type", "Content-Security-Policy", "Content-Security-Policy-Report-Only",
+	"Strict-Transport-Security", "X-Frame-Options", "Access-Control-Allow-Origin",
+	"Access-Control-Allow-Credentials",
+}
+
+// Cookie names that usually carry a session or credential
+var sensitiveCookiePattern = regexp.MustCompile(`(?i)sess|auth|token|jwt|login|remember|^sid$|[_.-]sid$`)
+
+// Points taken off a URL's score for each finding
+var securityHeaderDeductions = map[string]int{
+	"critical": 40,
+	"high":     25,
+	"medium":   10,
+	"low":      3,
+}
+
+// SecurityHeaderFinding is one graded issue in the headers or cookies a URL serves
+type SecurityHeaderFinding struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Title    string `json:"title"`
+	Details  string `json:"details"`
+}
+
+// CORSProbe is one request sent with a crafted Origin and what the server allowed in return
+type CORSProbe struct {
+	Kind             string `json:"kind"`
+	Origin           string `json:"origin"`
+	AllowOrigin      string `json:"allow_origin"`
+	AllowCredentials bool   `json:"allow_credentials"`
+	Reflected        bool   `json:"reflected"`
+}
+
+// SecurityHeaderResult is the header and cookie posture of one URL. Source is "probe" when the
+// URL answered the scan and "stored" when the headers captured by the metadata scan were used.
+type SecurityHeaderResult struct {
+	ID            string                  `json:"id"`
+	ScanID        string                  `json:"scan_id"`
+	ScopeTargetID string                  `json:"scope_target_id"`
+	URL           string                  `json:"url"`
+	FinalURL      string                  `json:"final_url"`
+	Source        string                  `json:"source"`
+	StatusCode    *int                    `json:"status_code"`
+	Grade         string                  `json:"grade"`
+	Score         int                     `json:"score"`
+	Headers       map[string]string       `json:"headers"`
+	CORSProbes    []CORSProbe             `json:"cors_probes"`
+	Findings      []SecurityHeaderFinding `json:"findings"`
+	CheckedAt     time.Time               `json:"checked_at"`
+}
+
+type SecurityHeaderScan struct {
+	ID            string    `json:"id"`
+	ScanID        string    `json:"scan_id"`
+	ScopeTargetID string    `json:"scope_target_id"`
+	Status        string    `json:"status"`
+	URLsChecked   int       `json:"urls_checked"`
+	FindingsCount int       `json:"findings_count"`
+	Error         *string   `json:"error"`
+	ExecTime      *string   `json:"execution_time"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// SecurityHeaderSummary rolls the latest results of a scope target up by grade, severity and
+// check. Checks counts the URLs with at least one non-informational finding of each check.
+type SecurityHeaderSummary struct {
+	ScopeTargetID string                 `json:"scope_target_id"`
+	URLs          int                    `json:"urls"`
+	AverageScore  int                    `json:"average_score"`
+	Grades        map[string]int         `json:"grades"`
+	Severities    map[string]int         `json:"severities"`
+	Checks        map[string]int         `json:"checks"`
+	LatestScan    *SecurityHeaderScan    `json:"latest_scan"`
+	Results       []SecurityHeaderResult `json:"results"`
+}
+
+func (r *SecurityHeaderResult) add(check, severity, title, details string) {
+	r.Findings = append(r.Findings, SecurityHeaderFinding{Check: check, Severity: severity, Title: title, Details: details})
+}
+
+// grade scores the result from its findings
+func (r *SecurityHeaderResult) grade() {
+	score := 100
+	for _, f := range r.Findings {
+		score -= securityHeaderDeductions[f.Severity]
+	}
+	if score < 0 {
+		score = 0
+	}
+	r.Score = score
+	r.Grade = letterGrade(score)
+}
+
+// parseCSP maps each directive of the policies to its sources. When a response carries several
+// policies the first occurrence of a directive is used.
+func parseCSP(values []string) map[string][]string {
+	directives := make(map[string][]string)
+	for _, value := range values {
+		for _, part := range strings.Split(value, ";") {
+			fields := strings.Fields(part)
+			if len(fields) == 0 {
+				continue
+			}
+			name := strings.ToLower(fields[0])
+			if _, exists := directives[name]; !exists {
+				sources := make([]string, 0, len(fields)-1)
+				for _, source := range fields[1:] {
+					sources = append(sources, strings.ToLower(source))
+				}
+				directives[name] = sources
+			}
+		}
+	}
+	return directives
+}
+
+// permissiveSources returns the sources that allow content from any origin
+func permissiveSources(sources []string) []string {
+	var permissive []string
+	for _, source := range sources {
+		switch source {
+		case "*", "http:", "https:", "data:", "blob:", "http://*", "https://*":
+			permissive = append(permissive, source)
+		}
+	}
+	return permissive
+}
+
+func isHTMLResponse(header http.Header) bool {
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	return contentType == "" || strings.Contains(contentType, "text/html") || strings.Contains(contentType, "xhtml")
+}
+
+func checkCSP(result *SecurityHeaderResult, header http.Header, directives map[string][]string) {
+	if len(header.Values("Content-Security-Policy")) == 0 {
+		if len(header.Values("Content-Security-Policy-Report-Only")) > 0 {
+			result.add(SecurityCheckCSP, "low", "Content-Security-Policy is only reported",
+				"The policy is sent as Content-Security-Policy-Report-Only, so violations are reported but nothing is blocked.")
+			return
+		}
+		result.add(SecurityCheckCSP, "medium", "Missing Content-Security-Policy",
+			"No policy restricts where scripts, frames and other content may load from, so injected markup runs unhindered.")
+		return
+	}
+
+	scriptSources, ok := directives["script-src"]
+	if !ok {
+		scriptSources, ok = directives["default-src"]
+	}
+	if !ok {
+		result.add(SecurityCheckCSP, "medium", "Content-Security-Policy does not restrict scripts",
+			"The policy has neither script-src nor default-src, so scripts may load from anywhere.")
+	} else {
+		nonceOrHash, strictDynamic := false, false
+		for _, source := range scriptSources {
+			if strings.HasPrefix(source, "'nonce-") || strings.HasPrefix(source, "'sha") {
+				nonceOrHash = true
+			}
+			if source == "'strict-dynamic'" {
+				strictDynamic = true
+			}
+		}
+		// Browsers ignore 'unsafe-inline' when a nonce or hash is present and host sources when
+		// 'strict-dynamic' is
+		if containsString(scriptSources, "'unsafe-inline'") && !nonceOrHash {
+			result.add(SecurityCheckCSP, "medium", "Content-Security-Policy allows inline scripts",
+				"script-src includes 'unsafe-inline' without a nonce or hash, which leaves injected inline scripts executable.")
+		}
+		if containsString(scriptSources, "'unsafe-eval'") {
+			result.add(SecurityCheckCSP, "low", "Content-Security-Policy allows eval",
+				"script-src includes 'unsafe-eval', so strings can be executed as code with eval() and similar functions.")
+		}
+		if permissive := permissiveSources(scriptSources); len(permissive) > 0 && !strictDynamic {
+			result.add(SecurityCheckCSP, "medium", "Content-Security-Policy allows scripts from any origin",
+				fmt.Sprintf("script-src allows %s, so an attacker can load a script from a host they control.", strings.Join(permissive, " ")))
+		}
+	}
+
+	if _, ok := directives["object-src"]; !ok {
+		if _, ok := directives["default-src"]; !ok {
+			result.add(SecurityCheckCSP, "low", "Content-Security-Policy does not restrict plugins",
+				"The policy has neither object-src nor default-src, so <object> and <embed> content may load from anywhere.")
+		}
+	}
+}
+
+// checkHSTS is skipped when finalURL is nil, as for stored headers of a plain HTTP URL that may
+// have redirected to HTTPS
+func checkHSTS(result *SecurityHeaderResult, header http.Header, finalURL *url.URL) {
+	if finalURL == nil {
+		return
+	}
+	if finalURL.Scheme != "https" {
+		result.add(SecurityCheckHSTS, "medium", "Served over HTTP without redirecting to HTTPS",
+			"The URL answers over plain HTTP instead of redirecting to HTTPS, so traffic can be read and modified in transit.")
+		return
+	}
+
+	value := header.Get("Strict-Transport-Security")
+	if value == "" {
+		result.add(SecurityCheckHSTS, "medium", "Missing Strict-Transport-Security",
+			"Without HSTS a first visit or a typed http:// link can be downgraded to plain HTTP.")
+		return
+	}
+
+	maxAge := -1
+	includeSubDomains, preload := false, false
+	for _, part := range strings.Split(value, ";") {
+		name, directiveValue, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(strings.TrimSpace(directiveValue), `"`)); err == nil && seconds >= 0 {
+				maxAge = seconds
+			}
+		case "includesubdomains":
+			includeSubDomains = true
+		case "preload":
+			preload = true
+		}
+	}
+
+	switch {
+	case maxAge < 0:
+		result.add(SecurityCheckHSTS, "medium", "Strict-Transport-Security has no valid max-age",
+			fmt.Sprintf("Browsers ignore the header %q because max-age is missing or malformed.", value))
+		return
+	case maxAge == 0:
+		result.add(SecurityCheckHSTS, "medium", "Strict-Transport-Security is disabled",
+			"max-age=0 tells browsers to forget the host's HSTS policy.")
+		return
+	case maxAge < hstsMinMaxAge:
+		result.add(SecurityCheckHSTS, "low", "Strict-Transport-Security max-age is short",
+			fmt.Sprintf("max-age is %d seconds; at least %d (180 days) is recommended.", maxAge, hstsMinMaxAge))
+	}
+
+	preloadReady := maxAge >= hstsPreloadMaxAge && includeSubDomains
+	switch {
+	case preload && !preloadReady:
+		result.add(SecurityCheckHSTS, "low", "Strict-Transport-Security preload requirements not met",
+			fmt.Sprintf("The preload directive is set, but the preload list requires max-age of at least %d and includeSubDomains.", hstsPreloadMaxAge))
+	case !preload:
+		details := "The host is not eligible for the browser HSTS preload list, so first visits are unprotected."
+		if !includeSubDomains {
+			details = "includeSubDomains is not set, so subdomains can still be reached over plain HTTP, and the host is not eligible for the browser HSTS preload list."
+		}
+		result.add(SecurityCheckHSTS, "info", "Strict-Transport-Security is not preloaded", details)
+	}
+}
+
+func checkFraming(result *SecurityHeaderResult, header http.Header, directives map[string][]string) {
+	frameAncestors, hasFrameAncestors := directives["frame-ancestors"]
+	if len(header.Values("Content-Security-Policy")) == 0 {
+		hasFrameAncestors = false
+	}
+	frameOptions := strings.ToUpper(strings.TrimSpace(header.Get("X-Frame-Options")))
+
+	if hasFrameAncestors {
+		// frame-ancestors takes precedence over X-Frame-Options in every current browser
+		if permissive := permissiveSources(frameAncestors); len(permissive) > 0 {
+			result.add(SecurityCheckFraming, "medium", "frame-ancestors allows framing by any site",
+				fmt.Sprintf("frame-ancestors allows %s, so the page can be framed for clickjacking.", strings.Join(permissive, " ")))
+		}
+		return
+	}
+
+	switch {
+	case frameOptions == "":
+		result.add(SecurityCheckFraming, "medium", "Missing clickjacking protection",
+			"Neither X-Frame-Options nor a frame-ancestors directive stops other sites from framing the page.")
+	case strings.HasPrefix(frameOptions, "ALLOW-FROM"):
+		result.add(SecurityCheckFraming, "low", "X-Frame-Options ALLOW-FROM is not supported",
+			"Current browsers ignore ALLOW-FROM, so the page can be framed by any site. Use frame-ancestors instead.")
+	case frameOptions != "DENY" && frameOptions != "SAMEORIGIN":
+		result.add(SecurityCheckFraming, "low", "Invalid X-Frame-Options value",
+			fmt.Sprintf("Browsers ignore X-Frame-Options %q; only DENY and SAMEORIGIN are recognized.", header.Get("X-Frame-Options")))
+	}
+}
+
+// checkCookies reports one finding per cookie listing every flag it is missing, at the
+// severity of the worst. Cookies already in seen were checked on a later response.
+func checkCookies(result *SecurityHeaderResult, header http.Header, finalURL *url.URL, seen map[string]bool) {
+	severityRank := map[string]int{"info": 0, "low": 1, "medium": 2}
+	for _, line := range header.Values("Set-Cookie") {
+		parts := strings.Split(line, ";")
+		name, _, _ := strings.Cut(strings.TrimSpace(parts[0]), "=")
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		secure, httpOnly, sameSite, path, domain := false, false, "", "", false
+		for _, attribute := range parts[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(attribute), "=")
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "secure":
+				secure = true
+			case "httponly":
+				httpOnly = true
+			case "samesite":
+				sameSite = strings.ToLower(strings.TrimSpace(value))
+			case "path":
+				path = strings.TrimSpace(value)
+			case "domain":
+				domain = true
+			}
+		}
+
+		sensitive := sensitiveCookiePattern.MatchString(name)
+		severity := ""
+		var issues []string
+		raise := func(level, issue string) {
+			issues = append(issues, issue)
+			if severity == "" || severityRank[level] > severityRank[severity] {
+				severity = level
+			}
+		}
+		if !secure && finalURL != nil && finalURL.Scheme == "https" {
+			if sensitive {
+				raise("medium", "no Secure flag, so it is also sent over plain HTTP")
+			} else {
+				raise("low", "no Secure flag, so it is also sent over plain HTTP")
+			}
+		}
+		if !httpOnly {
+			if sensitive {
+				raise("medium", "no HttpOnly flag, so scripts can read it")
+			} else {
+				raise("info", "no HttpOnly flag, so scripts can read it")
+			}
+		}
+		switch {
+		case sameSite == "":
+			raise("low", "no SameSite attribute, so browsers without a Lax default send it on cross-site requests")
+		case sameSite == "none" && !secure:
+			raise("low", "SameSite=None without Secure, which browsers reject")
+		case sameSite == "none" && sensitive:
+			raise("low", "SameSite=None, so it is sent on cross-site requests")
+		}
+		if strings.HasPrefix(name, "__Host-") && (!secure || domain || path != "/") {
+			raise("low", "a __Host- prefix without Secure, Path=/ and no Domain, which browsers reject")
+		} else if strings.HasPrefix(name, "__Secure-") && !secure {
+			raise("low", "a __Secure- prefix without Secure, which browsers reject")
+		}
+
+		if len(issues) > 0 {
+			result.add(SecurityCheckCookies, severity, fmt.Sprintf("Insecure cookie %s", name),
+				fmt.Sprintf("Cookie %s has %s.", name, strings.Join(issues, "; ")))
+		}
+	}
+}
+
+// analyzeSecurityHeaders runs every header check against a response and the cookie checks
+// against it and the redirects that led to it. A cookie set more than once is checked as the
+// last response set it.
+func analyzeSecurityHeaders(result *SecurityHeaderResult, header http.Header, finalURL *url.URL, redirects []*http.Response) {
+	result.Headers = make(map[string]string)
+	for _, name := range securityHeaderNames {
+		if values := header.Values(name); len(values) > 0 {
+			result.Headers[name] = strings.Join(values, ", ")
+		}
+	}
+
+	directives := parseCSP(header.Values("Content-Security-Policy"))
+	if isHTMLResponse(header) {
+		checkCSP(result, header, directives)
+		checkFraming(result, header, directives)
+	}
+	checkHSTS(result, header, finalURL)
+	seen := make(map[string]bool)
+	checkCookies(result, header, finalURL, seen)
+	for i := len(redirects) - 1; i >= 0; i-- {
+		checkCookies(result, redirects[i].Header, redirects[i].Request.URL, seen)
+	}
+}
+
+// sendCORSProbe GETs a URL with the custom user agent and header from settings plus the given
+// Origin, returning the response with its body drained
+func sendCORSProbe(client *http.Client, rawURL, origin string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	customUserAgent, customHeader := GetCustomHTTPSettings()
+	if customUserAgent != "" {
+		req.Header.Set("User-Agent", customUserAgent)
+	}
+	if name, value, ok := strings.Cut(customHeader, ":"); ok {
+		req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+	req.Header.Set("Origin", origin)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, securityHeaderBodyLimit))
+	return resp, nil
+}
+
+func corsProbeResult(kind, origin string, header http.Header) CORSProbe {
+	allowOrigin := strings.TrimSpace(header.Get("Access-Control-Allow-Origin"))
+	return CORSProbe{
+		Kind:             kind,
+		Origin:           origin,
+		AllowOrigin:      allowOrigin,
+		AllowCredentials: strings.EqualFold(strings.TrimSpace(header.Get("Access-Control-Allow-Credentials")), "true"),
+		Reflected:        allowOrigin != "" && strings.EqualFold(allowOrigin, origin),
+	}
+}
+
+// probeCORS follows up the first probe, sent from an arbitrary origin, with the origins that
+// weak allow-list checks accept: null, and look-alikes that start or end with the target host.
+// The follow-ups are skipped when the arbitrary origin is already reflected.
+func probeCORS(client *http.Client, rawURL string, first CORSProbe) []CORSProbe {
+	probes := []CORSProbe{first}
+	if first.Reflected {
+		return probes
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return probes
+	}
+	host := parsed.Hostname()
+	nonce := strings.ReplaceAll(uuid.New().String(), "-", "")[:8]
+	followUps := []struct{ kind, origin string }{
+		{"null", "null"},
+		{"prefix", parsed.Scheme + "://" + host + "." + nonce + ".example"},
+		{"suffix", parsed.Scheme + "://" + nonce + host},
+	}
+	for _, followUp := range followUps {
+		resp, err := sendCORSProbe(client, rawURL, followUp.origin)
+		if err != nil {
+			continue
+		}
+		probes = append(probes, corsProbeResult(followUp.kind, followUp.origin, resp.Header))
+	}
+	return probes
+}
+
+func checkCORS(result *SecurityHeaderResult, probes []CORSProbe) {
+	descriptions := map[string]string{
+		"arbitrary": "any origin",
+		"null":      "the null origin, which sandboxed iframes and local files send,",
+		"prefix":    "origins that merely start with the target host",
+		"suffix":    "origins that merely end with the target host",
+	}
+	for _, probe := range probes {
+		if probe.Reflected {
+			if probe.AllowCredentials {
+				result.add(SecurityCheckCORS, "high", "CORS trusts "+descriptions[probe.Kind]+" with credentials",
+					fmt.Sprintf("A request with Origin %s was answered with Access-Control-Allow-Origin %s and Access-Control-Allow-Credentials true, so any site can read authenticated responses.",
+						probe.Origin, probe.AllowOrigin))
+			} else {
+				result.add(SecurityCheckCORS, "low", "CORS trusts "+descriptions[probe.Kind],
+					fmt.Sprintf("A request with Origin %s was answered with Access-Control-Allow-Origin %s. Credentials are not allowed, so only unauthenticated responses are exposed.",
+						probe.Origin, probe.AllowOrigin))
+			}
+			continue
+		}
+		if probe.Kind == "arbitrary" && probe.AllowOrigin == "*" && probe.AllowCredentials {
+			result.add(SecurityCheckCORS, "info", "CORS wildcard sent with credentials",
+				"Access-Control-Allow-Origin * together with Access-Control-Allow-Credentials true is rejected by browsers, which suggests the policy is hand-built and worth reviewing.")
+		}
+	}
+}
+
+// checkSecurityHeaders probes a URL from an arbitrary origin and analyzes the response and the
+// cookies set along its redirects. When the URL does not answer, the headers stored by the
+// metadata scan are analyzed instead and CORS is left untested.
+func checkSecurityHeaders(client *http.Client, rawURL string, storedHeaders []byte) (*SecurityHeaderResult, error) {
+	result := &SecurityHeaderResult{URL: rawURL, FinalURL: rawURL, CORSProbes: []CORSProbe{}, CheckedAt: time.Now()}
+
+	var redirects []*http.Response
+	probeClient := *client
+	probeClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if client.CheckRedirect != nil {
+			if err := client.CheckRedirect(req, via); err != nil {
+				return err
+			}
+		}
+		redirects = append(redirects, req.Response)
+		return nil
+	}
+
+	nonce := strings.ReplaceAll(uuid.New().String(), "-", "")[:8]
+	origin := "https://" + nonce + ".example"
+	resp, err := sendCORSProbe(&probeClient, rawURL, origin)
+	if err != nil {
+		header := headersFromJSON(storedHeaders)
+		if len(header) == 0 {
+			return nil, err
+		}
+		parsed, parseErr := url.Parse(rawURL)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		result.Source = "stored"
+		// The stored headers are those of the page the URL ended on, which for a plain HTTP URL
+		// may have been an HTTPS redirect target, so its scheme is only known for HTTPS URLs
+		var finalURL *url.URL
+		if parsed.Scheme == "https" {
+			finalURL = parsed
+		}
+		analyzeSecurityHeaders(result, header, finalURL, nil)
+		result.grade()
+		return result, nil
+	}
+
+	result.Source = "probe"
+	result.FinalURL = resp.Request.URL.String()
+	result.StatusCode = &resp.StatusCode
+	analyzeSecurityHeaders(result, resp.Header, resp.Request.URL, redirects)
+	result.CORSProbes = probeCORS(client, rawURL, corsProbeResult("arbitrary", origin, resp.Header))
+	checkCORS(result, result.CORSProbes)
+	result.grade()
+	return result, nil
+}
+
+// collectSecurityHeaderTargets returns the live web servers of a scope target with the response
+// headers stored for each, if any
+func collectSecurityHeaderTargets(scopeTargetID string) (map[string][]byte, error) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT url, http_response_headers::text FROM target_urls
+		WHERE scope_target_id = $1::uuid AND no_longer_live = false
+		UNION ALL
+		SELECT lws.url, lws.http_response_headers::text FROM live_web_servers lws
+		JOIN ip_port_scans ips ON lws.scan_id = ips.scan_id
+		WHERE ips.scope_target_id = $1::uuid AND ips.status = 'success'`, scopeTargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get live web servers: %v", err)
+	}
+	defer rows.Close()
+
+	targets := make(map[string][]byte)
+	for rows.Next() {
+		var targetURL string
+		var headers *string
+		if err := rows.Scan(&targetURL, &headers); err != nil {
+			continue
+		}
+		if headers != nil {
+			targets[targetURL] = []byte(*headers)
+		} else if _, exists := targets[targetURL]; !exists {
+			targets[targetURL] = nil
+		}
+	}
+	return targets, rows.Err()
+}
+
+func RunSecurityHeaderScan(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ScopeTargetID string   `json:"scope_target_id"`
+		URLs          []string `json:"urls,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ScopeTargetID == "" {
+		http.Error(w, "Invalid request body. `scope_target_id` is required.", http.StatusBadRequest)
+		return
+	}
+
+	targets, err := collectSecurityHeaderTargets(payload.ScopeTargetID)
+	if err != nil {
+		log.Printf("[SECURITY HEADERS] [ERROR] %v", err)
+		http.Error(w, "Failed to get live web servers.", http.StatusInternalServerError)
+		return
+	}
+	if len(payload.URLs) > 0 {
+		requested := make(map[string][]byte)
+		for _, rawURL := range payload.URLs {
+			if parsed, err := url.Parse(strings.TrimSpace(rawURL)); err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") {
+				requested[parsed.String()] = targets[parsed.String()]
+			}
+		}
+		targets = requested
+	}
+	if len(targets) == 0 {
+		http.Error(w, "No URLs to analyze. Run a metadata scan first.", http.StatusBadRequest)
+		return
+	}
+
+	scanID := uuid.New().String()
+	_, err = dbPool.Exec(context.Background(),
+		`INSERT INTO security_header_scans (scan_id, scope_target_id, status) VALUES ($1, $2, $3)`,
+		scanID, payload.ScopeTargetID, "pending")
+	if err != nil {
+		log.Printf("[SECURITY HEADERS] [ERROR] Failed to create scan record: %v", err)
+		http.Error(w, "Failed to create scan record.", http.StatusInternalServerError)
+		return
+	}
+
+	go ExecuteSecurityHeaderScan(scanID, payload.ScopeTargetID, targets)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"scan_id": scanID})
+}
+
+// ExecuteSecurityHeaderScan analyzes every URL and replaces the stored results
+func ExecuteSecurityHeaderScan(scanID, scopeTargetID string, targets map[string][]byte) {
+	log.Printf("[SECURITY HEADERS] [INFO] Analyzing %d URLs for scope target %s (scan ID: %s)", len(targets), scopeTargetID, scanID)
+	startTime := time.Now()
+	updateSecurityHeaderScan(scanID, "processing", 0, 0, "", "")
+
+	client := newScanHTTPClient(securityHeaderTimeout)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var results []*SecurityHeaderResult
+	semaphore := make(chan struct{}, securityHeaderWorkers)
+	for targetURL, storedHeaders := range targets {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(targetURL string, storedHeaders []byte) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			result, err := checkSecurityHeaders(client, targetURL, storedHeaders)
+			if err != nil {
+				log.Printf("[SECURITY HEADERS] [WARN] Skipping %s: %v", targetURL, err)
+				return
+			}
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}(targetURL, storedHeaders)
+	}
+	wg.Wait()
+
+	findingsCount := 0
+	for _, result := range results {
+		findingsCount += len(result.Findings)
+	}
+
+	if err := saveSecurityHeaderResults(scanID, scopeTargetID, results); err != nil {
+		log.Printf("[SECURITY HEADERS] [ERROR] Failed to save results: %v", err)
+		updateSecurityHeaderScan(scanID, "error", len(results), findingsCount, err.Error(), time.Since(startTime).String())
+		return
+	}
+
+	execTime := time.Since(startTime).String()
+	updateSecurityHeaderScan(scanID, "success", len(results), findingsCount, "", execTime)
+	log.Printf("[SECURITY HEADERS] [INFO] Scan %s completed in %s: %d of %d URLs checked, %d findings",
+		scanID, execTime, len(results), len(targets), findingsCount)
+}
+
+// saveSecurityHeaderResults replaces the scope target's results with the latest ones
+func saveSecurityHeaderResults(scanID, scopeTargetID string, results []*SecurityHeaderResult) error {
+	tx, err := dbPool.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(), `DELETE FROM security_header_results WHERE scope_target_id = $1`, scopeTargetID); err != nil {
+		return fmt.Errorf("failed to delete old results: %v", err)
+	}
+
+	for _, r := range results {
+		headersJSON, _ := json.Marshal(r.Headers)
+		probesJSON, _ := json.Marshal(r.CORSProbes)
+		findingsJSON, _ := json.Marshal(r.Findings)
+		_, err := tx.Exec(context.Background(), `
+			INSERT INTO security_header_results
+				(scan_id, scope_target_id, url, final_url, source, status_code, grade, score, headers,
+				 cors_probes, findings, checked_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+			ON CONFLICT (scope_target_id, url) DO NOTHING`,
+			scanID, scopeTargetID, r.URL, r.FinalURL, r.Source, r.StatusCode, r.Grade, r.Score, headersJSON,
+			probesJSON, findingsJSON, r.CheckedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert result for %s: %v", r.URL, err)
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func updateSecurityHeaderScan(scanID, status string, urlsChecked, findingsCount int, errorMessage, execTime string) {
+	_, err := dbPool.Exec(context.Background(), `
+		UPDATE security_header_scans
+		SET status = $1, urls_checked = $2, findings_count = $3,
+		    error = NULLIF($4, ''), execution_time = NULLIF($5, '')
+		WHERE scan_id = $6`,
+		status, urlsChecked, findingsCount, errorMessage, execTime, scanID)
+	if err != nil {
+		log.Printf("[SECURITY HEADERS] [ERROR] Failed to update scan status: %v", err)
+	}
+}
+
+const securityHeaderScanColumns = `id, scan_id, scope_target_id, status, urls_checked, findings_count, error, execution_time, created_at`
+
+func scanSecurityHeaderScan(row interface{ Scan(...interface{}) error }) (SecurityHeaderScan, error) {
+	var scan SecurityHeaderScan
+	err := row.Scan(&scan.ID, &scan.ScanID, &scan.ScopeTargetID, &scan.Status, &scan.URLsChecked,
+		&scan.FindingsCount, &scan.Error, &scan.ExecTime, &scan.CreatedAt)
+	return scan, err
+}
+
+func GetSecurityHeaderScanStatus(w http.ResponseWriter, r *http.Request) {
+	scanID := mux.Vars(r)["scan_id"]
+
+	scan, err := scanSecurityHeaderScan(dbPool.QueryRow(context.Background(),
+		`SELECT `+securityHeaderScanColumns+` FROM security_header_scans WHERE scan_id = $1`, scanID))
+	if err != nil {
+		http.Error(w, "Scan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scan)
+}
+
+func GetSecurityHeaderScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT `+securityHeaderScanColumns+` FROM security_header_scans WHERE scope_target_id = $1 ORDER BY created_at DESC`,
+		scopeTargetID)
+	if err != nil {
+		log.Printf("[SECURITY HEADERS] [ERROR] Failed to get scans for target %s: %v", scopeTargetID, err)
+		http.Error(w, "Failed to get scans", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	scans := []SecurityHeaderScan{}
+	for rows.Next() {
+		scan, err := scanSecurityHeaderScan(rows)
+		if err != nil {
+			log.Printf("[SECURITY HEADERS] [ERROR] Failed to scan row: %v", err)
+			continue
+		}
+		scans = append(scans, scan)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scans)
+}
+
+func fetchSecurityHeaderResults(scopeTargetID string) ([]SecurityHeaderResult, error) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, scan_id, scope_target_id, url, final_url, source, status_code, grade, score, headers,
+		       cors_probes, findings, checked_at
+		FROM security_header_results
+		WHERE scope_target_id = $1::uuid
+		ORDER BY score ASC, url ASC`, scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []SecurityHeaderResult{}
+	for rows.Next() {
+		var r SecurityHeaderResult
+		var headersJSON, probesJSON, findingsJSON []byte
+		if err := rows.Scan(&r.ID, &r.ScanID, &r.ScopeTargetID, &r.URL, &r.FinalURL, &r.Source, &r.StatusCode,
+			&r.Grade, &r.Score, &headersJSON, &probesJSON, &findingsJSON, &r.CheckedAt); err != nil {
+			log.Printf("[SECURITY HEADERS] [ERROR] Failed to scan result: %v", err)
+			continue
+		}
+		r.Headers = map[string]string{}
+		json.Unmarshal(headersJSON, &r.Headers)
+		r.CORSProbes = []CORSProbe{}
+		json.Unmarshal(probesJSON, &r.CORSProbes)
+		r.Findings = []SecurityHeaderFinding{}
+		json.Unmarshal(findingsJSON, &r.Findings)
+		results = append(results, r)
+	}
+	return results, rows.Err()
+}
+
+// GetSecurityHeaderSummary returns the graded header posture of every URL of a scope target
+// along with grade, severity and per-check totals
+func GetSecurityHeaderSummary(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	results, err := fetchSecurityHeaderResults(scopeTargetID)
+	if err != nil {
+		log.Printf("[SECURITY HEADERS] [ERROR] Failed to get results: %v", err)
+		http.Error(w, "Failed to get security header results", http.StatusInternalServerError)
+		return
+	}
+
+	summary := SecurityHeaderSummary{
+		ScopeTargetID: scopeTargetID,
+		URLs:          len(results),
+		Grades:        map[string]int{"A": 0, "B": 0, "C": 0, "D": 0, "F": 0},
+		Severities:    map[string]int{"critical": 0, "high": 0, "medium": 0, "low": 0, "info": 0},
+		Checks:        make(map[string]int),
+		Results:       results,
+	}
+	for _, check := range SecurityHeaderChecks {
+		summary.Checks[check] = 0
+	}
+	totalScore := 0
+	for _, result := range results {
+		summary.Grades[result.Grade]++
+		totalScore += result.Score
+		failed := make(map[string]bool)
+		for _, f := range result.Findings {
+			summary.Severities[f.Severity]++
+			if f.Severity != "info" {
+				failed[f.Check] = true
+			}
+		}
+		for check := range failed {
+			summary.Checks[check]++
+		}
+	}
+	if len(results) > 0 {
+		summary.AverageScore = totalScore / len(results)
+	}
+
+	scan, err := scanSecurityHeaderScan(dbPool.QueryRow(context.Background(),
+		`SELECT `+securityHeaderScanColumns+` FROM security_header_scans WHERE scope_target_id = $1 ORDER BY created_at DESC LIMIT 1`,
+		scopeTargetID))
+	if err == nil {
+		summary.LatestScan = &scan
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
@@ -0,0 +1,556 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// DiscoveredService is an open port running something other than a plain web server
+type DiscoveredService struct {
+	ID              string    `json:"id"`
+	ScanID          string    `json:"scan_id"`
+	IPAddress       string    `json:"ip_address"`
+	Hostname        string    `json:"hostname,omitempty"`
+	Port            int       `json:"port"`
+	Transport       string    `json:"transport"`
+	Service         string    `json:"service"`
+	Product         string    `json:"product,omitempty"`
+	Version         string    `json:"version,omitempty"`
+	Banner          string    `json:"banner,omitempty"`
+	TLS             bool      `json:"tls"`
+	DetectionMethod string    `json:"detection_method"`
+	DiscoveredAt    time.Time `json:"discovered_at"`
+}
+
+// Ports that speak TLS before the service protocol starts
+var implicitTLSPorts = map[int]bool{465: true, 636: true, 990: true, 993: true, 995: true}
+
+// Well known service names, used when an open port neither sends a banner nor answers a probe
+var wellKnownServices = map[int]string{
+	21: "ftp", 22: "ssh", 23: "telnet", 25: "smtp", 53: "domain", 110: "pop3", 111: "rpcbind",
+	135: "msrpc", 139: "netbios-ssn", 143: "imap", 389: "ldap", 445: "microsoft-ds", 465: "smtps",
+	587: "submission", 636: "ldaps", 993: "imaps", 995: "pop3s", 1433: "mssql", 1521: "oracle",
+	2049: "nfs", 2375: "docker", 3306: "mysql", 3389: "rdp", 5432: "postgresql", 5672: "amqp",
+	5900: "vnc", 5985: "winrm", 6379: "redis", 9092: "kafka", 9200: "elasticsearch",
+	11211: "memcached", 27017: "mongodb",
+}
+
+// bannerRule identifies a service from the banner it sends on connect. The first capture group
+// of the version pattern is the product version.
+type bannerRule struct {
+	service string
+	match   *regexp.Regexp
+	product string
+	version *regexp.Regexp
+}
+
+var bannerRules = []bannerRule{
+	{"ftp", regexp.MustCompile(`^220[ -].*vsFTPd`), "vsftpd", regexp.MustCompile(`vsFTPd ([\d.]+)`)},
+	{"ftp", regexp.MustCompile(`^220[ -].*ProFTPD`), "ProFTPD", regexp.MustCompile(`ProFTPD ([\d.]+\w*)`)},
+	{"ftp", regexp.MustCompile(`^220[ -].*FileZilla Server`), "FileZilla Server", regexp.MustCompile(`FileZilla Server(?: version)? ([\d.]+\w*)`)},
+	{"ftp", regexp.MustCompile(`^220[ -].*Pure-FTPd`), "Pure-FTPd", nil},
+	{"ftp", regexp.MustCompile(`^220[ -].*Microsoft FTP Service`), "Microsoft ftpd", nil},
+	{"smtp", regexp.MustCompile(`^220[ -].*Microsoft ESMTP MAIL Service`), "Microsoft Exchange smtpd", regexp.MustCompile(`Version: ([\d.]+)`)},
+	{"smtp", regexp.MustCompile(`^220[ -].*Postfix`), "Postfix smtpd", nil},
+	{"smtp", regexp.MustCompile(`^220[ -].*Exim`), "Exim smtpd", regexp.MustCompile(`Exim ([\d.]+)`)},
+	{"smtp", regexp.MustCompile(`^220[ -].*Sendmail`), "Sendmail", regexp.MustCompile(`Sendmail ([\d.]+)`)},
+	{"smtp", regexp.MustCompile(`(?i)^220[ -].*SMTP`), "", nil},
+	{"ftp", regexp.MustCompile(`(?i)^220[ -].*FTP`), "", nil},
+	{"pop3", regexp.MustCompile(`^\+OK`), "", nil},
+	{"imap", regexp.MustCompile(`^\* OK`), "", nil},
+	{"vnc", regexp.MustCompile(`^RFB \d{3}\.\d{3}`), "", regexp.MustCompile(`^RFB (\d{3}\.\d{3})`)},
+}
+
+var (
+	sshBannerRegex        = regexp.MustCompile(`^SSH-([\d.]+)-(\S+)`)
+	redisVersionRegex     = regexp.MustCompile(`redis_version:([\w.]+)`)
+	memcachedVersionRegex = regexp.MustCompile(`^VERSION ([\w.]+)`)
+	elasticVersionRegex   = regexp.MustCompile(`"number"\s*:\s*"([^"]+)"`)
+)
+
+// serviceProbe identifies a client-speaks-first service by sending it a request
+type serviceProbe struct {
+	service string
+	ports   []int
+	tryTLS  bool
+	payload func(host string) []byte
+	match   func(resp []byte) (product, version string, ok bool)
+}
+
+var serviceProbes = []serviceProbe{
+	{
+		service: "redis",
+		ports:   []int{6379, 6380},
+		payload: func(string) []byte { return []byte("INFO server\r\n") },
+		match: func(resp []byte) (string, string, bool) {
+			if bytes.HasPrefix(resp, []byte("-NOAUTH")) || bytes.HasPrefix(resp, []byte("-DENIED")) {
+				return "Redis key-value store", "", true
+			}
+			if matches := redisVersionRegex.FindSubmatch(resp); matches != nil {
+				return "Redis key-value store", string(matches[1]), true
+			}
+			return "", "", false
+		},
+	},
+	{
+		service: "memcached",
+		ports:   []int{11211},
+		payload: func(string) []byte { return []byte("version\r\n") },
+		match: func(resp []byte) (string, string, bool) {
+			if matches := memcachedVersionRegex.FindSubmatch(resp); matches != nil {
+				return "Memcached", string(matches[1]), true
+			}
+			return "", "", false
+		},
+	},
+	{
+		service: "mongodb",
+		ports:   []int{27017, 27018, 27019},
+		payload: func(string) []byte { return mongoBuildInfoQuery() },
+		match:   matchMongoReply,
+	},
+	{
+		service: "postgresql",
+		ports:   []int{5432, 5433},
+		payload: func(string) []byte { return []byte{0x00, 0x00, 0x00, 0x08, 0x04, 0xd2, 0x16, 0x2f} }, // SSLRequest
+		match: func(resp []byte) (string, string, bool) {
+			if len(resp) == 1 && (resp[0] == 'S' || resp[0] == 'N') {
+				return "PostgreSQL", "", true
+			}
+			return "", "", false
+		},
+	},
+	{
+		service: "mssql",
+		ports:   []int{1433},
+		payload: func(string) []byte { return mssqlPreLoginPacket() },
+		match:   matchMSSQLPreLogin,
+	},
+	{
+		service: "rdp",
+		ports:   []int{3389},
+		// X.224 connection request asking for TLS
+		payload: func(string) []byte {
+			return []byte{0x03, 0x00, 0x00, 0x13, 0x0e, 0xe0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x08, 0x00, 0x03, 0x00, 0x00, 0x00}
+		},
+		match: func(resp []byte) (string, string, bool) {
+			if len(resp) >= 6 && resp[0] == 0x03 && resp[1] == 0x00 && resp[5]&0xf0 == 0xd0 {
+				return "Microsoft Terminal Services", "", true
+			}
+			return "", "", false
+		},
+	},
+	{
+		service: "elasticsearch",
+		ports:   []int{9200, 9201},
+		tryTLS:  true,
+		payload: func(host string) []byte {
+			return []byte(fmt.Sprintf("GET / HTTP/1.1\r\nHost: %s\r\nUser-Agent: Mozilla/5.0\r\nConnection: close\r\n\r\n", host))
+		},
+		match: func(resp []byte) (string, string, bool) {
+			if !bytes.Contains(resp, []byte("You Know, for Search")) && !bytes.Contains(resp, []byte(`"cluster_name"`)) &&
+				!bytes.Contains(bytes.ToLower(resp), []byte("x-elastic-product: elasticsearch")) {
+				return "", "", false
+			}
+			if matches := elasticVersionRegex.FindSubmatch(resp); matches != nil {
+				return "Elasticsearch", string(matches[1]), true
+			}
+			return "Elasticsearch", "", true
+		},
+	},
+}
+
+// fingerprintService identifies the service on an open port from its banner or by probing it.
+// It returns nil when the port stays silent and no probe applies, which leaves it to the HTTP check.
+func fingerprintService(scanID, ipAddr string, port int, timeout time.Duration) *DiscoveredService {
+	service := &DiscoveredService{
+		ScanID:       scanID,
+		IPAddress:    ipAddr,
+		Port:         port,
+		Transport:    "tcp",
+		DiscoveredAt: time.Now(),
+	}
+
+	banner, _ := exchangeProbe(ipAddr, port, timeout, false, nil)
+	if len(banner) == 0 && implicitTLSPorts[port] {
+		banner, _ = exchangeProbe(ipAddr, port, timeout, true, nil)
+		service.TLS = len(banner) > 0
+	}
+	if len(banner) > 0 {
+		matchBanner(service, banner)
+		service.Banner = sanitizeBanner(banner)
+		service.DetectionMethod = "banner"
+		return service
+	}
+
+	for _, probe := range serviceProbes {
+		if !containsPort(probe.ports, port) {
+			continue
+		}
+		attempts := []bool{false}
+		if probe.tryTLS {
+			attempts = append(attempts, true)
+		}
+		for _, useTLS := range attempts {
+			resp, err := exchangeProbe(ipAddr, port, timeout, useTLS, probe.payload(ipAddr))
+			if err != nil || len(resp) == 0 {
+				continue
+			}
+			if product, version, ok := probe.match(resp); ok {
+				service.Service = probe.service
+				service.Product = product
+				service.Version = version
+				service.TLS = useTLS
+				service.DetectionMethod = "probe"
+				return service
+			}
+		}
+	}
+
+	return nil
+}
+
+// wellKnownService records an open port that could only be identified by its number
+func wellKnownService(scanID, ipAddr string, port int) *DiscoveredService {
+	name, ok := wellKnownServices[port]
+	if !ok {
+		name = "unknown"
+	}
+	return &DiscoveredService{
+		ScanID:          scanID,
+		IPAddress:       ipAddr,
+		Port:            port,
+		Transport:       "tcp",
+		Service:         name,
+		DetectionMethod: "port",
+		DiscoveredAt:    time.Now(),
+	}
+}
+
+func matchBanner(service *DiscoveredService, banner []byte) {
+	if matches := sshBannerRegex.FindSubmatch(banner); matches != nil {
+		service.Service = "ssh"
+		software := string(matches[2])
+		if idx := strings.IndexAny(software, "_-"); idx != -1 {
+			service.Product, service.Version = software[:idx], software[idx+1:]
+		} else {
+			service.Product = software
+		}
+		return
+	}
+
+	// MySQL sends a binary handshake: 3 byte length, sequence id, then protocol version 10 and a
+	// NUL terminated server version. Hosts that may not connect get an error packet instead.
+	if len(banner) > 5 && banner[3] == 0x00 {
+		switch banner[4] {
+		case 0x0a:
+			version := banner[5:]
+			if idx := bytes.IndexByte(version, 0x00); idx != -1 {
+				version = version[:idx]
+			}
+			service.Service = "mysql"
+			service.Product = "MySQL"
+			service.Version = string(version)
+			if strings.Contains(service.Version, "MariaDB") {
+				service.Product = "MariaDB"
+				service.Version = strings.TrimSuffix(strings.TrimPrefix(service.Version, "5.5.5-"), "-MariaDB")
+			}
+			return
+		case 0xff:
+			if bytes.Contains(banner, []byte("MySQL")) || bytes.Contains(banner, []byte("MariaDB")) {
+				service.Service = "mysql"
+				service.Product = "MySQL"
+				return
+			}
+		}
+	}
+
+	if banner[0] == 0xff {
+		service.Service = "telnet"
+		return
+	}
+
+	for _, rule := range bannerRules {
+		if !rule.match.Match(banner) {
+			continue
+		}
+		service.Service = rule.service
+		service.Product = rule.product
+		if rule.version != nil {
+			if matches := rule.version.FindSubmatch(banner); matches != nil {
+				service.Version = string(matches[1])
+			}
+		}
+		return
+	}
+
+	// A bare 220 greeting is FTP or SMTP, tell them apart by port
+	if bytes.HasPrefix(banner, []byte("220")) {
+		switch service.Port {
+		case 25, 465, 587, 2525:
+			service.Service = "smtp"
+			return
+		case 21, 990, 2121:
+			service.Service = "ftp"
+			return
+		}
+	}
+
+	if name, ok := wellKnownServices[service.Port]; ok {
+		service.Service = name
+	} else {
+		service.Service = "unknown"
+	}
+}
+
+// exchangeProbe connects to a port, sends the payload if there is one and returns what the
+// service answers within the timeout
+func exchangeProbe(ipAddr string, port int, timeout time.Duration, useTLS bool, payload []byte) ([]byte, error) {
+	address := net.JoinHostPort(ipAddr, strconv.Itoa(port))
+	dialer := &net.Dialer{Timeout: timeout}
+
+	var conn net.Conn
+	var err error
+	if useTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{InsecureSkipVerify: true})
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	if len(payload) > 0 {
+		if _, err := conn.Write(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep reading briefly after the first chunk since multi-line banners and HTTP responses
+	// often arrive in several segments
+	buf := make([]byte, 4096)
+	n, err := conn.Read(buf)
+	for err == nil && n < len(buf) {
+		conn.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+		var more int
+		more, err = conn.Read(buf[n:])
+		n += more
+	}
+	if n == 0 {
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// sanitizeBanner keeps the start of a banner as printable text, escaping binary bytes
+func sanitizeBanner(banner []byte) string {
+	if len(banner) > 512 {
+		banner = banner[:512]
+	}
+	var sb strings.Builder
+	for _, b := range banner {
+		switch {
+		case b == '\n' || b == '\t':
+			sb.WriteByte(b)
+		case b == '\r':
+		case b >= 0x20 && b < 0x7f:
+			sb.WriteByte(b)
+		default:
+			fmt.Fprintf(&sb, "\\x%02x", b)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+func containsPort(ports []int, port int) bool {
+	for _, p := range ports {
+		if p == port {
+			return true
+		}
+	}
+	return false
+}
+
+// mongoBuildInfoQuery builds an OP_QUERY for {buildinfo: 1} on admin.$cmd. Servers that no longer
+// accept OP_QUERY commands still answer with an error reply, which is enough to identify them.
+func mongoBuildInfoQuery() []byte {
+	doc := []byte{0x14, 0x00, 0x00, 0x00, 0x10}
+	doc = append(doc, []byte("buildinfo\x00")...)
+	doc = append(doc, 0x01, 0x00, 0x00, 0x00, 0x00)
+
+	var body []byte
+	body = binary.LittleEndian.AppendUint32(body, 0) // flags
+	body = append(body, []byte("admin.$cmd\x00")...)
+	body = binary.LittleEndian.AppendUint32(body, 0)          // numberToSkip
+	body = binary.LittleEndian.AppendUint32(body, 0xffffffff) // numberToReturn -1
+	body = append(body, doc...)
+
+	var msg []byte
+	msg = binary.LittleEndian.AppendUint32(msg, uint32(16+len(body)))
+	msg = binary.LittleEndian.AppendUint32(msg, 1)    // requestID
+	msg = binary.LittleEndian.AppendUint32(msg, 0)    // responseTo
+	msg = binary.LittleEndian.AppendUint32(msg, 2004) // OP_QUERY
+	return append(msg, body...)
+}
+
+func matchMongoReply(resp []byte) (string, string, bool) {
+	if len(resp) < 16 {
+		return "", "", false
+	}
+	opCode := binary.LittleEndian.Uint32(resp[12:16])
+	if opCode != 1 && opCode != 2013 { // OP_REPLY, OP_MSG
+		return "", "", false
+	}
+
+	version := ""
+	marker := []byte("\x02version\x00")
+	if idx := bytes.Index(resp, marker); idx != -1 {
+		start := idx + len(marker)
+		if start+4 <= len(resp) {
+			length := int(binary.LittleEndian.Uint32(resp[start : start+4]))
+			if length > 1 && start+4+length <= len(resp) {
+				version = string(resp[start+4 : start+4+length-1])
+			}
+		}
+	}
+	return "MongoDB", version, true
+}
+
+// mssqlPreLoginPacket builds a TDS PRELOGIN request with the VERSION, ENCRYPTION, INSTOPT and
+// THREADID options
+func mssqlPreLoginPacket() []byte {
+	options := []struct {
+		token byte
+		data  []byte
+	}{
+		{0x00, make([]byte, 6)},
+		{0x01, []byte{0x02}}, // encryption not supported
+		{0x02, []byte{0x00}},
+		{0x03, make([]byte, 4)},
+	}
+
+	offset := len(options)*5 + 1
+	var table, data []byte
+	for _, option := range options {
+		table = append(table, option.token)
+		table = binary.BigEndian.AppendUint16(table, uint16(offset+len(data)))
+		table = binary.BigEndian.AppendUint16(table, uint16(len(option.data)))
+		data = append(data, option.data...)
+	}
+	table = append(table, 0xff)
+	payload := append(table, data...)
+
+	header := []byte{0x12, 0x01}
+	header = binary.BigEndian.AppendUint16(header, uint16(8+len(payload)))
+	header = append(header, 0x00, 0x00, 0x00, 0x00)
+	return append(header, payload...)
+}
+
+func matchMSSQLPreLogin(resp []byte) (string, string, bool) {
+	if len(resp) < 9 || resp[0] != 0x04 {
+		return "", "", false
+	}
+
+	payload := resp[8:]
+	for i := 0; i+5 <= len(payload) && payload[i] != 0xff; i += 5 {
+		if payload[i] != 0x00 {
+			continue
+		}
+		offset := int(binary.BigEndian.Uint16(payload[i+1 : i+3]))
+		length := int(binary.BigEndian.Uint16(payload[i+3 : i+5]))
+		if length >= 4 && offset+4 <= len(payload) {
+			v := payload[offset:]
+			return "Microsoft SQL Server", fmt.Sprintf("%d.%d.%d", v[0], v[1], binary.BigEndian.Uint16(v[2:4])), true
+		}
+	}
+	return "Microsoft SQL Server", "", true
+}
+
+func insertDiscoveredService(scanID string, service DiscoveredService) {
+	if service.Hostname == "" {
+		service.Hostname = resolveHostname(service.IPAddress)
+	}
+
+	query := `INSERT INTO discovered_services (scan_id, ip_address, hostname, port, transport, service, product, version, banner, is_tls, detection_method)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			  ON CONFLICT (scan_id, ip_address, port, transport) DO UPDATE SET
+			  hostname = EXCLUDED.hostname, service = EXCLUDED.service, product = EXCLUDED.product, version = EXCLUDED.version,
+			  banner = EXCLUDED.banner, is_tls = EXCLUDED.is_tls, detection_method = EXCLUDED.detection_method, discovered_at = NOW()`
+
+	_, err := dbPool.Exec(context.Background(), query,
+		scanID, service.IPAddress, service.Hostname, service.Port, service.Transport, service.Service,
+		service.Product, service.Version, service.Banner, service.TLS, service.DetectionMethod)
+	if err != nil {
+		log.Printf("[IP-PORT-SCAN] [ERROR] Failed to insert discovered service: %v", err)
+	}
+}
+
+func GetDiscoveredServices(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scanID := vars["scan_id"]
+
+	if scanID == "" {
+		http.Error(w, "Scan ID is required", http.StatusBadRequest)
+		return
+	}
+
+	query := `SELECT id, scan_id, ip_address, hostname, port, transport, service, product, version, banner,
+			  is_tls, detection_method, discovered_at
+			  FROM discovered_services WHERE scan_id = $1 ORDER BY ip_address, port`
+
+	rows, err := dbPool.Query(context.Background(), query, scanID)
+	if err != nil {
+		log.Printf("[IP-PORT-SCAN] [ERROR] Failed to get discovered services: %v", err)
+		http.Error(w, "Failed to get discovered services", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	services := []DiscoveredService{}
+	for rows.Next() {
+		var service DiscoveredService
+		var ipAddress net.IP
+		var hostname, product, version, banner *string
+
+		err := rows.Scan(&service.ID, &service.ScanID, &ipAddress, &hostname, &service.Port, &service.Transport,
+			&service.Service, &product, &version, &banner, &service.TLS, &service.DetectionMethod, &service.DiscoveredAt)
+		if err != nil {
+			log.Printf("[IP-PORT-SCAN] [ERROR] Error scanning discovered service row: %v", err)
+			continue
+		}
+
+		service.IPAddress = ipAddress.String()
+		if hostname != nil {
+			service.Hostname = *hostname
+		}
+		if product != nil {
+			service.Product = *product
+		}
+		if version != nil {
+			service.Version = *version
+		}
+		if banner != nil {
+			service.Banner = *banner
+		}
+
+		services = append(services, service)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(services)
+}
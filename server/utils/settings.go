@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"log"
+	"strings"
 	"time"
 )
 
@@ -100,6 +101,17 @@ func GetNucleiScreenshotRateLimit() int {
 	return GetRateLimit("nuclei_screenshot")
 }
 
+// GetGauProviders returns the archive providers GAU should query, defaulting to wayback
+func GetGauProviders() []string {
+	var providers sql.NullString
+	err := dbPool.QueryRow(context.Background(), "SELECT gau_providers FROM user_settings LIMIT 1").Scan(&providers)
+	if err != nil {
+		log.Printf("Error fetching GAU providers: %v", err)
+		return []string{"wayback"}
+	}
+	return normalizeGauProviders(strings.Split(providers.String, ","))
+}
+
 // GetCustomHTTPSettings retrieves the custom HTTP settings from the database
 func GetCustomHTTPSettings() (string, string) {
 	var customUserAgent, customHeader sql.NullString
@@ -0,0 +1,536 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	// Used when neither the request nor the auto scan config sets a cap
+	defaultMaxPermutationCandidates = 50000
+	// Learned words are the most frequent tokens in known subdomains
+	maxLearnedPermutationWords = 40
+	// Numeric labels are bumped up and down by this much
+	permutationNumberRange = 2
+)
+
+// Environment tokens are swapped with each other and inserted next to existing labels
+var permutationEnvTokens = []string{
+	"dev", "development", "stg", "stage", "staging", "qa", "uat", "test",
+	"sandbox", "preprod", "prod", "production", "demo", "beta",
+}
+
+// Words commonly found in subdomains, inserted alongside the words learned from the target
+var permutationCommonWords = []string{
+	"api", "admin", "app", "auth", "portal", "internal", "vpn", "mail",
+	"cdn", "static", "old", "new", "backup", "v1", "v2", "web",
+}
+
+var (
+	permutationLabelPattern  = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+	permutationNumberPattern = regexp.MustCompile(`[0-9]+`)
+	permutationTokenPattern  = regexp.MustCompile(`[a-z]{2,}`)
+)
+
+// PermutationScanStatus is a permutation scan. Result holds the candidates that resolved,
+// one per line, and Truncated reports whether the candidate cap was reached.
+type PermutationScanStatus struct {
+	ID                string    `json:"id"`
+	ScanID            string    `json:"scan_id"`
+	Domain            string    `json:"domain"`
+	Status            string    `json:"status"`
+	Result            *string   `json:"result"`
+	Error             *string   `json:"error"`
+	Command           *string   `json:"command"`
+	ExecTime          *string   `json:"execution_time"`
+	CandidateCount    int       `json:"candidate_count"`
+	Truncated         bool      `json:"truncated"`
+	ResolvedCount     int       `json:"resolved_count"`
+	CreatedAt         time.Time `json:"created_at"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	AutoScanSessionID *string   `json:"auto_scan_session_id"`
+}
+
+// permutationSet collects unique candidates in generation order and stops at the cap
+type permutationSet struct {
+	baseDomain string
+	known      map[string]bool
+	seen       map[string]bool
+	candidates []string
+	max        int
+	truncated  bool
+}
+
+func (s *permutationSet) full() bool {
+	return s.max > 0 && len(s.candidates) >= s.max
+}
+
+// add records a candidate given as the labels in front of the base domain
+func (s *permutationSet) add(prefix string) {
+	if s.full() {
+		s.truncated = true
+		return
+	}
+	prefix = strings.Trim(prefix, ".")
+	if prefix == "" {
+		return
+	}
+	for _, label := range strings.Split(prefix, ".") {
+		if len(label) > 63 || !permutationLabelPattern.MatchString(label) {
+			return
+		}
+	}
+	name := prefix + "." + s.baseDomain
+	if len(name) > 253 || s.known[name] || s.seen[name] {
+		return
+	}
+	s.seen[name] = true
+	s.candidates = append(s.candidates, name)
+}
+
+// learnPermutationWords returns the most frequent alphabetic tokens used in known prefixes
+func learnPermutationWords(prefixes []string) []string {
+	counts := make(map[string]int)
+	for _, prefix := range prefixes {
+		for _, token := range permutationTokenPattern.FindAllString(prefix, -1) {
+			counts[token]++
+		}
+	}
+
+	words := make([]string, 0, len(counts))
+	for word := range counts {
+		words = append(words, word)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if counts[words[i]] != counts[words[j]] {
+			return counts[words[i]] > counts[words[j]]
+		}
+		return words[i] < words[j]
+	})
+	if len(words) > maxLearnedPermutationWords {
+		words = words[:maxLearnedPermutationWords]
+	}
+	return words
+}
+
+// numericPermutations bumps each number in a prefix up and down, keeping zero padding
+func numericPermutations(prefix string) []string {
+	var results []string
+	for _, loc := range permutationNumberPattern.FindAllStringIndex(prefix, -1) {
+		digits := prefix[loc[0]:loc[1]]
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			continue
+		}
+		for delta := -permutationNumberRange; delta <= permutationNumberRange; delta++ {
+			if delta == 0 || n+delta < 0 {
+				continue
+			}
+			replacement := fmt.Sprintf("%0*d", len(digits), n+delta)
+			results = append(results, prefix[:loc[0]]+replacement+prefix[loc[1]:])
+		}
+	}
+	return results
+}
+
+// envSwapPermutations replaces environment tokens found in a prefix with every other one
+func envSwapPermutations(prefix string) []string {
+	var results []string
+	parts := strings.FieldsFunc(prefix, func(r rune) bool { return r == '.' || r == '-' })
+	for _, part := range parts {
+		if !containsString(permutationEnvTokens, part) {
+			continue
+		}
+		for _, env := range permutationEnvTokens {
+			if env == part {
+				continue
+			}
+			results = append(results, replaceToken(prefix, part, env))
+		}
+	}
+	return results
+}
+
+// replaceToken swaps whole dash or dot separated tokens only
+func replaceToken(prefix, old, replacement string) string {
+	var b strings.Builder
+	start := 0
+	for i := 0; i <= len(prefix); i++ {
+		if i < len(prefix) && prefix[i] != '.' && prefix[i] != '-' {
+			continue
+		}
+		if prefix[start:i] == old {
+			b.WriteString(replacement)
+		} else {
+			b.WriteString(prefix[start:i])
+		}
+		if i < len(prefix) {
+			b.WriteByte(prefix[i])
+		}
+		start = i + 1
+	}
+	return b.String()
+}
+
+// insertPermutations places a word in front of and after the first label, with both separators,
+// unless the prefix already contains it
+func insertPermutations(prefix, word string) []string {
+	first, rest := prefix, ""
+	if idx := strings.Index(prefix, "."); idx >= 0 {
+		first, rest = prefix[:idx], prefix[idx:]
+	}
+	if containsString(strings.FieldsFunc(prefix, func(r rune) bool { return r == '.' || r == '-' }), word) {
+		return nil
+	}
+	return []string{
+		word + "." + prefix,
+		word + "-" + prefix,
+		first + "-" + word + rest,
+		first + "." + word + rest,
+	}
+}
+
+// separatorPermutations swaps dashes for dots and dots for dashes, one at a time and all at once
+func separatorPermutations(prefix string) []string {
+	var results []string
+	for i := 0; i < len(prefix); i++ {
+		switch prefix[i] {
+		case '-':
+			results = append(results, prefix[:i]+"."+prefix[i+1:])
+		case '.':
+			results = append(results, prefix[:i]+"-"+prefix[i+1:])
+		}
+	}
+	if strings.Count(prefix, "-") > 1 {
+		results = append(results, strings.ReplaceAll(prefix, "-", "."))
+	}
+	if strings.Count(prefix, ".") > 1 {
+		results = append(results, strings.ReplaceAll(prefix, ".", "-"))
+	}
+	return results
+}
+
+// GenerateSubdomainPermutations derives new candidate names from subdomains already found
+// under baseDomain. The cheapest and most productive alterations run first across every
+// known name, so when the cap is hit it is word insertion that gets cut short.
+func GenerateSubdomainPermutations(subdomains []string, baseDomain string, maxCandidates int) ([]string, bool) {
+	baseDomain = strings.ToLower(strings.TrimSuffix(strings.TrimPrefix(baseDomain, "*."), "."))
+	set := &permutationSet{
+		baseDomain: baseDomain,
+		known:      make(map[string]bool),
+		seen:       make(map[string]bool),
+		max:        maxCandidates,
+	}
+
+	var prefixes []string
+	for _, subdomain := range subdomains {
+		name := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(subdomain), "."))
+		set.known[name] = true
+		if strings.HasPrefix(name, "*.") || !strings.HasSuffix(name, "."+baseDomain) {
+			continue
+		}
+		prefixes = append(prefixes, strings.TrimSuffix(name, "."+baseDomain))
+	}
+	sort.Strings(prefixes)
+
+	words := learnPermutationWords(prefixes)
+	for _, word := range append(permutationEnvTokens, permutationCommonWords...) {
+		if !containsString(words, word) {
+			words = append(words, word)
+		}
+	}
+
+	passes := []func(prefix string) []string{
+		separatorPermutations,
+		numericPermutations,
+		envSwapPermutations,
+		func(prefix string) []string {
+			var results []string
+			for _, env := range permutationEnvTokens {
+				results = append(results, insertPermutations(prefix, env)...)
+			}
+			return results
+		},
+		func(prefix string) []string {
+			var results []string
+			for _, word := range words {
+				results = append(results, insertPermutations(prefix, word)...)
+			}
+			return results
+		},
+	}
+	for _, pass := range passes {
+		for _, prefix := range prefixes {
+			for _, candidate := range pass(prefix) {
+				set.add(candidate)
+			}
+			if set.truncated {
+				return set.candidates, true
+			}
+		}
+	}
+	return set.candidates, false
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// getMaxPermutationCandidates reads the candidate cap from the auto scan config
+func getMaxPermutationCandidates() int {
+	var max int
+	err := dbPool.QueryRow(context.Background(), `SELECT max_permutation_candidates FROM auto_scan_config LIMIT 1`).Scan(&max)
+	if err != nil || max <= 0 {
+		return defaultMaxPermutationCandidates
+	}
+	return max
+}
+
+func RunPermutationScan(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		FQDN              string  `json:"fqdn"`
+		MaxCandidates     int     `json:"max_candidates,omitempty"`
+		AutoScanSessionID *string `json:"auto_scan_session_id,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.FQDN == "" {
+		http.Error(w, "Invalid request body. `fqdn` is required.", http.StatusBadRequest)
+		return
+	}
+
+	domain := payload.FQDN
+	var scopeTargetID string
+	err := dbPool.QueryRow(context.Background(),
+		`SELECT id FROM scope_targets WHERE type = 'Wildcard' AND scope_target = $1`,
+		"*."+domain).Scan(&scopeTargetID)
+	if err != nil {
+		log.Printf("[PERMUTATIONS] [ERROR] No matching wildcard scope target found for domain %s", domain)
+		http.Error(w, "No matching wildcard scope target found.", http.StatusBadRequest)
+		return
+	}
+
+	maxCandidates := payload.MaxCandidates
+	if maxCandidates <= 0 {
+		maxCandidates = getMaxPermutationCandidates()
+	}
+
+	scanID := uuid.New().String()
+	var sessionID interface{}
+	if payload.AutoScanSessionID != nil && *payload.AutoScanSessionID != "" {
+		sessionID = *payload.AutoScanSessionID
+	}
+	_, err = dbPool.Exec(context.Background(), `
+		INSERT INTO permutation_scans (scan_id, domain, status, scope_target_id, auto_scan_session_id)
+		VALUES ($1, $2, 'pending', $3, $4)`,
+		scanID, domain, scopeTargetID, sessionID)
+	if err != nil {
+		log.Printf("[PERMUTATIONS] [ERROR] Failed to create scan record: %v", err)
+		http.Error(w, "Failed to create scan record.", http.StatusInternalServerError)
+		return
+	}
+
+	go ExecutePermutationScan(scanID, domain, scopeTargetID, maxCandidates)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"scan_id": scanID})
+}
+
+// ExecutePermutationScan generates candidates from the consolidated subdomains and resolves
+// them with ShuffleDNS in resolve mode
+func ExecutePermutationScan(scanID, domain, scopeTargetID string, maxCandidates int) {
+	log.Printf("[PERMUTATIONS] [INFO] Starting permutation scan for %s (scan ID: %s)", domain, scanID)
+	startTime := time.Now()
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT subdomain FROM consolidated_subdomains WHERE scope_target_id = $1 AND NOT COALESCE(is_wildcard, false)`,
+		scopeTargetID)
+	if err != nil {
+		log.Printf("[PERMUTATIONS] [ERROR] Failed to get consolidated subdomains: %v", err)
+		updatePermutationScanStatus(scanID, "error", "", fmt.Sprintf("Failed to get consolidated subdomains: %v", err), "", time.Since(startTime).String())
+		return
+	}
+	var subdomains []string
+	for rows.Next() {
+		var subdomain string
+		if err := rows.Scan(&subdomain); err == nil {
+			subdomains = append(subdomains, subdomain)
+		}
+	}
+	rows.Close()
+
+	if len(subdomains) == 0 {
+		log.Printf("[PERMUTATIONS] [WARN] No consolidated subdomains to learn from for %s", domain)
+		updatePermutationScanStatus(scanID, "completed", "", "No consolidated subdomains to generate permutations from", "", time.Since(startTime).String())
+		return
+	}
+
+	candidates, truncated := GenerateSubdomainPermutations(subdomains, domain, maxCandidates)
+	log.Printf("[PERMUTATIONS] [INFO] Generated %d candidates from %d subdomains (truncated: %v)", len(candidates), len(subdomains), truncated)
+	if _, err := dbPool.Exec(context.Background(),
+		`UPDATE permutation_scans SET status = 'processing', candidate_count = $1, truncated = $2 WHERE scan_id = $3`,
+		len(candidates), truncated, scanID); err != nil {
+		log.Printf("[PERMUTATIONS] [ERROR] Failed to record candidate count: %v", err)
+	}
+	if len(candidates) == 0 {
+		updatePermutationScanStatus(scanID, "completed", "", "No new candidates generated", "", time.Since(startTime).String())
+		return
+	}
+
+	tempDir, err := os.MkdirTemp("", "permutations-")
+	if err != nil {
+		updatePermutationScanStatus(scanID, "error", "", fmt.Sprintf("Failed to create temp directory: %v", err), "", time.Since(startTime).String())
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	candidateFile := filepath.Join(tempDir, "candidates.txt")
+	if err := os.WriteFile(candidateFile, []byte(strings.Join(candidates, "\n")), 0644); err != nil {
+		updatePermutationScanStatus(scanID, "error", "", fmt.Sprintf("Failed to write candidate file: %v", err), "", time.Since(startTime).String())
+		return
+	}
+
+	containerFile := fmt.Sprintf("/tmp/permutations-%s.txt", scanID)
+	copyCmd := exec.Command("docker", "cp", candidateFile, "ars0n-framework-v2-shuffledns-1:"+containerFile)
+	if output, err := copyCmd.CombinedOutput(); err != nil {
+		log.Printf("[PERMUTATIONS] [ERROR] Failed to copy candidates to ShuffleDNS container: %v", err)
+		updatePermutationScanStatus(scanID, "error", "", fmt.Sprintf("Failed to copy candidates to container: %v %s", err, output), "", time.Since(startTime).String())
+		return
+	}
+	defer exec.Command("docker", "exec", "ars0n-framework-v2-shuffledns-1", "rm", "-f", containerFile).Run()
+
+	cmd := exec.Command(
+		"docker", "exec",
+		"ars0n-framework-v2-shuffledns-1",
+		"shuffledns",
+		"-d", domain,
+		"-list", containerFile,
+		"-r", "/app/wordlists/resolvers.txt",
+		"-silent",
+		"-massdns", "/usr/local/bin/massdns",
+		"-t", fmt.Sprintf("%d", GetShuffleDNSRateLimit()),
+		"-mode", "resolve",
+	)
+	log.Printf("[PERMUTATIONS] [INFO] Executing command: %s", cmd.String())
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	execTime := time.Since(startTime).String()
+
+	if err != nil {
+		log.Printf("[PERMUTATIONS] [ERROR] ShuffleDNS resolve failed: %v (%s)", err, stderr.String())
+		updatePermutationScanStatus(scanID, "error", "", stderr.String(), cmd.String(), execTime)
+		return
+	}
+
+	result := strings.TrimSpace(stdout.String())
+	if result == "" {
+		log.Printf("[PERMUTATIONS] [INFO] None of the %d candidates resolved", len(candidates))
+		updatePermutationScanStatus(scanID, "completed", "", "No candidates resolved", cmd.String(), execTime)
+		return
+	}
+
+	log.Printf("[PERMUTATIONS] [INFO] %d candidates resolved in %s", len(strings.Split(result, "\n")), execTime)
+	updatePermutationScanStatus(scanID, "success", result, stderr.String(), cmd.String(), execTime)
+}
+
+func updatePermutationScanStatus(scanID, status, result, stderr, command, execTime string) {
+	_, err := dbPool.Exec(context.Background(), `
+		UPDATE permutation_scans
+		SET status = $1, result = NULLIF($2, ''), error = NULLIF($3, ''), command = NULLIF($4, ''), execution_time = $5
+		WHERE scan_id = $6`,
+		status, result, stderr, command, execTime, scanID)
+	if err != nil {
+		log.Printf("[PERMUTATIONS] [ERROR] Failed to update scan status for %s: %v", scanID, err)
+	}
+}
+
+const permutationScanColumns = `id, scan_id, domain, status, result, error, command, execution_time,
+	candidate_count, truncated, created_at, scope_target_id, auto_scan_session_id`
+
+func scanPermutationScan(row interface{ Scan(...interface{}) error }) (PermutationScanStatus, error) {
+	var scan PermutationScanStatus
+	err := row.Scan(
+		&scan.ID,
+		&scan.ScanID,
+		&scan.Domain,
+		&scan.Status,
+		&scan.Result,
+		&scan.Error,
+		&scan.Command,
+		&scan.ExecTime,
+		&scan.CandidateCount,
+		&scan.Truncated,
+		&scan.CreatedAt,
+		&scan.ScopeTargetID,
+		&scan.AutoScanSessionID,
+	)
+	if err == nil && scan.Result != nil && *scan.Result != "" {
+		scan.ResolvedCount = len(strings.Split(*scan.Result, "\n"))
+	}
+	return scan, err
+}
+
+func GetPermutationScanStatus(w http.ResponseWriter, r *http.Request) {
+	scanID := mux.Vars(r)["scan_id"]
+	row := dbPool.QueryRow(context.Background(), `SELECT `+permutationScanColumns+` FROM permutation_scans WHERE scan_id = $1`, scanID)
+	scan, err := scanPermutationScan(row)
+	if err != nil {
+		http.Error(w, "Scan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scan)
+}
+
+func GetPermutationScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if scopeTargetID == "" {
+		http.Error(w, "Scope target ID is required", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT `+permutationScanColumns+` FROM permutation_scans WHERE scope_target_id = $1 ORDER BY created_at DESC`,
+		scopeTargetID)
+	if err != nil {
+		log.Printf("[PERMUTATIONS] [ERROR] Failed to get scans: %v", err)
+		http.Error(w, "Failed to get scans", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	scans := []PermutationScanStatus{}
+	for rows.Next() {
+		scan, err := scanPermutationScan(rows)
+		if err != nil {
+			log.Printf("[PERMUTATIONS] [ERROR] Failed to scan row: %v", err)
+			continue
+		}
+		scans = append(scans, scan)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scans)
+}
@@ -431,13 +431,7 @@ func ExecuteAndParseAssetfinderScan(scanID, domain string) {
 	log.Printf("[INFO] Starting Assetfinder scan for domain %s (scan ID: %s)", domain, scanID)
 	startTime := time.Now()
 
-	cmd := exec.Command(
-		"docker", "exec",
-		"ars0n-framework-v2-assetfinder-1",
-		"assetfinder",
-		"--subs-only",
-		domain,
-	)
+	cmd := assetfinderCommand(domain)
 
 	log.Printf("[INFO] Executing command: %s", cmd.String())
 
@@ -470,6 +464,16 @@ func ExecuteAndParseAssetfinderScan(scanID, domain string) {
 	log.Printf("[INFO] Scan status updated for scan %s", scanID)
 }
 
+func assetfinderCommand(domain string) *exec.Cmd {
+	return exec.Command(
+		"docker", "exec",
+		"ars0n-framework-v2-assetfinder-1",
+		"assetfinder",
+		"--subs-only",
+		domain,
+	)
+}
+
 func UpdateAssetfinderScanStatus(scanID, status, result, stderr, command, execTime string) {
 	log.Printf("[INFO] Updating Assetfinder scan status for %s to %s", scanID, status)
 	query := `UPDATE assetfinder_scans SET status = $1, result = $2, stderr = $3, command = $4, execution_time = $5 WHERE scan_id = $6`
@@ -599,14 +603,21 @@ func GetAssetfinderScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
 
 func RunGauScan(w http.ResponseWriter, r *http.Request) {
 	var payload struct {
-		FQDN              string  `json:"fqdn" binding:"required"`
-		AutoScanSessionID *string `json:"auto_scan_session_id,omitempty"`
+		FQDN              string   `json:"fqdn" binding:"required"`
+		Providers         []string `json:"providers,omitempty"`
+		AutoScanSessionID *string  `json:"auto_scan_session_id,omitempty"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.FQDN == "" {
 		http.Error(w, "Invalid request body. `fqdn` is required.", http.StatusBadRequest)
 		return
 	}
 
+	// Providers in the request override the ones saved in settings
+	providers := GetGauProviders()
+	if len(payload.Providers) > 0 {
+		providers = normalizeGauProviders(payload.Providers)
+	}
+
 	domain := payload.FQDN
 	wildcardDomain := fmt.Sprintf("*.%s", domain)
 
@@ -637,14 +648,14 @@ func RunGauScan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	go ExecuteAndParseGauScan(scanID, domain)
+	go ExecuteAndParseGauScan(scanID, domain, providers)
 
 	w.WriteHeader(http.StatusAccepted)
 	json.NewEncoder(w).Encode(map[string]string{"scan_id": scanID})
 }
 
-func ExecuteAndParseGauScan(scanID, domain string) {
-	log.Printf("[INFO] Starting GAU scan for domain %s (scan ID: %s)", domain, scanID)
+func ExecuteAndParseGauScan(scanID, domain string, providers []string) {
+	log.Printf("[INFO] Starting GAU scan for domain %s with providers %s (scan ID: %s)", domain, strings.Join(providers, ","), scanID)
 	startTime := time.Now()
 
 	// Get rate limit and custom HTTP settings
@@ -658,7 +669,7 @@ func ExecuteAndParseGauScan(scanID, domain string) {
 		"docker", "run", "--rm",
 		"sxcurity/gau:latest",
 		domain,
-		"--providers", "wayback",
+		"--providers", strings.Join(providers, ","),
 		"--json",
 		"--verbose",
 		"--subs",
@@ -727,6 +738,9 @@ func ExecuteAndParseGauScan(scanID, domain string) {
 		lineCount := len(lines)
 		log.Printf("[INFO] GAU scan found %d URLs for domain %s", lineCount, domain)
 
+		// Keep the full URL list in the corpus before large results are reduced below
+		storeGauURLCorpus(scanID, lines)
+
 		// Check if results exceed 1000 URLs
 		if lineCount > 1000 {
 			log.Printf("[INFO] Results exceed 1000 URLs, setting status to 'processing' while reducing to unique subdomains")
@@ -994,22 +1008,40 @@ func ExecuteAndParseCTLScan(scanID, domain string) {
 	log.Printf("[INFO] Starting CTL scan execution for domain %s (scan ID: %s)", domain, scanID)
 	startTime := time.Now()
 
+	url := ctlQueryURL(domain)
+	subdomains, err := fetchCTLSubdomains(domain)
+	if err != nil {
+		log.Printf("[ERROR] %v", err)
+		UpdateCTLScanStatus(scanID, "error", "", err.Error(), "", time.Since(startTime).String())
+		return
+	}
+
+	// Join results with newlines
+	result := strings.Join(subdomains, "\n")
+	log.Printf("[DEBUG] Final processed result length: %d bytes", len(result))
+
+	UpdateCTLScanStatus(scanID, "success", result, "", fmt.Sprintf("GET %s", url), time.Since(startTime).String())
+	log.Printf("[INFO] CTL scan completed and results stored successfully for domain %s", domain)
+}
+
+func ctlQueryURL(domain string) string {
+	return fmt.Sprintf("https://crt.sh/?q=%%.%s&output=json", domain)
+}
+
+// fetchCTLSubdomains queries crt.sh for certificates issued under domain and returns the
+// sorted, deduplicated names
+func fetchCTLSubdomains(domain string) ([]string, error) {
 	// Make HTTP request to crt.sh
-	url := fmt.Sprintf("https://crt.sh/?q=%%.%s&output=json", domain)
 	client := &http.Client{Timeout: 30 * time.Second}
 
-	resp, err := client.Get(url)
+	resp, err := client.Get(ctlQueryURL(domain))
 	if err != nil {
-		log.Printf("[ERROR] Failed to make request to crt.sh: %v", err)
-		UpdateCTLScanStatus(scanID, "error", "", fmt.Sprintf("Failed to make request to crt.sh: %v", err), "", time.Since(startTime).String())
-		return
+		return nil, fmt.Errorf("Failed to make request to crt.sh: %v", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("[ERROR] crt.sh returned non-200 status code: %d", resp.StatusCode)
-		UpdateCTLScanStatus(scanID, "error", "", fmt.Sprintf("crt.sh returned status code: %d", resp.StatusCode), "", time.Since(startTime).String())
-		return
+		return nil, fmt.Errorf("crt.sh returned status code: %d", resp.StatusCode)
 	}
 
 	var results []struct {
@@ -1017,9 +1049,7 @@ func ExecuteAndParseCTLScan(scanID, domain string) {
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
-		log.Printf("[ERROR] Failed to decode crt.sh response: %v", err)
-		UpdateCTLScanStatus(scanID, "error", "", fmt.Sprintf("Failed to decode crt.sh response: %v", err), "", time.Since(startTime).String())
-		return
+		return nil, fmt.Errorf("Failed to decode crt.sh response: %v", err)
 	}
 
 	// Process and deduplicate results
@@ -1038,13 +1068,7 @@ func ExecuteAndParseCTLScan(scanID, domain string) {
 		subdomains = append(subdomains, subdomain)
 	}
 	sort.Strings(subdomains)
-
-	// Join results with newlines
-	result := strings.Join(subdomains, "\n")
-	log.Printf("[DEBUG] Final processed result length: %d bytes", len(result))
-
-	UpdateCTLScanStatus(scanID, "success", result, "", fmt.Sprintf("GET %s", url), time.Since(startTime).String())
-	log.Printf("[INFO] CTL scan completed and results stored successfully for domain %s", domain)
+	return subdomains, nil
 }
 
 func UpdateCTLScanStatus(scanID, status, result, stderr, command, execTime string) {
@@ -1237,13 +1261,7 @@ func ExecuteAndParseSubfinderScan(scanID, domain string) {
 	log.Printf("[INFO] Starting Subfinder scan for domain %s (scan ID: %s)", domain, scanID)
 	startTime := time.Now()
 
-	cmd := exec.Command(
-		"docker", "exec",
-		"ars0n-framework-v2-subfinder-1",
-		"subfinder",
-		"-d", domain,
-		"-silent",
-	)
+	cmd := subfinderCommand(domain)
 
 	log.Printf("[INFO] Executing command: %s", cmd.String())
 
@@ -1276,6 +1294,16 @@ func ExecuteAndParseSubfinderScan(scanID, domain string) {
 	log.Printf("[INFO] Scan status updated for scan %s", scanID)
 }
 
+func subfinderCommand(domain string) *exec.Cmd {
+	return exec.Command(
+		"docker", "exec",
+		"ars0n-framework-v2-subfinder-1",
+		"subfinder",
+		"-d", domain,
+		"-silent",
+	)
+}
+
 func UpdateSubfinderScanStatus(scanID, status, result, stderr, command, execTime string) {
 	log.Printf("[INFO] Updating Subfinder scan status for %s to %s", scanID, status)
 	query := `UPDATE subfinder_scans SET status = $1, result = $2, stderr = $3, command = $4, execution_time = $5 WHERE scan_id = $6`
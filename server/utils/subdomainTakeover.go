@@ -0,0 +1,691 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ars0n-framework-v2-server/dnsresolver"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+const (
+	takeoverCheckWorkers = 20
+	takeoverBodyLimit    = 512 * 1024
+	takeoverReference    = "https://github.com/EdOverflow/can-i-take-over-xyz"
+)
+
+type SubdomainTakeoverScan struct {
+	ID                string    `json:"id"`
+	ScanID            string    `json:"scan_id"`
+	ScopeTargetID     string    `json:"scope_target_id"`
+	Status            string    `json:"status"`
+	CandidatesChecked int       `json:"candidates_checked"`
+	VulnerableCount   int       `json:"vulnerable_count"`
+	Error             *string   `json:"error"`
+	ExecTime          *string   `json:"execution_time"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// SubdomainTakeoverFinding is a dangling CNAME found on an FQDN. Status is "vulnerable" when
+// a service fingerprint matched and "potential" when the CNAME target simply no longer exists.
+type SubdomainTakeoverFinding struct {
+	ID               string    `json:"id"`
+	ScanID           string    `json:"scan_id"`
+	ScopeTargetID    string    `json:"scope_target_id"`
+	FQDN             string    `json:"fqdn"`
+	CNAME            string    `json:"cname"`
+	Service          string    `json:"service"`
+	Status           string    `json:"status"`
+	Severity         string    `json:"severity"`
+	NXDomain         bool      `json:"nxdomain"`
+	HTTPStatus       *int      `json:"http_status,omitempty"`
+	MatchedSignature *string   `json:"matched_signature,omitempty"`
+	LastChecked      time.Time `json:"last_checked"`
+}
+
+type takeoverFingerprint struct {
+	Service string
+	// CNAME targets handled by the service
+	CNAME *regexp.Regexp
+	// Response body fragments served for unclaimed resources
+	Signatures []string
+	// The service releases names that no longer resolve, so NXDOMAIN alone is enough
+	NXDomain bool
+}
+
+// Services known to allow claiming a name whose resource was deleted. Signatures follow
+// the responses documented by can-i-take-over-xyz.
+var takeoverFingerprints = []takeoverFingerprint{
+	{
+		Service:    "AWS S3",
+		CNAME:      regexp.MustCompile(`(^|\.)s3([.-][a-z0-9-]+)*\.amazonaws\.com$`),
+		Signatures: []string{"NoSuchBucket", "The specified bucket does not exist"},
+	},
+	{
+		Service:  "AWS Elastic Beanstalk",
+		CNAME:    regexp.MustCompile(`\.elasticbeanstalk\.com$`),
+		NXDomain: true,
+	},
+	{
+		Service:    "GitHub Pages",
+		CNAME:      regexp.MustCompile(`\.github\.io$`),
+		Signatures: []string{"There isn't a GitHub Pages site here."},
+	},
+	{
+		Service:    "Heroku",
+		CNAME:      regexp.MustCompile(`\.(herokuapp|herokudns|herokussl)\.com$`),
+		Signatures: []string{"No such app", "herokucdn.com/error-pages/no-such-app.html"},
+	},
+	{
+		Service:  "Microsoft Azure",
+		CNAME:    regexp.MustCompile(`\.(azurewebsites\.net|cloudapp\.net|cloudapp\.azure\.com|trafficmanager\.net|blob\.core\.windows\.net|azure-api\.net|azureedge\.net|azurefd\.net|azurecontainer\.io|azurehdinsight\.net|redis\.cache\.windows\.net|search\.windows\.net|servicebus\.windows\.net|database\.windows\.net|visualstudio\.com)$`),
+		NXDomain: true,
+	},
+	{
+		Service:    "Fastly",
+		CNAME:      regexp.MustCompile(`\.fastly(lb)?\.net$`),
+		Signatures: []string{"Fastly error: unknown domain"},
+	},
+	{
+		Service:    "Google Cloud Storage",
+		CNAME:      regexp.MustCompile(`(^|\.)c\.storage\.googleapis\.com$`),
+		Signatures: []string{"NoSuchBucket", "The specified bucket does not exist"},
+	},
+	{
+		Service:    "Shopify",
+		CNAME:      regexp.MustCompile(`\.myshopify\.com$`),
+		Signatures: []string{"Sorry, this shop is currently unavailable."},
+	},
+	{
+		Service:    "Pantheon",
+		CNAME:      regexp.MustCompile(`\.pantheonsite\.io$`),
+		Signatures: []string{"The gods are wise, but do not know of the site which you seek."},
+	},
+	{
+		Service:    "Zendesk",
+		CNAME:      regexp.MustCompile(`\.zendesk\.com$`),
+		Signatures: []string{"Help Center Closed"},
+	},
+	{
+		Service:    "Ghost",
+		CNAME:      regexp.MustCompile(`\.ghost\.io$`),
+		Signatures: []string{"Site unavailable.&#124;Failed to resolve DNS path for this host", "The thing you were looking for is no longer here, or never was"},
+	},
+	{
+		Service:    "Tumblr",
+		CNAME:      regexp.MustCompile(`(^|\.)domains\.tumblr\.com$`),
+		Signatures: []string{"Whatever you were looking for doesn't currently exist at this address"},
+	},
+	{
+		Service:    "Surge.sh",
+		CNAME:      regexp.MustCompile(`\.surge\.sh$`),
+		Signatures: []string{"project not found"},
+	},
+	{
+		Service:    "Bitbucket",
+		CNAME:      regexp.MustCompile(`\.bitbucket\.io$`),
+		Signatures: []string{"Repository not found"},
+	},
+	{
+		Service:    "Webflow",
+		CNAME:      regexp.MustCompile(`(^|\.)proxy(-ssl)?\.webflow\.com$`),
+		Signatures: []string{"The page you are looking for doesn't exist or has been moved."},
+	},
+	{
+		Service:    "Netlify",
+		CNAME:      regexp.MustCompile(`\.netlify\.(app|com)$`),
+		Signatures: []string{"Not Found - Request ID:"},
+	},
+	{
+		Service:    "Help Scout",
+		CNAME:      regexp.MustCompile(`\.helpscoutdocs\.com$`),
+		Signatures: []string{"No settings were found for this company:"},
+	},
+	{
+		Service:    "Readme.io",
+		CNAME:      regexp.MustCompile(`\.readme\.io$`),
+		Signatures: []string{"The creators of this project are still working on making everything perfect!", "Project doesnt exist... yet!"},
+	},
+	{
+		Service:    "Strikingly",
+		CNAME:      regexp.MustCompile(`\.strikinglydns\.com$`),
+		Signatures: []string{"But if you're looking to build your own website"},
+	},
+	{
+		Service:    "WordPress.com",
+		CNAME:      regexp.MustCompile(`\.wordpress\.com$`),
+		Signatures: []string{"Do you want to register"},
+	},
+}
+
+type takeoverCandidate struct {
+	FQDN  string
+	CNAME string
+}
+
+// takeoverResolver is the subset of lookups the checker needs, satisfied by the shared
+// resolver pool. Raw answers are needed to tell a dangling chain from a deleted record.
+type takeoverResolver interface {
+	Query(ctx context.Context, name string, qtype uint16) (*dnsresolver.Message, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+type takeoverChecker struct {
+	resolver     takeoverResolver
+	client       *http.Client
+	fingerprints []takeoverFingerprint
+	timeout      time.Duration
+}
+
+func newTakeoverChecker() *takeoverChecker {
+	return &takeoverChecker{
+		resolver: SharedDNSPool(),
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 3 {
+					return http.ErrUseLastResponse
+				}
+				return nil
+			},
+		},
+		fingerprints: takeoverFingerprints,
+		timeout:      5 * time.Second,
+	}
+}
+
+func normalizeDNSName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(name), "."))
+}
+
+func (c *takeoverChecker) matchFingerprint(targets ...string) *takeoverFingerprint {
+	for i := range c.fingerprints {
+		for _, target := range targets {
+			if target != "" && c.fingerprints[i].CNAME.MatchString(target) {
+				return &c.fingerprints[i]
+			}
+		}
+	}
+	return nil
+}
+
+// isNXDomain reports whether a name definitively does not exist. Timeouts and
+// SERVFAIL are not treated as NXDOMAIN.
+func (c *takeoverChecker) isNXDomain(name string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	_, err := c.resolver.LookupHost(ctx, name+".")
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// fetchBody requests the FQDN over HTTPS and then HTTP, returning the first response
+func (c *takeoverChecker) fetchBody(fqdn string) (int, string, bool) {
+	for _, scheme := range []string{"https", "http"} {
+		resp, err := c.client.Get(scheme + "://" + fqdn + "/")
+		if err != nil {
+			continue
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, takeoverBodyLimit))
+		resp.Body.Close()
+		if err != nil {
+			continue
+		}
+		return resp.StatusCode, string(body), true
+	}
+	return 0, "", false
+}
+
+// liveCNAME returns the target of the CNAME record the FQDN holds right now, or an empty
+// string when the name no longer exists, no longer has a CNAME or can't be resolved
+func (c *takeoverChecker) liveCNAME(fqdn string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	msg, err := c.resolver.Query(ctx, fqdn, dnsresolver.TypeCNAME)
+	if err != nil || msg.Rcode != dnsresolver.RcodeSuccess {
+		return ""
+	}
+	for _, rr := range msg.Answers {
+		if rr.Type == dnsresolver.TypeCNAME && normalizeDNSName(rr.Name) == fqdn {
+			return normalizeDNSName(rr.Target)
+		}
+	}
+	return ""
+}
+
+// chainEnd follows the CNAME chain starting at the FQDN to its last name. Answers are read
+// even from NXDOMAIN responses, which carry the chain up to the name that doesn't exist.
+func (c *takeoverChecker) chainEnd(fqdn, target string) string {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	msg, err := c.resolver.Query(ctx, fqdn, dnsresolver.TypeA)
+	if err != nil {
+		return target
+	}
+	canonical := fqdn
+	for hop := 0; hop < 16; hop++ {
+		next := ""
+		for _, rr := range msg.Answers {
+			if rr.Type == dnsresolver.TypeCNAME && normalizeDNSName(rr.Name) == canonical {
+				next = normalizeDNSName(rr.Target)
+				break
+			}
+		}
+		if next == "" {
+			break
+		}
+		canonical = next
+	}
+	if canonical == fqdn {
+		return target
+	}
+	return canonical
+}
+
+// check decides whether a CNAME candidate can be taken over. The FQDN must still hold a
+// CNAME record, so names deleted or repointed at an address since the original scan are
+// skipped. The chain behind it is then followed, and a target that fails to resolve is
+// what a dangling record looks like.
+func (c *takeoverChecker) check(candidate takeoverCandidate) *SubdomainTakeoverFinding {
+	fqdn := normalizeDNSName(candidate.FQDN)
+	if fqdn == "" || normalizeDNSName(candidate.CNAME) == "" {
+		return nil
+	}
+
+	target := c.liveCNAME(fqdn)
+	if target == "" || target == fqdn {
+		return nil
+	}
+	finalTarget := c.chainEnd(fqdn, target)
+
+	finding := &SubdomainTakeoverFinding{
+		FQDN:        fqdn,
+		CNAME:       target,
+		LastChecked: time.Now(),
+	}
+	finding.NXDomain = c.isNXDomain(finalTarget)
+	if !finding.NXDomain && finalTarget != target {
+		finding.NXDomain = c.isNXDomain(target)
+	}
+
+	fingerprint := c.matchFingerprint(target, finalTarget)
+	if fingerprint != nil {
+		finding.Service = fingerprint.Service
+
+		if fingerprint.NXDomain && finding.NXDomain {
+			signature := "NXDOMAIN"
+			finding.Status = "vulnerable"
+			finding.Severity = "high"
+			finding.MatchedSignature = &signature
+			return finding
+		}
+
+		if len(fingerprint.Signatures) > 0 && !finding.NXDomain {
+			statusCode, body, ok := c.fetchBody(fqdn)
+			if !ok {
+				return nil
+			}
+			for _, signature := range fingerprint.Signatures {
+				if strings.Contains(body, signature) {
+					matched := signature
+					finding.Status = "vulnerable"
+					finding.Severity = "high"
+					finding.HTTPStatus = &statusCode
+					finding.MatchedSignature = &matched
+					return finding
+				}
+			}
+			return nil
+		}
+	}
+
+	// A CNAME pointing at a name that no longer exists is worth a look even without a
+	// service fingerprint, since the target domain may be registrable
+	if finding.NXDomain {
+		signature := "NXDOMAIN"
+		if finding.Service == "" {
+			finding.Service = "Unknown"
+		}
+		finding.Status = "potential"
+		finding.Severity = "medium"
+		finding.MatchedSignature = &signature
+		return finding
+	}
+
+	return nil
+}
+
+var takeoverSlugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// takeoverTemplateID returns the finding template ID for a service, e.g. subdomain-takeover-aws-s3
+func takeoverTemplateID(service string) string {
+	return "subdomain-takeover-" + strings.Trim(takeoverSlugPattern.ReplaceAllString(strings.ToLower(service), "-"), "-")
+}
+
+var amassCNAMEPattern = regexp.MustCompile(`^\s*(\S+)\s+\(FQDN\)\s+-->\s+cname_record\s+-->\s+(\S+)\s+\(FQDN\)`)
+
+// collectTakeoverCandidates gathers every known FQDN to CNAME pair for a scope target
+func collectTakeoverCandidates(scopeTargetID string) ([]takeoverCandidate, error) {
+	seen := make(map[string]bool)
+	var candidates []takeoverCandidate
+	add := func(fqdn, cname string) {
+		fqdn = normalizeDNSName(fqdn)
+		cname = normalizeDNSName(cname)
+		if fqdn == "" || cname == "" || fqdn == cname {
+			return
+		}
+		key := fqdn + "|" + cname
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		candidates = append(candidates, takeoverCandidate{FQDN: fqdn, CNAME: cname})
+	}
+
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT root_domain, record
+		FROM dnsx_company_dns_records
+		WHERE scope_target_id = $1 AND record_type = 'CNAME'`, scopeTargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dnsx CNAME records: %v", err)
+	}
+	for rows.Next() {
+		var fqdn, cname string
+		if err := rows.Scan(&fqdn, &cname); err == nil {
+			add(fqdn, cname)
+		}
+	}
+	rows.Close()
+
+	rows, err = dbPool.Query(context.Background(), `
+		SELECT dr.record
+		FROM dns_records dr
+		JOIN amass_scans a ON dr.scan_id = a.scan_id
+		WHERE a.scope_target_id = $1 AND dr.record_type = 'CNAME'`, scopeTargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get amass CNAME records: %v", err)
+	}
+	for rows.Next() {
+		var record string
+		if err := rows.Scan(&record); err != nil {
+			continue
+		}
+		if matches := amassCNAMEPattern.FindStringSubmatch(record); len(matches) > 2 {
+			add(matches[1], matches[2])
+		}
+	}
+	rows.Close()
+
+	// target_urls stores CNAMEs as "host -> target"
+	rows, err = dbPool.Query(context.Background(), `
+		SELECT unnest(dns_cname_records)
+		FROM target_urls
+		WHERE scope_target_id = $1 AND dns_cname_records IS NOT NULL`, scopeTargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target URL CNAME records: %v", err)
+	}
+	for rows.Next() {
+		var record string
+		if err := rows.Scan(&record); err != nil {
+			continue
+		}
+		if parts := strings.SplitN(record, " -> ", 2); len(parts) == 2 {
+			add(parts[0], parts[1])
+		}
+	}
+	rows.Close()
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].FQDN != candidates[j].FQDN {
+			return candidates[i].FQDN < candidates[j].FQDN
+		}
+		return candidates[i].CNAME < candidates[j].CNAME
+	})
+	return candidates, nil
+}
+
+func RunSubdomainTakeoverScan(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ScopeTargetID string `json:"scope_target_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ScopeTargetID == "" {
+		http.Error(w, "Invalid request body. `scope_target_id` is required.", http.StatusBadRequest)
+		return
+	}
+
+	scanID := uuid.New().String()
+	_, err := dbPool.Exec(context.Background(),
+		`INSERT INTO subdomain_takeover_scans (scan_id, scope_target_id, status) VALUES ($1, $2, $3)`,
+		scanID, payload.ScopeTargetID, "pending")
+	if err != nil {
+		log.Printf("[TAKEOVER] [ERROR] Failed to create scan record: %v", err)
+		http.Error(w, "Failed to create scan record.", http.StatusInternalServerError)
+		return
+	}
+
+	go ExecuteSubdomainTakeoverScan(scanID, payload.ScopeTargetID)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"scan_id": scanID})
+}
+
+func ExecuteSubdomainTakeoverScan(scanID, scopeTargetID string) {
+	log.Printf("[TAKEOVER] [INFO] Starting subdomain takeover scan for scope target %s (scan ID: %s)", scopeTargetID, scanID)
+	startTime := time.Now()
+	updateSubdomainTakeoverScanStatus(scanID, "running", 0, 0, "", "")
+
+	candidates, err := collectTakeoverCandidates(scopeTargetID)
+	if err != nil {
+		log.Printf("[TAKEOVER] [ERROR] %v", err)
+		updateSubdomainTakeoverScanStatus(scanID, "error", 0, 0, err.Error(), time.Since(startTime).String())
+		return
+	}
+	log.Printf("[TAKEOVER] [INFO] Checking %d CNAME records", len(candidates))
+
+	checker := newTakeoverChecker()
+	var findings []*SubdomainTakeoverFinding
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, takeoverCheckWorkers)
+
+	for _, candidate := range candidates {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(candidate takeoverCandidate) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if finding := checker.check(candidate); finding != nil {
+				log.Printf("[TAKEOVER] [INFO] %s takeover on %s -> %s (%s)", finding.Status, finding.FQDN, finding.CNAME, finding.Service)
+				mu.Lock()
+				findings = append(findings, finding)
+				mu.Unlock()
+			}
+		}(candidate)
+	}
+	wg.Wait()
+
+	if err := saveSubdomainTakeoverFindings(scanID, scopeTargetID, findings); err != nil {
+		log.Printf("[TAKEOVER] [ERROR] Failed to save findings: %v", err)
+		updateSubdomainTakeoverScanStatus(scanID, "error", len(candidates), 0, err.Error(), time.Since(startTime).String())
+		return
+	}
+
+	vulnerable := 0
+	for _, finding := range findings {
+		if finding.Status == "vulnerable" {
+			vulnerable++
+		}
+	}
+
+	updateSubdomainTakeoverScanStatus(scanID, "success", len(candidates), vulnerable, "", time.Since(startTime).String())
+	log.Printf("[TAKEOVER] [INFO] Scan %s completed: %d candidates, %d vulnerable, %d potential", scanID, len(candidates), vulnerable, len(findings)-vulnerable)
+}
+
+// saveSubdomainTakeoverFindings replaces the scope target's findings with the latest results
+func saveSubdomainTakeoverFindings(scanID, scopeTargetID string, findings []*SubdomainTakeoverFinding) error {
+	tx, err := dbPool.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(), `DELETE FROM subdomain_takeover_findings WHERE scope_target_id = $1`, scopeTargetID); err != nil {
+		return fmt.Errorf("failed to delete old findings: %v", err)
+	}
+
+	for _, f := range findings {
+		_, err := tx.Exec(context.Background(), `
+			INSERT INTO subdomain_takeover_findings
+				(scan_id, scope_target_id, fqdn, cname, service, status, severity, nxdomain,
+				 http_status, matched_signature, last_checked)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			ON CONFLICT (scope_target_id, fqdn, cname) DO NOTHING`,
+			scanID, scopeTargetID, f.FQDN, f.CNAME, f.Service, f.Status, f.Severity, f.NXDomain,
+			f.HTTPStatus, f.MatchedSignature, f.LastChecked)
+		if err != nil {
+			return fmt.Errorf("failed to insert finding for %s: %v", f.FQDN, err)
+		}
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func updateSubdomainTakeoverScanStatus(scanID, status string, checked, vulnerable int, errorMessage, execTime string) {
+	_, err := dbPool.Exec(context.Background(), `
+		UPDATE subdomain_takeover_scans
+		SET status = $1, candidates_checked = $2, vulnerable_count = $3,
+		    error = NULLIF($4, ''), execution_time = NULLIF($5, '')
+		WHERE scan_id = $6`,
+		status, checked, vulnerable, errorMessage, execTime, scanID)
+	if err != nil {
+		log.Printf("[TAKEOVER] [ERROR] Failed to update scan status: %v", err)
+	}
+}
+
+func scanSubdomainTakeoverScan(row interface{ Scan(...interface{}) error }) (SubdomainTakeoverScan, error) {
+	var scan SubdomainTakeoverScan
+	var errorMessage, execTime sql.NullString
+	err := row.Scan(&scan.ID, &scan.ScanID, &scan.ScopeTargetID, &scan.Status,
+		&scan.CandidatesChecked, &scan.VulnerableCount, &errorMessage, &execTime, &scan.CreatedAt)
+	if errorMessage.Valid {
+		scan.Error = &errorMessage.String
+	}
+	if execTime.Valid {
+		scan.ExecTime = &execTime.String
+	}
+	return scan, err
+}
+
+const subdomainTakeoverScanColumns = `id, scan_id, scope_target_id, status, candidates_checked, vulnerable_count, error, execution_time, created_at`
+
+func GetSubdomainTakeoverScanStatus(w http.ResponseWriter, r *http.Request) {
+	scanID := mux.Vars(r)["scan_id"]
+
+	scan, err := scanSubdomainTakeoverScan(dbPool.QueryRow(context.Background(),
+		`SELECT `+subdomainTakeoverScanColumns+` FROM subdomain_takeover_scans WHERE scan_id = $1`, scanID))
+	if err != nil {
+		log.Printf("[TAKEOVER] [ERROR] Failed to get scan status: %v", err)
+		http.Error(w, "Failed to get scan status", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scan)
+}
+
+func GetSubdomainTakeoverScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT `+subdomainTakeoverScanColumns+` FROM subdomain_takeover_scans WHERE scope_target_id = $1 ORDER BY created_at DESC`,
+		scopeTargetID)
+	if err != nil {
+		log.Printf("[TAKEOVER] [ERROR] Failed to get scans for target %s: %v", scopeTargetID, err)
+		http.Error(w, "Failed to get scans", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	scans := []SubdomainTakeoverScan{}
+	for rows.Next() {
+		scan, err := scanSubdomainTakeoverScan(rows)
+		if err != nil {
+			log.Printf("[TAKEOVER] [ERROR] Failed to scan row: %v", err)
+			continue
+		}
+		scans = append(scans, scan)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scans)
+}
+
+func fetchSubdomainTakeoverFindings(scopeTargetID string) ([]SubdomainTakeoverFinding, error) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, scan_id, scope_target_id, fqdn, cname, service, status, severity, nxdomain,
+		       http_status, matched_signature, last_checked
+		FROM subdomain_takeover_findings
+		WHERE scope_target_id = $1::uuid
+		ORDER BY status DESC, fqdn ASC`, scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	findings := []SubdomainTakeoverFinding{}
+	for rows.Next() {
+		var f SubdomainTakeoverFinding
+		if err := rows.Scan(&f.ID, &f.ScanID, &f.ScopeTargetID, &f.FQDN, &f.CNAME, &f.Service, &f.Status,
+			&f.Severity, &f.NXDomain, &f.HTTPStatus, &f.MatchedSignature, &f.LastChecked); err != nil {
+			log.Printf("[TAKEOVER] [ERROR] Failed to scan finding: %v", err)
+			continue
+		}
+		findings = append(findings, f)
+	}
+	return findings, rows.Err()
+}
+
+// GetSubdomainTakeoverFindings returns the latest takeover findings for a scope target
+func GetSubdomainTakeoverFindings(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	findings, err := fetchSubdomainTakeoverFindings(scopeTargetID)
+	if err != nil {
+		log.Printf("[TAKEOVER] [ERROR] Failed to get findings: %v", err)
+		http.Error(w, "Failed to get takeover findings", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(findings)
+}
@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ars0n-framework-v2-server/dnsresolver"
+)
+
+// fakeTakeoverResolver answers like a recursive resolver for a fixed set of CNAME and A
+// records. Names with neither don't exist.
+type fakeTakeoverResolver struct {
+	cnames    map[string]string
+	addresses map[string]string
+}
+
+func (r *fakeTakeoverResolver) exists(name string) bool {
+	_, hasCNAME := r.cnames[name]
+	_, hasAddress := r.addresses[name]
+	return hasCNAME || hasAddress
+}
+
+func (r *fakeTakeoverResolver) Query(ctx context.Context, name string, qtype uint16) (*dnsresolver.Message, error) {
+	name = normalizeDNSName(name)
+	msg := &dnsresolver.Message{Response: true, Question: name, QType: qtype}
+	if qtype == dnsresolver.TypeCNAME {
+		if target, ok := r.cnames[name]; ok {
+			msg.Answers = append(msg.Answers, dnsresolver.Record{Name: name, Type: dnsresolver.TypeCNAME, Target: target})
+		} else if !r.exists(name) {
+			msg.Rcode = dnsresolver.RcodeNXDomain
+		}
+		return msg, nil
+	}
+
+	for hop := 0; hop < 16; hop++ {
+		target, ok := r.cnames[name]
+		if !ok {
+			break
+		}
+		msg.Answers = append(msg.Answers, dnsresolver.Record{Name: name, Type: dnsresolver.TypeCNAME, Target: target})
+		name = target
+	}
+	if address, ok := r.addresses[name]; ok {
+		msg.Answers = append(msg.Answers, dnsresolver.Record{Name: name, Type: dnsresolver.TypeA, IP: net.ParseIP(address)})
+	} else {
+		msg.Rcode = dnsresolver.RcodeNXDomain
+	}
+	return msg, nil
+}
+
+func (r *fakeTakeoverResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	msg, _ := r.Query(ctx, host, dnsresolver.TypeA)
+	if msg.Rcode == dnsresolver.RcodeNXDomain {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	var hosts []string
+	for _, rr := range msg.Answers {
+		if rr.Type == dnsresolver.TypeA {
+			hosts = append(hosts, rr.IP.String())
+		}
+	}
+	return hosts, nil
+}
+
+// newTestTakeoverChecker sends every HTTP request to server whatever host it names
+func newTestTakeoverChecker(resolver takeoverResolver, server *httptest.Server) *takeoverChecker {
+	address := strings.TrimPrefix(server.URL, "http://")
+	var dialer net.Dialer
+	return &takeoverChecker{
+		resolver: resolver,
+		client: &http.Client{
+			Timeout: 5 * time.Second,
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+					return dialer.DialContext(ctx, network, address)
+				},
+			},
+		},
+		fingerprints: takeoverFingerprints,
+		timeout:      time.Second,
+	}
+}
+
+func TestTakeoverCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host == "docs.example.com" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("<h1>404</h1><p>There isn't a GitHub Pages site here.</p>"))
+			return
+		}
+		w.Write([]byte("<h1>Welcome</h1>"))
+	}))
+	defer server.Close()
+
+	resolver := &fakeTakeoverResolver{
+		cnames: map[string]string{
+			"app.example.com":   "example-prod.us-east-1.elasticbeanstalk.com",
+			"docs.example.com":  "example.github.io",
+			"shop.example.com":  "example.github.io",
+			"moved.example.com": "example.azurewebsites.net",
+		},
+		addresses: map[string]string{
+			"example.github.io":         "185.199.108.153",
+			"example.azurewebsites.net": "20.40.202.1",
+			"api.example.com":           "203.0.113.10",
+		},
+	}
+	checker := newTestTakeoverChecker(resolver, server)
+
+	tests := []struct {
+		name      string
+		candidate takeoverCandidate
+		status    string
+		service   string
+		signature string
+	}{
+		{
+			name:      "nxdomain fingerprint",
+			candidate: takeoverCandidate{FQDN: "app.example.com", CNAME: "example-prod.us-east-1.elasticbeanstalk.com"},
+			status:    "vulnerable",
+			service:   "AWS Elastic Beanstalk",
+			signature: "NXDOMAIN",
+		},
+		{
+			name:      "body signature",
+			candidate: takeoverCandidate{FQDN: "docs.example.com", CNAME: "example.github.io"},
+			status:    "vulnerable",
+			service:   "GitHub Pages",
+			signature: "There isn't a GitHub Pages site here.",
+		},
+		{
+			name:      "claimed resource",
+			candidate: takeoverCandidate{FQDN: "shop.example.com", CNAME: "example.github.io"},
+		},
+		{
+			name:      "record removed",
+			candidate: takeoverCandidate{FQDN: "old.example.com", CNAME: "example-old.elasticbeanstalk.com"},
+		},
+		{
+			name:      "record replaced by an address",
+			candidate: takeoverCandidate{FQDN: "api.example.com", CNAME: "example-api.herokuapp.com"},
+		},
+		{
+			name:      "record repointed at a live target",
+			candidate: takeoverCandidate{FQDN: "moved.example.com", CNAME: "example-old.elasticbeanstalk.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding := checker.check(tt.candidate)
+			if tt.status == "" {
+				if finding != nil {
+					t.Fatalf("expected no finding, got %s (%s)", finding.Status, finding.Service)
+				}
+				return
+			}
+			if finding == nil {
+				t.Fatal("expected a finding, got none")
+			}
+			if finding.Status != tt.status || finding.Service != tt.service {
+				t.Errorf("got %s/%s, want %s/%s", finding.Status, finding.Service, tt.status, tt.service)
+			}
+			if finding.MatchedSignature == nil || *finding.MatchedSignature != tt.signature {
+				t.Errorf("matched signature %v, want %q", finding.MatchedSignature, tt.signature)
+			}
+		})
+	}
+}
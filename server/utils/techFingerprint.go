@@ -0,0 +1,833 @@
+package utils
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// defaultFingerprintRules is a Wappalyzer-format rule file covering common servers, CDNs,
+// frameworks and applications. Uploaded rule files are layered on top of it.
+//
+//go:embed fingerprints/technologies.json
+var defaultFingerprintRules []byte
+
+const (
+	fingerprintBodyLimit     = 1 << 20
+	fingerprintUploadLimit   = 20 << 20
+	fingerprintMaxConfidence = 100
+)
+
+var (
+	htmlMetaPattern        = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	htmlMetaNamePattern    = regexp.MustCompile(`(?i)\b(?:name|property|http-equiv)\s*=\s*["']([^"']+)["']`)
+	htmlMetaContentPattern = regexp.MustCompile(`(?i)\bcontent\s*=\s*["']([^"']*)["']`)
+	versionTernaryPattern  = regexp.MustCompile(`\\(\d)\?([^:]*):(.*)`)
+)
+
+// DetectedTechnology is a technology matched by the fingerprint engine. Confidence is the sum
+// of the confidence of every matching pattern, capped at 100.
+type DetectedTechnology struct {
+	Name       string   `json:"name"`
+	Version    string   `json:"version,omitempty"`
+	Categories []string `json:"categories"`
+	Confidence int      `json:"confidence"`
+	Website    string   `json:"website,omitempty"`
+}
+
+// String formats a technology the way httpx does, "Name:version"
+func (t DetectedTechnology) String() string {
+	if t.Version == "" {
+		return t.Name
+	}
+	return t.Name + ":" + t.Version
+}
+
+// FingerprintRuleFile is an uploaded Wappalyzer-format rule file
+type FingerprintRuleFile struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	TechnologyCount int       `json:"technology_count"`
+	CategoryCount   int       `json:"category_count"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// wappalyzerTechnology is one technology as written in a Wappalyzer rule file. Most fields take
+// a pattern or a list of patterns; scripts is the older name of scriptSrc.
+type wappalyzerTechnology struct {
+	Cats      []int                      `json:"cats"`
+	Headers   map[string]json.RawMessage `json:"headers"`
+	Cookies   map[string]json.RawMessage `json:"cookies"`
+	Meta      map[string]json.RawMessage `json:"meta"`
+	ScriptSrc json.RawMessage            `json:"scriptSrc"`
+	Scripts   json.RawMessage            `json:"scripts"`
+	HTML      json.RawMessage            `json:"html"`
+	Text      json.RawMessage            `json:"text"`
+	URL       json.RawMessage            `json:"url"`
+	Implies   json.RawMessage            `json:"implies"`
+	Excludes  json.RawMessage            `json:"excludes"`
+	Website   string                     `json:"website"`
+}
+
+// wappalyzerCategory accepts both the current {"name": ...} form and the older bare name
+type wappalyzerCategory struct {
+	Name string
+}
+
+func (c *wappalyzerCategory) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &c.Name); err == nil {
+		return nil
+	}
+	var category struct {
+		Name string `json:"name"`
+	}
+	err := json.Unmarshal(data, &category)
+	c.Name = category.Name
+	return err
+}
+
+// fingerprintPattern is a compiled Wappalyzer pattern such as "nginx/([\d.]+)\;version:\1".
+// A nil regex matches on presence alone.
+type fingerprintPattern struct {
+	regex      *regexp.Regexp
+	version    string
+	confidence int
+}
+
+type technologyFingerprint struct {
+	name       string
+	categories []int
+	website    string
+	headers    map[string][]fingerprintPattern
+	cookies    map[string][]fingerprintPattern
+	meta       map[string][]fingerprintPattern
+	scriptSrc  []fingerprintPattern
+	html       []fingerprintPattern
+	url        []fingerprintPattern
+	implies    []fingerprintImplication
+	excludes   []string
+}
+
+type fingerprintImplication struct {
+	name       string
+	confidence int
+}
+
+// FingerprintEngine matches HTTP responses against Wappalyzer-format rules
+type FingerprintEngine struct {
+	technologies map[string]*technologyFingerprint
+	categories   map[int]string
+}
+
+var (
+	fingerprintEngineMu sync.Mutex
+	fingerprintEngine   *FingerprintEngine
+)
+
+// currentFingerprintEngine returns the engine built from the embedded rules and every uploaded
+// rule file, building it on first use
+func currentFingerprintEngine() *FingerprintEngine {
+	fingerprintEngineMu.Lock()
+	defer fingerprintEngineMu.Unlock()
+	if fingerprintEngine != nil {
+		return fingerprintEngine
+	}
+
+	engine := &FingerprintEngine{
+		technologies: make(map[string]*technologyFingerprint),
+		categories:   make(map[int]string),
+	}
+	if _, _, err := engine.load(defaultFingerprintRules); err != nil {
+		log.Printf("[FINGERPRINT] [ERROR] Failed to load embedded rules: %v", err)
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT name, content FROM technology_fingerprint_rules ORDER BY created_at ASC`)
+	if err != nil {
+		log.Printf("[FINGERPRINT] [WARN] Failed to load uploaded rule files, using embedded rules only: %v", err)
+	} else {
+		for rows.Next() {
+			var name, content string
+			if err := rows.Scan(&name, &content); err != nil {
+				continue
+			}
+			if _, _, err := engine.load([]byte(content)); err != nil {
+				log.Printf("[FINGERPRINT] [WARN] Skipping rule file %s: %v", name, err)
+			}
+		}
+		rows.Close()
+	}
+
+	log.Printf("[FINGERPRINT] [INFO] Loaded %d technology fingerprints in %d categories",
+		len(engine.technologies), len(engine.categories))
+	fingerprintEngine = engine
+	return engine
+}
+
+// invalidateFingerprintEngine makes the next detection rebuild the engine from the rule files
+func invalidateFingerprintEngine() {
+	fingerprintEngineMu.Lock()
+	fingerprintEngine = nil
+	fingerprintEngineMu.Unlock()
+}
+
+// parseFingerprintRules reads a rule file in any of the layouts Wappalyzer has used: a single
+// file with technologies and categories keys, a technologies file holding just the technology
+// map, or a categories file keyed by category ID
+func parseFingerprintRules(data []byte) (map[string]wappalyzerTechnology, map[int]string, error) {
+	var combined struct {
+		Technologies map[string]wappalyzerTechnology `json:"technologies"`
+		Apps         map[string]wappalyzerTechnology `json:"apps"`
+		Categories   map[string]wappalyzerCategory   `json:"categories"`
+	}
+	if err := json.Unmarshal(data, &combined); err != nil {
+		return nil, nil, fmt.Errorf("invalid rule file: %v", err)
+	}
+
+	technologies := combined.Technologies
+	if technologies == nil {
+		technologies = combined.Apps
+	}
+	rawCategories := combined.Categories
+
+	if technologies == nil && rawCategories == nil {
+		var entries map[string]json.RawMessage
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, nil, fmt.Errorf("invalid rule file: %v", err)
+		}
+		categoriesOnly := len(entries) > 0
+		for key := range entries {
+			if _, err := strconv.Atoi(key); err != nil {
+				categoriesOnly = false
+				break
+			}
+		}
+		if categoriesOnly {
+			if err := json.Unmarshal(data, &rawCategories); err != nil {
+				return nil, nil, fmt.Errorf("invalid categories: %v", err)
+			}
+		} else if err := json.Unmarshal(data, &technologies); err != nil {
+			return nil, nil, fmt.Errorf("invalid technologies: %v", err)
+		}
+	}
+
+	categories := make(map[int]string)
+	for key, category := range rawCategories {
+		if id, err := strconv.Atoi(key); err == nil && category.Name != "" {
+			categories[id] = category.Name
+		}
+	}
+	if len(technologies) == 0 && len(categories) == 0 {
+		return nil, nil, fmt.Errorf("no technologies or categories found")
+	}
+	return technologies, categories, nil
+}
+
+// load adds the technologies and categories of a rule file, replacing technologies of the same
+// name. Patterns Go's regexp cannot compile, such as lookaheads, are skipped.
+func (e *FingerprintEngine) load(data []byte) (int, int, error) {
+	technologies, categories, err := parseFingerprintRules(data)
+	if err != nil {
+		return 0, 0, err
+	}
+	for id, name := range categories {
+		e.categories[id] = name
+	}
+	for name, technology := range technologies {
+		fingerprint := &technologyFingerprint{
+			name:       name,
+			categories: technology.Cats,
+			website:    technology.Website,
+			headers:    compilePatternMap(technology.Headers),
+			cookies:    compilePatternMap(technology.Cookies),
+			meta:       compilePatternMap(technology.Meta),
+			scriptSrc:  append(compilePatterns(technology.ScriptSrc), compilePatterns(technology.Scripts)...),
+			html:       append(compilePatterns(technology.HTML), compilePatterns(technology.Text)...),
+			url:        compilePatterns(technology.URL),
+			excludes:   patternStrings(technology.Excludes),
+		}
+		for _, implied := range patternStrings(technology.Implies) {
+			pattern := parseFingerprintPattern(implied)
+			impliedName, _, _ := strings.Cut(implied, `\;`)
+			fingerprint.implies = append(fingerprint.implies, fingerprintImplication{impliedName, pattern.confidence})
+		}
+		e.technologies[name] = fingerprint
+	}
+	return len(technologies), len(categories), nil
+}
+
+// patternStrings decodes a field holding either one string or a list of strings
+func patternStrings(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var single string
+	if json.Unmarshal(raw, &single) == nil {
+		return []string{single}
+	}
+	var list []string
+	json.Unmarshal(raw, &list)
+	return list
+}
+
+// parseFingerprintPattern splits a pattern into its regex and the version and confidence tags
+// that follow it, separated by \;
+func parseFingerprintPattern(value string) fingerprintPattern {
+	parts := strings.Split(value, `\;`)
+	pattern := fingerprintPattern{confidence: fingerprintMaxConfidence}
+	for _, tag := range parts[1:] {
+		key, tagValue, _ := strings.Cut(tag, ":")
+		switch key {
+		case "version":
+			pattern.version = tagValue
+		case "confidence":
+			if confidence, err := strconv.Atoi(tagValue); err == nil {
+				pattern.confidence = confidence
+			}
+		}
+	}
+	if parts[0] != "" {
+		pattern.regex, _ = regexp.Compile("(?i)" + parts[0])
+	}
+	return pattern
+}
+
+func compilePatterns(raw json.RawMessage) []fingerprintPattern {
+	var patterns []fingerprintPattern
+	for _, value := range patternStrings(raw) {
+		pattern := parseFingerprintPattern(value)
+		if pattern.regex != nil {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// compilePatternMap compiles keyed patterns. Keys are lowercased for case-insensitive lookups
+// and an empty pattern only requires the key to be present.
+func compilePatternMap(raw map[string]json.RawMessage) map[string][]fingerprintPattern {
+	if len(raw) == 0 {
+		return nil
+	}
+	compiled := make(map[string][]fingerprintPattern)
+	for key, value := range raw {
+		key = strings.ToLower(key)
+		for _, patternValue := range patternStrings(value) {
+			pattern := parseFingerprintPattern(patternValue)
+			if pattern.regex == nil && strings.SplitN(patternValue, `\;`, 2)[0] != "" {
+				continue
+			}
+			compiled[key] = append(compiled[key], pattern)
+		}
+	}
+	return compiled
+}
+
+// resolveVersion fills a version template such as "\1" or "\1?Enterprise:Community" from the
+// submatches of a pattern
+func resolveVersion(template string, submatches []string) string {
+	if template == "" {
+		return ""
+	}
+	if ternary := versionTernaryPattern.FindStringSubmatch(template); ternary != nil {
+		index, _ := strconv.Atoi(ternary[1])
+		if index < len(submatches) && submatches[index] != "" {
+			return ternary[2]
+		}
+		return ternary[3]
+	}
+	version := template
+	for i := len(submatches) - 1; i > 0; i-- {
+		version = strings.ReplaceAll(version, `\`+strconv.Itoa(i), submatches[i])
+	}
+	return strings.TrimSpace(version)
+}
+
+// match reports whether value satisfies the pattern and the version it reveals
+func (p fingerprintPattern) match(value string) (bool, string) {
+	if p.regex == nil {
+		return true, ""
+	}
+	submatches := p.regex.FindStringSubmatch(value)
+	if submatches == nil {
+		return false, ""
+	}
+	return true, resolveVersion(p.version, submatches)
+}
+
+// fingerprintEvidence is what a response offers for matching
+type fingerprintEvidence struct {
+	url       string
+	headers   map[string][]string
+	cookies   map[string]string
+	meta      map[string][]string
+	scriptSrc []string
+	html      string
+}
+
+func newFingerprintEvidence(targetURL string, headers http.Header, body []byte) fingerprintEvidence {
+	evidence := fingerprintEvidence{
+		url:     targetURL,
+		headers: make(map[string][]string),
+		cookies: make(map[string]string),
+		meta:    make(map[string][]string),
+	}
+	for name, values := range headers {
+		evidence.headers[strings.ToLower(name)] = values
+	}
+	for _, cookie := range (&http.Response{Header: headers}).Cookies() {
+		evidence.cookies[strings.ToLower(cookie.Name)] = cookie.Value
+	}
+
+	if len(body) > fingerprintBodyLimit {
+		body = body[:fingerprintBodyLimit]
+	}
+	evidence.html = string(body)
+	for _, tag := range htmlMetaPattern.FindAllString(evidence.html, -1) {
+		name := htmlMetaNamePattern.FindStringSubmatch(tag)
+		content := htmlMetaContentPattern.FindStringSubmatch(tag)
+		if name != nil && content != nil {
+			key := strings.ToLower(name[1])
+			evidence.meta[key] = append(evidence.meta[key], content[1])
+		}
+	}
+	for _, match := range scriptSrcPattern.FindAllStringSubmatch(evidence.html, -1) {
+		evidence.scriptSrc = append(evidence.scriptSrc, match[1])
+	}
+	return evidence
+}
+
+// Detect fingerprints a response. Headers and body may come from a live request or from a
+// stored response; implied technologies are added and excluded ones removed.
+func (e *FingerprintEngine) Detect(targetURL string, headers http.Header, body []byte) []DetectedTechnology {
+	evidence := newFingerprintEvidence(targetURL, headers, body)
+	detected := make(map[string]*DetectedTechnology)
+
+	record := func(fingerprint *technologyFingerprint, version string, confidence int) {
+		technology, exists := detected[fingerprint.name]
+		if !exists {
+			technology = &DetectedTechnology{Name: fingerprint.name}
+			detected[fingerprint.name] = technology
+		}
+		technology.Confidence += confidence
+		if len(version) > len(technology.Version) {
+			technology.Version = version
+		}
+	}
+	matchAll := func(fingerprint *technologyFingerprint, patterns []fingerprintPattern, values []string) {
+		for _, pattern := range patterns {
+			for _, value := range values {
+				if ok, version := pattern.match(value); ok {
+					record(fingerprint, version, pattern.confidence)
+					break
+				}
+			}
+		}
+	}
+
+	for _, fingerprint := range e.technologies {
+		for name, patterns := range fingerprint.headers {
+			if values, ok := evidence.headers[name]; ok {
+				matchAll(fingerprint, patterns, values)
+			}
+		}
+		for name, patterns := range fingerprint.cookies {
+			for cookieName, value := range evidence.cookies {
+				// Wappalyzer cookie names such as incap_ses_ are prefixes of per-site names
+				if cookieName == name || (strings.HasSuffix(name, "_") && strings.HasPrefix(cookieName, name)) {
+					matchAll(fingerprint, patterns, []string{value})
+					break
+				}
+			}
+		}
+		for name, patterns := range fingerprint.meta {
+			if values, ok := evidence.meta[name]; ok {
+				matchAll(fingerprint, patterns, values)
+			}
+		}
+		matchAll(fingerprint, fingerprint.scriptSrc, evidence.scriptSrc)
+		matchAll(fingerprint, fingerprint.html, []string{evidence.html})
+		matchAll(fingerprint, fingerprint.url, []string{evidence.url})
+	}
+
+	// Implications can chain, e.g. Next.js implies React and Node.js
+	for changed := true; changed; {
+		changed = false
+		for name, technology := range detected {
+			for _, implied := range e.technologies[name].implies {
+				if _, exists := detected[implied.name]; exists || e.technologies[implied.name] == nil {
+					continue
+				}
+				confidence := implied.confidence * technology.Confidence / fingerprintMaxConfidence
+				detected[implied.name] = &DetectedTechnology{Name: implied.name, Confidence: confidence}
+				changed = true
+			}
+		}
+	}
+	for name := range detected {
+		for _, excluded := range e.technologies[name].excludes {
+			delete(detected, excluded)
+		}
+	}
+
+	technologies := make([]DetectedTechnology, 0, len(detected))
+	for name, technology := range detected {
+		fingerprint := e.technologies[name]
+		if technology.Confidence > fingerprintMaxConfidence {
+			technology.Confidence = fingerprintMaxConfidence
+		}
+		technology.Website = fingerprint.website
+		technology.Categories = []string{}
+		for _, id := range fingerprint.categories {
+			if category, ok := e.categories[id]; ok {
+				technology.Categories = append(technology.Categories, category)
+			}
+		}
+		technologies = append(technologies, *technology)
+	}
+	sort.Slice(technologies, func(i, j int) bool { return technologies[i].Name < technologies[j].Name })
+	return technologies
+}
+
+// DetectTechnologies fingerprints a response with the current rules
+func DetectTechnologies(targetURL string, headers http.Header, body []byte) []DetectedTechnology {
+	return currentFingerprintEngine().Detect(targetURL, headers, body)
+}
+
+// technologyNames formats detected technologies as "Name:version" strings
+func technologyNames(technologies []DetectedTechnology) []string {
+	names := make([]string, 0, len(technologies))
+	for _, technology := range technologies {
+		names = append(names, technology.String())
+	}
+	return names
+}
+
+// mergeTechnologyNames adds detected technologies to a list produced by another tool such as
+// httpx, replacing entries for the same technology only when the detection carries a version
+func mergeTechnologyNames(existing []string, detected []DetectedTechnology) []string {
+	merged := append([]string{}, existing...)
+	for _, technology := range detected {
+		found := false
+		for i, name := range merged {
+			existingName, existingVersion, _ := strings.Cut(name, ":")
+			if !strings.EqualFold(existingName, technology.Name) {
+				continue
+			}
+			found = true
+			if existingVersion == "" && technology.Version != "" {
+				merged[i] = technology.String()
+			}
+			break
+		}
+		if !found {
+			merged = append(merged, technology.String())
+		}
+	}
+	return merged
+}
+
+// headersFromJSON converts headers stored as a JSON object, whose values are strings or lists
+// of strings, back into an http.Header
+func headersFromJSON(data []byte) http.Header {
+	headers := make(http.Header)
+	var stored map[string]interface{}
+	if json.Unmarshal(data, &stored) != nil {
+		return headers
+	}
+	for name, value := range stored {
+		switch v := value.(type) {
+		case string:
+			headers.Add(name, v)
+		case []interface{}:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					headers.Add(name, s)
+				}
+			}
+		}
+	}
+	return headers
+}
+
+// fingerprintConsolidatedWebServers re-runs the fingerprint engine over the stored responses of
+// a scope target's consolidated live web servers, so assets found by tools without technology
+// detection get the same coverage as httpx results
+func fingerprintConsolidatedWebServers(scopeTargetID string) (int, error) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT a.id, a.url, COALESCE(a.technologies, '{}'),
+		       COALESCE(a.http_response_headers, tu.http_response_headers),
+		       COALESCE(tu.http_response, '')
+		FROM consolidated_attack_surface_assets a
+		LEFT JOIN target_urls tu ON tu.scope_target_id = a.scope_target_id AND tu.url = a.url
+		WHERE a.scope_target_id = $1::uuid AND a.asset_type = 'live_web_server' AND a.url IS NOT NULL`,
+		scopeTargetID)
+	if err != nil {
+		return 0, err
+	}
+
+	type assetTechnologies struct {
+		id           string
+		technologies []string
+		details      []DetectedTechnology
+	}
+	var updates []assetTechnologies
+	engine := currentFingerprintEngine()
+	for rows.Next() {
+		var id, assetURL, body string
+		var technologies []string
+		var headersJSON []byte
+		if err := rows.Scan(&id, &assetURL, &technologies, &headersJSON, &body); err != nil {
+			log.Printf("[FINGERPRINT] [ERROR] Failed to scan consolidated web server: %v", err)
+			continue
+		}
+		if len(headersJSON) == 0 && body == "" {
+			continue
+		}
+		detected := engine.Detect(assetURL, headersFromJSON(headersJSON), []byte(body))
+		if len(detected) > 0 {
+			updates = append(updates, assetTechnologies{id, mergeTechnologyNames(technologies, detected), detected})
+		}
+	}
+	rows.Close()
+
+	for _, update := range updates {
+		details, _ := json.Marshal(update.details)
+		if _, err := dbPool.Exec(context.Background(), `
+			UPDATE consolidated_attack_surface_assets
+			SET technologies = $1, technology_details = $2::jsonb
+			WHERE id = $3`, update.technologies, string(details), update.id); err != nil {
+			log.Printf("[FINGERPRINT] [ERROR] Failed to update technologies of asset %s: %v", update.id, err)
+		}
+	}
+	return len(updates), nil
+}
+
+// TechnologyInventoryEntry is one technology version seen across a scope target's web servers
+type TechnologyInventoryEntry struct {
+	Name       string   `json:"name"`
+	Version    string   `json:"version"`
+	Categories []string `json:"categories"`
+	URLs       []string `json:"urls"`
+	Count      int      `json:"count"`
+}
+
+// GetTechnologyInventory lists the technologies fingerprinted on a scope target's target URLs
+// and IP/Port live web servers, grouped by name and version. category filters by category name.
+func GetTechnologyInventory(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+	category := r.URL.Query().Get("category")
+
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT url, technology_details FROM target_urls
+		WHERE scope_target_id = $1::uuid AND technology_details IS NOT NULL
+		UNION ALL
+		SELECT lws.url, lws.technology_details FROM live_web_servers lws
+		JOIN ip_port_scans ips ON lws.scan_id = ips.scan_id
+		WHERE ips.scope_target_id = $1::uuid AND lws.technology_details IS NOT NULL`, scopeTargetID)
+	if err != nil {
+		log.Printf("[FINGERPRINT] [ERROR] Failed to get technologies: %v", err)
+		http.Error(w, "Failed to get technologies", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	entries := make(map[string]*TechnologyInventoryEntry)
+	for rows.Next() {
+		var targetURL string
+		var detailsJSON []byte
+		if err := rows.Scan(&targetURL, &detailsJSON); err != nil {
+			continue
+		}
+		var details []DetectedTechnology
+		if json.Unmarshal(detailsJSON, &details) != nil {
+			continue
+		}
+		for _, technology := range details {
+			if category != "" && !containsString(technology.Categories, category) {
+				continue
+			}
+			key := technology.String()
+			entry, exists := entries[key]
+			if !exists {
+				entry = &TechnologyInventoryEntry{Name: technology.Name, Version: technology.Version, Categories: technology.Categories}
+				entries[key] = entry
+			}
+			if !containsString(entry.URLs, targetURL) {
+				entry.URLs = append(entry.URLs, targetURL)
+				entry.Count++
+			}
+		}
+	}
+
+	inventory := make([]TechnologyInventoryEntry, 0, len(entries))
+	for _, entry := range entries {
+		sort.Strings(entry.URLs)
+		inventory = append(inventory, *entry)
+	}
+	sort.Slice(inventory, func(i, j int) bool {
+		if inventory[i].Name != inventory[j].Name {
+			return inventory[i].Name < inventory[j].Name
+		}
+		return inventory[i].Version < inventory[j].Version
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(inventory)
+}
+
+// FingerprintURL fetches a URL and returns the technologies detected on it, for checking rules
+func FingerprintURL(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.URL == "" {
+		http.Error(w, "Invalid request body. `url` is required.", http.StatusBadRequest)
+		return
+	}
+
+	resp, body, err := fetchWithCustomHeaders(newScanHTTPClient(30*time.Second), payload.URL, fingerprintBodyLimit)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to fetch URL: %v", err), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(DetectTechnologies(resp.Request.URL.String(), resp.Header, body))
+}
+
+func GetFingerprintRuleFiles(w http.ResponseWriter, r *http.Request) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, name, technology_count, category_count, created_at, updated_at
+		FROM technology_fingerprint_rules
+		ORDER BY name ASC`)
+	if err != nil {
+		log.Printf("[FINGERPRINT] [ERROR] Failed to get rule files: %v", err)
+		http.Error(w, "Failed to get rule files", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	files := []FingerprintRuleFile{}
+	for rows.Next() {
+		var file FingerprintRuleFile
+		if err := rows.Scan(&file.ID, &file.Name, &file.TechnologyCount, &file.CategoryCount, &file.CreatedAt, &file.UpdatedAt); err != nil {
+			log.Printf("[FINGERPRINT] [ERROR] Failed to scan rule file: %v", err)
+			continue
+		}
+		files = append(files, file)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(files)
+}
+
+// UploadFingerprintRuleFile stores a Wappalyzer-format rule file sent as a multipart form with
+// a file field, or as the raw JSON body with the name in the name query parameter. A file with
+// the same name is replaced.
+func UploadFingerprintRuleFile(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSpace(r.URL.Query().Get("name"))
+	var data []byte
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(fingerprintUploadLimit); err != nil {
+			http.Error(w, "Failed to parse form data", http.StatusBadRequest)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Failed to get uploaded file", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		if formName := strings.TrimSpace(r.FormValue("name")); formName != "" {
+			name = formName
+		} else if name == "" {
+			name = header.Filename
+		}
+		if data, err = io.ReadAll(io.LimitReader(file, fingerprintUploadLimit)); err != nil {
+			http.Error(w, "Failed to read uploaded file", http.StatusBadRequest)
+			return
+		}
+	} else {
+		var err error
+		if data, err = io.ReadAll(io.LimitReader(r.Body, fingerprintUploadLimit)); err != nil {
+			http.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if name == "" {
+		http.Error(w, "Rule file name is required", http.StatusBadRequest)
+		return
+	}
+	technologies, categories, err := parseFingerprintRules(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var file FingerprintRuleFile
+	err = dbPool.QueryRow(context.Background(), `
+		INSERT INTO technology_fingerprint_rules (name, content, technology_count, category_count)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name) DO UPDATE SET
+			content = EXCLUDED.content,
+			technology_count = EXCLUDED.technology_count,
+			category_count = EXCLUDED.category_count,
+			updated_at = NOW()
+		RETURNING id, name, technology_count, category_count, created_at, updated_at`,
+		name, string(data), len(technologies), len(categories)).Scan(
+		&file.ID, &file.Name, &file.TechnologyCount, &file.CategoryCount, &file.CreatedAt, &file.UpdatedAt)
+	if err != nil {
+		log.Printf("[FINGERPRINT] [ERROR] Failed to store rule file %s: %v", name, err)
+		http.Error(w, "Failed to store rule file", http.StatusInternalServerError)
+		return
+	}
+	invalidateFingerprintEngine()
+	log.Printf("[FINGERPRINT] [INFO] Stored rule file %s with %d technologies and %d categories",
+		file.Name, file.TechnologyCount, file.CategoryCount)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(file)
+}
+
+func DeleteFingerprintRuleFile(w http.ResponseWriter, r *http.Request) {
+	fileID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(fileID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	result, err := dbPool.Exec(context.Background(), `DELETE FROM technology_fingerprint_rules WHERE id = $1`, fileID)
+	if err != nil {
+		log.Printf("[FINGERPRINT] [ERROR] Failed to delete rule file: %v", err)
+		http.Error(w, "Failed to delete rule file", http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		http.Error(w, "Rule file not found", http.StatusNotFound)
+		return
+	}
+	invalidateFingerprintEngine()
+
+	w.WriteHeader(http.StatusNoContent)
+}
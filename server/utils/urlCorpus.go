@@ -0,0 +1,435 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	urlCorpusBatchSize = 1000
+	// Patterns kept from a single scan, so a site with endless unique paths can't flood the table
+	maxURLCorpusPatterns = 200000
+	defaultURLPageSize   = 100
+	maxURLPageSize       = 1000
+)
+
+// GauProviders are the archives gau can query
+var GauProviders = []string{"wayback", "commoncrawl", "otx", "urlscan"}
+
+// URL categories. An entry can be in several, and "page" is used when no other applies.
+const (
+	URLCategoryAPI             = "api"
+	URLCategoryStatic          = "static"
+	URLCategoryJavaScript      = "javascript"
+	URLCategoryInterestingFile = "interesting_file"
+	URLCategoryParameterized   = "parameterized"
+	URLCategoryPage            = "page"
+)
+
+var URLCategories = []string{URLCategoryAPI, URLCategoryStatic, URLCategoryJavaScript, URLCategoryInterestingFile, URLCategoryParameterized, URLCategoryPage}
+
+// likeEscaper makes a search string match literally in an ILIKE pattern with ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+var (
+	staticExtensions = toStringSet([]string{
+		"css", "png", "jpg", "jpeg", "gif", "svg", "ico", "webp", "bmp", "tif", "tiff", "avif",
+		"woff", "woff2", "ttf", "eot", "otf", "mp3", "mp4", "avi", "mov", "webm", "flv", "wav", "ogg",
+	})
+	javascriptExtensions = toStringSet([]string{"js", "mjs", "jsx", "ts", "map"})
+	// Backups, dumps, configs, archives and keys that should rarely be reachable
+	interestingExtensions = toStringSet([]string{
+		"bak", "backup", "old", "orig", "save", "swp", "tmp", "sql", "db", "sqlite", "sqlite3", "mdb", "dump",
+		"env", "ini", "conf", "cfg", "config", "properties", "toml", "yml", "yaml", "log",
+		"zip", "tar", "gz", "tgz", "rar", "7z", "war", "jar",
+		"pem", "key", "p12", "pfx", "crt", "jks", "ppk", "htpasswd", "ds_store",
+		"csv", "xls", "xlsx", "doc", "docx",
+	})
+	apiExtensions = toStringSet([]string{"json", "asmx", "svc", "wsdl", "wadl"})
+
+	apiPathPattern         = regexp.MustCompile(`(^|/)(api|apis|rest|graphql|gql|v[0-9]+(\.[0-9]+)?|wp-json|swagger|swagger-ui|openapi|odata|rpc|jsonrpc|xmlrpc\.php|soap)(/|$|\.)`)
+	interestingPathPattern = regexp.MustCompile(`(^|/)\.(git|svn|hg|env|aws|ssh|docker)(/|$)`)
+
+	uuidSegmentPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	intSegmentPattern   = regexp.MustCompile(`^[0-9]+$`)
+	hashSegmentPattern  = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`)
+	tokenSegmentPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{32,}$`)
+)
+
+// CorpusURL is one path pattern on a host, with every parameter name and category seen for it.
+// ExampleURL is the first archived URL that matched the pattern. ParamValues keeps the distinct
+// values seen for each parameter, capped like the parameter inventory.
+type CorpusURL struct {
+	ID            string              `json:"id"`
+	ScopeTargetID string              `json:"scope_target_id"`
+	Host          string              `json:"host"`
+	Scheme        string              `json:"scheme"`
+	Pattern       string              `json:"pattern"`
+	ExampleURL    string              `json:"example_url"`
+	Extension     *string             `json:"extension"`
+	Params        []string            `json:"params"`
+	ParamValues   map[string][]string `json:"param_values"`
+	Categories    []string            `json:"categories"`
+	HitCount      int                 `json:"hit_count"`
+	Source        string              `json:"source"`
+	ScanID        *string             `json:"scan_id"`
+	FirstSeen     time.Time           `json:"first_seen"`
+	LastSeen      time.Time           `json:"last_seen"`
+}
+
+// CorpusURLPage is one page of a filtered URL corpus query. CategoryCounts covers the whole
+// corpus of the scope target, not just the filtered results.
+type CorpusURLPage struct {
+	URLs           []CorpusURL    `json:"urls"`
+	Total          int            `json:"total"`
+	Page           int            `json:"page"`
+	PageSize       int            `json:"page_size"`
+	CategoryCounts map[string]int `json:"category_counts"`
+}
+
+// normalizeGauProviders keeps the known providers in their canonical order, defaulting to wayback
+func normalizeGauProviders(providers []string) []string {
+	requested := make(map[string]bool)
+	for _, provider := range providers {
+		requested[strings.ToLower(strings.TrimSpace(provider))] = true
+	}
+	var normalized []string
+	for _, provider := range GauProviders {
+		if requested[provider] {
+			normalized = append(normalized, provider)
+		}
+	}
+	if len(normalized) == 0 {
+		return []string{"wayback"}
+	}
+	return normalized
+}
+
+// normalizeURLPath replaces the parts of a path that vary between otherwise identical URLs,
+// such as numeric IDs, UUIDs and hashes, so /users/12/orders and /users/57/orders share a pattern
+func normalizeURLPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		name, ext := segment, ""
+		if dot := strings.LastIndex(segment, "."); dot > 0 {
+			name, ext = segment[:dot], segment[dot:]
+		}
+		switch {
+		case intSegmentPattern.MatchString(name):
+			name = "{int}"
+		case uuidSegmentPattern.MatchString(name):
+			name = "{uuid}"
+		case hashSegmentPattern.MatchString(name):
+			name = "{hash}"
+		case tokenSegmentPattern.MatchString(name) && strings.ContainsAny(name, "0123456789"):
+			name = "{token}"
+		}
+		segments[i] = name + ext
+	}
+	return strings.Join(segments, "/")
+}
+
+// urlExtension returns the lower case extension of the last path segment without the dot
+func urlExtension(p string) string {
+	ext := strings.ToLower(strings.TrimPrefix(path.Ext(p), "."))
+	if len(ext) == 0 || len(ext) > 10 || strings.ContainsAny(ext, "{}") {
+		return ""
+	}
+	return ext
+}
+
+// classifyURL assigns the categories of a URL from its path, extension and parameters
+func classifyURL(urlPath, ext string, params []string) []string {
+	lowerPath := strings.ToLower(urlPath)
+	var categories []string
+	if apiPathPattern.MatchString(lowerPath) || apiExtensions[ext] {
+		categories = append(categories, URLCategoryAPI)
+	}
+	if staticExtensions[ext] {
+		categories = append(categories, URLCategoryStatic)
+	}
+	if javascriptExtensions[ext] {
+		categories = append(categories, URLCategoryJavaScript)
+	}
+	if interestingExtensions[ext] || interestingPathPattern.MatchString(lowerPath) {
+		categories = append(categories, URLCategoryInterestingFile)
+	}
+	if len(params) > 0 {
+		categories = append(categories, URLCategoryParameterized)
+	}
+	if len(categories) == 0 {
+		categories = append(categories, URLCategoryPage)
+	}
+	return categories
+}
+
+// parseCorpusLine reads a gau output line, which is JSON with a url field or a bare URL
+func parseCorpusLine(line string) string {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "{") {
+		var gauResult struct {
+			URL string `json:"url"`
+		}
+		if err := json.Unmarshal([]byte(line), &gauResult); err != nil {
+			return ""
+		}
+		return gauResult.URL
+	}
+	return line
+}
+
+// buildURLCorpus collapses archived URLs into one entry per host and path pattern. Parameter
+// names, values and categories are merged across every URL that shares a pattern.
+func buildURLCorpus(lines []string) []*CorpusURL {
+	entries := make(map[string]*CorpusURL)
+	params := make(map[string]map[string][]string)
+	var order []string
+
+	for _, line := range lines {
+		raw := parseCorpusLine(line)
+		if raw == "" {
+			continue
+		}
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+			continue
+		}
+		host := strings.ToLower(u.Hostname())
+		if port := u.Port(); port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+			host += ":" + port
+		}
+		pattern := normalizeURLPath(u.Path)
+		key := host + pattern
+
+		entry, exists := entries[key]
+		if !exists {
+			if len(entries) >= maxURLCorpusPatterns {
+				continue
+			}
+			entry = &CorpusURL{Host: host, Scheme: u.Scheme, Pattern: pattern, ExampleURL: raw}
+			if ext := urlExtension(u.Path); ext != "" {
+				entry.Extension = &ext
+			}
+			entries[key] = entry
+			params[key] = make(map[string][]string)
+			order = append(order, key)
+		}
+		entry.HitCount++
+		for name, values := range u.Query() {
+			if name == "" {
+				continue
+			}
+			seen, ok := params[key][name]
+			if !ok {
+				seen = []string{}
+			}
+			for _, value := range values {
+				if len(value) > maxParameterValueLen {
+					value = value[:maxParameterValueLen]
+				}
+				if len(seen) < maxParameterValues && !containsString(seen, value) {
+					seen = append(seen, value)
+				}
+			}
+			params[key][name] = seen
+		}
+	}
+
+	corpus := make([]*CorpusURL, 0, len(order))
+	for _, key := range order {
+		entry := entries[key]
+		entry.Params = []string{}
+		entry.ParamValues = params[key]
+		for name := range params[key] {
+			entry.Params = append(entry.Params, name)
+		}
+		sort.Strings(entry.Params)
+		ext := ""
+		if entry.Extension != nil {
+			ext = *entry.Extension
+		}
+		entry.Categories = classifyURL(entry.Pattern, ext, entry.Params)
+		corpus = append(corpus, entry)
+	}
+	return corpus
+}
+
+// storeGauURLCorpus adds the URLs from a gau scan to the scope target's corpus. Patterns that
+// already exist keep their first_seen date and gain any new parameters, values and categories,
+// losing "page" once they have another.
+func storeGauURLCorpus(scanID string, lines []string) {
+	var scopeTargetID string
+	err := dbPool.QueryRow(context.Background(), `SELECT scope_target_id FROM gau_scans WHERE scan_id = $1`, scanID).Scan(&scopeTargetID)
+	if err != nil {
+		log.Printf("[URL CORPUS] [ERROR] Failed to find scope target for GAU scan %s: %v", scanID, err)
+		return
+	}
+
+	corpus := buildURLCorpus(lines)
+	if len(corpus) == 0 {
+		return
+	}
+
+	for start := 0; start < len(corpus); start += urlCorpusBatchSize {
+		end := start + urlCorpusBatchSize
+		if end > len(corpus) {
+			end = len(corpus)
+		}
+		batch := &pgx.Batch{}
+		for _, entry := range corpus[start:end] {
+			paramValuesJSON, _ := json.Marshal(entry.ParamValues)
+			batch.Queue(`
+				INSERT INTO urls
+					(scope_target_id, host, scheme, pattern, example_url, extension, params, param_values,
+					 categories, hit_count, source, scan_id, first_seen, last_seen)
+				VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 'gau', $11, NOW(), NOW())
+				ON CONFLICT (scope_target_id, host, pattern) DO UPDATE SET
+					params = ARRAY(SELECT DISTINCT unnest(urls.params || EXCLUDED.params) ORDER BY 1),
+					param_values = (
+						SELECT COALESCE(jsonb_object_agg(name, to_jsonb(vals[1:$12::int])), '{}'::jsonb)
+						FROM (
+							SELECT p.key AS name, array_agg(DISTINCT v.value) AS vals
+							FROM (SELECT * FROM jsonb_each(COALESCE(urls.param_values, '{}'::jsonb))
+							      UNION ALL
+							      SELECT * FROM jsonb_each(EXCLUDED.param_values)) p,
+							     jsonb_array_elements_text(p.value) v
+							GROUP BY p.key
+						) merged),
+					categories = (
+						SELECT CASE WHEN cardinality(merged) > 1 THEN array_remove(merged, $13) ELSE merged END
+						FROM (SELECT ARRAY(SELECT DISTINCT unnest(urls.categories || EXCLUDED.categories) ORDER BY 1) AS merged) m),
+					hit_count = EXCLUDED.hit_count,
+					scan_id = EXCLUDED.scan_id,
+					last_seen = NOW()`,
+				scopeTargetID, entry.Host, entry.Scheme, entry.Pattern, entry.ExampleURL, entry.Extension,
+				entry.Params, paramValuesJSON, entry.Categories, entry.HitCount, scanID, maxParameterValues, URLCategoryPage)
+		}
+		if err := dbPool.SendBatch(context.Background(), batch).Close(); err != nil {
+			log.Printf("[URL CORPUS] [ERROR] Failed to store URL batch for scan %s: %v", scanID, err)
+			return
+		}
+	}
+	log.Printf("[URL CORPUS] [INFO] Stored %d URL patterns from %d GAU results for scope target %s", len(corpus), len(lines), scopeTargetID)
+}
+
+// GetURLCorpus returns a page of a scope target's URL corpus. Results can be filtered by
+// category, host, extension, parameter name and a search string matched against the pattern
+// and example URL.
+func GetURLCorpus(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	page, _ := strconv.Atoi(query.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(query.Get("page_size"))
+	if pageSize < 1 {
+		pageSize = defaultURLPageSize
+	} else if pageSize > maxURLPageSize {
+		pageSize = maxURLPageSize
+	}
+
+	conditions := []string{"scope_target_id = $1"}
+	args := []interface{}{scopeTargetID}
+	addCondition := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if category := query.Get("category"); category != "" {
+		if !toStringSet(URLCategories)[category] {
+			http.Error(w, "Unknown category. Use one of: "+strings.Join(URLCategories, ", "), http.StatusBadRequest)
+			return
+		}
+		addCondition("$%d = ANY(categories)", category)
+	}
+	if host := query.Get("host"); host != "" {
+		addCondition("host = $%d", strings.ToLower(host))
+	}
+	if ext := query.Get("extension"); ext != "" {
+		addCondition("extension = $%d", strings.ToLower(strings.TrimPrefix(ext, ".")))
+	}
+	if param := query.Get("param"); param != "" {
+		addCondition("$%d = ANY(params)", param)
+	}
+	if search := query.Get("search"); search != "" {
+		addCondition(`(pattern ILIKE '%%' || $%[1]d || '%%' ESCAPE '\' OR example_url ILIKE '%%' || $%[1]d || '%%' ESCAPE '\')`,
+			likeEscaper.Replace(search))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	result := CorpusURLPage{URLs: []CorpusURL{}, Page: page, PageSize: pageSize, CategoryCounts: map[string]int{}}
+	if err := dbPool.QueryRow(context.Background(), `SELECT COUNT(*) FROM urls WHERE `+where, args...).Scan(&result.Total); err != nil {
+		log.Printf("[URL CORPUS] [ERROR] Failed to count URLs: %v", err)
+		http.Error(w, "Failed to get URLs", http.StatusInternalServerError)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, scope_target_id, host, scheme, pattern, example_url, extension, params,
+		       COALESCE(param_values, '{}'), categories, hit_count, source, scan_id, first_seen, last_seen
+		FROM urls
+		WHERE `+where+`
+		ORDER BY host ASC, pattern ASC
+		LIMIT `+strconv.Itoa(pageSize)+` OFFSET `+strconv.Itoa((page-1)*pageSize), args...)
+	if err != nil {
+		log.Printf("[URL CORPUS] [ERROR] Failed to get URLs: %v", err)
+		http.Error(w, "Failed to get URLs", http.StatusInternalServerError)
+		return
+	}
+	for rows.Next() {
+		var u CorpusURL
+		var paramValues []byte
+		if err := rows.Scan(&u.ID, &u.ScopeTargetID, &u.Host, &u.Scheme, &u.Pattern, &u.ExampleURL, &u.Extension,
+			&u.Params, &paramValues, &u.Categories, &u.HitCount, &u.Source, &u.ScanID, &u.FirstSeen, &u.LastSeen); err != nil {
+			log.Printf("[URL CORPUS] [ERROR] Failed to scan URL: %v", err)
+			continue
+		}
+		json.Unmarshal(paramValues, &u.ParamValues)
+		result.URLs = append(result.URLs, u)
+	}
+	rows.Close()
+
+	rows, err = dbPool.Query(context.Background(), `
+		SELECT category, COUNT(*)
+		FROM urls, unnest(categories) AS category
+		WHERE scope_target_id = $1
+		GROUP BY category`, scopeTargetID)
+	if err == nil {
+		for rows.Next() {
+			var category string
+			var count int
+			if rows.Scan(&category, &count) == nil {
+				result.CategoryCounts[category] = count
+			}
+		}
+		rows.Close()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
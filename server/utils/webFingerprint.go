@@ -0,0 +1,838 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	webFingerprintWorkers   = 10
+	webFingerprintTimeout   = 15 * time.Second
+	faviconBodyLimit        = 1 << 20
+	shodanExportUploadLimit = 200 << 20
+	// Pages with fewer tags than this are too generic for their structure to identify an app
+	htmlStructureMinTags = 10
+)
+
+// Fingerprint kinds assets can be clustered by
+const (
+	FingerprintFavicon       = "favicon"
+	FingerprintHTMLStructure = "html_structure"
+	FingerprintTLSCert       = "tls_cert"
+	FingerprintJA3S          = "ja3s"
+)
+
+var WebFingerprintKinds = []string{FingerprintFavicon, FingerprintHTMLStructure, FingerprintTLSCert, FingerprintJA3S}
+
+var (
+	linkTagPattern     = regexp.MustCompile(`(?is)<link\s[^>]*>`)
+	linkRelPattern     = regexp.MustCompile(`(?i)\brel\s*=\s*["']?([^"'>]+)`)
+	linkHrefPattern    = regexp.MustCompile(`(?i)\bhref\s*=\s*["']?([^"'\s>]+)`)
+	htmlTagPattern     = regexp.MustCompile(`<\s*(/?)\s*([a-zA-Z][a-zA-Z0-9-]*)`)
+	htmlNoisePattern   = regexp.MustCompile(`(?is)<!--.*?-->|<script[^>]*>.*?</script>|<style[^>]*>.*?</style>`)
+	htmlTitleTagRegexp = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+)
+
+// WebFingerprintScan fingerprints every live web server of a scope target
+type WebFingerprintScan struct {
+	ID                 string    `json:"id"`
+	ScanID             string    `json:"scan_id"`
+	ScopeTargetID      string    `json:"scope_target_id"`
+	Status             string    `json:"status"`
+	TargetsCount       int       `json:"targets_count"`
+	FingerprintedCount int       `json:"fingerprinted_count"`
+	Error              *string   `json:"error"`
+	ExecTime           *string   `json:"execution_time"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// WebFingerprint holds the fingerprints of one live web server. FaviconHash is the signed
+// MurmurHash3 Shodan indexes as http.favicon.hash; JA3S hashes how the server answers a fixed
+// TLS 1.2 ClientHello.
+type WebFingerprint struct {
+	ID                string     `json:"id"`
+	ScanID            string     `json:"scan_id"`
+	ScopeTargetID     string     `json:"scope_target_id"`
+	URL               string     `json:"url"`
+	Source            string     `json:"source"`
+	Title             string     `json:"title"`
+	FaviconURL        *string    `json:"favicon_url"`
+	FaviconHash       *int32     `json:"favicon_hash"`
+	FaviconMD5        *string    `json:"favicon_md5"`
+	HTMLStructureHash *string    `json:"html_structure_hash"`
+	HTMLTagCount      int        `json:"html_tag_count"`
+	TLSCertSHA256     *string    `json:"tls_cert_sha256"`
+	TLSCertSubject    *string    `json:"tls_cert_subject"`
+	TLSCertIssuer     *string    `json:"tls_cert_issuer"`
+	TLSCertNotAfter   *time.Time `json:"tls_cert_not_after"`
+	TLSServerHello    *string    `json:"tls_server_hello"`
+	JA3S              *string    `json:"ja3s"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// WebFingerprintCluster is a group of web servers sharing one fingerprint value
+type WebFingerprintCluster struct {
+	Kind   string   `json:"kind"`
+	Value  string   `json:"value"`
+	Label  string   `json:"label"`
+	Count  int      `json:"count"`
+	URLs   []string `json:"urls"`
+	Titles []string `json:"titles"`
+}
+
+// ShodanFaviconMatch is a host from a Shodan export whose favicon matches one of a scope
+// target's web servers
+type ShodanFaviconMatch struct {
+	FaviconHash int32    `json:"favicon_hash"`
+	IP          string   `json:"ip"`
+	Port        int      `json:"port"`
+	Hostnames   []string `json:"hostnames"`
+	Org         string   `json:"org"`
+	Title       string   `json:"title"`
+	KnownURLs   []string `json:"known_urls"`
+}
+
+// murmur3Hash32 is the x86 32-bit MurmurHash3
+func murmur3Hash32(data []byte, seed uint32) uint32 {
+	const c1, c2 = 0xcc9e2d51, 0x1b873593
+	h := seed
+	nblocks := len(data) / 4
+	for i := 0; i < nblocks; i++ {
+		k := binary.LittleEndian.Uint32(data[i*4:])
+		k *= c1
+		k = (k << 15) | (k >> 17)
+		k *= c2
+		h ^= k
+		h = (h << 13) | (h >> 19)
+		h = h*5 + 0xe6546b64
+	}
+
+	var k uint32
+	tail := data[nblocks*4:]
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = (k << 15) | (k >> 17)
+		k *= c2
+		h ^= k
+	}
+
+	h ^= uint32(len(data))
+	h ^= h >> 16
+	h *= 0x85ebca6b
+	h ^= h >> 13
+	h *= 0xc2b2ae35
+	h ^= h >> 16
+	return h
+}
+
+// shodanFaviconHash hashes a favicon the way Shodan does: MurmurHash3 of the base64 encoding
+// wrapped at 76 characters with a trailing newline, as Python's base64.encodebytes produces
+func shodanFaviconHash(favicon []byte) int32 {
+	encoded := base64.StdEncoding.EncodeToString(favicon)
+	var wrapped strings.Builder
+	for len(encoded) > 76 {
+		wrapped.WriteString(encoded[:76])
+		wrapped.WriteByte('\n')
+		encoded = encoded[76:]
+	}
+	wrapped.WriteString(encoded)
+	wrapped.WriteByte('\n')
+	return int32(murmur3Hash32([]byte(wrapped.String()), 0))
+}
+
+// faviconURL returns the icon declared by a page's link tags, or /favicon.ico
+func faviconURL(pageURL *url.URL, body []byte) string {
+	for _, tag := range linkTagPattern.FindAllString(string(body), -1) {
+		rel := linkRelPattern.FindStringSubmatch(tag)
+		href := linkHrefPattern.FindStringSubmatch(tag)
+		if rel == nil || href == nil || !strings.Contains(strings.ToLower(rel[1]), "icon") {
+			continue
+		}
+		if ref, err := url.Parse(strings.TrimSpace(href[1])); err == nil && !strings.HasPrefix(href[1], "data:") {
+			return pageURL.ResolveReference(ref).String()
+		}
+	}
+	return pageURL.ResolveReference(&url.URL{Path: "/favicon.ico"}).String()
+}
+
+// htmlStructureHash hashes the sequence of opening and closing tags of a page, ignoring text,
+// attributes, comments and inline scripts and styles, so pages rendered by the same template
+// hash the same whatever their content
+func htmlStructureHash(body []byte) (string, int) {
+	stripped := htmlNoisePattern.ReplaceAllString(string(body), "")
+	var skeleton strings.Builder
+	count := 0
+	for _, match := range htmlTagPattern.FindAllStringSubmatch(stripped, -1) {
+		skeleton.WriteString(match[1])
+		skeleton.WriteString(strings.ToLower(match[2]))
+		skeleton.WriteByte(' ')
+		count++
+	}
+	if count < htmlStructureMinTags {
+		return "", count
+	}
+	sum := sha256.Sum256([]byte(skeleton.String()))
+	return hex.EncodeToString(sum[:]), count
+}
+
+// ja3sClientHello builds the fixed TLS 1.2 ClientHello whose ServerHello is hashed into JA3S.
+// The offer never changes so servers answering it the same way share a JA3S.
+func ja3sClientHello(serverName string) []byte {
+	ciphers := []uint16{0xc02b, 0xc02f, 0xc02c, 0xc030, 0xcca9, 0xcca8, 0xc013, 0xc014, 0x009c, 0x009d, 0x002f, 0x0035, 0x000a}
+
+	var extensions bytes.Buffer
+	addExtension := func(extensionType uint16, data []byte) {
+		binary.Write(&extensions, binary.BigEndian, extensionType)
+		binary.Write(&extensions, binary.BigEndian, uint16(len(data)))
+		extensions.Write(data)
+	}
+	if serverName != "" && net.ParseIP(serverName) == nil {
+		var sni bytes.Buffer
+		binary.Write(&sni, binary.BigEndian, uint16(len(serverName)+3))
+		sni.WriteByte(0)
+		binary.Write(&sni, binary.BigEndian, uint16(len(serverName)))
+		sni.WriteString(serverName)
+		addExtension(0x0000, sni.Bytes())
+	}
+	addExtension(0x000a, []byte{0x00, 0x06, 0x00, 0x1d, 0x00, 0x17, 0x00, 0x18})
+	addExtension(0x000b, []byte{0x01, 0x00})
+	addExtension(0x000d, []byte{0x00, 0x12, 0x04, 0x03, 0x08, 0x04, 0x04, 0x01, 0x05, 0x03, 0x08, 0x05, 0x05, 0x01, 0x08, 0x06, 0x06, 0x01, 0x02, 0x01})
+	addExtension(0x0010, []byte{0x00, 0x0c, 0x02, 'h', '2', 0x08, 'h', 't', 't', 'p', '/', '1', '.', '1'})
+	addExtension(0x0017, nil)
+	addExtension(0x0023, nil)
+	addExtension(0xff01, []byte{0x00})
+
+	var hello bytes.Buffer
+	hello.Write([]byte{0x03, 0x03})
+	random := make([]byte, 32)
+	rand.Read(random)
+	hello.Write(random)
+	hello.WriteByte(0)
+	binary.Write(&hello, binary.BigEndian, uint16(len(ciphers)*2))
+	for _, cipher := range ciphers {
+		binary.Write(&hello, binary.BigEndian, cipher)
+	}
+	hello.Write([]byte{0x01, 0x00})
+	binary.Write(&hello, binary.BigEndian, uint16(extensions.Len()))
+	hello.Write(extensions.Bytes())
+
+	handshake := append([]byte{0x01, byte(hello.Len() >> 16), byte(hello.Len() >> 8), byte(hello.Len())}, hello.Bytes()...)
+	record := []byte{0x16, 0x03, 0x01, byte(len(handshake) >> 8), byte(len(handshake))}
+	return append(record, handshake...)
+}
+
+// parseServerHello returns the JA3S string of a ServerHello message: version, cipher and
+// extension types in the order the server sent them
+func parseServerHello(message []byte) (string, error) {
+	if len(message) < 4 || message[0] != 0x02 {
+		return "", fmt.Errorf("not a ServerHello")
+	}
+	body := message[4:]
+	if len(body) < 35 {
+		return "", fmt.Errorf("truncated ServerHello")
+	}
+	version := binary.BigEndian.Uint16(body[0:2])
+	sessionIDLength := int(body[34])
+	offset := 35 + sessionIDLength
+	if len(body) < offset+3 {
+		return "", fmt.Errorf("truncated ServerHello")
+	}
+	cipher := binary.BigEndian.Uint16(body[offset:])
+	offset += 3
+
+	var extensionTypes []string
+	if len(body) >= offset+2 {
+		end := offset + 2 + int(binary.BigEndian.Uint16(body[offset:]))
+		if end > len(body) {
+			end = len(body)
+		}
+		for offset += 2; offset+4 <= end; {
+			extensionTypes = append(extensionTypes, strconv.Itoa(int(binary.BigEndian.Uint16(body[offset:]))))
+			offset += 4 + int(binary.BigEndian.Uint16(body[offset+2:]))
+		}
+	}
+	return fmt.Sprintf("%d,%d,%s", version, cipher, strings.Join(extensionTypes, "-")), nil
+}
+
+// tlsServerHello sends the fixed ClientHello and reads handshake records until the whole
+// ServerHello has arrived
+func tlsServerHello(address, serverName string) (string, error) {
+	conn, err := net.DialTimeout("tcp", address, webFingerprintTimeout)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(webFingerprintTimeout))
+
+	if _, err := conn.Write(ja3sClientHello(serverName)); err != nil {
+		return "", err
+	}
+
+	var handshake []byte
+	header := make([]byte, 5)
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return "", err
+		}
+		payload := make([]byte, binary.BigEndian.Uint16(header[3:]))
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			return "", err
+		}
+		if header[0] == 0x15 && len(payload) == 2 {
+			return "", fmt.Errorf("server sent alert %d", payload[len(payload)-1])
+		}
+		if header[0] != 0x16 {
+			return "", fmt.Errorf("unexpected record type %d", header[0])
+		}
+		handshake = append(handshake, payload...)
+		if len(handshake) >= 4 {
+			length := int(handshake[1])<<16 | int(handshake[2])<<8 | int(handshake[3])
+			if len(handshake) >= 4+length {
+				return parseServerHello(handshake[:4+length])
+			}
+		}
+	}
+}
+
+// fingerprintTLS records the leaf certificate and the JA3S of an HTTPS server
+func fingerprintTLS(fingerprint *WebFingerprint, target *url.URL) {
+	port := target.Port()
+	if port == "" {
+		port = "443"
+	}
+	address := net.JoinHostPort(target.Hostname(), port)
+
+	dialer := &net.Dialer{Timeout: webFingerprintTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, &tls.Config{
+		InsecureSkipVerify: true,
+		ServerName:         target.Hostname(),
+	})
+	if err == nil {
+		if certificates := conn.ConnectionState().PeerCertificates; len(certificates) > 0 {
+			leaf := certificates[0]
+			sum := sha256.Sum256(leaf.Raw)
+			digest := hex.EncodeToString(sum[:])
+			subject, issuer, notAfter := leaf.Subject.String(), leaf.Issuer.String(), leaf.NotAfter
+			fingerprint.TLSCertSHA256 = &digest
+			fingerprint.TLSCertSubject = &subject
+			fingerprint.TLSCertIssuer = &issuer
+			fingerprint.TLSCertNotAfter = &notAfter
+		}
+		conn.Close()
+	}
+
+	if serverHello, err := tlsServerHello(address, target.Hostname()); err == nil {
+		sum := md5.Sum([]byte(serverHello))
+		ja3s := hex.EncodeToString(sum[:])
+		fingerprint.TLSServerHello = &serverHello
+		fingerprint.JA3S = &ja3s
+	} else {
+		log.Printf("[WEB FINGERPRINT] [DEBUG] No ServerHello from %s: %v", address, err)
+	}
+}
+
+// fingerprintWebServer computes the favicon, HTML structure and TLS fingerprints of a URL
+func fingerprintWebServer(client *http.Client, targetURL string) (*WebFingerprint, error) {
+	resp, body, err := fetchWithCustomHeaders(client, targetURL, jsPageBodyLimit)
+	if err != nil {
+		return nil, err
+	}
+	fingerprint := &WebFingerprint{URL: targetURL}
+	if title := htmlTitleTagRegexp.FindSubmatch(body); title != nil {
+		fingerprint.Title = strings.TrimSpace(string(title[1]))
+	}
+	hash, count := htmlStructureHash(body)
+	if hash != "" {
+		fingerprint.HTMLStructureHash = &hash
+	}
+	fingerprint.HTMLTagCount = count
+
+	iconURL := faviconURL(resp.Request.URL, body)
+	if iconResp, icon, err := fetchWithCustomHeaders(client, iconURL, faviconBodyLimit); err == nil &&
+		iconResp.StatusCode == http.StatusOK && len(icon) > 0 && !bytes.HasPrefix(bytes.TrimSpace(icon), []byte("<")) {
+		hash := shodanFaviconHash(icon)
+		sum := md5.Sum(icon)
+		digest := hex.EncodeToString(sum[:])
+		fingerprint.FaviconURL = &iconURL
+		fingerprint.FaviconHash = &hash
+		fingerprint.FaviconMD5 = &digest
+	}
+
+	if parsed, err := url.Parse(targetURL); err == nil && parsed.Scheme == "https" {
+		fingerprintTLS(fingerprint, parsed)
+	}
+	return fingerprint, nil
+}
+
+// collectWebFingerprintTargets returns the live target URLs and IP/Port live web servers of
+// a scope target, keyed by URL with the source they came from
+func collectWebFingerprintTargets(scopeTargetID string) (map[string]string, error) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT url, 'target_url' FROM target_urls
+		WHERE scope_target_id = $1::uuid AND no_longer_live = false
+		UNION
+		SELECT lws.url, 'live_web_server' FROM live_web_servers lws
+		JOIN ip_port_scans ips ON lws.scan_id = ips.scan_id
+		WHERE ips.scope_target_id = $1::uuid AND ips.status = 'success'`, scopeTargetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get live web servers: %v", err)
+	}
+	defer rows.Close()
+
+	targets := make(map[string]string)
+	for rows.Next() {
+		var targetURL, source string
+		if err := rows.Scan(&targetURL, &source); err != nil {
+			continue
+		}
+		if _, exists := targets[targetURL]; !exists {
+			targets[targetURL] = source
+		}
+	}
+	return targets, rows.Err()
+}
+
+func RunWebFingerprintScan(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		ScopeTargetID string `json:"scope_target_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.ScopeTargetID == "" {
+		http.Error(w, "Invalid request body. `scope_target_id` is required.", http.StatusBadRequest)
+		return
+	}
+
+	scanID := uuid.New().String()
+	_, err := dbPool.Exec(context.Background(),
+		`INSERT INTO web_fingerprint_scans (scan_id, scope_target_id, status) VALUES ($1, $2, $3)`,
+		scanID, payload.ScopeTargetID, "pending")
+	if err != nil {
+		log.Printf("[WEB FINGERPRINT] [ERROR] Failed to create scan record: %v", err)
+		http.Error(w, "Failed to create scan record.", http.StatusInternalServerError)
+		return
+	}
+
+	go ExecuteWebFingerprintScan(scanID, payload.ScopeTargetID)
+
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]string{"scan_id": scanID})
+}
+
+// ExecuteWebFingerprintScan fingerprints every live web server of a scope target and replaces
+// the stored fingerprints
+func ExecuteWebFingerprintScan(scanID, scopeTargetID string) {
+	log.Printf("[WEB FINGERPRINT] [INFO] Starting web fingerprinting for scope target %s (scan ID: %s)", scopeTargetID, scanID)
+	startTime := time.Now()
+	updateWebFingerprintScan(scanID, "processing", WebFingerprintScan{}, "", "")
+
+	targets, err := collectWebFingerprintTargets(scopeTargetID)
+	if err != nil {
+		log.Printf("[WEB FINGERPRINT] [ERROR] %v", err)
+		updateWebFingerprintScan(scanID, "error", WebFingerprintScan{}, err.Error(), time.Since(startTime).String())
+		return
+	}
+	log.Printf("[WEB FINGERPRINT] [INFO] Fingerprinting %d live web servers", len(targets))
+
+	client := newScanHTTPClient(webFingerprintTimeout)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var fingerprints []*WebFingerprint
+	semaphore := make(chan struct{}, webFingerprintWorkers)
+	for targetURL, source := range targets {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(targetURL, source string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			fingerprint, err := fingerprintWebServer(client, targetURL)
+			if err != nil {
+				log.Printf("[WEB FINGERPRINT] [WARN] Skipping %s: %v", targetURL, err)
+				return
+			}
+			fingerprint.Source = source
+			mu.Lock()
+			fingerprints = append(fingerprints, fingerprint)
+			mu.Unlock()
+		}(targetURL, source)
+	}
+	wg.Wait()
+
+	counts := WebFingerprintScan{TargetsCount: len(targets), FingerprintedCount: len(fingerprints)}
+	if err := saveWebFingerprints(scanID, scopeTargetID, fingerprints); err != nil {
+		log.Printf("[WEB FINGERPRINT] [ERROR] Failed to save fingerprints: %v", err)
+		updateWebFingerprintScan(scanID, "error", counts, err.Error(), time.Since(startTime).String())
+		return
+	}
+
+	execTime := time.Since(startTime).String()
+	updateWebFingerprintScan(scanID, "success", counts, "", execTime)
+	log.Printf("[WEB FINGERPRINT] [INFO] Scan %s completed in %s: %d of %d web servers fingerprinted",
+		scanID, execTime, counts.FingerprintedCount, counts.TargetsCount)
+}
+
+func saveWebFingerprints(scanID, scopeTargetID string, fingerprints []*WebFingerprint) error {
+	tx, err := dbPool.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %v", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	if _, err := tx.Exec(context.Background(),
+		`DELETE FROM web_fingerprints WHERE scope_target_id = $1`, scopeTargetID); err != nil {
+		return fmt.Errorf("failed to delete old fingerprints: %v", err)
+	}
+
+	batch := &pgx.Batch{}
+	for _, f := range fingerprints {
+		batch.Queue(`
+			INSERT INTO web_fingerprints
+				(scan_id, scope_target_id, url, source, title, favicon_url, favicon_hash, favicon_md5,
+				 html_structure_hash, html_tag_count, tls_cert_sha256, tls_cert_subject, tls_cert_issuer,
+				 tls_cert_not_after, tls_server_hello, ja3s)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			ON CONFLICT (scope_target_id, url) DO NOTHING`,
+			scanID, scopeTargetID, f.URL, f.Source, f.Title, f.FaviconURL, f.FaviconHash, f.FaviconMD5,
+			f.HTMLStructureHash, f.HTMLTagCount, f.TLSCertSHA256, f.TLSCertSubject, f.TLSCertIssuer,
+			f.TLSCertNotAfter, f.TLSServerHello, f.JA3S)
+	}
+	if err := tx.SendBatch(context.Background(), batch).Close(); err != nil {
+		return fmt.Errorf("failed to store fingerprints: %v", err)
+	}
+
+	return tx.Commit(context.Background())
+}
+
+func updateWebFingerprintScan(scanID, status string, counts WebFingerprintScan, errorMessage, execTime string) {
+	_, err := dbPool.Exec(context.Background(), `
+		UPDATE web_fingerprint_scans
+		SET status = $1, targets_count = $2, fingerprinted_count = $3,
+		    error = NULLIF($4, ''), execution_time = NULLIF($5, '')
+		WHERE scan_id = $6`,
+		status, counts.TargetsCount, counts.FingerprintedCount, errorMessage, execTime, scanID)
+	if err != nil {
+		log.Printf("[WEB FINGERPRINT] [ERROR] Failed to update scan status: %v", err)
+	}
+}
+
+const webFingerprintScanColumns = `id, scan_id, scope_target_id, status, targets_count, fingerprinted_count,
+	error, execution_time, created_at`
+
+func scanWebFingerprintScan(row interface{ Scan(...interface{}) error }) (WebFingerprintScan, error) {
+	var scan WebFingerprintScan
+	err := row.Scan(
+		&scan.ID,
+		&scan.ScanID,
+		&scan.ScopeTargetID,
+		&scan.Status,
+		&scan.TargetsCount,
+		&scan.FingerprintedCount,
+		&scan.Error,
+		&scan.ExecTime,
+		&scan.CreatedAt,
+	)
+	return scan, err
+}
+
+func GetWebFingerprintScanStatus(w http.ResponseWriter, r *http.Request) {
+	scanID := mux.Vars(r)["scan_id"]
+
+	scan, err := scanWebFingerprintScan(dbPool.QueryRow(context.Background(),
+		`SELECT `+webFingerprintScanColumns+` FROM web_fingerprint_scans WHERE scan_id = $1`, scanID))
+	if err != nil {
+		http.Error(w, "Scan not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scan)
+}
+
+func GetWebFingerprintScansForScopeTarget(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(),
+		`SELECT `+webFingerprintScanColumns+` FROM web_fingerprint_scans WHERE scope_target_id = $1 ORDER BY created_at DESC`,
+		scopeTargetID)
+	if err != nil {
+		log.Printf("[WEB FINGERPRINT] [ERROR] Failed to get scans for target %s: %v", scopeTargetID, err)
+		http.Error(w, "Failed to get scans", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	scans := []WebFingerprintScan{}
+	for rows.Next() {
+		scan, err := scanWebFingerprintScan(rows)
+		if err != nil {
+			log.Printf("[WEB FINGERPRINT] [ERROR] Failed to scan row: %v", err)
+			continue
+		}
+		scans = append(scans, scan)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scans)
+}
+
+func fetchWebFingerprints(scopeTargetID string) ([]WebFingerprint, error) {
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT id, scan_id, scope_target_id, url, source, COALESCE(title, ''), favicon_url, favicon_hash,
+		       favicon_md5, html_structure_hash, html_tag_count, tls_cert_sha256, tls_cert_subject,
+		       tls_cert_issuer, tls_cert_not_after, tls_server_hello, ja3s, updated_at
+		FROM web_fingerprints
+		WHERE scope_target_id = $1::uuid
+		ORDER BY url ASC`, scopeTargetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fingerprints := []WebFingerprint{}
+	for rows.Next() {
+		var f WebFingerprint
+		if err := rows.Scan(&f.ID, &f.ScanID, &f.ScopeTargetID, &f.URL, &f.Source, &f.Title, &f.FaviconURL,
+			&f.FaviconHash, &f.FaviconMD5, &f.HTMLStructureHash, &f.HTMLTagCount, &f.TLSCertSHA256,
+			&f.TLSCertSubject, &f.TLSCertIssuer, &f.TLSCertNotAfter, &f.TLSServerHello, &f.JA3S,
+			&f.UpdatedAt); err != nil {
+			log.Printf("[WEB FINGERPRINT] [ERROR] Failed to scan fingerprint: %v", err)
+			continue
+		}
+		fingerprints = append(fingerprints, f)
+	}
+	return fingerprints, rows.Err()
+}
+
+func GetWebFingerprints(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	fingerprints, err := fetchWebFingerprints(scopeTargetID)
+	if err != nil {
+		log.Printf("[WEB FINGERPRINT] [ERROR] Failed to get fingerprints: %v", err)
+		http.Error(w, "Failed to get web fingerprints", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(fingerprints)
+}
+
+// clusterWebFingerprints groups fingerprints sharing a value of the given kind, keeping groups
+// of at least minSize web servers, largest first
+func clusterWebFingerprints(fingerprints []WebFingerprint, kind string, minSize int) []WebFingerprintCluster {
+	clusters := make(map[string]*WebFingerprintCluster)
+	var order []string
+	for _, f := range fingerprints {
+		var value, label string
+		switch kind {
+		case FingerprintFavicon:
+			if f.FaviconHash != nil {
+				value = strconv.Itoa(int(*f.FaviconHash))
+				label = "http.favicon.hash:" + value
+			}
+		case FingerprintHTMLStructure:
+			if f.HTMLStructureHash != nil {
+				value = *f.HTMLStructureHash
+				label = fmt.Sprintf("%d tags", f.HTMLTagCount)
+			}
+		case FingerprintTLSCert:
+			if f.TLSCertSHA256 != nil {
+				value = *f.TLSCertSHA256
+				if f.TLSCertSubject != nil {
+					label = *f.TLSCertSubject
+				}
+			}
+		case FingerprintJA3S:
+			if f.JA3S != nil {
+				value = *f.JA3S
+				if f.TLSServerHello != nil {
+					label = *f.TLSServerHello
+				}
+			}
+		}
+		if value == "" {
+			continue
+		}
+
+		cluster, exists := clusters[value]
+		if !exists {
+			cluster = &WebFingerprintCluster{Kind: kind, Value: value, Label: label, URLs: []string{}, Titles: []string{}}
+			clusters[value] = cluster
+			order = append(order, value)
+		}
+		cluster.URLs = append(cluster.URLs, f.URL)
+		cluster.Count++
+		if f.Title != "" && !containsString(cluster.Titles, f.Title) {
+			cluster.Titles = append(cluster.Titles, f.Title)
+		}
+	}
+
+	result := []WebFingerprintCluster{}
+	for _, value := range order {
+		if clusters[value].Count >= minSize {
+			result = append(result, *clusters[value])
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Count > result[j].Count })
+	return result
+}
+
+// GetWebFingerprintClusters groups a scope target's web servers by shared fingerprints. by picks
+// one kind (favicon, html_structure, tls_cert or ja3s), all kinds by default, and min_size the
+// smallest group returned, 2 by default.
+func GetWebFingerprintClusters(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	kinds := WebFingerprintKinds
+	if by := r.URL.Query().Get("by"); by != "" && by != "all" {
+		if !containsString(WebFingerprintKinds, by) {
+			http.Error(w, "Invalid `by`. Use one of: "+strings.Join(WebFingerprintKinds, ", "), http.StatusBadRequest)
+			return
+		}
+		kinds = []string{by}
+	}
+	minSize := 2
+	if raw := r.URL.Query().Get("min_size"); raw != "" {
+		if size, err := strconv.Atoi(raw); err == nil && size > 0 {
+			minSize = size
+		}
+	}
+
+	fingerprints, err := fetchWebFingerprints(scopeTargetID)
+	if err != nil {
+		log.Printf("[WEB FINGERPRINT] [ERROR] Failed to get fingerprints: %v", err)
+		http.Error(w, "Failed to get web fingerprints", http.StatusInternalServerError)
+		return
+	}
+
+	clusters := []WebFingerprintCluster{}
+	for _, kind := range kinds {
+		clusters = append(clusters, clusterWebFingerprints(fingerprints, kind, minSize)...)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(clusters)
+}
+
+// MatchShodanExport finds the hosts of an offline Shodan export, as downloaded with
+// `shodan download` and decompressed to one JSON banner per line, whose favicon hash matches
+// one of the scope target's web servers. The export is sent as the request body or as a
+// multipart file field.
+func MatchShodanExport(w http.ResponseWriter, r *http.Request) {
+	scopeTargetID := mux.Vars(r)["id"]
+	if _, err := uuid.Parse(scopeTargetID); err != nil {
+		http.Error(w, "Invalid UUID format", http.StatusBadRequest)
+		return
+	}
+
+	var export io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(32 << 20); err != nil {
+			http.Error(w, "Failed to parse form data", http.StatusBadRequest)
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, "Failed to get uploaded file", http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		export = file
+	}
+
+	fingerprints, err := fetchWebFingerprints(scopeTargetID)
+	if err != nil {
+		log.Printf("[WEB FINGERPRINT] [ERROR] Failed to get fingerprints: %v", err)
+		http.Error(w, "Failed to get web fingerprints", http.StatusInternalServerError)
+		return
+	}
+	knownURLs := make(map[int32][]string)
+	for _, f := range fingerprints {
+		if f.FaviconHash != nil {
+			knownURLs[*f.FaviconHash] = append(knownURLs[*f.FaviconHash], f.URL)
+		}
+	}
+
+	matches := []ShodanFaviconMatch{}
+	decoder := json.NewDecoder(io.LimitReader(export, shodanExportUploadLimit))
+	for {
+		var banner struct {
+			IPStr     string   `json:"ip_str"`
+			Port      int      `json:"port"`
+			Hostnames []string `json:"hostnames"`
+			Org       string   `json:"org"`
+			HTTP      *struct {
+				Title   string `json:"title"`
+				Favicon *struct {
+					Hash int32 `json:"hash"`
+				} `json:"favicon"`
+			} `json:"http"`
+		}
+		if err := decoder.Decode(&banner); err == io.EOF {
+			break
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("Invalid Shodan export: %v", err), http.StatusBadRequest)
+			return
+		}
+		if banner.HTTP == nil || banner.HTTP.Favicon == nil {
+			continue
+		}
+		if urls, ok := knownURLs[banner.HTTP.Favicon.Hash]; ok {
+			matches = append(matches, ShodanFaviconMatch{
+				FaviconHash: banner.HTTP.Favicon.Hash,
+				IP:          banner.IPStr,
+				Port:        banner.Port,
+				Hostnames:   banner.Hostnames,
+				Org:         banner.Org,
+				Title:       banner.HTTP.Title,
+				KnownURLs:   urls,
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(matches)
+}
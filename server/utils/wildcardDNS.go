@@ -0,0 +1,300 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const (
+	// Number of random labels resolved per zone when building a wildcard fingerprint
+	wildcardProbeCount = 3
+	// Concurrent lookups used for zone probes and candidate checks
+	wildcardResolveWorkers = 50
+	wildcardLookupTimeout  = 3 * time.Second
+)
+
+// WildcardDNSZone is the stored result of probing a parent zone for wildcard DNS
+type WildcardDNSZone struct {
+	Zone           string    `json:"zone"`
+	IsWildcard     bool      `json:"is_wildcard"`
+	WildcardIPs    []string  `json:"wildcard_ips"`
+	WildcardCNAMEs []string  `json:"wildcard_cnames"`
+	FilteredCount  int       `json:"filtered_count"`
+	CheckedAt      time.Time `json:"checked_at"`
+}
+
+type dnsAnswer struct {
+	IPs   []string
+	CNAME string
+}
+
+type wildcardFingerprint struct {
+	zone   string
+	ips    map[string]bool
+	cnames map[string]bool
+}
+
+// matches reports whether an answer is indistinguishable from the zone's wildcard.
+// Every returned address must come from the wildcard pool, or the name must alias
+// to the same CNAME target the random labels did.
+func (f *wildcardFingerprint) matches(answer dnsAnswer) bool {
+	if answer.CNAME != "" && f.cnames[answer.CNAME] {
+		return true
+	}
+	if len(answer.IPs) == 0 || len(f.ips) == 0 {
+		return false
+	}
+	for _, ip := range answer.IPs {
+		if !f.ips[ip] {
+			return false
+		}
+	}
+	return true
+}
+
+// wildcardResolver is the subset of lookups wildcard detection needs. Both *net.Resolver
+// and the shared resolver pool satisfy it.
+type wildcardResolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+	LookupCNAME(ctx context.Context, host string) (string, error)
+}
+
+// resolveDNSAnswer looks up the A/AAAA and CNAME answers for a name. The trailing dot
+// keeps the resolver from appending search domains to the random probe labels.
+func resolveDNSAnswer(resolver wildcardResolver, name string) (dnsAnswer, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), wildcardLookupTimeout)
+	defer cancel()
+
+	fqdn := strings.TrimSuffix(name, ".") + "."
+	addrs, err := resolver.LookupIPAddr(ctx, fqdn)
+	if err != nil || len(addrs) == 0 {
+		return dnsAnswer{}, false
+	}
+
+	var answer dnsAnswer
+	for _, addr := range addrs {
+		answer.IPs = append(answer.IPs, addr.IP.String())
+	}
+	sort.Strings(answer.IPs)
+
+	if cname, err := resolver.LookupCNAME(ctx, fqdn); err == nil {
+		cname = strings.ToLower(strings.TrimSuffix(cname, "."))
+		if cname != strings.ToLower(strings.TrimSuffix(name, ".")) {
+			answer.CNAME = cname
+		}
+	}
+
+	return answer, true
+}
+
+func randomDNSLabel() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "wc" + strings.ReplaceAll(time.Now().Format("150405.000000"), ".", "")
+	}
+	return "wc" + hex.EncodeToString(b)
+}
+
+// probeWildcardZone resolves random labels under a zone. Any answer at all means the
+// zone has a wildcard record; the union of the answers becomes its fingerprint.
+func probeWildcardZone(resolver wildcardResolver, zone string) *wildcardFingerprint {
+	fingerprint := &wildcardFingerprint{
+		zone:   zone,
+		ips:    make(map[string]bool),
+		cnames: make(map[string]bool),
+	}
+
+	wildcard := false
+	for i := 0; i < wildcardProbeCount; i++ {
+		answer, ok := resolveDNSAnswer(resolver, randomDNSLabel()+"."+zone)
+		if !ok {
+			continue
+		}
+		wildcard = true
+		for _, ip := range answer.IPs {
+			fingerprint.ips[ip] = true
+		}
+		if answer.CNAME != "" {
+			fingerprint.cnames[answer.CNAME] = true
+		}
+	}
+
+	if !wildcard {
+		return nil
+	}
+	return fingerprint
+}
+
+// parentZone returns the zone directly above a name, limited to the scope's base domain
+func parentZone(name, baseDomain string) string {
+	idx := strings.Index(name, ".")
+	if idx < 0 {
+		return ""
+	}
+	parent := name[idx+1:]
+	if parent != baseDomain && !strings.HasSuffix(parent, "."+baseDomain) {
+		return ""
+	}
+	return parent
+}
+
+// DetectWildcardSubdomains probes the parent zone of every candidate for wildcard DNS
+// and returns the candidates whose answers match their zone's wildcard fingerprint,
+// mapped to that zone. Probe results are stored in wildcard_dns_zones.
+func DetectWildcardSubdomains(scopeTargetID, baseDomain string, subdomains []string) map[string]string {
+	baseDomain = strings.ToLower(baseDomain)
+	byZone := make(map[string][]string)
+	for _, subdomain := range subdomains {
+		name := strings.ToLower(subdomain)
+		if strings.HasPrefix(name, "*.") {
+			continue
+		}
+		if zone := parentZone(name, baseDomain); zone != "" {
+			byZone[zone] = append(byZone[zone], subdomain)
+		}
+	}
+
+	if len(byZone) == 0 {
+		return map[string]string{}
+	}
+
+	log.Printf("[WILDCARD DNS] [INFO] Probing %d parent zones for wildcard DNS (scope target %s)", len(byZone), scopeTargetID)
+	resolver := SharedDNSPool()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, wildcardResolveWorkers)
+
+	fingerprints := make(map[string]*wildcardFingerprint)
+	for zone := range byZone {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(zone string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			fingerprint := probeWildcardZone(resolver, zone)
+			mu.Lock()
+			fingerprints[zone] = fingerprint
+			mu.Unlock()
+		}(zone)
+	}
+	wg.Wait()
+
+	flagged := make(map[string]string)
+	for zone, fingerprint := range fingerprints {
+		if fingerprint == nil {
+			continue
+		}
+		log.Printf("[WILDCARD DNS] [INFO] Wildcard detected for %s (%d IPs, %d CNAMEs), checking %d names", zone, len(fingerprint.ips), len(fingerprint.cnames), len(byZone[zone]))
+
+		for _, subdomain := range byZone[zone] {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(subdomain string, fingerprint *wildcardFingerprint) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				// Names that fail to resolve are left alone; only answers identical
+				// to the wildcard's are treated as noise.
+				answer, ok := resolveDNSAnswer(resolver, subdomain)
+				if ok && fingerprint.matches(answer) {
+					mu.Lock()
+					flagged[subdomain] = fingerprint.zone
+					mu.Unlock()
+				}
+			}(subdomain, fingerprint)
+		}
+	}
+	wg.Wait()
+
+	filteredByZone := make(map[string]int)
+	for _, zone := range flagged {
+		filteredByZone[zone]++
+	}
+	if _, err := dbPool.Exec(context.Background(), `DELETE FROM wildcard_dns_zones WHERE scope_target_id = $1`, scopeTargetID); err != nil {
+		log.Printf("[WILDCARD DNS] [ERROR] Failed to clear previous wildcard results: %v", err)
+	}
+	for zone, fingerprint := range fingerprints {
+		if err := saveWildcardDNSZone(scopeTargetID, zone, fingerprint, filteredByZone[zone]); err != nil {
+			log.Printf("[WILDCARD DNS] [ERROR] Failed to save wildcard result for %s: %v", zone, err)
+		}
+	}
+
+	log.Printf("[WILDCARD DNS] [INFO] Flagged %d of %d names as wildcard answers", len(flagged), len(subdomains))
+	return flagged
+}
+
+func saveWildcardDNSZone(scopeTargetID, zone string, fingerprint *wildcardFingerprint, filteredCount int) error {
+	ips := []string{}
+	cnames := []string{}
+	if fingerprint != nil {
+		for ip := range fingerprint.ips {
+			ips = append(ips, ip)
+		}
+		for cname := range fingerprint.cnames {
+			cnames = append(cnames, cname)
+		}
+		sort.Strings(ips)
+		sort.Strings(cnames)
+	}
+
+	_, err := dbPool.Exec(context.Background(), `
+		INSERT INTO wildcard_dns_zones (scope_target_id, zone, is_wildcard, wildcard_ips, wildcard_cnames, filtered_count, checked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (scope_target_id, zone) DO UPDATE SET
+			is_wildcard = EXCLUDED.is_wildcard,
+			wildcard_ips = EXCLUDED.wildcard_ips,
+			wildcard_cnames = EXCLUDED.wildcard_cnames,
+			filtered_count = EXCLUDED.filtered_count,
+			checked_at = EXCLUDED.checked_at`,
+		scopeTargetID, zone, fingerprint != nil, ips, cnames, filteredCount)
+	return err
+}
+
+// GetWildcardDNSZones returns the zones found to answer for random labels during the
+// last subdomain consolidation of a scope target
+func GetWildcardDNSZones(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	scopeTargetID := vars["id"]
+	if scopeTargetID == "" {
+		http.Error(w, "Scope target ID is required", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := dbPool.Query(context.Background(), `
+		SELECT zone, is_wildcard, wildcard_ips, wildcard_cnames, filtered_count, checked_at
+		FROM wildcard_dns_zones
+		WHERE scope_target_id = $1 AND is_wildcard = true
+		ORDER BY filtered_count DESC, zone ASC`, scopeTargetID)
+	if err != nil {
+		log.Printf("[WILDCARD DNS] [ERROR] Failed to get wildcard zones: %v", err)
+		http.Error(w, "Failed to get wildcard DNS zones", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	zones := []WildcardDNSZone{}
+	for rows.Next() {
+		var zone WildcardDNSZone
+		if err := rows.Scan(&zone.Zone, &zone.IsWildcard, &zone.WildcardIPs, &zone.WildcardCNAMEs, &zone.FilteredCount, &zone.CheckedAt); err != nil {
+			log.Printf("[WILDCARD DNS] [ERROR] Failed to scan wildcard zone: %v", err)
+			continue
+		}
+		zones = append(zones, zone)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(zones)
+}